@@ -13,16 +13,18 @@ import (
 	"github.com/ads-aggregator/ads-aggregator/internal/delivery/http/handler"
 	"github.com/ads-aggregator/ads-aggregator/internal/delivery/http/middleware"
 	"github.com/ads-aggregator/ads-aggregator/internal/delivery/http/router"
-	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
 	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/cache"
 	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/events"
 	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/persistence"
 	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/persistence/postgres"
-	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/platform"
-	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/platform/meta"
-	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/platform/shopee"
-	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/platform/tiktok"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/platform/connectors"
+	"github.com/ads-aggregator/ads-aggregator/internal/scheduler/jobs"
+	"github.com/ads-aggregator/ads-aggregator/internal/usecase/analytics"
 	"github.com/ads-aggregator/ads-aggregator/internal/usecase/auth"
+	syncUsecase "github.com/ads-aggregator/ads-aggregator/internal/usecase/sync"
+	"github.com/ads-aggregator/ads-aggregator/internal/usecase/webhook"
+	"github.com/ads-aggregator/ads-aggregator/pkg/crypto"
+	"github.com/ads-aggregator/ads-aggregator/pkg/currency"
 	"github.com/ads-aggregator/ads-aggregator/pkg/jwt"
 	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
@@ -55,7 +57,7 @@ func main() {
 	log.Info().Str("host", cfg.Database.Host).Msg("Database connected")
 
 	// Initialize platform connectors
-	connectorRegistry := initConnectors(cfg)
+	connectorRegistry := connectors.NewRegistry(cfg)
 	log.Info().Int("connectors", len(connectorRegistry.List())).Msg("Platform connectors initialized")
 
 	// Initialize Redis client for caching
@@ -104,6 +106,22 @@ func main() {
 	orgMemberRepo := postgres.NewOrganizationMemberRepository(db)
 	connectedAccRepo := postgres.NewConnectedAccountRepository(db)
 	verificationTokenRepo := postgres.NewVerificationTokenRepository(db)
+	metricsRepo := postgres.NewMetricsRepository(db)
+	campaignRepo := postgres.NewCampaignRepository(db)
+	reportJobRepo := postgres.NewReportJobRepository(db)
+	reportScheduleRepo := postgres.NewReportScheduleRepository(db)
+	orderRepo := postgres.NewOrderRepository(db)
+	attributionRepo := postgres.NewAttributionRepository(db)
+	salesSummaryRepo := postgres.NewSalesSummaryRepository(db)
+	skuCostRepo := postgres.NewSKUCostRepository(db)
+	budgetRepo := postgres.NewOrganizationBudgetRepository(db)
+	budgetAlertRepo := postgres.NewBudgetAlertRepository(db)
+	anomalyRepo := postgres.NewAnomalyRepository(db)
+	alertRuleRepo := postgres.NewAlertRuleRepository(db)
+	alertEventRepo := postgres.NewAlertEventRepository(db)
+	webhookEndpointRepo := postgres.NewWebhookEndpointRepository(db)
+	webhookDeliveryRepo := postgres.NewWebhookDeliveryRepository(db)
+	subscriptionRepo := postgres.NewSubscriptionRepository(db)
 
 	// Initialize state store for OAuth
 	stateStore := persistence.NewInMemoryStateStore(15 * time.Minute)
@@ -134,11 +152,107 @@ func main() {
 	)
 	log.Info().Msg("Auth service initialized")
 
+	// TOTP and webhook signing secrets are encrypted at rest when an encryption key is configured
+	var secretEncryptor *crypto.TokenEncryptor
+	if cfg.App.EncryptionKey != "" {
+		if secretEncryptor, err = crypto.NewTokenEncryptor(cfg.App.EncryptionKey); err != nil {
+			log.Fatal().Err(err).Msg("Invalid ENCRYPTION_KEY")
+		}
+	} else {
+		log.Warn().Msg("ENCRYPTION_KEY not set, MFA and webhook secrets will be stored unencrypted")
+	}
+	authService.SetMFARepository(postgres.NewUserMFARepository(db), secretEncryptor)
+
+	// Refresh tokens are rotated on use and revocable through server-side sessions
+	authService.SetSessionRepository(postgres.NewUserSessionRepository(db))
+
+	// Organization API keys authenticate alongside access tokens
+	apiKeyService := auth.NewAPIKeyService(postgres.NewAPIKeyRepository(db), subscriptionRepo)
+	authMiddleware.SetAPIKeyAuthenticator(apiKeyService)
+
+	// Exchange rates are imported by the worker; reload them periodically to pick up new days
+	rateProvider := currency.NewDatedRateProvider(currency.ECBBaseCurrency, postgres.NewExchangeRateRepository(db), currency.NewStaticRateProvider())
+	if err := rateProvider.Load(context.Background()); err != nil {
+		log.Warn().Err(err).Msg("Failed to load exchange rates, using static rates")
+	}
+	stopRateReload := make(chan struct{})
+	go reloadExchangeRates(rateProvider, cfg.Currency.RateReloadInterval, stopRateReload)
+
+	// Initialize analytics service; reports are queued here and built by the worker
+	analyticsService := analytics.NewServiceWithCurrency(metricsRepo, campaignRepo, currency.NewConverter(rateProvider))
+	analyticsService.SetOrganizationRepository(orgRepo)
+	analyticsService.SetReportJobRepository(reportJobRepo)
+	analyticsService.SetReportScheduleRepository(reportScheduleRepo)
+	analyticsService.SetAttributionRepositories(orderRepo, attributionRepo)
+	analyticsService.SetProfitRepositories(salesSummaryRepo, skuCostRepo)
+
+	// Organization budgets are compared against spend aggregated across platforms
+	aggregationService := analytics.NewAggregationService(metricsRepo, campaignRepo, connectedAccRepo, nil)
+	aggregationService.SetCurrencyConverter(currency.NewConverter(rateProvider))
+	budgetService := analytics.NewBudgetService(budgetRepo, budgetAlertRepo, aggregationService)
+	analyticsService.SetBudgetService(budgetService)
+
+	// Anomalies found from the API (manual runs and the syncs it runs) are pushed
+	// to connected clients; the worker emails the ones it finds after each sync
+	anomalyService := analytics.NewAnomalyService(metricsRepo, campaignRepo, anomalyRepo)
+	anomalyService.AddNotifier(jobs.NewAnomalyEventNotifier(broadcaster))
+
+	// Alert rules evaluated from the API (manual runs and the syncs it runs) are
+	// pushed to connected clients and webhooks; the worker evaluates them hourly
+	// and emails them
+	alertRuleService := analytics.NewAlertRuleService(analyticsService, alertRuleRepo, alertEventRepo)
+	alertRuleService.AddNotifier(jobs.NewAlertEventNotifier(broadcaster))
+	alertRuleService.AddNotifier(jobs.NewAlertWebhookNotifier(nil))
+
+	// Outbound webhooks are managed and replayed from the API; the worker sends and retries deliveries
+	webhookService := webhook.NewService(webhookEndpointRepo, webhookDeliveryRepo, subscriptionRepo, jobs.NewWebhookSender(nil))
+	webhookService.SetSecretEncryptor(secretEncryptor)
+	webhookPublisher := jobs.NewWebhookEventPublisher(webhookService, log.Logger)
+	alertRuleService.AddListener(webhookPublisher)
+
+	// The API claims sync jobs from the shared queue alongside the worker. Jobs
+	// run here stream their progress over SSE, and each metrics sync is checked
+	// for anomalies and alert rules. Newly connected accounts queue a backfill.
+	syncEngine := syncUsecase.NewService(
+		nil,
+		connectedAccRepo,
+		postgres.NewAdAccountRepository(db),
+		campaignRepo,
+		postgres.NewAdSetRepository(db),
+		postgres.NewAdRepository(db),
+		metricsRepo,
+		connectorRegistry,
+		log.Logger,
+	)
+	syncEngine.AddMetricsListener(jobs.NewAnomalyDetectionJob(connectedAccRepo, anomalyService, log.Logger))
+	syncEngine.AddMetricsListener(jobs.NewAlertRuleJob(connectedAccRepo, alertRuleService, log.Logger))
+	syncStateRepo := postgres.NewSyncStateRepository(db)
+	syncJobRepo := postgres.NewSyncJobRepository(db)
+	jobRunner := syncUsecase.NewJobRunner(
+		syncEngine,
+		syncStateRepo,
+		syncJobRepo,
+		postgres.NewRetryQueueRepository(db),
+		postgres.NewSyncErrorLogRepository(db),
+		nil,
+	)
+	jobRunner.SetOrderRepositories(orderRepo, salesSummaryRepo)
+	jobRunner.AddProgressListener(jobs.NewSyncEventNotifier(broadcaster))
+	jobRunner.AddJobListener(webhookPublisher)
+	syncScheduler := syncUsecase.NewScheduler(syncStateRepo, syncJobRepo, connectedAccRepo, nil)
+	syncScheduler.SetJobExecutor(jobRunner)
+	authService.AddAccountListener(syncScheduler)
+
 	// Initialize handlers
 	authHandler := handler.NewAuthHandler(authService)
+	authHandler.SetAPIKeyService(apiKeyService)
 	platformHandler := handler.NewPlatformHandler(nil, nil)
-	analyticsHandler := handler.NewAnalyticsHandler(nil, appCache)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService, appCache)
 	eventsHandler := handler.NewEventsHandler(broadcaster)
+	budgetHandler := handler.NewBudgetHandler(budgetService)
+	anomalyHandler := handler.NewAnomalyHandler(anomalyService)
+	alertRuleHandler := handler.NewAlertRuleHandler(alertRuleService)
+	webhookHandler := handler.NewWebhookEndpointHandler(webhookService)
 
 	// Initialize router
 	// Note: AllowedOrigins must be specific origins (not "*") when AllowCredentials is true
@@ -157,6 +271,10 @@ func main() {
 		platformHandler,
 		analyticsHandler,
 		eventsHandler,
+		budgetHandler,
+		anomalyHandler,
+		alertRuleHandler,
+		webhookHandler,
 		authMiddleware,
 		rateLimitMiddleware,
 	)
@@ -171,6 +289,13 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	syncCtx, stopSync := context.WithCancel(context.Background())
+	defer stopSync()
+	jobRunner.Start(syncCtx)
+	if err := syncScheduler.Start(syncCtx); err != nil {
+		log.Fatal().Err(err).Msg("Failed to start sync scheduler")
+	}
+
 	// Start server in goroutine
 	go func() {
 		log.Info().Msgf("Server listening on port %d", cfg.App.Port)
@@ -192,6 +317,9 @@ func main() {
 	if err := srv.Shutdown(ctx); err != nil {
 		log.Fatal().Err(err).Msg("Server forced to shutdown")
 	}
+	close(stopRateReload)
+	syncScheduler.Stop()
+	jobRunner.Stop()
 
 	// Close Redis connection
 	if redisClient != nil {
@@ -203,48 +331,25 @@ func main() {
 	log.Info().Msg("Server exited")
 }
 
-// initConnectors initializes platform connectors
-func initConnectors(cfg *config.Config) *platform.ConnectorRegistry {
-	registry := platform.NewConnectorRegistry()
-
-	// Meta connector
-	metaConnector := meta.NewConnector(&meta.Config{
-		AppID:           cfg.Meta.AppID,
-		AppSecret:       cfg.Meta.AppSecret,
-		RedirectURI:     cfg.Meta.RedirectURI,
-		APIVersion:      cfg.Meta.APIVersion,
-		RateLimitCalls:  cfg.Meta.RateLimitCalls,
-		RateLimitWindow: cfg.Meta.RateLimitWindow,
-		Timeout:         cfg.HTTP.Timeout,
-		MaxRetries:      cfg.HTTP.MaxRetries,
-	})
-	registry.Register(entity.PlatformMeta, metaConnector)
-
-	// TikTok connector
-	tiktokConnector := tiktok.NewConnector(&tiktok.Config{
-		AppID:           cfg.TikTok.AppID,
-		AppSecret:       cfg.TikTok.AppSecret,
-		RedirectURI:     cfg.TikTok.RedirectURI,
-		RateLimitCalls:  cfg.TikTok.RateLimitCalls,
-		RateLimitWindow: cfg.TikTok.RateLimitWindow,
-		Timeout:         cfg.HTTP.Timeout,
-		MaxRetries:      cfg.HTTP.MaxRetries,
-	})
-	registry.Register(entity.PlatformTikTok, tiktokConnector)
-
-	// Shopee connector
-	shopeePartnerID := int64(0)
-	fmt.Sscanf(cfg.Shopee.PartnerID, "%d", &shopeePartnerID)
-	shopeeConnector := shopee.NewConnector(&shopee.Config{
-		PartnerID:       shopeePartnerID,
-		PartnerKey:      cfg.Shopee.PartnerKey,
-		RedirectURI:     cfg.Shopee.RedirectURI,
-		RateLimitCalls:  cfg.Shopee.RateLimitCalls,
-		RateLimitWindow: cfg.Shopee.RateLimitWindow,
-		Timeout:         cfg.HTTP.Timeout,
-		MaxRetries:      cfg.HTTP.MaxRetries,
-	})
-	registry.Register(entity.PlatformShopee, shopeeConnector)
-
-	return registry
+// reloadExchangeRates reloads the stored exchange rates every interval until stop is closed
+func reloadExchangeRates(provider *currency.DatedRateProvider, interval time.Duration, stop <-chan struct{}) {
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+			if err := provider.Load(ctx); err != nil {
+				log.Warn().Err(err).Msg("Failed to reload exchange rates")
+			}
+			cancel()
+		}
+	}
 }
@@ -1,6 +1,6 @@
 // =============================================================================
 // Mock Platform API Server
-// Simulates Meta, TikTok, and Shopee Marketing APIs for local testing
+// Simulates Meta, TikTok, Shopee, and Google Ads APIs for local testing
 // =============================================================================
 
 package main
@@ -12,6 +12,7 @@ import (
 	"net/http"
 	"os"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -89,6 +90,16 @@ func main() {
 		shopee.POST("/api/v2/auth/access_token/get", shopeeRefreshToken)
 	}
 
+	// Google Ads API mocks
+	// Google uses custom methods (customers:listAccessibleCustomers, googleAds:search)
+	// which gin cannot route as static segments, so they are dispatched by path.
+	google := r.Group("/google")
+	{
+		google.GET("/v17/*method", googleAdsGet)
+		google.POST("/v17/*method", googleAdsPost)
+		google.POST("/token", googleRefreshToken)
+	}
+
 	port := os.Getenv("MOCK_PORT")
 	if port == "" {
 		port = "9090"
@@ -632,6 +643,86 @@ func shopeeRefreshToken(c *gin.Context) {
 	})
 }
 
+// =============================================================================
+// Google Ads API Mocks
+// =============================================================================
+
+func googleAdsGet(c *gin.Context) {
+	if c.Param("method") != "/customers:listAccessibleCustomers" {
+		googleNotFound(c)
+		return
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"resourceNames": []string{"customers/1234567890", "customers/9876543210"},
+	})
+}
+
+func googleAdsPost(c *gin.Context) {
+	method := c.Param("method")
+	if !strings.HasPrefix(method, "/customers/") || !strings.HasSuffix(method, "/googleAds:search") {
+		googleNotFound(c)
+		return
+	}
+	customerID := strings.TrimSuffix(strings.TrimPrefix(method, "/customers/"), "/googleAds:search")
+
+	var req struct {
+		Query string `json:"query"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, map[string]interface{}{
+			"error": map[string]interface{}{"code": 400, "message": "Invalid request body", "status": "INVALID_ARGUMENT"},
+		})
+		return
+	}
+
+	query := strings.ToLower(req.Query)
+	var results []map[string]interface{}
+
+	switch {
+	case strings.Contains(query, "segments.date") && strings.Contains(query, "select campaign.id"):
+		results = generateGoogleMetrics(30)
+	case strings.Contains(query, "from campaign"):
+		results = generateGoogleCampaigns(customerID, 5)
+	case strings.Contains(query, "from customer"):
+		results = []map[string]interface{}{
+			{
+				"customer": map[string]interface{}{
+					"resourceName":    "customers/" + customerID,
+					"id":              customerID,
+					"descriptiveName": "Test Google Ads Account " + customerID,
+					"currencyCode":    "MYR",
+					"timeZone":        "Asia/Kuala_Lumpur",
+					"status":          "ENABLED",
+					"manager":         customerID == "9876543210",
+				},
+			},
+		}
+	default:
+		results = []map[string]interface{}{}
+	}
+
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"results":   results,
+		"fieldMask": "",
+	})
+}
+
+func googleRefreshToken(c *gin.Context) {
+	c.JSON(http.StatusOK, map[string]interface{}{
+		"access_token": fmt.Sprintf("mock_google_token_%d", time.Now().Unix()),
+		"expires_in":   3599,
+		"scope":        "https://www.googleapis.com/auth/adwords",
+		"token_type":   "Bearer",
+	})
+}
+
+func googleNotFound(c *gin.Context) {
+	c.JSON(http.StatusNotFound, map[string]interface{}{
+		"error": map[string]interface{}{"code": 404, "message": "Method not found", "status": "NOT_FOUND"},
+	})
+}
+
 // =============================================================================
 // Helper Functions
 // =============================================================================
@@ -731,3 +822,69 @@ func generateMetaInsights(days int) []map[string]interface{} {
 
 	return insights
 }
+
+func generateGoogleCampaigns(customerID string, count int) []map[string]interface{} {
+	channelTypes := []string{"SEARCH", "DISPLAY", "SHOPPING", "VIDEO", "PERFORMANCE_MAX"}
+	statuses := []string{"ENABLED", "PAUSED", "ENABLED"}
+
+	rows := make([]map[string]interface{}, 0, count)
+
+	for i := 0; i < count; i++ {
+		campaignID := strconv.Itoa(20000000 + i)
+		channelType := channelTypes[rand.Intn(len(channelTypes))]
+		budgetMicros := int64((rand.Float64()*490 + 10) * 1000000) // RM10-500 in micros
+
+		rows = append(rows, map[string]interface{}{
+			"campaign": map[string]interface{}{
+				"resourceName":           fmt.Sprintf("customers/%s/campaigns/%s", customerID, campaignID),
+				"id":                     campaignID,
+				"name":                   fmt.Sprintf("Google Campaign %d - %s", i+1, channelType),
+				"status":                 statuses[rand.Intn(len(statuses))],
+				"advertisingChannelType": channelType,
+				"startDate":              time.Now().AddDate(0, 0, -rand.Intn(60)).Format("2006-01-02"),
+				"endDate":                "2037-12-30",
+			},
+			"campaignBudget": map[string]interface{}{
+				"amountMicros": strconv.FormatInt(budgetMicros, 10),
+			},
+			"customer": map[string]interface{}{
+				"currencyCode": "MYR",
+			},
+		})
+	}
+
+	return rows
+}
+
+func generateGoogleMetrics(days int) []map[string]interface{} {
+	rows := make([]map[string]interface{}, 0, days)
+	baseDate := time.Now().AddDate(0, 0, -days)
+
+	for i := 0; i < days; i++ {
+		date := baseDate.AddDate(0, 0, i)
+
+		costMicros := int64((rand.Float64()*490 + 10) * 1000000)
+		impressions := rand.Intn(49000) + 1000
+		clicks := int(float64(impressions) * (rand.Float64()*4 + 1) / 100)
+		conversions := float64(int(float64(clicks) * (rand.Float64()*7 + 1) / 100))
+		revenue := conversions * (rand.Float64()*100 + 50)
+
+		rows = append(rows, map[string]interface{}{
+			"segments": map[string]interface{}{
+				"date": date.Format("2006-01-02"),
+			},
+			"metrics": map[string]interface{}{
+				"impressions":      strconv.Itoa(impressions),
+				"clicks":           strconv.Itoa(clicks),
+				"costMicros":       strconv.FormatInt(costMicros, 10),
+				"conversions":      conversions,
+				"conversionsValue": revenue,
+			},
+			"customer": map[string]interface{}{
+				"currencyCode": "MYR",
+			},
+		})
+	}
+
+	return rows
+}
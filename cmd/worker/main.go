@@ -10,6 +10,19 @@ import (
 	"time"
 
 	"github.com/ads-aggregator/ads-aggregator/config"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/email"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/persistence/postgres"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/platform/connectors"
+	"github.com/ads-aggregator/ads-aggregator/internal/scheduler"
+	"github.com/ads-aggregator/ads-aggregator/internal/scheduler/jobs"
+	"github.com/ads-aggregator/ads-aggregator/internal/usecase/analytics"
+	"github.com/ads-aggregator/ads-aggregator/internal/usecase/oauth"
+	syncUsecase "github.com/ads-aggregator/ads-aggregator/internal/usecase/sync"
+	"github.com/ads-aggregator/ads-aggregator/internal/usecase/webhook"
+	"github.com/ads-aggregator/ads-aggregator/internal/worker"
+	"github.com/ads-aggregator/ads-aggregator/pkg/crypto"
+	"github.com/ads-aggregator/ads-aggregator/pkg/currency"
+	"github.com/redis/go-redis/v9"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 )
@@ -45,11 +58,191 @@ func main() {
 	healthPort := 8081
 	healthServer := startHealthServer(healthPort)
 
-	// TODO: Initialize database connection
-	// TODO: Initialize Redis connection
-	// TODO: Initialize scheduler with cron jobs
-	// TODO: Start data sync workers
-	// TODO: Start token refresh workers
+	// Initialize database
+	db, err := postgres.NewDatabase(&cfg.Database)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+	defer db.Close()
+	log.Info().Str("host", cfg.Database.Host).Msg("Database connected")
+
+	// Exchange rates: convert at the stored rate of each day, falling back to static rates
+	rateProvider := currency.NewDatedRateProvider(currency.ECBBaseCurrency, postgres.NewExchangeRateRepository(db), currency.NewStaticRateProvider())
+	if err := rateProvider.Load(ctx); err != nil {
+		log.Warn().Err(err).Msg("Failed to load exchange rates, using static rates")
+	}
+
+	// Start report worker
+	metricsRepo := postgres.NewMetricsRepository(db)
+	campaignRepo := postgres.NewCampaignRepository(db)
+	analyticsService := analytics.NewServiceWithCurrency(metricsRepo, campaignRepo, currency.NewConverter(rateProvider))
+	analyticsService.SetOrganizationRepository(postgres.NewOrganizationRepository(db))
+	analyticsService.SetReportJobRepository(postgres.NewReportJobRepository(db))
+
+	// Outbound webhooks: published events are queued as deliveries, which are sent every minute and retried with backoff
+	webhookService := webhook.NewService(
+		postgres.NewWebhookEndpointRepository(db),
+		postgres.NewWebhookDeliveryRepository(db),
+		postgres.NewSubscriptionRepository(db),
+		jobs.NewWebhookSender(nil),
+	)
+	if cfg.App.EncryptionKey != "" {
+		secretEncryptor, err := crypto.NewTokenEncryptor(cfg.App.EncryptionKey)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Invalid ENCRYPTION_KEY")
+		}
+		webhookService.SetSecretEncryptor(secretEncryptor)
+	}
+	webhookPublisher := jobs.NewWebhookEventPublisher(webhookService, log.Logger)
+	analyticsService.AddReportJobListener(webhookPublisher)
+
+	reportWorker := worker.NewReportWorker(nil, analyticsService, log.Logger)
+	if err := reportWorker.Start(ctx); err != nil {
+		log.Fatal().Err(err).Msg("Failed to start report worker")
+	}
+
+	// Initialize Redis; the email queue and scheduled report delivery depend on it
+	redisClient := redis.NewClient(&redis.Options{
+		Addr:     cfg.Redis.Addr(),
+		Password: cfg.Redis.Password,
+		DB:       cfg.Redis.DB,
+	})
+	defer redisClient.Close()
+
+	// Tokens expiring within the hour are refreshed on the token refresh
+	// schedule; an account whose token expired and cannot be refreshed is
+	// marked expired and reported to token.expired webhooks
+	connectedAccRepo := postgres.NewConnectedAccountRepository(db)
+	registry := connectors.NewRegistry(cfg)
+	tokenManager, err := oauth.NewTokenManager(connectedAccRepo, postgres.NewTokenRefreshLogRepository(db), registry, cfg.App.EncryptionKey, log.Logger)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize token manager")
+	}
+	tokenManager.AddExpiryListener(webhookPublisher)
+
+	// Exchange rates are imported daily; a fresh database is seeded right away
+	jobScheduler := scheduler.NewScheduler(nil, tokenManager, connectedAccRepo, log.Logger)
+	exchangeRateJob := jobs.NewExchangeRateJob(rateProvider, currency.NewECBSource(cfg.Currency.RateSourceURL), log.Logger)
+	jobScheduler.SetExchangeRateJob(exchangeRateJob)
+
+	// Organization budgets are checked hourly against month-to-date spend
+	budgetRepo := postgres.NewOrganizationBudgetRepository(db)
+	aggregationService := analytics.NewAggregationService(metricsRepo, campaignRepo, postgres.NewConnectedAccountRepository(db), nil)
+	aggregationService.SetCurrencyConverter(currency.NewConverter(rateProvider))
+	budgetService := analytics.NewBudgetService(budgetRepo, postgres.NewBudgetAlertRepository(db), aggregationService)
+	jobScheduler.SetBudgetAlertJob(jobs.NewBudgetAlertJob(budgetRepo, budgetService, log.Logger))
+
+	// Yesterday's campaign metrics are swept daily for anomalies; the same job
+	// checks the campaigns of each metrics sync run by this worker
+	anomalyService := analytics.NewAnomalyService(metricsRepo, campaignRepo, postgres.NewAnomalyRepository(db))
+	anomalyJob := jobs.NewAnomalyDetectionJob(postgres.NewConnectedAccountRepository(db), anomalyService, log.Logger)
+	jobScheduler.SetAnomalyDetectionJob(anomalyJob)
+
+	// User-defined alert rules are evaluated hourly; SSE delivery happens in the API
+	alertRuleService := analytics.NewAlertRuleService(analyticsService, postgres.NewAlertRuleRepository(db), postgres.NewAlertEventRepository(db))
+	alertRuleService.AddNotifier(jobs.NewAlertWebhookNotifier(nil))
+	alertRuleService.AddListener(webhookPublisher)
+	alertRuleJob := jobs.NewAlertRuleJob(postgres.NewConnectedAccountRepository(db), alertRuleService, log.Logger)
+	jobScheduler.SetAlertRuleJob(alertRuleJob)
+	jobScheduler.SetWebhookDeliveryJob(jobs.NewWebhookDeliveryJob(webhookService, log.Logger))
+
+	// Sync jobs are claimed from the queue by concurrent workers, each holding a
+	// lease it renews while the job runs; the reaper requeues jobs whose worker
+	// stopped renewing. Every metrics sync is checked for anomalies and alert
+	// rules, and finished jobs are reported to sync webhooks.
+	syncEngine := syncUsecase.NewService(
+		nil,
+		connectedAccRepo,
+		postgres.NewAdAccountRepository(db),
+		campaignRepo,
+		postgres.NewAdSetRepository(db),
+		postgres.NewAdRepository(db),
+		metricsRepo,
+		registry,
+		log.Logger,
+	)
+	syncEngine.AddMetricsListener(anomalyJob)
+	syncEngine.AddMetricsListener(alertRuleJob)
+	syncStateRepo := postgres.NewSyncStateRepository(db)
+	syncJobRepo := postgres.NewSyncJobRepository(db)
+	jobRunner := syncUsecase.NewJobRunner(
+		syncEngine,
+		syncStateRepo,
+		syncJobRepo,
+		postgres.NewRetryQueueRepository(db),
+		postgres.NewSyncErrorLogRepository(db),
+		nil,
+	)
+	jobRunner.SetOrderRepositories(postgres.NewOrderRepository(db), postgres.NewSalesSummaryRepository(db))
+	jobRunner.AddJobListener(webhookPublisher)
+	syncScheduler := syncUsecase.NewScheduler(syncStateRepo, syncJobRepo, connectedAccRepo, nil)
+	syncScheduler.SetJobExecutor(jobRunner)
+	if _, ok := rateProvider.LatestDate(); !ok {
+		go func() {
+			if _, err := exchangeRateJob.Run(ctx); err != nil {
+				log.Warn().Err(err).Msg("Initial exchange rate import failed")
+			}
+		}()
+	}
+
+	var emailManager *email.Manager
+	pingCtx, pingCancel := context.WithTimeout(ctx, 5*time.Second)
+	if err := redisClient.Ping(pingCtx).Err(); err != nil {
+		log.Warn().Err(err).Msg("Failed to connect to Redis, email delivery disabled")
+	} else {
+		log.Info().Str("addr", cfg.Redis.Addr()).Msg("Redis connected")
+
+		emailManager, err = email.NewManager(redisClient, newEmailConfig(cfg), nil)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to initialize email")
+		}
+		if err := emailManager.Start(ctx); err != nil {
+			log.Fatal().Err(err).Msg("Failed to start email worker")
+		}
+
+		// Scheduled report delivery
+		analyticsService.SetReportScheduleRepository(postgres.NewReportScheduleRepository(db))
+		jobScheduler.SetReportDeliveryJob(jobs.NewReportDeliveryJob(
+			postgres.NewReportScheduleRepository(db),
+			postgres.NewOrganizationRepository(db),
+			analyticsService,
+			emailManager.Trigger,
+			log.Logger,
+		))
+
+		// High and critical anomalies are emailed to organization owners and admins
+		anomalyService.AddNotifier(jobs.NewAnomalyEmailNotifier(
+			postgres.NewOrganizationMemberRepository(db),
+			postgres.NewOrganizationRepository(db),
+			emailManager.Trigger,
+		))
+
+		// Alert rules with the email channel are emailed to organization owners and admins
+		alertRuleService.AddNotifier(jobs.NewAlertEmailNotifier(
+			postgres.NewOrganizationMemberRepository(db),
+			postgres.NewOrganizationRepository(db),
+			emailManager.Trigger,
+		))
+	}
+	pingCancel()
+
+	if err := jobScheduler.Start(&scheduler.Config{
+		Enabled:                  cfg.Scheduler.Enabled,
+		ReportDeliverySchedule:   cfg.Scheduler.ReportDeliverySchedule,
+		ExchangeRateSchedule:     cfg.Scheduler.ExchangeRateSchedule,
+		BudgetAlertSchedule:      cfg.Scheduler.BudgetAlertSchedule,
+		AnomalyDetectionSchedule: cfg.Scheduler.AnomalyDetectionSchedule,
+		AlertRuleSchedule:        cfg.Scheduler.AlertRuleSchedule,
+		WebhookDeliverySchedule:  cfg.Scheduler.WebhookDeliverySchedule,
+		TokenRefreshCronSchedule: cfg.Scheduler.TokenRefreshCronSchedule,
+	}); err != nil {
+		log.Fatal().Err(err).Msg("Failed to start scheduler")
+	}
+
+	jobRunner.Start(ctx)
+	if err := syncScheduler.Start(ctx); err != nil {
+		log.Fatal().Err(err).Msg("Failed to start sync scheduler")
+	}
 
 	log.Info().
 		Str("app", cfg.App.Name).
@@ -64,6 +257,13 @@ func main() {
 	log.Info().Msg("Shutting down worker...")
 
 	// Graceful shutdown
+	syncScheduler.Stop()
+	jobRunner.Stop()
+	jobScheduler.Stop()
+	if emailManager != nil {
+		emailManager.Stop()
+	}
+	reportWorker.Stop()
 	cancel()
 
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -77,7 +277,23 @@ func main() {
 	time.Sleep(5 * time.Second)
 
 	log.Info().Msg("Worker exited")
-	_ = ctx // silence unused variable
+}
+
+// newEmailConfig maps application configuration to the email package configuration
+func newEmailConfig(cfg *config.Config) *email.Config {
+	apiKey := cfg.Email.ResendAPIKey
+	if cfg.Email.Provider == "sendgrid" {
+		apiKey = cfg.Email.SendGridAPIKey
+	}
+
+	return &email.Config{
+		Provider:    cfg.Email.Provider,
+		APIKey:      apiKey,
+		FromEmail:   cfg.Email.From,
+		FromName:    cfg.Email.FromName,
+		BaseURL:     cfg.Email.FrontendURL,
+		Environment: cfg.App.Env,
+	}
 }
 
 // startHealthServer starts an HTTP server for health checks
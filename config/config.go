@@ -19,7 +19,10 @@ type Config struct {
 	Meta      MetaConfig
 	TikTok    TikTokConfig
 	Shopee    ShopeeConfig
+	Google    GoogleConfig
+	Lazada    LazadaConfig
 	Scheduler SchedulerConfig
+	Currency  CurrencyConfig
 	Log       LogConfig
 	RateLimit RateLimitConfig
 	HTTP      HTTPClientConfig
@@ -103,12 +106,46 @@ type ShopeeConfig struct {
 	RateLimitWindow time.Duration
 }
 
+// GoogleConfig holds Google Ads API configuration
+type GoogleConfig struct {
+	ClientID        string
+	ClientSecret    string
+	DeveloperToken  string
+	LoginCustomerID string
+	RedirectURI     string
+	APIVersion      string
+	RateLimitCalls  int
+	RateLimitWindow time.Duration
+}
+
+// LazadaConfig holds Lazada Open Platform configuration
+type LazadaConfig struct {
+	AppKey          string
+	AppSecret       string
+	RedirectURI     string
+	Region          string
+	RateLimitCalls  int
+	RateLimitWindow time.Duration
+}
+
 // SchedulerConfig holds scheduler configuration
 type SchedulerConfig struct {
 	Enabled                    bool
 	SyncCronSchedule           string
 	TokenRefreshCronSchedule   string
 	MetricsAggregationSchedule string
+	ReportDeliverySchedule     string
+	ExchangeRateSchedule       string
+	BudgetAlertSchedule        string
+	AnomalyDetectionSchedule   string
+	AlertRuleSchedule          string
+	WebhookDeliverySchedule    string
+}
+
+// CurrencyConfig holds exchange rate configuration
+type CurrencyConfig struct {
+	RateSourceURL      string // ECB-style XML or CSV feed; a local file path also works
+	RateReloadInterval time.Duration
 }
 
 // LogConfig holds logging configuration
@@ -221,11 +258,39 @@ func Load() (*Config, error) {
 			RateLimitCalls:  getEnvAsInt("SHOPEE_RATE_LIMIT_CALLS", 1000),
 			RateLimitWindow: getEnvAsDuration("SHOPEE_RATE_LIMIT_WINDOW", time.Minute),
 		},
+		Google: GoogleConfig{
+			ClientID:        getEnv("GOOGLE_ADS_CLIENT_ID", ""),
+			ClientSecret:    getEnv("GOOGLE_ADS_CLIENT_SECRET", ""),
+			DeveloperToken:  getEnv("GOOGLE_ADS_DEVELOPER_TOKEN", ""),
+			LoginCustomerID: getEnv("GOOGLE_ADS_LOGIN_CUSTOMER_ID", ""),
+			RedirectURI:     getEnv("GOOGLE_ADS_REDIRECT_URI", "http://localhost:8080/api/v1/oauth/google/callback"),
+			APIVersion:      getEnv("GOOGLE_ADS_API_VERSION", "v17"),
+			RateLimitCalls:  getEnvAsInt("GOOGLE_ADS_RATE_LIMIT_CALLS", 1000),
+			RateLimitWindow: getEnvAsDuration("GOOGLE_ADS_RATE_LIMIT_WINDOW", time.Minute),
+		},
+		Lazada: LazadaConfig{
+			AppKey:          getEnv("LAZADA_APP_KEY", ""),
+			AppSecret:       getEnv("LAZADA_APP_SECRET", ""),
+			RedirectURI:     getEnv("LAZADA_REDIRECT_URI", "http://localhost:8080/api/v1/oauth/lazada/callback"),
+			Region:          getEnv("LAZADA_REGION", "MY"),
+			RateLimitCalls:  getEnvAsInt("LAZADA_RATE_LIMIT_CALLS", 500),
+			RateLimitWindow: getEnvAsDuration("LAZADA_RATE_LIMIT_WINDOW", time.Minute),
+		},
 		Scheduler: SchedulerConfig{
 			Enabled:                    getEnvAsBool("SCHEDULER_ENABLED", true),
 			SyncCronSchedule:           getEnv("SYNC_CRON_SCHEDULE", "0 * * * *"),
 			TokenRefreshCronSchedule:   getEnv("TOKEN_REFRESH_CRON_SCHEDULE", "*/30 * * * *"),
 			MetricsAggregationSchedule: getEnv("METRICS_AGGREGATION_CRON_SCHEDULE", "0 */6 * * *"),
+			ReportDeliverySchedule:     getEnv("REPORT_DELIVERY_CRON_SCHEDULE", "* * * * *"),
+			ExchangeRateSchedule:       getEnv("EXCHANGE_RATE_CRON_SCHEDULE", "0 17 * * *"),
+			BudgetAlertSchedule:        getEnv("BUDGET_ALERT_CRON_SCHEDULE", "15 * * * *"),
+			AnomalyDetectionSchedule:   getEnv("ANOMALY_DETECTION_CRON_SCHEDULE", "30 6 * * *"),
+			AlertRuleSchedule:          getEnv("ALERT_RULE_CRON_SCHEDULE", "45 * * * *"),
+			WebhookDeliverySchedule:    getEnv("WEBHOOK_DELIVERY_CRON_SCHEDULE", "* * * * *"),
+		},
+		Currency: CurrencyConfig{
+			RateSourceURL:      getEnv("EXCHANGE_RATE_SOURCE_URL", "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"),
+			RateReloadInterval: getEnvAsDuration("EXCHANGE_RATE_RELOAD_INTERVAL", time.Hour),
 		},
 		Log: LogConfig{
 			Level:  getEnv("LOG_LEVEL", "debug"),
@@ -0,0 +1,5 @@
+-- Google Ads platform support
+-- Extends the platform enum so Google Ads accounts, campaigns and metrics can be stored.
+-- ALTER TYPE ... ADD VALUE cannot run inside a transaction block on older PostgreSQL versions,
+-- so this migration must be applied on its own.
+ALTER TYPE platform_type ADD VALUE IF NOT EXISTS 'google';
@@ -0,0 +1,8 @@
+-- Lazada platform support
+-- Extends the platform enum so Lazada Sponsored Solutions campaigns, metrics and
+-- marketplace orders can be stored alongside Shopee's.
+-- ALTER TYPE ... ADD VALUE cannot run inside a transaction block on older PostgreSQL versions,
+-- so this migration must be applied on its own.
+ALTER TYPE platform_type ADD VALUE IF NOT EXISTS 'lazada';
+
+COMMENT ON TABLE orders IS 'E-commerce orders from Shopee and Lazada for ROAS calculation';
@@ -0,0 +1,51 @@
+-- Migration: Asynchronous report jobs
+-- Report generation runs in the worker instead of the HTTP request. A job row tracks
+-- status and artifact metadata; the generated file is stored separately so status
+-- polling never loads the report content.
+
+CREATE TABLE IF NOT EXISTS report_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    requested_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    format VARCHAR(10) NOT NULL, -- 'json', 'csv', 'xlsx', 'pdf'
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- 'pending', 'running', 'completed', 'failed', 'expired'
+
+    -- Report parameters
+    date_range_start DATE NOT NULL,
+    date_range_end DATE NOT NULL,
+    white_label BOOLEAN DEFAULT false,
+
+    -- Execution tracking
+    worker_id VARCHAR(100),
+    attempts INTEGER DEFAULT 0,
+    max_attempts INTEGER DEFAULT 3,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+
+    -- Artifact metadata
+    file_name VARCHAR(255),
+    content_type VARCHAR(100),
+    size_bytes BIGINT DEFAULT 0,
+    expires_at TIMESTAMP WITH TIME ZONE,
+
+    error_message TEXT,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_report_jobs_org ON report_jobs(organization_id, created_at DESC);
+CREATE INDEX idx_report_jobs_pending ON report_jobs(created_at) WHERE status = 'pending';
+CREATE INDEX idx_report_jobs_expires ON report_jobs(expires_at) WHERE expires_at IS NOT NULL;
+
+CREATE TABLE IF NOT EXISTS report_artifacts (
+    report_job_id UUID PRIMARY KEY REFERENCES report_jobs(id) ON DELETE CASCADE,
+    content BYTEA NOT NULL,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_report_artifacts_expires ON report_artifacts(expires_at);
+
+COMMENT ON TABLE report_jobs IS 'Asynchronous report generation jobs processed by the worker';
+COMMENT ON TABLE report_artifacts IS 'Generated report files, deleted once expired';
@@ -0,0 +1,36 @@
+-- Migration: Scheduled report delivery
+-- Organizations can email a report to a list of recipients on a cron schedule, e.g.
+-- "every Monday 9am, send the last 7 days report". The scheduler picks up schedules
+-- whose next_run_at has passed and advances it before delivering.
+
+CREATE TABLE IF NOT EXISTS report_schedules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    name VARCHAR(255) NOT NULL,
+
+    -- Schedule
+    cron_expression VARCHAR(100) NOT NULL, -- standard 5-field cron, e.g. '0 9 * * 1'
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC', -- IANA name, e.g. 'Asia/Kuala_Lumpur'
+
+    -- Report parameters
+    date_preset VARCHAR(30) NOT NULL, -- 'yesterday', 'last_7_days', 'last_14_days', 'last_30_days', 'last_week', 'last_month', 'month_to_date'
+    format VARCHAR(10) NOT NULL, -- 'csv', 'xlsx', 'pdf'
+    recipients JSONB NOT NULL DEFAULT '[]', -- list of email addresses
+    white_label BOOLEAN DEFAULT false,
+    is_active BOOLEAN DEFAULT true,
+
+    -- Run tracking
+    next_run_at TIMESTAMP WITH TIME ZONE,
+    last_run_at TIMESTAMP WITH TIME ZONE,
+    last_status VARCHAR(20), -- 'sent', 'failed'
+    last_error TEXT,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_report_schedules_org ON report_schedules(organization_id);
+CREATE INDEX idx_report_schedules_due ON report_schedules(next_run_at) WHERE is_active = true;
+
+COMMENT ON TABLE report_schedules IS 'Per-organization schedules for emailing analytics reports';
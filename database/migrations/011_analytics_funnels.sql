@@ -0,0 +1,17 @@
+-- Migration: Configurable analytics funnels
+-- Admins define funnels as an ordered list of event steps instead of relying on
+-- the funnels hardcoded in the analytics repository. A user converts on a step
+-- when they trigger its event (matching the optional property filters) after
+-- the previous step and within the conversion window of the first step.
+
+CREATE TABLE IF NOT EXISTS analytics_funnels (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    name VARCHAR(100) NOT NULL UNIQUE, -- used in /admin/funnels/:name
+    description TEXT,
+    steps JSONB NOT NULL DEFAULT '[]', -- [{"name": "...", "event_type": "...", "filters": {"key": "value"}}]
+    conversion_window_seconds BIGINT NOT NULL DEFAULT 0, -- 0 = no limit within the queried range
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+COMMENT ON TABLE analytics_funnels IS 'Admin-defined conversion funnels over analytics events';
@@ -0,0 +1,47 @@
+-- Migration: Multi-touch order attribution
+-- Ad clicks are recorded as touchpoints keyed on the marketplace customer. When
+-- orders are attributed, the clicks within the lookback window before each order
+-- (plus the UTM parameters captured on the order itself) are resolved to campaigns
+-- and the order revenue is split between them under every attribution model.
+-- orders.attributed_campaign_id / attributed_ad_id hold the last-click result.
+
+CREATE TABLE IF NOT EXISTS attribution_touchpoints (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    customer_id VARCHAR(255) NOT NULL, -- matches orders.customer_id
+    campaign_id UUID REFERENCES campaigns(id) ON DELETE SET NULL,
+    ad_id UUID REFERENCES ads(id) ON DELETE SET NULL,
+    click_id VARCHAR(255), -- fbclid, gclid, ttclid
+    utm_source VARCHAR(100),
+    utm_medium VARCHAR(100),
+    utm_campaign VARCHAR(255),
+    utm_content VARCHAR(255),
+    clicked_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_attribution_touchpoints_customer ON attribution_touchpoints(organization_id, customer_id, clicked_at);
+CREATE INDEX idx_attribution_touchpoints_clicked ON attribution_touchpoints(organization_id, clicked_at);
+
+CREATE TABLE IF NOT EXISTS order_attributions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    model VARCHAR(20) NOT NULL, -- 'last_click', 'first_click', 'linear', 'time_decay'
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    ad_id UUID REFERENCES ads(id) ON DELETE SET NULL,
+    touchpoint_id UUID REFERENCES attribution_touchpoints(id) ON DELETE SET NULL,
+    weight DECIMAL(10, 6) NOT NULL, -- share of the order credited to this campaign
+    revenue DECIMAL(15, 4) NOT NULL, -- weight * order total_amount
+    currency VARCHAR(3) DEFAULT 'MYR',
+    order_date DATE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_order_attributions_order ON order_attributions(order_id);
+CREATE INDEX idx_order_attributions_campaign ON order_attributions(organization_id, model, order_date, campaign_id);
+
+COMMENT ON TABLE attribution_touchpoints IS 'Ad clicks used to attribute marketplace orders to campaigns';
+COMMENT ON TABLE order_attributions IS 'Per-model share of each order credited to a campaign';
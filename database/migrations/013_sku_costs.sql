@@ -0,0 +1,20 @@
+-- Migration: SKU cost tables
+-- Sellers upload the unit cost of their SKUs when the marketplace does not supply
+-- it. Order items without a unit_cost fall back to the cost of their SKU in the
+-- order's currency when COGS and profit are calculated.
+
+CREATE TABLE IF NOT EXISTS sku_costs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    sku VARCHAR(100) NOT NULL, -- matches order_items.sku
+    unit_cost DECIMAL(15, 4) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'MYR',
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(organization_id, sku, currency)
+);
+
+CREATE INDEX idx_order_items_sku ON order_items(organization_id, sku);
+
+COMMENT ON TABLE sku_costs IS 'Uploaded unit costs per SKU, used where order items carry no cost';
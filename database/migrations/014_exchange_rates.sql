@@ -0,0 +1,20 @@
+-- Migration: Dated exchange rates
+-- Reference rates imported daily from an ECB-style feed. Metrics are converted at
+-- the rate published on or before their date, so historical spend keeps the rate
+-- of the day it was spent.
+
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    base_currency VARCHAR(3) NOT NULL,
+    quote_currency VARCHAR(3) NOT NULL,
+    rate_date DATE NOT NULL,
+    rate DECIMAL(20, 10) NOT NULL, -- quote units per base unit
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(base_currency, quote_currency, rate_date)
+);
+
+CREATE INDEX idx_exchange_rates_date ON exchange_rates(base_currency, rate_date);
+
+COMMENT ON TABLE exchange_rates IS 'Dated reference exchange rates used to convert metrics at the rate of their date';
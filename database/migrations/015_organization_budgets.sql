@@ -0,0 +1,43 @@
+-- Migration: Organization budgets
+-- Agencies agree one monthly ad budget per client that is split across platforms.
+-- Month-to-date spend is compared against the budget and each platform allocation,
+-- and an alert is raised the first time spend crosses 50%, 80% and 100% in a month.
+
+CREATE TABLE IF NOT EXISTS organization_budgets (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL UNIQUE REFERENCES organizations(id) ON DELETE CASCADE,
+    monthly_amount DECIMAL(15, 2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'MYR',
+    platform_allocations JSONB, -- e.g. {"meta": 5000, "tiktok": 3000}, in the budget currency
+    is_active BOOLEAN DEFAULT true,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS budget_alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    budget_id UUID NOT NULL REFERENCES organization_budgets(id) ON DELETE CASCADE,
+    month DATE NOT NULL, -- first day of the month
+    platform VARCHAR(20) NOT NULL DEFAULT '', -- '' for the whole budget
+    alert_type VARCHAR(20) NOT NULL, -- 'notice', 'warning', 'exceeded'
+    threshold INTEGER NOT NULL, -- 50, 80, 100
+    spend DECIMAL(15, 2),
+    budget DECIMAL(15, 2),
+    currency VARCHAR(3),
+    usage_percent DOUBLE PRECISION,
+
+    -- Notification
+    notified_at TIMESTAMP WITH TIME ZONE,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    acknowledged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(organization_id, month, platform, threshold)
+);
+
+CREATE INDEX idx_budget_alerts_org ON budget_alerts(organization_id, month);
+
+COMMENT ON TABLE organization_budgets IS 'Monthly cross-platform ad budget per organization';
+COMMENT ON TABLE budget_alerts IS 'Budget thresholds crossed by month-to-date spend';
@@ -0,0 +1,40 @@
+-- Migration: Metric anomalies
+-- After each metrics sync, yesterday's spend, CTR, CPC, CPA and ROAS of every synced
+-- campaign are compared with a rolling baseline (the same weekday over the previous
+-- eight weeks, or every day of the previous four weeks when there is too little history).
+-- Values far from the baseline median, measured in median absolute deviations, are
+-- recorded here and pushed to the organization.
+
+CREATE TABLE IF NOT EXISTS metric_anomalies (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    campaign_name VARCHAR(500),
+    platform platform_type NOT NULL,
+    metric_date DATE NOT NULL,
+    metric VARCHAR(20) NOT NULL, -- 'spend', 'ctr', 'cpc', 'cpa', 'roas'
+    value DOUBLE PRECISION,
+    expected DOUBLE PRECISION, -- baseline median
+    deviation DOUBLE PRECISION, -- baseline median absolute deviation
+    score DOUBLE PRECISION, -- robust z-score, negative for drops
+    change_percent DOUBLE PRECISION,
+    direction VARCHAR(10) NOT NULL, -- 'spike', 'drop'
+    severity VARCHAR(20) NOT NULL, -- 'low', 'medium', 'high', 'critical'
+    baseline VARCHAR(20) NOT NULL, -- 'day_of_week', 'rolling'
+    baseline_days INTEGER,
+    currency VARCHAR(3),
+
+    -- Notification
+    notified_at TIMESTAMP WITH TIME ZONE,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    acknowledged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(campaign_id, metric_date, metric)
+);
+
+CREATE INDEX idx_metric_anomalies_org ON metric_anomalies(organization_id, metric_date DESC);
+CREATE INDEX idx_metric_anomalies_unacknowledged ON metric_anomalies(organization_id) WHERE acknowledged_at IS NULL;
+
+COMMENT ON TABLE metric_anomalies IS 'Daily campaign metrics that deviate from their rolling baseline';
@@ -0,0 +1,63 @@
+-- Migration: Alert rules
+-- Organizations write rules such as "roas < 1.5 for 3 days" or "spend > 500" over
+-- daily campaign metrics, scoped to platforms, ad accounts or campaigns. Rules are
+-- evaluated for every campaign in scope after each metrics sync; each trigger is
+-- recorded in alert_events and delivered by email, SSE and/or webhook.
+
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    description TEXT,
+    expression TEXT NOT NULL, -- alert rule language, e.g. 'roas < 1.5 for 3 days'
+    currency VARCHAR(3) NOT NULL DEFAULT 'MYR', -- currency of monetary thresholds
+
+    -- Scope; NULL or empty matches every active campaign
+    platforms JSONB, -- e.g. ["meta"]
+    ad_account_ids JSONB,
+    campaign_ids JSONB,
+
+    -- Delivery
+    channels JSONB NOT NULL DEFAULT '[]', -- e.g. ["email", "sse", "webhook"]
+    webhook_url VARCHAR(1000),
+    webhook_secret VARCHAR(255),
+    cooldown_minutes INTEGER NOT NULL DEFAULT 1440,
+
+    is_active BOOLEAN DEFAULT true,
+    last_triggered_at TIMESTAMP WITH TIME ZONE,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_alert_rules_org ON alert_rules(organization_id) WHERE is_active = true;
+
+CREATE TABLE IF NOT EXISTS alert_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    rule_id UUID NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+    rule_name VARCHAR(255),
+    expression TEXT, -- as evaluated
+    campaign_id UUID NOT NULL REFERENCES campaigns(id) ON DELETE CASCADE,
+    campaign_name VARCHAR(500),
+    platform platform_type NOT NULL,
+    metric_date DATE NOT NULL, -- last day of the evaluated window
+    metric_values JSONB, -- metrics used by the rule on metric_date
+    currency VARCHAR(3),
+
+    -- Delivery
+    delivered_channels JSONB,
+    delivery_error TEXT,
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    acknowledged_by UUID REFERENCES users(id) ON DELETE SET NULL,
+
+    triggered_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    UNIQUE(rule_id, campaign_id, metric_date)
+);
+
+CREATE INDEX idx_alert_events_org ON alert_events(organization_id, triggered_at DESC);
+CREATE INDEX idx_alert_events_rule_campaign ON alert_events(rule_id, campaign_id, triggered_at DESC);
+
+COMMENT ON TABLE alert_rules IS 'User-defined alert rules over daily campaign metrics';
+COMMENT ON TABLE alert_events IS 'History of triggered alert rules';
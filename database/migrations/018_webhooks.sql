@@ -0,0 +1,52 @@
+-- Migration: Outbound webhooks
+-- Organizations on plans with webhooks register endpoint URLs and subscribe them
+-- to events such as sync.completed, sync.failed, token.expired, alert.triggered
+-- and report.ready. Each event is delivered to every subscribed endpoint as a
+-- request signed with the endpoint's secret; failed deliveries are retried with
+-- backoff and can be replayed from the delivery log.
+
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    url VARCHAR(1000) NOT NULL,
+    description TEXT,
+    secret VARCHAR(255) NOT NULL, -- HMAC-SHA256 key of the signature header
+    events JSONB NOT NULL DEFAULT '[]', -- e.g. ["sync.completed", "alert.triggered"]
+    is_active BOOLEAN DEFAULT true,
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_endpoints_org ON webhook_endpoints(organization_id) WHERE is_active = true;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    endpoint_id UUID NOT NULL REFERENCES webhook_endpoints(id) ON DELETE CASCADE,
+    event_id UUID NOT NULL, -- shared by the replays of an event
+    event_type VARCHAR(50) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, succeeded, failed
+    replay_of UUID REFERENCES webhook_deliveries(id) ON DELETE SET NULL,
+
+    -- Attempts; the response of the latest attempt is kept
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE,
+    last_attempt_at TIMESTAMP WITH TIME ZONE,
+    response_status INTEGER,
+    response_body TEXT,
+    duration_ms INTEGER,
+    error TEXT,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_webhook_deliveries_org ON webhook_deliveries(organization_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_endpoint ON webhook_deliveries(endpoint_id, created_at DESC);
+CREATE INDEX idx_webhook_deliveries_due ON webhook_deliveries(next_attempt_at) WHERE status = 'pending';
+
+COMMENT ON TABLE webhook_endpoints IS 'Organization endpoints receiving outbound webhooks';
+COMMENT ON TABLE webhook_deliveries IS 'Log of outbound webhook deliveries and their latest attempt';
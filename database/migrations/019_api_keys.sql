@@ -0,0 +1,27 @@
+-- Migration: Organization API keys
+-- Organizations on plans with API access create keys for scripts and BI tools.
+-- Keys authenticate like access tokens, limited to their scopes, which are the
+-- permission strings of organization roles (e.g. "analytics:read"). Only the
+-- SHA-256 hash of a key is stored.
+
+CREATE TABLE IF NOT EXISTS api_keys (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    name VARCHAR(255) NOT NULL,
+    prefix VARCHAR(32) NOT NULL, -- first characters of the key, shown in listings
+    key_hash VARCHAR(64) NOT NULL UNIQUE, -- hex SHA-256 of the key
+    scopes JSONB NOT NULL DEFAULT '[]', -- e.g. ["analytics:read", "campaigns:read"]
+    created_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    expires_at TIMESTAMP WITH TIME ZONE,
+    last_used_at TIMESTAMP WITH TIME ZONE,
+    last_used_ip VARCHAR(45),
+    rotated_at TIMESTAMP WITH TIME ZONE,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoked_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_api_keys_org ON api_keys(organization_id, created_at DESC);
+
+COMMENT ON TABLE api_keys IS 'Organization API keys for programmatic access';
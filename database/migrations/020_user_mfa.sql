@@ -0,0 +1,21 @@
+-- Migration: TOTP multi-factor authentication
+-- Users enrol an authenticator app; once the first code is confirmed, logins
+-- need a code or one of the single-use recovery codes after the password.
+-- Organizations can require it for owners and admins through the
+-- "require_mfa" key of organizations.settings.
+
+CREATE TABLE IF NOT EXISTS user_mfa (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL UNIQUE REFERENCES users(id) ON DELETE CASCADE,
+    secret VARCHAR(255) NOT NULL, -- base32 TOTP secret, AES-256-GCM encrypted when ENCRYPTION_KEY is set
+    enabled_at TIMESTAMP WITH TIME ZONE, -- NULL while enrolment is pending
+    last_used_step BIGINT NOT NULL DEFAULT 0, -- time step of the last accepted code
+    recovery_codes JSONB NOT NULL DEFAULT '[]', -- bcrypt hashes of unused recovery codes
+    recovery_codes_generated_at TIMESTAMP WITH TIME ZONE,
+    failed_attempts INTEGER NOT NULL DEFAULT 0, -- consecutive wrong codes
+    locked_until TIMESTAMP WITH TIME ZONE, -- codes are refused until then after too many wrong ones
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+COMMENT ON TABLE user_mfa IS 'TOTP second factors and recovery codes of users';
@@ -0,0 +1,27 @@
+-- Migration: Server-side login sessions
+-- Each login starts a session: a family of refresh tokens where every refresh
+-- exchanges the latest token for a new one. Presenting a token the session
+-- has already moved past means it was copied, and the session is revoked.
+-- Logout and per-device revoke end a session; access tokens issued for it
+-- stay valid until they expire.
+
+CREATE TABLE IF NOT EXISTS user_sessions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(), -- the sid claim of the session's tokens
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    current_token_id VARCHAR(64) NOT NULL UNIQUE, -- jti of the latest refresh token
+    previous_token_id VARCHAR(64), -- jti of the token it replaced
+    user_agent VARCHAR(500),
+    ip_address VARCHAR(45),
+    last_used_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    rotated_at TIMESTAMP WITH TIME ZONE,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    revoked_reason VARCHAR(50), -- logout, revoked, reuse_detected, password_change, user_disabled
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_user_sessions_user_active ON user_sessions(user_id, last_used_at DESC) WHERE revoked_at IS NULL;
+
+COMMENT ON TABLE user_sessions IS 'Login sessions and their refresh token rotation state';
@@ -0,0 +1,149 @@
+-- Migration: Sync job queue
+-- The scheduler queues a sync_jobs row per sync; workers claim the highest
+-- priority ready job with SELECT ... FOR UPDATE SKIP LOCKED and hold a lease on
+-- it, renewed by heartbeats. A job whose lease expires is requeued by the
+-- reaper, so a crashed worker never strands it. sync_states keeps the per
+-- account schedule and health the scheduler reads.
+
+CREATE TABLE IF NOT EXISTS sync_states (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    connected_account_id UUID NOT NULL UNIQUE REFERENCES connected_accounts(id) ON DELETE CASCADE,
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    platform platform_type NOT NULL,
+
+    -- Last successful sync timestamps
+    last_hourly_sync TIMESTAMP WITH TIME ZONE,
+    last_daily_sync TIMESTAMP WITH TIME ZONE,
+    last_full_sync TIMESTAMP WITH TIME ZONE,
+    last_metrics_sync TIMESTAMP WITH TIME ZONE,
+
+    -- Current sync
+    current_sync_id UUID,
+    current_sync_status VARCHAR(20) DEFAULT 'pending', -- 'pending', 'running', 'completed', 'failed', 'cancelled', 'retrying', 'rate_limited', 'paused'
+    is_syncing BOOLEAN DEFAULT false,
+
+    -- Statistics
+    total_syncs BIGINT DEFAULT 0,
+    successful_syncs BIGINT DEFAULT 0,
+    failed_syncs BIGINT DEFAULT 0,
+    consecutive_failures INTEGER DEFAULT 0,
+
+    -- Rate limit tracking
+    rate_limit_reset_at TIMESTAMP WITH TIME ZONE,
+    rate_limit_hits INTEGER DEFAULT 0,
+
+    next_scheduled_sync TIMESTAMP WITH TIME ZONE,
+    last_error TEXT,
+    last_error_at TIMESTAMP WITH TIME ZONE,
+    metadata JSONB DEFAULT '{}',
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sync_states_org ON sync_states(organization_id);
+CREATE INDEX idx_sync_states_platform ON sync_states(platform);
+
+CREATE TABLE IF NOT EXISTS sync_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    organization_id UUID NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    connected_account_id UUID NOT NULL REFERENCES connected_accounts(id) ON DELETE CASCADE,
+    platform platform_type NOT NULL,
+
+    -- Job configuration
+    sync_type VARCHAR(20) NOT NULL, -- 'hourly', 'daily', 'manual', 'webhook', 'initial', 'incremental'
+    sync_scope VARCHAR(20) NOT NULL, -- 'account', 'campaign', 'metrics', 'structure', 'orders'
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- 'pending', 'running', 'completed', 'failed', 'cancelled', 'retrying', 'rate_limited', 'paused'
+    priority INTEGER NOT NULL DEFAULT 0, -- higher runs first
+    campaign_id UUID REFERENCES campaigns(id) ON DELETE CASCADE,
+    date_range_start TIMESTAMP WITH TIME ZONE,
+    date_range_end TIMESTAMP WITH TIME ZONE,
+
+    -- Execution tracking
+    scheduled_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    started_at TIMESTAMP WITH TIME ZONE,
+    completed_at TIMESTAMP WITH TIME ZONE,
+    duration_seconds INTEGER,
+
+    -- Lease held by the worker running the job
+    worker_id VARCHAR(100),
+    lease_expires_at TIMESTAMP WITH TIME ZONE,
+    heartbeat_at TIMESTAMP WITH TIME ZONE,
+
+    -- Retry management
+    max_retries INTEGER DEFAULT 3,
+    retry_count INTEGER DEFAULT 0,
+    retry_after TIMESTAMP WITH TIME ZONE,
+    last_retry_error TEXT,
+
+    -- Progress and results
+    progress_percent INTEGER DEFAULT 0,
+    progress_message TEXT,
+    checkpoint JSONB DEFAULT '{}', -- work already saved, skipped when the job resumes
+    records_processed INTEGER DEFAULT 0,
+    records_failed INTEGER DEFAULT 0,
+    error_message TEXT,
+    error_code VARCHAR(50),
+
+    triggered_by VARCHAR(100), -- 'scheduler', 'webhook', 'user:{id}'
+    triggered_by_user UUID REFERENCES users(id) ON DELETE SET NULL,
+    metadata JSONB DEFAULT '{}',
+
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sync_jobs_claim ON sync_jobs(priority DESC, scheduled_at) WHERE status IN ('pending', 'retrying');
+CREATE INDEX idx_sync_jobs_lease ON sync_jobs(lease_expires_at) WHERE status = 'running';
+CREATE INDEX idx_sync_jobs_account ON sync_jobs(connected_account_id, created_at DESC);
+CREATE INDEX idx_sync_jobs_org ON sync_jobs(organization_id, created_at DESC);
+
+-- Every process running sync jobs runs the scheduler too; a scheduled job still
+-- queued for an account is not queued again by another process
+CREATE UNIQUE INDEX idx_sync_jobs_scheduled_once ON sync_jobs(connected_account_id, sync_type, sync_scope)
+    WHERE status = 'pending' AND triggered_by = 'scheduler';
+
+CREATE TABLE IF NOT EXISTS sync_error_logs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sync_job_id UUID NOT NULL REFERENCES sync_jobs(id) ON DELETE CASCADE,
+    connected_account_id UUID NOT NULL REFERENCES connected_accounts(id) ON DELETE CASCADE,
+    platform platform_type NOT NULL,
+    error_type VARCHAR(50) NOT NULL, -- 'api_error', 'rate_limit', 'auth', 'validation', 'network'
+    error_code VARCHAR(50),
+    error_message TEXT NOT NULL,
+    stack_trace TEXT,
+    request_path VARCHAR(500),
+    request_method VARCHAR(10),
+    response_code INTEGER,
+    is_retryable BOOLEAN DEFAULT false,
+    retry_after TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_sync_error_logs_job ON sync_error_logs(sync_job_id);
+CREATE INDEX idx_sync_error_logs_account ON sync_error_logs(connected_account_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS retry_queue_entries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    sync_job_id UUID NOT NULL REFERENCES sync_jobs(id) ON DELETE CASCADE,
+    connected_account_id UUID NOT NULL REFERENCES connected_accounts(id) ON DELETE CASCADE,
+    platform platform_type NOT NULL,
+    retry_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    retry_count INTEGER DEFAULT 0,
+    max_retries INTEGER DEFAULT 3,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_retry_queue_entries_retry_at ON retry_queue_entries(retry_at);
+CREATE INDEX idx_retry_queue_entries_job ON retry_queue_entries(sync_job_id);
+
+-- Existing accounts start with an empty state so the scheduler picks them up
+INSERT INTO sync_states (connected_account_id, organization_id, platform)
+SELECT id, organization_id, platform FROM connected_accounts
+ON CONFLICT (connected_account_id) DO NOTHING;
+
+COMMENT ON TABLE sync_states IS 'Per connected account sync schedule, health and rate limit state';
+COMMENT ON TABLE sync_jobs IS 'Sync job queue claimed by workers with SKIP LOCKED and held under a lease';
+COMMENT ON TABLE sync_error_logs IS 'Errors raised while running sync jobs';
+COMMENT ON TABLE retry_queue_entries IS 'Failed sync jobs waiting for their next attempt';
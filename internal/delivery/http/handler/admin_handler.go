@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"time"
@@ -34,7 +35,11 @@ func (h *AdminHandler) RegisterRoutes(r *gin.RouterGroup) {
 	r.GET("/users/:id", h.GetUserProfile)
 
 	// Funnels
+	r.GET("/funnels", h.ListFunnels)
+	r.POST("/funnels", h.CreateFunnel)
 	r.GET("/funnels/:name", h.GetFunnel)
+	r.PUT("/funnels/:name", h.UpdateFunnel)
+	r.DELETE("/funnels/:name", h.DeleteFunnel)
 
 	// Time series
 	r.GET("/timeseries/users", h.GetUsersTimeSeries)
@@ -200,6 +205,118 @@ func (h *AdminHandler) GetFunnel(c *gin.Context) {
 	c.JSON(http.StatusOK, funnel)
 }
 
+// FunnelRequest is the request body for creating or updating a funnel
+type FunnelRequest struct {
+	Name                    string                        `json:"name"`
+	Description             string                        `json:"description"`
+	Steps                   []domain.FunnelStepDefinition `json:"steps" binding:"required"`
+	ConversionWindowSeconds int64                         `json:"conversion_window_seconds"`
+}
+
+// ListFunnels returns the configured funnel definitions
+func (h *AdminHandler) ListFunnels(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	funnels, err := h.repo.ListFunnelDefinitions(ctx)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"funnels": funnels,
+	})
+}
+
+// CreateFunnel stores a new funnel definition
+func (h *AdminHandler) CreateFunnel(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req FunnelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	def := &domain.FunnelDefinition{
+		Name:                    req.Name,
+		Description:             req.Description,
+		Steps:                   req.Steps,
+		ConversionWindowSeconds: req.ConversionWindowSeconds,
+	}
+	if err := def.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.CreateFunnelDefinition(ctx, def); err != nil {
+		if errors.Is(err, domain.ErrFunnelExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, def)
+}
+
+// UpdateFunnel replaces the steps of an existing funnel definition
+func (h *AdminHandler) UpdateFunnel(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var req FunnelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request body", "details": err.Error()})
+		return
+	}
+
+	// Funnels are addressed by name, so it cannot be changed
+	def := &domain.FunnelDefinition{
+		Name:                    c.Param("name"),
+		Description:             req.Description,
+		Steps:                   req.Steps,
+		ConversionWindowSeconds: req.ConversionWindowSeconds,
+	}
+	if err := def.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.repo.UpdateFunnelDefinition(ctx, def); err != nil {
+		if errors.Is(err, domain.ErrFunnelNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	updated, err := h.repo.GetFunnelDefinition(ctx, def.Name)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, updated)
+}
+
+// DeleteFunnel removes a funnel definition
+func (h *AdminHandler) DeleteFunnel(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if err := h.repo.DeleteFunnelDefinition(ctx, c.Param("name")); err != nil {
+		if errors.Is(err, domain.ErrFunnelNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // GetUsersTimeSeries returns users time series data
 func (h *AdminHandler) GetUsersTimeSeries(c *gin.Context) {
 	ctx := c.Request.Context()
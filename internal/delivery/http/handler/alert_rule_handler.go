@@ -0,0 +1,314 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/delivery/http/middleware"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/usecase/analytics"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AlertRuleHandler handles alert rule and alert history HTTP requests
+type AlertRuleHandler struct {
+	alertRuleService *analytics.AlertRuleService
+}
+
+// NewAlertRuleHandler creates a new alert rule handler
+func NewAlertRuleHandler(alertRuleService *analytics.AlertRuleService) *AlertRuleHandler {
+	return &AlertRuleHandler{alertRuleService: alertRuleService}
+}
+
+// AlertRuleRequest is the API request for creating or updating an alert rule
+type AlertRuleRequest struct {
+	Name            string      `json:"name" binding:"required"`
+	Description     string      `json:"description,omitempty"`
+	Expression      string      `json:"expression" binding:"required"` // e.g. "roas < 1.5 for 3 days"
+	Currency        string      `json:"currency,omitempty"`
+	Platforms       []string    `json:"platforms,omitempty"`
+	AdAccountIDs    []uuid.UUID `json:"ad_account_ids,omitempty"`
+	CampaignIDs     []uuid.UUID `json:"campaign_ids,omitempty"`
+	Channels        []string    `json:"channels" binding:"required"`
+	WebhookURL      string      `json:"webhook_url,omitempty"`
+	WebhookSecret   string      `json:"webhook_secret,omitempty"`
+	CooldownMinutes *int        `json:"cooldown_minutes,omitempty"`
+	IsActive        *bool       `json:"is_active,omitempty"`
+}
+
+// toInput converts the request to the alert rule service input
+func (r *AlertRuleRequest) toInput() analytics.AlertRuleInput {
+	input := analytics.AlertRuleInput{
+		Name:            r.Name,
+		Description:     r.Description,
+		Expression:      r.Expression,
+		Currency:        r.Currency,
+		AdAccountIDs:    r.AdAccountIDs,
+		CampaignIDs:     r.CampaignIDs,
+		WebhookURL:      r.WebhookURL,
+		WebhookSecret:   r.WebhookSecret,
+		CooldownMinutes: r.CooldownMinutes,
+		IsActive:        r.IsActive,
+	}
+	for _, platform := range r.Platforms {
+		input.Platforms = append(input.Platforms, entity.Platform(strings.ToLower(platform)))
+	}
+	for _, channel := range r.Channels {
+		input.Channels = append(input.Channels, entity.AlertChannel(strings.ToLower(channel)))
+	}
+	return input
+}
+
+// ListAlertRules lists the organization's alert rules
+// @Summary List alert rules
+// @Tags Alerts
+// @Produce json
+// @Success 200 {array} entity.AlertRule
+// @Router /api/v1/alerts/rules [get]
+func (h *AlertRuleHandler) ListAlertRules(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	rules, err := h.alertRuleService.ListRules(c.Request.Context(), orgID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rules,
+	})
+}
+
+// GetAlertRule returns an alert rule
+// @Summary Get an alert rule
+// @Tags Alerts
+// @Produce json
+// @Param ruleId path string true "Rule ID"
+// @Success 200 {object} entity.AlertRule
+// @Router /api/v1/alerts/rules/{ruleId} [get]
+func (h *AlertRuleHandler) GetAlertRule(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	ruleID, err := uuid.Parse(c.Param("ruleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return
+	}
+
+	rule, err := h.alertRuleService.GetRule(c.Request.Context(), orgID, ruleID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// CreateAlertRule creates an alert rule
+// @Summary Create an alert rule
+// @Description Rules compare daily campaign metrics with thresholds, e.g. "roas < 1.5 for 3 days" or "spend > 500", and are evaluated after every metrics sync. Fields: spend, revenue, impressions, clicks, conversions, reach, likes, comments, shares, roas, cpa, ctr, cpc, cpm, conversion_rate. Channels: email, sse, webhook.
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param request body AlertRuleRequest true "Alert rule"
+// @Success 201 {object} entity.AlertRule
+// @Router /api/v1/alerts/rules [post]
+func (h *AlertRuleHandler) CreateAlertRule(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+	userID, _ := middleware.GetUserID(c)
+
+	var req AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.alertRuleService.CreateRule(c.Request.Context(), orgID, userID, req.toInput())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// UpdateAlertRule updates an alert rule
+// @Summary Update an alert rule
+// @Description Replaces the rule; the webhook secret is kept when none is given
+// @Tags Alerts
+// @Accept json
+// @Produce json
+// @Param ruleId path string true "Rule ID"
+// @Param request body AlertRuleRequest true "Alert rule"
+// @Success 200 {object} entity.AlertRule
+// @Router /api/v1/alerts/rules/{ruleId} [put]
+func (h *AlertRuleHandler) UpdateAlertRule(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	ruleID, err := uuid.Parse(c.Param("ruleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return
+	}
+
+	var req AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	rule, err := h.alertRuleService.UpdateRule(c.Request.Context(), orgID, ruleID, req.toInput())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// DeleteAlertRule deletes an alert rule
+// @Summary Delete an alert rule
+// @Tags Alerts
+// @Param ruleId path string true "Rule ID"
+// @Success 204
+// @Router /api/v1/alerts/rules/{ruleId} [delete]
+func (h *AlertRuleHandler) DeleteAlertRule(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	ruleID, err := uuid.Parse(c.Param("ruleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+		return
+	}
+
+	if err := h.alertRuleService.DeleteRule(c.Request.Context(), orgID, ruleID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// EvaluateAlertRules evaluates the organization's active alert rules now
+// @Summary Evaluate alert rules
+// @Description Normally runs after every metrics sync and hourly. Rules in their cooldown or that already triggered for the day are not triggered again.
+// @Tags Alerts
+// @Produce json
+// @Success 200 {array} entity.AlertEvent
+// @Router /api/v1/alerts/rules/evaluate [post]
+func (h *AlertRuleHandler) EvaluateAlertRules(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	alerts, err := h.alertRuleService.EvaluateRules(c.Request.Context(), orgID, nil, time.Now())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if alerts == nil {
+		alerts = []entity.AlertEvent{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    alerts,
+	})
+}
+
+// ListAlertHistory lists the alerts triggered by the organization's rules
+// @Summary List alert history
+// @Tags Alerts
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param rule_id query string false "Rule ID"
+// @Param campaign_id query string false "Campaign ID"
+// @Param unacknowledged query bool false "Only alerts not yet acknowledged"
+// @Param limit query int false "Maximum number of alerts" default(50)
+// @Success 200 {array} entity.AlertEvent
+// @Router /api/v1/alerts/history [get]
+func (h *AlertRuleHandler) ListAlertHistory(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	dateRange := parseDateRangeFromQuery(c)
+	limit, _ := parseInt(c.DefaultQuery("limit", "50"))
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+
+	filter := entity.AlertEventFilter{
+		OrganizationID: orgID,
+		DateRange:      &dateRange,
+		Unacknowledged: c.Query("unacknowledged") == "true",
+		Pagination:     &entity.Pagination{Page: 1, PageSize: limit},
+	}
+	if ruleID := c.Query("rule_id"); ruleID != "" {
+		id, err := uuid.Parse(ruleID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid rule ID"})
+			return
+		}
+		filter.RuleID = &id
+	}
+	if campaignID := c.Query("campaign_id"); campaignID != "" {
+		id, err := uuid.Parse(campaignID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+			return
+		}
+		filter.CampaignID = &id
+	}
+
+	events, total, err := h.alertRuleService.ListEvents(c.Request.Context(), filter)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    events,
+		"total":   total,
+	})
+}
+
+// AcknowledgeAlert marks a triggered alert as seen
+// @Summary Acknowledge an alert
+// @Tags Alerts
+// @Param eventId path string true "Alert ID"
+// @Success 204
+// @Router /api/v1/alerts/history/{eventId}/acknowledge [post]
+func (h *AlertRuleHandler) AcknowledgeAlert(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+	userID, _ := middleware.GetUserID(c)
+
+	eventID, err := uuid.Parse(c.Param("eventId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert ID"})
+		return
+	}
+
+	if err := h.alertRuleService.AcknowledgeEvent(c.Request.Context(), orgID, eventID, userID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
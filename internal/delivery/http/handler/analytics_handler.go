@@ -3,7 +3,9 @@ package handler
 import (
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"strings"
 	"time"
 
 	"github.com/ads-aggregator/ads-aggregator/internal/delivery/http/middleware"
@@ -439,23 +441,602 @@ func parseInt(s string) (int, error) {
 	return result, err
 }
 
-// GenerateReport generates a downloadable report
+// ReportJobResponse is the API response for an asynchronous report job
+type ReportJobResponse struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Format      string     `json:"format"`
+	StartDate   string     `json:"start_date"`
+	EndDate     string     `json:"end_date"`
+	FileName    string     `json:"file_name,omitempty"`
+	SizeBytes   int64      `json:"size_bytes,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	StatusURL   string     `json:"status_url"`
+	DownloadURL string     `json:"download_url,omitempty"`
+}
+
+// newReportJobResponse converts a report job to its API response
+func newReportJobResponse(job *entity.ReportJob) ReportJobResponse {
+	statusURL := "/api/v1/analytics/reports/" + job.ID.String()
+	resp := ReportJobResponse{
+		ID:          job.ID.String(),
+		Status:      string(job.Status),
+		Format:      string(job.Format),
+		StartDate:   job.DateRangeStart.Format("2006-01-02"),
+		EndDate:     job.DateRangeEnd.Format("2006-01-02"),
+		Error:       job.ErrorMessage,
+		CreatedAt:   job.CreatedAt,
+		CompletedAt: job.CompletedAt,
+		StatusURL:   statusURL,
+	}
+	if job.IsDownloadable() {
+		resp.FileName = job.FileName
+		resp.SizeBytes = job.SizeBytes
+		resp.ExpiresAt = job.ExpiresAt
+		resp.DownloadURL = statusURL + "/download"
+	}
+	return resp
+}
+
+// GenerateReport queues a report for asynchronous generation
+// @Summary Generate a client report
+// @Description Queues a report job and returns its ID; poll the status URL until it is completed
+// @Tags Analytics
+// @Produce json
+// @Param format query string false "Report format (json, csv, xlsx, pdf)" default(pdf)
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Success 202 {object} ReportJobResponse
+// @Router /api/v1/analytics/reports/generate [post]
 func (h *AnalyticsHandler) GenerateReport(c *gin.Context) {
 	orgID, _ := middleware.GetOrgID(c)
 	dateRange := parseDateRangeFromQuery(c)
 
-	report, err := h.analyticsService.GenerateReport(c.Request.Context(), orgID, dateRange)
+	req := analytics.ReportJobRequest{
+		OrganizationID: orgID,
+		DateRange:      dateRange,
+		Format:         entity.ReportFormat(c.DefaultQuery("format", string(entity.ReportFormatPDF))),
+	}
+	if userID, ok := middleware.GetUserID(c); ok {
+		req.RequestedBy = &userID
+	}
+
+	// Organization branding is only applied for plans with white-labelling
+	req.WhiteLabel = h.whiteLabelEnabled(c)
+
+	job, err := h.analyticsService.SubmitReportJob(c.Request.Context(), req)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"data": report})
+	c.JSON(http.StatusAccepted, gin.H{
+		"success": true,
+		"data":    newReportJobResponse(job),
+	})
+}
+
+// ListReports lists recent report jobs for the organization
+// @Summary List report jobs
+// @Tags Analytics
+// @Produce json
+// @Param limit query int false "Maximum number of jobs" default(20)
+// @Success 200 {array} ReportJobResponse
+// @Router /api/v1/analytics/reports [get]
+func (h *AnalyticsHandler) ListReports(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	limit, _ := parseInt(c.DefaultQuery("limit", "20"))
+
+	jobs, err := h.analyticsService.ListReportJobs(c.Request.Context(), orgID, limit)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	reports := make([]ReportJobResponse, 0, len(jobs))
+	for i := range jobs {
+		reports = append(reports, newReportJobResponse(&jobs[i]))
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    reports,
+	})
 }
 
-// GetReport retrieves a generated report
+// GetReport returns the status of a report job
+// @Summary Get report status
+// @Description Returns the job status and, once completed, a download link
+// @Tags Analytics
+// @Produce json
+// @Param reportId path string true "Report ID"
+// @Success 200 {object} ReportJobResponse
+// @Router /api/v1/analytics/reports/{reportId} [get]
 func (h *AnalyticsHandler) GetReport(c *gin.Context) {
-	c.JSON(http.StatusOK, gin.H{"data": nil})
+	orgID, _ := middleware.GetOrgID(c)
+
+	reportID, err := uuid.Parse(c.Param("reportId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	job, err := h.analyticsService.GetReportJob(c.Request.Context(), orgID, reportID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    newReportJobResponse(job),
+	})
+}
+
+// DownloadReport streams a completed report file
+// @Summary Download a generated report
+// @Tags Analytics
+// @Produce application/pdf,text/csv,application/vnd.openxmlformats-officedocument.spreadsheetml.sheet,json
+// @Param reportId path string true "Report ID"
+// @Success 200 {file} file
+// @Failure 409 {object} map[string]interface{} "Report not ready"
+// @Failure 410 {object} map[string]interface{} "Report expired"
+// @Router /api/v1/analytics/reports/{reportId}/download [get]
+func (h *AnalyticsHandler) DownloadReport(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	reportID, err := uuid.Parse(c.Param("reportId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid report ID"})
+		return
+	}
+
+	job, data, err := h.analyticsService.GetReportArtifact(c.Request.Context(), orgID, reportID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", "attachment; filename="+job.FileName)
+	c.Data(http.StatusOK, job.ContentType, data)
+}
+
+// ReportScheduleRequest is the API request for creating or updating a report schedule
+type ReportScheduleRequest struct {
+	Name           string   `json:"name" binding:"required"`
+	CronExpression string   `json:"cron_expression" binding:"required"`
+	Timezone       string   `json:"timezone,omitempty"`
+	DatePreset     string   `json:"date_preset" binding:"required"`
+	Format         string   `json:"format" binding:"required"`
+	Recipients     []string `json:"recipients" binding:"required"`
+	IsActive       *bool    `json:"is_active,omitempty"`
+}
+
+// toInput converts the request to the analytics service input
+func (r *ReportScheduleRequest) toInput() analytics.ReportScheduleInput {
+	return analytics.ReportScheduleInput{
+		Name:           r.Name,
+		CronExpression: r.CronExpression,
+		Timezone:       r.Timezone,
+		DatePreset:     entity.ReportDatePreset(r.DatePreset),
+		Format:         entity.ReportFormat(r.Format),
+		Recipients:     r.Recipients,
+		IsActive:       r.IsActive,
+	}
+}
+
+// ListReportSchedules lists the organization's report schedules
+// @Summary List report schedules
+// @Tags Analytics
+// @Produce json
+// @Success 200 {array} entity.ReportSchedule
+// @Router /api/v1/analytics/reports/schedules [get]
+func (h *AnalyticsHandler) ListReportSchedules(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	schedules, err := h.analyticsService.ListReportSchedules(c.Request.Context(), orgID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    schedules,
+	})
+}
+
+// CreateReportSchedule creates a report schedule
+// @Summary Schedule report delivery by email
+// @Description Emails a report to the recipients whenever the cron expression fires in the given timezone
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param request body ReportScheduleRequest true "Report schedule"
+// @Success 201 {object} entity.ReportSchedule
+// @Router /api/v1/analytics/reports/schedules [post]
+func (h *AnalyticsHandler) CreateReportSchedule(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	var req ReportScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	var createdBy *uuid.UUID
+	if userID, ok := middleware.GetUserID(c); ok {
+		createdBy = &userID
+	}
+
+	schedule, err := h.analyticsService.CreateReportSchedule(c.Request.Context(), orgID, createdBy, h.whiteLabelEnabled(c), req.toInput())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    schedule,
+	})
+}
+
+// UpdateReportSchedule updates a report schedule
+// @Summary Update a report schedule
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param scheduleId path string true "Schedule ID"
+// @Param request body ReportScheduleRequest true "Report schedule"
+// @Success 200 {object} entity.ReportSchedule
+// @Router /api/v1/analytics/reports/schedules/{scheduleId} [put]
+func (h *AnalyticsHandler) UpdateReportSchedule(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	scheduleID, err := uuid.Parse(c.Param("scheduleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID"})
+		return
+	}
+
+	var req ReportScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	schedule, err := h.analyticsService.UpdateReportSchedule(c.Request.Context(), orgID, scheduleID, h.whiteLabelEnabled(c), req.toInput())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    schedule,
+	})
+}
+
+// DeleteReportSchedule deletes a report schedule
+// @Summary Delete a report schedule
+// @Tags Analytics
+// @Param scheduleId path string true "Schedule ID"
+// @Success 204
+// @Router /api/v1/analytics/reports/schedules/{scheduleId} [delete]
+func (h *AnalyticsHandler) DeleteReportSchedule(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	scheduleID, err := uuid.Parse(c.Param("scheduleId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid schedule ID"})
+		return
+	}
+
+	if err := h.analyticsService.DeleteReportSchedule(c.Request.Context(), orgID, scheduleID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetCampaignAttribution returns attributed order revenue per campaign
+// @Summary Get campaign attribution
+// @Description Compares revenue attributed from marketplace orders with the platform-reported conversion value
+// @Tags Analytics
+// @Produce json
+// @Param model query string false "Attribution model (last_click, first_click, linear, time_decay)" default(last_click)
+// @Param currency query string false "Currency for all amounts" default(MYR)
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Success 200 {array} entity.CampaignAttribution
+// @Router /api/v1/analytics/attribution [get]
+func (h *AnalyticsHandler) GetCampaignAttribution(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+	dateRange := parseDateRangeFromQuery(c)
+	model := entity.AttributionModel(c.DefaultQuery("model", string(entity.AttributionModelLastClick)))
+
+	results, err := h.analyticsService.GetCampaignAttribution(c.Request.Context(), orgID, dateRange, model, c.Query("currency"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    results,
+	})
+}
+
+// RunAttribution attributes the organization's orders in a date range
+// @Summary Attribute orders to campaigns
+// @Description Recomputes order attribution under every model for orders in the date range
+// @Tags Analytics
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} analytics.AttributionRunResult
+// @Router /api/v1/analytics/attribution/run [post]
+func (h *AnalyticsHandler) RunAttribution(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+	dateRange := parseDateRangeFromQuery(c)
+
+	result, err := h.analyticsService.AttributeOrders(c.Request.Context(), orgID, dateRange)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// TouchpointRequest is the API request for recording an ad click
+type TouchpointRequest struct {
+	CustomerID  string `json:"customer_id" binding:"required"`
+	CampaignID  string `json:"campaign_id,omitempty"`
+	AdID        string `json:"ad_id,omitempty"`
+	ClickID     string `json:"click_id,omitempty"`
+	UTMSource   string `json:"utm_source,omitempty"`
+	UTMMedium   string `json:"utm_medium,omitempty"`
+	UTMCampaign string `json:"utm_campaign,omitempty"`
+	UTMContent  string `json:"utm_content,omitempty"`
+	ClickedAt   string `json:"clicked_at,omitempty"` // RFC3339, defaults to now
+}
+
+// RecordTouchpoint records an ad click for attribution
+// @Summary Record an ad click
+// @Description Stores a click with its UTM parameters so later orders from the same customer can be attributed
+// @Tags Analytics
+// @Accept json
+// @Produce json
+// @Param request body TouchpointRequest true "Touchpoint"
+// @Success 201 {object} entity.AttributionTouchpoint
+// @Router /api/v1/analytics/attribution/touchpoints [post]
+func (h *AnalyticsHandler) RecordTouchpoint(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	var req TouchpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	input := analytics.TouchpointInput{
+		CustomerID:  req.CustomerID,
+		ClickID:     req.ClickID,
+		UTMSource:   req.UTMSource,
+		UTMMedium:   req.UTMMedium,
+		UTMCampaign: req.UTMCampaign,
+		UTMContent:  req.UTMContent,
+	}
+	if req.CampaignID != "" {
+		campaignID, err := uuid.Parse(req.CampaignID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+			return
+		}
+		input.CampaignID = &campaignID
+	}
+	if req.AdID != "" {
+		adID, err := uuid.Parse(req.AdID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ad ID"})
+			return
+		}
+		input.AdID = &adID
+	}
+	if req.ClickedAt != "" {
+		clickedAt, err := parseDate(req.ClickedAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Invalid clicked_at format",
+				"details": "Use RFC3339",
+			})
+			return
+		}
+		input.ClickedAt = clickedAt
+	}
+
+	touchpoint, err := h.analyticsService.RecordTouchpoint(c.Request.Context(), orgID, input)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    touchpoint,
+	})
+}
+
+// GetProfitability returns profit after ad spend by day, platform and campaign
+// @Summary Get profitability
+// @Description Net profit after ad spend, COGS and marketplace fees, with POAS (profit on ad spend)
+// @Tags Analytics
+// @Produce json
+// @Param model query string false "Attribution model used to credit order profit to campaigns" default(last_click)
+// @Param currency query string false "Currency for all amounts" default(MYR)
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Success 200 {object} entity.ProfitabilityReport
+// @Router /api/v1/analytics/profit [get]
+func (h *AnalyticsHandler) GetProfitability(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+	dateRange := parseDateRangeFromQuery(c)
+	model := entity.AttributionModel(c.DefaultQuery("model", string(entity.AttributionModelLastClick)))
+
+	report, err := h.analyticsService.GetProfitability(c.Request.Context(), orgID, dateRange, model, c.Query("currency"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// UploadSKUCosts imports a SKU cost table
+// @Summary Upload SKU costs
+// @Description Imports unit costs from a CSV with sku, unit_cost and optional currency columns, used where the marketplace does not supply cost
+// @Tags Analytics
+// @Accept multipart/form-data,text/csv
+// @Produce json
+// @Param file formData file false "SKU cost CSV"
+// @Param currency query string false "Currency of rows without one" default(MYR)
+// @Success 200 {object} analytics.SKUCostImportResult
+// @Router /api/v1/analytics/profit/sku-costs [post]
+func (h *AnalyticsHandler) UploadSKUCosts(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	// Cap uploads at 10MB
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, 10<<20)
+
+	body := io.Reader(c.Request.Body)
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, err := c.FormFile("file")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Missing file"})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid file"})
+			return
+		}
+		defer f.Close()
+		body = f
+	}
+
+	result, err := h.analyticsService.UploadSKUCosts(c.Request.Context(), orgID, body, c.Query("currency"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
+// ListSKUCosts lists the organization's uploaded SKU costs
+// @Summary List SKU costs
+// @Tags Analytics
+// @Produce json
+// @Success 200 {array} entity.SKUCost
+// @Router /api/v1/analytics/profit/sku-costs [get]
+func (h *AnalyticsHandler) ListSKUCosts(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	costs, err := h.analyticsService.ListSKUCosts(c.Request.Context(), orgID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    costs,
+	})
+}
+
+// GetPacing returns budget pacing for the organization's active campaigns
+// @Summary Get budget pacing
+// @Description Month-to-date and lifetime pacing of active campaigns, with projected spend from the recent daily run rate
+// @Tags Analytics
+// @Produce json
+// @Param currency query string false "Currency for all amounts" default(MYR)
+// @Param flagged query bool false "Only list campaigns likely to under- or overspend"
+// @Success 200 {object} entity.PacingReport
+// @Router /api/v1/analytics/pacing [get]
+func (h *AnalyticsHandler) GetPacing(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	report, err := h.analyticsService.GetPacing(c.Request.Context(), orgID, c.Query("currency"), c.Query("flagged") == "true")
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// GetCampaignPacing returns budget pacing for a campaign and its ad sets
+// @Summary Get campaign budget pacing
+// @Tags Analytics
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Param currency query string false "Currency for all amounts" default(MYR)
+// @Success 200 {object} entity.CampaignPacing
+// @Router /api/v1/campaigns/{id}/pacing [get]
+func (h *AnalyticsHandler) GetCampaignPacing(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	campaignID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+		return
+	}
+
+	pacing, err := h.analyticsService.GetCampaignPacing(c.Request.Context(), orgID, campaignID, c.Query("currency"))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    pacing,
+	})
+}
+
+// whiteLabelEnabled reports whether organization branding applies to the tenant's reports
+func (h *AnalyticsHandler) whiteLabelEnabled(c *gin.Context) bool {
+	if tenant, ok := middleware.GetTenantContext(c); ok {
+		return tenant.Limits.WhiteLabelEnabled
+	}
+	return false
 }
 
 // ExportAnalytics handles GET /api/v1/analytics/export
@@ -494,16 +1075,17 @@ func (h *AnalyticsHandler) ExportAnalytics(c *gin.Context) {
 
 	// Set appropriate headers based on format
 	filename := "analytics_export_" + dateRange.StartDate.Format("2006-01-02") + "_" + dateRange.EndDate.Format("2006-01-02")
+	var contentType string
 	switch format {
 	case "xlsx":
-		c.Header("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		contentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
 		c.Header("Content-Disposition", "attachment; filename="+filename+".xlsx")
 	default:
-		c.Header("Content-Type", "text/csv")
+		contentType = "text/csv"
 		c.Header("Content-Disposition", "attachment; filename="+filename+".csv")
 	}
 
-	c.Data(http.StatusOK, c.GetHeader("Content-Type"), data)
+	c.Data(http.StatusOK, contentType, data)
 }
 
 // Campaign handlers
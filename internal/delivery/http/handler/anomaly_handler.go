@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/delivery/http/middleware"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/usecase/analytics"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AnomalyHandler handles metric anomaly HTTP requests
+type AnomalyHandler struct {
+	anomalyService *analytics.AnomalyService
+}
+
+// NewAnomalyHandler creates a new anomaly handler
+func NewAnomalyHandler(anomalyService *analytics.AnomalyService) *AnomalyHandler {
+	return &AnomalyHandler{anomalyService: anomalyService}
+}
+
+// ListAnomalies lists anomalies detected in the organization's campaign metrics
+// @Summary List metric anomalies
+// @Description Daily spend, CTR, CPC, CPA and ROAS values that deviate from the campaign's rolling baseline, most recent first
+// @Tags Anomalies
+// @Produce json
+// @Param start_date query string false "Start date (YYYY-MM-DD)"
+// @Param end_date query string false "End date (YYYY-MM-DD)"
+// @Param campaign_id query string false "Campaign ID"
+// @Param metric query string false "Metric (spend, ctr, cpc, cpa, roas)"
+// @Param min_severity query string false "Minimum severity (low, medium, high, critical)"
+// @Param unacknowledged query bool false "Only anomalies not yet acknowledged"
+// @Param limit query int false "Maximum number of anomalies" default(50)
+// @Success 200 {array} entity.MetricAnomaly
+// @Router /api/v1/analytics/anomalies [get]
+func (h *AnomalyHandler) ListAnomalies(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	dateRange := parseDateRangeFromQuery(c)
+	limit, _ := parseInt(c.DefaultQuery("limit", "50"))
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+
+	filter := entity.AnomalyFilter{
+		OrganizationID: orgID,
+		DateRange:      &dateRange,
+		Unacknowledged: c.Query("unacknowledged") == "true",
+		Pagination:     &entity.Pagination{Page: 1, PageSize: limit},
+	}
+	if campaignID := c.Query("campaign_id"); campaignID != "" {
+		id, err := uuid.Parse(campaignID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid campaign ID"})
+			return
+		}
+		filter.CampaignID = &id
+	}
+	if metric := entity.AnomalyMetric(c.Query("metric")); metric != "" {
+		if !metric.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid metric"})
+			return
+		}
+		filter.Metrics = []entity.AnomalyMetric{metric}
+	}
+	if severity := entity.AnomalySeverity(c.Query("min_severity")); severity != "" {
+		if severity.Rank() == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid severity"})
+			return
+		}
+		filter.MinSeverity = severity
+	}
+
+	anomalies, total, err := h.anomalyService.ListAnomalies(c.Request.Context(), filter)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    anomalies,
+		"total":   total,
+	})
+}
+
+// DetectAnomalies checks yesterday's metrics of the organization's active campaigns now
+// @Summary Run anomaly detection
+// @Description Normally runs after every metrics sync. Anomalies already recorded are not raised again.
+// @Tags Anomalies
+// @Produce json
+// @Success 200 {array} entity.MetricAnomaly
+// @Router /api/v1/analytics/anomalies/detect [post]
+func (h *AnomalyHandler) DetectAnomalies(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	anomalies, err := h.anomalyService.DetectAnomalies(c.Request.Context(), orgID, nil, time.Now())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+	if anomalies == nil {
+		anomalies = []entity.MetricAnomaly{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    anomalies,
+	})
+}
+
+// AcknowledgeAnomaly marks an anomaly as seen
+// @Summary Acknowledge a metric anomaly
+// @Tags Anomalies
+// @Param anomalyId path string true "Anomaly ID"
+// @Success 204
+// @Router /api/v1/analytics/anomalies/{anomalyId}/acknowledge [post]
+func (h *AnomalyHandler) AcknowledgeAnomaly(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+	userID, _ := middleware.GetUserID(c)
+
+	anomalyID, err := uuid.Parse(c.Param("anomalyId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid anomaly ID"})
+		return
+	}
+
+	if err := h.anomalyService.AcknowledgeAnomaly(c.Request.Context(), orgID, anomalyID, userID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
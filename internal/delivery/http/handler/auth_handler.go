@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"context"
 	"net/http"
 	"os"
 	"time"
@@ -22,9 +23,10 @@ const (
 
 // AuthHandler handles authentication-related HTTP requests
 type AuthHandler struct {
-	authService  *auth.Service
-	cookieDomain string
-	secureCookie bool
+	authService   *auth.Service
+	apiKeyService *auth.APIKeyService
+	cookieDomain  string
+	secureCookie  bool
 }
 
 // NewAuthHandler creates a new auth handler
@@ -54,7 +56,7 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	result, err := h.authService.Register(c.Request.Context(), &req)
+	result, err := h.authService.Register(h.clientContext(c), &req)
 	if err != nil {
 		respondWithError(c, err)
 		return
@@ -81,12 +83,26 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	result, err := h.authService.Login(c.Request.Context(), &req)
+	result, err := h.authService.Login(h.clientContext(c), &req)
 	if err != nil {
 		respondWithError(c, err)
 		return
 	}
 
+	// A second factor is needed before any cookie is set
+	if result.MFA != nil {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data": gin.H{
+				"mfaRequired":  true,
+				"mfaToken":     result.MFA.Token,
+				"mfaMethod":    result.MFA.Method,
+				"mfaExpiresAt": result.MFA.ExpiresAt,
+			},
+		})
+		return
+	}
+
 	// Set auth cookies
 	h.setAuthCookies(c, result.Tokens)
 
@@ -117,7 +133,7 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 		refreshToken = req.RefreshToken
 	}
 
-	tokens, err := h.authService.RefreshToken(c.Request.Context(), refreshToken)
+	tokens, err := h.authService.RefreshToken(h.clientContext(c), refreshToken)
 	if err != nil {
 		// Clear cookies on refresh failure
 		h.clearAuthCookies(c)
@@ -136,9 +152,23 @@ func (h *AuthHandler) RefreshToken(c *gin.Context) {
 	})
 }
 
-// Logout handles user logout
+// Logout handles user logout, ending the session of the refresh token
 func (h *AuthHandler) Logout(c *gin.Context) {
+	refreshToken, err := c.Cookie(RefreshTokenCookie)
+	if err != nil || refreshToken == "" {
+		var req struct {
+			RefreshToken string `json:"refresh_token"`
+		}
+		_ = c.ShouldBindJSON(&req)
+		refreshToken = req.RefreshToken
+	}
+
 	h.clearAuthCookies(c)
+	if err := h.authService.Logout(c.Request.Context(), refreshToken); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
@@ -283,7 +313,7 @@ func (h *AuthHandler) ChangePassword(c *gin.Context) {
 		return
 	}
 
-	err := h.authService.ChangePassword(c.Request.Context(), userID, req.CurrentPassword, req.NewPassword)
+	err := h.authService.ChangePassword(h.clientContext(c), userID, req.CurrentPassword, req.NewPassword)
 	if err != nil {
 		respondWithError(c, err)
 		return
@@ -397,6 +427,607 @@ func (h *AuthHandler) RemoveMember(c *gin.Context) {
 	})
 }
 
+// ============================================================================
+// Multi-Factor Authentication
+// ============================================================================
+
+// MFAChallengeRequest completes or continues a login that needs a second factor
+type MFAChallengeRequest struct {
+	MFAToken string `json:"mfaToken" binding:"required"`
+	Code     string `json:"code"`
+}
+
+// mfaAvailable responds with an error when multi-factor authentication is not configured
+func (h *AuthHandler) mfaAvailable(c *gin.Context) bool {
+	if !h.authService.MFAConfigured() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Multi-factor authentication is not configured"})
+		return false
+	}
+	return true
+}
+
+// respondWithLogin sets the auth cookies of a completed login and returns its user data
+func (h *AuthHandler) respondWithLogin(c *gin.Context, result *auth.AuthResponse) {
+	h.setAuthCookies(c, result.Tokens)
+
+	data := gin.H{
+		"user":         h.sanitizeUser(result.User),
+		"organization": result.Organization,
+		"expiresAt":    result.Tokens.AccessTokenExpiresAt,
+	}
+	if len(result.RecoveryCodes) > 0 {
+		data["recoveryCodes"] = result.RecoveryCodes
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    data,
+	})
+}
+
+// VerifyMFA completes a login with a TOTP code or a recovery code
+// @Summary Complete a login with a second factor
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body MFAChallengeRequest true "Challenge token from the login and a TOTP or recovery code"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/auth/mfa/verify [post]
+func (h *AuthHandler) VerifyMFA(c *gin.Context) {
+	if !h.mfaAvailable(c) {
+		return
+	}
+
+	var req MFAChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, errors.ErrValidation(err.Error()))
+		return
+	}
+
+	result, err := h.authService.VerifyMFALogin(h.clientContext(c), req.MFAToken, req.Code)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.respondWithLogin(c, result)
+}
+
+// BeginLoginMFAEnrollment starts the enrolment a login requires when the
+// organization enforces MFA for the user's role
+// @Summary Set up a second factor during login
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body MFAChallengeRequest true "Challenge token from the login"
+// @Success 200 {object} auth.MFAEnrollment
+// @Router /api/v1/auth/mfa/enroll [post]
+func (h *AuthHandler) BeginLoginMFAEnrollment(c *gin.Context) {
+	if !h.mfaAvailable(c) {
+		return
+	}
+
+	var req MFAChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, errors.ErrValidation(err.Error()))
+		return
+	}
+
+	enrollment, err := h.authService.BeginMFAEnrollmentForLogin(c.Request.Context(), req.MFAToken)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    enrollment,
+	})
+}
+
+// ConfirmLoginMFAEnrollment enables the second factor set up during a login and completes it
+// @Summary Confirm a second factor set up during login
+// @Description Returns the recovery codes, which are shown only once
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body MFAChallengeRequest true "Challenge token from the login and a TOTP code"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/auth/mfa/enroll/confirm [post]
+func (h *AuthHandler) ConfirmLoginMFAEnrollment(c *gin.Context) {
+	if !h.mfaAvailable(c) {
+		return
+	}
+
+	var req MFAChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, errors.ErrValidation(err.Error()))
+		return
+	}
+
+	result, err := h.authService.ConfirmMFAEnrollmentForLogin(h.clientContext(c), req.MFAToken, req.Code)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	h.respondWithLogin(c, result)
+}
+
+// GetMFAStatus returns the current user's second factor status
+// @Summary Get multi-factor authentication status
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} auth.MFAStatus
+// @Router /api/v1/user/mfa [get]
+func (h *AuthHandler) GetMFAStatus(c *gin.Context) {
+	if !h.mfaAvailable(c) {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	orgID, _ := middleware.GetOrgID(c)
+	role, _ := middleware.GetRole(c)
+
+	status, err := h.authService.GetMFAStatus(c.Request.Context(), userID, orgID, entity.UserRole(role))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    status,
+	})
+}
+
+// EnrollMFA starts TOTP enrolment for the current user
+// @Summary Start multi-factor authentication enrolment
+// @Description Returns a secret and an otpauth:// provisioning URI for authenticator apps. Enable the second factor with a code generated from it.
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} auth.MFAEnrollment
+// @Router /api/v1/user/mfa/enroll [post]
+func (h *AuthHandler) EnrollMFA(c *gin.Context) {
+	if !h.mfaAvailable(c) {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	enrollment, err := h.authService.BeginMFAEnrollment(c.Request.Context(), userID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    enrollment,
+	})
+}
+
+// EnableMFA enables the current user's enrolled second factor
+// @Summary Enable multi-factor authentication
+// @Description Returns the recovery codes, which are shown only once
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/user/mfa/enable [post]
+func (h *AuthHandler) EnableMFA(c *gin.Context) {
+	if !h.mfaAvailable(c) {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, errors.ErrValidation(err.Error()))
+		return
+	}
+
+	codes, err := h.authService.EnableMFA(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"recoveryCodes": codes,
+		},
+	})
+}
+
+// DisableMFA removes the current user's second factor
+// @Summary Disable multi-factor authentication
+// @Description Needs the password and a TOTP or recovery code. Not allowed when the organization requires MFA for the user's role.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/user/mfa/disable [post]
+func (h *AuthHandler) DisableMFA(c *gin.Context) {
+	if !h.mfaAvailable(c) {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+	orgID, _ := middleware.GetOrgID(c)
+	role, _ := middleware.GetRole(c)
+
+	var req struct {
+		Password string `json:"password" binding:"required"`
+		Code     string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, errors.ErrValidation(err.Error()))
+		return
+	}
+
+	if err := h.authService.DisableMFA(c.Request.Context(), userID, orgID, entity.UserRole(role), req.Password, req.Code); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"message": "Multi-factor authentication disabled",
+		},
+	})
+}
+
+// RegenerateRecoveryCodes replaces the current user's recovery codes
+// @Summary Regenerate MFA recovery codes
+// @Description Needs a TOTP code. Earlier recovery codes stop working; the new ones are shown only once.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/user/mfa/recovery-codes [post]
+func (h *AuthHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	if !h.mfaAvailable(c) {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	var req struct {
+		Code string `json:"code" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, errors.ErrValidation(err.Error()))
+		return
+	}
+
+	codes, err := h.authService.RegenerateRecoveryCodes(c.Request.Context(), userID, req.Code)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"recoveryCodes": codes,
+		},
+	})
+}
+
+// GetSecuritySettings returns the organization's authentication settings
+// @Summary Get organization security settings
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} auth.SecuritySettings
+// @Router /api/v1/settings/security [get]
+func (h *AuthHandler) GetSecuritySettings(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	settings, err := h.authService.GetSecuritySettings(c.Request.Context(), orgID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    settings,
+	})
+}
+
+// UpdateSecuritySettings updates the organization's authentication settings
+// @Summary Update organization security settings
+// @Description With require_mfa, owners and admins without a second factor must set one up at their next login
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body auth.SecuritySettings true "Security settings"
+// @Success 200 {object} auth.SecuritySettings
+// @Router /api/v1/settings/security [put]
+func (h *AuthHandler) UpdateSecuritySettings(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	var req auth.SecuritySettings
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, errors.ErrValidation(err.Error()))
+		return
+	}
+	if req.RequireMFA && !h.authService.MFAConfigured() {
+		respondWithError(c, errors.ErrBadRequest("Multi-factor authentication is not configured"))
+		return
+	}
+
+	settings, err := h.authService.UpdateSecuritySettings(c.Request.Context(), orgID, req)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    settings,
+	})
+}
+
+// ============================================================================
+// Sessions
+// ============================================================================
+
+// sessionsAvailable responds with an error when server-side sessions are not configured
+func (h *AuthHandler) sessionsAvailable(c *gin.Context) bool {
+	if !h.authService.SessionsConfigured() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Sessions are not configured"})
+		return false
+	}
+	return true
+}
+
+// ListSessions lists the current user's active sessions
+// @Summary List active sessions
+// @Description One session per logged-in device; the session of the request is marked current
+// @Tags Auth
+// @Produce json
+// @Success 200 {array} auth.Session
+// @Router /api/v1/user/sessions [get]
+func (h *AuthHandler) ListSessions(c *gin.Context) {
+	if !h.sessionsAvailable(c) {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	sessions, err := h.authService.ListSessions(c.Request.Context(), userID, currentSessionID(c))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    sessions,
+	})
+}
+
+// RevokeSession logs the current user out of one of their sessions
+// @Summary Revoke a session
+// @Description The session's refresh token stops working right away; its access token expires on its own
+// @Tags Auth
+// @Param sessionId path string true "Session ID"
+// @Success 204
+// @Router /api/v1/user/sessions/{sessionId} [delete]
+func (h *AuthHandler) RevokeSession(c *gin.Context) {
+	if !h.sessionsAvailable(c) {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	sessionID, err := parseUUID(c, "sessionId")
+	if err != nil {
+		respondWithError(c, errors.ErrBadRequest("Invalid session ID"))
+		return
+	}
+
+	if err := h.authService.RevokeSession(c.Request.Context(), userID, sessionID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	if sessionID == currentSessionID(c) {
+		h.clearAuthCookies(c)
+	}
+	c.Status(http.StatusNoContent)
+}
+
+// RevokeOtherSessions logs the current user out everywhere else
+// @Summary Revoke all other sessions
+// @Tags Auth
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/user/sessions [delete]
+func (h *AuthHandler) RevokeOtherSessions(c *gin.Context) {
+	if !h.sessionsAvailable(c) {
+		return
+	}
+	userID, _ := middleware.GetUserID(c)
+
+	revoked, err := h.authService.RevokeOtherSessions(c.Request.Context(), userID, currentSessionID(c))
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"revoked": revoked,
+		},
+	})
+}
+
+// ============================================================================
+// API Keys
+// ============================================================================
+
+// SetAPIKeyService sets the service behind the API key endpoints
+func (h *AuthHandler) SetAPIKeyService(apiKeyService *auth.APIKeyService) {
+	h.apiKeyService = apiKeyService
+}
+
+// CreateAPIKeyRequest is the API request for creating an API key
+type CreateAPIKeyRequest struct {
+	Name      string     `json:"name" binding:"required"`
+	Scopes    []string   `json:"scopes" binding:"required"` // e.g. ["analytics:read", "campaigns:read"]
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+// apiKeysAvailable responds with an error when API keys are not configured or
+// not included in the tenant's plan
+func (h *AuthHandler) apiKeysAvailable(c *gin.Context) bool {
+	if h.apiKeyService == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "API keys are not configured"})
+		return false
+	}
+	if tenant, ok := middleware.GetTenantContext(c); ok && !tenant.HasFeature("api_access") {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": gin.H{
+				"code":    "FEATURE_NOT_AVAILABLE",
+				"message": "This feature is not available on your current plan",
+				"feature": "api_access",
+				"plan":    tenant.SubscriptionTier,
+			},
+		})
+		return false
+	}
+	return true
+}
+
+// ListAPIKeys lists the organization's API keys, revoked ones included
+// @Summary List API keys
+// @Tags Auth
+// @Produce json
+// @Success 200 {array} entity.APIKey
+// @Router /api/v1/api-keys [get]
+func (h *AuthHandler) ListAPIKeys(c *gin.Context) {
+	if !h.apiKeysAvailable(c) {
+		return
+	}
+	orgID, _ := middleware.GetOrgID(c)
+
+	keys, err := h.apiKeyService.ListKeys(c.Request.Context(), orgID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    keys,
+	})
+}
+
+// CreateAPIKey creates an API key
+// @Summary Create an API key
+// @Description The key is returned only once. Send it in the X-API-Key header or as an Authorization bearer token. Scopes are permissions such as analytics:read or campaigns:write ("analytics:*" grants both) and cannot exceed your own.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body CreateAPIKeyRequest true "API key"
+// @Success 201 {object} map[string]interface{}
+// @Router /api/v1/api-keys [post]
+func (h *AuthHandler) CreateAPIKey(c *gin.Context) {
+	if !h.apiKeysAvailable(c) {
+		return
+	}
+	orgID, _ := middleware.GetOrgID(c)
+	userID, _ := middleware.GetUserID(c)
+	permissions, _ := middleware.GetPermissions(c)
+
+	var req CreateAPIKeyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondWithError(c, errors.ErrValidation(err.Error()))
+		return
+	}
+
+	key, rawKey, err := h.apiKeyService.CreateKey(c.Request.Context(), orgID, userID, permissions, auth.APIKeyInput{
+		Name:      req.Name,
+		Scopes:    req.Scopes,
+		ExpiresAt: req.ExpiresAt,
+	})
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data": gin.H{
+			"api_key": key,
+			"key":     rawKey,
+		},
+	})
+}
+
+// RotateAPIKey replaces an API key with a new one
+// @Summary Rotate an API key
+// @Description The new key is returned only once; the previous key stops working right away
+// @Tags Auth
+// @Produce json
+// @Param keyId path string true "API key ID"
+// @Success 200 {object} map[string]interface{}
+// @Router /api/v1/api-keys/{keyId}/rotate [post]
+func (h *AuthHandler) RotateAPIKey(c *gin.Context) {
+	if !h.apiKeysAvailable(c) {
+		return
+	}
+	orgID, _ := middleware.GetOrgID(c)
+
+	keyID, err := parseUUID(c, "keyId")
+	if err != nil {
+		respondWithError(c, errors.ErrBadRequest("Invalid API key ID"))
+		return
+	}
+
+	key, rawKey, err := h.apiKeyService.RotateKey(c.Request.Context(), orgID, keyID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data": gin.H{
+			"api_key": key,
+			"key":     rawKey,
+		},
+	})
+}
+
+// RevokeAPIKey revokes an API key
+// @Summary Revoke an API key
+// @Tags Auth
+// @Param keyId path string true "API key ID"
+// @Success 204
+// @Router /api/v1/api-keys/{keyId} [delete]
+func (h *AuthHandler) RevokeAPIKey(c *gin.Context) {
+	if !h.apiKeysAvailable(c) {
+		return
+	}
+	orgID, _ := middleware.GetOrgID(c)
+	userID, _ := middleware.GetUserID(c)
+
+	keyID, err := parseUUID(c, "keyId")
+	if err != nil {
+		respondWithError(c, errors.ErrBadRequest("Invalid API key ID"))
+		return
+	}
+
+	if err := h.apiKeyService.RevokeKey(c.Request.Context(), orgID, keyID, userID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // ============================================================================
 // OAuth Callbacks
 // ============================================================================
@@ -461,6 +1092,50 @@ func (h *AuthHandler) ShopeeCallback(c *gin.Context) {
 	c.Redirect(http.StatusTemporaryRedirect, redirectURL+"?platform=shopee&account_id="+account.ID.String()+"&shop_id="+shopID+"&success=true")
 }
 
+// GoogleCallback handles Google Ads OAuth callback
+func (h *AuthHandler) GoogleCallback(c *gin.Context) {
+	code := c.Query("code")
+	state := c.Query("state")
+	errorCode := c.Query("error")
+
+	if errorCode != "" {
+		c.Redirect(http.StatusTemporaryRedirect, "/connect?error="+errorCode+"&platform=google")
+		return
+	}
+
+	if code == "" {
+		c.Redirect(http.StatusTemporaryRedirect, "/connect?error=no_code&platform=google")
+		return
+	}
+
+	account, redirectURL, err := h.authService.HandleOAuthCallback(c.Request.Context(), entity.PlatformGoogle, code, state)
+	if err != nil {
+		c.Redirect(http.StatusTemporaryRedirect, "/connect?error=callback_failed&platform=google")
+		return
+	}
+
+	c.Redirect(http.StatusTemporaryRedirect, redirectURL+"?platform=google&account_id="+account.ID.String()+"&success=true")
+}
+
+// LazadaCallback handles Lazada OAuth callback
+func (h *AuthHandler) LazadaCallback(c *gin.Context) {
+	code := c.Query("code")
+	state := c.Query("state")
+
+	if code == "" {
+		c.Redirect(http.StatusTemporaryRedirect, "/connect?error=no_code&platform=lazada")
+		return
+	}
+
+	account, redirectURL, err := h.authService.HandleOAuthCallback(c.Request.Context(), entity.PlatformLazada, code, state)
+	if err != nil {
+		c.Redirect(http.StatusTemporaryRedirect, "/connect?error=callback_failed&platform=lazada")
+		return
+	}
+
+	c.Redirect(http.StatusTemporaryRedirect, redirectURL+"?platform=lazada&account_id="+account.ID.String()+"&success=true")
+}
+
 // ============================================================================
 // Cookie Management
 // ============================================================================
@@ -570,6 +1245,28 @@ func respondWithError(c *gin.Context, err error) {
 	})
 }
 
+// clientContext returns the request context carrying the client info recorded on sessions
+func (h *AuthHandler) clientContext(c *gin.Context) context.Context {
+	return auth.WithClientInfo(c.Request.Context(), auth.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+		SessionID: currentSessionID(c),
+	})
+}
+
+// currentSessionID returns the session of the request's access token, or uuid.Nil
+func currentSessionID(c *gin.Context) uuid.UUID {
+	claims, ok := middleware.GetClaims(c)
+	if !ok {
+		return uuid.Nil
+	}
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return uuid.Nil
+	}
+	return sessionID
+}
+
 func parseUUID(c *gin.Context, param string) (uuid.UUID, error) {
 	id := c.Param(param)
 	return uuid.Parse(id)
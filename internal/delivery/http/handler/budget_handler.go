@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/delivery/http/middleware"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/usecase/analytics"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// BudgetHandler handles organization budget HTTP requests
+type BudgetHandler struct {
+	budgetService *analytics.BudgetService
+}
+
+// NewBudgetHandler creates a new budget handler
+func NewBudgetHandler(budgetService *analytics.BudgetService) *BudgetHandler {
+	return &BudgetHandler{budgetService: budgetService}
+}
+
+// BudgetRequest is the API request for setting the organization budget
+type BudgetRequest struct {
+	MonthlyAmount       decimal.Decimal            `json:"monthly_amount" binding:"required"`
+	Currency            string                     `json:"currency,omitempty"`
+	PlatformAllocations map[string]decimal.Decimal `json:"platform_allocations,omitempty"`
+	IsActive            *bool                      `json:"is_active,omitempty"`
+}
+
+// toInput converts the request to the budget service input
+func (r *BudgetRequest) toInput() analytics.BudgetInput {
+	input := analytics.BudgetInput{
+		MonthlyAmount: r.MonthlyAmount,
+		Currency:      r.Currency,
+		IsActive:      r.IsActive,
+	}
+	if len(r.PlatformAllocations) > 0 {
+		input.PlatformAllocations = make(map[entity.Platform]decimal.Decimal, len(r.PlatformAllocations))
+		for platform, amount := range r.PlatformAllocations {
+			input.PlatformAllocations[entity.Platform(strings.ToLower(platform))] = amount
+		}
+	}
+	return input
+}
+
+// GetBudget returns the organization's monthly budget
+// @Summary Get the organization budget
+// @Tags Budgets
+// @Produce json
+// @Success 200 {object} entity.OrganizationBudget
+// @Router /api/v1/budget [get]
+func (h *BudgetHandler) GetBudget(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	budget, err := h.budgetService.GetBudget(c.Request.Context(), orgID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    budget,
+	})
+}
+
+// SetBudget creates or replaces the organization's monthly budget
+// @Summary Set the organization budget
+// @Description One monthly budget across all platforms, optionally split into per-platform allocations in the budget currency
+// @Tags Budgets
+// @Accept json
+// @Produce json
+// @Param request body BudgetRequest true "Budget"
+// @Success 200 {object} entity.OrganizationBudget
+// @Router /api/v1/budget [put]
+func (h *BudgetHandler) SetBudget(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	var req BudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	budget, err := h.budgetService.SetBudget(c.Request.Context(), orgID, req.toInput())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    budget,
+	})
+}
+
+// DeleteBudget deletes the organization's monthly budget
+// @Summary Delete the organization budget
+// @Tags Budgets
+// @Success 204
+// @Router /api/v1/budget [delete]
+func (h *BudgetHandler) DeleteBudget(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	if err := h.budgetService.DeleteBudget(c.Request.Context(), orgID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GetBudgetStatus returns month-to-date spend against the organization budget
+// @Summary Get budget usage
+// @Description Month-to-date spend against the budget and each platform allocation, with the alerts raised this month
+// @Tags Budgets
+// @Produce json
+// @Success 200 {object} entity.BudgetStatus
+// @Router /api/v1/budget/status [get]
+func (h *BudgetHandler) GetBudgetStatus(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+
+	status, err := h.budgetService.GetStatus(c.Request.Context(), orgID, time.Now())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    status,
+	})
+}
+
+// AcknowledgeBudgetAlert marks a budget alert as seen
+// @Summary Acknowledge a budget alert
+// @Tags Budgets
+// @Param alertId path string true "Alert ID"
+// @Success 204
+// @Router /api/v1/budget/alerts/{alertId}/acknowledge [post]
+func (h *BudgetHandler) AcknowledgeBudgetAlert(c *gin.Context) {
+	orgID, _ := middleware.GetOrgID(c)
+	userID, _ := middleware.GetUserID(c)
+
+	alertID, err := uuid.Parse(c.Param("alertId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid alert ID"})
+		return
+	}
+
+	if err := h.budgetService.AcknowledgeAlert(c.Request.Context(), orgID, alertID, userID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -1,7 +1,9 @@
 package handler
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"time"
@@ -300,19 +302,7 @@ func (h *SyncHandler) HandleGetSyncJobStatus(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	respondJSON(w, http.StatusOK, SyncJobStatusResponse{
-		ID:               job.ID.String(),
-		Status:           string(job.Status),
-		SyncType:         string(job.SyncType),
-		Platform:         string(job.Platform),
-		ProgressPercent:  job.ProgressPercent,
-		ProgressMessage:  job.ProgressMessage,
-		StartedAt:        job.StartedAt,
-		CompletedAt:      job.CompletedAt,
-		RecordsProcessed: job.RecordsProcessed,
-		RecordsFailed:    job.RecordsFailed,
-		ErrorMessage:     job.ErrorMessage,
-	})
+	respondJSON(w, http.StatusOK, newSyncJobStatusResponse(job))
 }
 
 // HandleListRecentJobs handles GET /api/v1/sync/jobs
@@ -333,20 +323,8 @@ func (h *SyncHandler) HandleListRecentJobs(w http.ResponseWriter, r *http.Reques
 	}
 
 	var response []SyncJobStatusResponse
-	for _, job := range jobs {
-		response = append(response, SyncJobStatusResponse{
-			ID:               job.ID.String(),
-			Status:           string(job.Status),
-			SyncType:         string(job.SyncType),
-			Platform:         string(job.Platform),
-			ProgressPercent:  job.ProgressPercent,
-			ProgressMessage:  job.ProgressMessage,
-			StartedAt:        job.StartedAt,
-			CompletedAt:      job.CompletedAt,
-			RecordsProcessed: job.RecordsProcessed,
-			RecordsFailed:    job.RecordsFailed,
-			ErrorMessage:     job.ErrorMessage,
-		})
+	for i := range jobs {
+		response = append(response, newSyncJobStatusResponse(&jobs[i]))
 	}
 
 	respondJSON(w, http.StatusOK, map[string]interface{}{
@@ -355,6 +333,61 @@ func (h *SyncHandler) HandleListRecentJobs(w http.ResponseWriter, r *http.Reques
 	})
 }
 
+// ============================================================================
+// Sync Job Control
+// ============================================================================
+
+// HandleCancelSyncJob handles POST /api/v1/sync/jobs/{job_id}/cancel
+func (h *SyncHandler) HandleCancelSyncJob(w http.ResponseWriter, r *http.Request) {
+	h.controlSyncJob(w, r, h.scheduler.CancelJob)
+}
+
+// HandlePauseSyncJob handles POST /api/v1/sync/jobs/{job_id}/pause
+func (h *SyncHandler) HandlePauseSyncJob(w http.ResponseWriter, r *http.Request) {
+	h.controlSyncJob(w, r, h.scheduler.PauseJob)
+}
+
+// HandleResumeSyncJob handles POST /api/v1/sync/jobs/{job_id}/resume
+func (h *SyncHandler) HandleResumeSyncJob(w http.ResponseWriter, r *http.Request) {
+	h.controlSyncJob(w, r, h.scheduler.ResumeJob)
+}
+
+// controlSyncJob applies a cancel, pause or resume to a job of the caller's organization
+func (h *SyncHandler) controlSyncJob(w http.ResponseWriter, r *http.Request, control func(ctx context.Context, jobID uuid.UUID) (*entity.SyncJob, error)) {
+	ctx := r.Context()
+
+	orgID, ok := ctx.Value("organization_id").(uuid.UUID)
+	if !ok {
+		respondError(w, http.StatusBadRequest, "organization_id required")
+		return
+	}
+
+	jobID, err := uuid.Parse(r.PathValue("job_id"))
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid job_id")
+		return
+	}
+
+	job, err := h.syncJobRepo.GetByID(ctx, jobID)
+	if err != nil || job.OrganizationID != orgID {
+		respondError(w, http.StatusNotFound, "job not found")
+		return
+	}
+
+	job, err = control(ctx, jobID)
+	if err != nil {
+		if errors.Is(err, syncpkg.ErrInvalidJobTransition) {
+			respondError(w, http.StatusConflict, err.Error())
+			return
+		}
+		log.Printf("[SyncHandler] Error updating sync job %s: %v", jobID, err)
+		respondError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, newSyncJobStatusResponse(job))
+}
+
 // ============================================================================
 // Sync State Endpoints
 // ============================================================================
@@ -391,6 +424,22 @@ func (h *SyncHandler) HandleGetSyncState(w http.ResponseWriter, r *http.Request)
 // Helper Functions
 // ============================================================================
 
+func newSyncJobStatusResponse(job *entity.SyncJob) SyncJobStatusResponse {
+	return SyncJobStatusResponse{
+		ID:               job.ID.String(),
+		Status:           string(job.Status),
+		SyncType:         string(job.SyncType),
+		Platform:         string(job.Platform),
+		ProgressPercent:  job.ProgressPercent,
+		ProgressMessage:  job.ProgressMessage,
+		StartedAt:        job.StartedAt,
+		CompletedAt:      job.CompletedAt,
+		RecordsProcessed: job.RecordsProcessed,
+		RecordsFailed:    job.RecordsFailed,
+		ErrorMessage:     job.ErrorMessage,
+	}
+}
+
 func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
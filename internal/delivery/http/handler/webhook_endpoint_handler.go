@@ -0,0 +1,396 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/delivery/http/middleware"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/usecase/webhook"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// WebhookEndpointHandler handles HTTP requests for managing outbound webhook
+// endpoints and their deliveries. Inbound platform webhooks are served by
+// WebhookHandler.
+type WebhookEndpointHandler struct {
+	webhookService *webhook.Service
+}
+
+// NewWebhookEndpointHandler creates a new outbound webhook handler
+func NewWebhookEndpointHandler(webhookService *webhook.Service) *WebhookEndpointHandler {
+	return &WebhookEndpointHandler{webhookService: webhookService}
+}
+
+// WebhookEndpointRequest is the API request for creating or updating a webhook endpoint
+type WebhookEndpointRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Description string   `json:"description,omitempty"`
+	Events      []string `json:"events" binding:"required"` // e.g. ["sync.completed", "report.ready"]
+	IsActive    *bool    `json:"is_active,omitempty"`
+}
+
+// toInput converts the request to the webhook service input
+func (r *WebhookEndpointRequest) toInput() webhook.EndpointInput {
+	input := webhook.EndpointInput{
+		URL:         r.URL,
+		Description: r.Description,
+		IsActive:    r.IsActive,
+	}
+	for _, event := range r.Events {
+		input.Events = append(input.Events, entity.WebhookEventType(strings.ToLower(event)))
+	}
+	return input
+}
+
+// webhooksAvailable responds with an error when webhooks are not included in the tenant's plan
+func (h *WebhookEndpointHandler) webhooksAvailable(c *gin.Context) bool {
+	if tenant, ok := middleware.GetTenantContext(c); ok && !tenant.HasFeature("webhooks") {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error": gin.H{
+				"code":    "FEATURE_NOT_AVAILABLE",
+				"message": "This feature is not available on your current plan",
+				"feature": "webhooks",
+				"plan":    tenant.SubscriptionTier,
+			},
+		})
+		return false
+	}
+	return true
+}
+
+// ListWebhookEventTypes lists the events webhook endpoints can subscribe to
+// @Summary List webhook event types
+// @Tags Webhooks
+// @Produce json
+// @Success 200 {array} string
+// @Router /api/v1/webhooks/events [get]
+func (h *WebhookEndpointHandler) ListWebhookEventTypes(c *gin.Context) {
+	if !h.webhooksAvailable(c) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    entity.WebhookEventTypes(),
+	})
+}
+
+// ListWebhookEndpoints lists the organization's webhook endpoints
+// @Summary List webhook endpoints
+// @Tags Webhooks
+// @Produce json
+// @Success 200 {array} entity.WebhookEndpoint
+// @Router /api/v1/webhooks/endpoints [get]
+func (h *WebhookEndpointHandler) ListWebhookEndpoints(c *gin.Context) {
+	if !h.webhooksAvailable(c) {
+		return
+	}
+	orgID, _ := middleware.GetOrgID(c)
+
+	endpoints, err := h.webhookService.ListEndpoints(c.Request.Context(), orgID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    endpoints,
+	})
+}
+
+// GetWebhookEndpoint returns a webhook endpoint
+// @Summary Get a webhook endpoint
+// @Tags Webhooks
+// @Produce json
+// @Param endpointId path string true "Endpoint ID"
+// @Success 200 {object} entity.WebhookEndpoint
+// @Router /api/v1/webhooks/endpoints/{endpointId} [get]
+func (h *WebhookEndpointHandler) GetWebhookEndpoint(c *gin.Context) {
+	if !h.webhooksAvailable(c) {
+		return
+	}
+	orgID, _ := middleware.GetOrgID(c)
+
+	endpointID, err := uuid.Parse(c.Param("endpointId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid endpoint ID"})
+		return
+	}
+
+	endpoint, err := h.webhookService.GetEndpoint(c.Request.Context(), orgID, endpointID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    endpoint,
+	})
+}
+
+// CreateWebhookEndpoint creates a webhook endpoint
+// @Summary Create a webhook endpoint
+// @Description Events are POSTed as JSON with X-AdsAnalytic-Event, X-AdsAnalytic-Delivery, X-AdsAnalytic-Timestamp and X-AdsAnalytic-Signature headers. The signature is "sha256=" followed by the hex HMAC-SHA256 of "{timestamp}.{body}" keyed with the endpoint's secret. Responses other than 2xx are retried with backoff for about a day. The secret is only returned in this response. Events: sync.completed, sync.failed, token.expired, alert.triggered, report.ready.
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param request body WebhookEndpointRequest true "Webhook endpoint"
+// @Success 201 {object} webhook.EndpointWithSecret
+// @Router /api/v1/webhooks/endpoints [post]
+func (h *WebhookEndpointHandler) CreateWebhookEndpoint(c *gin.Context) {
+	if !h.webhooksAvailable(c) {
+		return
+	}
+	orgID, _ := middleware.GetOrgID(c)
+	userID, _ := middleware.GetUserID(c)
+
+	var req WebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	endpoint, err := h.webhookService.CreateEndpoint(c.Request.Context(), orgID, userID, req.toInput())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    endpoint,
+	})
+}
+
+// UpdateWebhookEndpoint updates a webhook endpoint
+// @Summary Update a webhook endpoint
+// @Description Replaces the endpoint's URL, description and events; the secret is kept
+// @Tags Webhooks
+// @Accept json
+// @Produce json
+// @Param endpointId path string true "Endpoint ID"
+// @Param request body WebhookEndpointRequest true "Webhook endpoint"
+// @Success 200 {object} entity.WebhookEndpoint
+// @Router /api/v1/webhooks/endpoints/{endpointId} [put]
+func (h *WebhookEndpointHandler) UpdateWebhookEndpoint(c *gin.Context) {
+	if !h.webhooksAvailable(c) {
+		return
+	}
+	orgID, _ := middleware.GetOrgID(c)
+
+	endpointID, err := uuid.Parse(c.Param("endpointId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid endpoint ID"})
+		return
+	}
+
+	var req WebhookEndpointRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Invalid request body",
+			"details": err.Error(),
+		})
+		return
+	}
+
+	endpoint, err := h.webhookService.UpdateEndpoint(c.Request.Context(), orgID, endpointID, req.toInput())
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    endpoint,
+	})
+}
+
+// DeleteWebhookEndpoint deletes a webhook endpoint
+// @Summary Delete a webhook endpoint
+// @Description Also deletes the endpoint's delivery log
+// @Tags Webhooks
+// @Param endpointId path string true "Endpoint ID"
+// @Success 204
+// @Router /api/v1/webhooks/endpoints/{endpointId} [delete]
+func (h *WebhookEndpointHandler) DeleteWebhookEndpoint(c *gin.Context) {
+	if !h.webhooksAvailable(c) {
+		return
+	}
+	orgID, _ := middleware.GetOrgID(c)
+
+	endpointID, err := uuid.Parse(c.Param("endpointId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid endpoint ID"})
+		return
+	}
+
+	if err := h.webhookService.DeleteEndpoint(c.Request.Context(), orgID, endpointID); err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// RotateWebhookSecret replaces the signing secret of a webhook endpoint
+// @Summary Rotate a webhook secret
+// @Description The new secret is only returned in this response
+// @Tags Webhooks
+// @Produce json
+// @Param endpointId path string true "Endpoint ID"
+// @Success 200 {object} webhook.EndpointWithSecret
+// @Router /api/v1/webhooks/endpoints/{endpointId}/rotate-secret [post]
+func (h *WebhookEndpointHandler) RotateWebhookSecret(c *gin.Context) {
+	if !h.webhooksAvailable(c) {
+		return
+	}
+	orgID, _ := middleware.GetOrgID(c)
+
+	endpointID, err := uuid.Parse(c.Param("endpointId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid endpoint ID"})
+		return
+	}
+
+	endpoint, err := h.webhookService.RotateSecret(c.Request.Context(), orgID, endpointID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    endpoint,
+	})
+}
+
+// ListWebhookDeliveries lists the organization's webhook deliveries
+// @Summary List webhook deliveries
+// @Tags Webhooks
+// @Produce json
+// @Param endpoint_id query string false "Endpoint ID"
+// @Param event query string false "Event type"
+// @Param status query string false "Status (pending, succeeded, failed)"
+// @Param limit query int false "Maximum number of deliveries" default(50)
+// @Success 200 {array} entity.WebhookDelivery
+// @Router /api/v1/webhooks/deliveries [get]
+func (h *WebhookEndpointHandler) ListWebhookDeliveries(c *gin.Context) {
+	if !h.webhooksAvailable(c) {
+		return
+	}
+	orgID, _ := middleware.GetOrgID(c)
+
+	limit, _ := parseInt(c.DefaultQuery("limit", "50"))
+	if limit <= 0 || limit > 500 {
+		limit = 50
+	}
+
+	filter := entity.WebhookDeliveryFilter{
+		OrganizationID: orgID,
+		Pagination:     &entity.Pagination{Page: 1, PageSize: limit},
+	}
+	if endpointID := c.Query("endpoint_id"); endpointID != "" {
+		id, err := uuid.Parse(endpointID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid endpoint ID"})
+			return
+		}
+		filter.EndpointID = &id
+	}
+	if event := c.Query("event"); event != "" {
+		filter.EventType = entity.WebhookEventType(strings.ToLower(event))
+		if !filter.EventType.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid event type"})
+			return
+		}
+	}
+	if status := c.Query("status"); status != "" {
+		filter.Status = entity.WebhookDeliveryStatus(strings.ToLower(status))
+		if !filter.Status.IsValid() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid status"})
+			return
+		}
+	}
+
+	deliveries, total, err := h.webhookService.ListDeliveries(c.Request.Context(), filter)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    deliveries,
+		"total":   total,
+	})
+}
+
+// GetWebhookDelivery returns a webhook delivery with its payload and last response
+// @Summary Get a webhook delivery
+// @Tags Webhooks
+// @Produce json
+// @Param deliveryId path string true "Delivery ID"
+// @Success 200 {object} entity.WebhookDelivery
+// @Router /api/v1/webhooks/deliveries/{deliveryId} [get]
+func (h *WebhookEndpointHandler) GetWebhookDelivery(c *gin.Context) {
+	if !h.webhooksAvailable(c) {
+		return
+	}
+	orgID, _ := middleware.GetOrgID(c)
+
+	deliveryID, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return
+	}
+
+	delivery, err := h.webhookService.GetDelivery(c.Request.Context(), orgID, deliveryID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    delivery,
+	})
+}
+
+// ReplayWebhookDelivery sends a delivery's event to its endpoint again
+// @Summary Replay a webhook delivery
+// @Description Creates a new delivery with the same event ID and payload, attempted right away and retried like any other
+// @Tags Webhooks
+// @Produce json
+// @Param deliveryId path string true "Delivery ID"
+// @Success 201 {object} entity.WebhookDelivery
+// @Router /api/v1/webhooks/deliveries/{deliveryId}/replay [post]
+func (h *WebhookEndpointHandler) ReplayWebhookDelivery(c *gin.Context) {
+	if !h.webhooksAvailable(c) {
+		return
+	}
+	orgID, _ := middleware.GetOrgID(c)
+
+	deliveryID, err := uuid.Parse(c.Param("deliveryId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid delivery ID"})
+		return
+	}
+
+	delivery, err := h.webhookService.ReplayDelivery(c.Request.Context(), orgID, deliveryID)
+	if err != nil {
+		respondWithError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    delivery,
+	})
+}
@@ -502,7 +502,7 @@ func (h *WebhookHandler) createSyncJob(ctx context.Context, platform entity.Plat
 			SyncType:           entity.SyncTypeWebhook,
 			SyncScope:          scope,
 			Status:             entity.SyncStatusPending,
-			Priority:           5, // Medium-high priority
+			Priority:           entity.SyncJobPriorityWebhook,
 			ScheduledAt:        now,
 			DateRangeStart:     &start,
 			DateRangeEnd:       &now,
@@ -0,0 +1,135 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// APIKeyHeader carries an organization API key. Keys are also accepted as
+// Authorization bearer tokens.
+const APIKeyHeader = "X-API-Key"
+
+// ContextKeyAPIKeyID holds the ID of the API key that authenticated the request
+const ContextKeyAPIKeyID = "api_key_id"
+
+// APIKeyRole is the role set in the context of requests authenticated with an API key
+const APIKeyRole = "api_key"
+
+// APIKeyAuthenticator validates organization API keys. It is implemented by the
+// auth package's APIKeyService.
+type APIKeyAuthenticator interface {
+	Authenticate(ctx context.Context, rawKey, clientIP string) (*entity.APIKey, error)
+}
+
+// apiKeyRoutes maps the route prefixes reachable with API keys to the resource
+// of the permission they require: "<resource>:read" for GET and HEAD requests,
+// "<resource>:write" otherwise. Every other route rejects API keys.
+var apiKeyRoutes = []struct {
+	prefix   string
+	resource string
+}{
+	{"/api/v1/dashboard", "analytics"},
+	{"/api/v1/analytics", "analytics"},
+	{"/api/v1/budget", "analytics"},
+	{"/api/v1/alerts", "analytics"},
+	{"/api/v1/campaigns", "campaigns"},
+	{"/api/v1/ad-accounts", "campaigns"},
+	{"/api/v1/ad-sets", "campaigns"},
+	{"/api/v1/ads", "campaigns"},
+	{"/api/v1/connections", "accounts"},
+	{"/api/v1/platforms", "accounts"},
+}
+
+// APIKeyPermission returns the permission an API key needs for a route, and
+// false when the route cannot be used with API keys
+func APIKeyPermission(method, route string) (string, bool) {
+	for _, r := range apiKeyRoutes {
+		if route == r.prefix || strings.HasPrefix(route, r.prefix+"/") {
+			if method == http.MethodGet || method == http.MethodHead {
+				return r.resource + ":read", true
+			}
+			return r.resource + ":write", true
+		}
+	}
+	return "", false
+}
+
+// SetAPIKeyAuthenticator enables API key authentication alongside JWTs
+func (m *AuthMiddleware) SetAPIKeyAuthenticator(authenticator APIKeyAuthenticator) {
+	m.apiKeys = authenticator
+}
+
+// extractAPIKey returns the API key of the request from the API key header or
+// the Authorization header, if API keys are enabled
+func (m *AuthMiddleware) extractAPIKey(c *gin.Context) string {
+	if m.apiKeys == nil {
+		return ""
+	}
+	if key := c.GetHeader(APIKeyHeader); key != "" {
+		return key
+	}
+	if authHeader := c.GetHeader("Authorization"); strings.HasPrefix(authHeader, "Bearer "+entity.APIKeyPrefix) {
+		return strings.TrimPrefix(authHeader, "Bearer ")
+	}
+	return ""
+}
+
+// authenticateAPIKey authenticates the request with an API key. The key acts on
+// behalf of the user who created it, limited to its scopes and to the routes
+// in apiKeyRoutes.
+func (m *AuthMiddleware) authenticateAPIKey(c *gin.Context, rawKey string) {
+	key, err := m.apiKeys.Authenticate(c.Request.Context(), rawKey, c.ClientIP())
+	if err != nil {
+		appErr, ok := err.(*errors.AppError)
+		if !ok {
+			appErr = errors.ErrUnauthorized("Invalid API key")
+		}
+		m.abortWithError(c, appErr)
+		return
+	}
+
+	permission, ok := APIKeyPermission(c.Request.Method, c.FullPath())
+	if !ok {
+		m.abortWithError(c, errors.ErrForbidden("This endpoint cannot be used with an API key"))
+		return
+	}
+	if !m.hasPermission(key.Scopes, permission) {
+		m.abortWithError(c, errors.ErrForbidden("API key is missing the required scope: "+permission))
+		return
+	}
+
+	if key.CreatedBy != nil {
+		c.Set(ContextKeyUserID, *key.CreatedBy)
+	}
+	c.Set(ContextKeyOrgID, key.OrganizationID)
+	c.Set(ContextKeyRole, APIKeyRole)
+	c.Set(ContextKeyPermissions, key.Scopes)
+	c.Set(ContextKeyAPIKeyID, key.ID)
+
+	c.Next()
+}
+
+// hasPermission checks if any of the permissions matches the required one
+func (m *AuthMiddleware) hasPermission(permissions []string, required string) bool {
+	for _, p := range permissions {
+		if p == "*" || m.matchPermission(p, required) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAPIKeyID extracts the ID of the API key that authenticated the request
+func GetAPIKeyID(c *gin.Context) (uuid.UUID, bool) {
+	keyID, exists := c.Get(ContextKeyAPIKeyID)
+	if !exists {
+		return uuid.Nil, false
+	}
+	return keyID.(uuid.UUID), true
+}
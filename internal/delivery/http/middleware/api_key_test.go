@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/ads-aggregator/ads-aggregator/pkg/jwt"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type mockAPIKeyAuthenticator struct {
+	keys map[string]*entity.APIKey
+}
+
+func (m *mockAPIKeyAuthenticator) Authenticate(ctx context.Context, rawKey, clientIP string) (*entity.APIKey, error) {
+	if key, ok := m.keys[rawKey]; ok {
+		return key, nil
+	}
+	return nil, errors.ErrUnauthorized("Invalid API key")
+}
+
+func newAPIKeyTestRouter(key *entity.APIKey) *gin.Engine {
+	m := NewAuthMiddleware(jwt.NewManager("test-secret", 0, 0))
+	m.SetAPIKeyAuthenticator(&mockAPIKeyAuthenticator{keys: map[string]*entity.APIKey{"aak_valid": key}})
+
+	ok := func(c *gin.Context) {
+		orgID, _ := GetOrgID(c)
+		keyID, _ := GetAPIKeyID(c)
+		c.JSON(http.StatusOK, gin.H{"org_id": orgID, "key_id": keyID})
+	}
+
+	r := gin.New()
+	v1 := r.Group("/api/v1", m.Authenticate())
+	v1.GET("/analytics/reports/:reportId", ok)
+	v1.POST("/alerts/rules", ok)
+	v1.GET("/ads/:adId", ok)
+	v1.GET("/user/me", ok)
+	v1.POST("/api-keys", ok)
+	return r
+}
+
+func TestAuthenticate_APIKey(t *testing.T) {
+	userID := uuid.New()
+	key := &entity.APIKey{
+		BaseEntity:     entity.BaseEntity{ID: uuid.New()},
+		OrganizationID: uuid.New(),
+		Scopes:         []string{"analytics:read", "campaigns:*"},
+		CreatedBy:      &userID,
+	}
+	r := newAPIKeyTestRouter(key)
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		header string
+		value  string
+		want   int
+	}{
+		{"read scope via header", http.MethodGet, "/api/v1/analytics/reports/123", APIKeyHeader, "aak_valid", http.StatusOK},
+		{"read scope via bearer", http.MethodGet, "/api/v1/analytics/reports/123", "Authorization", "Bearer aak_valid", http.StatusOK},
+		{"wildcard scope", http.MethodGet, "/api/v1/ads/123", APIKeyHeader, "aak_valid", http.StatusOK},
+		{"missing write scope", http.MethodPost, "/api/v1/alerts/rules", APIKeyHeader, "aak_valid", http.StatusForbidden},
+		{"route not available to keys", http.MethodGet, "/api/v1/user/me", APIKeyHeader, "aak_valid", http.StatusForbidden},
+		{"keys cannot manage keys", http.MethodPost, "/api/v1/api-keys", APIKeyHeader, "aak_valid", http.StatusForbidden},
+		{"unknown key", http.MethodGet, "/api/v1/analytics/reports/123", APIKeyHeader, "aak_unknown", http.StatusUnauthorized},
+		{"invalid bearer token", http.MethodGet, "/api/v1/analytics/reports/123", "Authorization", "Bearer not-a-jwt", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			req.Header.Set(tt.header, tt.value)
+			w := httptest.NewRecorder()
+			r.ServeHTTP(w, req)
+
+			if w.Code != tt.want {
+				t.Errorf("status = %d, want %d (body %s)", w.Code, tt.want, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestAuthenticate_APIKeyContext(t *testing.T) {
+	userID := uuid.New()
+	key := &entity.APIKey{
+		BaseEntity:     entity.BaseEntity{ID: uuid.New()},
+		OrganizationID: uuid.New(),
+		Scopes:         []string{"analytics:read"},
+		CreatedBy:      &userID,
+	}
+	m := NewAuthMiddleware(jwt.NewManager("test-secret", 0, 0))
+	m.SetAPIKeyAuthenticator(&mockAPIKeyAuthenticator{keys: map[string]*entity.APIKey{"aak_valid": key}})
+
+	r := gin.New()
+	var ctx *gin.Context
+	r.GET("/api/v1/dashboard/summary", m.Authenticate(), func(c *gin.Context) {
+		ctx = c.Copy()
+		c.Status(http.StatusOK)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/dashboard/summary", nil)
+	req.Header.Set(APIKeyHeader, "aak_valid")
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", w.Code)
+	}
+	if orgID, _ := GetOrgID(ctx); orgID != key.OrganizationID {
+		t.Errorf("org ID = %s, want the key's organization", orgID)
+	}
+	if gotUser, _ := GetUserID(ctx); gotUser != userID {
+		t.Errorf("user ID = %s, want the key's creator", gotUser)
+	}
+	if role, _ := GetRole(ctx); role != APIKeyRole {
+		t.Errorf("role = %q, want %q", role, APIKeyRole)
+	}
+	if perms, _ := GetPermissions(ctx); len(perms) != 1 || perms[0] != "analytics:read" {
+		t.Errorf("permissions = %v, want the key's scopes", perms)
+	}
+}
+
+func TestAPIKeyPermission(t *testing.T) {
+	tests := []struct {
+		method string
+		route  string
+		want   string
+		ok     bool
+	}{
+		{http.MethodGet, "/api/v1/dashboard/summary", "analytics:read", true},
+		{http.MethodPut, "/api/v1/budget", "analytics:write", true},
+		{http.MethodDelete, "/api/v1/connections/:accountId", "accounts:write", true},
+		{http.MethodGet, "/api/v1/ad-sets/:adSetId", "campaigns:read", true},
+		{http.MethodGet, "/api/v1/adsfoo", "", false},
+		{http.MethodGet, "/api/v1/settings/profile", "", false},
+		{http.MethodPost, "/api/v1/webhooks/endpoints", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := APIKeyPermission(tt.method, tt.route)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("APIKeyPermission(%s, %s) = %q, %v, want %q, %v", tt.method, tt.route, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -215,6 +215,9 @@ func NewAuditMiddleware(logger AuditLogger) *AuditMiddleware {
 			"POST:/api/v1/auth/reset-password": AuditActionPasswordReset,
 			"PUT:/api/v1/auth/password":     AuditActionPasswordChange,
 			"POST:/api/v1/auth/refresh":     AuditActionTokenRefresh,
+			"POST:/api/v1/auth/mfa/verify":  AuditActionLogin,
+			"POST:/api/v1/user/mfa/enable":  AuditActionMFAEnable,
+			"POST:/api/v1/user/mfa/disable": AuditActionMFADisable,
 
 			"POST:/api/v1/oauth/connect":    AuditActionPlatformConnect,
 			"DELETE:/api/v1/accounts":       AuditActionPlatformDisconnect,
@@ -25,9 +25,10 @@ const (
 	RefreshTokenCookie = "refresh_token"
 )
 
-// AuthMiddleware handles JWT authentication
+// AuthMiddleware handles JWT and, when enabled, API key authentication
 type AuthMiddleware struct {
 	jwtManager *jwt.Manager
+	apiKeys    APIKeyAuthenticator
 }
 
 // NewAuthMiddleware creates a new auth middleware
@@ -37,9 +38,14 @@ func NewAuthMiddleware(jwtManager *jwt.Manager) *AuthMiddleware {
 	}
 }
 
-// Authenticate returns a middleware that validates JWT tokens
+// Authenticate returns a middleware that validates JWT tokens or API keys
 func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
 	return func(c *gin.Context) {
+		if apiKey := m.extractAPIKey(c); apiKey != "" {
+			m.authenticateAPIKey(c, apiKey)
+			return
+		}
+
 		// Try to extract token from cookie first, then fall back to Authorization header
 		token := m.extractToken(c)
 		if token == "" {
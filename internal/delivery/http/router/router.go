@@ -6,6 +6,7 @@ import (
 
 	"github.com/ads-aggregator/ads-aggregator/internal/delivery/http/handler"
 	"github.com/ads-aggregator/ads-aggregator/internal/delivery/http/middleware"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
@@ -41,6 +42,10 @@ type Router struct {
 	platformHandler  *handler.PlatformHandler
 	analyticsHandler *handler.AnalyticsHandler
 	eventsHandler    *handler.EventsHandler
+	budgetHandler    *handler.BudgetHandler
+	anomalyHandler   *handler.AnomalyHandler
+	alertRuleHandler *handler.AlertRuleHandler
+	webhookHandler   *handler.WebhookEndpointHandler
 
 	// Middleware
 	authMiddleware      *middleware.AuthMiddleware
@@ -54,6 +59,10 @@ func NewRouter(
 	platformHandler *handler.PlatformHandler,
 	analyticsHandler *handler.AnalyticsHandler,
 	eventsHandler *handler.EventsHandler,
+	budgetHandler *handler.BudgetHandler,
+	anomalyHandler *handler.AnomalyHandler,
+	alertRuleHandler *handler.AlertRuleHandler,
+	webhookHandler *handler.WebhookEndpointHandler,
 	authMiddleware *middleware.AuthMiddleware,
 	rateLimitMiddleware *middleware.RateLimitMiddleware,
 ) *Router {
@@ -70,6 +79,10 @@ func NewRouter(
 		platformHandler:     platformHandler,
 		analyticsHandler:    analyticsHandler,
 		eventsHandler:       eventsHandler,
+		budgetHandler:       budgetHandler,
+		anomalyHandler:      anomalyHandler,
+		alertRuleHandler:    alertRuleHandler,
+		webhookHandler:      webhookHandler,
 		authMiddleware:      authMiddleware,
 		rateLimitMiddleware: rateLimitMiddleware,
 	}
@@ -122,6 +135,11 @@ func (r *Router) setupPublicRoutes(rg *gin.RouterGroup) {
 		auth.POST("/reset-password", r.authHandler.ResetPassword)
 		auth.POST("/verify-email", r.authHandler.VerifyEmail)
 
+		// Second login step - authenticated by the MFA challenge token from /login
+		auth.POST("/mfa/verify", r.authHandler.VerifyMFA)
+		auth.POST("/mfa/enroll", r.authHandler.BeginLoginMFAEnrollment)
+		auth.POST("/mfa/enroll/confirm", r.authHandler.ConfirmLoginMFAEnrollment)
+
 		// Session check - optionally authenticated (returns user if token valid)
 		auth.GET("/session", r.authMiddleware.OptionalAuth(), r.authHandler.GetSession)
 
@@ -138,6 +156,8 @@ func (r *Router) setupOAuthCallbacks(rg *gin.RouterGroup) {
 		oauth.GET("/meta/callback", r.authHandler.MetaCallback)
 		oauth.GET("/tiktok/callback", r.authHandler.TikTokCallback)
 		oauth.GET("/shopee/callback", r.authHandler.ShopeeCallback)
+		oauth.GET("/google/callback", r.authHandler.GoogleCallback)
+		oauth.GET("/lazada/callback", r.authHandler.LazadaCallback)
 	}
 }
 
@@ -177,6 +197,7 @@ func (r *Router) setupProtectedRoutes(rg *gin.RouterGroup) {
 		campaigns.GET("", r.analyticsHandler.ListCampaigns)
 		campaigns.GET("/:id", r.analyticsHandler.GetCampaign)
 		campaigns.GET("/:id/metrics", r.analyticsHandler.GetCampaignMetrics)
+		campaigns.GET("/:id/pacing", r.analyticsHandler.GetCampaignPacing)
 		campaigns.GET("/:id/ad-sets", r.analyticsHandler.ListAdSets)
 		campaigns.GET("/:id/ads", r.analyticsHandler.ListAds)
 	}
@@ -205,8 +226,58 @@ func (r *Router) setupProtectedRoutes(rg *gin.RouterGroup) {
 		analytics.GET("/top-performers", r.analyticsHandler.GetTopPerformers)
 		analytics.GET("/export", r.analyticsHandler.ExportAnalytics)
 		analytics.POST("/calculate", r.analyticsHandler.CalculateMetrics)
+		analytics.GET("/reports", r.analyticsHandler.ListReports)
 		analytics.POST("/reports/generate", r.analyticsHandler.GenerateReport)
 		analytics.GET("/reports/:reportId", r.analyticsHandler.GetReport)
+		analytics.GET("/reports/:reportId/download", r.analyticsHandler.DownloadReport)
+		analytics.GET("/reports/schedules", r.analyticsHandler.ListReportSchedules)
+		analytics.POST("/reports/schedules", r.analyticsHandler.CreateReportSchedule)
+		analytics.PUT("/reports/schedules/:scheduleId", r.analyticsHandler.UpdateReportSchedule)
+		analytics.DELETE("/reports/schedules/:scheduleId", r.analyticsHandler.DeleteReportSchedule)
+		analytics.GET("/attribution", r.analyticsHandler.GetCampaignAttribution)
+		analytics.POST("/attribution/run", r.analyticsHandler.RunAttribution)
+		analytics.POST("/attribution/touchpoints", r.analyticsHandler.RecordTouchpoint)
+		analytics.GET("/profit", r.analyticsHandler.GetProfitability)
+		analytics.GET("/profit/sku-costs", r.analyticsHandler.ListSKUCosts)
+		analytics.POST("/profit/sku-costs", r.analyticsHandler.UploadSKUCosts)
+		analytics.GET("/pacing", r.analyticsHandler.GetPacing)
+
+		if r.anomalyHandler != nil {
+			analytics.GET("/anomalies", r.anomalyHandler.ListAnomalies)
+			analytics.POST("/anomalies/detect", r.anomalyHandler.DetectAnomalies)
+			analytics.POST("/anomalies/:anomalyId/acknowledge", r.anomalyHandler.AcknowledgeAnomaly)
+		}
+	}
+
+	// ============================================
+	// Budget routes
+	// ============================================
+	if r.budgetHandler != nil {
+		budget := protected.Group("/budget")
+		{
+			budget.GET("", r.budgetHandler.GetBudget)
+			budget.PUT("", r.budgetHandler.SetBudget)
+			budget.DELETE("", r.budgetHandler.DeleteBudget)
+			budget.GET("/status", r.budgetHandler.GetBudgetStatus)
+			budget.POST("/alerts/:alertId/acknowledge", r.budgetHandler.AcknowledgeBudgetAlert)
+		}
+	}
+
+	// ============================================
+	// Alert rule routes
+	// ============================================
+	if r.alertRuleHandler != nil {
+		alerts := protected.Group("/alerts")
+		{
+			alerts.GET("/rules", r.alertRuleHandler.ListAlertRules)
+			alerts.POST("/rules", r.alertRuleHandler.CreateAlertRule)
+			alerts.POST("/rules/evaluate", r.alertRuleHandler.EvaluateAlertRules)
+			alerts.GET("/rules/:ruleId", r.alertRuleHandler.GetAlertRule)
+			alerts.PUT("/rules/:ruleId", r.alertRuleHandler.UpdateAlertRule)
+			alerts.DELETE("/rules/:ruleId", r.alertRuleHandler.DeleteAlertRule)
+			alerts.GET("/history", r.alertRuleHandler.ListAlertHistory)
+			alerts.POST("/history/:eventId/acknowledge", r.alertRuleHandler.AcknowledgeAlert)
+		}
 	}
 
 	// ============================================
@@ -227,10 +298,26 @@ func (r *Router) setupProtectedRoutes(rg *gin.RouterGroup) {
 		settings.POST("/team/invite", r.authHandler.InviteMember)
 		settings.DELETE("/team/:memberId", r.authHandler.RemoveMember)
 
+		// Security (owners only)
+		settings.GET("/security", r.authMiddleware.RequireRole(string(entity.RoleOwner)), r.authHandler.GetSecuritySettings)
+		settings.PUT("/security", r.authMiddleware.RequireRole(string(entity.RoleOwner)), r.authHandler.UpdateSecuritySettings)
+
 		// Billing (placeholder)
 		settings.GET("/billing", r.getBillingInfo)
 	}
 
+	// ============================================
+	// API key routes (owners and admins)
+	// ============================================
+	apiKeys := protected.Group("/api-keys")
+	apiKeys.Use(r.authMiddleware.RequireRole(string(entity.RoleOwner), string(entity.RoleAdmin)))
+	{
+		apiKeys.GET("", r.authHandler.ListAPIKeys)
+		apiKeys.POST("", r.authHandler.CreateAPIKey)
+		apiKeys.POST("/:keyId/rotate", r.authHandler.RotateAPIKey)
+		apiKeys.DELETE("/:keyId", r.authHandler.RevokeAPIKey)
+	}
+
 	// ============================================
 	// User routes
 	// ============================================
@@ -240,6 +327,18 @@ func (r *Router) setupProtectedRoutes(rg *gin.RouterGroup) {
 		user.PUT("/me", r.authHandler.UpdateProfile)
 		user.POST("/change-password", r.authHandler.ChangePassword)
 		user.POST("/resend-verification", r.authHandler.ResendVerificationEmail)
+
+		// Multi-factor authentication
+		user.GET("/mfa", r.authHandler.GetMFAStatus)
+		user.POST("/mfa/enroll", r.authHandler.EnrollMFA)
+		user.POST("/mfa/enable", r.authHandler.EnableMFA)
+		user.POST("/mfa/disable", r.authHandler.DisableMFA)
+		user.POST("/mfa/recovery-codes", r.authHandler.RegenerateRecoveryCodes)
+
+		// Sessions (logged-in devices)
+		user.GET("/sessions", r.authHandler.ListSessions)
+		user.DELETE("/sessions", r.authHandler.RevokeOtherSessions)
+		user.DELETE("/sessions/:sessionId", r.authHandler.RevokeSession)
 	}
 
 	// ============================================
@@ -303,6 +402,22 @@ func (r *Router) setupProtectedRoutes(rg *gin.RouterGroup) {
 		webhooks.POST("/meta", r.platformHandler.MetaWebhook)
 		webhooks.POST("/tiktok", r.platformHandler.TikTokWebhook)
 		webhooks.POST("/shopee", r.platformHandler.ShopeeWebhook)
+
+		// Outbound webhooks are managed by owners and admins only
+		if r.webhookHandler != nil {
+			outbound := webhooks.Group("")
+			outbound.Use(r.authMiddleware.RequireRole(string(entity.RoleOwner), string(entity.RoleAdmin)))
+			outbound.GET("/events", r.webhookHandler.ListWebhookEventTypes)
+			outbound.GET("/endpoints", r.webhookHandler.ListWebhookEndpoints)
+			outbound.POST("/endpoints", r.webhookHandler.CreateWebhookEndpoint)
+			outbound.GET("/endpoints/:endpointId", r.webhookHandler.GetWebhookEndpoint)
+			outbound.PUT("/endpoints/:endpointId", r.webhookHandler.UpdateWebhookEndpoint)
+			outbound.DELETE("/endpoints/:endpointId", r.webhookHandler.DeleteWebhookEndpoint)
+			outbound.POST("/endpoints/:endpointId/rotate-secret", r.webhookHandler.RotateWebhookSecret)
+			outbound.GET("/deliveries", r.webhookHandler.ListWebhookDeliveries)
+			outbound.GET("/deliveries/:deliveryId", r.webhookHandler.GetWebhookDelivery)
+			outbound.POST("/deliveries/:deliveryId/replay", r.webhookHandler.ReplayWebhookDelivery)
+		}
 	}
 }
 
@@ -311,7 +426,7 @@ func (r *Router) corsMiddleware() gin.HandlerFunc {
 	return cors.New(cors.Config{
 		AllowOrigins:     r.config.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Request-ID", "X-API-Key"},
 		ExposeHeaders:    []string{"Content-Length", "X-Request-ID", "X-Total-Count"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
@@ -348,6 +463,8 @@ func (r *Router) getAvailablePlatforms(c *gin.Context) {
 			{"id": "meta", "name": "Meta Ads", "description": "Facebook & Instagram Ads", "icon": "meta"},
 			{"id": "tiktok", "name": "TikTok Ads", "description": "TikTok for Business", "icon": "tiktok"},
 			{"id": "shopee", "name": "Shopee Ads", "description": "Shopee Seller Ads", "icon": "shopee"},
+			{"id": "google", "name": "Google Ads", "description": "Google Search, Display & YouTube Ads", "icon": "google"},
+			{"id": "lazada", "name": "Lazada Ads", "description": "Lazada Sponsored Solutions", "icon": "lazada"},
 		},
 	})
 }
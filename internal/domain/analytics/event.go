@@ -190,12 +190,16 @@ type FunnelStep struct {
 	Count      int64   `json:"count"`
 	Percentage float64 `json:"percentage"`
 	DropOff    float64 `json:"drop_off"`
+	// Median time users took to reach this step from the previous one.
+	// Only set for configurable funnels.
+	MedianSecondsFromPrevious *float64 `json:"median_seconds_from_previous,omitempty"`
 }
 
 // Funnel represents a conversion funnel
 type Funnel struct {
-	Name  string       `json:"name"`
-	Steps []FunnelStep `json:"steps"`
+	Name                    string       `json:"name"`
+	ConversionWindowSeconds int64        `json:"conversion_window_seconds,omitempty"`
+	Steps                   []FunnelStep `json:"steps"`
 }
 
 // TimeSeriesPoint represents a point in time series data
@@ -0,0 +1,93 @@
+package analytics
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrFunnelNotFound is returned when no funnel definition exists with the given name
+	ErrFunnelNotFound = errors.New("funnel not found")
+	// ErrFunnelExists is returned when creating a funnel whose name is already taken
+	ErrFunnelExists = errors.New("funnel already exists")
+)
+
+// funnelNamePattern restricts funnel names to URL-safe slugs
+var funnelNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,99}$`)
+
+// FunnelStepDefinition is a single step of a configurable funnel
+type FunnelStepDefinition struct {
+	Name      string            `json:"name"`
+	EventType EventType         `json:"event_type"`
+	Filters   map[string]string `json:"filters,omitempty"` // Event properties that must match
+}
+
+// Matches reports whether an event satisfies this step
+func (s *FunnelStepDefinition) Matches(eventType EventType, properties map[string]interface{}) bool {
+	if eventType != s.EventType {
+		return false
+	}
+	for key, want := range s.Filters {
+		value, ok := properties[key]
+		if !ok || fmt.Sprint(value) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// FunnelDefinition is an admin-defined funnel stored in the database
+type FunnelDefinition struct {
+	ID                      uuid.UUID              `json:"id"`
+	Name                    string                 `json:"name"`
+	Description             string                 `json:"description,omitempty"`
+	Steps                   []FunnelStepDefinition `json:"steps"`
+	ConversionWindowSeconds int64                  `json:"conversion_window_seconds"` // 0 means no limit
+	CreatedAt               time.Time              `json:"created_at"`
+	UpdatedAt               time.Time              `json:"updated_at"`
+}
+
+// ConversionWindow returns the time a user has after the first step to complete the funnel
+func (d *FunnelDefinition) ConversionWindow() time.Duration {
+	return time.Duration(d.ConversionWindowSeconds) * time.Second
+}
+
+// EventTypes returns the distinct event types used by the funnel steps
+func (d *FunnelDefinition) EventTypes() []EventType {
+	seen := make(map[EventType]bool, len(d.Steps))
+	var types []EventType
+	for _, step := range d.Steps {
+		if !seen[step.EventType] {
+			seen[step.EventType] = true
+			types = append(types, step.EventType)
+		}
+	}
+	return types
+}
+
+// Validate checks that the funnel definition can be evaluated. Steps without
+// a name are named after their event type.
+func (d *FunnelDefinition) Validate() error {
+	if !funnelNamePattern.MatchString(d.Name) {
+		return errors.New("name must be lowercase letters, digits, '-' or '_'")
+	}
+	if len(d.Steps) < 2 {
+		return errors.New("a funnel needs at least 2 steps")
+	}
+	for i, step := range d.Steps {
+		if step.EventType == "" {
+			return fmt.Errorf("step %d is missing an event_type", i+1)
+		}
+		if step.Name == "" {
+			d.Steps[i].Name = string(step.EventType)
+		}
+	}
+	if d.ConversionWindowSeconds < 0 {
+		return errors.New("conversion_window_seconds cannot be negative")
+	}
+	return nil
+}
@@ -31,6 +31,11 @@ type Repository interface {
 
 	// Funnel analysis
 	GetFunnel(ctx context.Context, name string, from, to time.Time) (*Funnel, error)
+	ListFunnelDefinitions(ctx context.Context) ([]*FunnelDefinition, error)
+	GetFunnelDefinition(ctx context.Context, name string) (*FunnelDefinition, error)
+	CreateFunnelDefinition(ctx context.Context, def *FunnelDefinition) error
+	UpdateFunnelDefinition(ctx context.Context, def *FunnelDefinition) error
+	DeleteFunnelDefinition(ctx context.Context, name string) error
 
 	// Cohort analysis
 	GetCohortAnalysis(ctx context.Context, from, to time.Time, period string) (*CohortAnalysis, error)
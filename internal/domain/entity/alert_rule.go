@@ -0,0 +1,137 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AlertChannel is a way of delivering a triggered alert rule
+type AlertChannel string
+
+const (
+	AlertChannelEmail   AlertChannel = "email"   // Organization owners and admins
+	AlertChannelSSE     AlertChannel = "sse"     // Connected dashboard clients
+	AlertChannelWebhook AlertChannel = "webhook" // The rule's webhook URL
+)
+
+// IsValid checks if the channel is supported
+func (c AlertChannel) IsValid() bool {
+	switch c {
+	case AlertChannelEmail, AlertChannelSSE, AlertChannelWebhook:
+		return true
+	default:
+		return false
+	}
+}
+
+// DefaultAlertCooldownMinutes is the minimum time between two alerts of a rule for the same campaign
+const DefaultAlertCooldownMinutes = 24 * 60
+
+// AlertRule is a user-defined condition on daily campaign metrics, written in
+// the alert rule language, e.g. "roas < 1.5 for 3 days" or "spend > 500".
+// It is evaluated for every campaign in its scope after each metrics sync.
+type AlertRule struct {
+	BaseEntity
+	OrganizationID uuid.UUID `json:"organization_id" gorm:"type:uuid;not null;index"`
+	Name           string    `json:"name" gorm:"size:255;not null"`
+	Description    string    `json:"description,omitempty" gorm:"type:text"`
+	Expression     string    `json:"expression" gorm:"type:text;not null"`
+	Currency       string    `json:"currency" gorm:"size:3;not null;default:'MYR'"` // Currency of monetary thresholds
+
+	// Scope; empty lists match every active campaign of the organization
+	Platforms    []Platform  `json:"platforms,omitempty" gorm:"type:jsonb;serializer:json"`
+	AdAccountIDs []uuid.UUID `json:"ad_account_ids,omitempty" gorm:"type:jsonb;serializer:json"`
+	CampaignIDs  []uuid.UUID `json:"campaign_ids,omitempty" gorm:"type:jsonb;serializer:json"`
+
+	// Delivery
+	Channels        []AlertChannel `json:"channels" gorm:"type:jsonb;serializer:json"`
+	WebhookURL      string         `json:"webhook_url,omitempty" gorm:"size:1000"`
+	WebhookSecret   string         `json:"webhook_secret,omitempty" gorm:"size:255"` // Signs webhook payloads
+	CooldownMinutes int            `json:"cooldown_minutes" gorm:"default:1440"`
+
+	IsActive        bool       `json:"is_active" gorm:"default:true"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+	CreatedBy       *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
+}
+
+// HasChannel reports whether the rule delivers alerts over the channel
+func (r *AlertRule) HasChannel(channel AlertChannel) bool {
+	for _, c := range r.Channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// Cooldown returns the minimum time between two alerts for the same campaign
+func (r *AlertRule) Cooldown() time.Duration {
+	return time.Duration(r.CooldownMinutes) * time.Minute
+}
+
+// InScope reports whether a campaign is covered by the rule's scope
+func (r *AlertRule) InScope(campaign *Campaign) bool {
+	if len(r.Platforms) > 0 && !containsPlatform(r.Platforms, campaign.Platform) {
+		return false
+	}
+	if len(r.AdAccountIDs) > 0 && !containsUUID(r.AdAccountIDs, campaign.AdAccountID) {
+		return false
+	}
+	if len(r.CampaignIDs) > 0 && !containsUUID(r.CampaignIDs, campaign.ID) {
+		return false
+	}
+	return true
+}
+
+// AlertEvent records that an alert rule triggered for a campaign. At most one
+// event is recorded per rule, campaign and day.
+type AlertEvent struct {
+	ID             uuid.UUID          `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrganizationID uuid.UUID          `json:"organization_id" gorm:"type:uuid;not null;index"`
+	RuleID         uuid.UUID          `json:"rule_id" gorm:"type:uuid;not null"`
+	RuleName       string             `json:"rule_name" gorm:"size:255"`
+	Expression     string             `json:"expression" gorm:"type:text"` // As evaluated
+	CampaignID     uuid.UUID          `json:"campaign_id" gorm:"type:uuid;not null"`
+	CampaignName   string             `json:"campaign_name" gorm:"size:500"`
+	Platform       Platform           `json:"platform" gorm:"type:platform_type;not null"`
+	MetricDate     time.Time          `json:"metric_date" gorm:"type:date;not null"`                         // Last day of the evaluated window
+	Values         map[string]float64 `json:"values" gorm:"column:metric_values;type:jsonb;serializer:json"` // Metrics of the rule on MetricDate
+	Currency       string             `json:"currency" gorm:"size:3"`
+
+	// Delivery
+	DeliveredChannels []AlertChannel `json:"delivered_channels,omitempty" gorm:"type:jsonb;serializer:json"`
+	DeliveryError     string         `json:"delivery_error,omitempty" gorm:"type:text"`
+	AcknowledgedAt    *time.Time     `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy    *uuid.UUID     `json:"acknowledged_by,omitempty" gorm:"type:uuid"`
+
+	TriggeredAt time.Time `json:"triggered_at" gorm:"autoCreateTime"`
+}
+
+// AlertEventFilter represents filter options for listing alert history
+type AlertEventFilter struct {
+	OrganizationID uuid.UUID
+	RuleID         *uuid.UUID
+	CampaignID     *uuid.UUID
+	DateRange      *DateRange
+	Unacknowledged bool
+	Pagination     *Pagination
+}
+
+func containsPlatform(platforms []Platform, platform Platform) bool {
+	for _, p := range platforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
+
+func containsUUID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, i := range ids {
+		if i == id {
+			return true
+		}
+	}
+	return false
+}
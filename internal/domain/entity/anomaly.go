@@ -0,0 +1,119 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AnomalyMetric is a daily campaign metric watched by the anomaly detector
+type AnomalyMetric string
+
+const (
+	AnomalyMetricSpend AnomalyMetric = "spend"
+	AnomalyMetricCTR   AnomalyMetric = "ctr"
+	AnomalyMetricCPC   AnomalyMetric = "cpc"
+	AnomalyMetricCPA   AnomalyMetric = "cpa"
+	AnomalyMetricROAS  AnomalyMetric = "roas"
+)
+
+// AnomalyMetrics lists the metrics checked for every campaign, in order
+var AnomalyMetrics = []AnomalyMetric{
+	AnomalyMetricSpend,
+	AnomalyMetricCTR,
+	AnomalyMetricCPC,
+	AnomalyMetricCPA,
+	AnomalyMetricROAS,
+}
+
+// IsValid checks if the metric is watched by the anomaly detector
+func (m AnomalyMetric) IsValid() bool {
+	for _, metric := range AnomalyMetrics {
+		if m == metric {
+			return true
+		}
+	}
+	return false
+}
+
+// AnomalySeverity represents how far a value is from its baseline
+type AnomalySeverity string
+
+const (
+	AnomalySeverityLow      AnomalySeverity = "low"
+	AnomalySeverityMedium   AnomalySeverity = "medium"
+	AnomalySeverityHigh     AnomalySeverity = "high"
+	AnomalySeverityCritical AnomalySeverity = "critical" // e.g. spend dropped to zero
+)
+
+// Rank orders severities from low (1) to critical (4), 0 if unknown
+func (s AnomalySeverity) Rank() int {
+	switch s {
+	case AnomalySeverityLow:
+		return 1
+	case AnomalySeverityMedium:
+		return 2
+	case AnomalySeverityHigh:
+		return 3
+	case AnomalySeverityCritical:
+		return 4
+	default:
+		return 0
+	}
+}
+
+// AnomalyDirection tells whether a value is above or below its baseline
+type AnomalyDirection string
+
+const (
+	AnomalyDirectionSpike AnomalyDirection = "spike"
+	AnomalyDirectionDrop  AnomalyDirection = "drop"
+)
+
+// AnomalyBaseline is the method used to compute the expected value
+type AnomalyBaseline string
+
+const (
+	AnomalyBaselineDayOfWeek AnomalyBaseline = "day_of_week" // Same weekday over the previous weeks
+	AnomalyBaselineRolling   AnomalyBaseline = "rolling"     // Every day of the previous weeks
+)
+
+// MetricAnomaly records a daily campaign metric that deviates from its rolling
+// baseline. At most one anomaly is recorded per campaign, day and metric.
+type MetricAnomaly struct {
+	ID             uuid.UUID        `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrganizationID uuid.UUID        `json:"organization_id" gorm:"type:uuid;not null;index"`
+	CampaignID     uuid.UUID        `json:"campaign_id" gorm:"type:uuid;not null"`
+	CampaignName   string           `json:"campaign_name" gorm:"size:500"`
+	Platform       Platform         `json:"platform" gorm:"type:platform_type;not null"`
+	MetricDate     time.Time        `json:"metric_date" gorm:"type:date;not null"`
+	Metric         AnomalyMetric    `json:"metric" gorm:"size:20;not null"`
+	Value          float64          `json:"value"`
+	Expected       float64          `json:"expected"`  // Median of the baseline
+	Deviation      float64          `json:"deviation"` // Median absolute deviation of the baseline
+	Score          float64          `json:"score"`     // Robust z-score, negative for drops
+	ChangePercent  float64          `json:"change_percent"`
+	Direction      AnomalyDirection `json:"direction" gorm:"size:10;not null"`
+	Severity       AnomalySeverity  `json:"severity" gorm:"size:20;not null"`
+	Baseline       AnomalyBaseline  `json:"baseline" gorm:"size:20;not null"`
+	BaselineDays   int              `json:"baseline_days"`
+	Currency       string           `json:"currency,omitempty" gorm:"size:3"`
+
+	// Notification
+	NotifiedAt     *time.Time `json:"notified_at,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy *uuid.UUID `json:"acknowledged_by,omitempty" gorm:"type:uuid"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// AnomalyFilter represents filter options for listing anomalies
+type AnomalyFilter struct {
+	OrganizationID uuid.UUID
+	CampaignID     *uuid.UUID
+	Metrics        []AnomalyMetric
+	MinSeverity    AnomalySeverity
+	DateRange      *DateRange
+	Unacknowledged bool
+	Pagination     *Pagination
+}
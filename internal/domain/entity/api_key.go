@@ -0,0 +1,64 @@
+package entity
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyPrefix starts every API key so keys can be told apart from access tokens
+const APIKeyPrefix = "aak_"
+
+// APIKeyScopes returns the permissions an API key can be granted. They are the
+// permission strings of organization roles; "resource:*" grants every
+// permission of a resource.
+func APIKeyScopes() []string {
+	return []string{
+		"campaigns:read", "campaigns:write",
+		"analytics:read", "analytics:write",
+		"accounts:read", "accounts:write",
+	}
+}
+
+// IsValidAPIKeyScope checks if a scope can be granted to an API key
+func IsValidAPIKeyScope(scope string) bool {
+	for _, s := range APIKeyScopes() {
+		if resource, _, _ := strings.Cut(s, ":"); scope == s || scope == resource+":*" {
+			return true
+		}
+	}
+	return false
+}
+
+// APIKey authenticates programmatic access to an organization's data. Only a
+// hash of the key is stored; the key itself is shown once when it is created
+// or rotated.
+type APIKey struct {
+	BaseEntity
+	OrganizationID uuid.UUID  `json:"organization_id" gorm:"type:uuid;not null;index"`
+	Name           string     `json:"name" gorm:"size:255;not null"`
+	Prefix         string     `json:"prefix" gorm:"size:32;not null"` // First characters of the key, to tell keys apart
+	KeyHash        string     `json:"-" gorm:"size:64;not null;uniqueIndex"`
+	Scopes         []string   `json:"scopes" gorm:"type:jsonb;serializer:json"`
+	CreatedBy      *uuid.UUID `json:"created_by,omitempty" gorm:"type:uuid"`
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP     string     `json:"last_used_ip,omitempty" gorm:"size:45"`
+	RotatedAt      *time.Time `json:"rotated_at,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	RevokedBy      *uuid.UUID `json:"revoked_by,omitempty" gorm:"type:uuid"`
+}
+
+// TableName returns the table name
+func (APIKey) TableName() string {
+	return "api_keys"
+}
+
+// IsActive reports whether the key can authenticate requests at the given time
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
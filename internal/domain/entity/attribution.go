@@ -0,0 +1,141 @@
+package entity
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// AttributionModel decides how an order's revenue is shared between the ad clicks that preceded it
+type AttributionModel string
+
+const (
+	AttributionModelLastClick  AttributionModel = "last_click"
+	AttributionModelFirstClick AttributionModel = "first_click"
+	AttributionModelLinear     AttributionModel = "linear"
+	AttributionModelTimeDecay  AttributionModel = "time_decay"
+)
+
+const (
+	// DefaultAttributionLookback is how far before an order clicks are considered
+	DefaultAttributionLookback = 7 * 24 * time.Hour
+
+	// AttributionTimeDecayHalfLife is the age at which a click gets half the
+	// credit of a click made at the time of the order
+	AttributionTimeDecayHalfLife = 7 * 24 * time.Hour
+)
+
+// AttributionModels lists every supported model; orders are attributed under all of them
+func AttributionModels() []AttributionModel {
+	return []AttributionModel{
+		AttributionModelLastClick,
+		AttributionModelFirstClick,
+		AttributionModelLinear,
+		AttributionModelTimeDecay,
+	}
+}
+
+// IsValid reports whether m is a supported attribution model
+func (m AttributionModel) IsValid() bool {
+	switch m {
+	case AttributionModelLastClick, AttributionModelFirstClick, AttributionModelLinear, AttributionModelTimeDecay:
+		return true
+	default:
+		return false
+	}
+}
+
+// Weights returns the share of credit for each click, in the order given.
+// clicks must be sorted oldest first; the weights always sum to 1.
+func (m AttributionModel) Weights(clicks []time.Time, convertedAt time.Time) []float64 {
+	weights := make([]float64, len(clicks))
+	if len(clicks) == 0 {
+		return weights
+	}
+
+	switch m {
+	case AttributionModelFirstClick:
+		weights[0] = 1
+	case AttributionModelLinear:
+		for i := range weights {
+			weights[i] = 1 / float64(len(clicks))
+		}
+	case AttributionModelTimeDecay:
+		var total float64
+		for i, clickedAt := range clicks {
+			age := convertedAt.Sub(clickedAt)
+			if age < 0 {
+				age = 0
+			}
+			weights[i] = math.Pow(2, -float64(age)/float64(AttributionTimeDecayHalfLife))
+			total += weights[i]
+		}
+		for i := range weights {
+			weights[i] /= total
+		}
+	default:
+		weights[len(weights)-1] = 1
+	}
+
+	return weights
+}
+
+// AttributionTouchpoint is an ad click that can later be credited with an order.
+// Touchpoints are matched to orders on CustomerID.
+type AttributionTouchpoint struct {
+	BaseEntity
+	OrganizationID uuid.UUID  `json:"organization_id" gorm:"type:uuid;not null"`
+	CustomerID     string     `json:"customer_id" gorm:"size:255;not null"`
+	CampaignID     *uuid.UUID `json:"campaign_id,omitempty" gorm:"type:uuid"`
+	AdID           *uuid.UUID `json:"ad_id,omitempty" gorm:"type:uuid"`
+	ClickID        string     `json:"click_id,omitempty" gorm:"size:255"` // fbclid, gclid, ttclid
+	UTMSource      string     `json:"utm_source,omitempty" gorm:"column:utm_source;size:100"`
+	UTMMedium      string     `json:"utm_medium,omitempty" gorm:"column:utm_medium;size:100"`
+	UTMCampaign    string     `json:"utm_campaign,omitempty" gorm:"column:utm_campaign;size:255"`
+	UTMContent     string     `json:"utm_content,omitempty" gorm:"column:utm_content;size:255"`
+	ClickedAt      time.Time  `json:"clicked_at" gorm:"not null"`
+}
+
+// OrderAttribution is the share of an order credited to a campaign under one model
+type OrderAttribution struct {
+	BaseEntity
+	OrderID        uuid.UUID        `json:"order_id" gorm:"type:uuid;not null"`
+	OrganizationID uuid.UUID        `json:"organization_id" gorm:"type:uuid;not null"`
+	Model          AttributionModel `json:"model" gorm:"size:20;not null"`
+	CampaignID     uuid.UUID        `json:"campaign_id" gorm:"type:uuid;not null"`
+	AdID           *uuid.UUID       `json:"ad_id,omitempty" gorm:"type:uuid"`
+	TouchpointID   *uuid.UUID       `json:"touchpoint_id,omitempty" gorm:"type:uuid"`
+	Weight         decimal.Decimal  `json:"weight" gorm:"type:decimal(10,6);not null"`
+	Revenue        decimal.Decimal  `json:"revenue" gorm:"type:decimal(15,4);not null"`
+	Currency       string           `json:"currency" gorm:"size:3;default:'MYR'"`
+	OrderDate      time.Time        `json:"order_date" gorm:"type:date;not null"`
+}
+
+// AttributionTotal is the attributed orders and revenue of a campaign in one currency,
+// with the same share of the orders' shipping, COGS and marketplace fees
+type AttributionTotal struct {
+	CampaignID uuid.UUID       `json:"campaign_id"`
+	Currency   string          `json:"currency"`
+	Orders     decimal.Decimal `json:"orders"`
+	Revenue    decimal.Decimal `json:"revenue"`
+	Shipping   decimal.Decimal `json:"shipping"`
+	COGS       decimal.Decimal `json:"cogs" gorm:"column:cogs"`
+	Fees       decimal.Decimal `json:"fees"`
+}
+
+// CampaignAttribution compares attributed order revenue with what the ad platform reports
+type CampaignAttribution struct {
+	CampaignID              uuid.UUID        `json:"campaign_id"`
+	CampaignName            string           `json:"campaign_name"`
+	Platform                Platform         `json:"platform"`
+	Model                   AttributionModel `json:"model"`
+	Currency                string           `json:"currency"`
+	Spend                   decimal.Decimal  `json:"spend"`
+	PlatformConversionValue decimal.Decimal  `json:"platform_conversion_value"`
+	PlatformROAS            float64          `json:"platform_roas"`
+	AttributedOrders        float64          `json:"attributed_orders"`
+	AttributedRevenue       decimal.Decimal  `json:"attributed_revenue"`
+	AttributedROAS          float64          `json:"attributed_roas"`
+}
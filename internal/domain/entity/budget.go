@@ -0,0 +1,112 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// OrganizationBudget is the monthly ad budget of an organization across all
+// platforms, optionally split into per-platform allocations
+type OrganizationBudget struct {
+	BaseEntity
+	OrganizationID      uuid.UUID                    `json:"organization_id" gorm:"type:uuid;not null;uniqueIndex"`
+	MonthlyAmount       decimal.Decimal              `json:"monthly_amount" gorm:"type:decimal(15,2);not null"`
+	Currency            string                       `json:"currency" gorm:"size:3;not null;default:'MYR'"`
+	PlatformAllocations map[Platform]decimal.Decimal `json:"platform_allocations,omitempty" gorm:"type:jsonb;serializer:json"`
+	IsActive            bool                         `json:"is_active" gorm:"default:true"`
+}
+
+// BudgetAlertThresholds are the percentages of a budget at which an alert is raised
+var BudgetAlertThresholds = []int{50, 80, 100}
+
+// BudgetAlertType represents the severity of a budget alert
+type BudgetAlertType string
+
+const (
+	BudgetAlertNotice   BudgetAlertType = "notice"   // 50% spent
+	BudgetAlertWarning  BudgetAlertType = "warning"  // 80% spent
+	BudgetAlertExceeded BudgetAlertType = "exceeded" // 100% spent
+)
+
+// BudgetAlertTypeFor returns the alert type raised at a threshold
+func BudgetAlertTypeFor(threshold int) BudgetAlertType {
+	switch {
+	case threshold >= 100:
+		return BudgetAlertExceeded
+	case threshold >= 80:
+		return BudgetAlertWarning
+	default:
+		return BudgetAlertNotice
+	}
+}
+
+// BudgetAlert records that month-to-date spend crossed a threshold of the
+// organization budget, or of one platform's allocation when Platform is set.
+// At most one alert is raised per month, platform and threshold.
+type BudgetAlert struct {
+	ID             uuid.UUID       `json:"id" gorm:"type:uuid;primary_key;default:uuid_generate_v4()"`
+	OrganizationID uuid.UUID       `json:"organization_id" gorm:"type:uuid;not null;index"`
+	BudgetID       uuid.UUID       `json:"budget_id" gorm:"type:uuid;not null"`
+	Month          time.Time       `json:"month" gorm:"type:date;not null"` // First day of the month
+	Platform       Platform        `json:"platform,omitempty" gorm:"size:20;not null;default:''"`
+	AlertType      BudgetAlertType `json:"alert_type" gorm:"size:20;not null"`
+	Threshold      int             `json:"threshold"`
+	Spend          decimal.Decimal `json:"spend" gorm:"type:decimal(15,2)"`
+	Budget         decimal.Decimal `json:"budget" gorm:"type:decimal(15,2)"`
+	Currency       string          `json:"currency" gorm:"size:3"`
+	UsagePercent   float64         `json:"usage_percent"`
+
+	// Notification
+	NotifiedAt     *time.Time `json:"notified_at,omitempty"`
+	AcknowledgedAt *time.Time `json:"acknowledged_at,omitempty"`
+	AcknowledgedBy *uuid.UUID `json:"acknowledged_by,omitempty" gorm:"type:uuid"`
+
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// BudgetUsage compares month-to-date spend with a budget or platform allocation
+type BudgetUsage struct {
+	Platform      Platform        `json:"platform,omitempty"`
+	Budget        decimal.Decimal `json:"budget"`
+	Spend         decimal.Decimal `json:"spend"`
+	Remaining     decimal.Decimal `json:"remaining"` // Negative once the budget is overspent
+	UsagePercent  float64         `json:"usage_percent"`
+	ThresholdHit  int             `json:"threshold_hit"` // Highest alert threshold reached, 0 if none
+	IsOverBudget  bool            `json:"is_over_budget"`
+	IsUnallocated bool            `json:"is_unallocated,omitempty"` // Spend on a platform without an allocation
+}
+
+// NewBudgetUsage calculates usage of budget given spend
+func NewBudgetUsage(platform Platform, budget, spend decimal.Decimal) BudgetUsage {
+	usage := BudgetUsage{
+		Platform:  platform,
+		Budget:    budget,
+		Spend:     spend,
+		Remaining: budget.Sub(spend),
+	}
+	if budget.IsPositive() {
+		usage.UsagePercent, _ = spend.Div(budget).Mul(decimal.NewFromInt(100)).Float64()
+		for _, threshold := range BudgetAlertThresholds {
+			if usage.UsagePercent >= float64(threshold) {
+				usage.ThresholdHit = threshold
+			}
+		}
+	}
+	usage.IsOverBudget = budget.IsPositive() && spend.GreaterThan(budget)
+	return usage
+}
+
+// BudgetStatus is the month-to-date state of an organization budget
+type BudgetStatus struct {
+	BudgetID    uuid.UUID     `json:"budget_id"`
+	Currency    string        `json:"currency"`
+	Month       time.Time     `json:"month"`
+	AsOf        time.Time     `json:"as_of"`
+	DaysElapsed int           `json:"days_elapsed"`
+	DaysInMonth int           `json:"days_in_month"`
+	Total       BudgetUsage   `json:"total"`
+	Platforms   []BudgetUsage `json:"platforms"`
+	Alerts      []BudgetAlert `json:"alerts"`
+}
@@ -0,0 +1,16 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// ExchangeRate is the number of QuoteCurrency units one BaseCurrency unit bought on RateDate
+type ExchangeRate struct {
+	BaseEntity
+	BaseCurrency  string          `json:"base_currency" gorm:"size:3;not null"`
+	QuoteCurrency string          `json:"quote_currency" gorm:"size:3;not null"`
+	RateDate      time.Time       `json:"rate_date" gorm:"type:date;not null"`
+	Rate          decimal.Decimal `json:"rate" gorm:"type:decimal(20,10);not null"`
+}
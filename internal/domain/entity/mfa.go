@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OrgSettingRequireMFA is the organization setting that makes a second factor
+// mandatory for owners and admins
+const OrgSettingRequireMFA = "require_mfa"
+
+// MFAMethodTOTP is the time-based one-time password second factor
+const MFAMethodTOTP = "totp"
+
+// UserMFA holds a user's TOTP second factor. The factor is pending until the
+// user proves their authenticator app works by entering a first code.
+type UserMFA struct {
+	BaseEntity
+	UserID                   uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;uniqueIndex"`
+	Secret                   string     `json:"-" gorm:"size:255;not null"` // Base32 TOTP secret, encrypted when an encryption key is configured
+	EnabledAt                *time.Time `json:"enabled_at,omitempty"`
+	LastUsedStep             int64      `json:"-" gorm:"default:0"`                  // Time step of the last accepted code, to reject replays
+	RecoveryCodes            []string   `json:"-" gorm:"type:jsonb;serializer:json"` // bcrypt hashes of the unused recovery codes
+	RecoveryCodesGeneratedAt *time.Time `json:"recovery_codes_generated_at,omitempty"`
+	FailedAttempts           int        `json:"-" gorm:"default:0"` // Consecutive wrong codes
+	LockedUntil              *time.Time `json:"locked_until,omitempty"`
+}
+
+// TableName returns the table name
+func (UserMFA) TableName() string {
+	return "user_mfa"
+}
+
+// IsEnabled reports whether the second factor is required at login
+func (m *UserMFA) IsEnabled() bool {
+	return m != nil && m.EnabledAt != nil
+}
+
+// IsLocked reports whether too many wrong codes were entered to accept another at the given time
+func (m *UserMFA) IsLocked(now time.Time) bool {
+	return m.LockedUntil != nil && now.Before(*m.LockedUntil)
+}
+
+// RequiresMFA reports whether the organization requires a second factor for
+// members with the given role. Only owners and admins can be required to use one.
+func (o *Organization) RequiresMFA(role UserRole) bool {
+	if role != RoleOwner && role != RoleAdmin {
+		return false
+	}
+	required, _ := o.Settings[OrgSettingRequireMFA].(bool)
+	return required
+}
@@ -0,0 +1,162 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// OrderStatus represents the normalized status of a marketplace order
+type OrderStatus string
+
+const (
+	OrderStatusPending         OrderStatus = "pending"
+	OrderStatusConfirmed       OrderStatus = "confirmed"
+	OrderStatusShipped         OrderStatus = "shipped"
+	OrderStatusDelivered       OrderStatus = "delivered"
+	OrderStatusCancelled       OrderStatus = "cancelled"
+	OrderStatusRefunded        OrderStatus = "refunded"
+	OrderStatusReturnRequested OrderStatus = "return_requested"
+	OrderStatusReturned        OrderStatus = "returned"
+)
+
+// IsRevenue reports whether the order counts towards attributed revenue
+func (s OrderStatus) IsRevenue() bool {
+	switch s {
+	case OrderStatusConfirmed, OrderStatusShipped, OrderStatusDelivered:
+		return true
+	default:
+		return false
+	}
+}
+
+// Order represents an e-commerce order from a marketplace (Shopee, Lazada) used for ROAS calculation
+type Order struct {
+	BaseEntity
+	ShopeeShopID    *uuid.UUID  `json:"shopee_shop_id,omitempty" gorm:"type:uuid"`
+	OrganizationID  uuid.UUID   `json:"organization_id" gorm:"type:uuid;not null"`
+	Platform        Platform    `json:"platform" gorm:"type:platform_type;not null"`
+	PlatformOrderID string      `json:"platform_order_id" gorm:"size:100;not null"`
+	PlatformOrderSN string      `json:"platform_order_sn,omitempty" gorm:"size:100"`
+	Status          OrderStatus `json:"status" gorm:"type:order_status;default:'pending'"`
+
+	// Customer info (anonymized/hashed for privacy)
+	CustomerID   string `json:"customer_id,omitempty" gorm:"size:255"`
+	CustomerName string `json:"customer_name,omitempty" gorm:"size:255"`
+
+	// Pricing
+	Currency         string          `json:"currency" gorm:"size:3;default:'MYR'"`
+	Subtotal         decimal.Decimal `json:"subtotal" gorm:"type:decimal(15,4);default:0"`
+	ShippingFee      decimal.Decimal `json:"shipping_fee" gorm:"type:decimal(15,4);default:0"`
+	DiscountAmount   decimal.Decimal `json:"discount_amount" gorm:"type:decimal(15,4);default:0"`
+	VoucherAmount    decimal.Decimal `json:"voucher_amount" gorm:"type:decimal(15,4);default:0"`
+	SellerDiscount   decimal.Decimal `json:"seller_discount" gorm:"type:decimal(15,4);default:0"`
+	PlatformDiscount decimal.Decimal `json:"platform_discount" gorm:"type:decimal(15,4);default:0"`
+	TotalAmount      decimal.Decimal `json:"total_amount" gorm:"type:decimal(15,4);default:0"`
+
+	// Commission & Fees
+	CommissionFee  decimal.Decimal `json:"commission_fee" gorm:"type:decimal(15,4);default:0"`
+	ServiceFee     decimal.Decimal `json:"service_fee" gorm:"type:decimal(15,4);default:0"`
+	TransactionFee decimal.Decimal `json:"transaction_fee" gorm:"type:decimal(15,4);default:0"`
+
+	// Profit calculation
+	EstimatedProfit *decimal.Decimal `json:"estimated_profit,omitempty" gorm:"type:decimal(15,4)"`
+
+	// Tracking
+	TrackingNumber  string `json:"tracking_number,omitempty" gorm:"size:100"`
+	ShippingCarrier string `json:"shipping_carrier,omitempty" gorm:"size:100"`
+
+	// Attribution (link to ad campaign if trackable)
+	AttributedCampaignID *uuid.UUID `json:"attributed_campaign_id,omitempty" gorm:"type:uuid"`
+	AttributedAdID       *uuid.UUID `json:"attributed_ad_id,omitempty" gorm:"type:uuid"`
+	UTMSource            string     `json:"utm_source,omitempty" gorm:"column:utm_source;size:100"`
+	UTMMedium            string     `json:"utm_medium,omitempty" gorm:"column:utm_medium;size:100"`
+	UTMCampaign          string     `json:"utm_campaign,omitempty" gorm:"column:utm_campaign;size:255"`
+	UTMContent           string     `json:"utm_content,omitempty" gorm:"column:utm_content;size:255"`
+
+	// Timestamps
+	OrderDate      time.Time  `json:"order_date" gorm:"type:date;not null"`
+	OrderCreatedAt time.Time  `json:"order_created_at" gorm:"not null"`
+	PaidAt         *time.Time `json:"paid_at,omitempty"`
+	ShippedAt      *time.Time `json:"shipped_at,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	CancelledAt    *time.Time `json:"cancelled_at,omitempty"`
+
+	// Platform-specific data
+	PlatformData JSONMap    `json:"platform_data,omitempty" gorm:"type:jsonb;default:'{}'"`
+	LastSyncedAt *time.Time `json:"last_synced_at,omitempty"`
+
+	// Relations
+	Items []OrderItem `json:"items,omitempty" gorm:"foreignKey:OrderID"`
+}
+
+// OrderItem represents an individual line item within an order
+type OrderItem struct {
+	BaseEntity
+	OrderID        uuid.UUID `json:"order_id" gorm:"type:uuid;not null"`
+	OrganizationID uuid.UUID `json:"organization_id" gorm:"type:uuid;not null"`
+
+	// Product info
+	PlatformItemID    string `json:"platform_item_id,omitempty" gorm:"size:100"`
+	PlatformProductID string `json:"platform_product_id,omitempty" gorm:"size:100"`
+	SKU               string `json:"sku,omitempty" gorm:"column:sku;size:100"`
+	ProductName       string `json:"product_name,omitempty" gorm:"size:500"`
+	VariationName     string `json:"variation_name,omitempty" gorm:"size:255"`
+
+	// Pricing
+	Quantity       int             `json:"quantity" gorm:"not null;default:1"`
+	UnitPrice      decimal.Decimal `json:"unit_price" gorm:"type:decimal(15,4);not null"`
+	DiscountAmount decimal.Decimal `json:"discount_amount" gorm:"type:decimal(15,4);default:0"`
+	TotalPrice     decimal.Decimal `json:"total_price" gorm:"type:decimal(15,4);not null"`
+
+	// Cost (for profit calculation)
+	UnitCost *decimal.Decimal `json:"unit_cost,omitempty" gorm:"type:decimal(15,4)"`
+
+	Status       OrderStatus `json:"status,omitempty" gorm:"type:order_status"`
+	PlatformData JSONMap     `json:"platform_data,omitempty" gorm:"type:jsonb;default:'{}'"`
+}
+
+// OrderFilter represents filters for querying orders
+type OrderFilter struct {
+	OrganizationID uuid.UUID     `json:"organization_id"`
+	Platforms      []Platform    `json:"platforms,omitempty"`
+	Statuses       []OrderStatus `json:"statuses,omitempty"`
+	DateRange      *DateRange    `json:"date_range,omitempty"`
+	Pagination     *Pagination   `json:"pagination,omitempty"`
+}
+
+// SalesSummaryDaily is the daily rollup of a shop's orders, used to compare GMV with ad spend
+type SalesSummaryDaily struct {
+	BaseEntity
+	OrganizationID uuid.UUID  `json:"organization_id" gorm:"type:uuid;not null"`
+	ShopeeShopID   *uuid.UUID `json:"shopee_shop_id,omitempty" gorm:"type:uuid"`
+	Platform       Platform   `json:"platform" gorm:"type:platform_type;not null"`
+	SummaryDate    time.Time  `json:"summary_date" gorm:"type:date;not null"`
+
+	// Order counts
+	TotalOrders     int `json:"total_orders" gorm:"default:0"`
+	ConfirmedOrders int `json:"confirmed_orders" gorm:"default:0"`
+	CancelledOrders int `json:"cancelled_orders" gorm:"default:0"`
+
+	// Revenue
+	GrossRevenue      decimal.Decimal `json:"gross_revenue" gorm:"type:decimal(15,4);default:0"`
+	NetRevenue        decimal.Decimal `json:"net_revenue" gorm:"type:decimal(15,4);default:0"`
+	TotalDiscounts    decimal.Decimal `json:"total_discounts" gorm:"type:decimal(15,4);default:0"`
+	ShippingCollected decimal.Decimal `json:"shipping_collected" gorm:"type:decimal(15,4);default:0"`
+
+	// Costs
+	TotalCOGS      decimal.Decimal `json:"total_cogs" gorm:"column:total_cogs;type:decimal(15,4);default:0"`
+	CommissionFees decimal.Decimal `json:"commission_fees" gorm:"type:decimal(15,4);default:0"`
+	ServiceFees    decimal.Decimal `json:"service_fees" gorm:"type:decimal(15,4);default:0"`
+
+	EstimatedProfit decimal.Decimal `json:"estimated_profit" gorm:"type:decimal(15,4);default:0"`
+	TotalItemsSold  int             `json:"total_items_sold" gorm:"default:0"`
+	Currency        string          `json:"currency" gorm:"size:3;default:'MYR'"`
+	LastSyncedAt    *time.Time      `json:"last_synced_at,omitempty"`
+}
+
+// TableName returns the table name
+func (SalesSummaryDaily) TableName() string {
+	return "sales_summary_daily"
+}
@@ -0,0 +1,97 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// PacingPeriod is the budget period spend is paced against
+type PacingPeriod string
+
+const (
+	PacingPeriodMonthToDate PacingPeriod = "month_to_date"
+	PacingPeriodLifetime    PacingPeriod = "lifetime"
+)
+
+// PacingStatus summarizes whether projected spend will land on budget
+type PacingStatus string
+
+const (
+	PacingStatusOnTrack       PacingStatus = "on_track"
+	PacingStatusUnderspending PacingStatus = "underspending"
+	PacingStatusOverspending  PacingStatus = "overspending"
+	PacingStatusNotStarted    PacingStatus = "not_started" // No completed day in the period yet
+	PacingStatusNoBudget      PacingStatus = "no_budget"
+)
+
+const (
+	// PacingTolerance is how far projected spend may drift from budget, as a
+	// fraction of the budget, before a campaign is flagged
+	PacingTolerance = 0.10
+
+	// PacingRunRateDays is how many recent completed days the run rate averages
+	PacingRunRateDays = 7
+)
+
+// IsFlagged reports whether the status is a likely under- or overspend
+func (s PacingStatus) IsFlagged() bool {
+	return s == PacingStatusUnderspending || s == PacingStatusOverspending
+}
+
+// BudgetPacing compares spend with budget over one period. Only completed days
+// count; today's partial spend is left out of spend and the run rate.
+type BudgetPacing struct {
+	Period         PacingPeriod    `json:"period"`
+	StartDate      time.Time       `json:"start_date"`
+	EndDate        time.Time       `json:"end_date"`
+	Budget         decimal.Decimal `json:"budget"`
+	Spend          decimal.Decimal `json:"spend"`
+	ExpectedSpend  decimal.Decimal `json:"expected_spend"` // Budget spread evenly over the elapsed days
+	PacingPct      float64         `json:"pacing_pct"`     // Spend / expected spend * 100
+	RunRate        decimal.Decimal `json:"run_rate"`       // Average daily spend over recent days
+	ProjectedSpend decimal.Decimal `json:"projected_spend"`
+	ProjectedPct   float64         `json:"projected_pct"` // Projected spend / budget * 100
+	DaysElapsed    int             `json:"days_elapsed"`
+	DaysRemaining  int             `json:"days_remaining"`
+	Status         PacingStatus    `json:"status"`
+}
+
+// AdSetPacing is the pacing of an ad set with its own budget
+type AdSetPacing struct {
+	AdSetID     uuid.UUID     `json:"ad_set_id"`
+	AdSetName   string        `json:"ad_set_name"`
+	MonthToDate *BudgetPacing `json:"month_to_date,omitempty"`
+	Lifetime    *BudgetPacing `json:"lifetime,omitempty"`
+	Status      PacingStatus  `json:"status"`
+}
+
+// CampaignPacing is the month-to-date and lifetime pacing of a campaign.
+// Campaigns without a budget of their own are paced against their ad sets' budgets.
+type CampaignPacing struct {
+	CampaignID     uuid.UUID        `json:"campaign_id"`
+	CampaignName   string           `json:"campaign_name"`
+	Platform       Platform         `json:"platform"`
+	CampaignStatus CampaignStatus   `json:"campaign_status"`
+	Currency       string           `json:"currency"`
+	DailyBudget    *decimal.Decimal `json:"daily_budget,omitempty"`
+	LifetimeBudget *decimal.Decimal `json:"lifetime_budget,omitempty"`
+	BudgetSource   string           `json:"budget_source"` // "campaign", "ad_sets" or "none"
+	MonthToDate    *BudgetPacing    `json:"month_to_date,omitempty"`
+	Lifetime       *BudgetPacing    `json:"lifetime,omitempty"`
+	AdSets         []AdSetPacing    `json:"ad_sets,omitempty"`
+	Status         PacingStatus     `json:"status"` // The most severe status of the periods
+}
+
+// PacingReport is the month-to-date pacing of an organization's active campaigns
+type PacingReport struct {
+	Currency       string           `json:"currency"`
+	AsOf           time.Time        `json:"as_of"`
+	MonthBudget    decimal.Decimal  `json:"month_budget"`
+	MonthSpend     decimal.Decimal  `json:"month_spend"`
+	ProjectedSpend decimal.Decimal  `json:"projected_spend"`
+	Overspending   int              `json:"overspending"`
+	Underspending  int              `json:"underspending"`
+	Campaigns      []CampaignPacing `json:"campaigns"`
+}
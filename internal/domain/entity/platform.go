@@ -13,6 +13,8 @@ const (
 	PlatformMeta   Platform = "meta"
 	PlatformTikTok Platform = "tiktok"
 	PlatformShopee Platform = "shopee"
+	PlatformGoogle Platform = "google"
+	PlatformLazada Platform = "lazada"
 )
 
 // String returns the string representation of the platform
@@ -23,7 +25,7 @@ func (p Platform) String() string {
 // IsValid checks if the platform is valid
 func (p Platform) IsValid() bool {
 	switch p {
-	case PlatformMeta, PlatformTikTok, PlatformShopee:
+	case PlatformMeta, PlatformTikTok, PlatformShopee, PlatformGoogle, PlatformLazada:
 		return true
 	default:
 		return false
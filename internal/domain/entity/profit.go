@@ -0,0 +1,66 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// SKUCost is the uploaded unit cost of a SKU, used for order items the
+// marketplace did not supply a cost for
+type SKUCost struct {
+	BaseEntity
+	OrganizationID uuid.UUID       `json:"organization_id" gorm:"type:uuid;not null"`
+	SKU            string          `json:"sku" gorm:"column:sku;size:100;not null"`
+	UnitCost       decimal.Decimal `json:"unit_cost" gorm:"type:decimal(15,4);not null"`
+	Currency       string          `json:"currency" gorm:"size:3;default:'MYR'"`
+}
+
+// ProfitSummary is the profitability of a day, platform, campaign or a whole date range.
+// GrossProfit is revenue less shipping, COGS and marketplace fees; NetProfit also
+// deducts ad spend.
+type ProfitSummary struct {
+	Date         *time.Time `json:"date,omitempty"`
+	Platform     Platform   `json:"platform,omitempty"`
+	CampaignID   *uuid.UUID `json:"campaign_id,omitempty"`
+	CampaignName string     `json:"campaign_name,omitempty"`
+
+	Orders          float64         `json:"orders"` // Fractional for campaigns under multi-touch models
+	Revenue         decimal.Decimal `json:"revenue"`
+	Shipping        decimal.Decimal `json:"shipping"`
+	COGS            decimal.Decimal `json:"cogs"`
+	MarketplaceFees decimal.Decimal `json:"marketplace_fees"`
+	GrossProfit     decimal.Decimal `json:"gross_profit"`
+	AdSpend         decimal.Decimal `json:"ad_spend"`
+	NetProfit       decimal.Decimal `json:"net_profit"`
+
+	POAS                  float64 `json:"poas"`                    // Gross profit / ad spend
+	ContributionMarginPct float64 `json:"contribution_margin_pct"` // Net profit / revenue * 100
+}
+
+// Finalize derives gross profit, net profit, POAS and margin from the revenue and cost totals
+func (p *ProfitSummary) Finalize() {
+	p.GrossProfit = p.Revenue.Sub(p.Shipping).Sub(p.COGS).Sub(p.MarketplaceFees)
+	p.NetProfit = p.GrossProfit.Sub(p.AdSpend)
+
+	p.POAS = 0
+	if p.AdSpend.IsPositive() {
+		p.POAS, _ = p.GrossProfit.Div(p.AdSpend).Float64()
+	}
+	p.ContributionMarginPct = 0
+	if p.Revenue.IsPositive() {
+		p.ContributionMarginPct, _ = p.NetProfit.Div(p.Revenue).Mul(decimal.NewFromInt(100)).Float64()
+	}
+}
+
+// ProfitabilityReport breaks down profit after ad spend by day, platform and campaign
+type ProfitabilityReport struct {
+	Currency   string           `json:"currency"`
+	DateRange  DateRange        `json:"date_range"`
+	Model      AttributionModel `json:"model"` // Used to credit order profit to campaigns
+	Total      ProfitSummary    `json:"total"`
+	Daily      []ProfitSummary  `json:"daily"`
+	ByPlatform []ProfitSummary  `json:"by_platform"`
+	ByCampaign []ProfitSummary  `json:"by_campaign"`
+}
@@ -0,0 +1,283 @@
+package entity
+
+import (
+	"fmt"
+	"math/bits"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+)
+
+// ReportFormat represents the output format of a generated report
+type ReportFormat string
+
+const (
+	ReportFormatJSON ReportFormat = "json"
+	ReportFormatCSV  ReportFormat = "csv"
+	ReportFormatXLSX ReportFormat = "xlsx"
+	ReportFormatPDF  ReportFormat = "pdf"
+)
+
+// IsValid checks if the report format is supported
+func (f ReportFormat) IsValid() bool {
+	switch f {
+	case ReportFormatJSON, ReportFormatCSV, ReportFormatXLSX, ReportFormatPDF:
+		return true
+	default:
+		return false
+	}
+}
+
+// ContentType returns the MIME type of the report artifact
+func (f ReportFormat) ContentType() string {
+	switch f {
+	case ReportFormatCSV:
+		return "text/csv"
+	case ReportFormatXLSX:
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	case ReportFormatPDF:
+		return "application/pdf"
+	default:
+		return "application/json"
+	}
+}
+
+// ReportJobStatus represents the lifecycle state of a report job
+type ReportJobStatus string
+
+const (
+	ReportJobStatusPending   ReportJobStatus = "pending"
+	ReportJobStatusRunning   ReportJobStatus = "running"
+	ReportJobStatusCompleted ReportJobStatus = "completed"
+	ReportJobStatusFailed    ReportJobStatus = "failed"
+	ReportJobStatusExpired   ReportJobStatus = "expired"
+)
+
+// ============================================================================
+// Report Job Entity
+// ============================================================================
+
+// ReportJob represents an asynchronous report generation request
+type ReportJob struct {
+	BaseEntity
+	OrganizationID uuid.UUID       `json:"organization_id" gorm:"type:uuid;not null;index"`
+	RequestedBy    *uuid.UUID      `json:"requested_by,omitempty" gorm:"type:uuid"`
+	Format         ReportFormat    `json:"format" gorm:"size:10;not null"`
+	Status         ReportJobStatus `json:"status" gorm:"size:20;not null;default:'pending';index"`
+
+	// Report parameters
+	DateRangeStart time.Time `json:"date_range_start" gorm:"type:date;not null"`
+	DateRangeEnd   time.Time `json:"date_range_end" gorm:"type:date;not null"`
+	WhiteLabel     bool      `json:"white_label" gorm:"default:false"`
+
+	// Execution tracking
+	WorkerID    string     `json:"-" gorm:"size:100"`
+	Attempts    int        `json:"attempts" gorm:"default:0"`
+	MaxAttempts int        `json:"max_attempts" gorm:"default:3"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+
+	// Artifact details (content is stored separately in ReportArtifact)
+	FileName    string     `json:"file_name,omitempty" gorm:"size:255"`
+	ContentType string     `json:"content_type,omitempty" gorm:"size:100"`
+	SizeBytes   int64      `json:"size_bytes,omitempty" gorm:"default:0"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty" gorm:"index"`
+
+	ErrorMessage string `json:"error_message,omitempty"`
+}
+
+// ReportJobAbandonedMessage is recorded on jobs failed because their worker
+// stopped before finishing on every attempt
+const ReportJobAbandonedMessage = "Report worker stopped before finishing on every attempt"
+
+// DateRange returns the report period
+func (j *ReportJob) DateRange() DateRange {
+	return DateRange{StartDate: j.DateRangeStart, EndDate: j.DateRangeEnd}
+}
+
+// IsExpired checks if the generated artifact has passed its retention period
+func (j *ReportJob) IsExpired() bool {
+	if j.Status == ReportJobStatusExpired {
+		return true
+	}
+	return j.ExpiresAt != nil && time.Now().After(*j.ExpiresAt)
+}
+
+// IsDownloadable checks if the artifact is ready for download
+func (j *ReportJob) IsDownloadable() bool {
+	return j.Status == ReportJobStatusCompleted && !j.IsExpired()
+}
+
+// CanRetry checks if a failed attempt may be retried
+func (j *ReportJob) CanRetry() bool {
+	return j.Attempts < j.MaxAttempts
+}
+
+// MarkCompleted marks the job as completed with the stored artifact details
+func (j *ReportJob) MarkCompleted(fileName, contentType string, size int64, expiresAt time.Time) {
+	now := time.Now()
+	j.Status = ReportJobStatusCompleted
+	j.CompletedAt = &now
+	j.FileName = fileName
+	j.ContentType = contentType
+	j.SizeBytes = size
+	j.ExpiresAt = &expiresAt
+	j.ErrorMessage = ""
+}
+
+// MarkFailed records a failed attempt. The job returns to pending while attempts remain.
+func (j *ReportJob) MarkFailed(err error) {
+	j.ErrorMessage = err.Error()
+	j.WorkerID = ""
+	if j.CanRetry() {
+		j.Status = ReportJobStatusPending
+		return
+	}
+
+	now := time.Now()
+	j.Status = ReportJobStatusFailed
+	j.CompletedAt = &now
+}
+
+// ReportArtifact holds the generated report file for a job
+type ReportArtifact struct {
+	ReportJobID uuid.UUID `json:"report_job_id" gorm:"type:uuid;primaryKey"`
+	Content     []byte    `json:"-" gorm:"type:bytea;not null"`
+	ExpiresAt   time.Time `json:"expires_at" gorm:"not null;index"`
+	CreatedAt   time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// ============================================================================
+// Report Schedule Entity
+// ============================================================================
+
+// ReportDatePreset is a date range relative to the time a scheduled report runs
+type ReportDatePreset string
+
+const (
+	ReportDatePresetYesterday   ReportDatePreset = "yesterday"
+	ReportDatePresetLast7Days   ReportDatePreset = "last_7_days"
+	ReportDatePresetLast14Days  ReportDatePreset = "last_14_days"
+	ReportDatePresetLast30Days  ReportDatePreset = "last_30_days"
+	ReportDatePresetLastWeek    ReportDatePreset = "last_week"
+	ReportDatePresetLastMonth   ReportDatePreset = "last_month"
+	ReportDatePresetMonthToDate ReportDatePreset = "month_to_date"
+)
+
+// IsValid checks if the date preset is supported
+func (p ReportDatePreset) IsValid() bool {
+	switch p {
+	case ReportDatePresetYesterday, ReportDatePresetLast7Days, ReportDatePresetLast14Days,
+		ReportDatePresetLast30Days, ReportDatePresetLastWeek, ReportDatePresetLastMonth,
+		ReportDatePresetMonthToDate:
+		return true
+	default:
+		return false
+	}
+}
+
+// Resolve returns the date range for a report run at now. Ranges end yesterday so
+// that a report never includes a partially synced day, except month_to_date on the
+// first of the month which covers the previous day only.
+func (p ReportDatePreset) Resolve(now time.Time) DateRange {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	yesterday := today.AddDate(0, 0, -1)
+
+	switch p {
+	case ReportDatePresetYesterday:
+		return DateRange{StartDate: yesterday, EndDate: yesterday}
+	case ReportDatePresetLast14Days:
+		return DateRange{StartDate: today.AddDate(0, 0, -14), EndDate: yesterday}
+	case ReportDatePresetLast30Days:
+		return DateRange{StartDate: today.AddDate(0, 0, -30), EndDate: yesterday}
+	case ReportDatePresetLastWeek:
+		// Previous Monday to Sunday
+		offset := (int(today.Weekday()) + 6) % 7
+		monday := today.AddDate(0, 0, -offset-7)
+		return DateRange{StartDate: monday, EndDate: monday.AddDate(0, 0, 6)}
+	case ReportDatePresetLastMonth:
+		firstOfMonth := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, today.Location())
+		return DateRange{StartDate: firstOfMonth.AddDate(0, -1, 0), EndDate: firstOfMonth.AddDate(0, 0, -1)}
+	case ReportDatePresetMonthToDate:
+		firstOfMonth := time.Date(yesterday.Year(), yesterday.Month(), 1, 0, 0, 0, 0, today.Location())
+		return DateRange{StartDate: firstOfMonth, EndDate: yesterday}
+	default:
+		return DateRange{StartDate: today.AddDate(0, 0, -7), EndDate: yesterday}
+	}
+}
+
+// Report schedule run statuses
+const (
+	ReportScheduleRunSent   = "sent"
+	ReportScheduleRunFailed = "failed"
+)
+
+// ReportSchedule delivers a report to a list of recipients on a cron schedule
+type ReportSchedule struct {
+	BaseEntity
+	OrganizationID uuid.UUID        `json:"organization_id" gorm:"type:uuid;not null;index"`
+	CreatedBy      *uuid.UUID       `json:"created_by,omitempty" gorm:"type:uuid"`
+	Name           string           `json:"name" gorm:"size:255;not null"`
+	CronExpression string           `json:"cron_expression" gorm:"size:100;not null"`
+	Timezone       string           `json:"timezone" gorm:"size:64;not null;default:'UTC'"`
+	DatePreset     ReportDatePreset `json:"date_preset" gorm:"size:30;not null"`
+	Format         ReportFormat     `json:"format" gorm:"size:10;not null"`
+	Recipients     []string         `json:"recipients" gorm:"type:jsonb;serializer:json"`
+	WhiteLabel     bool             `json:"white_label" gorm:"default:false"`
+	IsActive       bool             `json:"is_active" gorm:"default:true"`
+
+	// Run tracking
+	NextRunAt  *time.Time `json:"next_run_at,omitempty" gorm:"index"`
+	LastRunAt  *time.Time `json:"last_run_at,omitempty"`
+	LastStatus string     `json:"last_status,omitempty" gorm:"size:20"`
+	LastError  string     `json:"last_error,omitempty"`
+}
+
+// Location returns the schedule's time zone, falling back to UTC
+func (s *ReportSchedule) Location() *time.Location {
+	if s.Timezone == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// NextRunAfter returns the first time after t that the schedule fires
+func (s *ReportSchedule) NextRunAfter(t time.Time) (time.Time, error) {
+	schedule, err := cron.ParseStandard(s.CronExpression)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return schedule.Next(t.In(s.Location())), nil
+}
+
+// ValidateFrequency rejects cron expressions that fire more often than hourly.
+// A standard schedule fires at most once an hour when it names a single minute.
+func (s *ReportSchedule) ValidateFrequency() error {
+	schedule, err := cron.ParseStandard(s.CronExpression)
+	if err != nil {
+		return err
+	}
+
+	switch sched := schedule.(type) {
+	case *cron.SpecSchedule:
+		// Bits 0-59 are the minutes; the top bit only marks a "*" field
+		if bits.OnesCount64(sched.Minute&(1<<60-1)) != 1 {
+			return fmt.Errorf("schedule must not run more often than hourly")
+		}
+	case cron.ConstantDelaySchedule:
+		if sched.Delay < time.Hour {
+			return fmt.Errorf("schedule must not run more often than hourly")
+		}
+	}
+	return nil
+}
+
+// ReportDateRange returns the report period for a run at the given time
+func (s *ReportSchedule) ReportDateRange(runAt time.Time) DateRange {
+	return s.DatePreset.Resolve(runAt.In(s.Location()))
+}
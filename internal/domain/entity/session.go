@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Reasons a session was revoked
+const (
+	SessionRevokedLogout         = "logout"
+	SessionRevokedByUser         = "revoked"
+	SessionRevokedReuse          = "reuse_detected"
+	SessionRevokedPasswordChange = "password_change"
+	SessionRevokedUserDisabled   = "user_disabled"
+)
+
+// UserSession is a login on one device: a family of refresh tokens, each
+// issued by rotating the previous one. Only the latest token of the family can
+// be exchanged; presenting an older one means it was copied, and the whole
+// family is revoked. Tokens carry the session ID as their sid claim.
+type UserSession struct {
+	BaseEntity
+	UserID          uuid.UUID  `json:"user_id" gorm:"type:uuid;not null;index"`
+	OrganizationID  uuid.UUID  `json:"organization_id" gorm:"type:uuid;not null"`
+	CurrentTokenID  string     `json:"-" gorm:"size:64;not null;uniqueIndex"` // jti of the latest refresh token
+	PreviousTokenID string     `json:"-" gorm:"size:64"`                      // jti of the token it replaced
+	UserAgent       string     `json:"user_agent,omitempty" gorm:"size:500"`
+	IPAddress       string     `json:"ip_address,omitempty" gorm:"size:45"`
+	LastUsedAt      time.Time  `json:"last_used_at"`
+	RotatedAt       *time.Time `json:"rotated_at,omitempty"`
+	ExpiresAt       time.Time  `json:"expires_at"`
+	RevokedAt       *time.Time `json:"revoked_at,omitempty"`
+	RevokedReason   string     `json:"revoked_reason,omitempty" gorm:"size:50"`
+}
+
+// TableName returns the table name
+func (UserSession) TableName() string {
+	return "user_sessions"
+}
+
+// IsActive reports whether the session's refresh token can be exchanged at the given time
+func (s *UserSession) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -21,6 +21,7 @@ const (
 	SyncStatusCancelled  SyncStatus = "cancelled"
 	SyncStatusRetrying   SyncStatus = "retrying"
 	SyncStatusRateLimited SyncStatus = "rate_limited"
+	SyncStatusPaused     SyncStatus = "paused" // Stopped by a user, resumes from its checkpoint
 )
 
 // SyncType represents the type of sync operation
@@ -43,6 +44,16 @@ const (
 	SyncScopeCampaign  SyncScope = "campaign"  // Sync specific campaign
 	SyncScopeMetrics   SyncScope = "metrics"   // Sync metrics only
 	SyncScopeStructure SyncScope = "structure" // Sync campaign structure only
+	SyncScopeOrders    SyncScope = "orders"    // Sync marketplace orders and daily sales
+)
+
+// Sync job priorities. Workers claim higher priorities first.
+const (
+	SyncJobPriorityBackfill = -1
+	SyncJobPriorityDaily   = 0
+	SyncJobPriorityHourly  = 1
+	SyncJobPriorityWebhook = 5
+	SyncJobPriorityManual  = 10
 )
 
 // ============================================================================
@@ -88,7 +99,7 @@ type SyncState struct {
 	LastErrorAt    *time.Time `json:"last_error_at,omitempty"`
 
 	// Metadata
-	Metadata JSONMap `json:"metadata,omitempty" gorm:"type:jsonb;default:'{}'"`
+	Metadata JSONMap `json:"metadata,omitempty" gorm:"type:jsonb;default:'{}';serializer:json"`
 }
 
 // CalculateDataFreshness calculates minutes since last successful sync
@@ -176,6 +187,11 @@ type SyncJob struct {
 	CompletedAt     *time.Time `json:"completed_at,omitempty"`
 	DurationSeconds *int       `json:"duration_seconds,omitempty"`
 
+	// Lease held by the worker running the job, renewed by heartbeats
+	WorkerID       string     `json:"worker_id,omitempty" gorm:"size:100"`
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty" gorm:"index"`
+	HeartbeatAt    *time.Time `json:"heartbeat_at,omitempty"`
+
 	// Retry management
 	MaxRetries     int        `json:"max_retries" gorm:"default:3"`
 	RetryCount     int        `json:"retry_count" gorm:"default:0"`
@@ -186,6 +202,9 @@ type SyncJob struct {
 	ProgressPercent int    `json:"progress_percent" gorm:"default:0"`
 	ProgressMessage string `json:"progress_message,omitempty"`
 
+	// Work already saved, so a resumed or retried run skips it
+	Checkpoint JSONMap `json:"checkpoint,omitempty" gorm:"type:jsonb;default:'{}';serializer:json"`
+
 	// Results
 	RecordsProcessed int    `json:"records_processed" gorm:"default:0"`
 	RecordsFailed    int    `json:"records_failed" gorm:"default:0"`
@@ -197,7 +216,7 @@ type SyncJob struct {
 	TriggeredByUser  *uuid.UUID `json:"triggered_by_user,omitempty" gorm:"type:uuid"`
 
 	// Metadata
-	Metadata JSONMap `json:"metadata,omitempty" gorm:"type:jsonb;default:'{}'"`
+	Metadata JSONMap `json:"metadata,omitempty" gorm:"type:jsonb;default:'{}';serializer:json"`
 }
 
 // CanRetry checks if job can be retried
@@ -412,6 +431,11 @@ type SyncScheduleConfig struct {
 	MaxRequestsPerMinute int `json:"max_requests_per_minute"`
 	RequestBurstSize     int `json:"request_burst_size"`
 
+	// Historical backfill settings
+	BackfillMaxMonths         int `json:"backfill_max_months"`          // How far back the platform keeps metrics
+	BackfillChunkDays         int `json:"backfill_chunk_days"`          // Days fetched per request, each checkpointed
+	BackfillRequestsPerMinute int `json:"backfill_requests_per_minute"` // Share of the rate limit backfills may use
+
 	// Retry settings
 	MaxRetries          int `json:"max_retries"`
 	RetryBackoffSeconds int `json:"retry_backoff_seconds"`
@@ -430,6 +454,9 @@ func DefaultSyncScheduleConfigs() map[Platform]SyncScheduleConfig {
 			DailySyncLookbackDays: 7,
 			MaxRequestsPerMinute:  200, // Meta allows ~200/min for insights
 			RequestBurstSize:      50,
+			BackfillMaxMonths:     37, // Meta keeps insights for 37 months
+			BackfillChunkDays:     30,
+			BackfillRequestsPerMinute: 100,
 			MaxRetries:            3,
 			RetryBackoffSeconds:   60,
 			MaxRetryBackoff:       900, // 15 min max
@@ -443,6 +470,9 @@ func DefaultSyncScheduleConfigs() map[Platform]SyncScheduleConfig {
 			DailySyncLookbackDays: 7,
 			MaxRequestsPerMinute:  10, // TikTok has strict limits
 			RequestBurstSize:      5,
+			BackfillMaxMonths:     12,
+			BackfillChunkDays:     30,
+			BackfillRequestsPerMinute: 5,
 			MaxRetries:            3,
 			RetryBackoffSeconds:   120, // TikTok needs longer backoff
 			MaxRetryBackoff:       1800, // 30 min max
@@ -456,6 +486,41 @@ func DefaultSyncScheduleConfigs() map[Platform]SyncScheduleConfig {
 			DailySyncLookbackDays: 7,
 			MaxRequestsPerMinute:  60, // Shopee moderate limits
 			RequestBurstSize:      20,
+			BackfillMaxMonths:     6,
+			BackfillChunkDays:     30,
+			BackfillRequestsPerMinute: 30,
+			MaxRetries:            3,
+			RetryBackoffSeconds:   60,
+			MaxRetryBackoff:       900,
+		},
+		PlatformGoogle: {
+			Platform:              PlatformGoogle,
+			HourlySyncEnabled:     true,
+			HourlySyncMinuteOffset: 20,
+			DailySyncEnabled:      true,
+			DailySyncHour:         3,
+			DailySyncLookbackDays: 7,
+			MaxRequestsPerMinute:  100, // Basic access developer tokens are capped per day
+			RequestBurstSize:      20,
+			BackfillMaxMonths:     36,
+			BackfillChunkDays:     90,
+			BackfillRequestsPerMinute: 50,
+			MaxRetries:            3,
+			RetryBackoffSeconds:   60,
+			MaxRetryBackoff:       900,
+		},
+		PlatformLazada: {
+			Platform:              PlatformLazada,
+			HourlySyncEnabled:     true,
+			HourlySyncMinuteOffset: 25,
+			DailySyncEnabled:      true,
+			DailySyncHour:         3,
+			DailySyncLookbackDays: 7,
+			MaxRequestsPerMinute:  50, // Lazada throttles per app and per seller
+			RequestBurstSize:      10,
+			BackfillMaxMonths:     6,
+			BackfillChunkDays:     30,
+			BackfillRequestsPerMinute: 25,
 			MaxRetries:            3,
 			RetryBackoffSeconds:   60,
 			MaxRetryBackoff:       900,
@@ -0,0 +1,114 @@
+package entity
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEventType is an event an organization can receive on its webhook endpoints
+type WebhookEventType string
+
+const (
+	WebhookEventSyncCompleted  WebhookEventType = "sync.completed"  // A sync job of a connected account finished
+	WebhookEventSyncFailed     WebhookEventType = "sync.failed"     // A sync job failed and will not be retried
+	WebhookEventTokenExpired   WebhookEventType = "token.expired"   // A connected account must be reconnected
+	WebhookEventAlertTriggered WebhookEventType = "alert.triggered" // An alert rule triggered
+	WebhookEventReportReady    WebhookEventType = "report.ready"    // A queued report can be downloaded
+)
+
+// WebhookEventTypes returns every event type endpoints can subscribe to
+func WebhookEventTypes() []WebhookEventType {
+	return []WebhookEventType{
+		WebhookEventSyncCompleted,
+		WebhookEventSyncFailed,
+		WebhookEventTokenExpired,
+		WebhookEventAlertTriggered,
+		WebhookEventReportReady,
+	}
+}
+
+// IsValid checks if the event type is supported
+func (t WebhookEventType) IsValid() bool {
+	for _, eventType := range WebhookEventTypes() {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookEndpoint is a URL registered by an organization to receive the events
+// it subscribes to. Every request is signed with the endpoint's secret.
+type WebhookEndpoint struct {
+	BaseEntity
+	OrganizationID uuid.UUID          `json:"organization_id" gorm:"type:uuid;not null;index"`
+	URL            string             `json:"url" gorm:"size:1000;not null"`
+	Description    string             `json:"description,omitempty" gorm:"type:text"`
+	Secret         string             `json:"-" gorm:"size:255;not null"` // Signs delivery payloads, encrypted when an encryption key is configured
+	Events         []WebhookEventType `json:"events" gorm:"type:jsonb;serializer:json"`
+	IsActive       bool               `json:"is_active" gorm:"default:true"`
+	CreatedBy      *uuid.UUID         `json:"created_by,omitempty" gorm:"type:uuid"`
+}
+
+// Subscribes reports whether the endpoint receives events of the type
+func (e *WebhookEndpoint) Subscribes(eventType WebhookEventType) bool {
+	for _, t := range e.Events {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDeliveryStatus represents the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"   // Waiting for its next attempt
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded" // The endpoint responded with 2xx
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"    // Every attempt failed
+)
+
+// IsValid checks if the status is supported
+func (s WebhookDeliveryStatus) IsValid() bool {
+	switch s {
+	case WebhookDeliveryPending, WebhookDeliverySucceeded, WebhookDeliveryFailed:
+		return true
+	default:
+		return false
+	}
+}
+
+// WebhookDelivery is an event sent to one endpoint, with the outcome of its
+// latest attempt. A replay is a new delivery of the same event and payload.
+type WebhookDelivery struct {
+	BaseEntity
+	OrganizationID uuid.UUID             `json:"organization_id" gorm:"type:uuid;not null;index"`
+	EndpointID     uuid.UUID             `json:"endpoint_id" gorm:"type:uuid;not null;index"`
+	EventID        uuid.UUID             `json:"event_id" gorm:"type:uuid;not null"` // Shared by the replays of an event
+	EventType      WebhookEventType      `json:"event_type" gorm:"size:50;not null"`
+	Payload        json.RawMessage       `json:"payload" gorm:"type:jsonb;not null"`
+	Status         WebhookDeliveryStatus `json:"status" gorm:"size:20;not null;default:'pending'"`
+	ReplayOf       *uuid.UUID            `json:"replay_of,omitempty" gorm:"type:uuid"`
+
+	// Attempts
+	Attempts       int        `json:"attempts" gorm:"default:0"`
+	NextAttemptAt  *time.Time `json:"next_attempt_at,omitempty"` // Unset once the delivery succeeded or failed
+	LastAttemptAt  *time.Time `json:"last_attempt_at,omitempty"`
+	ResponseStatus int        `json:"response_status,omitempty"`
+	ResponseBody   string     `json:"response_body,omitempty" gorm:"type:text"` // Truncated
+	DurationMs     int        `json:"duration_ms,omitempty"`
+	Error          string     `json:"error,omitempty" gorm:"type:text"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+}
+
+// WebhookDeliveryFilter represents filter options for listing webhook deliveries
+type WebhookDeliveryFilter struct {
+	OrganizationID uuid.UUID
+	EndpointID     *uuid.UUID
+	EventType      WebhookEventType
+	Status         WebhookDeliveryStatus
+	Pagination     *Pagination
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/pkg/currency"
 	"github.com/google/uuid"
 )
 
@@ -305,6 +306,328 @@ type MetricsRepository interface {
 	GetTopPerformingCampaigns(ctx context.Context, orgID uuid.UUID, dateRange entity.DateRange, limit int) ([]entity.TopPerformer, error)
 }
 
+// OrderRepository defines the interface for marketplace order persistence
+type OrderRepository interface {
+	// GetByID retrieves an order by ID, including its items
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.Order, error)
+
+	// GetByPlatformOrderID retrieves by platform-specific order ID
+	GetByPlatformOrderID(ctx context.Context, orgID uuid.UUID, platform entity.Platform, platformOrderID string) (*entity.Order, error)
+
+	// List lists orders with filters
+	List(ctx context.Context, filter entity.OrderFilter) ([]entity.Order, int64, error)
+
+	// Upsert creates or updates an order and replaces its items
+	Upsert(ctx context.Context, order *entity.Order) error
+
+	// BulkUpsert creates or updates multiple orders and their items
+	BulkUpsert(ctx context.Context, orders []entity.Order) error
+}
+
+// SalesSummaryRepository defines the interface for daily marketplace sales rollups
+type SalesSummaryRepository interface {
+	// RebuildDaily recomputes an organization's daily summaries for the given dates from its orders
+	RebuildDaily(ctx context.Context, orgID uuid.UUID, platform entity.Platform, dates []time.Time) error
+
+	// RebuildForSKUs rebuilds the summaries of every day with orders containing one of the SKUs
+	RebuildForSKUs(ctx context.Context, orgID uuid.UUID, skus []string) error
+
+	// List lists daily summaries for an organization within a date range
+	List(ctx context.Context, orgID uuid.UUID, dateRange entity.DateRange) ([]entity.SalesSummaryDaily, error)
+}
+
+// SKUCostRepository defines the interface for uploaded SKU cost persistence
+type SKUCostRepository interface {
+	// BulkUpsert creates or updates costs keyed on organization, SKU and currency
+	BulkUpsert(ctx context.Context, costs []entity.SKUCost) error
+
+	// List lists an organization's SKU costs
+	List(ctx context.Context, orgID uuid.UUID) ([]entity.SKUCost, error)
+}
+
+// OrganizationBudgetRepository defines the interface for organization budget persistence
+type OrganizationBudgetRepository interface {
+	// GetByOrganization retrieves the budget of an organization
+	GetByOrganization(ctx context.Context, orgID uuid.UUID) (*entity.OrganizationBudget, error)
+
+	// Upsert creates or replaces the budget of an organization
+	Upsert(ctx context.Context, budget *entity.OrganizationBudget) error
+
+	// Delete deletes the budget of an organization
+	Delete(ctx context.Context, orgID uuid.UUID) error
+
+	// ListActive lists every active budget
+	ListActive(ctx context.Context) ([]entity.OrganizationBudget, error)
+}
+
+// BudgetAlertRepository defines the interface for budget alert persistence
+type BudgetAlertRepository interface {
+	// CreateIfNotExists records an alert unless one was already raised for the same
+	// organization, month, platform and threshold. It reports whether the alert was created.
+	CreateIfNotExists(ctx context.Context, alert *entity.BudgetAlert) (bool, error)
+
+	// ListByMonth lists the alerts raised for an organization in the month starting at month
+	ListByMonth(ctx context.Context, orgID uuid.UUID, month time.Time) ([]entity.BudgetAlert, error)
+
+	// Acknowledge acknowledges an alert belonging to the organization. It reports
+	// whether the alert was found.
+	Acknowledge(ctx context.Context, orgID, alertID, userID uuid.UUID) (bool, error)
+}
+
+// AnomalyRepository defines the interface for metric anomaly persistence
+type AnomalyRepository interface {
+	// CreateIfNotExists records an anomaly unless one was already recorded for the
+	// same campaign, day and metric. It reports whether the anomaly was created.
+	CreateIfNotExists(ctx context.Context, anomaly *entity.MetricAnomaly) (bool, error)
+
+	// List lists anomalies with filters, most recent first
+	List(ctx context.Context, filter entity.AnomalyFilter) ([]entity.MetricAnomaly, int64, error)
+
+	// MarkNotified records when anomalies were pushed to the organization
+	MarkNotified(ctx context.Context, ids []uuid.UUID, notifiedAt time.Time) error
+
+	// Acknowledge acknowledges an anomaly belonging to the organization. It reports
+	// whether the anomaly was found.
+	Acknowledge(ctx context.Context, orgID, anomalyID, userID uuid.UUID) (bool, error)
+}
+
+// AlertRuleRepository defines the interface for alert rule persistence
+type AlertRuleRepository interface {
+	// Create creates a new alert rule
+	Create(ctx context.Context, rule *entity.AlertRule) error
+
+	// GetByID retrieves an alert rule by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.AlertRule, error)
+
+	// Update updates an alert rule
+	Update(ctx context.Context, rule *entity.AlertRule) error
+
+	// Delete deletes an alert rule
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// ListByOrganization lists the alert rules of an organization
+	ListByOrganization(ctx context.Context, orgID uuid.UUID) ([]entity.AlertRule, error)
+
+	// ListActive lists the active alert rules of an organization
+	ListActive(ctx context.Context, orgID uuid.UUID) ([]entity.AlertRule, error)
+
+	// SetLastTriggered records when a rule last triggered
+	SetLastTriggered(ctx context.Context, id uuid.UUID, triggeredAt time.Time) error
+}
+
+// AlertEventRepository defines the interface for alert history persistence
+type AlertEventRepository interface {
+	// CreateIfNotExists records an alert unless the rule already triggered for the
+	// same campaign and day. It reports whether the alert was created.
+	CreateIfNotExists(ctx context.Context, event *entity.AlertEvent) (bool, error)
+
+	// LastTriggeredAt returns when the rule last triggered for a campaign, nil if never
+	LastTriggeredAt(ctx context.Context, ruleID, campaignID uuid.UUID) (*time.Time, error)
+
+	// UpdateDelivery records the channels an alert was delivered over
+	UpdateDelivery(ctx context.Context, id uuid.UUID, channels []entity.AlertChannel, deliveryError string) error
+
+	// List lists alerts with filters, most recent first
+	List(ctx context.Context, filter entity.AlertEventFilter) ([]entity.AlertEvent, int64, error)
+
+	// Acknowledge acknowledges an alert belonging to the organization. It reports
+	// whether the alert was found.
+	Acknowledge(ctx context.Context, orgID, eventID, userID uuid.UUID) (bool, error)
+}
+
+// WebhookEndpointRepository defines the interface for outbound webhook endpoint persistence
+type WebhookEndpointRepository interface {
+	// Create creates a new endpoint
+	Create(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+
+	// GetByID retrieves an endpoint by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.WebhookEndpoint, error)
+
+	// Update updates an endpoint
+	Update(ctx context.Context, endpoint *entity.WebhookEndpoint) error
+
+	// Delete deletes an endpoint and its deliveries
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// ListByOrganization lists the endpoints of an organization
+	ListByOrganization(ctx context.Context, orgID uuid.UUID) ([]entity.WebhookEndpoint, error)
+
+	// ListSubscribed lists the active endpoints of an organization subscribed to an event type
+	ListSubscribed(ctx context.Context, orgID uuid.UUID, eventType entity.WebhookEventType) ([]entity.WebhookEndpoint, error)
+}
+
+// WebhookDeliveryRepository defines the interface for outbound webhook delivery persistence
+type WebhookDeliveryRepository interface {
+	// Create creates a new delivery
+	Create(ctx context.Context, delivery *entity.WebhookDelivery) error
+
+	// GetByID retrieves a delivery by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error)
+
+	// Update records the outcome of a delivery attempt
+	Update(ctx context.Context, delivery *entity.WebhookDelivery) error
+
+	// ClaimDue claims up to limit pending deliveries whose next attempt is due,
+	// postponing their next attempt by lease so no other worker claims them
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity.WebhookDelivery, error)
+
+	// List lists deliveries with filters, most recent first
+	List(ctx context.Context, filter entity.WebhookDeliveryFilter) ([]entity.WebhookDelivery, int64, error)
+}
+
+// APIKeyRepository defines the interface for organization API key persistence
+type APIKeyRepository interface {
+	// Create creates a new API key
+	Create(ctx context.Context, key *entity.APIKey) error
+
+	// GetByID retrieves an API key by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.APIKey, error)
+
+	// GetByHash retrieves an API key by the hash of the key
+	GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error)
+
+	// Update updates an API key
+	Update(ctx context.Context, key *entity.APIKey) error
+
+	// ListByOrganization lists an organization's API keys, revoked ones included
+	ListByOrganization(ctx context.Context, orgID uuid.UUID) ([]entity.APIKey, error)
+
+	// UpdateLastUsed records when and from where a key was last used
+	UpdateLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time, ip string) error
+}
+
+// UserMFARepository defines the interface for users' second factor persistence
+type UserMFARepository interface {
+	// GetByUserID retrieves a user's second factor, or nil if they have none
+	GetByUserID(ctx context.Context, userID uuid.UUID) (*entity.UserMFA, error)
+
+	// Save creates or updates a user's second factor
+	Save(ctx context.Context, mfa *entity.UserMFA) error
+
+	// UseStep saves the time step of an accepted code and clears the failed
+	// attempts, unless a code of that step or a later one was accepted
+	// meanwhile; it reports whether it did
+	UseStep(ctx context.Context, mfa *entity.UserMFA) (bool, error)
+
+	// UseRecoveryCode saves the recovery codes left after using the one with
+	// the given hash and clears the failed attempts, unless that code was used
+	// meanwhile; it reports whether it did
+	UseRecoveryCode(ctx context.Context, mfa *entity.UserMFA, usedHash string) (bool, error)
+
+	// DeleteByUserID removes a user's second factor
+	DeleteByUserID(ctx context.Context, userID uuid.UUID) error
+}
+
+// UserSessionRepository defines the interface for login session persistence
+type UserSessionRepository interface {
+	// Create creates a new session
+	Create(ctx context.Context, session *entity.UserSession) error
+
+	// GetByID retrieves a session by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.UserSession, error)
+
+	// GetByTokenID retrieves the session whose latest refresh token has the given ID
+	GetByTokenID(ctx context.Context, tokenID string) (*entity.UserSession, error)
+
+	// Rotate saves a session's new refresh token, unless its token is no longer
+	// previousTokenID or it was revoked meanwhile; it reports whether it did
+	Rotate(ctx context.Context, session *entity.UserSession, previousTokenID string) (bool, error)
+
+	// Revoke revokes a session
+	Revoke(ctx context.Context, id uuid.UUID, reason string, revokedAt time.Time) error
+
+	// RevokeByUser revokes every active session of a user except exceptID and
+	// returns how many were revoked
+	RevokeByUser(ctx context.Context, userID, exceptID uuid.UUID, reason string, revokedAt time.Time) (int64, error)
+
+	// ListActiveByUser lists a user's unrevoked, unexpired sessions, most recently used first
+	ListActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]entity.UserSession, error)
+}
+
+// ExchangeRateRepository defines the interface for dated exchange rate persistence
+type ExchangeRateRepository interface {
+	// SaveRates creates or updates rates keyed on base currency, quote currency and date
+	SaveRates(ctx context.Context, rates []currency.DatedRate) error
+
+	// LoadRates lists every stored rate quoted against base, oldest first
+	LoadRates(ctx context.Context, base string) ([]currency.DatedRate, error)
+}
+
+// ReportJobRepository defines the interface for asynchronous report job persistence
+type ReportJobRepository interface {
+	// Create creates a new report job
+	Create(ctx context.Context, job *entity.ReportJob) error
+
+	// GetByID retrieves a report job by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.ReportJob, error)
+
+	// Update updates a report job
+	Update(ctx context.Context, job *entity.ReportJob) error
+
+	// ListByOrganization lists the most recent report jobs for an organization
+	ListByOrganization(ctx context.Context, orgID uuid.UUID, limit int) ([]entity.ReportJob, error)
+
+	// ClaimNext atomically claims the oldest pending job for a worker, returning nil if none are pending
+	ClaimNext(ctx context.Context, workerID string) (*entity.ReportJob, error)
+
+	// ResetStale returns running jobs that started before the cutoff to pending,
+	// and fails those without attempts left, returning how many jobs it reset
+	ResetStale(ctx context.Context, startedBefore time.Time) (int64, error)
+
+	// SaveArtifact stores the generated report file and marks the job completed
+	SaveArtifact(ctx context.Context, job *entity.ReportJob, artifact *entity.ReportArtifact) error
+
+	// GetArtifact retrieves the generated report file for a job
+	GetArtifact(ctx context.Context, jobID uuid.UUID) (*entity.ReportArtifact, error)
+
+	// DeleteExpired removes artifacts past their expiry and marks their jobs expired
+	DeleteExpired(ctx context.Context, now time.Time) (int64, error)
+}
+
+// ReportScheduleRepository defines the interface for scheduled report delivery persistence
+type ReportScheduleRepository interface {
+	// Create creates a new report schedule
+	Create(ctx context.Context, schedule *entity.ReportSchedule) error
+
+	// GetByID retrieves a report schedule by ID
+	GetByID(ctx context.Context, id uuid.UUID) (*entity.ReportSchedule, error)
+
+	// Update updates a report schedule
+	Update(ctx context.Context, schedule *entity.ReportSchedule) error
+
+	// Delete deletes a report schedule
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// ListByOrganization lists all report schedules for an organization
+	ListByOrganization(ctx context.Context, orgID uuid.UUID) ([]entity.ReportSchedule, error)
+
+	// ListDue lists active schedules whose next run is at or before now
+	ListDue(ctx context.Context, now time.Time, limit int) ([]entity.ReportSchedule, error)
+
+	// ClaimRun advances a schedule's next run only if it still equals expectedNextRun,
+	// so that a run is executed by exactly one worker
+	ClaimRun(ctx context.Context, id uuid.UUID, expectedNextRun, nextRun, runAt time.Time) (bool, error)
+
+	// RecordRunResult stores the outcome of the latest run
+	RecordRunResult(ctx context.Context, id uuid.UUID, status, errorMessage string) error
+}
+
+// AttributionRepository defines the interface for order attribution persistence
+type AttributionRepository interface {
+	// CreateTouchpoint records an ad click
+	CreateTouchpoint(ctx context.Context, touchpoint *entity.AttributionTouchpoint) error
+
+	// ListTouchpoints lists an organization's clicks between from and to, oldest first
+	ListTouchpoints(ctx context.Context, orgID uuid.UUID, from, to time.Time) ([]entity.AttributionTouchpoint, error)
+
+	// ReplaceOrderAttributions replaces all attributions of an order and sets the
+	// order's attributed campaign and ad to its last-click attribution
+	ReplaceOrderAttributions(ctx context.Context, orderID uuid.UUID, attributions []entity.OrderAttribution) error
+
+	// SummarizeByCampaign totals attributed orders and revenue per campaign and currency
+	SummarizeByCampaign(ctx context.Context, orgID uuid.UUID, model entity.AttributionModel, dateRange entity.DateRange) ([]entity.AttributionTotal, error)
+}
+
 // TokenRefreshLogRepository defines the interface for token refresh log persistence
 type TokenRefreshLogRepository interface {
 	// Create creates a new token refresh log entry
@@ -346,6 +669,10 @@ type SyncStateRepository interface {
 	// UpdateSyncCompleted marks a sync as completed
 	UpdateSyncCompleted(ctx context.Context, id uuid.UUID, syncType entity.SyncType) error
 
+	// UpdateSyncStopped clears the syncing flag after a job was paused or
+	// cancelled, without counting it as a success or failure
+	UpdateSyncStopped(ctx context.Context, id uuid.UUID, status entity.SyncStatus) error
+
 	// UpdateSyncFailed marks a sync as failed
 	UpdateSyncFailed(ctx context.Context, id uuid.UUID, err string) error
 
@@ -382,6 +709,28 @@ type SyncJobRepository interface {
 	// ClaimJob atomically claims a pending job for processing
 	ClaimJob(ctx context.Context, jobID uuid.UUID, workerID string) error
 
+	// ClaimNext atomically claims the ready job (pending, or retrying and
+	// due) with the highest priority, oldest first, and leases it to the worker until leaseUntil. Rows locked
+	// by a concurrent claim are skipped (SELECT ... FOR UPDATE SKIP LOCKED), so
+	// no two workers get the same job. Returns nil if no job is ready.
+	ClaimNext(ctx context.Context, workerID string, leaseUntil time.Time) (*entity.SyncJob, error)
+
+	// ExtendLease renews the lease of a running job held by the worker. Returns
+	// false if the worker no longer holds it.
+	ExtendLease(ctx context.Context, id uuid.UUID, workerID string, leaseUntil time.Time) (bool, error)
+
+	// SetStatusIf moves a job to status if its current status is one of from,
+	// reporting whether it did. Leaving running ends the job's lease.
+	SetStatusIf(ctx context.Context, id uuid.UUID, status entity.SyncStatus, from ...entity.SyncStatus) (bool, error)
+
+	// SaveCheckpoint stores the checkpoint and record counts of a running job
+	SaveCheckpoint(ctx context.Context, id uuid.UUID, checkpoint entity.JSONMap, recordsProcessed, recordsFailed int) error
+
+	// RequeueExpired returns running jobs whose lease expired before now to
+	// pending, counting the lost run as a retry. Jobs out of retries are marked
+	// failed instead. Returns the number of jobs released.
+	RequeueExpired(ctx context.Context, now time.Time) (int64, error)
+
 	// UpdateProgress updates job progress
 	UpdateProgress(ctx context.Context, id uuid.UUID, percent int, message string) error
 
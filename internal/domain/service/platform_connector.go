@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
 	"github.com/google/uuid"
@@ -76,6 +77,43 @@ type PlatformConnector interface {
 	HealthCheck(ctx context.Context) error
 }
 
+// OrderConnector is implemented by marketplace connectors that can sync shop orders
+type OrderConnector interface {
+	// GetUpdatedOrders retrieves the orders of a shop updated within a time range,
+	// including their items. The organization is left for the caller to set.
+	GetUpdatedOrders(ctx context.Context, accessToken string, shopID string, startTime, endTime time.Time) ([]entity.Order, error)
+}
+
+// IncrementalStructureConnector is implemented by connectors that can list only
+// the campaigns, ad sets and ads of an ad account changed since a point in time
+type IncrementalStructureConnector interface {
+	// GetStructureChanges retrieves the objects of an ad account updated after
+	// since. Objects deleted since then are included, with the deleted status.
+	GetStructureChanges(ctx context.Context, accessToken string, adAccountID string, since time.Time) (*StructureChanges, error)
+}
+
+// StructureChanges holds the objects of an ad account changed since a point in
+// time. They are listed per account, so ad sets and ads carry the platform IDs
+// of their parents.
+type StructureChanges struct {
+	Campaigns []entity.Campaign
+	AdSets    []AdSetChange
+	Ads       []AdChange
+}
+
+// AdSetChange is a changed ad set with the platform ID of its campaign
+type AdSetChange struct {
+	AdSet              entity.AdSet
+	PlatformCampaignID string
+}
+
+// AdChange is a changed ad with the platform IDs of its campaign and ad set
+type AdChange struct {
+	Ad                 entity.Ad
+	PlatformCampaignID string
+	PlatformAdSetID    string
+}
+
 // RateLimitStatus represents the current rate limit status for a platform
 type RateLimitStatus struct {
 	Platform  entity.Platform
@@ -105,7 +143,7 @@ func (r *SyncResult) IsSuccess() bool {
 
 // HasPartialSuccess returns true if some items were synced despite errors
 func (r *SyncResult) HasPartialSuccess() bool {
-	return len(r.Errors) > 0 && (r.CampaignsSynced > 0 || r.AdSetsSynced > 0 || r.AdsSynced > 0)
+	return len(r.Errors) > 0 && (r.CampaignsSynced > 0 || r.AdSetsSynced > 0 || r.AdsSynced > 0 || r.MetricsSynced > 0)
 }
 
 // ConnectorConfig holds common configuration for platform connectors
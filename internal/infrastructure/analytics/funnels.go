@@ -0,0 +1,366 @@
+package analytics
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ads-aggregator/ads-aggregator/internal/domain/analytics"
+)
+
+// ListFunnelDefinitions returns all configured funnels ordered by name
+func (r *PostgresRepository) ListFunnelDefinitions(ctx context.Context) ([]*domain.FunnelDefinition, error) {
+	rows, err := r.db.WithContext(ctx).Raw(`
+		SELECT id, name, description, steps, conversion_window_seconds, created_at, updated_at
+		FROM analytics_funnels
+		ORDER BY name
+	`).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	definitions := []*domain.FunnelDefinition{}
+	for rows.Next() {
+		def, err := scanFunnelDefinition(rows)
+		if err != nil {
+			return nil, err
+		}
+		definitions = append(definitions, def)
+	}
+
+	return definitions, nil
+}
+
+// GetFunnelDefinition retrieves a configured funnel by name
+func (r *PostgresRepository) GetFunnelDefinition(ctx context.Context, name string) (*domain.FunnelDefinition, error) {
+	rows, err := r.db.WithContext(ctx).Raw(`
+		SELECT id, name, description, steps, conversion_window_seconds, created_at, updated_at
+		FROM analytics_funnels
+		WHERE name = ?
+	`, name).Rows()
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	if !rows.Next() {
+		return nil, domain.ErrFunnelNotFound
+	}
+	return scanFunnelDefinition(rows)
+}
+
+// CreateFunnelDefinition stores a new funnel. It returns ErrFunnelExists if the name is taken.
+func (r *PostgresRepository) CreateFunnelDefinition(ctx context.Context, def *domain.FunnelDefinition) error {
+	steps, err := json.Marshal(def.Steps)
+	if err != nil {
+		return fmt.Errorf("failed to marshal funnel steps: %w", err)
+	}
+
+	if def.ID == uuid.Nil {
+		def.ID = uuid.New()
+	}
+	now := time.Now()
+	def.CreatedAt = now
+	def.UpdatedAt = now
+
+	result := r.db.WithContext(ctx).Exec(`
+		INSERT INTO analytics_funnels (id, name, description, steps, conversion_window_seconds, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT (name) DO NOTHING
+	`, def.ID, def.Name, def.Description, steps, def.ConversionWindowSeconds, def.CreatedAt, def.UpdatedAt)
+	if result.Error != nil {
+		return fmt.Errorf("failed to insert funnel: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrFunnelExists
+	}
+
+	return nil
+}
+
+// UpdateFunnelDefinition replaces the description, steps and conversion window of a funnel
+func (r *PostgresRepository) UpdateFunnelDefinition(ctx context.Context, def *domain.FunnelDefinition) error {
+	steps, err := json.Marshal(def.Steps)
+	if err != nil {
+		return fmt.Errorf("failed to marshal funnel steps: %w", err)
+	}
+
+	def.UpdatedAt = time.Now()
+	result := r.db.WithContext(ctx).Exec(`
+		UPDATE analytics_funnels
+		SET description = ?, steps = ?, conversion_window_seconds = ?, updated_at = ?
+		WHERE name = ?
+	`, def.Description, steps, def.ConversionWindowSeconds, def.UpdatedAt, def.Name)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update funnel: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrFunnelNotFound
+	}
+
+	return nil
+}
+
+// DeleteFunnelDefinition removes a configured funnel
+func (r *PostgresRepository) DeleteFunnelDefinition(ctx context.Context, name string) error {
+	result := r.db.WithContext(ctx).Exec("DELETE FROM analytics_funnels WHERE name = ?", name)
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete funnel: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrFunnelNotFound
+	}
+	return nil
+}
+
+// scanFunnelDefinition scans a row into a FunnelDefinition
+func scanFunnelDefinition(rows *sql.Rows) (*domain.FunnelDefinition, error) {
+	var def domain.FunnelDefinition
+	var description sql.NullString
+	var steps []byte
+
+	err := rows.Scan(
+		&def.ID, &def.Name, &description, &steps,
+		&def.ConversionWindowSeconds, &def.CreatedAt, &def.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	def.Description = description.String
+	if len(steps) > 0 {
+		if err := json.Unmarshal(steps, &def.Steps); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal funnel steps: %w", err)
+		}
+	}
+
+	return &def, nil
+}
+
+// funnelUserPageSize is how many users' events getConfiguredFunnel loads per query
+const funnelUserPageSize = 500
+
+// getConfiguredFunnel evaluates a stored funnel definition against analytics
+// events. Events are loaded a page of users at a time so memory stays bounded
+// however many events fall inside the range.
+func (r *PostgresRepository) getConfiguredFunnel(ctx context.Context, def *domain.FunnelDefinition, from, to time.Time) (*domain.Funnel, error) {
+	// Users entering the funnel near the end of the range may still convert within the window
+	until := to
+	if window := def.ConversionWindow(); window > 0 {
+		until = to.Add(window)
+	}
+
+	types := make([]string, 0, len(def.Steps))
+	for _, t := range def.EventTypes() {
+		types = append(types, string(t))
+	}
+
+	counter := newFunnelCounter(def, from, to)
+	afterUser := uuid.Nil
+	for {
+		events, err := r.funnelEventsPage(ctx, types, from, to, until, afterUser)
+		if err != nil {
+			return nil, err
+		}
+		if len(events) == 0 {
+			break
+		}
+
+		users := 0
+		start := 0
+		for i := 1; i <= len(events); i++ {
+			if i == len(events) || events[i].UserID != events[start].UserID {
+				counter.addUser(events[start:i])
+				start = i
+				users++
+			}
+		}
+
+		afterUser = events[len(events)-1].UserID
+		if users < funnelUserPageSize {
+			break
+		}
+	}
+
+	return counter.result(), nil
+}
+
+// funnelEventsPage returns the matching events of the next page of users after
+// afterUser who have a first-step candidate inside [from, to], ordered by user
+// and time
+func (r *PostgresRepository) funnelEventsPage(ctx context.Context, types []string, from, to, until time.Time, afterUser uuid.UUID) ([]funnelEvent, error) {
+	rows, err := r.db.WithContext(ctx).Raw(`
+		SELECT user_id, event_type, properties, timestamp
+		FROM analytics_events
+		WHERE event_type IN ? AND timestamp >= ? AND timestamp <= ?
+			AND user_id IN (
+				SELECT DISTINCT user_id
+				FROM analytics_events
+				WHERE user_id IS NOT NULL AND user_id > ? AND event_type IN ? AND timestamp >= ? AND timestamp <= ?
+				ORDER BY user_id
+				LIMIT ?
+			)
+		ORDER BY user_id, timestamp
+	`, types, from, until, afterUser, types, from, to, funnelUserPageSize).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query funnel events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []funnelEvent
+	for rows.Next() {
+		var e funnelEvent
+		var properties []byte
+		if err := rows.Scan(&e.UserID, &e.Type, &properties, &e.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan funnel event: %w", err)
+		}
+		if len(properties) > 0 {
+			if err := json.Unmarshal(properties, &e.Properties); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal funnel event properties: %w", err)
+			}
+		}
+		events = append(events, e)
+	}
+
+	return events, rows.Err()
+}
+
+// funnelEvent is the subset of an event needed to evaluate a funnel
+type funnelEvent struct {
+	UserID     uuid.UUID
+	Type       domain.EventType
+	Properties map[string]interface{}
+	Timestamp  time.Time
+}
+
+// computeFunnel evaluates a funnel over an in-memory set of events
+func computeFunnel(def *domain.FunnelDefinition, from, to time.Time, events []funnelEvent) *domain.Funnel {
+	byUser := make(map[uuid.UUID][]funnelEvent)
+	var users []uuid.UUID
+	for _, e := range events {
+		if _, ok := byUser[e.UserID]; !ok {
+			users = append(users, e.UserID)
+		}
+		byUser[e.UserID] = append(byUser[e.UserID], e)
+	}
+
+	counter := newFunnelCounter(def, from, to)
+	for _, userID := range users {
+		counter.addUser(byUser[userID])
+	}
+	return counter.result()
+}
+
+// funnelCounter accumulates step counts one user at a time. A user enters the
+// funnel with their first matching first-step event inside [from, to] and
+// reaches each later step with the first matching event after the previous
+// step, as long as it happens within the conversion window of entering.
+type funnelCounter struct {
+	def       *domain.FunnelDefinition
+	from, to  time.Time
+	window    time.Duration
+	counts    []int64
+	durations [][]float64
+}
+
+func newFunnelCounter(def *domain.FunnelDefinition, from, to time.Time) *funnelCounter {
+	return &funnelCounter{
+		def:       def,
+		from:      from,
+		to:        to,
+		window:    def.ConversionWindow(),
+		counts:    make([]int64, len(def.Steps)),
+		durations: make([][]float64, len(def.Steps)),
+	}
+}
+
+// addUser walks the events of a single user
+func (c *funnelCounter) addUser(userEvents []funnelEvent) {
+	sort.SliceStable(userEvents, func(i, j int) bool {
+		return userEvents[i].Timestamp.Before(userEvents[j].Timestamp)
+	})
+
+	step := 0
+	var entered, previous time.Time
+	for _, e := range userEvents {
+		if step == len(c.def.Steps) {
+			break
+		}
+		if step == 0 {
+			if e.Timestamp.Before(c.from) || e.Timestamp.After(c.to) {
+				continue
+			}
+		} else if c.window > 0 && e.Timestamp.Sub(entered) > c.window {
+			break
+		}
+		if !c.def.Steps[step].Matches(e.Type, e.Properties) {
+			continue
+		}
+
+		if step == 0 {
+			entered = e.Timestamp
+		} else {
+			c.durations[step] = append(c.durations[step], e.Timestamp.Sub(previous).Seconds())
+		}
+		previous = e.Timestamp
+		c.counts[step]++
+		step++
+	}
+}
+
+// result builds the funnel from the accumulated counts
+func (c *funnelCounter) result() *domain.Funnel {
+	funnel := &domain.Funnel{
+		Name:                    c.def.Name,
+		ConversionWindowSeconds: c.def.ConversionWindowSeconds,
+	}
+	counts := c.counts
+
+	for i, stepDef := range c.def.Steps {
+		funnelStep := domain.FunnelStep{
+			Name:  stepDef.Name,
+			Count: counts[i],
+		}
+
+		if i == 0 {
+			if counts[0] > 0 {
+				funnelStep.Percentage = 100
+			}
+		} else {
+			if counts[0] > 0 {
+				funnelStep.Percentage = float64(counts[i]) / float64(counts[0]) * 100
+			}
+			if counts[i-1] > 0 {
+				funnelStep.DropOff = float64(counts[i-1]-counts[i]) / float64(counts[i-1]) * 100
+			}
+			if median, ok := medianOf(c.durations[i]); ok {
+				funnelStep.MedianSecondsFromPrevious = &median
+			}
+		}
+
+		funnel.Steps = append(funnel.Steps, funnelStep)
+	}
+
+	return funnel
+}
+
+// medianOf returns the median of values, or false if there are none
+func medianOf(values []float64) (float64, bool) {
+	if len(values) == 0 {
+		return 0, false
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2, true
+	}
+	return sorted[mid], true
+}
@@ -0,0 +1,167 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	domain "github.com/ads-aggregator/ads-aggregator/internal/domain/analytics"
+)
+
+func TestComputeFunnel(t *testing.T) {
+	start := time.Date(2024, 9, 2, 9, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time { return start.Add(time.Duration(minutes) * time.Minute) }
+
+	def := &domain.FunnelDefinition{
+		Name: "export",
+		Steps: []domain.FunnelStepDefinition{
+			{Name: "Dashboard", EventType: domain.EventDashboardViewed},
+			{Name: "Campaign", EventType: domain.EventCampaignViewed, Filters: map[string]string{"platform": "meta"}},
+			{Name: "Export", EventType: domain.EventCampaignExported},
+		},
+		ConversionWindowSeconds: 3600,
+	}
+
+	alice, bob, carol, dave := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	events := []funnelEvent{
+		// alice completes the funnel in 10 + 20 minutes
+		{alice, domain.EventDashboardViewed, nil, at(0)},
+		{alice, domain.EventCampaignViewed, map[string]interface{}{"platform": "meta"}, at(10)},
+		{alice, domain.EventCampaignExported, nil, at(30)},
+		// bob views a Google campaign first, which does not match the filter
+		{bob, domain.EventDashboardViewed, nil, at(0)},
+		{bob, domain.EventCampaignViewed, map[string]interface{}{"platform": "google"}, at(5)},
+		{bob, domain.EventCampaignViewed, map[string]interface{}{"platform": "meta"}, at(30)},
+		// carol exports before viewing a campaign, then runs out of time
+		{carol, domain.EventDashboardViewed, nil, at(0)},
+		{carol, domain.EventCampaignExported, nil, at(1)},
+		{carol, domain.EventCampaignViewed, map[string]interface{}{"platform": "meta"}, at(2)},
+		{carol, domain.EventCampaignExported, nil, at(90)},
+		// dave only enters before the range
+		{dave, domain.EventDashboardViewed, nil, start.Add(-48 * time.Hour)},
+		{dave, domain.EventCampaignViewed, map[string]interface{}{"platform": "meta"}, at(1)},
+	}
+
+	funnel := computeFunnel(def, start, start.Add(24*time.Hour), events)
+
+	if funnel.Name != "export" || len(funnel.Steps) != 3 {
+		t.Fatalf("unexpected funnel %+v", funnel)
+	}
+
+	wantCounts := []int64{3, 3, 1}
+	for i, want := range wantCounts {
+		if funnel.Steps[i].Count != want {
+			t.Errorf("step %d count = %d, want %d", i, funnel.Steps[i].Count, want)
+		}
+	}
+
+	if funnel.Steps[0].Percentage != 100 || funnel.Steps[0].MedianSecondsFromPrevious != nil {
+		t.Errorf("unexpected first step %+v", funnel.Steps[0])
+	}
+
+	export := funnel.Steps[2]
+	if export.DropOff < 66.6 || export.DropOff > 66.7 {
+		t.Errorf("export drop-off = %f, want 66.67", export.DropOff)
+	}
+
+	// alice 10m, bob 30m, carol 2m
+	if median := funnel.Steps[1].MedianSecondsFromPrevious; median == nil || *median != 600 {
+		t.Errorf("campaign median = %v, want 600", median)
+	}
+	if median := export.MedianSecondsFromPrevious; median == nil || *median != 1200 {
+		t.Errorf("export median = %v, want 1200", median)
+	}
+}
+
+func TestComputeFunnel_NoEvents(t *testing.T) {
+	def := &domain.FunnelDefinition{
+		Name: "empty",
+		Steps: []domain.FunnelStepDefinition{
+			{Name: "A", EventType: domain.EventUserRegistered},
+			{Name: "B", EventType: domain.EventPlatformConnected},
+		},
+	}
+
+	funnel := computeFunnel(def, time.Now().Add(-time.Hour), time.Now(), nil)
+	for _, step := range funnel.Steps {
+		if step.Count != 0 || step.Percentage != 0 || step.DropOff != 0 || step.MedianSecondsFromPrevious != nil {
+			t.Errorf("unexpected step %+v", step)
+		}
+	}
+}
+
+func TestFunnelCounter_AddsUsersAcrossPages(t *testing.T) {
+	start := time.Date(2024, 9, 2, 9, 0, 0, 0, time.UTC)
+	def := &domain.FunnelDefinition{
+		Name: "onboarding",
+		Steps: []domain.FunnelStepDefinition{
+			{Name: "Registered", EventType: domain.EventUserRegistered},
+			{Name: "Connected", EventType: domain.EventPlatformConnected},
+		},
+	}
+
+	alice, bob := uuid.New(), uuid.New()
+	firstPage := []funnelEvent{
+		{alice, domain.EventUserRegistered, nil, start},
+		{alice, domain.EventPlatformConnected, nil, start.Add(time.Minute)},
+	}
+	secondPage := []funnelEvent{
+		{bob, domain.EventUserRegistered, nil, start.Add(time.Hour)},
+	}
+
+	counter := newFunnelCounter(def, start, start.Add(24*time.Hour))
+	counter.addUser(firstPage)
+	counter.addUser(secondPage)
+	paged := counter.result()
+
+	whole := computeFunnel(def, start, start.Add(24*time.Hour), append(firstPage, secondPage...))
+	for i := range whole.Steps {
+		if paged.Steps[i].Count != whole.Steps[i].Count || paged.Steps[i].DropOff != whole.Steps[i].DropOff {
+			t.Errorf("step %d: paged %+v, whole %+v", i, paged.Steps[i], whole.Steps[i])
+		}
+	}
+	if paged.Steps[0].Count != 2 || paged.Steps[1].Count != 1 {
+		t.Errorf("unexpected counts %d, %d", paged.Steps[0].Count, paged.Steps[1].Count)
+	}
+}
+
+func TestFunnelDefinition_Validate(t *testing.T) {
+	valid := func() *domain.FunnelDefinition {
+		return &domain.FunnelDefinition{
+			Name: "onboarding",
+			Steps: []domain.FunnelStepDefinition{
+				{EventType: domain.EventUserRegistered},
+				{EventType: domain.EventPlatformConnected},
+			},
+		}
+	}
+
+	def := valid()
+	if err := def.Validate(); err != nil {
+		t.Fatalf("expected valid funnel, got %v", err)
+	}
+	if def.Steps[0].Name != string(domain.EventUserRegistered) {
+		t.Errorf("expected step name to default to the event type, got %q", def.Steps[0].Name)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*domain.FunnelDefinition)
+	}{
+		{"invalid name", func(d *domain.FunnelDefinition) { d.Name = "My Funnel" }},
+		{"single step", func(d *domain.FunnelDefinition) { d.Steps = d.Steps[:1] }},
+		{"missing event type", func(d *domain.FunnelDefinition) { d.Steps[1].EventType = "" }},
+		{"negative window", func(d *domain.FunnelDefinition) { d.ConversionWindowSeconds = -1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			def := valid()
+			tt.modify(def)
+			if err := def.Validate(); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -419,8 +421,17 @@ func (r *PostgresRepository) GetMetricsHistory(ctx context.Context, metric strin
 	return r.GetActiveUsersTimeSeries(ctx, from, to, "day")
 }
 
-// GetFunnel returns conversion funnel data
+// GetFunnel returns conversion funnel data. Funnels configured in the
+// database take precedence over the built-in ones.
 func (r *PostgresRepository) GetFunnel(ctx context.Context, name string, from, to time.Time) (*domain.Funnel, error) {
+	def, err := r.GetFunnelDefinition(ctx, name)
+	if err == nil {
+		return r.getConfiguredFunnel(ctx, def, from, to)
+	}
+	if !errors.Is(err, domain.ErrFunnelNotFound) {
+		return nil, err
+	}
+
 	funnel := &domain.Funnel{Name: name}
 
 	type stepDef struct {
@@ -475,10 +486,182 @@ func (r *PostgresRepository) GetFunnel(ctx context.Context, name string, from, t
 	return funnel, nil
 }
 
-// GetCohortAnalysis returns cohort retention analysis
+// GetCohortAnalysis returns signup cohort retention analysis. Users are grouped
+// by the period of their first seen event. Retention at each period offset is
+// the share of the cohort that was active in that period, counted separately
+// for every period up to the end of the window, not only users active
+// throughout.
 func (r *PostgresRepository) GetCohortAnalysis(ctx context.Context, from, to time.Time, period string) (*domain.CohortAnalysis, error) {
-	analysis := &domain.CohortAnalysis{Period: period}
-	return analysis, nil
+	truncate, period := cohortPeriod(period)
+
+	rows, err := r.db.WithContext(ctx).Raw(`
+		SELECT user_id, first_seen_at
+		FROM analytics_profiles
+		WHERE first_seen_at >= ? AND first_seen_at <= ?
+	`, from, to).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cohort signups: %w", err)
+	}
+
+	var signups []cohortSignup
+	for rows.Next() {
+		var s cohortSignup
+		if err := rows.Scan(&s.UserID, &s.FirstSeenAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan cohort signup: %w", err)
+		}
+		signups = append(signups, s)
+	}
+	rows.Close()
+
+	if len(signups) == 0 {
+		return &domain.CohortAnalysis{Period: period, Cohorts: []domain.Cohort{}}, nil
+	}
+
+	// Events are collapsed to one row per user and period before leaving the database
+	query := fmt.Sprintf(`
+		SELECT DISTINCT e.user_id, date_trunc('%s', e.timestamp AT TIME ZONE 'UTC') AS active_at
+		FROM analytics_events e
+		JOIN analytics_profiles ap ON ap.user_id = e.user_id
+		WHERE ap.first_seen_at >= ? AND ap.first_seen_at <= ?
+		  AND e.timestamp >= ? AND e.timestamp <= ?
+	`, truncate)
+
+	rows, err = r.db.WithContext(ctx).Raw(query, from, to, from, to).Rows()
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cohort activity: %w", err)
+	}
+	defer rows.Close()
+
+	var activity []cohortActivity
+	for rows.Next() {
+		var a cohortActivity
+		if err := rows.Scan(&a.UserID, &a.Timestamp); err != nil {
+			return nil, fmt.Errorf("failed to scan cohort activity: %w", err)
+		}
+		activity = append(activity, a)
+	}
+
+	return buildCohortAnalysis(period, truncate, to, signups, activity), nil
+}
+
+// cohortSignup is a user and the time they were first seen
+type cohortSignup struct {
+	UserID      uuid.UUID
+	FirstSeenAt time.Time
+}
+
+// cohortActivity is a point in time at which a user was active
+type cohortActivity struct {
+	UserID    uuid.UUID
+	Timestamp time.Time
+}
+
+// cohortPeriod maps the requested period to a date_trunc unit and the
+// period name reported back to callers. Unknown values fall back to weekly.
+func cohortPeriod(period string) (truncate, name string) {
+	switch period {
+	case "day", "daily":
+		return "day", "daily"
+	case "month", "monthly":
+		return "month", "monthly"
+	default:
+		return "week", "weekly"
+	}
+}
+
+// truncateToPeriod truncates t in UTC the same way PostgreSQL date_trunc does
+func truncateToPeriod(t time.Time, truncate string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch truncate {
+	case "month":
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	case "week":
+		// ISO weeks start on Monday
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	default:
+		return day
+	}
+}
+
+// periodOffset returns the number of whole periods between two truncated times
+func periodOffset(start, t time.Time, truncate string) int {
+	switch truncate {
+	case "month":
+		return (t.Year()-start.Year())*12 + int(t.Month()) - int(start.Month())
+	case "week":
+		return int(t.Sub(start).Hours()/24) / 7
+	default:
+		return int(t.Sub(start).Hours() / 24)
+	}
+}
+
+// buildCohortAnalysis groups signups into cohorts and computes, for every
+// period offset up to the end of the window, the percentage of each cohort
+// that was active in that period. Each period is counted on its own, so a user
+// inactive in one period still counts in a later one. Activity before a user's
+// cohort is ignored.
+func buildCohortAnalysis(period, truncate string, to time.Time, signups []cohortSignup, activity []cohortActivity) *domain.CohortAnalysis {
+	analysis := &domain.CohortAnalysis{Period: period, Cohorts: []domain.Cohort{}}
+	last := truncateToPeriod(to, truncate)
+
+	userCohort := make(map[uuid.UUID]time.Time, len(signups))
+	sizes := make(map[time.Time]int64)
+	for _, s := range signups {
+		cohort := truncateToPeriod(s.FirstSeenAt, truncate)
+		userCohort[s.UserID] = cohort
+		sizes[cohort]++
+	}
+
+	// Distinct active users per cohort and offset
+	type userOffset struct {
+		userID uuid.UUID
+		offset int
+	}
+	seen := make(map[userOffset]bool)
+	active := make(map[time.Time]map[int]int64)
+	for _, a := range activity {
+		cohort, ok := userCohort[a.UserID]
+		if !ok {
+			continue
+		}
+		at := truncateToPeriod(a.Timestamp, truncate)
+		if at.Before(cohort) || at.After(last) {
+			continue
+		}
+		offset := periodOffset(cohort, at, truncate)
+		key := userOffset{a.UserID, offset}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if active[cohort] == nil {
+			active[cohort] = make(map[int]int64)
+		}
+		active[cohort][offset]++
+	}
+
+	cohorts := make([]time.Time, 0, len(sizes))
+	for cohort := range sizes {
+		cohorts = append(cohorts, cohort)
+	}
+	sort.Slice(cohorts, func(i, j int) bool { return cohorts[i].Before(cohorts[j]) })
+
+	for _, cohort := range cohorts {
+		size := sizes[cohort]
+		retention := make([]float64, periodOffset(cohort, last, truncate)+1)
+		for offset := range retention {
+			retention[offset] = float64(active[cohort][offset]) / float64(size) * 100
+		}
+		analysis.Cohorts = append(analysis.Cohorts, domain.Cohort{
+			Date:      cohort,
+			Size:      size,
+			Retention: retention,
+		})
+	}
+
+	return analysis
 }
 
 // GetMRR returns Monthly Recurring Revenue
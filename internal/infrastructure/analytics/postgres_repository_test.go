@@ -0,0 +1,157 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// cohortDataset seeds three signup weeks in September 2024. Weeks start on
+// Monday: 2 Sep, 9 Sep and 16 Sep.
+func cohortDataset() ([]cohortSignup, []cohortActivity) {
+	day := func(d, h int) time.Time { return time.Date(2024, 9, d, h, 0, 0, 0, time.UTC) }
+
+	alice, bob, carol, dave, erin, frank := uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New(), uuid.New()
+
+	signups := []cohortSignup{
+		// Week of 2 Sep
+		{alice, day(2, 10)},
+		{bob, day(4, 12)},
+		{carol, day(8, 23)},
+		{dave, day(6, 9)},
+		// Week of 9 Sep
+		{erin, day(10, 8)},
+		// Outside the analysed window
+		{frank, time.Date(2024, 8, 20, 0, 0, 0, 0, time.UTC)},
+	}
+
+	activity := []cohortActivity{
+		// Everyone is active in their signup week
+		{alice, day(2, 10)}, {bob, day(4, 12)}, {carol, day(8, 23)}, {dave, day(6, 9)}, {erin, day(10, 8)},
+		// Repeated events in the same week count once
+		{alice, day(3, 9)}, {alice, day(5, 9)},
+		// Week +1
+		{alice, day(9, 9)}, {bob, day(11, 9)}, {erin, day(17, 9)},
+		// Week +2
+		{alice, day(16, 9)},
+		// After the window ends
+		{bob, time.Date(2024, 9, 30, 9, 0, 0, 0, time.UTC)},
+		// Users outside the window are ignored
+		{frank, day(9, 9)},
+	}
+
+	return signups, activity
+}
+
+func TestBuildCohortAnalysis_Weekly(t *testing.T) {
+	signups, activity := cohortDataset()
+	from := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 9, 20, 0, 0, 0, 0, time.UTC)
+
+	// The repository only passes signups inside the window
+	var inWindow []cohortSignup
+	for _, s := range signups {
+		if !s.FirstSeenAt.Before(from) && !s.FirstSeenAt.After(to) {
+			inWindow = append(inWindow, s)
+		}
+	}
+
+	truncate, period := cohortPeriod("week")
+	analysis := buildCohortAnalysis(period, truncate, to, inWindow, activity)
+
+	if analysis.Period != "weekly" {
+		t.Errorf("expected weekly period, got %q", analysis.Period)
+	}
+	if len(analysis.Cohorts) != 2 {
+		t.Fatalf("expected 2 cohorts, got %d", len(analysis.Cohorts))
+	}
+
+	first := analysis.Cohorts[0]
+	if !first.Date.Equal(time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected first cohort date %v", first.Date)
+	}
+	if first.Size != 4 {
+		t.Errorf("expected first cohort size 4, got %d", first.Size)
+	}
+	assertRetention(t, first.Retention, []float64{100, 50, 25})
+
+	second := analysis.Cohorts[1]
+	if !second.Date.Equal(time.Date(2024, 9, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected second cohort date %v", second.Date)
+	}
+	if second.Size != 1 {
+		t.Errorf("expected second cohort size 1, got %d", second.Size)
+	}
+	assertRetention(t, second.Retention, []float64{100, 100})
+}
+
+func TestBuildCohortAnalysis_Monthly(t *testing.T) {
+	signups, activity := cohortDataset()
+	to := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+
+	truncate, period := cohortPeriod("monthly")
+	analysis := buildCohortAnalysis(period, truncate, to, signups, activity)
+
+	if len(analysis.Cohorts) != 2 {
+		t.Fatalf("expected 2 cohorts, got %d", len(analysis.Cohorts))
+	}
+
+	// August: frank was active in September
+	assertRetention(t, analysis.Cohorts[0].Retention, []float64{0, 100, 0})
+	// September: nobody was active again in October
+	assertRetention(t, analysis.Cohorts[1].Retention, []float64{100, 0})
+}
+
+func TestBuildCohortAnalysis_Daily(t *testing.T) {
+	signups, activity := cohortDataset()
+	to := time.Date(2024, 9, 3, 23, 0, 0, 0, time.UTC)
+
+	truncate, period := cohortPeriod("daily")
+	analysis := buildCohortAnalysis(period, truncate, to, signups[:1], activity)
+
+	if analysis.Period != "daily" || len(analysis.Cohorts) != 1 {
+		t.Fatalf("unexpected analysis %+v", analysis)
+	}
+	assertRetention(t, analysis.Cohorts[0].Retention, []float64{100, 100})
+}
+
+func TestBuildCohortAnalysis_Empty(t *testing.T) {
+	analysis := buildCohortAnalysis("weekly", "week", time.Now(), nil, nil)
+	if analysis.Cohorts == nil || len(analysis.Cohorts) != 0 {
+		t.Errorf("expected empty cohorts, got %v", analysis.Cohorts)
+	}
+}
+
+func TestTruncateToPeriod(t *testing.T) {
+	// Sunday 8 Sep 2024 belongs to the week starting Monday 2 Sep
+	sunday := time.Date(2024, 9, 8, 23, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		truncate string
+		want     time.Time
+	}{
+		{"day", time.Date(2024, 9, 8, 0, 0, 0, 0, time.UTC)},
+		{"week", time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC)},
+		{"month", time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		if got := truncateToPeriod(sunday, tt.truncate); !got.Equal(tt.want) {
+			t.Errorf("truncateToPeriod(%s) = %v, want %v", tt.truncate, got, tt.want)
+		}
+	}
+}
+
+func assertRetention(t *testing.T, got, want []float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("retention = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("retention = %v, want %v", got, want)
+			return
+		}
+	}
+}
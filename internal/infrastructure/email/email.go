@@ -51,6 +51,9 @@
 //   - payment_failed: Payment failure warning
 //   - connect_reminder: Reminder to connect platform
 //   - inactive_reminder: Reminder for inactive users
+//   - scheduled_report: Report delivered by an organization's report schedule
+//   - anomaly_alert: Unusual campaign metrics found after a metrics sync
+//   - alert_triggered: Campaigns matching a user-defined alert rule
 package email
 
 import (
@@ -3,6 +3,7 @@ package email
 import (
 	"bytes"
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"html/template"
@@ -26,6 +27,9 @@ const (
 	EmailTypePaymentFailed       EmailType = "payment_failed"
 	EmailTypeConnectReminder     EmailType = "connect_reminder"
 	EmailTypeInactiveReminder    EmailType = "inactive_reminder"
+	EmailTypeScheduledReport     EmailType = "scheduled_report"
+	EmailTypeAnomalyAlert        EmailType = "anomaly_alert"
+	EmailTypeAlertTriggered      EmailType = "alert_triggered"
 )
 
 // EmailStatus represents the status of an email
@@ -56,6 +60,14 @@ type Email struct {
 	RetryCount  int                    `json:"retry_count"`
 	CreatedAt   time.Time              `json:"created_at"`
 	ScheduledAt *time.Time             `json:"scheduled_at,omitempty"`
+	Attachments []Attachment           `json:"attachments,omitempty"`
+}
+
+// Attachment is a file sent with an email
+type Attachment struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Content     []byte `json:"content"`
 }
 
 // EmailService interface for sending emails
@@ -106,12 +118,19 @@ func NewResendService(cfg *Config) *ResendService {
 
 // ResendRequest represents a Resend API request
 type ResendRequest struct {
-	From    string   `json:"from"`
-	To      []string `json:"to"`
-	Subject string   `json:"subject"`
-	HTML    string   `json:"html"`
-	ReplyTo string   `json:"reply_to,omitempty"`
-	Tags    []Tag    `json:"tags,omitempty"`
+	From        string             `json:"from"`
+	To          []string           `json:"to"`
+	Subject     string             `json:"subject"`
+	HTML        string             `json:"html"`
+	ReplyTo     string             `json:"reply_to,omitempty"`
+	Tags        []Tag              `json:"tags,omitempty"`
+	Attachments []ResendAttachment `json:"attachments,omitempty"`
+}
+
+// ResendAttachment represents a Resend attachment with base64 content
+type ResendAttachment struct {
+	Filename string `json:"filename"`
+	Content  string `json:"content"`
 }
 
 // Tag represents an email tag for tracking
@@ -152,6 +171,12 @@ func (s *ResendService) SendTemplate(ctx context.Context, email *Email, htmlCont
 			{Name: "email_id", Value: email.ID},
 		},
 	}
+	for _, a := range email.Attachments {
+		reqBody.Attachments = append(reqBody.Attachments, ResendAttachment{
+			Filename: a.Filename,
+			Content:  base64.StdEncoding.EncodeToString(a.Content),
+		})
+	}
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
@@ -241,12 +266,21 @@ func NewSendGridService(cfg *Config) *SendGridService {
 
 // SendGridRequest represents a SendGrid API request
 type SendGridRequest struct {
-	Personalizations []Personalization `json:"personalizations"`
-	From             EmailAddress      `json:"from"`
-	ReplyTo          *EmailAddress     `json:"reply_to,omitempty"`
-	Subject          string            `json:"subject"`
-	Content          []Content         `json:"content"`
-	TrackingSettings *TrackingSettings `json:"tracking_settings,omitempty"`
+	Personalizations []Personalization    `json:"personalizations"`
+	From             EmailAddress         `json:"from"`
+	ReplyTo          *EmailAddress        `json:"reply_to,omitempty"`
+	Subject          string               `json:"subject"`
+	Content          []Content            `json:"content"`
+	TrackingSettings *TrackingSettings    `json:"tracking_settings,omitempty"`
+	Attachments      []SendGridAttachment `json:"attachments,omitempty"`
+}
+
+// SendGridAttachment represents a SendGrid attachment with base64 content
+type SendGridAttachment struct {
+	Content     string `json:"content"`
+	Type        string `json:"type,omitempty"`
+	Filename    string `json:"filename"`
+	Disposition string `json:"disposition,omitempty"`
 }
 
 // Personalization represents SendGrid personalization
@@ -320,6 +354,15 @@ func (s *SendGridService) SendTemplate(ctx context.Context, email *Email, htmlCo
 		reqBody.ReplyTo = &EmailAddress{Email: s.config.ReplyTo}
 	}
 
+	for _, a := range email.Attachments {
+		reqBody.Attachments = append(reqBody.Attachments, SendGridAttachment{
+			Content:     base64.StdEncoding.EncodeToString(a.Content),
+			Type:        a.ContentType,
+			Filename:    a.Filename,
+			Disposition: "attachment",
+		})
+	}
+
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
 		return fmt.Errorf("failed to marshal request: %w", err)
@@ -181,6 +181,9 @@ var templates = map[EmailType]string{
 	EmailTypePaymentFailed:       paymentFailedTemplate,
 	EmailTypeConnectReminder:     connectReminderTemplate,
 	EmailTypeInactiveReminder:    inactiveReminderTemplate,
+	EmailTypeScheduledReport:     scheduledReportTemplate,
+	EmailTypeAnomalyAlert:        anomalyAlertTemplate,
+	EmailTypeAlertTriggered:      alertTriggeredTemplate,
 }
 
 // welcomeTemplate - sent after registration
@@ -580,6 +583,85 @@ const inactiveReminderTemplate = `
 {{end}}
 `
 
+// scheduledReportTemplate - report delivered by an organization's report schedule
+const scheduledReportTemplate = `
+{{define "body"}}
+<h1>{{.ScheduleName}} 📊</h1>
+
+<p>Hai,</p>
+
+<p>Laporan prestasi iklan <strong>{{.CompanyName}}</strong> untuk tempoh <strong>{{.PeriodStart}}</strong> hingga <strong>{{.PeriodEnd}}</strong> telah siap.</p>
+
+<div class="success-box">
+    <strong>Laporan dilampirkan</strong><br>
+    Fail: {{.FileName}} ({{.Format}})
+</div>
+
+<p style="text-align: center;">
+    <a href="{{.BaseURL}}/dashboard" class="button">Lihat Dashboard</a>
+</p>
+
+<p style="font-size: 12px; color: #6b7280;">Anda menerima emel ini kerana alamat anda ditambah pada jadual laporan "{{.ScheduleName}}". Hubungi pentadbir akaun anda untuk berhenti menerimanya.</p>
+
+<p>— Tim AdsAnalytic</p>
+{{end}}
+`
+
+// anomalyAlertTemplate - unusual campaign metrics found after a metrics sync
+const anomalyAlertTemplate = `
+{{define "body"}}
+<h1>Perubahan luar biasa dikesan ⚠️</h1>
+
+<p>Hai,</p>
+
+<p>Kami mengesan <strong>{{.Count}} perubahan luar biasa</strong> pada metrik kempen <strong>{{.CompanyName}}</strong> untuk <strong>{{.MetricDate}}</strong> berbanding prestasi biasa kempen tersebut.</p>
+
+{{range .Anomalies}}
+<div class="{{if .Critical}}error-box{{else}}warning-box{{end}}">
+    <strong>{{.CampaignName}}</strong> ({{.Platform}})<br>
+    {{.Metric}}: {{.Value}} berbanding biasa {{.Expected}}{{if .ChangePercent}} ({{.ChangePercent}}){{end}}
+    {{if .Critical}}<br>Perbelanjaan terhenti sepenuhnya. Semak status iklan, baki akaun dan sambungan platform.{{end}}
+</div>
+{{end}}
+
+<p style="text-align: center;">
+    <a href="{{.BaseURL}}/dashboard/anomalies" class="button">Lihat Semua Anomali</a>
+</p>
+
+<p style="font-size: 12px; color: #6b7280;">Anda menerima emel ini sebagai pemilik atau pentadbir organisasi. Hanya perubahan berkeutamaan tinggi dihantar melalui emel.</p>
+
+<p>— Tim AdsAnalytic</p>
+{{end}}
+`
+
+// alertTriggeredTemplate - campaigns matching a user-defined alert rule
+const alertTriggeredTemplate = `
+{{define "body"}}
+<h1>Amaran "{{.RuleName}}" dicetuskan 🔔</h1>
+
+<p>Hai,</p>
+
+<p><strong>{{.Count}} kempen</strong> <strong>{{.CompanyName}}</strong> memenuhi syarat amaran anda untuk <strong>{{.MetricDate}}</strong>:</p>
+
+<p style="font-family: monospace; background-color: #f3f4f6; padding: 12px; border-radius: 6px;">{{.Expression}}</p>
+
+{{range .Alerts}}
+<div class="warning-box">
+    <strong>{{.CampaignName}}</strong> ({{.Platform}})<br>
+    {{.Values}}
+</div>
+{{end}}
+
+<p style="text-align: center;">
+    <a href="{{.BaseURL}}/dashboard/alerts" class="button">Lihat Sejarah Amaran</a>
+</p>
+
+<p style="font-size: 12px; color: #6b7280;">Anda menerima emel ini sebagai pemilik atau pentadbir organisasi. Ubah atau nyahaktifkan peraturan amaran di tetapan amaran.</p>
+
+<p>— Tim AdsAnalytic</p>
+{{end}}
+`
+
 // GetSubject returns the default subject for an email type
 func GetSubject(emailType EmailType, data map[string]interface{}) string {
 	switch emailType {
@@ -606,6 +688,12 @@ func GetSubject(emailType EmailType, data map[string]interface{}) string {
 		return "Jangan lupa connect platform iklan anda! 📱"
 	case EmailTypeInactiveReminder:
 		return "Data iklan anda menunggu! 📊"
+	case EmailTypeScheduledReport:
+		return fmt.Sprintf("📊 %v: %v - %v", data["ScheduleName"], data["PeriodStart"], data["PeriodEnd"])
+	case EmailTypeAnomalyAlert:
+		return fmt.Sprintf("⚠️ %v perubahan luar biasa dikesan - %v", data["Count"], data["CompanyName"])
+	case EmailTypeAlertTriggered:
+		return fmt.Sprintf("🔔 Amaran: %v - %v kempen", data["RuleName"], data["Count"])
 	default:
 		return "Notifikasi dari AdsAnalytic"
 	}
@@ -3,6 +3,7 @@ package email
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -91,6 +92,53 @@ type CampaignSummary struct {
 	Revenue float64
 }
 
+// ScheduledReportData contains a report generated by a report schedule
+type ScheduledReportData struct {
+	Recipients   []string
+	ScheduleName string
+	CompanyName  string
+	PeriodStart  time.Time
+	PeriodEnd    time.Time
+	Format       string
+	Attachment   Attachment
+}
+
+// AnomalyAlertData contains the anomalies found in an organization's campaign metrics
+type AnomalyAlertData struct {
+	Recipients  []string
+	CompanyName string
+	MetricDate  time.Time
+	Anomalies   []AnomalyItem
+}
+
+// AnomalyItem describes one anomalous campaign metric
+type AnomalyItem struct {
+	CampaignName  string
+	Platform      string
+	Metric        string
+	Value         string // Formatted with its unit, e.g. "RM 120.00" or "1.25%"
+	Expected      string
+	ChangePercent float64
+	Critical      bool
+}
+
+// AlertTriggeredData contains the campaigns that triggered an alert rule
+type AlertTriggeredData struct {
+	Recipients  []string
+	CompanyName string
+	RuleName    string
+	Expression  string
+	MetricDate  time.Time
+	Alerts      []AlertItem
+}
+
+// AlertItem describes one campaign that triggered an alert rule
+type AlertItem struct {
+	CampaignName string
+	Platform     string
+	Values       string // Formatted metrics of the rule, e.g. "ROAS 1.20, Spend RM 100.00"
+}
+
 // TriggerWelcomeEmail sends a welcome email after registration
 func (t *EmailTrigger) TriggerWelcomeEmail(ctx context.Context, user *UserData) error {
 	data := map[string]interface{}{
@@ -331,6 +379,118 @@ func (t *EmailTrigger) TriggerInactiveReminderEmail(ctx context.Context, user *U
 	return t.queue.Enqueue(ctx, email)
 }
 
+// TriggerScheduledReportEmail sends a scheduled report to each recipient with the report attached
+func (t *EmailTrigger) TriggerScheduledReportEmail(ctx context.Context, report *ScheduledReportData) error {
+	data := map[string]interface{}{
+		"ScheduleName": report.ScheduleName,
+		"CompanyName":  report.CompanyName,
+		"PeriodStart":  report.PeriodStart.Format("2 Jan 2006"),
+		"PeriodEnd":    report.PeriodEnd.Format("2 Jan 2006"),
+		"Format":       strings.ToUpper(report.Format),
+		"FileName":     report.Attachment.Filename,
+		"BaseURL":      t.config.BaseURL,
+	}
+
+	for _, recipient := range report.Recipients {
+		email := NewEmail(
+			recipient,
+			"",
+			GetSubject(EmailTypeScheduledReport, data),
+			EmailTypeScheduledReport,
+			data,
+		)
+		email.Attachments = []Attachment{report.Attachment}
+
+		if err := t.queue.Enqueue(ctx, email); err != nil {
+			return fmt.Errorf("failed to enqueue report for %s: %w", recipient, err)
+		}
+	}
+
+	return nil
+}
+
+// TriggerAnomalyAlertEmail sends the anomalies found after a metrics sync to each recipient
+func (t *EmailTrigger) TriggerAnomalyAlertEmail(ctx context.Context, alert *AnomalyAlertData) error {
+	anomalies := make([]map[string]interface{}, len(alert.Anomalies))
+	for i, anomaly := range alert.Anomalies {
+		change := ""
+		if anomaly.ChangePercent != 0 {
+			change = fmt.Sprintf("%+.0f%%", anomaly.ChangePercent)
+		}
+		anomalies[i] = map[string]interface{}{
+			"CampaignName":  anomaly.CampaignName,
+			"Platform":      anomaly.Platform,
+			"Metric":        anomaly.Metric,
+			"Value":         anomaly.Value,
+			"Expected":      anomaly.Expected,
+			"ChangePercent": change,
+			"Critical":      anomaly.Critical,
+		}
+	}
+
+	data := map[string]interface{}{
+		"CompanyName": alert.CompanyName,
+		"MetricDate":  alert.MetricDate.Format("2 Jan 2006"),
+		"Count":       len(alert.Anomalies),
+		"Anomalies":   anomalies,
+		"BaseURL":     t.config.BaseURL,
+	}
+
+	for _, recipient := range alert.Recipients {
+		email := NewEmail(
+			recipient,
+			"",
+			GetSubject(EmailTypeAnomalyAlert, data),
+			EmailTypeAnomalyAlert,
+			data,
+		)
+
+		if err := t.queue.Enqueue(ctx, email); err != nil {
+			return fmt.Errorf("failed to enqueue anomaly alert for %s: %w", recipient, err)
+		}
+	}
+
+	return nil
+}
+
+// TriggerAlertTriggeredEmail sends the campaigns that triggered an alert rule to each recipient
+func (t *EmailTrigger) TriggerAlertTriggeredEmail(ctx context.Context, alert *AlertTriggeredData) error {
+	alerts := make([]map[string]interface{}, len(alert.Alerts))
+	for i, item := range alert.Alerts {
+		alerts[i] = map[string]interface{}{
+			"CampaignName": item.CampaignName,
+			"Platform":     item.Platform,
+			"Values":       item.Values,
+		}
+	}
+
+	data := map[string]interface{}{
+		"CompanyName": alert.CompanyName,
+		"RuleName":    alert.RuleName,
+		"Expression":  alert.Expression,
+		"MetricDate":  alert.MetricDate.Format("2 Jan 2006"),
+		"Count":       len(alert.Alerts),
+		"Alerts":      alerts,
+		"BaseURL":     t.config.BaseURL,
+	}
+
+	for _, recipient := range alert.Recipients {
+		email := NewEmail(
+			recipient,
+			"",
+			GetSubject(EmailTypeAlertTriggered, data),
+			EmailTypeAlertTriggered,
+			data,
+		)
+
+		if err := t.queue.Enqueue(ctx, email); err != nil {
+			return fmt.Errorf("failed to enqueue alert for %s: %w", recipient, err)
+		}
+	}
+
+	return nil
+}
+
 // ScheduleConnectReminder schedules a connect reminder for a new user
 func (t *EmailTrigger) ScheduleConnectReminder(ctx context.Context, user *UserData) error {
 	// Schedule for 24 hours from now
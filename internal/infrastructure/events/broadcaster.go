@@ -12,11 +12,13 @@ import (
 type EventType string
 
 const (
-	EventSyncStarted   EventType = "sync:started"
-	EventSyncProgress  EventType = "sync:progress"
-	EventSyncCompleted EventType = "sync:completed"
-	EventSyncError     EventType = "sync:error"
-	EventDataUpdated   EventType = "data:updated"
+	EventSyncStarted     EventType = "sync:started"
+	EventSyncProgress    EventType = "sync:progress"
+	EventSyncCompleted   EventType = "sync:completed"
+	EventSyncError       EventType = "sync:error"
+	EventDataUpdated     EventType = "data:updated"
+	EventAnomalyDetected EventType = "anomaly:detected"
+	EventAlertTriggered  EventType = "alert:triggered"
 )
 
 // Event represents a server-sent event
@@ -63,6 +65,45 @@ type DataUpdatedData struct {
 	Affected []string `json:"affected"` // e.g., ["dashboard", "campaigns"]
 }
 
+// AnomalyData describes one metric anomaly in an anomaly:detected event
+type AnomalyData struct {
+	ID            string  `json:"id"`
+	CampaignID    string  `json:"campaign_id"`
+	CampaignName  string  `json:"campaign_name"`
+	Platform      string  `json:"platform"`
+	MetricDate    string  `json:"metric_date"` // YYYY-MM-DD
+	Metric        string  `json:"metric"`
+	Value         float64 `json:"value"`
+	Expected      float64 `json:"expected"`
+	ChangePercent float64 `json:"change_percent"`
+	Direction     string  `json:"direction"`
+	Severity      string  `json:"severity"`
+}
+
+// AnomalyDetectedData contains data for anomaly:detected event
+type AnomalyDetectedData struct {
+	Anomalies []AnomalyData `json:"anomalies"`
+}
+
+// AlertData describes one campaign that triggered an alert rule
+type AlertData struct {
+	ID           string             `json:"id"`
+	CampaignID   string             `json:"campaign_id"`
+	CampaignName string             `json:"campaign_name"`
+	Platform     string             `json:"platform"`
+	MetricDate   string             `json:"metric_date"` // YYYY-MM-DD
+	Values       map[string]float64 `json:"values"`
+	Currency     string             `json:"currency"`
+}
+
+// AlertTriggeredData contains data for alert:triggered event
+type AlertTriggeredData struct {
+	RuleID     string      `json:"rule_id"`
+	RuleName   string      `json:"rule_name"`
+	Expression string      `json:"expression"`
+	Alerts     []AlertData `json:"alerts"`
+}
+
 // Client represents a connected SSE client
 type Client struct {
 	ID       string
@@ -230,3 +271,20 @@ func NewDataUpdatedEvent(affected []string) Event {
 		Affected: affected,
 	})
 }
+
+// NewAnomalyDetectedEvent creates an anomaly:detected event
+func NewAnomalyDetectedEvent(anomalies []AnomalyData) Event {
+	return NewEvent(EventAnomalyDetected, AnomalyDetectedData{
+		Anomalies: anomalies,
+	})
+}
+
+// NewAlertTriggeredEvent creates an alert:triggered event
+func NewAlertTriggeredEvent(ruleID, ruleName, expression string, alerts []AlertData) Event {
+	return NewEvent(EventAlertTriggered, AlertTriggeredData{
+		RuleID:     ruleID,
+		RuleName:   ruleName,
+		Expression: expression,
+		Alerts:     alerts,
+	})
+}
@@ -234,3 +234,37 @@ func (r *CampaignRepository) UpdateLastSynced(ctx context.Context, id uuid.UUID)
 }
 
 var _ repository.CampaignRepository = (*CampaignRepository)(nil)
+
+// TokenRefreshLogRepository implements repository.TokenRefreshLogRepository
+type TokenRefreshLogRepository struct {
+	db *Database
+}
+
+// NewTokenRefreshLogRepository creates a new token refresh log repository
+func NewTokenRefreshLogRepository(db *Database) *TokenRefreshLogRepository {
+	return &TokenRefreshLogRepository{db: db}
+}
+
+func (r *TokenRefreshLogRepository) Create(ctx context.Context, log *entity.TokenRefreshLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+func (r *TokenRefreshLogRepository) GetByAccountID(ctx context.Context, accountID uuid.UUID, limit int) ([]entity.TokenRefreshLog, error) {
+	var logs []entity.TokenRefreshLog
+	if err := r.db.WithContext(ctx).
+		Where("connected_account_id = ?", accountID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+func (r *TokenRefreshLogRepository) DeleteOld(ctx context.Context, olderThanDays int) error {
+	return r.db.WithContext(ctx).
+		Where("created_at < ?", time.Now().AddDate(0, 0, -olderThanDays)).
+		Delete(&entity.TokenRefreshLog{}).Error
+}
+
+var _ repository.TokenRefreshLogRepository = (*TokenRefreshLogRepository)(nil)
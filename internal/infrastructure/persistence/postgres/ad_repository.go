@@ -0,0 +1,213 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AdSetRepository implements repository.AdSetRepository
+type AdSetRepository struct {
+	db *Database
+}
+
+// NewAdSetRepository creates a new ad set repository
+func NewAdSetRepository(db *Database) *AdSetRepository {
+	return &AdSetRepository{db: db}
+}
+
+func (r *AdSetRepository) Create(ctx context.Context, adSet *entity.AdSet) error {
+	return r.db.WithContext(ctx).Create(adSet).Error
+}
+
+func (r *AdSetRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.AdSet, error) {
+	var adSet entity.AdSet
+	if err := r.db.WithContext(ctx).First(&adSet, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &adSet, nil
+}
+
+func (r *AdSetRepository) GetByPlatformID(ctx context.Context, campaignID uuid.UUID, platformAdSetID string) (*entity.AdSet, error) {
+	var adSet entity.AdSet
+	if err := r.db.WithContext(ctx).First(&adSet, "campaign_id = ? AND platform_ad_set_id = ?", campaignID, platformAdSetID).Error; err != nil {
+		return nil, err
+	}
+	return &adSet, nil
+}
+
+func (r *AdSetRepository) Update(ctx context.Context, adSet *entity.AdSet) error {
+	return r.db.WithContext(ctx).Save(adSet).Error
+}
+
+func (r *AdSetRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entity.AdSet{}, "id = ?", id).Error
+}
+
+func (r *AdSetRepository) List(ctx context.Context, filter entity.AdSetFilter) ([]entity.AdSet, int64, error) {
+	var adSets []entity.AdSet
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entity.AdSet{})
+
+	if filter.OrganizationID != uuid.Nil {
+		query = query.Where("organization_id = ?", filter.OrganizationID)
+	}
+	if len(filter.CampaignIDs) > 0 {
+		query = query.Where("campaign_id IN ?", filter.CampaignIDs)
+	}
+	query = filterPlatformEntities(query, filter.Platforms, filter.Statuses)
+	if filter.SearchTerm != "" {
+		query = query.Where("platform_ad_set_name ILIKE ?", "%"+filter.SearchTerm+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Pagination != nil {
+		query = query.Offset(filter.Pagination.Offset()).Limit(filter.Pagination.PageSize)
+	}
+
+	if err := query.Order("created_at DESC").Find(&adSets).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return adSets, total, nil
+}
+
+func (r *AdSetRepository) ListByCampaign(ctx context.Context, campaignID uuid.UUID) ([]entity.AdSet, error) {
+	var adSets []entity.AdSet
+	if err := r.db.WithContext(ctx).Where("campaign_id = ?", campaignID).Find(&adSets).Error; err != nil {
+		return nil, err
+	}
+	return adSets, nil
+}
+
+func (r *AdSetRepository) Upsert(ctx context.Context, adSet *entity.AdSet) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "campaign_id"}, {Name: "platform_ad_set_id"}},
+		UpdateAll: true,
+	}).Create(adSet).Error
+}
+
+func (r *AdSetRepository) BulkUpsert(ctx context.Context, adSets []entity.AdSet) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "campaign_id"}, {Name: "platform_ad_set_id"}},
+		UpdateAll: true,
+	}).CreateInBatches(adSets, 100).Error
+}
+
+var _ repository.AdSetRepository = (*AdSetRepository)(nil)
+
+// AdRepository implements repository.AdRepository
+type AdRepository struct {
+	db *Database
+}
+
+// NewAdRepository creates a new ad repository
+func NewAdRepository(db *Database) *AdRepository {
+	return &AdRepository{db: db}
+}
+
+func (r *AdRepository) Create(ctx context.Context, ad *entity.Ad) error {
+	return r.db.WithContext(ctx).Create(ad).Error
+}
+
+func (r *AdRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Ad, error) {
+	var ad entity.Ad
+	if err := r.db.WithContext(ctx).First(&ad, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &ad, nil
+}
+
+func (r *AdRepository) GetByPlatformID(ctx context.Context, adSetID uuid.UUID, platformAdID string) (*entity.Ad, error) {
+	var ad entity.Ad
+	if err := r.db.WithContext(ctx).First(&ad, "ad_set_id = ? AND platform_ad_id = ?", adSetID, platformAdID).Error; err != nil {
+		return nil, err
+	}
+	return &ad, nil
+}
+
+func (r *AdRepository) Update(ctx context.Context, ad *entity.Ad) error {
+	return r.db.WithContext(ctx).Save(ad).Error
+}
+
+func (r *AdRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entity.Ad{}, "id = ?", id).Error
+}
+
+func (r *AdRepository) List(ctx context.Context, filter entity.AdFilter) ([]entity.Ad, int64, error) {
+	var ads []entity.Ad
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entity.Ad{})
+
+	if filter.OrganizationID != uuid.Nil {
+		query = query.Where("organization_id = ?", filter.OrganizationID)
+	}
+	if len(filter.AdSetIDs) > 0 {
+		query = query.Where("ad_set_id IN ?", filter.AdSetIDs)
+	}
+	if len(filter.CampaignIDs) > 0 {
+		query = query.Where("campaign_id IN ?", filter.CampaignIDs)
+	}
+	query = filterPlatformEntities(query, filter.Platforms, filter.Statuses)
+	if filter.SearchTerm != "" {
+		query = query.Where("platform_ad_name ILIKE ?", "%"+filter.SearchTerm+"%")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Pagination != nil {
+		query = query.Offset(filter.Pagination.Offset()).Limit(filter.Pagination.PageSize)
+	}
+
+	if err := query.Order("created_at DESC").Find(&ads).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return ads, total, nil
+}
+
+func (r *AdRepository) ListByAdSet(ctx context.Context, adSetID uuid.UUID) ([]entity.Ad, error) {
+	var ads []entity.Ad
+	if err := r.db.WithContext(ctx).Where("ad_set_id = ?", adSetID).Find(&ads).Error; err != nil {
+		return nil, err
+	}
+	return ads, nil
+}
+
+func (r *AdRepository) Upsert(ctx context.Context, ad *entity.Ad) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ad_set_id"}, {Name: "platform_ad_id"}},
+		UpdateAll: true,
+	}).Create(ad).Error
+}
+
+func (r *AdRepository) BulkUpsert(ctx context.Context, ads []entity.Ad) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "ad_set_id"}, {Name: "platform_ad_id"}},
+		UpdateAll: true,
+	}).CreateInBatches(ads, 100).Error
+}
+
+// filterPlatformEntities applies the platform and status filters ad sets and ads share
+func filterPlatformEntities(query *gorm.DB, platforms []entity.Platform, statuses []entity.CampaignStatus) *gorm.DB {
+	if len(platforms) > 0 {
+		query = query.Where("platform IN ?", platforms)
+	}
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	return query
+}
+
+var _ repository.AdRepository = (*AdRepository)(nil)
@@ -0,0 +1,159 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// AlertRuleRepository implements repository.AlertRuleRepository
+type AlertRuleRepository struct {
+	db *Database
+}
+
+// NewAlertRuleRepository creates a new alert rule repository
+func NewAlertRuleRepository(db *Database) *AlertRuleRepository {
+	return &AlertRuleRepository{db: db}
+}
+
+func (r *AlertRuleRepository) Create(ctx context.Context, rule *entity.AlertRule) error {
+	return r.db.WithContext(ctx).Create(rule).Error
+}
+
+func (r *AlertRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.AlertRule, error) {
+	var rule entity.AlertRule
+	if err := r.db.WithContext(ctx).First(&rule, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *AlertRuleRepository) Update(ctx context.Context, rule *entity.AlertRule) error {
+	return r.db.WithContext(ctx).Save(rule).Error
+}
+
+func (r *AlertRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entity.AlertRule{}, "id = ?", id).Error
+}
+
+func (r *AlertRuleRepository) ListByOrganization(ctx context.Context, orgID uuid.UUID) ([]entity.AlertRule, error) {
+	var rules []entity.AlertRule
+	if err := r.db.WithContext(ctx).
+		Where("organization_id = ?", orgID).
+		Order("created_at ASC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *AlertRuleRepository) ListActive(ctx context.Context, orgID uuid.UUID) ([]entity.AlertRule, error) {
+	var rules []entity.AlertRule
+	if err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND is_active = ?", orgID, true).
+		Order("created_at ASC").
+		Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *AlertRuleRepository) SetLastTriggered(ctx context.Context, id uuid.UUID, triggeredAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&entity.AlertRule{}).
+		Where("id = ?", id).
+		Update("last_triggered_at", triggeredAt).Error
+}
+
+// AlertEventRepository implements repository.AlertEventRepository
+type AlertEventRepository struct {
+	db *Database
+}
+
+// NewAlertEventRepository creates a new alert event repository
+func NewAlertEventRepository(db *Database) *AlertEventRepository {
+	return &AlertEventRepository{db: db}
+}
+
+func (r *AlertEventRepository) CreateIfNotExists(ctx context.Context, event *entity.AlertEvent) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "rule_id"}, {Name: "campaign_id"}, {Name: "metric_date"}},
+		DoNothing: true,
+	}).Create(event)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *AlertEventRepository) LastTriggeredAt(ctx context.Context, ruleID, campaignID uuid.UUID) (*time.Time, error) {
+	var event entity.AlertEvent
+	result := r.db.WithContext(ctx).
+		Where("rule_id = ? AND campaign_id = ?", ruleID, campaignID).
+		Order("triggered_at DESC").
+		Limit(1).
+		Find(&event)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return nil, nil
+	}
+	return &event.TriggeredAt, nil
+}
+
+func (r *AlertEventRepository) UpdateDelivery(ctx context.Context, id uuid.UUID, channels []entity.AlertChannel, deliveryError string) error {
+	return r.db.WithContext(ctx).Model(&entity.AlertEvent{ID: id}).
+		Select("delivered_channels", "delivery_error").
+		Updates(&entity.AlertEvent{DeliveredChannels: channels, DeliveryError: deliveryError}).Error
+}
+
+func (r *AlertEventRepository) List(ctx context.Context, filter entity.AlertEventFilter) ([]entity.AlertEvent, int64, error) {
+	var events []entity.AlertEvent
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entity.AlertEvent{}).
+		Where("organization_id = ?", filter.OrganizationID)
+
+	if filter.RuleID != nil {
+		query = query.Where("rule_id = ?", *filter.RuleID)
+	}
+	if filter.CampaignID != nil {
+		query = query.Where("campaign_id = ?", *filter.CampaignID)
+	}
+	if filter.DateRange != nil {
+		query = query.Where("metric_date BETWEEN ? AND ?", filter.DateRange.StartDate, filter.DateRange.EndDate)
+	}
+	if filter.Unacknowledged {
+		query = query.Where("acknowledged_at IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Pagination != nil {
+		query = query.Offset(filter.Pagination.Offset()).Limit(filter.Pagination.PageSize)
+	}
+
+	if err := query.Order("triggered_at DESC").Find(&events).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return events, total, nil
+}
+
+func (r *AlertEventRepository) Acknowledge(ctx context.Context, orgID, eventID, userID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.AlertEvent{}).
+		Where("id = ? AND organization_id = ?", eventID, orgID).
+		Updates(map[string]interface{}{
+			"acknowledged_at": time.Now(),
+			"acknowledged_by": userID,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+var (
+	_ repository.AlertRuleRepository  = (*AlertRuleRepository)(nil)
+	_ repository.AlertEventRepository = (*AlertEventRepository)(nil)
+)
@@ -0,0 +1,99 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// AnomalyRepository implements repository.AnomalyRepository
+type AnomalyRepository struct {
+	db *Database
+}
+
+// NewAnomalyRepository creates a new anomaly repository
+func NewAnomalyRepository(db *Database) *AnomalyRepository {
+	return &AnomalyRepository{db: db}
+}
+
+func (r *AnomalyRepository) CreateIfNotExists(ctx context.Context, anomaly *entity.MetricAnomaly) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "campaign_id"}, {Name: "metric_date"}, {Name: "metric"}},
+		DoNothing: true,
+	}).Create(anomaly)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *AnomalyRepository) List(ctx context.Context, filter entity.AnomalyFilter) ([]entity.MetricAnomaly, int64, error) {
+	var anomalies []entity.MetricAnomaly
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entity.MetricAnomaly{}).
+		Where("organization_id = ?", filter.OrganizationID)
+
+	if filter.CampaignID != nil {
+		query = query.Where("campaign_id = ?", *filter.CampaignID)
+	}
+	if len(filter.Metrics) > 0 {
+		query = query.Where("metric IN ?", filter.Metrics)
+	}
+	if filter.MinSeverity != "" {
+		var severities []entity.AnomalySeverity
+		for _, severity := range []entity.AnomalySeverity{
+			entity.AnomalySeverityLow,
+			entity.AnomalySeverityMedium,
+			entity.AnomalySeverityHigh,
+			entity.AnomalySeverityCritical,
+		} {
+			if severity.Rank() >= filter.MinSeverity.Rank() {
+				severities = append(severities, severity)
+			}
+		}
+		query = query.Where("severity IN ?", severities)
+	}
+	if filter.DateRange != nil {
+		query = query.Where("metric_date BETWEEN ? AND ?", filter.DateRange.StartDate, filter.DateRange.EndDate)
+	}
+	if filter.Unacknowledged {
+		query = query.Where("acknowledged_at IS NULL")
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Pagination != nil {
+		query = query.Offset(filter.Pagination.Offset()).Limit(filter.Pagination.PageSize)
+	}
+
+	if err := query.Order("metric_date DESC, created_at DESC").Find(&anomalies).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return anomalies, total, nil
+}
+
+func (r *AnomalyRepository) MarkNotified(ctx context.Context, ids []uuid.UUID, notifiedAt time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Model(&entity.MetricAnomaly{}).
+		Where("id IN ?", ids).
+		Update("notified_at", notifiedAt).Error
+}
+
+func (r *AnomalyRepository) Acknowledge(ctx context.Context, orgID, anomalyID, userID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.MetricAnomaly{}).
+		Where("id = ? AND organization_id = ?", anomalyID, orgID).
+		Updates(map[string]interface{}{
+			"acknowledged_at": time.Now(),
+			"acknowledged_by": userID,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+var _ repository.AnomalyRepository = (*AnomalyRepository)(nil)
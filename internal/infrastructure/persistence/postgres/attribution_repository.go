@@ -0,0 +1,90 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// AttributionRepository implements repository.AttributionRepository
+type AttributionRepository struct {
+	db *Database
+}
+
+// NewAttributionRepository creates a new attribution repository
+func NewAttributionRepository(db *Database) *AttributionRepository {
+	return &AttributionRepository{db: db}
+}
+
+func (r *AttributionRepository) CreateTouchpoint(ctx context.Context, touchpoint *entity.AttributionTouchpoint) error {
+	return r.db.WithContext(ctx).Create(touchpoint).Error
+}
+
+func (r *AttributionRepository) ListTouchpoints(ctx context.Context, orgID uuid.UUID, from, to time.Time) ([]entity.AttributionTouchpoint, error) {
+	var touchpoints []entity.AttributionTouchpoint
+	if err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND clicked_at BETWEEN ? AND ?", orgID, from, to).
+		Order("clicked_at").
+		Find(&touchpoints).Error; err != nil {
+		return nil, err
+	}
+	return touchpoints, nil
+}
+
+func (r *AttributionRepository) ReplaceOrderAttributions(ctx context.Context, orderID uuid.UUID, attributions []entity.OrderAttribution) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("order_id = ?", orderID).Delete(&entity.OrderAttribution{}).Error; err != nil {
+			return err
+		}
+
+		var campaignID, adID *uuid.UUID
+		for i := range attributions {
+			a := &attributions[i]
+			if a.Model == entity.AttributionModelLastClick {
+				campaignID = &a.CampaignID
+				adID = a.AdID
+			}
+		}
+
+		if err := tx.Model(&entity.Order{}).
+			Where("id = ?", orderID).
+			Updates(map[string]interface{}{
+				"attributed_campaign_id": campaignID,
+				"attributed_ad_id":       adID,
+			}).Error; err != nil {
+			return err
+		}
+
+		if len(attributions) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(attributions, 100).Error
+	})
+}
+
+func (r *AttributionRepository) SummarizeByCampaign(ctx context.Context, orgID uuid.UUID, model entity.AttributionModel, dateRange entity.DateRange) ([]entity.AttributionTotal, error) {
+	var totals []entity.AttributionTotal
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			oa.campaign_id, oa.currency,
+			SUM(oa.weight) AS orders,
+			SUM(oa.revenue) AS revenue,
+			SUM(oa.weight * COALESCE(o.shipping_fee, 0)) AS shipping,
+			SUM(oa.weight * COALESCE(i.cogs, 0)) AS cogs,
+			SUM(oa.weight * (COALESCE(o.commission_fee, 0) + COALESCE(o.service_fee, 0) + COALESCE(o.transaction_fee, 0))) AS fees
+		FROM order_attributions oa
+		JOIN orders o ON o.id = oa.order_id
+		`+orderItemCostsLateral+`
+		WHERE oa.organization_id = ? AND oa.model = ? AND oa.order_date BETWEEN ? AND ?
+		GROUP BY oa.campaign_id, oa.currency
+	`, orgID, model, dateRange.StartDate, dateRange.EndDate).Scan(&totals).Error; err != nil {
+		return nil, err
+	}
+	return totals, nil
+}
+
+var _ repository.AttributionRepository = (*AttributionRepository)(nil)
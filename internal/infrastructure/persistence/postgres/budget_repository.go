@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// OrganizationBudgetRepository implements repository.OrganizationBudgetRepository
+type OrganizationBudgetRepository struct {
+	db *Database
+}
+
+// NewOrganizationBudgetRepository creates a new organization budget repository
+func NewOrganizationBudgetRepository(db *Database) *OrganizationBudgetRepository {
+	return &OrganizationBudgetRepository{db: db}
+}
+
+func (r *OrganizationBudgetRepository) GetByOrganization(ctx context.Context, orgID uuid.UUID) (*entity.OrganizationBudget, error) {
+	var budget entity.OrganizationBudget
+	if err := r.db.WithContext(ctx).First(&budget, "organization_id = ?", orgID).Error; err != nil {
+		return nil, err
+	}
+	return &budget, nil
+}
+
+func (r *OrganizationBudgetRepository) Upsert(ctx context.Context, budget *entity.OrganizationBudget) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "organization_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"monthly_amount", "currency", "platform_allocations", "is_active", "updated_at"}),
+	}).Create(budget).Error
+}
+
+func (r *OrganizationBudgetRepository) Delete(ctx context.Context, orgID uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entity.OrganizationBudget{}, "organization_id = ?", orgID).Error
+}
+
+func (r *OrganizationBudgetRepository) ListActive(ctx context.Context) ([]entity.OrganizationBudget, error) {
+	var budgets []entity.OrganizationBudget
+	if err := r.db.WithContext(ctx).
+		Where("is_active = ?", true).
+		Order("organization_id").
+		Find(&budgets).Error; err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+// BudgetAlertRepository implements repository.BudgetAlertRepository
+type BudgetAlertRepository struct {
+	db *Database
+}
+
+// NewBudgetAlertRepository creates a new budget alert repository
+func NewBudgetAlertRepository(db *Database) *BudgetAlertRepository {
+	return &BudgetAlertRepository{db: db}
+}
+
+func (r *BudgetAlertRepository) CreateIfNotExists(ctx context.Context, alert *entity.BudgetAlert) (bool, error) {
+	result := r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "organization_id"}, {Name: "month"}, {Name: "platform"}, {Name: "threshold"}},
+		DoNothing: true,
+	}).Create(alert)
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *BudgetAlertRepository) ListByMonth(ctx context.Context, orgID uuid.UUID, month time.Time) ([]entity.BudgetAlert, error) {
+	var alerts []entity.BudgetAlert
+	if err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND month = ?", orgID, month).
+		Order("created_at DESC").
+		Find(&alerts).Error; err != nil {
+		return nil, err
+	}
+	return alerts, nil
+}
+
+func (r *BudgetAlertRepository) Acknowledge(ctx context.Context, orgID, alertID, userID uuid.UUID) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.BudgetAlert{}).
+		Where("id = ? AND organization_id = ?", alertID, orgID).
+		Updates(map[string]interface{}{
+			"acknowledged_at": time.Now(),
+			"acknowledged_by": userID,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+var (
+	_ repository.OrganizationBudgetRepository = (*OrganizationBudgetRepository)(nil)
+	_ repository.BudgetAlertRepository        = (*BudgetAlertRepository)(nil)
+)
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/currency"
+	"gorm.io/gorm/clause"
+)
+
+// ExchangeRateRepository implements repository.ExchangeRateRepository
+type ExchangeRateRepository struct {
+	db *Database
+}
+
+// NewExchangeRateRepository creates a new exchange rate repository
+func NewExchangeRateRepository(db *Database) *ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+func (r *ExchangeRateRepository) SaveRates(ctx context.Context, rates []currency.DatedRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	rows := make([]entity.ExchangeRate, len(rates))
+	for i, rate := range rates {
+		rows[i] = entity.ExchangeRate{
+			BaseEntity:    entity.NewBaseEntity(),
+			BaseCurrency:  rate.Base,
+			QuoteCurrency: rate.Quote,
+			RateDate:      rate.Date,
+			Rate:          rate.Rate,
+		}
+	}
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "base_currency"}, {Name: "quote_currency"}, {Name: "rate_date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).CreateInBatches(rows, 1000).Error
+}
+
+func (r *ExchangeRateRepository) LoadRates(ctx context.Context, base string) ([]currency.DatedRate, error) {
+	var rows []entity.ExchangeRate
+	if err := r.db.WithContext(ctx).
+		Where("base_currency = ?", base).
+		Order("rate_date").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	rates := make([]currency.DatedRate, len(rows))
+	for i, row := range rows {
+		rates[i] = currency.DatedRate{
+			Date:  row.RateDate,
+			Base:  row.BaseCurrency,
+			Quote: row.QuoteCurrency,
+			Rate:  row.Rate,
+		}
+	}
+	return rates, nil
+}
+
+var _ repository.ExchangeRateRepository = (*ExchangeRateRepository)(nil)
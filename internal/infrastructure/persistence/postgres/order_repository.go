@@ -0,0 +1,141 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OrderRepository implements repository.OrderRepository
+type OrderRepository struct {
+	db *Database
+}
+
+func NewOrderRepository(db *Database) *OrderRepository {
+	return &OrderRepository{db: db}
+}
+
+func (r *OrderRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Order, error) {
+	var order entity.Order
+	if err := r.db.WithContext(ctx).Preload("Items").First(&order, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *OrderRepository) GetByPlatformOrderID(ctx context.Context, orgID uuid.UUID, platform entity.Platform, platformOrderID string) (*entity.Order, error) {
+	var order entity.Order
+	if err := r.db.WithContext(ctx).Preload("Items").First(&order,
+		"organization_id = ? AND platform = ? AND platform_order_id = ?",
+		orgID, platform, platformOrderID).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *OrderRepository) List(ctx context.Context, filter entity.OrderFilter) ([]entity.Order, int64, error) {
+	var orders []entity.Order
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entity.Order{})
+
+	if filter.OrganizationID != uuid.Nil {
+		query = query.Where("organization_id = ?", filter.OrganizationID)
+	}
+	if len(filter.Platforms) > 0 {
+		query = query.Where("platform IN ?", filter.Platforms)
+	}
+	if len(filter.Statuses) > 0 {
+		query = query.Where("status IN ?", filter.Statuses)
+	}
+	if filter.DateRange != nil {
+		query = query.Where("order_date BETWEEN ? AND ?", filter.DateRange.StartDate, filter.DateRange.EndDate)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Pagination != nil {
+		query = query.Offset(filter.Pagination.Offset()).Limit(filter.Pagination.PageSize)
+	}
+
+	if err := query.Order("order_created_at DESC").Find(&orders).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return orders, total, nil
+}
+
+func (r *OrderRepository) Upsert(ctx context.Context, order *entity.Order) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return upsertOrder(tx, order)
+	})
+}
+
+func (r *OrderRepository) BulkUpsert(ctx context.Context, orders []entity.Order) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for i := range orders {
+			if err := upsertOrder(tx, &orders[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// orderUpsertColumns are the columns refreshed when an order is synced again.
+// The attributed campaign and ad are owned by the attribution run and are kept.
+var orderUpsertColumns = []string{
+	"shopee_shop_id", "platform_order_sn", "status", "customer_id", "customer_name",
+	"currency", "subtotal", "shipping_fee", "discount_amount", "voucher_amount",
+	"seller_discount", "platform_discount", "total_amount",
+	"commission_fee", "service_fee", "transaction_fee", "estimated_profit",
+	"tracking_number", "shipping_carrier",
+	"utm_source", "utm_medium", "utm_campaign", "utm_content",
+	"order_date", "order_created_at", "paid_at", "shipped_at", "delivered_at", "cancelled_at",
+	"platform_data", "last_synced_at", "updated_at",
+}
+
+// upsertOrder writes the order keyed on (organization_id, platform, platform_order_id)
+// and replaces its items, since marketplaces return the full item list on every fetch.
+func upsertOrder(tx *gorm.DB, order *entity.Order) error {
+	items := order.Items
+
+	if err := tx.Omit("Items").Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "organization_id"}, {Name: "platform"}, {Name: "platform_order_id"}},
+		DoUpdates: clause.AssignmentColumns(orderUpsertColumns),
+	}).Create(order).Error; err != nil {
+		return err
+	}
+
+	// On conflict the generated ID is discarded, so read back the stored one
+	if err := tx.Model(&entity.Order{}).
+		Select("id").
+		Where("organization_id = ? AND platform = ? AND platform_order_id = ?",
+			order.OrganizationID, order.Platform, order.PlatformOrderID).
+		Scan(&order.ID).Error; err != nil {
+		return err
+	}
+
+	if err := tx.Where("order_id = ?", order.ID).Delete(&entity.OrderItem{}).Error; err != nil {
+		return err
+	}
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	for i := range items {
+		items[i].OrderID = order.ID
+		items[i].OrganizationID = order.OrganizationID
+	}
+
+	return tx.CreateInBatches(items, 100).Error
+}
+
+var _ repository.OrderRepository = (*OrderRepository)(nil)
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ReportJobRepository implements repository.ReportJobRepository
+type ReportJobRepository struct {
+	db *Database
+}
+
+// NewReportJobRepository creates a new report job repository
+func NewReportJobRepository(db *Database) *ReportJobRepository {
+	return &ReportJobRepository{db: db}
+}
+
+func (r *ReportJobRepository) Create(ctx context.Context, job *entity.ReportJob) error {
+	return r.db.WithContext(ctx).Create(job).Error
+}
+
+func (r *ReportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.ReportJob, error) {
+	var job entity.ReportJob
+	if err := r.db.WithContext(ctx).First(&job, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *ReportJobRepository) Update(ctx context.Context, job *entity.ReportJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+func (r *ReportJobRepository) ListByOrganization(ctx context.Context, orgID uuid.UUID, limit int) ([]entity.ReportJob, error) {
+	var jobs []entity.ReportJob
+	if err := r.db.WithContext(ctx).
+		Where("organization_id = ?", orgID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+// ClaimNext uses SKIP LOCKED so concurrent workers never claim the same job
+func (r *ReportJobRepository) ClaimNext(ctx context.Context, workerID string) (*entity.ReportJob, error) {
+	var jobs []entity.ReportJob
+	if err := r.db.WithContext(ctx).Raw(`
+		UPDATE report_jobs
+		SET status = ?, worker_id = ?, attempts = attempts + 1, started_at = NOW(), updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM report_jobs
+			WHERE status = ?
+			ORDER BY created_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING *`,
+		entity.ReportJobStatusRunning, workerID, entity.ReportJobStatusPending,
+	).Scan(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	return &jobs[0], nil
+}
+
+// ResetStale requeues stale jobs with attempts left. A job that has stopped its
+// worker on every attempt is failed, so it cannot keep taking workers down.
+func (r *ReportJobRepository) ResetStale(ctx context.Context, startedBefore time.Time) (int64, error) {
+	var reset int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		failed := tx.Model(&entity.ReportJob{}).
+			Where("status = ? AND started_at < ? AND attempts >= max_attempts", entity.ReportJobStatusRunning, startedBefore).
+			Updates(map[string]interface{}{
+				"status":        entity.ReportJobStatusFailed,
+				"worker_id":     "",
+				"error_message": entity.ReportJobAbandonedMessage,
+				"completed_at":  time.Now(),
+			})
+		if failed.Error != nil {
+			return failed.Error
+		}
+
+		requeued := tx.Model(&entity.ReportJob{}).
+			Where("status = ? AND started_at < ? AND attempts < max_attempts", entity.ReportJobStatusRunning, startedBefore).
+			Updates(map[string]interface{}{
+				"status":    entity.ReportJobStatusPending,
+				"worker_id": "",
+			})
+		reset = failed.RowsAffected + requeued.RowsAffected
+		return requeued.Error
+	})
+	return reset, err
+}
+
+func (r *ReportJobRepository) SaveArtifact(ctx context.Context, job *entity.ReportJob, artifact *entity.ReportArtifact) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "report_job_id"}},
+			UpdateAll: true,
+		}).Create(artifact).Error; err != nil {
+			return err
+		}
+		return tx.Save(job).Error
+	})
+}
+
+func (r *ReportJobRepository) GetArtifact(ctx context.Context, jobID uuid.UUID) (*entity.ReportArtifact, error) {
+	var artifact entity.ReportArtifact
+	if err := r.db.WithContext(ctx).First(&artifact, "report_job_id = ?", jobID).Error; err != nil {
+		return nil, err
+	}
+	return &artifact, nil
+}
+
+func (r *ReportJobRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	var deleted int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entity.ReportJob{}).
+			Where("status = ? AND expires_at < ?", entity.ReportJobStatusCompleted, now).
+			Update("status", entity.ReportJobStatusExpired).Error; err != nil {
+			return err
+		}
+
+		result := tx.Where("expires_at < ?", now).Delete(&entity.ReportArtifact{})
+		deleted = result.RowsAffected
+		return result.Error
+	})
+	return deleted, err
+}
+
+var _ repository.ReportJobRepository = (*ReportJobRepository)(nil)
@@ -0,0 +1,84 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+// ReportScheduleRepository implements repository.ReportScheduleRepository
+type ReportScheduleRepository struct {
+	db *Database
+}
+
+// NewReportScheduleRepository creates a new report schedule repository
+func NewReportScheduleRepository(db *Database) *ReportScheduleRepository {
+	return &ReportScheduleRepository{db: db}
+}
+
+func (r *ReportScheduleRepository) Create(ctx context.Context, schedule *entity.ReportSchedule) error {
+	return r.db.WithContext(ctx).Create(schedule).Error
+}
+
+func (r *ReportScheduleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.ReportSchedule, error) {
+	var schedule entity.ReportSchedule
+	if err := r.db.WithContext(ctx).First(&schedule, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &schedule, nil
+}
+
+func (r *ReportScheduleRepository) Update(ctx context.Context, schedule *entity.ReportSchedule) error {
+	return r.db.WithContext(ctx).Save(schedule).Error
+}
+
+func (r *ReportScheduleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entity.ReportSchedule{}, "id = ?", id).Error
+}
+
+func (r *ReportScheduleRepository) ListByOrganization(ctx context.Context, orgID uuid.UUID) ([]entity.ReportSchedule, error) {
+	var schedules []entity.ReportSchedule
+	if err := r.db.WithContext(ctx).
+		Where("organization_id = ?", orgID).
+		Order("created_at").
+		Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *ReportScheduleRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]entity.ReportSchedule, error) {
+	var schedules []entity.ReportSchedule
+	if err := r.db.WithContext(ctx).
+		Where("is_active = ? AND next_run_at <= ?", true, now).
+		Order("next_run_at").
+		Limit(limit).
+		Find(&schedules).Error; err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+func (r *ReportScheduleRepository) ClaimRun(ctx context.Context, id uuid.UUID, expectedNextRun, nextRun, runAt time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).Model(&entity.ReportSchedule{}).
+		Where("id = ? AND next_run_at = ?", id, expectedNextRun).
+		Updates(map[string]interface{}{
+			"next_run_at": nextRun,
+			"last_run_at": runAt,
+		})
+	return result.RowsAffected == 1, result.Error
+}
+
+func (r *ReportScheduleRepository) RecordRunResult(ctx context.Context, id uuid.UUID, status, errorMessage string) error {
+	return r.db.WithContext(ctx).Model(&entity.ReportSchedule{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"last_status": status,
+			"last_error":  errorMessage,
+		}).Error
+}
+
+var _ repository.ReportScheduleRepository = (*ReportScheduleRepository)(nil)
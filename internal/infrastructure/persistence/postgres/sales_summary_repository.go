@@ -0,0 +1,151 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// SalesSummaryRepository implements repository.SalesSummaryRepository
+type SalesSummaryRepository struct {
+	db *Database
+}
+
+// NewSalesSummaryRepository creates a new sales summary repository
+func NewSalesSummaryRepository(db *Database) *SalesSummaryRepository {
+	return &SalesSummaryRepository{db: db}
+}
+
+// orderItemCostsLateral totals the items sold and COGS of order o. Items without
+// a unit cost fall back to the uploaded cost of their SKU in the order's currency.
+const orderItemCostsLateral = `
+	LEFT JOIN LATERAL (
+		SELECT
+			SUM(oi.quantity) AS items_sold,
+			SUM(oi.quantity * COALESCE(oi.unit_cost, sc.unit_cost, 0)) AS cogs
+		FROM order_items oi
+		LEFT JOIN sku_costs sc
+			ON sc.organization_id = oi.organization_id AND sc.sku = oi.sku AND sc.currency = o.currency
+		WHERE oi.order_id = o.id
+	) i ON true
+`
+
+// RebuildDaily replaces the summaries of the given dates with fresh totals from
+// the orders table. Revenue, costs and items only count orders whose status
+// counts as revenue (see entity.OrderStatus.IsRevenue).
+func (r *SalesSummaryRepository) RebuildDaily(ctx context.Context, orgID uuid.UUID, platform entity.Platform, dates []time.Time) error {
+	if len(dates) == 0 {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return rebuildDailySummaries(tx, orgID, platform, dates)
+	})
+}
+
+// RebuildForSKUs rebuilds the summaries of every day with orders containing one of the SKUs
+func (r *SalesSummaryRepository) RebuildForSKUs(ctx context.Context, orgID uuid.UUID, skus []string) error {
+	const batchSize = 1000
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		dates := make(map[entity.Platform]map[time.Time]bool)
+		for i := 0; i < len(skus); i += batchSize {
+			end := i + batchSize
+			if end > len(skus) {
+				end = len(skus)
+			}
+
+			var rows []struct {
+				Platform  entity.Platform
+				OrderDate time.Time
+			}
+			if err := tx.Raw(`
+				SELECT DISTINCT o.platform, o.order_date
+				FROM orders o
+				JOIN order_items oi ON oi.order_id = o.id
+				WHERE o.organization_id = ? AND oi.sku IN ?
+			`, orgID, skus[i:end]).Scan(&rows).Error; err != nil {
+				return err
+			}
+
+			for _, row := range rows {
+				if dates[row.Platform] == nil {
+					dates[row.Platform] = make(map[time.Time]bool)
+				}
+				dates[row.Platform][row.OrderDate] = true
+			}
+		}
+
+		for platform, days := range dates {
+			list := make([]time.Time, 0, len(days))
+			for day := range days {
+				list = append(list, day)
+			}
+			if err := rebuildDailySummaries(tx, orgID, platform, list); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func rebuildDailySummaries(tx *gorm.DB, orgID uuid.UUID, platform entity.Platform, dates []time.Time) error {
+	if err := tx.Exec(`
+		DELETE FROM sales_summary_daily
+		WHERE organization_id = ? AND platform = ? AND summary_date IN ?
+	`, orgID, platform, dates).Error; err != nil {
+		return err
+	}
+
+	return tx.Exec(`
+		INSERT INTO sales_summary_daily (
+			organization_id, shopee_shop_id, platform, summary_date,
+			total_orders, confirmed_orders, cancelled_orders,
+			gross_revenue, net_revenue, total_discounts, shipping_collected,
+			total_cogs, commission_fees, service_fees, estimated_profit,
+			total_items_sold, currency, last_synced_at
+		)
+		SELECT
+			o.organization_id, o.shopee_shop_id, o.platform, o.order_date,
+			COUNT(*),
+			COUNT(*) FILTER (WHERE o.status IN ('confirmed', 'shipped', 'delivered')),
+			COUNT(*) FILTER (WHERE o.status = 'cancelled'),
+			COALESCE(SUM(o.subtotal) FILTER (WHERE o.status IN ('confirmed', 'shipped', 'delivered')), 0),
+			COALESCE(SUM(o.total_amount) FILTER (WHERE o.status IN ('confirmed', 'shipped', 'delivered')), 0),
+			COALESCE(SUM(COALESCE(o.discount_amount, 0) + COALESCE(o.voucher_amount, 0))
+				FILTER (WHERE o.status IN ('confirmed', 'shipped', 'delivered')), 0),
+			COALESCE(SUM(COALESCE(o.shipping_fee, 0)) FILTER (WHERE o.status IN ('confirmed', 'shipped', 'delivered')), 0),
+			COALESCE(SUM(i.cogs) FILTER (WHERE o.status IN ('confirmed', 'shipped', 'delivered')), 0),
+			COALESCE(SUM(COALESCE(o.commission_fee, 0)) FILTER (WHERE o.status IN ('confirmed', 'shipped', 'delivered')), 0),
+			COALESCE(SUM(COALESCE(o.service_fee, 0) + COALESCE(o.transaction_fee, 0))
+				FILTER (WHERE o.status IN ('confirmed', 'shipped', 'delivered')), 0),
+			COALESCE(SUM(
+				o.total_amount - COALESCE(o.shipping_fee, 0) - COALESCE(i.cogs, 0)
+				- COALESCE(o.commission_fee, 0) - COALESCE(o.service_fee, 0) - COALESCE(o.transaction_fee, 0)
+			) FILTER (WHERE o.status IN ('confirmed', 'shipped', 'delivered')), 0),
+			COALESCE(SUM(i.items_sold) FILTER (WHERE o.status IN ('confirmed', 'shipped', 'delivered')), 0),
+			o.currency,
+			NOW()
+		FROM orders o
+		`+orderItemCostsLateral+`
+		WHERE o.organization_id = ? AND o.platform = ? AND o.order_date IN ?
+		GROUP BY o.organization_id, o.shopee_shop_id, o.platform, o.order_date, o.currency
+	`, orgID, platform, dates).Error
+}
+
+func (r *SalesSummaryRepository) List(ctx context.Context, orgID uuid.UUID, dateRange entity.DateRange) ([]entity.SalesSummaryDaily, error) {
+	var summaries []entity.SalesSummaryDaily
+	if err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND summary_date BETWEEN ? AND ?", orgID, dateRange.StartDate, dateRange.EndDate).
+		Order("summary_date, platform").
+		Find(&summaries).Error; err != nil {
+		return nil, err
+	}
+	return summaries, nil
+}
+
+var _ repository.SalesSummaryRepository = (*SalesSummaryRepository)(nil)
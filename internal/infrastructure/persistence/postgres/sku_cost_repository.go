@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm/clause"
+)
+
+// SKUCostRepository implements repository.SKUCostRepository
+type SKUCostRepository struct {
+	db *Database
+}
+
+// NewSKUCostRepository creates a new SKU cost repository
+func NewSKUCostRepository(db *Database) *SKUCostRepository {
+	return &SKUCostRepository{db: db}
+}
+
+func (r *SKUCostRepository) BulkUpsert(ctx context.Context, costs []entity.SKUCost) error {
+	if len(costs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "organization_id"}, {Name: "sku"}, {Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"unit_cost", "updated_at"}),
+	}).CreateInBatches(costs, 500).Error
+}
+
+func (r *SKUCostRepository) List(ctx context.Context, orgID uuid.UUID) ([]entity.SKUCost, error) {
+	var costs []entity.SKUCost
+	if err := r.db.WithContext(ctx).
+		Where("organization_id = ?", orgID).
+		Order("sku, currency").
+		Find(&costs).Error; err != nil {
+		return nil, err
+	}
+	return costs, nil
+}
+
+var _ repository.SKUCostRepository = (*SKUCostRepository)(nil)
@@ -0,0 +1,116 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+// SubscriptionRepository implements repository.SubscriptionRepository
+type SubscriptionRepository struct {
+	db *Database
+}
+
+// NewSubscriptionRepository creates a new subscription repository
+func NewSubscriptionRepository(db *Database) *SubscriptionRepository {
+	return &SubscriptionRepository{db: db}
+}
+
+func (r *SubscriptionRepository) Create(ctx context.Context, sub *entity.Subscription) error {
+	return r.db.WithContext(ctx).Create(sub).Error
+}
+
+func (r *SubscriptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Subscription, error) {
+	return r.first(ctx, "id = ?", id)
+}
+
+func (r *SubscriptionRepository) GetByOrganization(ctx context.Context, orgID uuid.UUID) (*entity.Subscription, error) {
+	return r.first(ctx, "organization_id = ?", orgID)
+}
+
+func (r *SubscriptionRepository) GetByStripeCustomerID(ctx context.Context, customerID string) (*entity.Subscription, error) {
+	return r.first(ctx, "stripe_customer_id = ?", customerID)
+}
+
+func (r *SubscriptionRepository) GetByStripeSubscriptionID(ctx context.Context, subscriptionID string) (*entity.Subscription, error) {
+	return r.first(ctx, "stripe_subscription_id = ?", subscriptionID)
+}
+
+func (r *SubscriptionRepository) first(ctx context.Context, query string, args ...interface{}) (*entity.Subscription, error) {
+	var sub entity.Subscription
+	if err := r.db.WithContext(ctx).Where(query, args...).First(&sub).Error; err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *SubscriptionRepository) Update(ctx context.Context, sub *entity.Subscription) error {
+	return r.db.WithContext(ctx).Save(sub).Error
+}
+
+func (r *SubscriptionRepository) UpdateStatus(ctx context.Context, id uuid.UUID, status entity.SubscriptionStatus) error {
+	return r.update(ctx, id, map[string]interface{}{"status": status})
+}
+
+func (r *SubscriptionRepository) UpdatePlan(ctx context.Context, id uuid.UUID, tier entity.PlanTier, cycle entity.BillingCycle) error {
+	return r.update(ctx, id, map[string]interface{}{
+		"plan_tier":     tier,
+		"billing_cycle": cycle,
+	})
+}
+
+func (r *SubscriptionRepository) RecordPayment(ctx context.Context, id uuid.UUID, amount float64, paidAt time.Time) error {
+	return r.update(ctx, id, map[string]interface{}{
+		"last_payment_at":     paidAt,
+		"last_payment_amount": decimal.NewFromFloat(amount),
+	})
+}
+
+func (r *SubscriptionRepository) IncrementPaymentFails(ctx context.Context, id uuid.UUID) error {
+	return r.update(ctx, id, map[string]interface{}{
+		"payment_fail_count": gorm.Expr("payment_fail_count + 1"),
+	})
+}
+
+func (r *SubscriptionRepository) ResetPaymentFails(ctx context.Context, id uuid.UUID) error {
+	return r.update(ctx, id, map[string]interface{}{"payment_fail_count": 0})
+}
+
+func (r *SubscriptionRepository) update(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.Subscription{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+func (r *SubscriptionRepository) ListExpiring(ctx context.Context, withinDays int) ([]entity.Subscription, error) {
+	now := time.Now()
+	var subs []entity.Subscription
+	if err := r.db.WithContext(ctx).
+		Where("status IN ? AND current_period_end BETWEEN ? AND ?",
+			[]entity.SubscriptionStatus{entity.SubscriptionStatusActive, entity.SubscriptionStatusTrialing},
+			now, now.AddDate(0, 0, withinDays)).
+		Order("current_period_end").
+		Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *SubscriptionRepository) ListPastDue(ctx context.Context) ([]entity.Subscription, error) {
+	var subs []entity.Subscription
+	if err := r.db.WithContext(ctx).
+		Where("status = ?", entity.SubscriptionStatusPastDue).
+		Order("updated_at").
+		Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+var _ repository.SubscriptionRepository = (*SubscriptionRepository)(nil)
@@ -0,0 +1,670 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// An account is due a little before a full interval has passed, so a run
+	// that finished late does not make it skip the next slot
+	hourlySyncDueAfter = 50 * time.Minute
+	dailySyncDueAfter  = 20 * time.Hour
+
+	// Failures in a row after which an account is reported unhealthy
+	unhealthyConsecutiveFailures = 3
+
+	syncJobLeaseExpiredMessage = "sync job lease expired on every attempt"
+)
+
+// SyncStateRepository implements repository.SyncStateRepository. States are
+// keyed by connected account: every Update method takes the connected account ID.
+type SyncStateRepository struct {
+	db *Database
+}
+
+// NewSyncStateRepository creates a new sync state repository
+func NewSyncStateRepository(db *Database) *SyncStateRepository {
+	return &SyncStateRepository{db: db}
+}
+
+func (r *SyncStateRepository) Create(ctx context.Context, state *entity.SyncState) error {
+	return r.db.WithContext(ctx).Create(state).Error
+}
+
+func (r *SyncStateRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.SyncState, error) {
+	var state entity.SyncState
+	if err := r.db.WithContext(ctx).First(&state, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+// GetByConnectedAccount gives an account that never synced an empty state
+func (r *SyncStateRepository) GetByConnectedAccount(ctx context.Context, connectedAccountID uuid.UUID) (*entity.SyncState, error) {
+	db := r.db.WithContext(ctx)
+	if err := ensureSyncStates(db, "id = ?", connectedAccountID); err != nil {
+		return nil, err
+	}
+
+	var state entity.SyncState
+	if err := db.First(&state, "connected_account_id = ?", connectedAccountID).Error; err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *SyncStateRepository) Update(ctx context.Context, state *entity.SyncState) error {
+	return r.db.WithContext(ctx).Save(state).Error
+}
+
+func (r *SyncStateRepository) ListByOrganization(ctx context.Context, orgID uuid.UUID) ([]entity.SyncState, error) {
+	db := r.db.WithContext(ctx)
+	if err := ensureSyncStates(db, "organization_id = ?", orgID); err != nil {
+		return nil, err
+	}
+
+	var states []entity.SyncState
+	if err := db.Where("organization_id = ?", orgID).Order("platform").Find(&states).Error; err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+func (r *SyncStateRepository) ListDueForHourlySync(ctx context.Context, platform entity.Platform) ([]entity.SyncState, error) {
+	return r.listDue(ctx, platform, "sync_states.last_hourly_sync IS NULL OR sync_states.last_hourly_sync < ?",
+		time.Now().Add(-hourlySyncDueAfter))
+}
+
+// ListDueForDailySync is asked at the platform's daily sync hour, so an account
+// is due unless a daily sync already ran since the previous day's slot
+func (r *SyncStateRepository) ListDueForDailySync(ctx context.Context, platform entity.Platform, _ int) ([]entity.SyncState, error) {
+	return r.listDue(ctx, platform, "sync_states.last_daily_sync IS NULL OR sync_states.last_daily_sync < ?",
+		time.Now().Add(-dailySyncDueAfter))
+}
+
+// listDue lists the states of the platform's active accounts matching due.
+// Accounts connected since the last run get a state first, so they are
+// scheduled too.
+func (r *SyncStateRepository) listDue(ctx context.Context, platform entity.Platform, due string, args ...interface{}) ([]entity.SyncState, error) {
+	db := r.db.WithContext(ctx)
+	if err := ensureSyncStates(db, "status = ? AND platform = ?", entity.AccountStatusActive, platform); err != nil {
+		return nil, err
+	}
+
+	var states []entity.SyncState
+	if err := db.Select("sync_states.*").
+		Joins("JOIN connected_accounts ON connected_accounts.id = sync_states.connected_account_id").
+		Where("sync_states.platform = ? AND connected_accounts.status = ?", platform, entity.AccountStatusActive).
+		Where(due, args...).
+		Find(&states).Error; err != nil {
+		return nil, err
+	}
+	return states, nil
+}
+
+// ensureSyncStates creates an empty state for the connected accounts matching
+// the condition that have none
+func ensureSyncStates(db *gorm.DB, condition string, args ...interface{}) error {
+	return db.Exec(`
+		INSERT INTO sync_states (connected_account_id, organization_id, platform)
+		SELECT id, organization_id, platform FROM connected_accounts
+		WHERE `+condition+`
+		ON CONFLICT (connected_account_id) DO NOTHING`,
+		args...,
+	).Error
+}
+
+func (r *SyncStateRepository) UpdateSyncStarted(ctx context.Context, id uuid.UUID, syncJobID uuid.UUID) error {
+	db := r.db.WithContext(ctx)
+	if err := ensureSyncStates(db, "id = ?", id); err != nil {
+		return err
+	}
+	return r.update(db, id, map[string]interface{}{
+		"current_sync_id":     syncJobID,
+		"current_sync_status": entity.SyncStatusRunning,
+		"is_syncing":          true,
+		"total_syncs":         gorm.Expr("total_syncs + 1"),
+	})
+}
+
+// UpdateSyncCompleted records the run under its sync type: daily and initial
+// runs sync the whole account, so they count as full syncs
+func (r *SyncStateRepository) UpdateSyncCompleted(ctx context.Context, id uuid.UUID, syncType entity.SyncType) error {
+	now := time.Now()
+	updates := map[string]interface{}{
+		"current_sync_status":  entity.SyncStatusCompleted,
+		"is_syncing":           false,
+		"successful_syncs":     gorm.Expr("successful_syncs + 1"),
+		"consecutive_failures": 0,
+		"last_metrics_sync":    now,
+	}
+	switch syncType {
+	case entity.SyncTypeHourly:
+		updates["last_hourly_sync"] = now
+	case entity.SyncTypeDaily:
+		updates["last_daily_sync"] = now
+		updates["last_full_sync"] = now
+	case entity.SyncTypeInitial:
+		updates["last_full_sync"] = now
+	}
+	return r.update(r.db.WithContext(ctx), id, updates)
+}
+
+func (r *SyncStateRepository) UpdateSyncStopped(ctx context.Context, id uuid.UUID, status entity.SyncStatus) error {
+	return r.update(r.db.WithContext(ctx), id, map[string]interface{}{
+		"current_sync_status": status,
+		"is_syncing":          false,
+	})
+}
+
+func (r *SyncStateRepository) UpdateSyncFailed(ctx context.Context, id uuid.UUID, err string) error {
+	return r.update(r.db.WithContext(ctx), id, map[string]interface{}{
+		"current_sync_status":  entity.SyncStatusFailed,
+		"is_syncing":           false,
+		"failed_syncs":         gorm.Expr("failed_syncs + 1"),
+		"consecutive_failures": gorm.Expr("consecutive_failures + 1"),
+		"last_error":           err,
+		"last_error_at":        time.Now(),
+	})
+}
+
+func (r *SyncStateRepository) UpdateRateLimit(ctx context.Context, id uuid.UUID, resetAt *time.Time) error {
+	updates := map[string]interface{}{"rate_limit_reset_at": resetAt}
+	if resetAt != nil {
+		updates["rate_limit_hits"] = gorm.Expr("rate_limit_hits + 1")
+	}
+	return r.update(r.db.WithContext(ctx), id, updates)
+}
+
+func (r *SyncStateRepository) update(db *gorm.DB, connectedAccountID uuid.UUID, updates map[string]interface{}) error {
+	return db.Model(&entity.SyncState{}).
+		Where("connected_account_id = ?", connectedAccountID).
+		Updates(updates).Error
+}
+
+func (r *SyncStateRepository) GetDataFreshness(ctx context.Context, orgID uuid.UUID) ([]entity.DataFreshnessInfo, error) {
+	states, err := r.ListByOrganization(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	var accounts []entity.ConnectedAccount
+	if err := r.db.WithContext(ctx).
+		Select("id", "platform_account_name").
+		Where("organization_id = ?", orgID).
+		Find(&accounts).Error; err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID]string, len(accounts))
+	for _, account := range accounts {
+		names[account.ID] = account.PlatformAccountName
+	}
+
+	infos := make([]entity.DataFreshnessInfo, 0, len(states))
+	for _, state := range states {
+		lastSynced := state.LastMetricsSync
+		if state.LastHourlySync != nil && (lastSynced == nil || state.LastHourlySync.After(*lastSynced)) {
+			lastSynced = state.LastHourlySync
+		}
+		infos = append(infos, entity.DataFreshnessInfo{
+			Platform:             state.Platform,
+			ConnectedAccountID:   state.ConnectedAccountID,
+			ConnectedAccountName: names[state.ConnectedAccountID],
+			FreshnessStatus:      state.GetFreshnessStatus(),
+			MinutesSinceLastSync: state.CalculateDataFreshness(),
+			LastSyncedAt:         lastSynced,
+			IsSyncing:            state.IsSyncing,
+			NextScheduledSync:    state.NextScheduledSync,
+			IsHealthy:            state.ConsecutiveFailures < unhealthyConsecutiveFailures,
+			ConsecutiveFailures:  state.ConsecutiveFailures,
+			LastError:            state.LastError,
+			IsRateLimited:        state.IsRateLimited(),
+			RateLimitResetAt:     state.RateLimitResetAt,
+		})
+	}
+	return infos, nil
+}
+
+var _ repository.SyncStateRepository = (*SyncStateRepository)(nil)
+
+// SyncJobRepository implements repository.SyncJobRepository
+type SyncJobRepository struct {
+	db *Database
+}
+
+// NewSyncJobRepository creates a new sync job repository
+func NewSyncJobRepository(db *Database) *SyncJobRepository {
+	return &SyncJobRepository{db: db}
+}
+
+// Create skips a scheduled job that the scheduler of another process already
+// queued (see idx_sync_jobs_scheduled_once)
+func (r *SyncJobRepository) Create(ctx context.Context, job *entity.SyncJob) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(job).Error
+}
+
+func (r *SyncJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.SyncJob, error) {
+	var job entity.SyncJob
+	if err := r.db.WithContext(ctx).First(&job, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *SyncJobRepository) Update(ctx context.Context, job *entity.SyncJob) error {
+	return r.db.WithContext(ctx).Save(job).Error
+}
+
+func (r *SyncJobRepository) ListPending(ctx context.Context, limit int) ([]entity.SyncJob, error) {
+	var jobs []entity.SyncJob
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND scheduled_at <= ?", entity.SyncStatusPending, time.Now()).
+		Order("priority DESC, scheduled_at").
+		Limit(limit).
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *SyncJobRepository) ListByConnectedAccount(ctx context.Context, connectedAccountID uuid.UUID, limit int) ([]entity.SyncJob, error) {
+	return r.listRecent(ctx, limit, "connected_account_id = ?", connectedAccountID)
+}
+
+func (r *SyncJobRepository) ListRecent(ctx context.Context, orgID uuid.UUID, limit int) ([]entity.SyncJob, error) {
+	return r.listRecent(ctx, limit, "organization_id = ?", orgID)
+}
+
+func (r *SyncJobRepository) listRecent(ctx context.Context, limit int, query string, args ...interface{}) ([]entity.SyncJob, error) {
+	var jobs []entity.SyncJob
+	if err := r.db.WithContext(ctx).
+		Where(query, args...).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *SyncJobRepository) GetRunningJobs(ctx context.Context) ([]entity.SyncJob, error) {
+	var jobs []entity.SyncJob
+	if err := r.db.WithContext(ctx).
+		Where("status = ?", entity.SyncStatusRunning).
+		Order("started_at").
+		Find(&jobs).Error; err != nil {
+		return nil, err
+	}
+	return jobs, nil
+}
+
+func (r *SyncJobRepository) ClaimJob(ctx context.Context, jobID uuid.UUID, workerID string) error {
+	now := time.Now()
+	result := r.db.WithContext(ctx).
+		Model(&entity.SyncJob{}).
+		Where("id = ? AND status = ?", jobID, entity.SyncStatusPending).
+		Updates(map[string]interface{}{
+			"status":       entity.SyncStatusRunning,
+			"worker_id":    workerID,
+			"started_at":   now,
+			"heartbeat_at": now,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("sync job %s is not pending", jobID)
+	}
+	return nil
+}
+
+// ClaimNext uses SKIP LOCKED so concurrent workers never claim the same job
+func (r *SyncJobRepository) ClaimNext(ctx context.Context, workerID string, leaseUntil time.Time) (*entity.SyncJob, error) {
+	var jobs []entity.SyncJob
+	if err := r.db.WithContext(ctx).Raw(`
+		UPDATE sync_jobs
+		SET status = ?, worker_id = ?, lease_expires_at = ?, heartbeat_at = NOW(),
+			started_at = COALESCE(started_at, NOW()), updated_at = NOW()
+		WHERE id = (
+			SELECT id FROM sync_jobs
+			WHERE status = ? OR (status = ? AND (retry_after IS NULL OR retry_after <= NOW()))
+			ORDER BY priority DESC, scheduled_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING *`,
+		entity.SyncStatusRunning, workerID, leaseUntil,
+		entity.SyncStatusPending, entity.SyncStatusRetrying,
+	).Scan(&jobs).Error; err != nil {
+		return nil, err
+	}
+
+	if len(jobs) == 0 {
+		return nil, nil
+	}
+	return &jobs[0], nil
+}
+
+func (r *SyncJobRepository) ExtendLease(ctx context.Context, id uuid.UUID, workerID string, leaseUntil time.Time) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.SyncJob{}).
+		Where("id = ? AND status = ? AND worker_id = ?", id, entity.SyncStatusRunning, workerID).
+		Updates(map[string]interface{}{
+			"lease_expires_at": leaseUntil,
+			"heartbeat_at":     time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *SyncJobRepository) SetStatusIf(ctx context.Context, id uuid.UUID, status entity.SyncStatus, from ...entity.SyncStatus) (bool, error) {
+	if len(from) == 0 {
+		return false, nil
+	}
+
+	updates := map[string]interface{}{"status": status}
+	if status != entity.SyncStatusRunning {
+		releaseLease(updates)
+	}
+	result := r.db.WithContext(ctx).
+		Model(&entity.SyncJob{}).
+		Where("id = ? AND status IN ?", id, from).
+		Updates(updates)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *SyncJobRepository) SaveCheckpoint(ctx context.Context, id uuid.UUID, checkpoint entity.JSONMap, recordsProcessed, recordsFailed int) error {
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return err
+	}
+	return r.update(ctx, id, map[string]interface{}{
+		"checkpoint":        gorm.Expr("?::jsonb", string(data)),
+		"records_processed": recordsProcessed,
+		"records_failed":    recordsFailed,
+	})
+}
+
+// RequeueExpired runs in one transaction. The sync states of jobs that are
+// failed are released too, or their accounts would stay marked as syncing.
+func (r *SyncJobRepository) RequeueExpired(ctx context.Context, now time.Time) (int64, error) {
+	var released int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec(`
+			UPDATE sync_states
+			SET is_syncing = false, current_sync_status = ?, updated_at = NOW()
+			WHERE current_sync_id IN (
+				SELECT id FROM sync_jobs
+				WHERE status = ? AND lease_expires_at < ? AND retry_count >= max_retries
+			)`,
+			entity.SyncStatusFailed, entity.SyncStatusRunning, now,
+		).Error; err != nil {
+			return err
+		}
+
+		failed := tx.Model(&entity.SyncJob{}).
+			Where("status = ? AND lease_expires_at < ? AND retry_count >= max_retries", entity.SyncStatusRunning, now).
+			Updates(releaseLease(map[string]interface{}{
+				"status":        entity.SyncStatusFailed,
+				"error_message": syncJobLeaseExpiredMessage,
+				"completed_at":  now,
+			}))
+		if failed.Error != nil {
+			return failed.Error
+		}
+
+		requeued := tx.Model(&entity.SyncJob{}).
+			Where("status = ? AND lease_expires_at < ? AND retry_count < max_retries", entity.SyncStatusRunning, now).
+			Updates(releaseLease(map[string]interface{}{
+				"status":      entity.SyncStatusPending,
+				"retry_count": gorm.Expr("retry_count + 1"),
+			}))
+		released = failed.RowsAffected + requeued.RowsAffected
+		return requeued.Error
+	})
+	return released, err
+}
+
+func (r *SyncJobRepository) UpdateProgress(ctx context.Context, id uuid.UUID, percent int, message string) error {
+	return r.update(ctx, id, map[string]interface{}{
+		"progress_percent": percent,
+		"progress_message": message,
+	})
+}
+
+func (r *SyncJobRepository) MarkCompleted(ctx context.Context, id uuid.UUID, recordsProcessed, recordsFailed int) error {
+	return r.update(ctx, id, finishJob(map[string]interface{}{
+		"status":            entity.SyncStatusCompleted,
+		"progress_percent":  100,
+		"records_processed": recordsProcessed,
+		"records_failed":    recordsFailed,
+	}))
+}
+
+func (r *SyncJobRepository) MarkFailed(ctx context.Context, id uuid.UUID, err, errorCode string) error {
+	return r.update(ctx, id, finishJob(map[string]interface{}{
+		"status":        entity.SyncStatusFailed,
+		"error_message": err,
+		"error_code":    errorCode,
+	}))
+}
+
+// ScheduleRetry only retries a job that is still running. The job runner and
+// the scheduler both report a failed run, so the second report is a no-op
+// instead of counting the run twice.
+func (r *SyncJobRepository) ScheduleRetry(ctx context.Context, id uuid.UUID, retryAfter time.Time, lastError string) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.SyncJob{}).
+		Where("id = ? AND status = ?", id, entity.SyncStatusRunning).
+		Updates(releaseLease(map[string]interface{}{
+			"status":           entity.SyncStatusRetrying,
+			"retry_after":      retryAfter,
+			"retry_count":      gorm.Expr("retry_count + 1"),
+			"last_retry_error": lastError,
+		})).Error
+}
+
+func (r *SyncJobRepository) update(ctx context.Context, id uuid.UUID, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.SyncJob{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+// releaseLease adds the updates that end a job's lease
+func releaseLease(updates map[string]interface{}) map[string]interface{} {
+	updates["worker_id"] = ""
+	updates["lease_expires_at"] = nil
+	return updates
+}
+
+// finishJob adds the updates that end a job's run
+func finishJob(updates map[string]interface{}) map[string]interface{} {
+	updates["completed_at"] = gorm.Expr("NOW()")
+	updates["duration_seconds"] = gorm.Expr("EXTRACT(EPOCH FROM NOW() - started_at)::integer")
+	return releaseLease(updates)
+}
+
+func (r *SyncJobRepository) DeleteOldJobs(ctx context.Context, olderThanDays int) error {
+	return r.db.WithContext(ctx).
+		Where("created_at < ? AND status IN ?", time.Now().AddDate(0, 0, -olderThanDays),
+			[]entity.SyncStatus{entity.SyncStatusCompleted, entity.SyncStatusFailed, entity.SyncStatusCancelled}).
+		Delete(&entity.SyncJob{}).Error
+}
+
+func (r *SyncJobRepository) GetJobStats(ctx context.Context, orgID uuid.UUID, dateRange entity.DateRange) (*repository.SyncJobStats, error) {
+	var stats repository.SyncJobStats
+	if err := r.db.WithContext(ctx).Raw(`
+		SELECT
+			COUNT(*) AS total_jobs,
+			COUNT(*) FILTER (WHERE status = ?) AS pending_jobs,
+			COUNT(*) FILTER (WHERE status = ?) AS running_jobs,
+			COUNT(*) FILTER (WHERE status = ?) AS completed_jobs,
+			COUNT(*) FILTER (WHERE status = ?) AS failed_jobs,
+			COALESCE(AVG(duration_seconds), 0)::integer AS average_duration
+		FROM sync_jobs
+		WHERE organization_id = ? AND created_at BETWEEN ? AND ?`,
+		entity.SyncStatusPending, entity.SyncStatusRunning, entity.SyncStatusCompleted, entity.SyncStatusFailed,
+		orgID, dateRange.StartDate, dateRange.EndDate,
+	).Scan(&stats).Error; err != nil {
+		return nil, err
+	}
+	return &stats, nil
+}
+
+var _ repository.SyncJobRepository = (*SyncJobRepository)(nil)
+
+// RetryQueueRepository implements repository.RetryQueueRepository
+type RetryQueueRepository struct {
+	db *Database
+}
+
+// NewRetryQueueRepository creates a new retry queue repository
+func NewRetryQueueRepository(db *Database) *RetryQueueRepository {
+	return &RetryQueueRepository{db: db}
+}
+
+func (r *RetryQueueRepository) Enqueue(ctx context.Context, entry *entity.RetryQueueEntry) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *RetryQueueRepository) Dequeue(ctx context.Context, limit int) ([]entity.RetryQueueEntry, error) {
+	var entries []entity.RetryQueueEntry
+	if err := r.db.WithContext(ctx).
+		Where("retry_at <= ?", time.Now()).
+		Order("retry_at").
+		Limit(limit).
+		Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *RetryQueueRepository) Remove(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entity.RetryQueueEntry{}, "id = ?", id).Error
+}
+
+func (r *RetryQueueRepository) GetByJobID(ctx context.Context, jobID uuid.UUID) (*entity.RetryQueueEntry, error) {
+	var entry entity.RetryQueueEntry
+	if err := r.db.WithContext(ctx).First(&entry, "sync_job_id = ?", jobID).Error; err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *RetryQueueRepository) UpdateRetryCount(ctx context.Context, id uuid.UUID, count int, nextRetry time.Time, lastError string) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.RetryQueueEntry{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"retry_count": count,
+			"retry_at":    nextRetry,
+			"last_error":  lastError,
+		}).Error
+}
+
+// CleanupExpired removes entries that are out of retries
+func (r *RetryQueueRepository) CleanupExpired(ctx context.Context) (int, error) {
+	result := r.db.WithContext(ctx).Where("retry_count >= max_retries").Delete(&entity.RetryQueueEntry{})
+	return int(result.RowsAffected), result.Error
+}
+
+var _ repository.RetryQueueRepository = (*RetryQueueRepository)(nil)
+
+// SyncErrorLogRepository implements repository.SyncErrorLogRepository
+type SyncErrorLogRepository struct {
+	db *Database
+}
+
+// NewSyncErrorLogRepository creates a new sync error log repository
+func NewSyncErrorLogRepository(db *Database) *SyncErrorLogRepository {
+	return &SyncErrorLogRepository{db: db}
+}
+
+func (r *SyncErrorLogRepository) Create(ctx context.Context, log *entity.SyncErrorLog) error {
+	return r.db.WithContext(ctx).Create(log).Error
+}
+
+func (r *SyncErrorLogRepository) ListByJob(ctx context.Context, jobID uuid.UUID) ([]entity.SyncErrorLog, error) {
+	var logs []entity.SyncErrorLog
+	if err := r.db.WithContext(ctx).
+		Where("sync_job_id = ?", jobID).
+		Order("created_at").
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+func (r *SyncErrorLogRepository) ListByAccount(ctx context.Context, connectedAccountID uuid.UUID, limit int) ([]entity.SyncErrorLog, error) {
+	var logs []entity.SyncErrorLog
+	if err := r.db.WithContext(ctx).
+		Where("connected_account_id = ?", connectedAccountID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+// ListRecent finds the organization's logs through their connected accounts
+func (r *SyncErrorLogRepository) ListRecent(ctx context.Context, orgID uuid.UUID, limit int) ([]entity.SyncErrorLog, error) {
+	var logs []entity.SyncErrorLog
+	if err := r.db.WithContext(ctx).
+		Select("sync_error_logs.*").
+		Joins("JOIN connected_accounts ON connected_accounts.id = sync_error_logs.connected_account_id").
+		Where("connected_accounts.organization_id = ?", orgID).
+		Order("sync_error_logs.created_at DESC").
+		Limit(limit).
+		Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+func (r *SyncErrorLogRepository) DeleteOld(ctx context.Context, olderThanDays int) error {
+	return r.db.WithContext(ctx).
+		Where("created_at < ?", time.Now().AddDate(0, 0, -olderThanDays)).
+		Delete(&entity.SyncErrorLog{}).Error
+}
+
+// GetErrorStats counts the organization's errors by error type
+func (r *SyncErrorLogRepository) GetErrorStats(ctx context.Context, orgID uuid.UUID, dateRange entity.DateRange) (map[string]int, error) {
+	var rows []struct {
+		ErrorType string
+		Count     int
+	}
+	if err := r.db.WithContext(ctx).
+		Model(&entity.SyncErrorLog{}).
+		Select("sync_error_logs.error_type, COUNT(*) AS count").
+		Joins("JOIN connected_accounts ON connected_accounts.id = sync_error_logs.connected_account_id").
+		Where("connected_accounts.organization_id = ? AND sync_error_logs.created_at BETWEEN ? AND ?",
+			orgID, dateRange.StartDate, dateRange.EndDate).
+		Group("sync_error_logs.error_type").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	stats := make(map[string]int, len(rows))
+	for _, row := range rows {
+		stats[row.ErrorType] = row.Count
+	}
+	return stats, nil
+}
+
+var _ repository.SyncErrorLogRepository = (*SyncErrorLogRepository)(nil)
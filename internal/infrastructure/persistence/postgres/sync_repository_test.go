@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDatabase connects to TEST_DATABASE_DSN, a scratch database with the
+// migrations applied. Tests that need it are skipped when it is not set.
+func openTestDatabase(t *testing.T) *Database {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database instance: %v", err)
+	}
+	t.Cleanup(func() { sqlDB.Close() })
+	return &Database{db}
+}
+
+func TestSyncJobRepository_ConcurrentClaimsNeverShareAJob(t *testing.T) {
+	db := openTestDatabase(t)
+	ctx := context.Background()
+
+	orgID := uuid.New()
+	accountID := uuid.New()
+	if err := db.Exec(`INSERT INTO organizations (id, name, slug) VALUES (?, 'Claim test', ?)`,
+		orgID, "claim-test-"+orgID.String()).Error; err != nil {
+		t.Fatalf("failed to create organization: %v", err)
+	}
+	t.Cleanup(func() { db.Exec(`DELETE FROM organizations WHERE id = ?`, orgID) })
+	if err := db.Exec(`INSERT INTO connected_accounts (id, organization_id, platform, platform_account_id, access_token)
+		VALUES (?, ?, ?, 'claim-test', 'token')`, accountID, orgID, entity.PlatformMeta).Error; err != nil {
+		t.Fatalf("failed to create connected account: %v", err)
+	}
+
+	repo := NewSyncJobRepository(db)
+	const jobCount = 40
+	queued := make(map[uuid.UUID]bool, jobCount)
+	for i := 0; i < jobCount; i++ {
+		job := &entity.SyncJob{
+			BaseEntity:         entity.NewBaseEntity(),
+			OrganizationID:     orgID,
+			ConnectedAccountID: accountID,
+			Platform:           entity.PlatformMeta,
+			SyncType:           entity.SyncTypeManual,
+			SyncScope:          entity.SyncScopeMetrics,
+			Status:             entity.SyncStatusPending,
+			Priority:           entity.SyncJobPriorityManual,
+			ScheduledAt:        time.Now(),
+			MaxRetries:         3,
+		}
+		if err := repo.Create(ctx, job); err != nil {
+			t.Fatalf("failed to create job: %v", err)
+		}
+		queued[job.ID] = true
+	}
+
+	// Both claimers drain the queue at once; each claim runs in its own
+	// transaction, so only SKIP LOCKED keeps them apart
+	start := make(chan struct{})
+	claimed := make([][]uuid.UUID, 2)
+	errs := make([]error, 2)
+	var wg sync.WaitGroup
+	for w := range claimed {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			<-start
+			workerID := uuid.NewString()
+			for {
+				job, err := repo.ClaimNext(ctx, workerID, time.Now().Add(time.Minute))
+				if err != nil {
+					errs[w] = err
+					return
+				}
+				if job == nil {
+					return
+				}
+				if job.WorkerID != workerID || job.Status != entity.SyncStatusRunning {
+					t.Errorf("claimed job %s has worker %q and status %s", job.ID, job.WorkerID, job.Status)
+				}
+				claimed[w] = append(claimed[w], job.ID)
+			}
+		}(w)
+	}
+	close(start)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			t.Fatalf("ClaimNext failed: %v", err)
+		}
+	}
+
+	seen := make(map[uuid.UUID]int)
+	for w, ids := range claimed {
+		for _, id := range ids {
+			if prev, ok := seen[id]; ok {
+				t.Fatalf("job %s claimed by worker %d and worker %d", id, prev, w)
+			}
+			seen[id] = w
+		}
+	}
+	for id := range queued {
+		if _, ok := seen[id]; !ok {
+			t.Errorf("job %s was never claimed", id)
+		}
+	}
+}
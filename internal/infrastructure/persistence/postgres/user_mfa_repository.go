@@ -0,0 +1,85 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// UserMFARepository implements repository.UserMFARepository
+type UserMFARepository struct {
+	db *Database
+}
+
+// NewUserMFARepository creates a new user MFA repository
+func NewUserMFARepository(db *Database) *UserMFARepository {
+	return &UserMFARepository{db: db}
+}
+
+// GetByUserID returns nil without an error when the user has no second factor,
+// so callers can tell it apart from a failed lookup and fail closed on the latter
+func (r *UserMFARepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*entity.UserMFA, error) {
+	var mfa entity.UserMFA
+	if err := r.db.WithContext(ctx).First(&mfa, "user_id = ?", userID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &mfa, nil
+}
+
+func (r *UserMFARepository) Save(ctx context.Context, mfa *entity.UserMFA) error {
+	return r.db.WithContext(ctx).Save(mfa).Error
+}
+
+// UseStep only updates a factor whose last used step is still before the new
+// one, so two logins racing with the same code cannot both be accepted
+func (r *UserMFARepository) UseStep(ctx context.Context, mfa *entity.UserMFA) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.UserMFA{}).
+		Where("user_id = ? AND last_used_step < ?", mfa.UserID, mfa.LastUsedStep).
+		Updates(map[string]interface{}{
+			"last_used_step":  mfa.LastUsedStep,
+			"failed_attempts": 0,
+			"locked_until":    nil,
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// UseRecoveryCode only updates a factor that still holds the used code's hash
+func (r *UserMFARepository) UseRecoveryCode(ctx context.Context, mfa *entity.UserMFA, usedHash string) (bool, error) {
+	codes, err := json.Marshal(mfa.RecoveryCodes)
+	if err != nil {
+		return false, err
+	}
+	result := r.db.WithContext(ctx).
+		Model(&entity.UserMFA{}).
+		Where("user_id = ? AND jsonb_exists(recovery_codes, ?)", mfa.UserID, usedHash).
+		Updates(map[string]interface{}{
+			"recovery_codes":  gorm.Expr("?::jsonb", string(codes)),
+			"failed_attempts": 0,
+			"locked_until":    nil,
+			"updated_at":      time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *UserMFARepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entity.UserMFA{}, "user_id = ?", userID).Error
+}
+
+var _ repository.UserMFARepository = (*UserMFARepository)(nil)
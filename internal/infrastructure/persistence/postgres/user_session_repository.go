@@ -0,0 +1,96 @@
+package postgres
+
+import (
+	"context"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+// UserSessionRepository implements repository.UserSessionRepository
+type UserSessionRepository struct {
+	db *Database
+}
+
+// NewUserSessionRepository creates a new user session repository
+func NewUserSessionRepository(db *Database) *UserSessionRepository {
+	return &UserSessionRepository{db: db}
+}
+
+func (r *UserSessionRepository) Create(ctx context.Context, session *entity.UserSession) error {
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *UserSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.UserSession, error) {
+	var session entity.UserSession
+	if err := r.db.WithContext(ctx).First(&session, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *UserSessionRepository) GetByTokenID(ctx context.Context, tokenID string) (*entity.UserSession, error) {
+	var session entity.UserSession
+	if err := r.db.WithContext(ctx).First(&session, "current_token_id = ?", tokenID).Error; err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// Rotate compares and swaps the current token so that of two concurrent
+// exchanges of the same refresh token only one succeeds
+func (r *UserSessionRepository) Rotate(ctx context.Context, session *entity.UserSession, previousTokenID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.UserSession{}).
+		Where("id = ? AND current_token_id = ? AND revoked_at IS NULL", session.ID, previousTokenID).
+		Updates(map[string]interface{}{
+			"current_token_id":  session.CurrentTokenID,
+			"previous_token_id": session.PreviousTokenID,
+			"user_agent":        session.UserAgent,
+			"ip_address":        session.IPAddress,
+			"last_used_at":      session.LastUsedAt,
+			"rotated_at":        session.RotatedAt,
+			"expires_at":        session.ExpiresAt,
+			"updated_at":        time.Now(),
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *UserSessionRepository) Revoke(ctx context.Context, id uuid.UUID, reason string, revokedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&entity.UserSession{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{
+			"revoked_at":     revokedAt,
+			"revoked_reason": reason,
+			"updated_at":     time.Now(),
+		}).Error
+}
+
+func (r *UserSessionRepository) RevokeByUser(ctx context.Context, userID, exceptID uuid.UUID, reason string, revokedAt time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&entity.UserSession{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL AND expires_at > ?", userID, exceptID, revokedAt).
+		Updates(map[string]interface{}{
+			"revoked_at":     revokedAt,
+			"revoked_reason": reason,
+			"updated_at":     time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
+
+func (r *UserSessionRepository) ListActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]entity.UserSession, error) {
+	var sessions []entity.UserSession
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_used_at DESC").
+		Find(&sessions).Error
+	return sessions, err
+}
+
+var _ repository.UserSessionRepository = (*UserSessionRepository)(nil)
@@ -0,0 +1,152 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+// WebhookEndpointRepository implements repository.WebhookEndpointRepository
+type WebhookEndpointRepository struct {
+	db *Database
+}
+
+// NewWebhookEndpointRepository creates a new webhook endpoint repository
+func NewWebhookEndpointRepository(db *Database) *WebhookEndpointRepository {
+	return &WebhookEndpointRepository{db: db}
+}
+
+func (r *WebhookEndpointRepository) Create(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	return r.db.WithContext(ctx).Create(endpoint).Error
+}
+
+func (r *WebhookEndpointRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.WebhookEndpoint, error) {
+	var endpoint entity.WebhookEndpoint
+	if err := r.db.WithContext(ctx).First(&endpoint, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+func (r *WebhookEndpointRepository) Update(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	return r.db.WithContext(ctx).Save(endpoint).Error
+}
+
+func (r *WebhookEndpointRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&entity.WebhookEndpoint{}, "id = ?", id).Error
+}
+
+func (r *WebhookEndpointRepository) ListByOrganization(ctx context.Context, orgID uuid.UUID) ([]entity.WebhookEndpoint, error) {
+	var endpoints []entity.WebhookEndpoint
+	if err := r.db.WithContext(ctx).
+		Where("organization_id = ?", orgID).
+		Order("created_at ASC").
+		Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+func (r *WebhookEndpointRepository) ListSubscribed(ctx context.Context, orgID uuid.UUID, eventType entity.WebhookEventType) ([]entity.WebhookEndpoint, error) {
+	events, err := json.Marshal([]entity.WebhookEventType{eventType})
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []entity.WebhookEndpoint
+	if err := r.db.WithContext(ctx).
+		Where("organization_id = ? AND is_active = ? AND events @> ?::jsonb", orgID, true, string(events)).
+		Order("created_at ASC").
+		Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	return endpoints, nil
+}
+
+// WebhookDeliveryRepository implements repository.WebhookDeliveryRepository
+type WebhookDeliveryRepository struct {
+	db *Database
+}
+
+// NewWebhookDeliveryRepository creates a new webhook delivery repository
+func NewWebhookDeliveryRepository(db *Database) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+func (r *WebhookDeliveryRepository) Create(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Create(delivery).Error
+}
+
+func (r *WebhookDeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error) {
+	var delivery entity.WebhookDelivery
+	if err := r.db.WithContext(ctx).First(&delivery, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *WebhookDeliveryRepository) Update(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	return r.db.WithContext(ctx).Save(delivery).Error
+}
+
+// ClaimDue uses SKIP LOCKED so concurrent workers never claim the same delivery
+func (r *WebhookDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	if err := r.db.WithContext(ctx).Raw(`
+		UPDATE webhook_deliveries
+		SET next_attempt_at = ?, updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE status = ? AND next_attempt_at <= ?
+			ORDER BY next_attempt_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT ?
+		)
+		RETURNING *`,
+		now.Add(lease), entity.WebhookDeliveryPending, now, limit,
+	).Scan(&deliveries).Error; err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *WebhookDeliveryRepository) List(ctx context.Context, filter entity.WebhookDeliveryFilter) ([]entity.WebhookDelivery, int64, error) {
+	var deliveries []entity.WebhookDelivery
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entity.WebhookDelivery{}).
+		Where("organization_id = ?", filter.OrganizationID)
+
+	if filter.EndpointID != nil {
+		query = query.Where("endpoint_id = ?", *filter.EndpointID)
+	}
+	if filter.EventType != "" {
+		query = query.Where("event_type = ?", filter.EventType)
+	}
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Pagination != nil {
+		query = query.Offset(filter.Pagination.Offset()).Limit(filter.Pagination.PageSize)
+	}
+
+	if err := query.Order("created_at DESC").Find(&deliveries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, total, nil
+}
+
+var (
+	_ repository.WebhookEndpointRepository = (*WebhookEndpointRepository)(nil)
+	_ repository.WebhookDeliveryRepository = (*WebhookDeliveryRepository)(nil)
+)
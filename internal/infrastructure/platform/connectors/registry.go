@@ -0,0 +1,88 @@
+package connectors
+
+import (
+	"fmt"
+
+	"github.com/ads-aggregator/ads-aggregator/config"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/platform"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/platform/google"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/platform/lazada"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/platform/meta"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/platform/shopee"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/platform/tiktok"
+)
+
+// NewRegistry creates a registry holding a connector for every supported platform
+func NewRegistry(cfg *config.Config) *platform.ConnectorRegistry {
+	registry := platform.NewConnectorRegistry()
+
+	// Meta connector
+	metaConnector := meta.NewConnector(&meta.Config{
+		AppID:           cfg.Meta.AppID,
+		AppSecret:       cfg.Meta.AppSecret,
+		RedirectURI:     cfg.Meta.RedirectURI,
+		APIVersion:      cfg.Meta.APIVersion,
+		RateLimitCalls:  cfg.Meta.RateLimitCalls,
+		RateLimitWindow: cfg.Meta.RateLimitWindow,
+		Timeout:         cfg.HTTP.Timeout,
+		MaxRetries:      cfg.HTTP.MaxRetries,
+	})
+	registry.Register(entity.PlatformMeta, metaConnector)
+
+	// TikTok connector
+	tiktokConnector := tiktok.NewConnector(&tiktok.Config{
+		AppID:           cfg.TikTok.AppID,
+		AppSecret:       cfg.TikTok.AppSecret,
+		RedirectURI:     cfg.TikTok.RedirectURI,
+		RateLimitCalls:  cfg.TikTok.RateLimitCalls,
+		RateLimitWindow: cfg.TikTok.RateLimitWindow,
+		Timeout:         cfg.HTTP.Timeout,
+		MaxRetries:      cfg.HTTP.MaxRetries,
+	})
+	registry.Register(entity.PlatformTikTok, tiktokConnector)
+
+	// Shopee connector
+	shopeePartnerID := int64(0)
+	fmt.Sscanf(cfg.Shopee.PartnerID, "%d", &shopeePartnerID)
+	shopeeConnector := shopee.NewConnector(&shopee.Config{
+		PartnerID:       shopeePartnerID,
+		PartnerKey:      cfg.Shopee.PartnerKey,
+		RedirectURI:     cfg.Shopee.RedirectURI,
+		RateLimitCalls:  cfg.Shopee.RateLimitCalls,
+		RateLimitWindow: cfg.Shopee.RateLimitWindow,
+		Timeout:         cfg.HTTP.Timeout,
+		MaxRetries:      cfg.HTTP.MaxRetries,
+	})
+	registry.Register(entity.PlatformShopee, shopeeConnector)
+
+	// Google Ads connector
+	googleConnector := google.NewConnector(&google.Config{
+		ClientID:        cfg.Google.ClientID,
+		ClientSecret:    cfg.Google.ClientSecret,
+		RedirectURI:     cfg.Google.RedirectURI,
+		DeveloperToken:  cfg.Google.DeveloperToken,
+		LoginCustomerID: cfg.Google.LoginCustomerID,
+		APIVersion:      cfg.Google.APIVersion,
+		RateLimitCalls:  cfg.Google.RateLimitCalls,
+		RateLimitWindow: cfg.Google.RateLimitWindow,
+		Timeout:         cfg.HTTP.Timeout,
+		MaxRetries:      cfg.HTTP.MaxRetries,
+	})
+	registry.Register(entity.PlatformGoogle, googleConnector)
+
+	// Lazada connector
+	lazadaConnector := lazada.NewConnector(&lazada.Config{
+		AppKey:          cfg.Lazada.AppKey,
+		AppSecret:       cfg.Lazada.AppSecret,
+		RedirectURI:     cfg.Lazada.RedirectURI,
+		Region:          lazada.Region(cfg.Lazada.Region),
+		RateLimitCalls:  cfg.Lazada.RateLimitCalls,
+		RateLimitWindow: cfg.Lazada.RateLimitWindow,
+		Timeout:         cfg.HTTP.Timeout,
+		MaxRetries:      cfg.HTTP.MaxRetries,
+	})
+	registry.Register(entity.PlatformLazada, lazadaConnector)
+
+	return registry
+}
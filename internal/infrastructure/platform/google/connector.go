@@ -0,0 +1,1123 @@
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/service"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/platform"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/ads-aggregator/ads-aggregator/pkg/httpclient"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// Google Ads API endpoints
+	productionBaseURL  = "https://googleads.googleapis.com"
+	authURL            = "https://accounts.google.com/o/oauth2/v2/auth"
+	productionOAuthURL = "https://oauth2.googleapis.com"
+	userInfoURL        = "https://www.googleapis.com/oauth2/v2/userinfo"
+
+	// OAuth paths (relative to the OAuth base URL)
+	tokenPath  = "/token"
+	revokePath = "/revoke"
+
+	// Scopes required for reporting and account discovery
+	defaultScopes = "https://www.googleapis.com/auth/adwords https://www.googleapis.com/auth/userinfo.email https://www.googleapis.com/auth/userinfo.profile"
+
+	// Default API version
+	defaultAPIVersion = "v17"
+
+	// Google Ads reports money in micros (1,000,000 micros = 1 unit of currency)
+	microsPerUnit = 1000000
+
+	// Google Ads error statuses
+	GoogleErrorUnauthenticated    = "UNAUTHENTICATED"
+	GoogleErrorPermissionDenied   = "PERMISSION_DENIED"
+	GoogleErrorResourceExhausted  = "RESOURCE_EXHAUSTED"
+	GoogleErrorInvalidArgument    = "INVALID_ARGUMENT"
+	GoogleErrorNotFound           = "NOT_FOUND"
+	GoogleErrorInternal           = "INTERNAL"
+	GoogleErrorUnavailable        = "UNAVAILABLE"
+	GoogleErrorDeadlineExceeded   = "DEADLINE_EXCEEDED"
+	GoogleOAuthErrorInvalidGrant  = "invalid_grant"
+	GoogleOAuthErrorInvalidClient = "invalid_client"
+)
+
+// GAQL select lists
+const (
+	customerFields = "customer.id, customer.descriptive_name, customer.currency_code, customer.time_zone, customer.status, customer.manager"
+
+	campaignFields = "campaign.resource_name, campaign.id, campaign.name, campaign.status, campaign.advertising_channel_type, " +
+		"campaign.start_date, campaign.end_date, campaign_budget.amount_micros, campaign_budget.total_amount_micros, customer.currency_code"
+
+	adGroupFields = "ad_group.resource_name, ad_group.id, ad_group.name, ad_group.status, ad_group.type, " +
+		"ad_group.cpc_bid_micros, ad_group.target_cpa_micros, ad_group.campaign"
+
+	adFields = "ad_group_ad.resource_name, ad_group_ad.status, ad_group_ad.ad.id, ad_group_ad.ad.name, ad_group_ad.ad.type, " +
+		"ad_group_ad.ad.final_urls, ad_group_ad.ad.display_url, " +
+		"ad_group_ad.ad.responsive_search_ad.headlines, ad_group_ad.ad.responsive_search_ad.descriptions, " +
+		"ad_group_ad.ad.expanded_text_ad.headline_part1, ad_group_ad.ad.expanded_text_ad.description, " +
+		"ad_group_ad.ad_group, ad_group.campaign"
+
+	metricFields = "metrics.impressions, metrics.clicks, metrics.cost_micros, metrics.conversions, " +
+		"metrics.conversions_value, metrics.all_conversions, metrics.video_views, metrics.interactions, " +
+		"metrics.engagements, customer.currency_code"
+)
+
+// Connector implements the PlatformConnector interface for Google Ads
+type Connector struct {
+	*platform.BaseConnector
+	config     *Config
+	apiVersion string
+	baseURL    string
+	oauthURL   string
+}
+
+// Config holds Google Ads-specific configuration
+type Config struct {
+	ClientID        string
+	ClientSecret    string
+	RedirectURI     string
+	DeveloperToken  string // Required on every Google Ads API request
+	LoginCustomerID string // Manager (MCC) account ID, when accessing client accounts through a manager
+	APIVersion      string
+	BaseURL         string // Overrides the Google Ads API host (e.g. the local mock API)
+	OAuthURL        string // Overrides the OAuth token host (e.g. the local mock API)
+	RateLimitCalls  int
+	RateLimitWindow time.Duration
+	Timeout         time.Duration
+	MaxRetries      int
+}
+
+// DefaultConfig returns default Google Ads connector configuration
+func DefaultConfig() *Config {
+	return &Config{
+		APIVersion:      defaultAPIVersion,
+		RateLimitCalls:  1000,
+		RateLimitWindow: time.Minute,
+		Timeout:         30 * time.Second,
+		MaxRetries:      3,
+	}
+}
+
+// NewConnector creates a new Google Ads connector
+func NewConnector(config *Config) *Connector {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	apiVersion := config.APIVersion
+	if apiVersion == "" {
+		apiVersion = defaultAPIVersion
+	}
+
+	baseURL := productionBaseURL
+	if config.BaseURL != "" {
+		baseURL = strings.TrimRight(config.BaseURL, "/")
+	}
+
+	oauthURL := productionOAuthURL
+	if config.OAuthURL != "" {
+		oauthURL = strings.TrimRight(config.OAuthURL, "/")
+	}
+
+	baseConfig := &platform.ConnectorConfig{
+		AppID:           config.ClientID,
+		AppSecret:       config.ClientSecret,
+		RedirectURI:     config.RedirectURI,
+		APIVersion:      apiVersion,
+		BaseURL:         baseURL,
+		RateLimitCalls:  config.RateLimitCalls,
+		RateLimitWindow: config.RateLimitWindow,
+		Timeout:         config.Timeout,
+		MaxRetries:      config.MaxRetries,
+	}
+
+	return &Connector{
+		BaseConnector: platform.NewBaseConnector(entity.PlatformGoogle, baseConfig),
+		config:        config,
+		apiVersion:    apiVersion,
+		baseURL:       baseURL,
+		oauthURL:      oauthURL,
+	}
+}
+
+// ============================================================================
+// OAuth Methods
+// ============================================================================
+
+// GetAuthURL generates the OAuth authorization URL.
+// access_type=offline and prompt=consent make Google always return a refresh token.
+func (c *Connector) GetAuthURL(state string) string {
+	params := url.Values{
+		"client_id":     {c.config.ClientID},
+		"redirect_uri":  {c.config.RedirectURI},
+		"state":         {state},
+		"scope":         {defaultScopes},
+		"response_type": {"code"},
+		"access_type":   {"offline"},
+		"prompt":        {"consent"},
+	}
+	return authURL + "?" + params.Encode()
+}
+
+// ExchangeCode exchanges an authorization code for OAuth tokens
+func (c *Connector) ExchangeCode(ctx context.Context, code string) (*entity.OAuthToken, error) {
+	form := url.Values{
+		"code":          {code},
+		"client_id":     {c.config.ClientID},
+		"client_secret": {c.config.ClientSecret},
+		"redirect_uri":  {c.config.RedirectURI},
+		"grant_type":    {"authorization_code"},
+	}
+
+	tokenResp, err := c.postTokenForm(ctx, c.oauthURL+tokenPath, form)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenResp.toOAuthToken(""), nil
+}
+
+// RefreshToken refreshes an expired access token.
+// Google does not rotate refresh tokens, so the original one is carried over.
+func (c *Connector) RefreshToken(ctx context.Context, refreshToken string) (*entity.OAuthToken, error) {
+	form := url.Values{
+		"refresh_token": {refreshToken},
+		"client_id":     {c.config.ClientID},
+		"client_secret": {c.config.ClientSecret},
+		"grant_type":    {"refresh_token"},
+	}
+
+	tokenResp, err := c.postTokenForm(ctx, c.oauthURL+tokenPath, form)
+	if err != nil {
+		return nil, err
+	}
+
+	return tokenResp.toOAuthToken(refreshToken), nil
+}
+
+// RevokeToken revokes an access or refresh token
+func (c *Connector) RevokeToken(ctx context.Context, accessToken string) error {
+	form := url.Values{"token": {accessToken}}
+
+	resp, err := c.DoRequest(ctx, &httpclient.Request{
+		Method:     http.MethodPost,
+		URL:        c.oauthURL + revokePath,
+		Headers:    map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+		BodyReader: strings.NewReader(form.Encode()),
+	})
+	if err != nil {
+		if resp != nil {
+			return c.HandleGoogleError(resp.StatusCode, resp.Body)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// postTokenForm posts a form-encoded request to the Google OAuth token endpoint
+func (c *Connector) postTokenForm(ctx context.Context, endpoint string, form url.Values) (*googleTokenResponse, error) {
+	resp, err := c.DoRequest(ctx, &httpclient.Request{
+		Method:     http.MethodPost,
+		URL:        endpoint,
+		Headers:    map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+		BodyReader: strings.NewReader(form.Encode()),
+	})
+	if err != nil {
+		if resp != nil {
+			return nil, c.HandleGoogleError(resp.StatusCode, resp.Body)
+		}
+		return nil, err
+	}
+
+	var tokenResp googleTokenResponse
+	if err := c.ParseJSON(resp.Body, &tokenResp); err != nil {
+		return nil, err
+	}
+
+	if tokenResp.Error != "" {
+		return nil, errors.NewOAuthError(entity.PlatformGoogle.String(), tokenResp.Error, tokenResp.ErrorDescription)
+	}
+
+	return &tokenResp, nil
+}
+
+// ============================================================================
+// User & Account Methods
+// ============================================================================
+
+// GetUserInfo retrieves the authenticated user's information
+func (c *Connector) GetUserInfo(ctx context.Context, accessToken string) (*entity.PlatformUser, error) {
+	resp, err := c.DoGet(ctx, userInfoURL, c.BuildAuthHeader(accessToken), nil)
+	if err != nil {
+		if resp != nil {
+			return nil, c.HandleGoogleError(resp.StatusCode, resp.Body)
+		}
+		return nil, err
+	}
+
+	var userResp struct {
+		ID      string `json:"id"`
+		Email   string `json:"email"`
+		Name    string `json:"name"`
+		Picture string `json:"picture"`
+	}
+
+	if err := c.ParseJSON(resp.Body, &userResp); err != nil {
+		return nil, err
+	}
+
+	return &entity.PlatformUser{
+		ID:        userResp.ID,
+		Name:      userResp.Name,
+		Email:     userResp.Email,
+		AvatarURL: userResp.Picture,
+	}, nil
+}
+
+// GetAdAccounts retrieves all customer accounts accessible by the token.
+// Manager (MCC) accounts are skipped because they carry no campaigns of their own.
+func (c *Connector) GetAdAccounts(ctx context.Context, accessToken string) ([]entity.PlatformAccount, error) {
+	endpoint := fmt.Sprintf("%s/%s/customers:listAccessibleCustomers", c.baseURL, c.apiVersion)
+
+	resp, err := c.DoGet(ctx, endpoint, c.buildGoogleHeaders(accessToken), nil)
+	if err != nil {
+		if resp != nil {
+			return nil, c.HandleGoogleError(resp.StatusCode, resp.Body)
+		}
+		return nil, err
+	}
+
+	var listResp struct {
+		ResourceNames []string `json:"resourceNames"`
+	}
+
+	if err := c.ParseJSON(resp.Body, &listResp); err != nil {
+		return nil, err
+	}
+
+	var accounts []entity.PlatformAccount
+	for _, resourceName := range listResp.ResourceNames {
+		customerID := strings.TrimPrefix(resourceName, "customers/")
+
+		rows, err := c.Search(ctx, accessToken, customerID, "SELECT "+customerFields+" FROM customer")
+		if err != nil {
+			return accounts, err
+		}
+
+		for _, row := range rows {
+			if row.Customer.Manager {
+				continue
+			}
+			accounts = append(accounts, entity.PlatformAccount{
+				ID:       row.Customer.ID,
+				Name:     row.Customer.DescriptiveName,
+				Currency: row.Customer.CurrencyCode,
+				Timezone: row.Customer.TimeZone,
+				Status:   strings.ToLower(row.Customer.Status),
+			})
+		}
+	}
+
+	return accounts, nil
+}
+
+// ============================================================================
+// Campaign Methods
+// ============================================================================
+
+// GetCampaigns retrieves all campaigns for a customer account
+func (c *Connector) GetCampaigns(ctx context.Context, accessToken string, adAccountID string) ([]entity.Campaign, error) {
+	customerID := normalizeCustomerID(adAccountID)
+	query := "SELECT " + campaignFields + " FROM campaign ORDER BY campaign.id"
+
+	rows, err := c.Search(ctx, accessToken, customerID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	campaigns := make([]entity.Campaign, 0, len(rows))
+	for _, row := range rows {
+		campaigns = append(campaigns, c.mapCampaign(row))
+	}
+
+	return campaigns, nil
+}
+
+// GetCampaign retrieves a single campaign by its resource name
+func (c *Connector) GetCampaign(ctx context.Context, accessToken string, campaignID string) (*entity.Campaign, error) {
+	customerID, id, err := parseResourceName(campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM campaign WHERE campaign.id = %s", campaignFields, id)
+	rows, err := c.Search(ctx, accessToken, customerID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.ErrNotFound("Campaign")
+	}
+
+	campaign := c.mapCampaign(rows[0])
+	return &campaign, nil
+}
+
+// ============================================================================
+// AdSet (Ad Group) Methods
+// ============================================================================
+
+// GetAdSets retrieves all ad groups for a campaign
+func (c *Connector) GetAdSets(ctx context.Context, accessToken string, campaignID string) ([]entity.AdSet, error) {
+	customerID, id, err := parseResourceName(campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM ad_group WHERE campaign.id = %s ORDER BY ad_group.id", adGroupFields, id)
+	rows, err := c.Search(ctx, accessToken, customerID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	adSets := make([]entity.AdSet, 0, len(rows))
+	for _, row := range rows {
+		adSets = append(adSets, c.mapAdGroup(row))
+	}
+
+	return adSets, nil
+}
+
+// GetAdSet retrieves a single ad group by its resource name
+func (c *Connector) GetAdSet(ctx context.Context, accessToken string, adSetID string) (*entity.AdSet, error) {
+	customerID, id, err := parseResourceName(adSetID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM ad_group WHERE ad_group.id = %s", adGroupFields, id)
+	rows, err := c.Search(ctx, accessToken, customerID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.ErrNotFound("AdSet")
+	}
+
+	adSet := c.mapAdGroup(rows[0])
+	return &adSet, nil
+}
+
+// ============================================================================
+// Ad Methods
+// ============================================================================
+
+// GetAds retrieves all ads for an ad group
+func (c *Connector) GetAds(ctx context.Context, accessToken string, adSetID string) ([]entity.Ad, error) {
+	customerID, id, err := parseResourceName(adSetID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM ad_group_ad WHERE ad_group.id = %s ORDER BY ad_group_ad.ad.id", adFields, id)
+	rows, err := c.Search(ctx, accessToken, customerID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	ads := make([]entity.Ad, 0, len(rows))
+	for _, row := range rows {
+		ads = append(ads, c.mapAd(row))
+	}
+
+	return ads, nil
+}
+
+// GetAd retrieves a single ad by its ad_group_ad resource name
+func (c *Connector) GetAd(ctx context.Context, accessToken string, adID string) (*entity.Ad, error) {
+	customerID, id, err := parseResourceName(adID)
+	if err != nil {
+		return nil, err
+	}
+
+	// ad_group_ad IDs are composite: {ad_group_id}~{ad_id}
+	parts := strings.SplitN(id, "~", 2)
+	if len(parts) != 2 {
+		return nil, errors.ErrValidation("invalid Google Ads ad resource name: " + adID)
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM ad_group_ad WHERE ad_group.id = %s AND ad_group_ad.ad.id = %s", adFields, parts[0], parts[1])
+	rows, err := c.Search(ctx, accessToken, customerID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, errors.ErrNotFound("Ad")
+	}
+
+	ad := c.mapAd(rows[0])
+	return &ad, nil
+}
+
+// ============================================================================
+// Insights Methods
+// ============================================================================
+
+// GetCampaignInsights retrieves daily performance metrics for a campaign
+func (c *Connector) GetCampaignInsights(ctx context.Context, accessToken string, campaignID string, dateRange entity.DateRange) ([]entity.CampaignMetricsDaily, error) {
+	customerID, id, err := parseResourceName(campaignID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(
+		"SELECT campaign.id, segments.date, %s FROM campaign WHERE campaign.id = %s AND %s ORDER BY segments.date",
+		metricFields, id, c.formatDateFilter(dateRange),
+	)
+
+	rows, err := c.Search(ctx, accessToken, customerID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics []entity.CampaignMetricsDaily
+	for _, row := range rows {
+		metrics = append(metrics, c.mapCampaignMetrics(row))
+	}
+
+	return metrics, nil
+}
+
+// GetAdSetInsights retrieves daily performance metrics for an ad group
+func (c *Connector) GetAdSetInsights(ctx context.Context, accessToken string, adSetID string, dateRange entity.DateRange) ([]entity.AdSetMetricsDaily, error) {
+	customerID, id, err := parseResourceName(adSetID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(
+		"SELECT ad_group.id, segments.date, %s FROM ad_group WHERE ad_group.id = %s AND %s ORDER BY segments.date",
+		metricFields, id, c.formatDateFilter(dateRange),
+	)
+
+	rows, err := c.Search(ctx, accessToken, customerID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics []entity.AdSetMetricsDaily
+	for _, row := range rows {
+		metrics = append(metrics, c.mapAdGroupMetrics(row))
+	}
+
+	return metrics, nil
+}
+
+// GetAdInsights retrieves daily performance metrics for an ad
+func (c *Connector) GetAdInsights(ctx context.Context, accessToken string, adID string, dateRange entity.DateRange) ([]entity.AdMetricsDaily, error) {
+	customerID, id, err := parseResourceName(adID)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := strings.SplitN(id, "~", 2)
+	if len(parts) != 2 {
+		return nil, errors.ErrValidation("invalid Google Ads ad resource name: " + adID)
+	}
+
+	query := fmt.Sprintf(
+		"SELECT ad_group_ad.ad.id, segments.date, %s FROM ad_group_ad WHERE ad_group.id = %s AND ad_group_ad.ad.id = %s AND %s ORDER BY segments.date",
+		metricFields, parts[0], parts[1], c.formatDateFilter(dateRange),
+	)
+
+	rows, err := c.Search(ctx, accessToken, customerID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	var metrics []entity.AdMetricsDaily
+	for _, row := range rows {
+		metrics = append(metrics, c.mapAdMetrics(row))
+	}
+
+	return metrics, nil
+}
+
+// GetAccountInsights retrieves aggregated insights for a customer account
+func (c *Connector) GetAccountInsights(ctx context.Context, accessToken string, adAccountID string, dateRange entity.DateRange) (*entity.AggregatedMetrics, error) {
+	customerID := normalizeCustomerID(adAccountID)
+
+	// Filtering on segments.date without selecting it aggregates over the whole range
+	query := fmt.Sprintf("SELECT %s FROM customer WHERE %s", metricFields, c.formatDateFilter(dateRange))
+
+	rows, err := c.Search(ctx, accessToken, customerID, query)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregated := &entity.AggregatedMetrics{}
+	for _, row := range rows {
+		aggregated.Currency = row.Customer.CurrencyCode
+		aggregated.TotalSpend = aggregated.TotalSpend.Add(microsToDecimal(row.Metrics.CostMicros))
+		aggregated.TotalImpressions += parseInt64(row.Metrics.Impressions)
+		aggregated.TotalClicks += parseInt64(row.Metrics.Clicks)
+		aggregated.TotalConversions += int64(row.Metrics.Conversions)
+		aggregated.TotalRevenue = aggregated.TotalRevenue.Add(decimal.NewFromFloat(row.Metrics.ConversionsValue))
+	}
+
+	// Calculate derived metrics
+	if aggregated.TotalImpressions > 0 {
+		aggregated.AverageCTR = float64(aggregated.TotalClicks) / float64(aggregated.TotalImpressions) * 100
+		aggregated.AverageCPM = aggregated.TotalSpend.Div(decimal.NewFromInt(aggregated.TotalImpressions)).Mul(decimal.NewFromInt(1000))
+	}
+
+	if aggregated.TotalClicks > 0 {
+		aggregated.AverageCPC = aggregated.TotalSpend.Div(decimal.NewFromInt(aggregated.TotalClicks))
+	}
+
+	if aggregated.TotalConversions > 0 {
+		aggregated.AverageCPA = aggregated.TotalSpend.Div(decimal.NewFromInt(aggregated.TotalConversions))
+	}
+
+	if aggregated.TotalSpend.IsPositive() {
+		aggregated.OverallROAS, _ = aggregated.TotalRevenue.Div(aggregated.TotalSpend).Float64()
+	}
+
+	return aggregated, nil
+}
+
+// HealthCheck verifies the connector can reach the Google Ads API
+func (c *Connector) HealthCheck(ctx context.Context) error {
+	if c.config.DeveloperToken == "" {
+		return errors.New(errors.ErrCodePlatformUnavailable, "Google Ads developer token is not configured", http.StatusServiceUnavailable)
+	}
+	return nil
+}
+
+// ============================================================================
+// GAQL Search
+// ============================================================================
+
+// Search runs a GAQL query against a customer account and follows nextPageToken until exhausted
+func (c *Connector) Search(ctx context.Context, accessToken string, customerID string, query string) ([]googleRow, error) {
+	endpoint := fmt.Sprintf("%s/%s/customers/%s/googleAds:search", c.baseURL, c.apiVersion, normalizeCustomerID(customerID))
+
+	var allRows []googleRow
+	pageToken := ""
+
+	for {
+		body := map[string]string{"query": query}
+		if pageToken != "" {
+			body["pageToken"] = pageToken
+		}
+
+		resp, err := c.DoPost(ctx, endpoint, c.buildGoogleHeaders(accessToken), body)
+		if err != nil {
+			if resp != nil {
+				return allRows, c.HandleGoogleError(resp.StatusCode, resp.Body)
+			}
+			return allRows, err
+		}
+
+		var searchResp struct {
+			Results       []googleRow `json:"results"`
+			NextPageToken string      `json:"nextPageToken"`
+		}
+
+		if err := c.ParseJSON(resp.Body, &searchResp); err != nil {
+			return allRows, err
+		}
+
+		allRows = append(allRows, searchResp.Results...)
+
+		if searchResp.NextPageToken == "" {
+			break
+		}
+		pageToken = searchResp.NextPageToken
+
+		// Respect context cancellation between pages
+		select {
+		case <-ctx.Done():
+			return allRows, ctx.Err()
+		default:
+		}
+	}
+
+	return allRows, nil
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+func (c *Connector) buildGoogleHeaders(accessToken string) map[string]string {
+	headers := map[string]string{
+		"Authorization":   "Bearer " + accessToken,
+		"developer-token": c.config.DeveloperToken,
+	}
+	if c.config.LoginCustomerID != "" {
+		headers["login-customer-id"] = normalizeCustomerID(c.config.LoginCustomerID)
+	}
+	return headers
+}
+
+func (c *Connector) formatDateFilter(dateRange entity.DateRange) string {
+	return fmt.Sprintf("segments.date BETWEEN '%s' AND '%s'",
+		dateRange.StartDate.Format("2006-01-02"),
+		dateRange.EndDate.Format("2006-01-02"),
+	)
+}
+
+// normalizeCustomerID strips the dashes Google shows in the UI (123-456-7890 -> 1234567890)
+func normalizeCustomerID(customerID string) string {
+	customerID = strings.TrimPrefix(customerID, "customers/")
+	return strings.ReplaceAll(customerID, "-", "")
+}
+
+// Resource name IDs are numeric, or {ad_group_id}~{ad_id} for ads. They are
+// spliced into GAQL queries, so nothing else may get through.
+var (
+	customerIDPattern = regexp.MustCompile(`^[0-9]+$`)
+	resourceIDPattern = regexp.MustCompile(`^[0-9]+(~[0-9]+)?$`)
+)
+
+// parseResourceName splits a resource name such as customers/123/campaigns/456
+// into its customer ID and trailing object ID
+func parseResourceName(resourceName string) (customerID string, id string, err error) {
+	parts := strings.Split(resourceName, "/")
+	if len(parts) != 4 || parts[0] != "customers" || !customerIDPattern.MatchString(parts[1]) || !resourceIDPattern.MatchString(parts[3]) {
+		return "", "", errors.ErrValidation("invalid Google Ads resource name: " + resourceName)
+	}
+	return parts[1], parts[3], nil
+}
+
+func parseInt64(s string) int64 {
+	val, _ := strconv.ParseInt(s, 10, 64)
+	return val
+}
+
+func microsToDecimal(micros string) decimal.Decimal {
+	if micros == "" {
+		return decimal.Zero
+	}
+	val, err := decimal.NewFromString(micros)
+	if err != nil {
+		return decimal.Zero
+	}
+	return val.Div(decimal.NewFromInt(microsPerUnit))
+}
+
+func parseDate(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
+
+// ============================================================================
+// Google Ads API Response Types
+// ============================================================================
+
+type googleTokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	RefreshToken     string `json:"refresh_token"`
+	ExpiresIn        int    `json:"expires_in"`
+	TokenType        string `json:"token_type"`
+	Scope            string `json:"scope"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (t *googleTokenResponse) toOAuthToken(fallbackRefreshToken string) *entity.OAuthToken {
+	refreshToken := t.RefreshToken
+	if refreshToken == "" {
+		refreshToken = fallbackRefreshToken
+	}
+
+	tokenType := t.TokenType
+	if tokenType == "" {
+		tokenType = "Bearer"
+	}
+
+	var scopes []string
+	if t.Scope != "" {
+		scopes = strings.Fields(t.Scope)
+	}
+
+	return &entity.OAuthToken{
+		AccessToken:  t.AccessToken,
+		RefreshToken: refreshToken,
+		TokenType:    tokenType,
+		ExpiresIn:    t.ExpiresIn,
+		ExpiresAt:    time.Now().Add(time.Duration(t.ExpiresIn) * time.Second),
+		Scopes:       scopes,
+	}
+}
+
+// googleRow is a single GoogleAdsRow from a search response.
+// The REST API encodes int64 fields as strings and doubles as numbers.
+type googleRow struct {
+	Customer struct {
+		ID              string `json:"id"`
+		DescriptiveName string `json:"descriptiveName"`
+		CurrencyCode    string `json:"currencyCode"`
+		TimeZone        string `json:"timeZone"`
+		Status          string `json:"status"`
+		Manager         bool   `json:"manager"`
+	} `json:"customer"`
+
+	Campaign struct {
+		ResourceName           string `json:"resourceName"`
+		ID                     string `json:"id"`
+		Name                   string `json:"name"`
+		Status                 string `json:"status"`
+		AdvertisingChannelType string `json:"advertisingChannelType"`
+		StartDate              string `json:"startDate"`
+		EndDate                string `json:"endDate"`
+	} `json:"campaign"`
+
+	CampaignBudget struct {
+		AmountMicros      string `json:"amountMicros"`
+		TotalAmountMicros string `json:"totalAmountMicros"`
+	} `json:"campaignBudget"`
+
+	AdGroup struct {
+		ResourceName    string `json:"resourceName"`
+		ID              string `json:"id"`
+		Name            string `json:"name"`
+		Status          string `json:"status"`
+		Type            string `json:"type"`
+		CpcBidMicros    string `json:"cpcBidMicros"`
+		TargetCpaMicros string `json:"targetCpaMicros"`
+		Campaign        string `json:"campaign"`
+	} `json:"adGroup"`
+
+	AdGroupAd struct {
+		ResourceName string `json:"resourceName"`
+		Status       string `json:"status"`
+		AdGroup      string `json:"adGroup"`
+		Ad           struct {
+			ID                 string   `json:"id"`
+			Name               string   `json:"name"`
+			Type               string   `json:"type"`
+			FinalURLs          []string `json:"finalUrls"`
+			DisplayURL         string   `json:"displayUrl"`
+			ResponsiveSearchAd struct {
+				Headlines    []googleTextAsset `json:"headlines"`
+				Descriptions []googleTextAsset `json:"descriptions"`
+			} `json:"responsiveSearchAd"`
+			ExpandedTextAd struct {
+				HeadlinePart1 string `json:"headlinePart1"`
+				Description   string `json:"description"`
+			} `json:"expandedTextAd"`
+		} `json:"ad"`
+	} `json:"adGroupAd"`
+
+	Metrics struct {
+		Impressions      string  `json:"impressions"`
+		Clicks           string  `json:"clicks"`
+		CostMicros       string  `json:"costMicros"`
+		Conversions      float64 `json:"conversions"`
+		ConversionsValue float64 `json:"conversionsValue"`
+		AllConversions   float64 `json:"allConversions"`
+		VideoViews       string  `json:"videoViews"`
+		Interactions     string  `json:"interactions"`
+		Engagements      string  `json:"engagements"`
+	} `json:"metrics"`
+
+	Segments struct {
+		Date string `json:"date"`
+	} `json:"segments"`
+}
+
+type googleTextAsset struct {
+	Text string `json:"text"`
+}
+
+// ============================================================================
+// Mapping Methods
+// ============================================================================
+
+func (c *Connector) mapCampaign(row googleRow) entity.Campaign {
+	gc := row.Campaign
+
+	campaign := entity.Campaign{
+		Platform:             entity.PlatformGoogle,
+		PlatformCampaignID:   gc.ResourceName,
+		PlatformCampaignName: gc.Name,
+		Status:               c.mapStatus(gc.Status),
+		Objective:            c.mapObjective(gc.AdvertisingChannelType),
+		BudgetCurrency:       row.Customer.CurrencyCode,
+		StartDate:            parseDate(gc.StartDate),
+		EndDate:              parseDate(gc.EndDate),
+		PlatformData: entity.JSONMap{
+			"campaign_id":              gc.ID,
+			"advertising_channel_type": gc.AdvertisingChannelType,
+		},
+	}
+
+	if row.CampaignBudget.AmountMicros != "" {
+		budget := microsToDecimal(row.CampaignBudget.AmountMicros)
+		campaign.DailyBudget = &budget
+	}
+
+	if row.CampaignBudget.TotalAmountMicros != "" {
+		budget := microsToDecimal(row.CampaignBudget.TotalAmountMicros)
+		campaign.LifetimeBudget = &budget
+	}
+
+	return campaign
+}
+
+func (c *Connector) mapAdGroup(row googleRow) entity.AdSet {
+	ag := row.AdGroup
+
+	adSet := entity.AdSet{
+		Platform:          entity.PlatformGoogle,
+		PlatformAdSetID:   ag.ResourceName,
+		PlatformAdSetName: ag.Name,
+		Status:            c.mapStatus(ag.Status),
+		PlatformData: entity.JSONMap{
+			"ad_group_id":   ag.ID,
+			"ad_group_type": ag.Type,
+			"campaign":      ag.Campaign,
+		},
+	}
+
+	switch {
+	case ag.TargetCpaMicros != "" && ag.TargetCpaMicros != "0":
+		bid := microsToDecimal(ag.TargetCpaMicros)
+		adSet.BidAmount = &bid
+		adSet.BidStrategy = "TARGET_CPA"
+	case ag.CpcBidMicros != "":
+		bid := microsToDecimal(ag.CpcBidMicros)
+		adSet.BidAmount = &bid
+		adSet.BidStrategy = "MANUAL_CPC"
+	}
+
+	return adSet
+}
+
+func (c *Connector) mapAd(row googleRow) entity.Ad {
+	ga := row.AdGroupAd
+
+	ad := entity.Ad{
+		Platform:       entity.PlatformGoogle,
+		PlatformAdID:   ga.ResourceName,
+		PlatformAdName: ga.Ad.Name,
+		Status:         c.mapStatus(ga.Status),
+		DisplayURL:     ga.Ad.DisplayURL,
+		CreativeData: entity.JSONMap{
+			"ad_id":   ga.Ad.ID,
+			"ad_type": ga.Ad.Type,
+		},
+		PlatformData: entity.JSONMap{
+			"ad_group": ga.AdGroup,
+		},
+	}
+
+	if len(ga.Ad.FinalURLs) > 0 {
+		ad.DestinationURL = ga.Ad.FinalURLs[0]
+	}
+
+	// Responsive search ads carry several headline/description assets; keep the
+	// first of each as the canonical copy and the full set in CreativeData.
+	rsa := ga.Ad.ResponsiveSearchAd
+	if len(rsa.Headlines) > 0 {
+		ad.Headline = rsa.Headlines[0].Text
+		ad.CreativeData["headlines"] = textAssets(rsa.Headlines)
+	} else {
+		ad.Headline = ga.Ad.ExpandedTextAd.HeadlinePart1
+	}
+
+	if len(rsa.Descriptions) > 0 {
+		ad.Description = rsa.Descriptions[0].Text
+		ad.CreativeData["descriptions"] = textAssets(rsa.Descriptions)
+	} else {
+		ad.Description = ga.Ad.ExpandedTextAd.Description
+	}
+
+	if ad.PlatformAdName == "" {
+		ad.PlatformAdName = ad.Headline
+	}
+
+	return ad
+}
+
+func textAssets(assets []googleTextAsset) []string {
+	texts := make([]string, 0, len(assets))
+	for _, a := range assets {
+		texts = append(texts, a.Text)
+	}
+	return texts
+}
+
+func (c *Connector) mapCampaignMetrics(row googleRow) entity.CampaignMetricsDaily {
+	metricDate, _ := time.Parse("2006-01-02", row.Segments.Date)
+
+	metric := entity.CampaignMetricsDaily{
+		BaseEntity:      entity.BaseEntity{ID: uuid.New()},
+		Platform:        entity.PlatformGoogle,
+		MetricDate:      metricDate,
+		Currency:        row.Customer.CurrencyCode,
+		Impressions:     parseInt64(row.Metrics.Impressions),
+		Clicks:          parseInt64(row.Metrics.Clicks),
+		Spend:           microsToDecimal(row.Metrics.CostMicros),
+		VideoViews:      parseInt64(row.Metrics.VideoViews),
+		Conversions:     int64(row.Metrics.Conversions),
+		ConversionValue: decimal.NewFromFloat(row.Metrics.ConversionsValue),
+		PlatformMetrics: entity.JSONMap{
+			"conversions":     row.Metrics.Conversions,
+			"all_conversions": row.Metrics.AllConversions,
+			"interactions":    parseInt64(row.Metrics.Interactions),
+			"engagements":     parseInt64(row.Metrics.Engagements),
+		},
+	}
+
+	metric.CalculateDerivedMetrics()
+	return metric
+}
+
+func (c *Connector) mapAdGroupMetrics(row googleRow) entity.AdSetMetricsDaily {
+	metricDate, _ := time.Parse("2006-01-02", row.Segments.Date)
+
+	return entity.AdSetMetricsDaily{
+		BaseEntity:      entity.BaseEntity{ID: uuid.New()},
+		Platform:        entity.PlatformGoogle,
+		MetricDate:      metricDate,
+		Currency:        row.Customer.CurrencyCode,
+		Impressions:     parseInt64(row.Metrics.Impressions),
+		Clicks:          parseInt64(row.Metrics.Clicks),
+		Spend:           microsToDecimal(row.Metrics.CostMicros),
+		VideoViews:      parseInt64(row.Metrics.VideoViews),
+		Conversions:     int64(row.Metrics.Conversions),
+		ConversionValue: decimal.NewFromFloat(row.Metrics.ConversionsValue),
+	}
+}
+
+func (c *Connector) mapAdMetrics(row googleRow) entity.AdMetricsDaily {
+	metricDate, _ := time.Parse("2006-01-02", row.Segments.Date)
+
+	return entity.AdMetricsDaily{
+		BaseEntity:      entity.BaseEntity{ID: uuid.New()},
+		Platform:        entity.PlatformGoogle,
+		MetricDate:      metricDate,
+		Currency:        row.Customer.CurrencyCode,
+		Impressions:     parseInt64(row.Metrics.Impressions),
+		Clicks:          parseInt64(row.Metrics.Clicks),
+		Spend:           microsToDecimal(row.Metrics.CostMicros),
+		VideoViews:      parseInt64(row.Metrics.VideoViews),
+		Conversions:     int64(row.Metrics.Conversions),
+		ConversionValue: decimal.NewFromFloat(row.Metrics.ConversionsValue),
+	}
+}
+
+func (c *Connector) mapStatus(status string) entity.CampaignStatus {
+	switch strings.ToUpper(status) {
+	case "ENABLED":
+		return entity.CampaignStatusActive
+	case "PAUSED":
+		return entity.CampaignStatusPaused
+	case "REMOVED":
+		return entity.CampaignStatusDeleted
+	default:
+		return entity.CampaignStatusDraft
+	}
+}
+
+func (c *Connector) mapObjective(channelType string) entity.CampaignObjective {
+	switch strings.ToUpper(channelType) {
+	case "SEARCH":
+		return entity.ObjectiveTraffic
+	case "DISPLAY":
+		return entity.ObjectiveAwareness
+	case "SHOPPING":
+		return entity.ObjectiveSales
+	case "VIDEO":
+		return entity.ObjectiveVideoViews
+	case "MULTI_CHANNEL":
+		return entity.ObjectiveAppPromotion
+	case "LOCAL", "SMART":
+		return entity.ObjectiveStoreTraffic
+	case "DISCOVERY", "DEMAND_GEN":
+		return entity.ObjectiveEngagement
+	default:
+		return entity.ObjectiveConversions
+	}
+}
+
+// ============================================================================
+// Error Handling
+// ============================================================================
+
+// HandleGoogleError converts a Google API or OAuth error body into an application error
+func (c *Connector) HandleGoogleError(statusCode int, body []byte) error {
+	platformName := entity.PlatformGoogle.String()
+
+	// OAuth endpoints return {"error": "invalid_grant", "error_description": "..."}
+	var oauthErr struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &oauthErr); err == nil && oauthErr.Error != "" {
+		if oauthErr.Error == GoogleOAuthErrorInvalidGrant {
+			return errors.NewTokenExpiredError(platformName, "refresh", time.Now())
+		}
+		return errors.NewOAuthError(platformName, oauthErr.Error, oauthErr.ErrorDescription)
+	}
+
+	// Google Ads API returns {"error": {"code": 401, "message": "...", "status": "UNAUTHENTICATED"}}
+	var apiErr struct {
+		Error struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+			Status  string `json:"status"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &apiErr); err != nil || apiErr.Error.Status == "" {
+		return errors.NewPlatformAPIError(platformName, statusCode, "UNKNOWN",
+			fmt.Sprintf("API request failed with status %d", statusCode)).WithRawResponse(body)
+	}
+
+	switch apiErr.Error.Status {
+	case GoogleErrorUnauthenticated:
+		return errors.NewTokenExpiredError(platformName, "access", time.Now())
+	case GoogleErrorPermissionDenied:
+		return errors.NewPlatformAPIError(platformName, http.StatusForbidden, apiErr.Error.Status, "Permission denied: "+apiErr.Error.Message)
+	case GoogleErrorResourceExhausted:
+		return errors.NewRateLimitError(platformName, 60*time.Second)
+	case GoogleErrorNotFound:
+		return errors.ErrNotFound("resource")
+	case GoogleErrorInvalidArgument:
+		return errors.ErrValidation(apiErr.Error.Message).WithMetadata("platform", platformName)
+	case GoogleErrorInternal, GoogleErrorUnavailable, GoogleErrorDeadlineExceeded:
+		platformErr := errors.NewPlatformAPIError(platformName, http.StatusServiceUnavailable, apiErr.Error.Status, apiErr.Error.Message)
+		platformErr.WithMetadata("is_transient", "true")
+		return platformErr
+	default:
+		return errors.NewPlatformAPIError(platformName, statusCode, apiErr.Error.Status, apiErr.Error.Message).WithRawResponse(body)
+	}
+}
+
+// Ensure Connector implements PlatformConnector interface
+var _ service.PlatformConnector = (*Connector)(nil)
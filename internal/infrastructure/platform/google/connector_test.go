@@ -0,0 +1,335 @@
+package google
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/shopspring/decimal"
+)
+
+// ============================================================================
+// Config Tests
+// ============================================================================
+
+func TestDefaultConfig(t *testing.T) {
+	config := DefaultConfig()
+
+	if config == nil {
+		t.Fatal("DefaultConfig() returned nil")
+	}
+
+	if config.APIVersion != "v17" {
+		t.Errorf("APIVersion = %q, want %q", config.APIVersion, "v17")
+	}
+
+	if config.RateLimitCalls != 1000 {
+		t.Errorf("RateLimitCalls = %d, want %d", config.RateLimitCalls, 1000)
+	}
+
+	if config.MaxRetries != 3 {
+		t.Errorf("MaxRetries = %d, want %d", config.MaxRetries, 3)
+	}
+}
+
+func TestNewConnector_WithNilConfig(t *testing.T) {
+	connector := NewConnector(nil)
+
+	if connector == nil {
+		t.Fatal("NewConnector() returned nil")
+	}
+
+	if connector.baseURL != productionBaseURL {
+		t.Errorf("baseURL = %q, want %q", connector.baseURL, productionBaseURL)
+	}
+
+	if connector.Platform() != entity.PlatformGoogle {
+		t.Errorf("Platform() = %q, want %q", connector.Platform(), entity.PlatformGoogle)
+	}
+}
+
+// ============================================================================
+// OAuth Tests
+// ============================================================================
+
+func TestGetAuthURL(t *testing.T) {
+	connector := NewConnector(&Config{
+		ClientID:    "test_client_id",
+		RedirectURI: "http://localhost:8080/callback",
+	})
+
+	authURL := connector.GetAuthURL("test_state")
+
+	expected := []string{
+		"accounts.google.com",
+		"client_id=test_client_id",
+		"state=test_state",
+		"access_type=offline",
+		"prompt=consent",
+		"adwords",
+	}
+
+	for _, part := range expected {
+		if !strings.Contains(authURL, part) {
+			t.Errorf("GetAuthURL() = %q, missing %q", authURL, part)
+		}
+	}
+}
+
+func TestRefreshToken_KeepsRefreshToken(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Fatalf("ParseForm() error = %v", err)
+		}
+		if got := r.PostForm.Get("grant_type"); got != "refresh_token" {
+			t.Errorf("grant_type = %q, want %q", got, "refresh_token")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"new_access","expires_in":3599,"token_type":"Bearer","scope":"https://www.googleapis.com/auth/adwords"}`))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.OAuthURL = server.URL
+	connector := NewConnector(config)
+
+	token, err := connector.RefreshToken(context.Background(), "original_refresh")
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+
+	if token.AccessToken != "new_access" {
+		t.Errorf("AccessToken = %q, want %q", token.AccessToken, "new_access")
+	}
+
+	if token.RefreshToken != "original_refresh" {
+		t.Errorf("RefreshToken = %q, want %q", token.RefreshToken, "original_refresh")
+	}
+
+	if token.ExpiresAt.Before(time.Now()) {
+		t.Errorf("ExpiresAt = %v, want a future time", token.ExpiresAt)
+	}
+}
+
+func TestRefreshToken_InvalidGrant(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error":"invalid_grant","error_description":"Token has been expired or revoked."}`))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.OAuthURL = server.URL
+	config.MaxRetries = 1
+	connector := NewConnector(config)
+
+	_, err := connector.RefreshToken(context.Background(), "revoked_refresh")
+	if err == nil {
+		t.Fatal("RefreshToken() error = nil, want token error")
+	}
+
+	if _, ok := err.(*errors.TokenError); !ok {
+		t.Errorf("RefreshToken() error type = %T, want *errors.TokenError", err)
+	}
+}
+
+// ============================================================================
+// GAQL Search Tests
+// ============================================================================
+
+func TestSearch_FollowsPageToken(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		if got := r.Header.Get("developer-token"); got != "dev_token" {
+			t.Errorf("developer-token = %q, want %q", got, "dev_token")
+		}
+		if got := r.Header.Get("login-customer-id"); got != "1112223333" {
+			t.Errorf("login-customer-id = %q, want %q", got, "1112223333")
+		}
+		if !strings.HasSuffix(r.URL.Path, "/v17/customers/1234567890/googleAds:search") {
+			t.Errorf("path = %q, want googleAds:search for customer 1234567890", r.URL.Path)
+		}
+
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+
+		w.Header().Set("Content-Type", "application/json")
+		if body["pageToken"] == "" {
+			w.Write([]byte(`{"results":[{"campaign":{"resourceName":"customers/1234567890/campaigns/1","id":"1","name":"First","status":"ENABLED","advertisingChannelType":"SEARCH"},"campaignBudget":{"amountMicros":"50000000"},"customer":{"currencyCode":"MYR"}}],"nextPageToken":"page2"}`))
+			return
+		}
+		w.Write([]byte(`{"results":[{"campaign":{"resourceName":"customers/1234567890/campaigns/2","id":"2","name":"Second","status":"PAUSED","advertisingChannelType":"SHOPPING"},"customer":{"currencyCode":"MYR"}}]}`))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = server.URL
+	config.DeveloperToken = "dev_token"
+	config.LoginCustomerID = "111-222-3333"
+	connector := NewConnector(config)
+
+	campaigns, err := connector.GetCampaigns(context.Background(), "access_token", "123-456-7890")
+	if err != nil {
+		t.Fatalf("GetCampaigns() error = %v", err)
+	}
+
+	if calls != 2 {
+		t.Errorf("search calls = %d, want %d", calls, 2)
+	}
+
+	if len(campaigns) != 2 {
+		t.Fatalf("len(campaigns) = %d, want %d", len(campaigns), 2)
+	}
+
+	first := campaigns[0]
+	if first.PlatformCampaignID != "customers/1234567890/campaigns/1" {
+		t.Errorf("PlatformCampaignID = %q, want resource name", first.PlatformCampaignID)
+	}
+	if first.DailyBudget == nil || !first.DailyBudget.Equal(decimal.NewFromInt(50)) {
+		t.Errorf("DailyBudget = %v, want 50", first.DailyBudget)
+	}
+	if first.Status != entity.CampaignStatusActive {
+		t.Errorf("Status = %q, want %q", first.Status, entity.CampaignStatusActive)
+	}
+
+	if campaigns[1].Objective != entity.ObjectiveSales {
+		t.Errorf("Objective = %q, want %q", campaigns[1].Objective, entity.ObjectiveSales)
+	}
+}
+
+func TestGetCampaignInsights_MapsMicros(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if !strings.Contains(body["query"], "segments.date BETWEEN '2024-01-01' AND '2024-01-07'") {
+			t.Errorf("query = %q, missing date filter", body["query"])
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"results":[{"segments":{"date":"2024-01-02"},"metrics":{"impressions":"1000","clicks":"50","costMicros":"25500000","conversions":5,"conversionsValue":102},"customer":{"currencyCode":"USD"}}]}`))
+	}))
+	defer server.Close()
+
+	config := DefaultConfig()
+	config.BaseURL = server.URL
+	connector := NewConnector(config)
+
+	dateRange := entity.DateRange{
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 1, 7, 0, 0, 0, 0, time.UTC),
+	}
+
+	metrics, err := connector.GetCampaignInsights(context.Background(), "access_token", "customers/1234567890/campaigns/99", dateRange)
+	if err != nil {
+		t.Fatalf("GetCampaignInsights() error = %v", err)
+	}
+
+	if len(metrics) != 1 {
+		t.Fatalf("len(metrics) = %d, want %d", len(metrics), 1)
+	}
+
+	m := metrics[0]
+	if !m.Spend.Equal(decimal.NewFromFloat(25.5)) {
+		t.Errorf("Spend = %v, want 25.5", m.Spend)
+	}
+	if m.Impressions != 1000 || m.Clicks != 50 || m.Conversions != 5 {
+		t.Errorf("Impressions/Clicks/Conversions = %d/%d/%d, want 1000/50/5", m.Impressions, m.Clicks, m.Conversions)
+	}
+	if m.Currency != "USD" {
+		t.Errorf("Currency = %q, want %q", m.Currency, "USD")
+	}
+	if m.ROAS == nil || *m.ROAS != 4 {
+		t.Errorf("ROAS = %v, want 4", m.ROAS)
+	}
+}
+
+// ============================================================================
+// Helper Tests
+// ============================================================================
+
+func TestParseResourceName(t *testing.T) {
+	tests := []struct {
+		input        string
+		wantCustomer string
+		wantID       string
+		wantErr      bool
+	}{
+		{"customers/123/campaigns/456", "123", "456", false},
+		{"customers/123/adGroupAds/10~20", "123", "10~20", false},
+		{"456", "", "", true},
+		{"customers//campaigns/456", "", "", true},
+		{"customers/1/campaigns/1 OR campaign.id > 0", "", "", true},
+		{"customers/1 OR 1=1/campaigns/1", "", "", true},
+		{"customers/1/adGroupAds/10~20 OR ad_group.id > 0", "", "", true},
+		{"customers/1/adGroupAds/10~", "", "", true},
+		{"customers/123-456/campaigns/1", "", "", true},
+		{"customers/1/campaigns/-1", "", "", true},
+	}
+
+	for _, tt := range tests {
+		customerID, id, err := parseResourceName(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseResourceName(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if customerID != tt.wantCustomer || id != tt.wantID {
+			t.Errorf("parseResourceName(%q) = %q, %q, want %q, %q", tt.input, customerID, id, tt.wantCustomer, tt.wantID)
+		}
+	}
+}
+
+func TestMapStatus(t *testing.T) {
+	connector := NewConnector(nil)
+
+	tests := []struct {
+		input    string
+		expected entity.CampaignStatus
+	}{
+		{"ENABLED", entity.CampaignStatusActive},
+		{"PAUSED", entity.CampaignStatusPaused},
+		{"REMOVED", entity.CampaignStatusDeleted},
+		{"UNKNOWN", entity.CampaignStatusDraft},
+	}
+
+	for _, tt := range tests {
+		result := connector.mapStatus(tt.input)
+		if result != tt.expected {
+			t.Errorf("mapStatus(%q) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestHandleGoogleError(t *testing.T) {
+	connector := NewConnector(nil)
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		err := connector.HandleGoogleError(http.StatusUnauthorized, []byte(`{"error":{"code":401,"message":"Request had invalid authentication credentials.","status":"UNAUTHENTICATED"}}`))
+		if _, ok := err.(*errors.TokenError); !ok {
+			t.Errorf("HandleGoogleError() type = %T, want *errors.TokenError", err)
+		}
+	})
+
+	t.Run("resource exhausted", func(t *testing.T) {
+		err := connector.HandleGoogleError(http.StatusTooManyRequests, []byte(`{"error":{"code":429,"message":"Too many requests.","status":"RESOURCE_EXHAUSTED"}}`))
+		if _, ok := err.(*errors.RateLimitError); !ok {
+			t.Errorf("HandleGoogleError() type = %T, want *errors.RateLimitError", err)
+		}
+	})
+
+	t.Run("unparseable body", func(t *testing.T) {
+		err := connector.HandleGoogleError(http.StatusBadGateway, []byte(`<html>bad gateway</html>`))
+		if _, ok := err.(*errors.PlatformAPIError); !ok {
+			t.Errorf("HandleGoogleError() type = %T, want *errors.PlatformAPIError", err)
+		}
+	})
+}
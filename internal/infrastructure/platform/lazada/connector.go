@@ -0,0 +1,1282 @@
+package lazada
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/service"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/platform"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ============================================================================
+// Region Configuration
+// ============================================================================
+
+// Region represents a Lazada region/market
+type Region string
+
+const (
+	RegionMalaysia    Region = "MY"
+	RegionSingapore   Region = "SG"
+	RegionThailand    Region = "TH"
+	RegionVietnam     Region = "VN"
+	RegionPhilippines Region = "PH"
+	RegionIndonesia   Region = "ID"
+)
+
+// RegionBaseURL returns the API gateway URL for a specific region
+func RegionBaseURL(region Region) string {
+	switch region {
+	case RegionMalaysia:
+		return "https://api.lazada.com.my/rest"
+	case RegionSingapore:
+		return "https://api.lazada.sg/rest"
+	case RegionThailand:
+		return "https://api.lazada.co.th/rest"
+	case RegionVietnam:
+		return "https://api.lazada.vn/rest"
+	case RegionPhilippines:
+		return "https://api.lazada.com.ph/rest"
+	case RegionIndonesia:
+		return "https://api.lazada.co.id/rest"
+	default:
+		return "https://api.lazada.com.my/rest"
+	}
+}
+
+// RegionCurrency returns the default currency for a region
+func RegionCurrency(region Region) string {
+	switch region {
+	case RegionMalaysia:
+		return "MYR"
+	case RegionSingapore:
+		return "SGD"
+	case RegionThailand:
+		return "THB"
+	case RegionVietnam:
+		return "VND"
+	case RegionPhilippines:
+		return "PHP"
+	case RegionIndonesia:
+		return "IDR"
+	default:
+		return "MYR"
+	}
+}
+
+// RegionTimezone returns the seller timezone for a region
+func RegionTimezone(region Region) string {
+	switch region {
+	case RegionMalaysia:
+		return "Asia/Kuala_Lumpur"
+	case RegionSingapore:
+		return "Asia/Singapore"
+	case RegionThailand:
+		return "Asia/Bangkok"
+	case RegionVietnam:
+		return "Asia/Ho_Chi_Minh"
+	case RegionPhilippines:
+		return "Asia/Manila"
+	case RegionIndonesia:
+		return "Asia/Jakarta"
+	default:
+		return "Asia/Kuala_Lumpur"
+	}
+}
+
+// ============================================================================
+// Constants
+// ============================================================================
+
+const (
+	// OAuth endpoints (shared across all regions)
+	authURL         = "https://auth.lazada.com/oauth/authorize"
+	authBaseURL     = "https://auth.lazada.com/rest"
+	tokenCreatePath = "/auth/token/create"
+	tokenRefresh    = "/auth/token/refresh"
+
+	// Seller API paths
+	getSellerPath = "/seller/get"
+
+	// Sponsored Solutions API paths
+	getCampaignListPath   = "/sponsor/solutions/campaign/searchCampaignList"
+	getCampaignReportPath = "/sponsor/solutions/report/getReportCampaignOnFilter"
+
+	// Order API paths
+	getOrdersPath         = "/orders/get"
+	getMultipleOrderItems = "/orders/items/get"
+
+	// Sponsored Discovery business code
+	BizCodeSponsoredDiscovery = "sponsoredSearch"
+
+	// Signing method
+	signMethod = "sha256"
+
+	// Default pagination
+	DefaultPageSize = 100
+
+	// Lazada allows at most 50 order IDs per /orders/items/get call
+	maxOrderItemsBatch = 50
+)
+
+// ============================================================================
+// Error Codes
+// ============================================================================
+
+const (
+	// Common Lazada Open Platform error codes
+	LazadaErrorIllegalAccessToken  = "IllegalAccessToken"
+	LazadaErrorIllegalRefreshToken = "IllegalRefreshToken"
+	LazadaErrorIncompleteSignature = "IncompleteSignature"
+	LazadaErrorInvalidTimestamp    = "InvalidTimestamp"
+	LazadaErrorAppCallLimit        = "AppCallLimit"
+	LazadaErrorApiCallLimit        = "ApiCallLimit"
+	LazadaErrorAccessDenied        = "AccessDenied"
+	LazadaErrorMissingParameter    = "MissingParameter"
+	LazadaErrorInvalidParameter    = "InvalidParameter"
+	LazadaErrorServiceTimeout      = "ServiceTimeout"
+	LazadaErrorInternalError       = "InternalError"
+	LazadaErrorServiceUnavailable  = "ServiceUnavailable"
+)
+
+// Connector implements the PlatformConnector interface for Lazada Sponsored Solutions
+type Connector struct {
+	*platform.BaseConnector
+	config      *Config
+	baseURL     string
+	authBaseURL string
+}
+
+// Config holds Lazada-specific configuration
+type Config struct {
+	AppKey          string
+	AppSecret       string
+	RedirectURI     string
+	Region          Region // MY, SG, TH, VN, PH, ID
+	BaseURL         string // Overrides the regional API gateway (e.g. the local mock API)
+	AuthBaseURL     string // Overrides the OAuth token gateway (e.g. the local mock API)
+	RateLimitCalls  int
+	RateLimitWindow time.Duration
+	Timeout         time.Duration
+	MaxRetries      int
+}
+
+// DefaultConfig returns default Lazada connector configuration
+func DefaultConfig() *Config {
+	return &Config{
+		Region:          RegionMalaysia,
+		RateLimitCalls:  500,
+		RateLimitWindow: time.Minute,
+		Timeout:         30 * time.Second,
+		MaxRetries:      3,
+	}
+}
+
+// NewConnector creates a new Lazada connector
+func NewConnector(config *Config) *Connector {
+	if config == nil {
+		config = DefaultConfig()
+	}
+
+	regionURL := RegionBaseURL(config.Region)
+	if config.BaseURL != "" {
+		regionURL = strings.TrimRight(config.BaseURL, "/")
+	}
+
+	tokenURL := authBaseURL
+	if config.AuthBaseURL != "" {
+		tokenURL = strings.TrimRight(config.AuthBaseURL, "/")
+	}
+
+	baseConfig := &platform.ConnectorConfig{
+		AppID:           config.AppKey,
+		AppSecret:       config.AppSecret,
+		RedirectURI:     config.RedirectURI,
+		BaseURL:         regionURL,
+		RateLimitCalls:  config.RateLimitCalls,
+		RateLimitWindow: config.RateLimitWindow,
+		Timeout:         config.Timeout,
+		MaxRetries:      config.MaxRetries,
+	}
+
+	return &Connector{
+		BaseConnector: platform.NewBaseConnector(entity.PlatformLazada, baseConfig),
+		config:        config,
+		baseURL:       regionURL,
+		authBaseURL:   tokenURL,
+	}
+}
+
+// NewConnectorWithRegion creates a new Lazada connector for a specific region
+func NewConnectorWithRegion(appKey string, appSecret string, region Region) *Connector {
+	config := DefaultConfig()
+	config.AppKey = appKey
+	config.AppSecret = appSecret
+	config.Region = region
+	return NewConnector(config)
+}
+
+// GetRegion returns the configured region
+func (c *Connector) GetRegion() Region {
+	return c.config.Region
+}
+
+// GetCurrency returns the default currency for the configured region
+func (c *Connector) GetCurrency() string {
+	return RegionCurrency(c.config.Region)
+}
+
+// ============================================================================
+// OAuth Methods
+// ============================================================================
+
+// GetAuthURL generates the OAuth authorization URL
+func (c *Connector) GetAuthURL(state string) string {
+	params := url.Values{
+		"response_type": {"code"},
+		"force_auth":    {"true"},
+		"redirect_uri":  {c.config.RedirectURI},
+		"client_id":     {c.config.AppKey},
+		"state":         {state},
+	}
+	return authURL + "?" + params.Encode()
+}
+
+// ExchangeCode exchanges an authorization code for OAuth tokens
+func (c *Connector) ExchangeCode(ctx context.Context, code string) (*entity.OAuthToken, error) {
+	return c.requestToken(ctx, tokenCreatePath, map[string]string{"code": code})
+}
+
+// RefreshToken refreshes an expired access token
+func (c *Connector) RefreshToken(ctx context.Context, refreshToken string) (*entity.OAuthToken, error) {
+	return c.requestToken(ctx, tokenRefresh, map[string]string{"refresh_token": refreshToken})
+}
+
+// RevokeToken revokes an access token
+func (c *Connector) RevokeToken(ctx context.Context, accessToken string) error {
+	// Lazada doesn't have a token revocation endpoint; sellers revoke from Seller Center
+	return nil
+}
+
+func (c *Connector) requestToken(ctx context.Context, path string, params map[string]string) (*entity.OAuthToken, error) {
+	signParams := c.SignRequestWithParams(path, "", params)
+
+	resp, err := c.DoGet(ctx, c.authBaseURL+path, nil, signParams)
+	if err != nil {
+		if resp != nil {
+			return nil, c.handleErrorBody(resp.StatusCode, resp.Body)
+		}
+		return nil, err
+	}
+
+	var tokenResp struct {
+		Code             string `json:"code"`
+		Type             string `json:"type"`
+		Message          string `json:"message"`
+		AccessToken      string `json:"access_token"`
+		RefreshToken     string `json:"refresh_token"`
+		ExpiresIn        int    `json:"expires_in"`
+		RefreshExpiresIn int    `json:"refresh_expires_in"`
+		Account          string `json:"account"`
+		Country          string `json:"country"`
+	}
+
+	if err := c.ParseJSON(resp.Body, &tokenResp); err != nil {
+		return nil, err
+	}
+
+	if tokenResp.Code != "" && tokenResp.Code != "0" {
+		return nil, errors.NewOAuthError(
+			entity.PlatformLazada.String(),
+			tokenResp.Code,
+			tokenResp.Message,
+		)
+	}
+
+	return &entity.OAuthToken{
+		AccessToken:  tokenResp.AccessToken,
+		RefreshToken: tokenResp.RefreshToken,
+		TokenType:    "Bearer",
+		ExpiresIn:    tokenResp.ExpiresIn,
+		ExpiresAt:    time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// ============================================================================
+// User & Account Methods
+// ============================================================================
+
+// lazadaSeller represents the /seller/get response payload
+type lazadaSeller struct {
+	SellerID    int64  `json:"seller_id"`
+	Name        string `json:"name"`
+	NameCompany string `json:"name_company"`
+	Email       string `json:"email"`
+	ShortCode   string `json:"short_code"`
+	Status      string `json:"status"`
+	Location    string `json:"location"`
+	CrossBorder bool   `json:"cb"`
+	LogoURL     string `json:"logo_url"`
+}
+
+func (c *Connector) getSeller(ctx context.Context, accessToken string) (*lazadaSeller, error) {
+	data, err := c.call(ctx, getSellerPath, accessToken, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var seller lazadaSeller
+	if err := json.Unmarshal(data, &seller); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodePlatformAPI, "failed to parse Lazada seller", http.StatusBadGateway)
+	}
+
+	return &seller, nil
+}
+
+// GetUserInfo retrieves the authenticated seller's information
+func (c *Connector) GetUserInfo(ctx context.Context, accessToken string) (*entity.PlatformUser, error) {
+	seller, err := c.getSeller(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	return &entity.PlatformUser{
+		ID:        strconv.FormatInt(seller.SellerID, 10),
+		Name:      seller.Name,
+		Email:     seller.Email,
+		AvatarURL: seller.LogoURL,
+	}, nil
+}
+
+// GetAdAccounts retrieves the seller account accessible by the token.
+// Lazada tokens are scoped to a single seller per region, so this returns one account.
+func (c *Connector) GetAdAccounts(ctx context.Context, accessToken string) ([]entity.PlatformAccount, error) {
+	seller, err := c.getSeller(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	status := strings.ToLower(seller.Status)
+	if status == "" {
+		status = "active"
+	}
+
+	return []entity.PlatformAccount{
+		{
+			ID:       strconv.FormatInt(seller.SellerID, 10),
+			Name:     seller.Name,
+			Currency: c.GetCurrency(),
+			Timezone: RegionTimezone(c.config.Region),
+			Status:   status,
+		},
+	}, nil
+}
+
+// ============================================================================
+// Campaign Methods
+// ============================================================================
+
+// LazadaCampaign represents a Sponsored Discovery campaign
+type LazadaCampaign struct {
+	CampaignID   int64        `json:"campaignId"`
+	CampaignName string       `json:"campaignName"`
+	CampaignType string       `json:"campaignType"`
+	Status       string       `json:"status"`
+	DayBudget    lazadaNumber `json:"dayBudget"`
+	TotalBudget  lazadaNumber `json:"totalBudget"`
+	StartDate    string       `json:"startDate"`
+	EndDate      string       `json:"endDate"`
+	CreateTime   int64        `json:"createTime"` // Unix milliseconds
+	UpdateTime   int64        `json:"updateTime"` // Unix milliseconds
+}
+
+// GetCampaigns retrieves all Sponsored Discovery campaigns for a seller
+func (c *Connector) GetCampaigns(ctx context.Context, accessToken string, sellerID string) ([]entity.Campaign, error) {
+	lazadaCampaigns, err := c.GetAllCampaigns(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	campaigns := make([]entity.Campaign, 0, len(lazadaCampaigns))
+	for _, lc := range lazadaCampaigns {
+		campaigns = append(campaigns, c.mapCampaign(lc))
+	}
+
+	return campaigns, nil
+}
+
+// GetCampaign retrieves a single campaign by ID
+func (c *Connector) GetCampaign(ctx context.Context, accessToken string, campaignID string) (*entity.Campaign, error) {
+	// The Sponsored Solutions API has no single campaign endpoint, so filter the list
+	lazadaCampaigns, err := c.GetAllCampaigns(ctx, accessToken)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, lc := range lazadaCampaigns {
+		if strconv.FormatInt(lc.CampaignID, 10) == campaignID {
+			campaign := c.mapCampaign(lc)
+			return &campaign, nil
+		}
+	}
+
+	return nil, errors.ErrNotFound("Campaign")
+}
+
+// GetAllCampaigns retrieves all Sponsored Discovery campaigns with pagination
+func (c *Connector) GetAllCampaigns(ctx context.Context, accessToken string) ([]LazadaCampaign, error) {
+	var allCampaigns []LazadaCampaign
+	pageNo := 1
+
+	for {
+		data, err := c.call(ctx, getCampaignListPath, accessToken, map[string]string{
+			"bizCode":  BizCodeSponsoredDiscovery,
+			"pageNo":   strconv.Itoa(pageNo),
+			"pageSize": strconv.Itoa(DefaultPageSize),
+		})
+		if err != nil {
+			return allCampaigns, err
+		}
+
+		var page struct {
+			TotalCount int              `json:"totalCount"`
+			Result     []LazadaCampaign `json:"result"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return allCampaigns, errors.Wrap(err, errors.ErrCodePlatformAPI, "failed to parse Lazada campaigns", http.StatusBadGateway)
+		}
+
+		allCampaigns = append(allCampaigns, page.Result...)
+
+		if len(page.Result) < DefaultPageSize || len(allCampaigns) >= page.TotalCount {
+			break
+		}
+		pageNo++
+
+		select {
+		case <-ctx.Done():
+			return allCampaigns, ctx.Err()
+		default:
+		}
+	}
+
+	return allCampaigns, nil
+}
+
+// ============================================================================
+// AdSet Methods (Sponsored Discovery has no ad sets, uses flat structure)
+// ============================================================================
+
+// GetAdSets returns empty as Lazada uses flat campaign structure
+func (c *Connector) GetAdSets(ctx context.Context, accessToken string, campaignID string) ([]entity.AdSet, error) {
+	return []entity.AdSet{}, nil
+}
+
+// GetAdSet returns nil as Lazada doesn't have ad sets
+func (c *Connector) GetAdSet(ctx context.Context, accessToken string, adSetID string) (*entity.AdSet, error) {
+	return nil, errors.ErrNotFound("AdSet")
+}
+
+// ============================================================================
+// Ad Methods
+// ============================================================================
+
+// GetAds returns empty as Lazada reports at campaign level
+func (c *Connector) GetAds(ctx context.Context, accessToken string, adSetID string) ([]entity.Ad, error) {
+	return []entity.Ad{}, nil
+}
+
+// GetAd retrieves a single ad by ID
+func (c *Connector) GetAd(ctx context.Context, accessToken string, adID string) (*entity.Ad, error) {
+	return nil, errors.ErrNotFound("Ad")
+}
+
+// ============================================================================
+// Insights Methods
+// ============================================================================
+
+// LazadaDailyReport represents one day of Sponsored Discovery campaign performance
+type LazadaDailyReport struct {
+	Date          string       `json:"date"`
+	CampaignID    int64        `json:"campaignId"`
+	Impressions   lazadaNumber `json:"impressions"`
+	Clicks        lazadaNumber `json:"clicks"`
+	Spend         lazadaNumber `json:"spend"`
+	Orders        lazadaNumber `json:"orders"`
+	UnitsSold     lazadaNumber `json:"unitsSold"`
+	Revenue       lazadaNumber `json:"revenue"`
+	DirectOrders  lazadaNumber `json:"directOrders"`
+	DirectRevenue lazadaNumber `json:"directRevenue"`
+	AddToCart     lazadaNumber `json:"addToCart"`
+}
+
+// GetCampaignInsights retrieves daily performance metrics for a campaign
+func (c *Connector) GetCampaignInsights(ctx context.Context, accessToken string, campaignID string, dateRange entity.DateRange) ([]entity.CampaignMetricsDaily, error) {
+	reports, err := c.GetCampaignDailyReport(ctx, accessToken, campaignID, dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.MapToNormalizedMetrics(reports, uuid.Nil, uuid.Nil), nil
+}
+
+// GetAdSetInsights returns nil as Lazada doesn't have ad sets
+func (c *Connector) GetAdSetInsights(ctx context.Context, accessToken string, adSetID string, dateRange entity.DateRange) ([]entity.AdSetMetricsDaily, error) {
+	return nil, nil
+}
+
+// GetAdInsights returns nil as Lazada uses campaign-level metrics
+func (c *Connector) GetAdInsights(ctx context.Context, accessToken string, adID string, dateRange entity.DateRange) ([]entity.AdMetricsDaily, error) {
+	return nil, nil
+}
+
+// GetAccountInsights retrieves aggregated Sponsored Discovery insights for a seller
+func (c *Connector) GetAccountInsights(ctx context.Context, accessToken string, sellerID string, dateRange entity.DateRange) (*entity.AggregatedMetrics, error) {
+	// An empty campaign ID reports across all campaigns
+	reports, err := c.GetCampaignDailyReport(ctx, accessToken, "", dateRange)
+	if err != nil {
+		return nil, err
+	}
+
+	aggregated := &entity.AggregatedMetrics{
+		Currency: c.GetCurrency(),
+	}
+
+	for _, r := range reports {
+		aggregated.TotalSpend = aggregated.TotalSpend.Add(r.Spend.Decimal())
+		aggregated.TotalImpressions += r.Impressions.Int64()
+		aggregated.TotalClicks += r.Clicks.Int64()
+		aggregated.TotalConversions += r.Orders.Int64()
+		aggregated.TotalRevenue = aggregated.TotalRevenue.Add(r.Revenue.Decimal())
+	}
+
+	// Calculate derived metrics
+	if aggregated.TotalImpressions > 0 {
+		aggregated.AverageCTR = float64(aggregated.TotalClicks) / float64(aggregated.TotalImpressions) * 100
+		aggregated.AverageCPM = aggregated.TotalSpend.Div(decimal.NewFromInt(aggregated.TotalImpressions)).Mul(decimal.NewFromInt(1000))
+	}
+
+	if aggregated.TotalClicks > 0 {
+		aggregated.AverageCPC = aggregated.TotalSpend.Div(decimal.NewFromInt(aggregated.TotalClicks))
+	}
+
+	if aggregated.TotalConversions > 0 {
+		aggregated.AverageCPA = aggregated.TotalSpend.Div(decimal.NewFromInt(aggregated.TotalConversions))
+	}
+
+	if aggregated.TotalSpend.IsPositive() {
+		aggregated.OverallROAS, _ = aggregated.TotalRevenue.Div(aggregated.TotalSpend).Float64()
+	}
+
+	return aggregated, nil
+}
+
+// GetCampaignDailyReport retrieves the day-by-day report for a campaign, or for all
+// campaigns when campaignID is empty
+func (c *Connector) GetCampaignDailyReport(ctx context.Context, accessToken string, campaignID string, dateRange entity.DateRange) ([]LazadaDailyReport, error) {
+	var allReports []LazadaDailyReport
+	pageNo := 1
+
+	for {
+		params := map[string]string{
+			"bizCode":   BizCodeSponsoredDiscovery,
+			"startDate": dateRange.StartDate.Format("2006-01-02"),
+			"endDate":   dateRange.EndDate.Format("2006-01-02"),
+			"dimension": "day",
+			"pageNo":    strconv.Itoa(pageNo),
+			"pageSize":  strconv.Itoa(DefaultPageSize),
+		}
+		if campaignID != "" {
+			params["campaignId"] = campaignID
+		}
+
+		data, err := c.call(ctx, getCampaignReportPath, accessToken, params)
+		if err != nil {
+			return allReports, err
+		}
+
+		var page struct {
+			TotalCount int                 `json:"totalCount"`
+			Result     []LazadaDailyReport `json:"result"`
+		}
+		if err := json.Unmarshal(data, &page); err != nil {
+			return allReports, errors.Wrap(err, errors.ErrCodePlatformAPI, "failed to parse Lazada campaign report", http.StatusBadGateway)
+		}
+
+		allReports = append(allReports, page.Result...)
+
+		if len(page.Result) < DefaultPageSize || len(allReports) >= page.TotalCount {
+			break
+		}
+		pageNo++
+
+		select {
+		case <-ctx.Done():
+			return allReports, ctx.Err()
+		default:
+		}
+	}
+
+	return allReports, nil
+}
+
+// MapToNormalizedMetrics converts Lazada daily reports to normalized CampaignMetricsDaily
+func (c *Connector) MapToNormalizedMetrics(reports []LazadaDailyReport, campaignID uuid.UUID, orgID uuid.UUID) []entity.CampaignMetricsDaily {
+	var metrics []entity.CampaignMetricsDaily
+
+	for _, r := range reports {
+		metricDate, _ := time.Parse("2006-01-02", r.Date)
+
+		metric := entity.CampaignMetricsDaily{
+			BaseEntity:      entity.NewBaseEntity(),
+			CampaignID:      campaignID,
+			OrganizationID:  orgID,
+			Platform:        entity.PlatformLazada,
+			MetricDate:      metricDate,
+			Currency:        c.GetCurrency(),
+			Impressions:     r.Impressions.Int64(),
+			Clicks:          r.Clicks.Int64(),
+			Spend:           r.Spend.Decimal(),
+			Purchases:       r.DirectOrders.Int64(),
+			PurchaseValue:   r.DirectRevenue.Decimal(),
+			AddToCart:       r.AddToCart.Int64(),
+			Conversions:     r.Orders.Int64(),
+			ConversionValue: r.Revenue.Decimal(),
+			PlatformMetrics: entity.JSONMap{
+				"lazada_campaign_id": r.CampaignID,
+				"units_sold":         r.UnitsSold.Int64(),
+				"direct_orders":      r.DirectOrders.Int64(),
+				"direct_revenue":     r.DirectRevenue.Float64(),
+			},
+		}
+
+		metric.CalculateDerivedMetrics()
+
+		now := time.Now()
+		metric.LastSyncedAt = &now
+
+		metrics = append(metrics, metric)
+	}
+
+	return metrics
+}
+
+// HealthCheck verifies the connector is configured to call the Lazada API
+func (c *Connector) HealthCheck(ctx context.Context) error {
+	if c.config.AppKey == "" || c.config.AppSecret == "" {
+		return errors.New(errors.ErrCodePlatformUnavailable, "Lazada app credentials are not configured", http.StatusServiceUnavailable)
+	}
+	return nil
+}
+
+// ============================================================================
+// Order API Methods (for Revenue Calculation)
+// ============================================================================
+
+// Lazada order statuses
+const (
+	OrderStatusUnpaid      = "unpaid"
+	OrderStatusPending     = "pending"
+	OrderStatusPacked      = "packed"
+	OrderStatusReadyToShip = "ready_to_ship"
+	OrderStatusShipped     = "shipped"
+	OrderStatusDelivered   = "delivered"
+	OrderStatusCanceled    = "canceled"
+	OrderStatusReturned    = "returned"
+	OrderStatusFailed      = "failed"
+	OrderStatusShippedBack = "shipped_back"
+)
+
+// LazadaOrder represents an order from /orders/get
+type LazadaOrder struct {
+	OrderID           int64             `json:"order_id"`
+	OrderNumber       int64             `json:"order_number"`
+	CreatedAt         string            `json:"created_at"` // "2006-01-02 15:04:05 -0700"
+	UpdatedAt         string            `json:"updated_at"`
+	Price             lazadaNumber      `json:"price"` // Sum of item prices before vouchers
+	ShippingFee       lazadaNumber      `json:"shipping_fee"`
+	Voucher           lazadaNumber      `json:"voucher"`
+	VoucherSeller     lazadaNumber      `json:"voucher_seller"`
+	VoucherPlatform   lazadaNumber      `json:"voucher_platform"`
+	Statuses          []string          `json:"statuses"`
+	ItemsCount        int               `json:"items_count"`
+	PaymentMethod     string            `json:"payment_method"`
+	CustomerFirstName string            `json:"customer_first_name"`
+	WarehouseCode     string            `json:"warehouse_code"`
+	Items             []LazadaOrderItem `json:"-"`
+}
+
+// LazadaOrderItem represents a single order line. Lazada returns one line per unit sold.
+type LazadaOrderItem struct {
+	OrderItemID      int64        `json:"order_item_id"`
+	OrderID          int64        `json:"order_id"`
+	ProductID        int64        `json:"product_id"`
+	Name             string       `json:"name"`
+	SKU              string       `json:"sku"`
+	ShopSKU          string       `json:"shop_sku"`
+	Variation        string       `json:"variation"`
+	ItemPrice        lazadaNumber `json:"item_price"`
+	PaidPrice        lazadaNumber `json:"paid_price"`
+	VoucherAmount    lazadaNumber `json:"voucher_amount"`
+	Status           string       `json:"status"`
+	TrackingCode     string       `json:"tracking_code"`
+	ShipmentProvider string       `json:"shipment_provider"`
+	Currency         string       `json:"currency"`
+}
+
+// GetOrderListParams holds parameters for order list request
+type GetOrderListParams struct {
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time // Filters on update time instead of creation time when set
+	UpdatedBefore time.Time
+	Status        string // Optional filter
+	Offset        int
+	Limit         int
+}
+
+// GetOrderList retrieves one page of orders, returning the total count reported by Lazada
+func (c *Connector) GetOrderList(ctx context.Context, accessToken string, params GetOrderListParams) ([]LazadaOrder, int, error) {
+	if params.Limit <= 0 {
+		params.Limit = DefaultPageSize
+	}
+
+	query := map[string]string{
+		"offset":         strconv.Itoa(params.Offset),
+		"limit":          strconv.Itoa(params.Limit),
+		"sort_direction": "ASC",
+	}
+	if !params.UpdatedAfter.IsZero() {
+		query["update_after"] = params.UpdatedAfter.Format(time.RFC3339)
+		query["update_before"] = params.UpdatedBefore.Format(time.RFC3339)
+		query["sort_by"] = "updated_at"
+	} else {
+		query["created_after"] = params.CreatedAfter.Format(time.RFC3339)
+		query["created_before"] = params.CreatedBefore.Format(time.RFC3339)
+		query["sort_by"] = "created_at"
+	}
+	if params.Status != "" {
+		query["status"] = params.Status
+	}
+
+	data, err := c.call(ctx, getOrdersPath, accessToken, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var page struct {
+		Count      int           `json:"count"`
+		CountTotal int           `json:"countTotal"`
+		Orders     []LazadaOrder `json:"orders"`
+	}
+	if err := json.Unmarshal(data, &page); err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodePlatformAPI, "failed to parse Lazada orders", http.StatusBadGateway)
+	}
+
+	return page.Orders, page.CountTotal, nil
+}
+
+// GetAllOrders retrieves all orders created within a time range, including their items
+func (c *Connector) GetAllOrders(ctx context.Context, accessToken string, startTime, endTime time.Time) ([]LazadaOrder, error) {
+	return c.listOrders(ctx, accessToken, GetOrderListParams{
+		CreatedAfter:  startTime,
+		CreatedBefore: endTime,
+		Limit:         DefaultPageSize,
+	})
+}
+
+// GetUpdatedOrders retrieves the orders updated within a time range, including
+// their items, as normalized orders. It implements service.OrderConnector; Lazada
+// tokens are scoped to one seller, so shopID is not needed, and the caller sets
+// the organization.
+func (c *Connector) GetUpdatedOrders(ctx context.Context, accessToken string, shopID string, startTime, endTime time.Time) ([]entity.Order, error) {
+	orders, err := c.listOrders(ctx, accessToken, GetOrderListParams{
+		UpdatedAfter:  startTime,
+		UpdatedBefore: endTime,
+		Limit:         DefaultPageSize,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return c.MapToOrders(orders, uuid.Nil), nil
+}
+
+// listOrders pages through every order matching params and attaches their items
+func (c *Connector) listOrders(ctx context.Context, accessToken string, params GetOrderListParams) ([]LazadaOrder, error) {
+	var allOrders []LazadaOrder
+
+	for {
+		orders, total, err := c.GetOrderList(ctx, accessToken, params)
+		if err != nil {
+			return allOrders, err
+		}
+
+		allOrders = append(allOrders, orders...)
+
+		if len(orders) < params.Limit || len(allOrders) >= total {
+			break
+		}
+		params.Offset += len(orders)
+
+		select {
+		case <-ctx.Done():
+			return allOrders, ctx.Err()
+		default:
+		}
+	}
+
+	if err := c.attachOrderItems(ctx, accessToken, allOrders); err != nil {
+		return allOrders, err
+	}
+
+	return allOrders, nil
+}
+
+// attachOrderItems fetches order lines in batches and attaches them to their orders
+func (c *Connector) attachOrderItems(ctx context.Context, accessToken string, orders []LazadaOrder) error {
+	index := make(map[int64]int, len(orders))
+	for i, o := range orders {
+		index[o.OrderID] = i
+	}
+
+	for i := 0; i < len(orders); i += maxOrderItemsBatch {
+		end := i + maxOrderItemsBatch
+		if end > len(orders) {
+			end = len(orders)
+		}
+
+		ids := make([]string, 0, end-i)
+		for _, o := range orders[i:end] {
+			ids = append(ids, strconv.FormatInt(o.OrderID, 10))
+		}
+
+		data, err := c.call(ctx, getMultipleOrderItems, accessToken, map[string]string{
+			"order_ids": "[" + strings.Join(ids, ",") + "]",
+		})
+		if err != nil {
+			return err
+		}
+
+		var batch []struct {
+			OrderID    int64             `json:"order_id"`
+			OrderItems []LazadaOrderItem `json:"order_items"`
+		}
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return errors.Wrap(err, errors.ErrCodePlatformAPI, "failed to parse Lazada order items", http.StatusBadGateway)
+		}
+
+		for _, b := range batch {
+			if idx, ok := index[b.OrderID]; ok {
+				orders[idx].Items = b.OrderItems
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+	}
+
+	return nil
+}
+
+// MapToOrder converts a Lazada order into the normalized order stored in the orders table
+func (c *Connector) MapToOrder(lo LazadaOrder, orgID uuid.UUID) entity.Order {
+	createdAt := parseLazadaTime(lo.CreatedAt)
+	status := entity.OrderStatusPending
+	if len(lo.Statuses) > 0 {
+		status = mapOrderStatus(lo.Statuses[0])
+	}
+
+	subtotal := lo.Price.Decimal()
+	shippingFee := lo.ShippingFee.Decimal()
+	voucher := lo.Voucher.Decimal()
+	now := time.Now()
+
+	order := entity.Order{
+		OrganizationID:   orgID,
+		Platform:         entity.PlatformLazada,
+		PlatformOrderID:  strconv.FormatInt(lo.OrderID, 10),
+		PlatformOrderSN:  strconv.FormatInt(lo.OrderNumber, 10),
+		Status:           status,
+		CustomerName:     lo.CustomerFirstName,
+		Currency:         c.GetCurrency(),
+		Subtotal:         subtotal,
+		ShippingFee:      shippingFee,
+		VoucherAmount:    voucher,
+		SellerDiscount:   lo.VoucherSeller.Decimal(),
+		PlatformDiscount: lo.VoucherPlatform.Decimal(),
+		TotalAmount:      subtotal.Add(shippingFee).Sub(voucher),
+		OrderDate:        time.Date(createdAt.Year(), createdAt.Month(), createdAt.Day(), 0, 0, 0, 0, time.UTC),
+		OrderCreatedAt:   createdAt,
+		PlatformData: entity.JSONMap{
+			"payment_method": lo.PaymentMethod,
+			"items_count":    lo.ItemsCount,
+			"statuses":       lo.Statuses,
+			"warehouse_code": lo.WarehouseCode,
+		},
+		LastSyncedAt: &now,
+	}
+
+	switch status {
+	case entity.OrderStatusCancelled:
+		updatedAt := parseLazadaTime(lo.UpdatedAt)
+		order.CancelledAt = &updatedAt
+	case entity.OrderStatusDelivered:
+		updatedAt := parseLazadaTime(lo.UpdatedAt)
+		order.DeliveredAt = &updatedAt
+	}
+
+	for _, li := range lo.Items {
+		if order.TrackingNumber == "" && li.TrackingCode != "" {
+			order.TrackingNumber = li.TrackingCode
+			order.ShippingCarrier = li.ShipmentProvider
+		}
+
+		order.Items = append(order.Items, entity.OrderItem{
+			OrganizationID:    orgID,
+			PlatformItemID:    strconv.FormatInt(li.OrderItemID, 10),
+			PlatformProductID: strconv.FormatInt(li.ProductID, 10),
+			SKU:               li.SKU,
+			ProductName:       li.Name,
+			VariationName:     li.Variation,
+			Quantity:          1,
+			UnitPrice:         li.ItemPrice.Decimal(),
+			DiscountAmount:    li.VoucherAmount.Decimal(),
+			TotalPrice:        li.PaidPrice.Decimal(),
+			Status:            mapOrderStatus(li.Status),
+			PlatformData: entity.JSONMap{
+				"shop_sku": li.ShopSKU,
+			},
+		})
+	}
+
+	return order
+}
+
+// MapToOrders converts Lazada orders into normalized orders
+func (c *Connector) MapToOrders(orders []LazadaOrder, orgID uuid.UUID) []entity.Order {
+	result := make([]entity.Order, 0, len(orders))
+	for _, lo := range orders {
+		result = append(result, c.MapToOrder(lo, orgID))
+	}
+	return result
+}
+
+// CalculateRevenueFromOrders calculates total revenue from orders that count towards revenue
+func (c *Connector) CalculateRevenueFromOrders(orders []entity.Order) (totalRevenue decimal.Decimal, completedCount int) {
+	for _, order := range orders {
+		if order.Status.IsRevenue() {
+			totalRevenue = totalRevenue.Add(order.TotalAmount)
+			completedCount++
+		}
+	}
+	return
+}
+
+// CalculateDailyRevenue groups orders by date and calculates daily revenue
+func (c *Connector) CalculateDailyRevenue(orders []entity.Order) map[string]decimal.Decimal {
+	dailyRevenue := make(map[string]decimal.Decimal)
+
+	for _, order := range orders {
+		if order.Status.IsRevenue() {
+			date := order.OrderDate.Format("2006-01-02")
+			dailyRevenue[date] = dailyRevenue[date].Add(order.TotalAmount)
+		}
+	}
+
+	return dailyRevenue
+}
+
+// ============================================================================
+// Helper Methods
+// ============================================================================
+
+// generateSign generates the uppercase HMAC-SHA256 signature for the Lazada API
+func (c *Connector) generateSign(baseString string) string {
+	h := hmac.New(sha256.New, []byte(c.config.AppSecret))
+	h.Write([]byte(baseString))
+	return strings.ToUpper(hex.EncodeToString(h.Sum(nil)))
+}
+
+// SignRequest signs a request for the Lazada API.
+// Signature format: api_path + key1value1key2value2... with all parameters sorted by key
+func (c *Connector) SignRequest(path string, params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if k == "sign" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var sb strings.Builder
+	sb.WriteString(path)
+	for _, k := range keys {
+		sb.WriteString(k)
+		sb.WriteString(params[k])
+	}
+
+	return c.generateSign(sb.String())
+}
+
+// SignRequestWithParams adds the system parameters and signature to the business parameters
+func (c *Connector) SignRequestWithParams(path string, accessToken string, params map[string]string) map[string]string {
+	signed := make(map[string]string, len(params)+5)
+	for k, v := range params {
+		signed[k] = v
+	}
+
+	signed["app_key"] = c.config.AppKey
+	signed["timestamp"] = strconv.FormatInt(time.Now().UnixMilli(), 10)
+	signed["sign_method"] = signMethod
+	if accessToken != "" {
+		signed["access_token"] = accessToken
+	}
+
+	signed["sign"] = c.SignRequest(path, signed)
+	return signed
+}
+
+// call performs a signed GET against the regional API gateway and returns the "data" payload
+func (c *Connector) call(ctx context.Context, path string, accessToken string, params map[string]string) (json.RawMessage, error) {
+	signParams := c.SignRequestWithParams(path, accessToken, params)
+
+	resp, err := c.DoGet(ctx, c.baseURL+path, nil, signParams)
+	if err != nil {
+		if resp != nil {
+			return nil, c.handleErrorBody(resp.StatusCode, resp.Body)
+		}
+		return nil, err
+	}
+
+	var envelope lazadaResponse
+	if err := c.ParseJSON(resp.Body, &envelope); err != nil {
+		return nil, err
+	}
+
+	if envelope.Code != "" && envelope.Code != "0" {
+		return nil, c.HandleLazadaError(envelope.Code, envelope.Message, resp.StatusCode)
+	}
+
+	return envelope.Data, nil
+}
+
+func (c *Connector) handleErrorBody(statusCode int, body []byte) error {
+	var envelope lazadaResponse
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Code == "" {
+		return errors.NewPlatformAPIError(entity.PlatformLazada.String(), statusCode, "UNKNOWN",
+			fmt.Sprintf("API request failed with status %d", statusCode)).WithRawResponse(body)
+	}
+	return c.HandleLazadaError(envelope.Code, envelope.Message, statusCode)
+}
+
+func (c *Connector) mapCampaign(lc LazadaCampaign) entity.Campaign {
+	campaign := entity.Campaign{
+		Platform:             entity.PlatformLazada,
+		PlatformCampaignID:   strconv.FormatInt(lc.CampaignID, 10),
+		PlatformCampaignName: lc.CampaignName,
+		Status:               c.mapCampaignStatus(lc.Status),
+		Objective:            c.mapObjective(lc.CampaignType),
+		BudgetCurrency:       c.GetCurrency(),
+		PlatformData: entity.JSONMap{
+			"biz_code":      BizCodeSponsoredDiscovery,
+			"campaign_type": lc.CampaignType,
+		},
+	}
+
+	if budget := lc.DayBudget.Decimal(); budget.IsPositive() {
+		campaign.DailyBudget = &budget
+	}
+
+	if budget := lc.TotalBudget.Decimal(); budget.IsPositive() {
+		campaign.LifetimeBudget = &budget
+	}
+
+	if t, err := time.Parse("2006-01-02", lc.StartDate); err == nil {
+		campaign.StartDate = &t
+	}
+
+	if t, err := time.Parse("2006-01-02", lc.EndDate); err == nil {
+		campaign.EndDate = &t
+	}
+
+	if lc.CreateTime > 0 {
+		t := time.UnixMilli(lc.CreateTime)
+		campaign.PlatformCreatedAt = &t
+	}
+
+	if lc.UpdateTime > 0 {
+		t := time.UnixMilli(lc.UpdateTime)
+		campaign.PlatformUpdatedAt = &t
+	}
+
+	return campaign
+}
+
+func (c *Connector) mapCampaignStatus(status string) entity.CampaignStatus {
+	switch strings.ToLower(status) {
+	case "online", "active", "ongoing", "1":
+		return entity.CampaignStatusActive
+	case "paused", "offline", "suspended", "0":
+		return entity.CampaignStatusPaused
+	case "ended", "finished", "expired":
+		return entity.CampaignStatusArchived
+	case "deleted", "-1":
+		return entity.CampaignStatusDeleted
+	default:
+		return entity.CampaignStatusDraft
+	}
+}
+
+func (c *Connector) mapObjective(campaignType string) entity.CampaignObjective {
+	switch strings.ToLower(campaignType) {
+	case "store", "shop":
+		return entity.ObjectiveTraffic
+	case "brand", "awareness":
+		return entity.ObjectiveAwareness
+	default:
+		return entity.ObjectiveSales
+	}
+}
+
+// mapOrderStatus maps a Lazada order/item status to the normalized order status
+func mapOrderStatus(status string) entity.OrderStatus {
+	switch strings.ToLower(status) {
+	case OrderStatusUnpaid, OrderStatusPending:
+		return entity.OrderStatusPending
+	case OrderStatusPacked, OrderStatusReadyToShip, "repacked", "topack":
+		return entity.OrderStatusConfirmed
+	case OrderStatusShipped:
+		return entity.OrderStatusShipped
+	case OrderStatusDelivered, "confirmed":
+		return entity.OrderStatusDelivered
+	case OrderStatusCanceled, OrderStatusFailed, "lost_by_3pl", "damaged_by_3pl":
+		return entity.OrderStatusCancelled
+	case OrderStatusReturned, OrderStatusShippedBack, "shipped_back_success":
+		return entity.OrderStatusReturned
+	default:
+		return entity.OrderStatusPending
+	}
+}
+
+// parseLazadaTime parses Lazada's "2006-01-02 15:04:05 -0700" timestamps, keeping the seller's offset
+func parseLazadaTime(s string) time.Time {
+	for _, layout := range []string{"2006-01-02 15:04:05 -0700", time.RFC3339} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// ============================================================================
+// Error Handling
+// ============================================================================
+
+// HandleLazadaError converts Lazada error codes to appropriate error types
+func (c *Connector) HandleLazadaError(errorCode string, message string, statusCode int) error {
+	platformName := entity.PlatformLazada.String()
+
+	switch errorCode {
+	case LazadaErrorIllegalAccessToken:
+		return errors.NewTokenExpiredError(platformName, "access", time.Now())
+	case LazadaErrorIllegalRefreshToken:
+		return errors.NewTokenExpiredError(platformName, "refresh", time.Now())
+	case LazadaErrorAppCallLimit, LazadaErrorApiCallLimit:
+		return errors.NewRateLimitError(platformName, 60*time.Second)
+	case LazadaErrorIncompleteSignature:
+		return errors.NewPlatformAPIError(platformName, http.StatusUnauthorized, errorCode, "Invalid signature - check app_secret and parameter ordering")
+	case LazadaErrorInvalidTimestamp:
+		return errors.NewPlatformAPIError(platformName, http.StatusBadRequest, errorCode, "Invalid timestamp - ensure server time is synchronized")
+	case LazadaErrorAccessDenied:
+		return errors.NewPlatformAPIError(platformName, http.StatusForbidden, errorCode, "Access denied - seller must re-authorize the app")
+	case LazadaErrorServiceTimeout, LazadaErrorInternalError, LazadaErrorServiceUnavailable:
+		platformErr := errors.NewPlatformAPIError(platformName, http.StatusServiceUnavailable, errorCode, message)
+		platformErr.WithMetadata("is_transient", "true")
+		return platformErr
+	default:
+		return errors.NewPlatformAPIError(platformName, statusCode, errorCode, message)
+	}
+}
+
+// IsRetryableError checks if a Lazada error is retryable
+func (c *Connector) IsRetryableError(errorCode string) bool {
+	switch errorCode {
+	case LazadaErrorAppCallLimit, LazadaErrorApiCallLimit, LazadaErrorServiceTimeout,
+		LazadaErrorInternalError, LazadaErrorServiceUnavailable:
+		return true
+	default:
+		return false
+	}
+}
+
+// ============================================================================
+// Response Types
+// ============================================================================
+
+// lazadaResponse is the envelope shared by all Lazada Open Platform responses
+type lazadaResponse struct {
+	Code      string          `json:"code"`
+	Type      string          `json:"type"`
+	Message   string          `json:"message"`
+	RequestID string          `json:"request_id"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// lazadaNumber accepts both JSON numbers and numeric strings, as Lazada mixes the two
+type lazadaNumber string
+
+// UnmarshalJSON implements json.Unmarshaler
+func (n *lazadaNumber) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "null" {
+		s = ""
+	}
+	*n = lazadaNumber(s)
+	return nil
+}
+
+// Decimal returns the value as a decimal, or zero if it is empty or malformed
+func (n lazadaNumber) Decimal() decimal.Decimal {
+	if n == "" {
+		return decimal.Zero
+	}
+	d, err := decimal.NewFromString(strings.ReplaceAll(string(n), ",", ""))
+	if err != nil {
+		return decimal.Zero
+	}
+	return d
+}
+
+// Int64 returns the value as an integer
+func (n lazadaNumber) Int64() int64 {
+	return n.Decimal().IntPart()
+}
+
+// Float64 returns the value as a float
+func (n lazadaNumber) Float64() float64 {
+	f, _ := n.Decimal().Float64()
+	return f
+}
+
+// Ensure Connector implements the PlatformConnector and OrderConnector interfaces
+var _ service.PlatformConnector = (*Connector)(nil)
+var _ service.OrderConnector = (*Connector)(nil)
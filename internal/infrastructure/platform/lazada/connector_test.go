@@ -0,0 +1,336 @@
+package lazada
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ============================================================================
+// Region Tests
+// ============================================================================
+
+func TestRegionBaseURL(t *testing.T) {
+	tests := []struct {
+		region   Region
+		expected string
+	}{
+		{RegionMalaysia, "https://api.lazada.com.my/rest"},
+		{RegionSingapore, "https://api.lazada.sg/rest"},
+		{RegionThailand, "https://api.lazada.co.th/rest"},
+		{RegionIndonesia, "https://api.lazada.co.id/rest"},
+		{Region("XX"), "https://api.lazada.com.my/rest"},
+	}
+
+	for _, tt := range tests {
+		result := RegionBaseURL(tt.region)
+		if result != tt.expected {
+			t.Errorf("RegionBaseURL(%q) = %q, want %q", tt.region, result, tt.expected)
+		}
+	}
+}
+
+func TestRegionCurrency(t *testing.T) {
+	tests := []struct {
+		region   Region
+		expected string
+	}{
+		{RegionMalaysia, "MYR"},
+		{RegionSingapore, "SGD"},
+		{RegionThailand, "THB"},
+		{RegionVietnam, "VND"},
+		{RegionPhilippines, "PHP"},
+		{RegionIndonesia, "IDR"},
+	}
+
+	for _, tt := range tests {
+		result := RegionCurrency(tt.region)
+		if result != tt.expected {
+			t.Errorf("RegionCurrency(%q) = %q, want %q", tt.region, result, tt.expected)
+		}
+	}
+}
+
+// ============================================================================
+// Signing Tests
+// ============================================================================
+
+func TestSignRequest(t *testing.T) {
+	connector := NewConnectorWithRegion("123456", "secret_key", RegionMalaysia)
+
+	params := map[string]string{
+		"app_key":      "123456",
+		"timestamp":    "1517820392000",
+		"sign_method":  "sha256",
+		"access_token": "test_token",
+		"limit":        "10",
+		"sign":         "ignored",
+	}
+
+	// Computed independently: HMAC-SHA256("secret_key", "/orders/get" + sorted key/value pairs)
+	expected := "ECDD2CCB26C7ED61D8C69E0C4EE2BAE6DE4115DCE55415FF19F2D1BD017E91F8"
+
+	if sign := connector.SignRequest("/orders/get", params); sign != expected {
+		t.Errorf("SignRequest() = %q, want %q", sign, expected)
+	}
+}
+
+func TestSignRequestWithParams(t *testing.T) {
+	connector := NewConnectorWithRegion("123456", "secret_key", RegionMalaysia)
+
+	signed := connector.SignRequestWithParams("/seller/get", "test_token", map[string]string{"foo": "bar"})
+
+	for _, key := range []string{"app_key", "timestamp", "sign_method", "access_token", "sign", "foo"} {
+		if signed[key] == "" {
+			t.Errorf("SignRequestWithParams() missing %q", key)
+		}
+	}
+
+	if signed["sign"] != connector.SignRequest("/seller/get", signed) {
+		t.Error("SignRequestWithParams() sign does not match the signed parameters")
+	}
+}
+
+// ============================================================================
+// Campaign & Insights Tests
+// ============================================================================
+
+func newTestConnector(serverURL string) *Connector {
+	config := DefaultConfig()
+	config.AppKey = "123456"
+	config.AppSecret = "secret_key"
+	config.BaseURL = serverURL
+	config.AuthBaseURL = serverURL
+	return NewConnector(config)
+}
+
+func TestGetCampaignInsights(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != getCampaignReportPath {
+			t.Errorf("path = %q, want %q", r.URL.Path, getCampaignReportPath)
+		}
+		if got := r.URL.Query().Get("campaignId"); got != "555" {
+			t.Errorf("campaignId = %q, want %q", got, "555")
+		}
+		if r.URL.Query().Get("sign") == "" {
+			t.Error("request is not signed")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":"0","request_id":"abc","data":{"totalCount":1,"result":[{"date":"2024-03-01","campaignId":555,"impressions":"2000","clicks":100,"spend":"50.00","orders":"4","revenue":"200.00","directOrders":3,"directRevenue":"150.00"}]}}`))
+	}))
+	defer server.Close()
+
+	connector := newTestConnector(server.URL)
+
+	dateRange := entity.DateRange{
+		StartDate: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	metrics, err := connector.GetCampaignInsights(context.Background(), "test_token", "555", dateRange)
+	if err != nil {
+		t.Fatalf("GetCampaignInsights() error = %v", err)
+	}
+
+	if len(metrics) != 1 {
+		t.Fatalf("len(metrics) = %d, want %d", len(metrics), 1)
+	}
+
+	m := metrics[0]
+	if m.Platform != entity.PlatformLazada {
+		t.Errorf("Platform = %q, want %q", m.Platform, entity.PlatformLazada)
+	}
+	if m.Impressions != 2000 || m.Clicks != 100 || m.Conversions != 4 {
+		t.Errorf("Impressions/Clicks/Conversions = %d/%d/%d, want 2000/100/4", m.Impressions, m.Clicks, m.Conversions)
+	}
+	if !m.Spend.Equal(decimal.NewFromInt(50)) {
+		t.Errorf("Spend = %v, want 50", m.Spend)
+	}
+	if m.ROAS == nil || *m.ROAS != 4 {
+		t.Errorf("ROAS = %v, want 4", m.ROAS)
+	}
+	if m.Currency != "MYR" {
+		t.Errorf("Currency = %q, want %q", m.Currency, "MYR")
+	}
+}
+
+func TestCall_ErrorEnvelope(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"type":"ISV","code":"IllegalAccessToken","message":"The specified access token is invalid or expired","request_id":"abc"}`))
+	}))
+	defer server.Close()
+
+	connector := newTestConnector(server.URL)
+
+	_, err := connector.GetAdAccounts(context.Background(), "expired_token")
+	if err == nil {
+		t.Fatal("GetAdAccounts() error = nil, want token error")
+	}
+
+	if _, ok := err.(*errors.TokenError); !ok {
+		t.Errorf("GetAdAccounts() error type = %T, want *errors.TokenError", err)
+	}
+}
+
+// ============================================================================
+// Order Tests
+// ============================================================================
+
+func TestGetAllOrders_AttachesItems(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case getOrdersPath:
+			w.Write([]byte(`{"code":"0","data":{"count":1,"countTotal":1,"orders":[{"order_id":1001,"order_number":3001,"created_at":"2024-03-01 23:30:00 +0800","updated_at":"2024-03-03 10:00:00 +0800","price":"120.00","shipping_fee":5,"voucher":"10.00","voucher_seller":"10.00","voucher_platform":"0.00","statuses":["delivered"],"items_count":2,"payment_method":"COD"}]}}`))
+		case getMultipleOrderItems:
+			if got := r.URL.Query().Get("order_ids"); got != "[1001]" {
+				t.Errorf("order_ids = %q, want %q", got, "[1001]")
+			}
+			w.Write([]byte(`{"code":"0","data":[{"order_id":1001,"order_items":[{"order_item_id":1,"order_id":1001,"product_id":77,"name":"Widget","sku":"W-1","item_price":"60.00","paid_price":"55.00","voucher_amount":"5.00","status":"delivered","tracking_code":"TRK1","shipment_provider":"LEX"},{"order_item_id":2,"order_id":1001,"product_id":77,"name":"Widget","sku":"W-1","item_price":"60.00","paid_price":"55.00","voucher_amount":"5.00","status":"delivered"}]}]}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	connector := newTestConnector(server.URL)
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	lazadaOrders, err := connector.GetAllOrders(context.Background(), "test_token", start, start.AddDate(0, 0, 7))
+	if err != nil {
+		t.Fatalf("GetAllOrders() error = %v", err)
+	}
+
+	if len(lazadaOrders) != 1 || len(lazadaOrders[0].Items) != 2 {
+		t.Fatalf("GetAllOrders() returned %d orders, want 1 order with 2 items", len(lazadaOrders))
+	}
+
+	orgID := uuid.New()
+	order := connector.MapToOrder(lazadaOrders[0], orgID)
+
+	if order.PlatformOrderID != "1001" || order.PlatformOrderSN != "3001" {
+		t.Errorf("PlatformOrderID/SN = %q/%q, want 1001/3001", order.PlatformOrderID, order.PlatformOrderSN)
+	}
+	if order.Status != entity.OrderStatusDelivered {
+		t.Errorf("Status = %q, want %q", order.Status, entity.OrderStatusDelivered)
+	}
+	if !order.TotalAmount.Equal(decimal.NewFromInt(115)) {
+		t.Errorf("TotalAmount = %v, want 115", order.TotalAmount)
+	}
+	// Order date is the seller's local date, not the UTC date
+	if got := order.OrderDate.Format("2006-01-02"); got != "2024-03-01" {
+		t.Errorf("OrderDate = %q, want %q", got, "2024-03-01")
+	}
+	if order.TrackingNumber != "TRK1" {
+		t.Errorf("TrackingNumber = %q, want %q", order.TrackingNumber, "TRK1")
+	}
+	if len(order.Items) != 2 || order.Items[0].OrganizationID != orgID || order.Items[0].SKU != "W-1" {
+		t.Errorf("Items = %+v, want 2 items for SKU W-1", order.Items)
+	}
+
+	revenue, count := connector.CalculateRevenueFromOrders([]entity.Order{order})
+	if !revenue.Equal(decimal.NewFromInt(115)) || count != 1 {
+		t.Errorf("CalculateRevenueFromOrders() = %v, %d, want 115, 1", revenue, count)
+	}
+}
+
+func TestGetUpdatedOrders_FiltersOnUpdateTime(t *testing.T) {
+	start := time.Date(2024, 3, 3, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		switch r.URL.Path {
+		case getOrdersPath:
+			query := r.URL.Query()
+			if got := query.Get("update_after"); got != start.Format(time.RFC3339) {
+				t.Errorf("update_after = %q, want %q", got, start.Format(time.RFC3339))
+			}
+			if got := query.Get("update_before"); got != end.Format(time.RFC3339) {
+				t.Errorf("update_before = %q, want %q", got, end.Format(time.RFC3339))
+			}
+			if query.Get("created_after") != "" {
+				t.Errorf("created_after = %q, want it unset", query.Get("created_after"))
+			}
+			w.Write([]byte(`{"code":"0","data":{"count":1,"countTotal":1,"orders":[{"order_id":1001,"order_number":3001,"created_at":"2024-03-01 23:30:00 +0800","updated_at":"2024-03-03 10:00:00 +0800","price":"120.00","shipping_fee":5,"voucher":"0.00","statuses":["shipped"],"items_count":1}]}}`))
+		case getMultipleOrderItems:
+			w.Write([]byte(`{"code":"0","data":[{"order_id":1001,"order_items":[{"order_item_id":1,"order_id":1001,"product_id":77,"name":"Widget","sku":"W-1","item_price":"120.00","paid_price":"120.00","status":"shipped"}]}]}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	connector := newTestConnector(server.URL)
+
+	orders, err := connector.GetUpdatedOrders(context.Background(), "test_token", "seller_1", start, end)
+	if err != nil {
+		t.Fatalf("GetUpdatedOrders() error = %v", err)
+	}
+
+	if len(orders) != 1 || len(orders[0].Items) != 1 {
+		t.Fatalf("GetUpdatedOrders() = %+v, want 1 order with 1 item", orders)
+	}
+	if orders[0].Platform != entity.PlatformLazada || orders[0].PlatformOrderID != "1001" {
+		t.Errorf("order = %s/%s, want lazada/1001", orders[0].Platform, orders[0].PlatformOrderID)
+	}
+	if orders[0].OrganizationID != uuid.Nil {
+		t.Errorf("OrganizationID = %s, want it left for the caller", orders[0].OrganizationID)
+	}
+}
+
+func TestMapOrderStatus(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected entity.OrderStatus
+	}{
+		{"unpaid", entity.OrderStatusPending},
+		{"pending", entity.OrderStatusPending},
+		{"ready_to_ship", entity.OrderStatusConfirmed},
+		{"shipped", entity.OrderStatusShipped},
+		{"delivered", entity.OrderStatusDelivered},
+		{"canceled", entity.OrderStatusCancelled},
+		{"returned", entity.OrderStatusReturned},
+	}
+
+	for _, tt := range tests {
+		result := mapOrderStatus(tt.input)
+		if result != tt.expected {
+			t.Errorf("mapOrderStatus(%q) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+}
+
+func TestLazadaNumber(t *testing.T) {
+	var payload struct {
+		A lazadaNumber `json:"a"`
+		B lazadaNumber `json:"b"`
+		C lazadaNumber `json:"c"`
+	}
+
+	if err := json.Unmarshal([]byte(`{"a":"12.50","b":7,"c":null}`), &payload); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	if !payload.A.Decimal().Equal(decimal.NewFromFloat(12.5)) {
+		t.Errorf("A = %v, want 12.5", payload.A.Decimal())
+	}
+	if payload.B.Int64() != 7 {
+		t.Errorf("B = %d, want 7", payload.B.Int64())
+	}
+	if !payload.C.Decimal().IsZero() {
+		t.Errorf("C = %v, want 0", payload.C.Decimal())
+	}
+}
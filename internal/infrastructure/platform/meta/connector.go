@@ -579,6 +579,102 @@ func (c *Connector) GetAd(ctx context.Context, accessToken string, adID string)
 	return &ad, nil
 }
 
+// ============================================================================
+// Incremental Structure Methods
+// ============================================================================
+
+// Effective statuses asked for by incremental listings. Edges leave deleted and
+// archived objects out unless they are filtered on explicitly.
+var (
+	campaignEffectiveStatuses = []string{"ACTIVE", "PAUSED", "DELETED", "ARCHIVED", "IN_PROCESS", "WITH_ISSUES"}
+	adSetEffectiveStatuses    = []string{"ACTIVE", "PAUSED", "DELETED", "ARCHIVED", "IN_PROCESS", "WITH_ISSUES", "CAMPAIGN_PAUSED"}
+	adEffectiveStatuses       = []string{"ACTIVE", "PAUSED", "DELETED", "ARCHIVED", "IN_PROCESS", "WITH_ISSUES", "CAMPAIGN_PAUSED", "ADSET_PAUSED", "PENDING_REVIEW", "DISAPPROVED", "PREAPPROVED", "PENDING_BILLING_INFO"}
+)
+
+// GetStructureChanges retrieves the campaigns, ad sets and ads of an ad account
+// updated after since, with one account-level listing each
+func (c *Connector) GetStructureChanges(ctx context.Context, accessToken string, adAccountID string, since time.Time) (*service.StructureChanges, error) {
+	changes := &service.StructureChanges{}
+
+	campaigns, err := listUpdatedSince[metaCampaign](ctx, c, accessToken, adAccountID, "campaigns", campaignFields, campaignEffectiveStatuses, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed campaigns: %w", err)
+	}
+	for _, mc := range campaigns {
+		changes.Campaigns = append(changes.Campaigns, c.mapCampaign(mc))
+	}
+
+	adSets, err := listUpdatedSince[metaAdSet](ctx, c, accessToken, adAccountID, "adsets", adSetFields+",campaign_id", adSetEffectiveStatuses, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed ad sets: %w", err)
+	}
+	for _, mas := range adSets {
+		changes.AdSets = append(changes.AdSets, service.AdSetChange{
+			AdSet:              c.mapAdSet(mas),
+			PlatformCampaignID: mas.CampaignID,
+		})
+	}
+
+	ads, err := listUpdatedSince[metaAd](ctx, c, accessToken, adAccountID, "ads", adFields+",campaign_id,adset_id", adEffectiveStatuses, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list changed ads: %w", err)
+	}
+	for _, ma := range ads {
+		changes.Ads = append(changes.Ads, service.AdChange{
+			Ad:                 c.mapAd(ma),
+			PlatformCampaignID: ma.CampaignID,
+			PlatformAdSetID:    ma.AdSetID,
+		})
+	}
+
+	return changes, nil
+}
+
+// listUpdatedSince lists the objects on an ad account edge updated after since
+func listUpdatedSince[T any](ctx context.Context, c *Connector, accessToken, adAccountID, edge, fields string, statuses []string, since time.Time) ([]T, error) {
+	endpoint := fmt.Sprintf("%s/%s/act_%s/%s", baseURL, c.apiVersion, adAccountID, edge)
+	filtering := updatedSinceFiltering(since, statuses)
+
+	return platform.FetchAllPages(ctx, func(cursor string) ([]T, string, error) {
+		params := map[string]string{
+			"fields":    fields,
+			"filtering": filtering,
+			"limit":     "100",
+		}
+		if cursor != "" {
+			params["after"] = cursor
+		}
+
+		resp, err := c.DoGet(ctx, endpoint, c.BuildAuthHeader(accessToken), params)
+		if err != nil {
+			return nil, "", err
+		}
+
+		var pageResp struct {
+			Data   []T                 `json:"data"`
+			Paging platform.PagingInfo `json:"paging"`
+		}
+		if err := c.ParseJSON(resp.Body, &pageResp); err != nil {
+			return nil, "", err
+		}
+
+		if pageResp.Paging.Next == "" {
+			return pageResp.Data, "", nil
+		}
+		return pageResp.Data, pageResp.Paging.Cursors.After, nil
+	})
+}
+
+// updatedSinceFiltering builds the filtering parameter selecting objects in any
+// of the given effective statuses updated after since
+func updatedSinceFiltering(since time.Time, statuses []string) string {
+	filterJSON, _ := json.Marshal([]map[string]interface{}{
+		{"field": "updated_time", "operator": "GREATER_THAN", "value": since.Unix()},
+		{"field": "effective_status", "operator": "IN", "value": statuses},
+	})
+	return string(filterJSON)
+}
+
 // ============================================================================
 // Insights Methods
 // ============================================================================
@@ -759,6 +855,7 @@ type metaCampaign struct {
 
 type metaAdSet struct {
 	ID               string          `json:"id"`
+	CampaignID       string          `json:"campaign_id"`
 	Name             string          `json:"name"`
 	Status           string          `json:"status"`
 	DailyBudget      string          `json:"daily_budget"`
@@ -772,10 +869,12 @@ type metaAdSet struct {
 }
 
 type metaAd struct {
-	ID       string `json:"id"`
-	Name     string `json:"name"`
-	Status   string `json:"status"`
-	Creative struct {
+	ID         string `json:"id"`
+	CampaignID string `json:"campaign_id"`
+	AdSetID    string `json:"adset_id"`
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Creative   struct {
 		ID           string `json:"id"`
 		Name         string `json:"name"`
 		Title        string `json:"title"`
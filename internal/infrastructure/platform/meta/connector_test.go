@@ -3,6 +3,7 @@ package meta
 import (
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 
@@ -263,6 +264,29 @@ func TestFormatTimeRange(t *testing.T) {
 	}
 }
 
+func TestUpdatedSinceFiltering(t *testing.T) {
+	since := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	result := updatedSinceFiltering(since, []string{"ACTIVE", "DELETED"})
+
+	expected := `[{"field":"updated_time","operator":"GREATER_THAN","value":1735689600},{"field":"effective_status","operator":"IN","value":["ACTIVE","DELETED"]}]`
+	if result != expected {
+		t.Errorf("updatedSinceFiltering() = %q, want %q", result, expected)
+	}
+}
+
+func TestEffectiveStatuses_IncludeDeleted(t *testing.T) {
+	for name, statuses := range map[string][]string{
+		"campaigns": campaignEffectiveStatuses,
+		"ad sets":   adSetEffectiveStatuses,
+		"ads":       adEffectiveStatuses,
+	} {
+		if !contains(strings.Join(statuses, ","), "DELETED") {
+			t.Errorf("%s statuses %v leave out deleted objects", name, statuses)
+		}
+	}
+}
+
 // ============================================================================
 // Mock Server Tests
 // ============================================================================
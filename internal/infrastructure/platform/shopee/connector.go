@@ -121,6 +121,47 @@ func RegionCurrency(region Region) string {
 	}
 }
 
+// RegionLocation returns the time zone orders of a region are dated in
+func RegionLocation(region Region) *time.Location {
+	name, offset := "Asia/Kuala_Lumpur", 8*60*60
+	switch region {
+	case RegionSingapore:
+		name, offset = "Asia/Singapore", 8*60*60
+	case RegionThailand:
+		name, offset = "Asia/Bangkok", 7*60*60
+	case RegionVietnam:
+		name, offset = "Asia/Ho_Chi_Minh", 7*60*60
+	case RegionPhilippines:
+		name, offset = "Asia/Manila", 8*60*60
+	case RegionIndonesia:
+		name, offset = "Asia/Jakarta", 7*60*60
+	case RegionTaiwan:
+		name, offset = "Asia/Taipei", 8*60*60
+	case RegionBrazil:
+		name, offset = "America/Sao_Paulo", -3*60*60
+	case RegionMexico:
+		name, offset = "America/Mexico_City", -6*60*60
+	case RegionColombia:
+		name, offset = "America/Bogota", -5*60*60
+	case RegionChile:
+		name, offset = "America/Santiago", -4*60*60
+	case RegionPoland:
+		name, offset = "Europe/Warsaw", 1*60*60
+	case RegionSpain:
+		name, offset = "Europe/Madrid", 1*60*60
+	case RegionFrance:
+		name, offset = "Europe/Paris", 1*60*60
+	case RegionIndia:
+		name, offset = "Asia/Kolkata", 5*60*60 + 30*60
+	}
+
+	if loc, err := time.LoadLocation(name); err == nil {
+		return loc
+	}
+	// Fall back to the standard offset when the host has no tz database
+	return time.FixedZone(name, offset)
+}
+
 // ============================================================================
 // Constants
 // ============================================================================
@@ -140,6 +181,9 @@ const (
 	getOrderListPath   = "/api/v2/order/get_order_list"
 	getOrderDetailPath = "/api/v2/order/get_order_detail"
 
+	// Shopee rejects order list requests spanning more than 15 days
+	maxOrderListRange = 15 * 24 * time.Hour
+
 	// Token expiry (Shopee tokens expire in 4 hours)
 	TokenExpiryDuration = 4 * time.Hour
 
@@ -1144,6 +1188,7 @@ const (
 	OrderStatusInCancel    OrderStatus = "IN_CANCEL"
 	OrderStatusCancelled   OrderStatus = "CANCELLED"
 	OrderStatusInvoicePend OrderStatus = "INVOICE_PENDING"
+	OrderStatusToReturn    OrderStatus = "TO_RETURN"
 )
 
 // ShopeeOrder represents a Shopee order
@@ -1158,6 +1203,8 @@ type ShopeeOrder struct {
 	ShipByDate     int64       `json:"ship_by_date,omitempty"`
 	BuyerUserID    int64       `json:"buyer_user_id"`
 	MessageToSeller string     `json:"message_to_seller,omitempty"`
+	EstimatedShippingFee float64 `json:"estimated_shipping_fee,omitempty"`
+	ShippingCarrier      string  `json:"shipping_carrier,omitempty"`
 	ItemList       []struct {
 		ItemID         int64   `json:"item_id"`
 		ItemName       string  `json:"item_name"`
@@ -1247,38 +1294,60 @@ func (c *Connector) GetOrderList(ctx context.Context, accessToken string, params
 	return orderSNs, orderResp.Response.NextCursor, orderResp.Response.More, nil
 }
 
-// GetAllOrders retrieves all orders within a time range with pagination
+// GetAllOrders retrieves all orders created within a time range with pagination
 func (c *Connector) GetAllOrders(ctx context.Context, accessToken string, shopID int64, startTime, endTime time.Time) ([]string, error) {
-	var allOrderSNs []string
-	cursor := ""
+	return c.listOrders(ctx, accessToken, shopID, "create_time", startTime, endTime)
+}
 
-	params := GetOrderListParams{
-		ShopID:         shopID,
-		TimeRangeField: "create_time",
-		TimeFrom:       startTime.Unix(),
-		TimeTo:         endTime.Unix(),
-		PageSize:       100,
-	}
+// GetUpdatedOrderSNs retrieves the serial numbers of all orders updated within a time range
+func (c *Connector) GetUpdatedOrderSNs(ctx context.Context, accessToken string, shopID int64, startTime, endTime time.Time) ([]string, error) {
+	return c.listOrders(ctx, accessToken, shopID, "update_time", startTime, endTime)
+}
 
-	for {
-		params.Cursor = cursor
+// listOrders pages through the order list, splitting the time range into
+// windows Shopee accepts
+func (c *Connector) listOrders(ctx context.Context, accessToken string, shopID int64, timeRangeField string, startTime, endTime time.Time) ([]string, error) {
+	var allOrderSNs []string
+	seen := make(map[string]bool)
 
-		orderSNs, nextCursor, hasMore, err := c.GetOrderList(ctx, accessToken, params)
-		if err != nil {
-			return allOrderSNs, err
+	for windowStart := startTime; windowStart.Before(endTime); windowStart = windowStart.Add(maxOrderListRange) {
+		windowEnd := windowStart.Add(maxOrderListRange)
+		if windowEnd.After(endTime) {
+			windowEnd = endTime
 		}
 
-		allOrderSNs = append(allOrderSNs, orderSNs...)
-
-		if !hasMore || nextCursor == "" {
-			break
+		params := GetOrderListParams{
+			ShopID:         shopID,
+			TimeRangeField: timeRangeField,
+			TimeFrom:       windowStart.Unix(),
+			TimeTo:         windowEnd.Unix(),
+			PageSize:       DefaultPageSize,
 		}
-		cursor = nextCursor
 
-		select {
-		case <-ctx.Done():
-			return allOrderSNs, ctx.Err()
-		default:
+		for {
+			orderSNs, nextCursor, hasMore, err := c.GetOrderList(ctx, accessToken, params)
+			if err != nil {
+				return allOrderSNs, err
+			}
+
+			// Windows share their boundary second, so an order can be listed twice
+			for _, sn := range orderSNs {
+				if !seen[sn] {
+					seen[sn] = true
+					allOrderSNs = append(allOrderSNs, sn)
+				}
+			}
+
+			if !hasMore || nextCursor == "" {
+				break
+			}
+			params.Cursor = nextCursor
+
+			select {
+			case <-ctx.Done():
+				return allOrderSNs, ctx.Err()
+			default:
+			}
 		}
 	}
 
@@ -1323,7 +1392,7 @@ func (c *Connector) fetchOrderDetailsBatch(ctx context.Context, accessToken stri
 	path := getOrderDetailPath
 	signParams := c.SignRequestWithParams(path, accessToken, shopID)
 	signParams["order_sn_list"] = strings.Join(orderSNs, ",")
-	signParams["response_optional_fields"] = "item_list,buyer_user_id"
+	signParams["response_optional_fields"] = "item_list,buyer_user_id,total_amount,pay_time,estimated_shipping_fee,shipping_carrier"
 
 	endpoint := fmt.Sprintf("%s%s", c.baseURL, path)
 
@@ -1351,6 +1420,156 @@ func (c *Connector) fetchOrderDetailsBatch(ctx context.Context, accessToken stri
 	return detailResp.Response.OrderList, nil
 }
 
+// GetUpdatedOrders retrieves the orders of a shop updated within a time range,
+// including their items, as normalized orders. It implements service.OrderConnector;
+// the caller sets the organization.
+func (c *Connector) GetUpdatedOrders(ctx context.Context, accessToken string, shopID string, startTime, endTime time.Time) ([]entity.Order, error) {
+	id, err := strconv.ParseInt(shopID, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Shopee shop ID %q: %w", shopID, err)
+	}
+
+	orderSNs, err := c.GetUpdatedOrderSNs(ctx, accessToken, id, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+
+	orders, err := c.GetOrderDetails(ctx, accessToken, id, orderSNs)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.MapToOrders(orders, uuid.Nil), nil
+}
+
+// MapToOrder converts a Shopee order into the normalized order stored in the orders table
+func (c *Connector) MapToOrder(so ShopeeOrder, orgID uuid.UUID) entity.Order {
+	loc := RegionLocation(c.config.Region)
+	createdAt := time.Unix(so.CreateTime, 0).In(loc)
+	updatedAt := time.Unix(so.UpdateTime, 0).In(loc)
+	status := mapOrderStatus(so.OrderStatus)
+
+	currency := so.Currency
+	if currency == "" {
+		currency = c.GetCurrency()
+	}
+	now := time.Now()
+
+	order := entity.Order{
+		OrganizationID:  orgID,
+		Platform:        entity.PlatformShopee,
+		PlatformOrderID: so.OrderSN,
+		PlatformOrderSN: so.OrderSN,
+		Status:          status,
+		Currency:        currency,
+		ShippingFee:     decimal.NewFromFloat(so.EstimatedShippingFee),
+		TotalAmount:     decimal.NewFromFloat(so.TotalAmount),
+		ShippingCarrier: so.ShippingCarrier,
+		OrderDate:       time.Date(createdAt.Year(), createdAt.Month(), createdAt.Day(), 0, 0, 0, 0, time.UTC),
+		OrderCreatedAt:  createdAt,
+		PlatformData: entity.JSONMap{
+			"order_status": string(so.OrderStatus),
+			"update_time":  so.UpdateTime,
+		},
+		LastSyncedAt: &now,
+	}
+
+	if so.BuyerUserID != 0 {
+		order.CustomerID = strconv.FormatInt(so.BuyerUserID, 10)
+	}
+	if so.PayTime > 0 {
+		paidAt := time.Unix(so.PayTime, 0).In(loc)
+		order.PaidAt = &paidAt
+	}
+
+	switch status {
+	case entity.OrderStatusCancelled:
+		order.CancelledAt = &updatedAt
+	case entity.OrderStatusShipped:
+		order.ShippedAt = &updatedAt
+	case entity.OrderStatusDelivered:
+		order.DeliveredAt = &updatedAt
+	}
+
+	// Orders from Shopee Ads carry the ad campaign; expose it the same way as a
+	// UTM-tagged order so attribution can resolve it by platform campaign ID
+	if so.AdCampaignID != 0 {
+		campaignID := strconv.FormatUint(so.AdCampaignID, 10)
+		order.UTMSource = "shopee"
+		order.UTMCampaign = campaignID
+		order.PlatformData["ad_campaign_id"] = campaignID
+	}
+
+	for _, item := range so.ItemList {
+		quantity := item.ModelQuantity
+		if quantity <= 0 {
+			quantity = 1
+		}
+		qty := decimal.NewFromInt(int64(quantity))
+		unitPrice := decimal.NewFromFloat(item.ModelPrice)
+		totalPrice := unitPrice.Mul(qty)
+
+		discount := decimal.Zero
+		if item.ModelOrigPrice > item.ModelPrice {
+			discount = decimal.NewFromFloat(item.ModelOrigPrice).Sub(unitPrice).Mul(qty)
+		}
+
+		sku := item.ModelSKU
+		if sku == "" {
+			sku = item.ItemSKU
+		}
+
+		order.Subtotal = order.Subtotal.Add(totalPrice)
+		order.DiscountAmount = order.DiscountAmount.Add(discount)
+		order.Items = append(order.Items, entity.OrderItem{
+			OrganizationID:    orgID,
+			PlatformItemID:    strconv.FormatInt(item.ModelID, 10),
+			PlatformProductID: strconv.FormatInt(item.ItemID, 10),
+			SKU:               sku,
+			ProductName:       item.ItemName,
+			VariationName:     item.ModelName,
+			Quantity:          quantity,
+			UnitPrice:         unitPrice,
+			DiscountAmount:    discount,
+			TotalPrice:        totalPrice,
+			Status:            status,
+			PlatformData: entity.JSONMap{
+				"original_price": item.ModelOrigPrice,
+			},
+		})
+	}
+
+	return order
+}
+
+// MapToOrders converts Shopee orders into normalized orders
+func (c *Connector) MapToOrders(orders []ShopeeOrder, orgID uuid.UUID) []entity.Order {
+	result := make([]entity.Order, 0, len(orders))
+	for _, so := range orders {
+		result = append(result, c.MapToOrder(so, orgID))
+	}
+	return result
+}
+
+// mapOrderStatus maps a Shopee order status to the normalized order status
+func mapOrderStatus(status OrderStatus) entity.OrderStatus {
+	switch status {
+	case OrderStatusReady, OrderStatusProcessed:
+		return entity.OrderStatusConfirmed
+	case OrderStatusShipped:
+		return entity.OrderStatusShipped
+	case OrderStatusCompleted:
+		return entity.OrderStatusDelivered
+	case OrderStatusInCancel, OrderStatusCancelled:
+		// A pending cancellation is not counted as revenue until the seller rejects it
+		return entity.OrderStatusCancelled
+	case OrderStatusToReturn:
+		return entity.OrderStatusReturnRequested
+	default:
+		return entity.OrderStatusPending
+	}
+}
+
 // CalculateRevenueFromOrders calculates total revenue from completed orders
 func (c *Connector) CalculateRevenueFromOrders(orders []ShopeeOrder) (totalRevenue decimal.Decimal, completedCount int) {
 	for _, order := range orders {
@@ -1512,5 +1731,6 @@ func SortAdsByPerformance(metrics []ShopeePerformanceMetrics, sortBy string, des
 	})
 }
 
-// Ensure Connector implements PlatformConnector interface
+// Ensure Connector implements the PlatformConnector and OrderConnector interfaces
 var _ service.PlatformConnector = (*Connector)(nil)
+var _ service.OrderConnector = (*Connector)(nil)
@@ -0,0 +1,212 @@
+package shopee
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+func newTestConnector(serverURL string) *Connector {
+	connector := NewConnectorWithRegion(2001234, "partner_key", RegionMalaysia)
+	connector.baseURL = serverURL
+	return connector
+}
+
+// ============================================================================
+// Order Tests
+// ============================================================================
+
+func TestGetUpdatedOrders(t *testing.T) {
+	var windows [][2]int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		query := r.URL.Query()
+
+		switch r.URL.Path {
+		case getOrderListPath:
+			if got := query.Get("time_range_field"); got != "update_time" {
+				t.Errorf("time_range_field = %q, want %q", got, "update_time")
+			}
+			from, _ := strconv.ParseInt(query.Get("time_from"), 10, 64)
+			to, _ := strconv.ParseInt(query.Get("time_to"), 10, 64)
+
+			if query.Get("cursor") == "" {
+				windows = append(windows, [2]int64{from, to})
+			}
+
+			// The first window has two pages; the order on the boundary is listed by both windows
+			switch {
+			case len(windows) == 1 && query.Get("cursor") == "":
+				w.Write([]byte(`{"response":{"more":true,"next_cursor":"p2","order_list":[{"order_sn":"A1"}]}}`))
+			case len(windows) == 1:
+				w.Write([]byte(`{"response":{"more":false,"order_list":[{"order_sn":"A2"}]}}`))
+			default:
+				w.Write([]byte(`{"response":{"more":false,"order_list":[{"order_sn":"A2"},{"order_sn":"B1"}]}}`))
+			}
+		case getOrderDetailPath:
+			if got := query.Get("order_sn_list"); got != "A1,A2,B1" {
+				t.Errorf("order_sn_list = %q, want %q", got, "A1,A2,B1")
+			}
+			w.Write([]byte(`{"response":{"order_list":[
+				{"order_sn":"A1","order_status":"COMPLETED","total_amount":50,"create_time":1725120000,"update_time":1725206400},
+				{"order_sn":"A2","order_status":"CANCELLED","total_amount":20,"create_time":1725120000,"update_time":1725206400},
+				{"order_sn":"B1","order_status":"READY_TO_SHIP","total_amount":30,"create_time":1725120000,"update_time":1725206400}
+			]}}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	connector := newTestConnector(server.URL)
+
+	start := time.Date(2024, 8, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(20 * 24 * time.Hour)
+
+	orders, err := connector.GetUpdatedOrders(context.Background(), "test_token", "123456", start, end)
+	if err != nil {
+		t.Fatalf("GetUpdatedOrders() error = %v", err)
+	}
+
+	if len(windows) != 2 {
+		t.Fatalf("requested %d windows, want 2", len(windows))
+	}
+	if windows[0][0] != start.Unix() || windows[0][1] != start.Add(maxOrderListRange).Unix() || windows[1][1] != end.Unix() {
+		t.Errorf("unexpected windows %v", windows)
+	}
+
+	if len(orders) != 3 {
+		t.Fatalf("len(orders) = %d, want 3", len(orders))
+	}
+	if orders[0].OrganizationID != uuid.Nil {
+		t.Error("expected the organization to be left for the caller")
+	}
+
+	if _, err := connector.GetUpdatedOrders(context.Background(), "test_token", "shop", start, end); err == nil {
+		t.Error("expected error for a non-numeric shop ID")
+	}
+}
+
+func TestMapToOrder(t *testing.T) {
+	connector := NewConnectorWithRegion(2001234, "partner_key", RegionMalaysia)
+	orgID := uuid.New()
+
+	// 2024-09-01 17:30 UTC is already 2024-09-02 in Malaysia
+	created := time.Date(2024, 9, 1, 17, 30, 0, 0, time.UTC)
+	so := ShopeeOrder{
+		OrderSN:              "240902ABCD",
+		OrderStatus:          OrderStatusCompleted,
+		TotalAmount:          104.5,
+		CreateTime:           created.Unix(),
+		UpdateTime:           created.Add(72 * time.Hour).Unix(),
+		PayTime:              created.Add(time.Minute).Unix(),
+		BuyerUserID:          987654,
+		EstimatedShippingFee: 4.5,
+		ShippingCarrier:      "SPX Express",
+		AdCampaignID:         445566,
+	}
+	so.ItemList = append(so.ItemList, struct {
+		ItemID         int64   `json:"item_id"`
+		ItemName       string  `json:"item_name"`
+		ItemSKU        string  `json:"item_sku"`
+		ModelID        int64   `json:"model_id"`
+		ModelName      string  `json:"model_name"`
+		ModelSKU       string  `json:"model_sku"`
+		ModelQuantity  int     `json:"model_quantity_purchased"`
+		ModelPrice     float64 `json:"model_discounted_price"`
+		ModelOrigPrice float64 `json:"model_original_price"`
+	}{
+		ItemID: 11, ItemName: "Tumbler", ItemSKU: "TMB", ModelID: 22, ModelName: "Blue",
+		ModelQuantity: 2, ModelPrice: 50, ModelOrigPrice: 60,
+	})
+
+	order := connector.MapToOrder(so, orgID)
+
+	if order.Platform != entity.PlatformShopee || order.PlatformOrderID != "240902ABCD" || order.OrganizationID != orgID {
+		t.Errorf("unexpected identifiers %+v", order)
+	}
+	if order.Status != entity.OrderStatusDelivered || order.DeliveredAt == nil {
+		t.Errorf("Status = %q, DeliveredAt = %v", order.Status, order.DeliveredAt)
+	}
+	if want := time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC); !order.OrderDate.Equal(want) {
+		t.Errorf("OrderDate = %v, want %v", order.OrderDate, want)
+	}
+	if order.Currency != "MYR" || order.CustomerID != "987654" || order.PaidAt == nil {
+		t.Errorf("unexpected order %+v", order)
+	}
+	if !order.TotalAmount.Equal(decimal.NewFromFloat(104.5)) || !order.Subtotal.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("TotalAmount/Subtotal = %v/%v, want 104.5/100", order.TotalAmount, order.Subtotal)
+	}
+	if !order.DiscountAmount.Equal(decimal.NewFromInt(20)) {
+		t.Errorf("DiscountAmount = %v, want 20", order.DiscountAmount)
+	}
+	if order.UTMSource != "shopee" || order.UTMCampaign != "445566" {
+		t.Errorf("UTM = %q/%q, want shopee/445566", order.UTMSource, order.UTMCampaign)
+	}
+
+	if len(order.Items) != 1 {
+		t.Fatalf("len(Items) = %d, want 1", len(order.Items))
+	}
+	item := order.Items[0]
+	if item.SKU != "TMB" || item.Quantity != 2 || item.PlatformProductID != "11" || item.PlatformItemID != "22" {
+		t.Errorf("unexpected item %+v", item)
+	}
+	if !item.UnitPrice.Equal(decimal.NewFromInt(50)) || !item.TotalPrice.Equal(decimal.NewFromInt(100)) {
+		t.Errorf("UnitPrice/TotalPrice = %v/%v, want 50/100", item.UnitPrice, item.TotalPrice)
+	}
+}
+
+func TestMapOrderStatus(t *testing.T) {
+	tests := []struct {
+		status   OrderStatus
+		expected entity.OrderStatus
+	}{
+		{OrderStatusUnpaid, entity.OrderStatusPending},
+		{OrderStatusReady, entity.OrderStatusConfirmed},
+		{OrderStatusProcessed, entity.OrderStatusConfirmed},
+		{OrderStatusShipped, entity.OrderStatusShipped},
+		{OrderStatusCompleted, entity.OrderStatusDelivered},
+		{OrderStatusInCancel, entity.OrderStatusCancelled},
+		{OrderStatusCancelled, entity.OrderStatusCancelled},
+		{OrderStatusToReturn, entity.OrderStatusReturnRequested},
+		{OrderStatus("SOMETHING_NEW"), entity.OrderStatusPending},
+	}
+
+	for _, tt := range tests {
+		if result := mapOrderStatus(tt.status); result != tt.expected {
+			t.Errorf("mapOrderStatus(%q) = %q, want %q", tt.status, result, tt.expected)
+		}
+	}
+}
+
+func TestRegionLocation(t *testing.T) {
+	at := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		region Region
+		offset int
+	}{
+		{RegionMalaysia, 8 * 60 * 60},
+		{RegionThailand, 7 * 60 * 60},
+		{RegionIndia, 5*60*60 + 30*60},
+		{Region("XX"), 8 * 60 * 60},
+	}
+
+	for _, tt := range tests {
+		_, offset := at.In(RegionLocation(tt.region)).Zone()
+		if offset != tt.offset {
+			t.Errorf("RegionLocation(%q) offset = %d, want %d", tt.region, offset, tt.offset)
+		}
+		if !strings.Contains(RegionLocation(tt.region).String(), "/") {
+			t.Errorf("RegionLocation(%q) = %q, want an IANA zone name", tt.region, RegionLocation(tt.region))
+		}
+	}
+}
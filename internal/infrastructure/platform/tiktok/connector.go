@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net/url"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -399,16 +400,7 @@ func (c *Connector) GetCampaigns(ctx context.Context, accessToken string, adAcco
 			Code    int    `json:"code"`
 			Message string `json:"message"`
 			Data    struct {
-				List []struct {
-					CampaignID    string `json:"campaign_id"`
-					CampaignName  string `json:"campaign_name"`
-					ObjectiveType string `json:"objective_type"`
-					Status        string `json:"status"`
-					Budget        string `json:"budget"`
-					BudgetMode    string `json:"budget_mode"`
-					CreateTime    string `json:"create_time"`
-					ModifyTime    string `json:"modify_time"`
-				} `json:"list"`
+				List     []tiktokCampaign `json:"list"`
 				PageInfo struct {
 					Page       int `json:"page"`
 					PageSize   int `json:"page_size"`
@@ -432,36 +424,7 @@ func (c *Connector) GetCampaigns(ctx context.Context, accessToken string, adAcco
 		}
 
 		for _, camp := range campResp.Data.List {
-			campaign := entity.Campaign{
-				Platform:             entity.PlatformTikTok,
-				PlatformCampaignID:   camp.CampaignID,
-				PlatformCampaignName: camp.CampaignName,
-				Status:               c.mapCampaignStatus(camp.Status),
-				Objective:            c.mapObjective(camp.ObjectiveType),
-			}
-
-			if camp.Budget != "" {
-				budget, _ := decimal.NewFromString(camp.Budget)
-				if camp.BudgetMode == "BUDGET_MODE_DAY" {
-					campaign.DailyBudget = &budget
-				} else {
-					campaign.LifetimeBudget = &budget
-				}
-			}
-
-			if camp.CreateTime != "" {
-				if t, err := time.Parse("2006-01-02 15:04:05", camp.CreateTime); err == nil {
-					campaign.PlatformCreatedAt = &t
-				}
-			}
-
-			if camp.ModifyTime != "" {
-				if t, err := time.Parse("2006-01-02 15:04:05", camp.ModifyTime); err == nil {
-					campaign.PlatformUpdatedAt = &t
-				}
-			}
-
-			allCampaigns = append(allCampaigns, campaign)
+			allCampaigns = append(allCampaigns, c.mapCampaign(camp))
 		}
 
 		if page >= campResp.Data.PageInfo.TotalPage {
@@ -524,16 +487,7 @@ func (c *Connector) GetAdSets(ctx context.Context, accessToken string, campaignI
 			Code    int    `json:"code"`
 			Message string `json:"message"`
 			Data    struct {
-				List []struct {
-					AdgroupID        string  `json:"adgroup_id"`
-					AdgroupName      string  `json:"adgroup_name"`
-					Status           string  `json:"status"`
-					Budget           float64 `json:"budget"`
-					BudgetMode       string  `json:"budget_mode"`
-					BidType          string  `json:"bid_type"`
-					Bid              float64 `json:"bid"`
-					OptimizationGoal string  `json:"optimization_goal"`
-				} `json:"list"`
+				List     []tiktokAdGroup `json:"list"`
 				PageInfo struct {
 					Page      int `json:"page"`
 					PageSize  int `json:"page_size"`
@@ -551,25 +505,7 @@ func (c *Connector) GetAdSets(ctx context.Context, accessToken string, campaignI
 		}
 
 		for _, ag := range adgroupResp.Data.List {
-			adSet := entity.AdSet{
-				Platform:          entity.PlatformTikTok,
-				PlatformAdSetID:   ag.AdgroupID,
-				PlatformAdSetName: ag.AdgroupName,
-				Status:            c.mapCampaignStatus(ag.Status),
-				BidStrategy:       ag.OptimizationGoal,
-			}
-
-			budget := decimal.NewFromFloat(ag.Budget)
-			if ag.BudgetMode == "BUDGET_MODE_DAY" {
-				adSet.DailyBudget = &budget
-			} else {
-				adSet.LifetimeBudget = &budget
-			}
-
-			bid := decimal.NewFromFloat(ag.Bid)
-			adSet.BidAmount = &bid
-
-			allAdSets = append(allAdSets, adSet)
+			allAdSets = append(allAdSets, c.mapAdGroup(ag))
 		}
 
 		if page >= adgroupResp.Data.PageInfo.TotalPage {
@@ -630,16 +566,7 @@ func (c *Connector) GetAds(ctx context.Context, accessToken string, adSetID stri
 			Code    int    `json:"code"`
 			Message string `json:"message"`
 			Data    struct {
-				List []struct {
-					AdID           string   `json:"ad_id"`
-					AdName         string   `json:"ad_name"`
-					Status         string   `json:"status"`
-					AdText         string   `json:"ad_text"`
-					CallToAction   string   `json:"call_to_action"`
-					LandingPageURL string   `json:"landing_page_url"`
-					ImageIDs       []string `json:"image_ids"`
-					VideoID        string   `json:"video_id"`
-				} `json:"list"`
+				List     []tiktokAd `json:"list"`
 				PageInfo struct {
 					Page      int `json:"page"`
 					PageSize  int `json:"page_size"`
@@ -657,20 +584,7 @@ func (c *Connector) GetAds(ctx context.Context, accessToken string, adSetID stri
 		}
 
 		for _, a := range adResp.Data.List {
-			ad := entity.Ad{
-				Platform:       entity.PlatformTikTok,
-				PlatformAdID:   a.AdID,
-				PlatformAdName: a.AdName,
-				Status:         c.mapCampaignStatus(a.Status),
-				Description:    a.AdText,
-				CallToAction:   a.CallToAction,
-				DestinationURL: a.LandingPageURL,
-				CreativeData: entity.JSONMap{
-					"video_id":  a.VideoID,
-					"image_ids": a.ImageIDs,
-				},
-			}
-			allAds = append(allAds, ad)
+			allAds = append(allAds, c.mapAd(a))
 		}
 
 		if page >= adResp.Data.PageInfo.TotalPage {
@@ -698,6 +612,122 @@ func (c *Connector) GetAd(ctx context.Context, accessToken string, adID string)
 	return nil, errors.ErrNotFound("Ad")
 }
 
+// ============================================================================
+// Incremental Structure Methods
+// ============================================================================
+
+// GetStructureChanges retrieves the campaigns, ad groups and ads of an
+// advertiser modified after since. The /get/ endpoints only filter on creation
+// time, so objects are selected on their modify_time here; one advertiser-level
+// listing per level still replaces the per-campaign and per-ad-group requests
+// of a full sync.
+func (c *Connector) GetStructureChanges(ctx context.Context, accessToken string, adAccountID string, since time.Time) (*service.StructureChanges, error) {
+	changes := &service.StructureChanges{}
+
+	campaigns, err := listAdvertiserObjects[tiktokCampaign](ctx, c, accessToken, adAccountID, "campaign/get/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list campaigns: %w", err)
+	}
+	for _, camp := range campaigns {
+		if modifiedAfter(camp.ModifyTime, since) {
+			changes.Campaigns = append(changes.Campaigns, c.mapCampaign(camp))
+		}
+	}
+
+	adGroups, err := listAdvertiserObjects[tiktokAdGroup](ctx, c, accessToken, adAccountID, "adgroup/get/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ad groups: %w", err)
+	}
+	for _, ag := range adGroups {
+		if modifiedAfter(ag.ModifyTime, since) {
+			changes.AdSets = append(changes.AdSets, service.AdSetChange{
+				AdSet:              c.mapAdGroup(ag),
+				PlatformCampaignID: ag.CampaignID,
+			})
+		}
+	}
+
+	ads, err := listAdvertiserObjects[tiktokAd](ctx, c, accessToken, adAccountID, "ad/get/")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ads: %w", err)
+	}
+	for _, a := range ads {
+		if modifiedAfter(a.ModifyTime, since) {
+			changes.Ads = append(changes.Ads, service.AdChange{
+				Ad:                 c.mapAd(a),
+				PlatformCampaignID: a.CampaignID,
+				PlatformAdSetID:    a.AdgroupID,
+			})
+		}
+	}
+
+	return changes, nil
+}
+
+// listAdvertiserObjects lists every object of an advertiser on a /get/
+// endpoint, deleted ones included
+func listAdvertiserObjects[T any](ctx context.Context, c *Connector, accessToken, advertiserID, path string) ([]T, error) {
+	endpoint := fmt.Sprintf("%s/%s/%s", c.baseURL, apiVersion, path)
+	filteringJSON, _ := json.Marshal(map[string]interface{}{
+		"primary_status": "STATUS_ALL",
+	})
+
+	return platform.FetchAllPages(ctx, func(cursor string) ([]T, string, error) {
+		page := 1
+		if cursor != "" {
+			page, _ = strconv.Atoi(cursor)
+		}
+
+		params := map[string]string{
+			"advertiser_id": advertiserID,
+			"page":          strconv.Itoa(page),
+			"page_size":     "100",
+			"filtering":     string(filteringJSON),
+		}
+
+		resp, err := c.DoGet(ctx, endpoint, c.buildTikTokHeaders(accessToken), params)
+		if err != nil {
+			return nil, "", err
+		}
+
+		var pageResp struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+			Data    struct {
+				List     []T `json:"list"`
+				PageInfo struct {
+					Page      int `json:"page"`
+					TotalPage int `json:"total_page"`
+				} `json:"page_info"`
+			} `json:"data"`
+		}
+		if err := c.ParseJSON(resp.Body, &pageResp); err != nil {
+			return nil, "", err
+		}
+
+		if pageResp.Code != 0 {
+			return nil, "", errors.NewPlatformAPIError(
+				entity.PlatformTikTok.String(),
+				pageResp.Code,
+				fmt.Sprintf("%d", pageResp.Code),
+				pageResp.Message,
+			)
+		}
+
+		if page >= pageResp.Data.PageInfo.TotalPage {
+			return pageResp.Data.List, "", nil
+		}
+		return pageResp.Data.List, strconv.Itoa(page + 1), nil
+	})
+}
+
+// modifiedAfter reports whether a TikTok modify_time is after since. Objects
+// without a readable modify time count as modified.
+func modifiedAfter(modifyTime string, since time.Time) bool {
+	t, err := time.Parse(tiktokTimeLayout, modifyTime)
+	return err != nil || t.After(since)
+}
+
 // ============================================================================
 // Insights Methods
 // ============================================================================
@@ -895,6 +925,130 @@ func (c *Connector) BuildSignedHeaders(accessToken string, timestamp string) map
 	}
 }
 
+// tiktokTimeLayout is the layout of the create and modify times TikTok returns, in UTC
+const tiktokTimeLayout = "2006-01-02 15:04:05"
+
+type tiktokCampaign struct {
+	CampaignID      string `json:"campaign_id"`
+	CampaignName    string `json:"campaign_name"`
+	ObjectiveType   string `json:"objective_type"`
+	Status          string `json:"status"`
+	SecondaryStatus string `json:"secondary_status"`
+	Budget          string `json:"budget"`
+	BudgetMode      string `json:"budget_mode"`
+	CreateTime      string `json:"create_time"`
+	ModifyTime      string `json:"modify_time"`
+}
+
+type tiktokAdGroup struct {
+	AdgroupID        string  `json:"adgroup_id"`
+	CampaignID       string  `json:"campaign_id"`
+	AdgroupName      string  `json:"adgroup_name"`
+	Status           string  `json:"status"`
+	SecondaryStatus  string  `json:"secondary_status"`
+	Budget           float64 `json:"budget"`
+	BudgetMode       string  `json:"budget_mode"`
+	BidType          string  `json:"bid_type"`
+	Bid              float64 `json:"bid"`
+	OptimizationGoal string  `json:"optimization_goal"`
+	ModifyTime       string  `json:"modify_time"`
+}
+
+type tiktokAd struct {
+	AdID            string   `json:"ad_id"`
+	CampaignID      string   `json:"campaign_id"`
+	AdgroupID       string   `json:"adgroup_id"`
+	AdName          string   `json:"ad_name"`
+	Status          string   `json:"status"`
+	SecondaryStatus string   `json:"secondary_status"`
+	AdText          string   `json:"ad_text"`
+	CallToAction    string   `json:"call_to_action"`
+	LandingPageURL  string   `json:"landing_page_url"`
+	ImageIDs        []string `json:"image_ids"`
+	VideoID         string   `json:"video_id"`
+	ModifyTime      string   `json:"modify_time"`
+}
+
+func (c *Connector) mapCampaign(camp tiktokCampaign) entity.Campaign {
+	campaign := entity.Campaign{
+		Platform:             entity.PlatformTikTok,
+		PlatformCampaignID:   camp.CampaignID,
+		PlatformCampaignName: camp.CampaignName,
+		Status:               c.mapObjectStatus(camp.Status, camp.SecondaryStatus),
+		Objective:            c.mapObjective(camp.ObjectiveType),
+	}
+
+	if camp.Budget != "" {
+		budget, _ := decimal.NewFromString(camp.Budget)
+		if camp.BudgetMode == "BUDGET_MODE_DAY" {
+			campaign.DailyBudget = &budget
+		} else {
+			campaign.LifetimeBudget = &budget
+		}
+	}
+
+	if camp.CreateTime != "" {
+		if t, err := time.Parse(tiktokTimeLayout, camp.CreateTime); err == nil {
+			campaign.PlatformCreatedAt = &t
+		}
+	}
+
+	if camp.ModifyTime != "" {
+		if t, err := time.Parse(tiktokTimeLayout, camp.ModifyTime); err == nil {
+			campaign.PlatformUpdatedAt = &t
+		}
+	}
+
+	return campaign
+}
+
+func (c *Connector) mapAdGroup(ag tiktokAdGroup) entity.AdSet {
+	adSet := entity.AdSet{
+		Platform:          entity.PlatformTikTok,
+		PlatformAdSetID:   ag.AdgroupID,
+		PlatformAdSetName: ag.AdgroupName,
+		Status:            c.mapObjectStatus(ag.Status, ag.SecondaryStatus),
+		BidStrategy:       ag.OptimizationGoal,
+	}
+
+	budget := decimal.NewFromFloat(ag.Budget)
+	if ag.BudgetMode == "BUDGET_MODE_DAY" {
+		adSet.DailyBudget = &budget
+	} else {
+		adSet.LifetimeBudget = &budget
+	}
+
+	bid := decimal.NewFromFloat(ag.Bid)
+	adSet.BidAmount = &bid
+
+	return adSet
+}
+
+func (c *Connector) mapAd(a tiktokAd) entity.Ad {
+	return entity.Ad{
+		Platform:       entity.PlatformTikTok,
+		PlatformAdID:   a.AdID,
+		PlatformAdName: a.AdName,
+		Status:         c.mapObjectStatus(a.Status, a.SecondaryStatus),
+		Description:    a.AdText,
+		CallToAction:   a.CallToAction,
+		DestinationURL: a.LandingPageURL,
+		CreativeData: entity.JSONMap{
+			"video_id":  a.VideoID,
+			"image_ids": a.ImageIDs,
+		},
+	}
+}
+
+// mapObjectStatus maps the status of a campaign, ad group or ad. Deleted
+// objects only show it in their secondary status, e.g. CAMPAIGN_STATUS_DELETE.
+func (c *Connector) mapObjectStatus(status, secondaryStatus string) entity.CampaignStatus {
+	if strings.HasSuffix(strings.ToUpper(secondaryStatus), "_DELETE") {
+		return entity.CampaignStatusDeleted
+	}
+	return c.mapCampaignStatus(status)
+}
+
 func (c *Connector) mapCampaignStatus(status string) entity.CampaignStatus {
 	switch strings.ToUpper(status) {
 	case "ENABLE", "STATUS_ENABLE":
@@ -0,0 +1,107 @@
+package tiktok
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+)
+
+// ============================================================================
+// Incremental Structure Tests
+// ============================================================================
+
+func newTestConnector(serverURL string) *Connector {
+	connector := NewConnector(DefaultConfig())
+	connector.baseURL = serverURL
+	return connector
+}
+
+func TestGetStructureChanges(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("advertiser_id"); got != "7001" {
+			t.Errorf("advertiser_id = %q, want %q", got, "7001")
+		}
+		if got := r.URL.Query().Get("filtering"); got != `{"primary_status":"STATUS_ALL"}` {
+			t.Errorf("filtering = %q, want deleted objects included", got)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/v1.3/campaign/get/":
+			if r.URL.Query().Get("page") == "1" {
+				w.Write([]byte(`{"code":0,"data":{"list":[{"campaign_id":"c1","campaign_name":"Old","status":"ENABLE","modify_time":"2024-02-01 00:00:00"}],"page_info":{"page":1,"total_page":2}}}`))
+				return
+			}
+			w.Write([]byte(`{"code":0,"data":{"list":[{"campaign_id":"c2","campaign_name":"Removed","status":"DISABLE","secondary_status":"CAMPAIGN_STATUS_DELETE","modify_time":"2024-03-02 08:00:00"}],"page_info":{"page":2,"total_page":2}}}`))
+		case "/v1.3/adgroup/get/":
+			w.Write([]byte(`{"code":0,"data":{"list":[{"adgroup_id":"g1","campaign_id":"c1","adgroup_name":"Group","status":"ENABLE","budget":50,"budget_mode":"BUDGET_MODE_DAY","modify_time":"2024-03-02 09:00:00"}],"page_info":{"page":1,"total_page":1}}}`))
+		case "/v1.3/ad/get/":
+			w.Write([]byte(`{"code":0,"data":{"list":[{"ad_id":"a1","campaign_id":"c1","adgroup_id":"g1","ad_name":"Unchanged","status":"ENABLE","modify_time":"2024-01-15 00:00:00"}],"page_info":{"page":1,"total_page":1}}}`))
+		default:
+			t.Errorf("unexpected path %q", r.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	since := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	changes, err := newTestConnector(server.URL).GetStructureChanges(context.Background(), "test_token", "7001", since)
+	if err != nil {
+		t.Fatalf("GetStructureChanges() error = %v", err)
+	}
+
+	if len(changes.Campaigns) != 1 || changes.Campaigns[0].PlatformCampaignID != "c2" {
+		t.Fatalf("campaigns = %+v, want only c2", changes.Campaigns)
+	}
+	if changes.Campaigns[0].Status != entity.CampaignStatusDeleted {
+		t.Errorf("deleted campaign status = %q, want %q", changes.Campaigns[0].Status, entity.CampaignStatusDeleted)
+	}
+
+	if len(changes.AdSets) != 1 || changes.AdSets[0].PlatformCampaignID != "c1" {
+		t.Fatalf("ad sets = %+v, want g1 of c1", changes.AdSets)
+	}
+	if changes.AdSets[0].AdSet.DailyBudget == nil || changes.AdSets[0].AdSet.DailyBudget.String() != "50" {
+		t.Errorf("ad set daily budget = %v, want 50", changes.AdSets[0].AdSet.DailyBudget)
+	}
+
+	if len(changes.Ads) != 0 {
+		t.Errorf("ads = %+v, want none changed", changes.Ads)
+	}
+}
+
+func TestGetStructureChanges_APIError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"code":40100,"message":"Too many requests"}`))
+	}))
+	defer server.Close()
+
+	_, err := newTestConnector(server.URL).GetStructureChanges(context.Background(), "test_token", "7001", time.Now())
+	if err == nil {
+		t.Fatal("GetStructureChanges() error = nil, want the API error")
+	}
+}
+
+func TestMapObjectStatus(t *testing.T) {
+	connector := NewConnector(nil)
+
+	tests := []struct {
+		status          string
+		secondaryStatus string
+		want            entity.CampaignStatus
+	}{
+		{"ENABLE", "CAMPAIGN_STATUS_ENABLE", entity.CampaignStatusActive},
+		{"DISABLE", "ADGROUP_STATUS_DELETE", entity.CampaignStatusDeleted},
+		{"STATUS_DELETE", "", entity.CampaignStatusDeleted},
+		{"DISABLE", "", entity.CampaignStatusPaused},
+	}
+
+	for _, tt := range tests {
+		if got := connector.mapObjectStatus(tt.status, tt.secondaryStatus); got != tt.want {
+			t.Errorf("mapObjectStatus(%q, %q) = %q, want %q", tt.status, tt.secondaryStatus, got, tt.want)
+		}
+	}
+}
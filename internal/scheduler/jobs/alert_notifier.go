@@ -0,0 +1,227 @@
+package jobs
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/email"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/events"
+	"github.com/ads-aggregator/ads-aggregator/pkg/httpclient"
+)
+
+// Headers of webhook requests. The signature is the hex-encoded HMAC-SHA256 of
+// "<timestamp>.<body>" keyed with the rule's or endpoint's webhook secret. The
+// delivery ID is only sent to organization webhook endpoints.
+const (
+	WebhookEventHeader     = "X-AdsAnalytic-Event"
+	WebhookTimestampHeader = "X-AdsAnalytic-Timestamp"
+	WebhookSignatureHeader = "X-AdsAnalytic-Signature"
+	WebhookDeliveryHeader  = "X-AdsAnalytic-Delivery"
+
+	alertWebhookEvent   = "alert.triggered"
+	alertWebhookTimeout = 10 * time.Second
+)
+
+// AlertEventNotifier pushes triggered alert rules over the SSE event stream
+type AlertEventNotifier struct {
+	broadcaster OrgBroadcaster
+}
+
+// NewAlertEventNotifier creates a new alert event notifier
+func NewAlertEventNotifier(broadcaster OrgBroadcaster) *AlertEventNotifier {
+	return &AlertEventNotifier{broadcaster: broadcaster}
+}
+
+// Channel returns the SSE channel
+func (n *AlertEventNotifier) Channel() entity.AlertChannel {
+	return entity.AlertChannelSSE
+}
+
+// NotifyAlerts broadcasts an alert:triggered event to the organization
+func (n *AlertEventNotifier) NotifyAlerts(ctx context.Context, rule *entity.AlertRule, alerts []entity.AlertEvent) error {
+	data := alertTriggeredData(rule, alerts)
+	n.broadcaster.BroadcastToOrg(rule.OrganizationID, events.NewAlertTriggeredEvent(data.RuleID, data.RuleName, data.Expression, data.Alerts))
+	return nil
+}
+
+// AlertMailer delivers triggered alert rules by email
+type AlertMailer interface {
+	TriggerAlertTriggeredEmail(ctx context.Context, alert *email.AlertTriggeredData) error
+}
+
+// AlertEmailNotifier emails triggered alert rules to the owners and admins of the organization
+type AlertEmailNotifier struct {
+	memberRepo repository.OrganizationMemberRepository
+	orgRepo    repository.OrganizationRepository
+	mailer     AlertMailer
+}
+
+// NewAlertEmailNotifier creates a new alert email notifier
+func NewAlertEmailNotifier(
+	memberRepo repository.OrganizationMemberRepository,
+	orgRepo repository.OrganizationRepository,
+	mailer AlertMailer,
+) *AlertEmailNotifier {
+	return &AlertEmailNotifier{
+		memberRepo: memberRepo,
+		orgRepo:    orgRepo,
+		mailer:     mailer,
+	}
+}
+
+// Channel returns the email channel
+func (n *AlertEmailNotifier) Channel() entity.AlertChannel {
+	return entity.AlertChannelEmail
+}
+
+// NotifyAlerts emails the campaigns that triggered the rule
+func (n *AlertEmailNotifier) NotifyAlerts(ctx context.Context, rule *entity.AlertRule, alerts []entity.AlertEvent) error {
+	recipients, err := adminRecipients(ctx, n.memberRepo, rule.OrganizationID)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	items := make([]email.AlertItem, len(alerts))
+	for i, alert := range alerts {
+		items[i] = email.AlertItem{
+			CampaignName: alert.CampaignName,
+			Platform:     string(alert.Platform),
+			Values:       formatAlertValues(alert.Values, alert.Currency),
+		}
+	}
+
+	return n.mailer.TriggerAlertTriggeredEmail(ctx, &email.AlertTriggeredData{
+		Recipients:  recipients,
+		CompanyName: companyName(ctx, n.orgRepo, rule.OrganizationID),
+		RuleName:    rule.Name,
+		Expression:  rule.Expression,
+		MetricDate:  alerts[0].MetricDate,
+		Alerts:      items,
+	})
+}
+
+// AlertWebhookNotifier posts triggered alert rules to the rule's webhook URL
+type AlertWebhookNotifier struct {
+	client *http.Client
+}
+
+// NewAlertWebhookNotifier creates a new alert webhook notifier. Without a
+// client it uses one that only connects to public addresses, since webhook
+// URLs come from users.
+func NewAlertWebhookNotifier(client *http.Client) *AlertWebhookNotifier {
+	if client == nil {
+		client = httpclient.NewPublicClient(alertWebhookTimeout)
+	}
+	return &AlertWebhookNotifier{client: client}
+}
+
+// Channel returns the webhook channel
+func (n *AlertWebhookNotifier) Channel() entity.AlertChannel {
+	return entity.AlertChannelWebhook
+}
+
+// AlertWebhookPayload is the JSON body of an alert webhook request
+type AlertWebhookPayload struct {
+	Event          string                    `json:"event"`
+	OrganizationID string                    `json:"organization_id"`
+	Timestamp      time.Time                 `json:"timestamp"`
+	Data           events.AlertTriggeredData `json:"data"`
+}
+
+// NotifyAlerts posts a signed alert.triggered payload. Any response other than 2xx is an error.
+func (n *AlertWebhookNotifier) NotifyAlerts(ctx context.Context, rule *entity.AlertRule, alerts []entity.AlertEvent) error {
+	if rule.WebhookURL == "" {
+		return fmt.Errorf("rule has no webhook URL")
+	}
+
+	now := time.Now().UTC()
+	body, err := json.Marshal(AlertWebhookPayload{
+		Event:          alertWebhookEvent,
+		OrganizationID: rule.OrganizationID.String(),
+		Timestamp:      now,
+		Data:           alertTriggeredData(rule, alerts),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	_, err = postWebhook(ctx, n.client, rule.WebhookURL, rule.WebhookSecret, alertWebhookEvent, body, nil)
+	return err
+}
+
+// SignWebhookPayload returns the hex-encoded HMAC-SHA256 of "<timestamp>.<body>"
+func SignWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func alertTriggeredData(rule *entity.AlertRule, alerts []entity.AlertEvent) events.AlertTriggeredData {
+	data := events.AlertTriggeredData{
+		RuleID:     rule.ID.String(),
+		RuleName:   rule.Name,
+		Expression: rule.Expression,
+		Alerts:     make([]events.AlertData, len(alerts)),
+	}
+	for i, alert := range alerts {
+		data.Alerts[i] = events.AlertData{
+			ID:           alert.ID.String(),
+			CampaignID:   alert.CampaignID.String(),
+			CampaignName: alert.CampaignName,
+			Platform:     string(alert.Platform),
+			MetricDate:   alert.MetricDate.Format("2006-01-02"),
+			Values:       alert.Values,
+			Currency:     alert.Currency,
+		}
+	}
+	return data
+}
+
+// formatAlertValues formats the metrics of an alert in name order, e.g. "ROAS 1.20x, Spend MYR 100.00"
+func formatAlertValues(values map[string]float64, currency string) string {
+	fields := make([]string, 0, len(values))
+	for field := range values {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = formatAlertValue(field, values[field], currency)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func formatAlertValue(field string, value float64, currency string) string {
+	switch field {
+	case "spend":
+		return fmt.Sprintf("Spend %s %.2f", currency, value)
+	case "revenue":
+		return fmt.Sprintf("Revenue %s %.2f", currency, value)
+	case "cpa", "cpc", "cpm":
+		return fmt.Sprintf("%s %s %.2f", strings.ToUpper(field), currency, value)
+	case "roas":
+		return fmt.Sprintf("ROAS %.2fx", value)
+	case "ctr":
+		return fmt.Sprintf("CTR %.2f%%", value)
+	case "conversion_rate":
+		return fmt.Sprintf("Conversion rate %.2f%%", value)
+	default:
+		return fmt.Sprintf("%s %.0f", strings.ToUpper(field[:1])+field[1:], value)
+	}
+}
@@ -0,0 +1,108 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// AlertRuleEvaluator evaluates user-defined alert rules. It is implemented by the analytics alert rule service.
+type AlertRuleEvaluator interface {
+	EvaluateRules(ctx context.Context, orgID uuid.UUID, campaignIDs []uuid.UUID, asOf time.Time) ([]entity.AlertEvent, error)
+}
+
+// AlertRuleJob evaluates alert rules after each metrics sync and on a periodic
+// sweep over every organization with an active connection
+type AlertRuleJob struct {
+	connectedAccRepo repository.ConnectedAccountRepository
+	evaluator        AlertRuleEvaluator
+	logger           zerolog.Logger
+}
+
+// NewAlertRuleJob creates a new alert rule job
+func NewAlertRuleJob(connectedAccRepo repository.ConnectedAccountRepository, evaluator AlertRuleEvaluator, logger zerolog.Logger) *AlertRuleJob {
+	return &AlertRuleJob{
+		connectedAccRepo: connectedAccRepo,
+		evaluator:        evaluator,
+		logger:           logger.With().Str("job", "alert_rules").Logger(),
+	}
+}
+
+// AlertRuleResult represents the result of an alert rule run
+type AlertRuleResult struct {
+	Organizations int
+	Alerts        int
+	Failed        int
+}
+
+// MetricsSynced evaluates the rules against the campaigns whose metrics were
+// just synced. It implements the sync package's MetricsSyncListener.
+func (j *AlertRuleJob) MetricsSynced(ctx context.Context, orgID uuid.UUID, campaignIDs []uuid.UUID) {
+	alerts, err := j.evaluator.EvaluateRules(ctx, orgID, campaignIDs, time.Now())
+	j.logAlerts(orgID, alerts)
+	if err != nil {
+		j.logger.Error().Err(err).Str("org_id", orgID.String()).Msg("Failed to evaluate alert rules after metrics sync")
+	}
+}
+
+// Run evaluates the rules of every organization with an active connection. A
+// failing organization does not stop the others.
+func (j *AlertRuleJob) Run(ctx context.Context, now time.Time) (*AlertRuleResult, error) {
+	accounts, err := j.connectedAccRepo.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list connected accounts: %w", err)
+	}
+
+	result := &AlertRuleResult{}
+	seen := make(map[uuid.UUID]bool)
+	for _, account := range accounts {
+		if seen[account.OrganizationID] {
+			continue
+		}
+		seen[account.OrganizationID] = true
+
+		alerts, err := j.evaluator.EvaluateRules(ctx, account.OrganizationID, nil, now)
+		j.logAlerts(account.OrganizationID, alerts)
+		result.Alerts += len(alerts)
+		if err != nil {
+			j.logger.Error().Err(err).Str("org_id", account.OrganizationID.String()).Msg("Failed to evaluate alert rules")
+			result.Failed++
+			continue
+		}
+		result.Organizations++
+	}
+
+	if len(seen) > 0 {
+		j.logger.Info().
+			Int("organizations", result.Organizations).
+			Int("alerts", result.Alerts).
+			Int("failed", result.Failed).
+			Msg("Alert rule evaluation completed")
+	}
+
+	return result, nil
+}
+
+func (j *AlertRuleJob) logAlerts(orgID uuid.UUID, alerts []entity.AlertEvent) {
+	for _, alert := range alerts {
+		j.logger.Info().
+			Str("org_id", orgID.String()).
+			Str("rule_id", alert.RuleID.String()).
+			Str("campaign_id", alert.CampaignID.String()).
+			Strs("channels", alertChannelNames(alert.DeliveredChannels)).
+			Msg("Alert rule triggered")
+	}
+}
+
+func alertChannelNames(channels []entity.AlertChannel) []string {
+	names := make([]string, len(channels))
+	for i, channel := range channels {
+		names[i] = string(channel)
+	}
+	return names
+}
@@ -0,0 +1,195 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/email"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/events"
+	"github.com/ads-aggregator/ads-aggregator/pkg/httpclient"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type stubAlertRuleEvaluator struct {
+	alerts    map[uuid.UUID][]entity.AlertEvent
+	failOn    uuid.UUID
+	calls     map[uuid.UUID]int
+	campaigns []uuid.UUID
+}
+
+func (s *stubAlertRuleEvaluator) EvaluateRules(ctx context.Context, orgID uuid.UUID, campaignIDs []uuid.UUID, asOf time.Time) ([]entity.AlertEvent, error) {
+	if s.calls == nil {
+		s.calls = make(map[uuid.UUID]int)
+	}
+	s.calls[orgID]++
+	s.campaigns = campaignIDs
+	if orgID == s.failOn {
+		return nil, errors.New("metrics unavailable")
+	}
+	return s.alerts[orgID], nil
+}
+
+func TestAlertRuleJob_Run(t *testing.T) {
+	orgA, orgB := uuid.New(), uuid.New()
+	accounts := &stubConnectedAccountRepo{accounts: []entity.ConnectedAccount{
+		{OrganizationID: orgA, Platform: entity.PlatformMeta},
+		{OrganizationID: orgA, Platform: entity.PlatformShopee},
+		{OrganizationID: orgB, Platform: entity.PlatformMeta},
+	}}
+	evaluator := &stubAlertRuleEvaluator{
+		alerts: map[uuid.UUID][]entity.AlertEvent{orgA: {{ID: uuid.New()}, {ID: uuid.New()}}},
+		failOn: orgB,
+	}
+
+	result, err := NewAlertRuleJob(accounts, evaluator, zerolog.Nop()).Run(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Organizations != 1 || result.Alerts != 2 || result.Failed != 1 {
+		t.Errorf("result = %+v, want 1 organization, 2 alerts, 1 failed", result)
+	}
+	if evaluator.calls[orgA] != 1 {
+		t.Errorf("organization with two accounts evaluated %d times, want 1", evaluator.calls[orgA])
+	}
+}
+
+func TestAlertRuleJob_MetricsSynced(t *testing.T) {
+	orgID, campaignID := uuid.New(), uuid.New()
+	evaluator := &stubAlertRuleEvaluator{}
+
+	NewAlertRuleJob(&stubConnectedAccountRepo{}, evaluator, zerolog.Nop()).
+		MetricsSynced(context.Background(), orgID, []uuid.UUID{campaignID})
+
+	if evaluator.calls[orgID] != 1 || len(evaluator.campaigns) != 1 || evaluator.campaigns[0] != campaignID {
+		t.Errorf("evaluator called %d times with %v, want once with the synced campaign", evaluator.calls[orgID], evaluator.campaigns)
+	}
+}
+
+func testAlertRule() (*entity.AlertRule, []entity.AlertEvent) {
+	rule := &entity.AlertRule{
+		BaseEntity:     entity.BaseEntity{ID: uuid.New()},
+		OrganizationID: uuid.New(),
+		Name:           "Low ROAS",
+		Expression:     "roas < 1.5 for 3 days",
+		WebhookSecret:  "s3cret",
+	}
+	alerts := []entity.AlertEvent{{
+		ID:           uuid.New(),
+		RuleID:       rule.ID,
+		CampaignID:   uuid.New(),
+		CampaignName: "Raya Sale",
+		Platform:     entity.PlatformMeta,
+		MetricDate:   time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC),
+		Values:       map[string]float64{"roas": 1.2, "spend": 100},
+		Currency:     "MYR",
+	}}
+	return rule, alerts
+}
+
+func TestAlertEventNotifier(t *testing.T) {
+	broadcaster := &recordingBroadcaster{}
+	rule, alerts := testAlertRule()
+
+	if err := NewAlertEventNotifier(broadcaster).NotifyAlerts(context.Background(), rule, alerts); err != nil {
+		t.Fatalf("NotifyAlerts() error = %v", err)
+	}
+
+	if len(broadcaster.events) != 1 || broadcaster.events[0].Type != events.EventAlertTriggered {
+		t.Fatalf("events = %+v, want one alert:triggered event", broadcaster.events)
+	}
+	data := broadcaster.events[0].Data.(events.AlertTriggeredData)
+	if data.RuleName != "Low ROAS" || len(data.Alerts) != 1 || data.Alerts[0].MetricDate != "2026-03-15" {
+		t.Errorf("event data = %+v", data)
+	}
+}
+
+type recordingAlertMailer struct {
+	sent []*email.AlertTriggeredData
+}
+
+func (m *recordingAlertMailer) TriggerAlertTriggeredEmail(ctx context.Context, alert *email.AlertTriggeredData) error {
+	m.sent = append(m.sent, alert)
+	return nil
+}
+
+func TestAlertEmailNotifier(t *testing.T) {
+	members := &stubMemberRepo{members: []entity.OrganizationMember{
+		{Role: entity.RoleOwner, IsActive: true, User: &entity.User{Email: "owner@example.com"}},
+		{Role: entity.RoleViewer, IsActive: true, User: &entity.User{Email: "viewer@example.com"}},
+	}}
+	mailer := &recordingAlertMailer{}
+	rule, alerts := testAlertRule()
+
+	if err := NewAlertEmailNotifier(members, nil, mailer).NotifyAlerts(context.Background(), rule, alerts); err != nil {
+		t.Fatalf("NotifyAlerts() error = %v", err)
+	}
+	if len(mailer.sent) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(mailer.sent))
+	}
+
+	sent := mailer.sent[0]
+	if len(sent.Recipients) != 1 || sent.Recipients[0] != "owner@example.com" {
+		t.Errorf("recipients = %v, want the owner", sent.Recipients)
+	}
+	if sent.RuleName != "Low ROAS" || sent.Alerts[0].Values != "ROAS 1.20x, Spend MYR 100.00" {
+		t.Errorf("email = %+v", sent.Alerts)
+	}
+}
+
+func TestAlertWebhookNotifier(t *testing.T) {
+	rule, alerts := testAlertRule()
+
+	var received AlertWebhookPayload
+	var headers http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+		body, _ = io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &received)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	rule.WebhookURL = server.URL
+
+	notifier := NewAlertWebhookNotifier(server.Client())
+	if err := notifier.NotifyAlerts(context.Background(), rule, alerts); err != nil {
+		t.Fatalf("NotifyAlerts() error = %v", err)
+	}
+
+	if headers.Get(WebhookEventHeader) != "alert.triggered" || received.Event != "alert.triggered" {
+		t.Errorf("event = %q / %q, want alert.triggered", headers.Get(WebhookEventHeader), received.Event)
+	}
+	want := "sha256=" + SignWebhookPayload("s3cret", headers.Get(WebhookTimestampHeader), body)
+	if headers.Get(WebhookSignatureHeader) != want {
+		t.Errorf("signature = %q, want %q", headers.Get(WebhookSignatureHeader), want)
+	}
+	if received.OrganizationID != rule.OrganizationID.String() || len(received.Data.Alerts) != 1 || received.Data.Alerts[0].Values["roas"] != 1.2 {
+		t.Errorf("payload = %+v", received)
+	}
+
+	// Non-2xx responses are delivery failures
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+	rule.WebhookURL = failing.URL
+
+	err := notifier.NotifyAlerts(context.Background(), rule, alerts)
+	if err == nil || !strings.Contains(err.Error(), "502") {
+		t.Errorf("NotifyAlerts() error = %v, want status 502", err)
+	}
+
+	// The default client does not connect to internal addresses
+	if err := NewAlertWebhookNotifier(nil).NotifyAlerts(context.Background(), rule, alerts); !errors.Is(err, httpclient.ErrNonPublicAddress) {
+		t.Errorf("NotifyAlerts() to a loopback address error = %v, want ErrNonPublicAddress", err)
+	}
+}
@@ -0,0 +1,101 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// AnomalyDetector looks for anomalies in daily campaign metrics. It is implemented by the analytics anomaly service.
+type AnomalyDetector interface {
+	DetectAnomalies(ctx context.Context, orgID uuid.UUID, campaignIDs []uuid.UUID, asOf time.Time) ([]entity.MetricAnomaly, error)
+}
+
+// AnomalyDetectionJob runs anomaly detection after each metrics sync and on a
+// daily sweep over every organization with an active connection
+type AnomalyDetectionJob struct {
+	connectedAccRepo repository.ConnectedAccountRepository
+	detector         AnomalyDetector
+	logger           zerolog.Logger
+}
+
+// NewAnomalyDetectionJob creates a new anomaly detection job
+func NewAnomalyDetectionJob(connectedAccRepo repository.ConnectedAccountRepository, detector AnomalyDetector, logger zerolog.Logger) *AnomalyDetectionJob {
+	return &AnomalyDetectionJob{
+		connectedAccRepo: connectedAccRepo,
+		detector:         detector,
+		logger:           logger.With().Str("job", "anomaly_detection").Logger(),
+	}
+}
+
+// AnomalyDetectionResult represents the result of an anomaly detection run
+type AnomalyDetectionResult struct {
+	Organizations int
+	Anomalies     int
+	Failed        int
+}
+
+// MetricsSynced checks the campaigns whose metrics were just synced. It
+// implements the sync package's MetricsSyncListener.
+func (j *AnomalyDetectionJob) MetricsSynced(ctx context.Context, orgID uuid.UUID, campaignIDs []uuid.UUID) {
+	anomalies, err := j.detector.DetectAnomalies(ctx, orgID, campaignIDs, time.Now())
+	j.logAnomalies(orgID, anomalies)
+	if err != nil {
+		j.logger.Error().Err(err).Str("org_id", orgID.String()).Msg("Failed to detect anomalies after metrics sync")
+	}
+}
+
+// Run checks all active campaigns of every organization with an active
+// connection. A failing organization does not stop the others.
+func (j *AnomalyDetectionJob) Run(ctx context.Context, now time.Time) (*AnomalyDetectionResult, error) {
+	accounts, err := j.connectedAccRepo.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list connected accounts: %w", err)
+	}
+
+	result := &AnomalyDetectionResult{}
+	seen := make(map[uuid.UUID]bool)
+	for _, account := range accounts {
+		if seen[account.OrganizationID] {
+			continue
+		}
+		seen[account.OrganizationID] = true
+
+		anomalies, err := j.detector.DetectAnomalies(ctx, account.OrganizationID, nil, now)
+		j.logAnomalies(account.OrganizationID, anomalies)
+		result.Anomalies += len(anomalies)
+		if err != nil {
+			j.logger.Error().Err(err).Str("org_id", account.OrganizationID.String()).Msg("Failed to detect anomalies")
+			result.Failed++
+			continue
+		}
+		result.Organizations++
+	}
+
+	if len(seen) > 0 {
+		j.logger.Info().
+			Int("organizations", result.Organizations).
+			Int("anomalies", result.Anomalies).
+			Int("failed", result.Failed).
+			Msg("Anomaly detection completed")
+	}
+
+	return result, nil
+}
+
+func (j *AnomalyDetectionJob) logAnomalies(orgID uuid.UUID, anomalies []entity.MetricAnomaly) {
+	for _, anomaly := range anomalies {
+		j.logger.Info().
+			Str("org_id", orgID.String()).
+			Str("campaign_id", anomaly.CampaignID.String()).
+			Str("metric", string(anomaly.Metric)).
+			Str("severity", string(anomaly.Severity)).
+			Float64("change_percent", anomaly.ChangePercent).
+			Msg("Metric anomaly detected")
+	}
+}
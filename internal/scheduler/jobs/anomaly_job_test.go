@@ -0,0 +1,228 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/email"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/events"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type stubConnectedAccountRepo struct {
+	accounts []entity.ConnectedAccount
+}
+
+func (s *stubConnectedAccountRepo) Create(ctx context.Context, account *entity.ConnectedAccount) error {
+	return nil
+}
+func (s *stubConnectedAccountRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.ConnectedAccount, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *stubConnectedAccountRepo) GetByPlatformAccountID(ctx context.Context, orgID uuid.UUID, platform entity.Platform, platformAccountID string) (*entity.ConnectedAccount, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *stubConnectedAccountRepo) Update(ctx context.Context, account *entity.ConnectedAccount) error {
+	return nil
+}
+func (s *stubConnectedAccountRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
+func (s *stubConnectedAccountRepo) ListByOrganization(ctx context.Context, orgID uuid.UUID) ([]entity.ConnectedAccount, error) {
+	return nil, nil
+}
+func (s *stubConnectedAccountRepo) ListByPlatform(ctx context.Context, orgID uuid.UUID, platform entity.Platform) ([]entity.ConnectedAccount, error) {
+	return nil, nil
+}
+func (s *stubConnectedAccountRepo) ListExpiring(ctx context.Context, withinMinutes int) ([]entity.ConnectedAccount, error) {
+	return nil, nil
+}
+func (s *stubConnectedAccountRepo) ListActive(ctx context.Context) ([]entity.ConnectedAccount, error) {
+	return s.accounts, nil
+}
+func (s *stubConnectedAccountRepo) UpdateTokens(ctx context.Context, id uuid.UUID, accessToken, refreshToken string, expiresAt *interface{}) error {
+	return nil
+}
+func (s *stubConnectedAccountRepo) UpdateSyncStatus(ctx context.Context, id uuid.UUID, status entity.AccountStatus, syncError string) error {
+	return nil
+}
+func (s *stubConnectedAccountRepo) UpdateLastSynced(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+type stubAnomalyDetector struct {
+	anomalies map[uuid.UUID][]entity.MetricAnomaly
+	failOn    uuid.UUID
+	calls     map[uuid.UUID]int
+	campaigns []uuid.UUID
+}
+
+func (s *stubAnomalyDetector) DetectAnomalies(ctx context.Context, orgID uuid.UUID, campaignIDs []uuid.UUID, asOf time.Time) ([]entity.MetricAnomaly, error) {
+	if s.calls == nil {
+		s.calls = make(map[uuid.UUID]int)
+	}
+	s.calls[orgID]++
+	s.campaigns = campaignIDs
+	if orgID == s.failOn {
+		return nil, errors.New("metrics unavailable")
+	}
+	return s.anomalies[orgID], nil
+}
+
+func TestAnomalyDetectionJob_Run(t *testing.T) {
+	orgA, orgB := uuid.New(), uuid.New()
+	accounts := &stubConnectedAccountRepo{accounts: []entity.ConnectedAccount{
+		{OrganizationID: orgA, Platform: entity.PlatformMeta},
+		{OrganizationID: orgA, Platform: entity.PlatformTikTok},
+		{OrganizationID: orgB, Platform: entity.PlatformMeta},
+	}}
+	detector := &stubAnomalyDetector{
+		anomalies: map[uuid.UUID][]entity.MetricAnomaly{
+			orgA: {{OrganizationID: orgA, Metric: entity.AnomalyMetricCPA}},
+		},
+		failOn: orgB,
+	}
+
+	result, err := NewAnomalyDetectionJob(accounts, detector, zerolog.Nop()).Run(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// Each organization is checked once, however many accounts it has
+	if detector.calls[orgA] != 1 {
+		t.Errorf("organization A checked %d times, want 1", detector.calls[orgA])
+	}
+	if result.Organizations != 1 || result.Anomalies != 1 || result.Failed != 1 {
+		t.Errorf("result = %+v, want 1 organization, 1 anomaly, 1 failed", result)
+	}
+}
+
+func TestAnomalyDetectionJob_MetricsSynced(t *testing.T) {
+	orgID, campaignID := uuid.New(), uuid.New()
+	detector := &stubAnomalyDetector{}
+
+	NewAnomalyDetectionJob(&stubConnectedAccountRepo{}, detector, zerolog.Nop()).
+		MetricsSynced(context.Background(), orgID, []uuid.UUID{campaignID})
+
+	if detector.calls[orgID] != 1 || len(detector.campaigns) != 1 || detector.campaigns[0] != campaignID {
+		t.Errorf("detector called %d times with %v, want once with the synced campaign", detector.calls[orgID], detector.campaigns)
+	}
+}
+
+type recordingBroadcaster struct {
+	events []events.Event
+}
+
+func (b *recordingBroadcaster) BroadcastToOrg(orgID uuid.UUID, event events.Event) {
+	b.events = append(b.events, event)
+}
+
+func TestAnomalyEventNotifier(t *testing.T) {
+	broadcaster := &recordingBroadcaster{}
+	anomaly := entity.MetricAnomaly{
+		ID:         uuid.New(),
+		CampaignID: uuid.New(),
+		MetricDate: time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC),
+		Metric:     entity.AnomalyMetricSpend,
+		Severity:   entity.AnomalySeverityLow,
+	}
+
+	if err := NewAnomalyEventNotifier(broadcaster).NotifyAnomalies(context.Background(), uuid.New(), []entity.MetricAnomaly{anomaly}); err != nil {
+		t.Fatalf("NotifyAnomalies() error = %v", err)
+	}
+
+	if len(broadcaster.events) != 1 || broadcaster.events[0].Type != events.EventAnomalyDetected {
+		t.Fatalf("events = %+v, want one anomaly:detected event", broadcaster.events)
+	}
+	data := broadcaster.events[0].Data.(events.AnomalyDetectedData)
+	if len(data.Anomalies) != 1 || data.Anomalies[0].MetricDate != "2026-03-15" || data.Anomalies[0].Severity != "low" {
+		t.Errorf("event data = %+v", data)
+	}
+}
+
+type stubMemberRepo struct {
+	members []entity.OrganizationMember
+}
+
+func (s *stubMemberRepo) Create(ctx context.Context, member *entity.OrganizationMember) error {
+	return nil
+}
+func (s *stubMemberRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.OrganizationMember, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *stubMemberRepo) GetByOrgAndUser(ctx context.Context, orgID, userID uuid.UUID) (*entity.OrganizationMember, error) {
+	return nil, errors.New("not implemented")
+}
+func (s *stubMemberRepo) Update(ctx context.Context, member *entity.OrganizationMember) error {
+	return nil
+}
+func (s *stubMemberRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
+func (s *stubMemberRepo) ListByOrganization(ctx context.Context, orgID uuid.UUID, pagination *entity.Pagination) ([]entity.OrganizationMember, error) {
+	return s.members, nil
+}
+func (s *stubMemberRepo) ListByUser(ctx context.Context, userID uuid.UUID) ([]entity.OrganizationMember, error) {
+	return nil, nil
+}
+func (s *stubMemberRepo) UpdateRole(ctx context.Context, id uuid.UUID, role entity.UserRole) error {
+	return nil
+}
+
+type recordingAnomalyMailer struct {
+	sent []*email.AnomalyAlertData
+}
+
+func (m *recordingAnomalyMailer) TriggerAnomalyAlertEmail(ctx context.Context, alert *email.AnomalyAlertData) error {
+	m.sent = append(m.sent, alert)
+	return nil
+}
+
+func TestAnomalyEmailNotifier(t *testing.T) {
+	members := &stubMemberRepo{members: []entity.OrganizationMember{
+		{Role: entity.RoleOwner, IsActive: true, User: &entity.User{Email: "owner@example.com"}},
+		{Role: entity.RoleAdmin, IsActive: true, User: &entity.User{Email: "admin@example.com"}},
+		{Role: entity.RoleViewer, IsActive: true, User: &entity.User{Email: "viewer@example.com"}},
+		{Role: entity.RoleAdmin, IsActive: false, User: &entity.User{Email: "former@example.com"}},
+	}}
+	mailer := &recordingAnomalyMailer{}
+	notifier := NewAnomalyEmailNotifier(members, nil, mailer)
+	day := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	// Low and medium anomalies are not emailed
+	err := notifier.NotifyAnomalies(context.Background(), uuid.New(), []entity.MetricAnomaly{
+		{MetricDate: day, Metric: entity.AnomalyMetricCTR, Severity: entity.AnomalySeverityMedium},
+	})
+	if err != nil || len(mailer.sent) != 0 {
+		t.Fatalf("NotifyAnomalies() sent %d emails, err = %v, want none", len(mailer.sent), err)
+	}
+
+	err = notifier.NotifyAnomalies(context.Background(), uuid.New(), []entity.MetricAnomaly{
+		{MetricDate: day, Metric: entity.AnomalyMetricCTR, Severity: entity.AnomalySeverityLow},
+		{MetricDate: day, Metric: entity.AnomalyMetricSpend, Severity: entity.AnomalySeverityCritical, Value: 0, Expected: 100, Currency: "MYR", ChangePercent: -100},
+		{MetricDate: day, Metric: entity.AnomalyMetricROAS, Severity: entity.AnomalySeverityHigh, Value: 1.6, Expected: 4},
+	})
+	if err != nil {
+		t.Fatalf("NotifyAnomalies() error = %v", err)
+	}
+	if len(mailer.sent) != 1 {
+		t.Fatalf("sent %d emails, want 1", len(mailer.sent))
+	}
+
+	alert := mailer.sent[0]
+	if len(alert.Recipients) != 2 || alert.Recipients[0] != "owner@example.com" || alert.Recipients[1] != "admin@example.com" {
+		t.Errorf("recipients = %v, want the active owner and admin", alert.Recipients)
+	}
+	if alert.CompanyName != "AdsAnalytic" || !alert.MetricDate.Equal(day) {
+		t.Errorf("alert = %+v", alert)
+	}
+	if len(alert.Anomalies) != 2 {
+		t.Fatalf("anomalies = %+v, want the critical and high ones", alert.Anomalies)
+	}
+	if spend := alert.Anomalies[0]; !spend.Critical || spend.Value != "MYR 0.00" || spend.Expected != "MYR 100.00" {
+		t.Errorf("spend item = %+v", spend)
+	}
+	if roas := alert.Anomalies[1]; roas.Critical || roas.Metric != "ROAS" || roas.Value != "1.60x" {
+		t.Errorf("ROAS item = %+v", roas)
+	}
+}
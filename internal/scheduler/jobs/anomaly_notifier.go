@@ -0,0 +1,169 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/email"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/events"
+	"github.com/google/uuid"
+)
+
+// OrgBroadcaster pushes events to the organization's connected clients
+type OrgBroadcaster interface {
+	BroadcastToOrg(orgID uuid.UUID, event events.Event)
+}
+
+// AnomalyEventNotifier pushes every new anomaly over the SSE event stream
+type AnomalyEventNotifier struct {
+	broadcaster OrgBroadcaster
+}
+
+// NewAnomalyEventNotifier creates a new anomaly event notifier
+func NewAnomalyEventNotifier(broadcaster OrgBroadcaster) *AnomalyEventNotifier {
+	return &AnomalyEventNotifier{broadcaster: broadcaster}
+}
+
+// NotifyAnomalies broadcasts an anomaly:detected event to the organization
+func (n *AnomalyEventNotifier) NotifyAnomalies(ctx context.Context, orgID uuid.UUID, anomalies []entity.MetricAnomaly) error {
+	data := make([]events.AnomalyData, len(anomalies))
+	for i, anomaly := range anomalies {
+		data[i] = events.AnomalyData{
+			ID:            anomaly.ID.String(),
+			CampaignID:    anomaly.CampaignID.String(),
+			CampaignName:  anomaly.CampaignName,
+			Platform:      string(anomaly.Platform),
+			MetricDate:    anomaly.MetricDate.Format("2006-01-02"),
+			Metric:        string(anomaly.Metric),
+			Value:         anomaly.Value,
+			Expected:      anomaly.Expected,
+			ChangePercent: anomaly.ChangePercent,
+			Direction:     string(anomaly.Direction),
+			Severity:      string(anomaly.Severity),
+		}
+	}
+
+	n.broadcaster.BroadcastToOrg(orgID, events.NewAnomalyDetectedEvent(data))
+	return nil
+}
+
+// AnomalyMailer delivers anomaly alerts by email
+type AnomalyMailer interface {
+	TriggerAnomalyAlertEmail(ctx context.Context, alert *email.AnomalyAlertData) error
+}
+
+// AnomalyEmailNotifier emails high and critical anomalies to the owners and
+// admins of the organization. Lower severities are only shown in the app.
+type AnomalyEmailNotifier struct {
+	memberRepo repository.OrganizationMemberRepository
+	orgRepo    repository.OrganizationRepository
+	mailer     AnomalyMailer
+}
+
+// NewAnomalyEmailNotifier creates a new anomaly email notifier
+func NewAnomalyEmailNotifier(
+	memberRepo repository.OrganizationMemberRepository,
+	orgRepo repository.OrganizationRepository,
+	mailer AnomalyMailer,
+) *AnomalyEmailNotifier {
+	return &AnomalyEmailNotifier{
+		memberRepo: memberRepo,
+		orgRepo:    orgRepo,
+		mailer:     mailer,
+	}
+}
+
+// NotifyAnomalies emails the anomalies of at least high severity
+func (n *AnomalyEmailNotifier) NotifyAnomalies(ctx context.Context, orgID uuid.UUID, anomalies []entity.MetricAnomaly) error {
+	var items []email.AnomalyItem
+	for _, anomaly := range anomalies {
+		if anomaly.Severity.Rank() < entity.AnomalySeverityHigh.Rank() {
+			continue
+		}
+		items = append(items, email.AnomalyItem{
+			CampaignName:  anomaly.CampaignName,
+			Platform:      string(anomaly.Platform),
+			Metric:        anomalyMetricLabel(anomaly.Metric),
+			Value:         formatAnomalyValue(anomaly.Metric, anomaly.Value, anomaly.Currency),
+			Expected:      formatAnomalyValue(anomaly.Metric, anomaly.Expected, anomaly.Currency),
+			ChangePercent: anomaly.ChangePercent,
+			Critical:      anomaly.Severity == entity.AnomalySeverityCritical,
+		})
+	}
+	if len(items) == 0 {
+		return nil
+	}
+
+	recipients, err := adminRecipients(ctx, n.memberRepo, orgID)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	return n.mailer.TriggerAnomalyAlertEmail(ctx, &email.AnomalyAlertData{
+		Recipients:  recipients,
+		CompanyName: companyName(ctx, n.orgRepo, orgID),
+		MetricDate:  anomalies[0].MetricDate,
+		Anomalies:   items,
+	})
+}
+
+// adminRecipients returns the email addresses of the active owners and admins of an organization
+func adminRecipients(ctx context.Context, memberRepo repository.OrganizationMemberRepository, orgID uuid.UUID) ([]string, error) {
+	members, err := memberRepo.ListByOrganization(ctx, orgID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list organization members: %w", err)
+	}
+	var recipients []string
+	for _, member := range members {
+		if !member.IsActive || member.User == nil || member.User.Email == "" {
+			continue
+		}
+		if member.Role == entity.RoleOwner || member.Role == entity.RoleAdmin {
+			recipients = append(recipients, member.User.Email)
+		}
+	}
+	return recipients, nil
+}
+
+// companyName returns the name of an organization shown in emails
+func companyName(ctx context.Context, orgRepo repository.OrganizationRepository, orgID uuid.UUID) string {
+	if orgRepo != nil {
+		if org, err := orgRepo.GetByID(ctx, orgID); err == nil && org != nil {
+			return org.Name
+		}
+	}
+	return "AdsAnalytic"
+}
+
+func anomalyMetricLabel(metric entity.AnomalyMetric) string {
+	switch metric {
+	case entity.AnomalyMetricSpend:
+		return "Spend"
+	case entity.AnomalyMetricCTR:
+		return "CTR"
+	case entity.AnomalyMetricCPC:
+		return "CPC"
+	case entity.AnomalyMetricCPA:
+		return "CPA"
+	case entity.AnomalyMetricROAS:
+		return "ROAS"
+	default:
+		return string(metric)
+	}
+}
+
+func formatAnomalyValue(metric entity.AnomalyMetric, value float64, currency string) string {
+	switch metric {
+	case entity.AnomalyMetricCTR:
+		return fmt.Sprintf("%.2f%%", value)
+	case entity.AnomalyMetricROAS:
+		return fmt.Sprintf("%.2fx", value)
+	default:
+		return fmt.Sprintf("%s %.2f", currency, value)
+	}
+}
@@ -0,0 +1,79 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// BudgetAlertChecker raises budget alerts for an organization. It is implemented by the analytics budget service.
+type BudgetAlertChecker interface {
+	CheckAlerts(ctx context.Context, orgID uuid.UUID, asOf time.Time) ([]entity.BudgetAlert, error)
+}
+
+// BudgetAlertJob checks every active organization budget against month-to-date spend
+type BudgetAlertJob struct {
+	budgetRepo repository.OrganizationBudgetRepository
+	checker    BudgetAlertChecker
+	logger     zerolog.Logger
+}
+
+// NewBudgetAlertJob creates a new budget alert job
+func NewBudgetAlertJob(budgetRepo repository.OrganizationBudgetRepository, checker BudgetAlertChecker, logger zerolog.Logger) *BudgetAlertJob {
+	return &BudgetAlertJob{
+		budgetRepo: budgetRepo,
+		checker:    checker,
+		logger:     logger.With().Str("job", "budget_alerts").Logger(),
+	}
+}
+
+// BudgetAlertResult represents the result of a budget alert run
+type BudgetAlertResult struct {
+	Checked int
+	Raised  int
+	Failed  int
+}
+
+// Run checks each active budget. A failing organization does not stop the others.
+func (j *BudgetAlertJob) Run(ctx context.Context, now time.Time) (*BudgetAlertResult, error) {
+	budgets, err := j.budgetRepo.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list budgets: %w", err)
+	}
+
+	result := &BudgetAlertResult{}
+	for _, budget := range budgets {
+		alerts, err := j.checker.CheckAlerts(ctx, budget.OrganizationID, now)
+		if err != nil {
+			j.logger.Error().Err(err).Str("org_id", budget.OrganizationID.String()).Msg("Failed to check budget")
+			result.Failed++
+			continue
+		}
+		result.Checked++
+		result.Raised += len(alerts)
+
+		for _, alert := range alerts {
+			j.logger.Info().
+				Str("org_id", alert.OrganizationID.String()).
+				Str("platform", string(alert.Platform)).
+				Int("threshold", alert.Threshold).
+				Float64("usage_percent", alert.UsagePercent).
+				Msg("Budget alert raised")
+		}
+	}
+
+	if len(budgets) > 0 {
+		j.logger.Info().
+			Int("checked", result.Checked).
+			Int("raised", result.Raised).
+			Int("failed", result.Failed).
+			Msg("Budget check completed")
+	}
+
+	return result, nil
+}
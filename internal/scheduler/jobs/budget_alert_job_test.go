@@ -0,0 +1,67 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type stubBudgetRepository struct {
+	budgets []entity.OrganizationBudget
+}
+
+func (s *stubBudgetRepository) GetByOrganization(ctx context.Context, orgID uuid.UUID) (*entity.OrganizationBudget, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (s *stubBudgetRepository) Upsert(ctx context.Context, budget *entity.OrganizationBudget) error {
+	return nil
+}
+
+func (s *stubBudgetRepository) Delete(ctx context.Context, orgID uuid.UUID) error {
+	return nil
+}
+
+func (s *stubBudgetRepository) ListActive(ctx context.Context) ([]entity.OrganizationBudget, error) {
+	return s.budgets, nil
+}
+
+type stubBudgetChecker struct {
+	alerts map[uuid.UUID][]entity.BudgetAlert
+	failOn uuid.UUID
+}
+
+func (s *stubBudgetChecker) CheckAlerts(ctx context.Context, orgID uuid.UUID, asOf time.Time) ([]entity.BudgetAlert, error) {
+	if orgID == s.failOn {
+		return nil, errors.New("aggregation failed")
+	}
+	return s.alerts[orgID], nil
+}
+
+func TestBudgetAlertJob_Run(t *testing.T) {
+	orgA, orgB, orgC := uuid.New(), uuid.New(), uuid.New()
+	repo := &stubBudgetRepository{budgets: []entity.OrganizationBudget{
+		{OrganizationID: orgA}, {OrganizationID: orgB}, {OrganizationID: orgC},
+	}}
+	checker := &stubBudgetChecker{
+		alerts: map[uuid.UUID][]entity.BudgetAlert{
+			orgA: {{OrganizationID: orgA, Threshold: 50}, {OrganizationID: orgA, Threshold: 80}},
+		},
+		failOn: orgB,
+	}
+
+	result, err := NewBudgetAlertJob(repo, checker, zerolog.Nop()).Run(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	// A failing organization does not stop the others
+	if result.Checked != 2 || result.Raised != 2 || result.Failed != 1 {
+		t.Errorf("result = %+v, want 2 checked, 2 raised, 1 failed", result)
+	}
+}
@@ -0,0 +1,55 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/pkg/currency"
+	"github.com/rs/zerolog"
+)
+
+// RateImporter imports exchange rates from a feed. It is implemented by currency.DatedRateProvider.
+type RateImporter interface {
+	Import(ctx context.Context, source currency.RateSource) (int, error)
+}
+
+// ExchangeRateJob imports the latest reference exchange rates
+type ExchangeRateJob struct {
+	importer RateImporter
+	source   currency.RateSource
+	logger   zerolog.Logger
+}
+
+// NewExchangeRateJob creates a new exchange rate import job
+func NewExchangeRateJob(importer RateImporter, source currency.RateSource, logger zerolog.Logger) *ExchangeRateJob {
+	return &ExchangeRateJob{
+		importer: importer,
+		source:   source,
+		logger:   logger.With().Str("job", "exchange_rates").Logger(),
+	}
+}
+
+// ExchangeRateResult represents the result of an exchange rate import
+type ExchangeRateResult struct {
+	Imported int
+	Duration time.Duration
+}
+
+// Run fetches the feed and stores its rates. Rates already stored are updated in place,
+// so overlapping feeds such as the 90-day history can be imported every day.
+func (j *ExchangeRateJob) Run(ctx context.Context) (*ExchangeRateResult, error) {
+	start := time.Now()
+
+	imported, err := j.importer.Import(ctx, j.source)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ExchangeRateResult{Imported: imported, Duration: time.Since(start)}
+	j.logger.Info().
+		Int("imported", result.Imported).
+		Dur("duration", result.Duration).
+		Msg("Exchange rates imported")
+
+	return result, nil
+}
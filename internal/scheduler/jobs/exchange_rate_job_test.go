@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/pkg/currency"
+	"github.com/rs/zerolog"
+	"github.com/shopspring/decimal"
+)
+
+type stubRateSource struct {
+	rates []currency.DatedRate
+	err   error
+}
+
+func (s *stubRateSource) FetchRates(ctx context.Context) ([]currency.DatedRate, error) {
+	return s.rates, s.err
+}
+
+func TestExchangeRateJob_Run(t *testing.T) {
+	rateDate := time.Date(2024, 9, 2, 0, 0, 0, 0, time.UTC)
+	provider := currency.NewDatedRateProvider("EUR", nil, nil)
+	source := &stubRateSource{rates: []currency.DatedRate{
+		{Date: rateDate, Base: "EUR", Quote: "USD", Rate: decimal.RequireFromString("1.1048")},
+		{Date: rateDate, Base: "EUR", Quote: "MYR", Rate: decimal.RequireFromString("4.8078")},
+	}}
+
+	job := NewExchangeRateJob(provider, source, zerolog.Nop())
+	result, err := job.Run(context.Background())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if result.Imported != 2 {
+		t.Errorf("Imported = %d, want 2", result.Imported)
+	}
+	if latest, ok := provider.LatestDate(); !ok || !latest.Equal(rateDate) {
+		t.Errorf("LatestDate() = %v, %v", latest, ok)
+	}
+
+	source.err = errors.New("feed unavailable")
+	if _, err := job.Run(context.Background()); err == nil {
+		t.Error("expected error when the feed fails")
+	}
+}
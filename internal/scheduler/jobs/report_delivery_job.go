@@ -0,0 +1,162 @@
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/email"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ReportRenderer builds report files. It is implemented by the analytics service.
+type ReportRenderer interface {
+	RenderReport(ctx context.Context, orgID uuid.UUID, dateRange entity.DateRange, format entity.ReportFormat, whiteLabel bool) ([]byte, error)
+}
+
+// ReportMailer delivers a generated report by email
+type ReportMailer interface {
+	TriggerScheduledReportEmail(ctx context.Context, report *email.ScheduledReportData) error
+}
+
+// ReportDeliveryJob emails reports for report schedules that are due
+type ReportDeliveryJob struct {
+	scheduleRepo repository.ReportScheduleRepository
+	orgRepo      repository.OrganizationRepository
+	renderer     ReportRenderer
+	mailer       ReportMailer
+	logger       zerolog.Logger
+	batchSize    int
+}
+
+// NewReportDeliveryJob creates a new report delivery job
+func NewReportDeliveryJob(
+	scheduleRepo repository.ReportScheduleRepository,
+	orgRepo repository.OrganizationRepository,
+	renderer ReportRenderer,
+	mailer ReportMailer,
+	logger zerolog.Logger,
+) *ReportDeliveryJob {
+	return &ReportDeliveryJob{
+		scheduleRepo: scheduleRepo,
+		orgRepo:      orgRepo,
+		renderer:     renderer,
+		mailer:       mailer,
+		logger:       logger.With().Str("job", "report_delivery").Logger(),
+		batchSize:    50,
+	}
+}
+
+// ReportDeliveryResult represents the result of a report delivery run
+type ReportDeliveryResult struct {
+	Due       int
+	Delivered int
+	Failed    int
+	Skipped   int
+}
+
+// Run delivers every schedule whose next run is at or before now
+func (j *ReportDeliveryJob) Run(ctx context.Context, now time.Time) (*ReportDeliveryResult, error) {
+	result := &ReportDeliveryResult{}
+
+	schedules, err := j.scheduleRepo.ListDue(ctx, now, j.batchSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list due report schedules: %w", err)
+	}
+	result.Due = len(schedules)
+
+	for i := range schedules {
+		schedule := &schedules[i]
+
+		claimed, err := j.claim(ctx, schedule, now)
+		if err != nil {
+			j.logger.Error().Err(err).Str("schedule_id", schedule.ID.String()).Msg("Failed to claim report schedule")
+			result.Failed++
+			continue
+		}
+		if !claimed {
+			// Another worker picked up this run
+			result.Skipped++
+			continue
+		}
+
+		status, message := entity.ReportScheduleRunSent, ""
+		if err := j.deliver(ctx, schedule, now); err != nil {
+			j.logger.Error().
+				Err(err).
+				Str("schedule_id", schedule.ID.String()).
+				Str("org_id", schedule.OrganizationID.String()).
+				Msg("Scheduled report delivery failed")
+			status, message = entity.ReportScheduleRunFailed, err.Error()
+			result.Failed++
+		} else {
+			result.Delivered++
+		}
+
+		if err := j.scheduleRepo.RecordRunResult(ctx, schedule.ID, status, message); err != nil {
+			j.logger.Error().Err(err).Str("schedule_id", schedule.ID.String()).Msg("Failed to record report schedule result")
+		}
+	}
+
+	if result.Due > 0 {
+		j.logger.Info().
+			Int("due", result.Due).
+			Int("delivered", result.Delivered).
+			Int("failed", result.Failed).
+			Int("skipped", result.Skipped).
+			Msg("Report delivery completed")
+	}
+
+	return result, nil
+}
+
+// claim advances the schedule to its next run so that no other worker delivers this run
+func (j *ReportDeliveryJob) claim(ctx context.Context, schedule *entity.ReportSchedule, now time.Time) (bool, error) {
+	if schedule.NextRunAt == nil {
+		return false, nil
+	}
+	nextRun, err := schedule.NextRunAfter(now)
+	if err != nil {
+		return false, fmt.Errorf("invalid cron expression %q: %w", schedule.CronExpression, err)
+	}
+	return j.scheduleRepo.ClaimRun(ctx, schedule.ID, *schedule.NextRunAt, nextRun, now)
+}
+
+// deliver renders the report and queues it for each recipient
+func (j *ReportDeliveryJob) deliver(ctx context.Context, schedule *entity.ReportSchedule, now time.Time) error {
+	if len(schedule.Recipients) == 0 {
+		return fmt.Errorf("schedule has no recipients")
+	}
+
+	dateRange := schedule.ReportDateRange(now)
+
+	data, err := j.renderer.RenderReport(ctx, schedule.OrganizationID, dateRange, schedule.Format, schedule.WhiteLabel)
+	if err != nil {
+		return fmt.Errorf("failed to render report: %w", err)
+	}
+
+	companyName := "AdsAnalytic"
+	if j.orgRepo != nil {
+		if org, err := j.orgRepo.GetByID(ctx, schedule.OrganizationID); err == nil && org != nil {
+			companyName = org.Name
+		}
+	}
+
+	return j.mailer.TriggerScheduledReportEmail(ctx, &email.ScheduledReportData{
+		Recipients:   schedule.Recipients,
+		ScheduleName: schedule.Name,
+		CompanyName:  companyName,
+		PeriodStart:  dateRange.StartDate,
+		PeriodEnd:    dateRange.EndDate,
+		Format:       string(schedule.Format),
+		Attachment: email.Attachment{
+			Filename: fmt.Sprintf("report_%s_%s.%s",
+				dateRange.StartDate.Format("2006-01-02"), dateRange.EndDate.Format("2006-01-02"), schedule.Format),
+			ContentType: schedule.Format.ContentType(),
+			Content:     data,
+		},
+	})
+}
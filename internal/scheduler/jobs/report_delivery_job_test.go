@@ -0,0 +1,144 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/email"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+type mockScheduleRepo struct {
+	due     []entity.ReportSchedule
+	claimed map[uuid.UUID]time.Time
+	results map[uuid.UUID]string
+	// claimFails marks schedules that another worker already claimed
+	claimFails map[uuid.UUID]bool
+}
+
+func (m *mockScheduleRepo) Create(ctx context.Context, schedule *entity.ReportSchedule) error {
+	return nil
+}
+
+func (m *mockScheduleRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.ReportSchedule, error) {
+	return nil, errors.New("not found")
+}
+
+func (m *mockScheduleRepo) Update(ctx context.Context, schedule *entity.ReportSchedule) error {
+	return nil
+}
+
+func (m *mockScheduleRepo) Delete(ctx context.Context, id uuid.UUID) error { return nil }
+
+func (m *mockScheduleRepo) ListByOrganization(ctx context.Context, orgID uuid.UUID) ([]entity.ReportSchedule, error) {
+	return nil, nil
+}
+
+func (m *mockScheduleRepo) ListDue(ctx context.Context, now time.Time, limit int) ([]entity.ReportSchedule, error) {
+	return m.due, nil
+}
+
+func (m *mockScheduleRepo) ClaimRun(ctx context.Context, id uuid.UUID, expectedNextRun, nextRun, runAt time.Time) (bool, error) {
+	if m.claimFails[id] {
+		return false, nil
+	}
+	m.claimed[id] = nextRun
+	return true, nil
+}
+
+func (m *mockScheduleRepo) RecordRunResult(ctx context.Context, id uuid.UUID, status, errorMessage string) error {
+	m.results[id] = status
+	return nil
+}
+
+type mockRenderer struct {
+	ranges []entity.DateRange
+}
+
+func (m *mockRenderer) RenderReport(ctx context.Context, orgID uuid.UUID, dateRange entity.DateRange, format entity.ReportFormat, whiteLabel bool) ([]byte, error) {
+	m.ranges = append(m.ranges, dateRange)
+	return []byte("%PDF-1.4"), nil
+}
+
+type mockMailer struct {
+	sent []*email.ScheduledReportData
+}
+
+func (m *mockMailer) TriggerScheduledReportEmail(ctx context.Context, report *email.ScheduledReportData) error {
+	m.sent = append(m.sent, report)
+	return nil
+}
+
+func TestReportDeliveryJob_Run(t *testing.T) {
+	// Monday 9 Sep 2024 09:00 in Kuala Lumpur
+	now := time.Date(2024, 9, 9, 1, 0, 0, 0, time.UTC)
+	due := now.Add(-30 * time.Second)
+
+	newSchedule := func(recipients ...string) entity.ReportSchedule {
+		return entity.ReportSchedule{
+			BaseEntity:     entity.NewBaseEntity(),
+			OrganizationID: uuid.New(),
+			Name:           "Weekly",
+			CronExpression: "0 9 * * 1",
+			Timezone:       "Asia/Kuala_Lumpur",
+			DatePreset:     entity.ReportDatePresetLast7Days,
+			Format:         entity.ReportFormatPDF,
+			Recipients:     recipients,
+			IsActive:       true,
+			NextRunAt:      &due,
+		}
+	}
+
+	sent := newSchedule("a@example.com", "b@example.com")
+	noRecipients := newSchedule()
+	taken := newSchedule("c@example.com")
+
+	repo := &mockScheduleRepo{
+		due:        []entity.ReportSchedule{sent, noRecipients, taken},
+		claimed:    make(map[uuid.UUID]time.Time),
+		results:    make(map[uuid.UUID]string),
+		claimFails: map[uuid.UUID]bool{taken.ID: true},
+	}
+	renderer := &mockRenderer{}
+	mailer := &mockMailer{}
+
+	job := NewReportDeliveryJob(repo, nil, renderer, mailer, zerolog.Nop())
+	result, err := job.Run(context.Background(), now)
+	if err != nil {
+		t.Fatalf("Run failed: %v", err)
+	}
+
+	if result.Due != 3 || result.Delivered != 1 || result.Failed != 1 || result.Skipped != 1 {
+		t.Errorf("unexpected result %+v", result)
+	}
+
+	if len(mailer.sent) != 1 {
+		t.Fatalf("expected 1 email batch, got %d", len(mailer.sent))
+	}
+	report := mailer.sent[0]
+	if len(report.Recipients) != 2 || report.Attachment.ContentType != "application/pdf" {
+		t.Errorf("unexpected report %+v", report)
+	}
+	if report.Attachment.Filename != "report_2024-09-02_2024-09-08.pdf" {
+		t.Errorf("unexpected attachment name %q", report.Attachment.Filename)
+	}
+
+	// The next run is advanced by a week
+	if next := repo.claimed[sent.ID]; !next.Equal(time.Date(2024, 9, 16, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("unexpected next run %v", next)
+	}
+
+	if repo.results[sent.ID] != entity.ReportScheduleRunSent {
+		t.Errorf("expected sent status, got %q", repo.results[sent.ID])
+	}
+	if repo.results[noRecipients.ID] != entity.ReportScheduleRunFailed {
+		t.Errorf("expected failed status, got %q", repo.results[noRecipients.ID])
+	}
+	if _, ok := repo.results[taken.ID]; ok {
+		t.Error("a schedule claimed by another worker should not be recorded")
+	}
+}
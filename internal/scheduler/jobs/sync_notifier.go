@@ -0,0 +1,26 @@
+package jobs
+
+import (
+	"context"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/infrastructure/events"
+)
+
+// SyncEventNotifier pushes sync job progress, such as the chunks of a
+// backfill, over the SSE event stream. It implements the sync package's
+// SyncProgressListener.
+type SyncEventNotifier struct {
+	broadcaster OrgBroadcaster
+}
+
+// NewSyncEventNotifier creates a new sync event notifier
+func NewSyncEventNotifier(broadcaster OrgBroadcaster) *SyncEventNotifier {
+	return &SyncEventNotifier{broadcaster: broadcaster}
+}
+
+// SyncJobProgress broadcasts a sync:progress event to the job's organization
+func (n *SyncEventNotifier) SyncJobProgress(ctx context.Context, job *entity.SyncJob, percent int, message string) {
+	event := events.NewSyncProgressEvent(string(job.Platform), job.ConnectedAccountID.String(), percent, message)
+	n.broadcaster.BroadcastToOrg(job.OrganizationID, event)
+}
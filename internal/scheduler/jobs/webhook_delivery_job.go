@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/rs/zerolog"
+)
+
+// WebhookDeliverer sends outbound webhook deliveries. It is implemented by the webhook service.
+type WebhookDeliverer interface {
+	DeliverDue(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error)
+}
+
+// WebhookDeliveryJob sends the webhook deliveries whose next attempt is due,
+// newly published ones included
+type WebhookDeliveryJob struct {
+	deliverer WebhookDeliverer
+	logger    zerolog.Logger
+	batchSize int
+}
+
+// NewWebhookDeliveryJob creates a new webhook delivery job
+func NewWebhookDeliveryJob(deliverer WebhookDeliverer, logger zerolog.Logger) *WebhookDeliveryJob {
+	return &WebhookDeliveryJob{
+		deliverer: deliverer,
+		logger:    logger.With().Str("job", "webhook_deliveries").Logger(),
+		batchSize: 100,
+	}
+}
+
+// WebhookDeliveryResult represents the result of a webhook delivery run
+type WebhookDeliveryResult struct {
+	Attempted int
+	Succeeded int
+	Retrying  int
+	Failed    int
+}
+
+// Run attempts due deliveries in batches until none are left
+func (j *WebhookDeliveryJob) Run(ctx context.Context, now time.Time) (*WebhookDeliveryResult, error) {
+	result := &WebhookDeliveryResult{}
+	for {
+		deliveries, err := j.deliverer.DeliverDue(ctx, now, j.batchSize)
+		j.count(result, deliveries)
+		if err != nil {
+			return result, err
+		}
+		if len(deliveries) < j.batchSize {
+			break
+		}
+	}
+
+	if result.Attempted > 0 {
+		j.logger.Info().
+			Int("attempted", result.Attempted).
+			Int("succeeded", result.Succeeded).
+			Int("retrying", result.Retrying).
+			Int("failed", result.Failed).
+			Msg("Webhook deliveries attempted")
+	}
+
+	return result, nil
+}
+
+func (j *WebhookDeliveryJob) count(result *WebhookDeliveryResult, deliveries []entity.WebhookDelivery) {
+	for _, delivery := range deliveries {
+		result.Attempted++
+		switch delivery.Status {
+		case entity.WebhookDeliverySucceeded:
+			result.Succeeded++
+		case entity.WebhookDeliveryPending:
+			result.Retrying++
+		case entity.WebhookDeliveryFailed:
+			result.Failed++
+			j.logger.Warn().
+				Str("org_id", delivery.OrganizationID.String()).
+				Str("delivery_id", delivery.ID.String()).
+				Str("event", string(delivery.EventType)).
+				Int("attempts", delivery.Attempts).
+				Str("error", delivery.Error).
+				Msg("Webhook delivery failed permanently")
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/usecase/webhook"
+	appErrors "github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/ads-aggregator/ads-aggregator/pkg/httpclient"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func TestWebhookSender_Send(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("queued"))
+	}))
+	defer server.Close()
+
+	endpoint := &entity.WebhookEndpoint{URL: server.URL, Secret: "whsec_test"}
+	delivery := &entity.WebhookDelivery{
+		BaseEntity: entity.BaseEntity{ID: uuid.New()},
+		EventType:  entity.WebhookEventSyncCompleted,
+		Payload:    []byte(`{"event":"sync.completed"}`),
+	}
+
+	resp, err := NewWebhookSender(server.Client()).Send(context.Background(), endpoint, delivery)
+	if err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if resp.StatusCode != http.StatusAccepted || resp.Body != "queued" {
+		t.Errorf("response = %d %q, want 202 \"queued\"", resp.StatusCode, resp.Body)
+	}
+	if got.Header.Get(WebhookEventHeader) != "sync.completed" || got.Header.Get(WebhookDeliveryHeader) != delivery.ID.String() {
+		t.Errorf("event = %q, delivery = %q, want the delivery's event and ID",
+			got.Header.Get(WebhookEventHeader), got.Header.Get(WebhookDeliveryHeader))
+	}
+	want := "sha256=" + SignWebhookPayload("whsec_test", got.Header.Get(WebhookTimestampHeader), body)
+	if got.Header.Get(WebhookSignatureHeader) != want {
+		t.Errorf("signature = %q, want %q", got.Header.Get(WebhookSignatureHeader), want)
+	}
+}
+
+func TestWebhookSender_SendErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "boom", http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	resp, err := NewWebhookSender(server.Client()).Send(context.Background(),
+		&entity.WebhookEndpoint{URL: server.URL},
+		&entity.WebhookDelivery{EventType: entity.WebhookEventReportReady, Payload: []byte(`{}`)})
+	if err == nil {
+		t.Fatal("Send() error = nil, want error for 500")
+	}
+	if resp == nil || resp.StatusCode != http.StatusInternalServerError {
+		t.Errorf("response = %+v, want the 500 response", resp)
+	}
+}
+
+func TestWebhookSender_RefusesNonPublicAddresses(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	_, err := NewWebhookSender(nil).Send(context.Background(),
+		&entity.WebhookEndpoint{URL: server.URL},
+		&entity.WebhookDelivery{EventType: entity.WebhookEventReportReady, Payload: []byte(`{}`)})
+	if !errors.Is(err, httpclient.ErrNonPublicAddress) {
+		t.Errorf("Send() to a loopback address error = %v, want ErrNonPublicAddress", err)
+	}
+	if called {
+		t.Error("request reached the loopback server")
+	}
+}
+
+type stubWebhookPublisher struct {
+	events []entity.WebhookEventType
+	data   []interface{}
+}
+
+func (s *stubWebhookPublisher) Publish(ctx context.Context, orgID uuid.UUID, eventType entity.WebhookEventType, data interface{}) ([]entity.WebhookDelivery, error) {
+	s.events = append(s.events, eventType)
+	s.data = append(s.data, data)
+	return nil, nil
+}
+
+func TestWebhookEventPublisher_SyncJobFailed(t *testing.T) {
+	expiredAt := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	job := &entity.SyncJob{
+		BaseEntity:         entity.BaseEntity{ID: uuid.New()},
+		OrganizationID:     uuid.New(),
+		ConnectedAccountID: uuid.New(),
+		Platform:           entity.PlatformMeta,
+		ErrorMessage:       "token expired",
+		ErrorCode:          "auth",
+	}
+
+	stub := &stubWebhookPublisher{}
+	publisher := NewWebhookEventPublisher(stub, zerolog.Nop())
+
+	publisher.SyncJobFailed(context.Background(), job, errors.New("rate limited"))
+	if len(stub.events) != 1 || stub.events[0] != entity.WebhookEventSyncFailed {
+		t.Fatalf("events = %v, want only sync.failed", stub.events)
+	}
+
+	stub.events, stub.data = nil, nil
+	publisher.SyncJobFailed(context.Background(), job, appErrors.NewTokenExpiredError("meta", "access", expiredAt))
+	if len(stub.events) != 2 || stub.events[1] != entity.WebhookEventTokenExpired {
+		t.Fatalf("events = %v, want sync.failed and token.expired", stub.events)
+	}
+	data, ok := stub.data[1].(webhook.TokenExpiredData)
+	if !ok || data.ConnectedAccountID != job.ConnectedAccountID.String() || data.ExpiredAt == nil || !data.ExpiredAt.Equal(expiredAt) {
+		t.Errorf("token.expired data = %+v, want the account and expiry", stub.data[1])
+	}
+	if sync := stub.data[0].(webhook.SyncData); sync.Error != "token expired" || sync.ErrorType != "auth" {
+		t.Errorf("sync.failed data = %+v, want the job's error", sync)
+	}
+}
+
+type stubWebhookDeliverer struct {
+	batches [][]entity.WebhookDelivery
+	calls   int
+}
+
+func (s *stubWebhookDeliverer) DeliverDue(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	if s.calls >= len(s.batches) {
+		return nil, nil
+	}
+	batch := s.batches[s.calls]
+	s.calls++
+	return batch, nil
+}
+
+func TestWebhookDeliveryJob_Run(t *testing.T) {
+	full := make([]entity.WebhookDelivery, 2)
+	for i := range full {
+		full[i].Status = entity.WebhookDeliverySucceeded
+	}
+	deliverer := &stubWebhookDeliverer{batches: [][]entity.WebhookDelivery{
+		full,
+		{{Status: entity.WebhookDeliveryPending}, {Status: entity.WebhookDeliveryFailed}},
+		{{Status: entity.WebhookDeliveryPending}},
+	}}
+	job := NewWebhookDeliveryJob(deliverer, zerolog.Nop())
+	job.batchSize = 2
+
+	result, err := job.Run(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if deliverer.calls != 3 {
+		t.Errorf("DeliverDue called %d times, want 3", deliverer.calls)
+	}
+	if result.Attempted != 5 || result.Succeeded != 2 || result.Retrying != 2 || result.Failed != 1 {
+		t.Errorf("result = %+v, want 5 attempted, 2 succeeded, 2 retrying, 1 failed", result)
+	}
+}
@@ -0,0 +1,130 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/usecase/webhook"
+	appErrors "github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// WebhookPublisher delivers events to organization webhook endpoints. It is
+// implemented by the webhook service.
+type WebhookPublisher interface {
+	Publish(ctx context.Context, orgID uuid.UUID, eventType entity.WebhookEventType, data interface{}) ([]entity.WebhookDelivery, error)
+}
+
+// WebhookEventPublisher publishes sync, token, alert and report events to
+// organization webhook endpoints. It implements the sync package's
+// SyncJobListener, the oauth package's TokenExpiryListener and the analytics
+// package's AlertListener and ReportJobListener.
+type WebhookEventPublisher struct {
+	publisher WebhookPublisher
+	logger    zerolog.Logger
+}
+
+// NewWebhookEventPublisher creates a new webhook event publisher
+func NewWebhookEventPublisher(publisher WebhookPublisher, logger zerolog.Logger) *WebhookEventPublisher {
+	return &WebhookEventPublisher{
+		publisher: publisher,
+		logger:    logger.With().Str("component", "webhook_publisher").Logger(),
+	}
+}
+
+// SyncJobCompleted publishes a sync.completed event
+func (p *WebhookEventPublisher) SyncJobCompleted(ctx context.Context, job *entity.SyncJob) {
+	p.publish(ctx, job.OrganizationID, entity.WebhookEventSyncCompleted, syncData(job))
+}
+
+// SyncJobFailed publishes a sync.failed event, and a token.expired event when
+// the job failed because the account's token expired
+func (p *WebhookEventPublisher) SyncJobFailed(ctx context.Context, job *entity.SyncJob, err error) {
+	p.publish(ctx, job.OrganizationID, entity.WebhookEventSyncFailed, syncData(job))
+
+	if appErrors.IsTokenExpired(err) {
+		data := webhook.TokenExpiredData{
+			ConnectedAccountID: job.ConnectedAccountID.String(),
+			Platform:           string(job.Platform),
+		}
+		var tokenErr *appErrors.TokenError
+		if errors.As(err, &tokenErr) && !tokenErr.ExpiredAt.IsZero() {
+			data.ExpiredAt = &tokenErr.ExpiredAt
+		}
+		p.publish(ctx, job.OrganizationID, entity.WebhookEventTokenExpired, data)
+	}
+}
+
+// TokenExpired publishes a token.expired event
+func (p *WebhookEventPublisher) TokenExpired(ctx context.Context, account *entity.ConnectedAccount) {
+	p.publish(ctx, account.OrganizationID, entity.WebhookEventTokenExpired, webhook.TokenExpiredData{
+		ConnectedAccountID: account.ID.String(),
+		Platform:           string(account.Platform),
+		AccountName:        account.PlatformAccountName,
+		ExpiredAt:          account.TokenExpiresAt,
+	})
+}
+
+// AlertsTriggered publishes an alert.triggered event with the same data as alert rule webhooks
+func (p *WebhookEventPublisher) AlertsTriggered(ctx context.Context, rule *entity.AlertRule, alerts []entity.AlertEvent) {
+	p.publish(ctx, rule.OrganizationID, entity.WebhookEventAlertTriggered, alertTriggeredData(rule, alerts))
+}
+
+// ReportJobCompleted publishes a report.ready event
+func (p *WebhookEventPublisher) ReportJobCompleted(ctx context.Context, job *entity.ReportJob) {
+	p.publish(ctx, job.OrganizationID, entity.WebhookEventReportReady, webhook.ReportReadyData{
+		ReportID:    job.ID.String(),
+		Format:      string(job.Format),
+		FileName:    job.FileName,
+		SizeBytes:   job.SizeBytes,
+		StartDate:   job.DateRangeStart.Format("2006-01-02"),
+		EndDate:     job.DateRangeEnd.Format("2006-01-02"),
+		DownloadURL: fmt.Sprintf("/api/v1/analytics/reports/%s/download", job.ID),
+		ExpiresAt:   job.ExpiresAt,
+	})
+}
+
+// publish logs failures instead of returning them so that webhooks never fail the caller
+func (p *WebhookEventPublisher) publish(ctx context.Context, orgID uuid.UUID, eventType entity.WebhookEventType, data interface{}) {
+	deliveries, err := p.publisher.Publish(ctx, orgID, eventType, data)
+	if err != nil {
+		p.logger.Error().Err(err).
+			Str("org_id", orgID.String()).
+			Str("event", string(eventType)).
+			Msg("Failed to publish webhook event")
+	}
+	for _, delivery := range deliveries {
+		if delivery.Status == entity.WebhookDeliveryPending {
+			p.logger.Warn().
+				Str("org_id", orgID.String()).
+				Str("event", string(eventType)).
+				Str("delivery_id", delivery.ID.String()).
+				Str("error", delivery.Error).
+				Msg("Webhook delivery failed, will retry")
+		}
+	}
+}
+
+func syncData(job *entity.SyncJob) webhook.SyncData {
+	finishedAt := time.Now().UTC()
+	if job.CompletedAt != nil {
+		finishedAt = *job.CompletedAt
+	}
+	return webhook.SyncData{
+		SyncJobID:          job.ID.String(),
+		ConnectedAccountID: job.ConnectedAccountID.String(),
+		Platform:           string(job.Platform),
+		SyncType:           string(job.SyncType),
+		SyncScope:          string(job.SyncScope),
+		RecordsProcessed:   job.RecordsProcessed,
+		RecordsFailed:      job.RecordsFailed,
+		StartedAt:          job.StartedAt,
+		FinishedAt:         finishedAt,
+		Error:              job.ErrorMessage,
+		ErrorType:          job.ErrorCode,
+	}
+}
@@ -0,0 +1,86 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/usecase/webhook"
+	"github.com/ads-aggregator/ads-aggregator/pkg/httpclient"
+)
+
+// webhookResponseLimit is the number of response bytes read from an endpoint
+const webhookResponseLimit = 4096
+
+// WebhookSender posts outbound webhook deliveries to organization endpoints,
+// signed like alert rule webhooks. It implements webhook.Sender.
+type WebhookSender struct {
+	client *http.Client
+}
+
+// NewWebhookSender creates a new webhook sender. Without a client it uses one
+// that only connects to public addresses, since endpoint URLs come from users.
+func NewWebhookSender(client *http.Client) *WebhookSender {
+	if client == nil {
+		client = httpclient.NewPublicClient(alertWebhookTimeout)
+	}
+	return &WebhookSender{client: client}
+}
+
+// Send posts the delivery's payload to the endpoint. Any response other than 2xx is an error.
+func (s *WebhookSender) Send(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) (*webhook.Response, error) {
+	headers := map[string]string{
+		WebhookDeliveryHeader: delivery.ID.String(),
+	}
+	return postWebhook(ctx, s.client, endpoint.URL, endpoint.Secret, string(delivery.EventType), delivery.Payload, headers)
+}
+
+// postWebhook posts a JSON body with the event, timestamp and, when there is a
+// secret, signature headers. It returns the response whenever one was received
+// and an error for transport failures and responses other than 2xx.
+func postWebhook(
+	ctx context.Context,
+	client *http.Client,
+	url, secret, event string,
+	body []byte,
+	headers map[string]string,
+) (*webhook.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "AdsAnalytic-Webhook/1.0")
+	req.Header.Set(WebhookEventHeader, event)
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	if secret != "" {
+		req.Header.Set(WebhookSignatureHeader, "sha256="+SignWebhookPayload(secret, timestamp, body))
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLimit))
+	result := &webhook.Response{
+		StatusCode: resp.StatusCode,
+		Body:       string(respBody),
+		Duration:   time.Since(start),
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return result, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return result, nil
+}
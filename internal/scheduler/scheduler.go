@@ -8,6 +8,7 @@ import (
 	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
 	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
 	domainService "github.com/ads-aggregator/ads-aggregator/internal/domain/service"
+	"github.com/ads-aggregator/ads-aggregator/internal/scheduler/jobs"
 	"github.com/google/uuid"
 	"github.com/robfig/cron/v3"
 	"github.com/rs/zerolog"
@@ -19,6 +20,12 @@ type Scheduler struct {
 	syncService      SyncService
 	tokenManager     TokenManager
 	connectedAccRepo repository.ConnectedAccountRepository
+	reportDelivery   ReportDeliveryRunner
+	exchangeRates    ExchangeRateRunner
+	budgetAlerts     BudgetAlertRunner
+	anomalies        AnomalyDetectionRunner
+	alertRules       AlertRuleRunner
+	webhooks         WebhookDeliveryRunner
 	logger           zerolog.Logger
 	mu               sync.RWMutex
 	running          bool
@@ -38,12 +45,48 @@ type TokenManager interface {
 	RefreshAllExpiring(ctx context.Context) (int, error)
 }
 
+// ReportDeliveryRunner interface for scheduled report delivery
+type ReportDeliveryRunner interface {
+	Run(ctx context.Context, now time.Time) (*jobs.ReportDeliveryResult, error)
+}
+
+// ExchangeRateRunner interface for importing exchange rates
+type ExchangeRateRunner interface {
+	Run(ctx context.Context) (*jobs.ExchangeRateResult, error)
+}
+
+// BudgetAlertRunner interface for checking organization budgets
+type BudgetAlertRunner interface {
+	Run(ctx context.Context, now time.Time) (*jobs.BudgetAlertResult, error)
+}
+
+// AnomalyDetectionRunner interface for detecting metric anomalies
+type AnomalyDetectionRunner interface {
+	Run(ctx context.Context, now time.Time) (*jobs.AnomalyDetectionResult, error)
+}
+
+// AlertRuleRunner interface for evaluating user-defined alert rules
+type AlertRuleRunner interface {
+	Run(ctx context.Context, now time.Time) (*jobs.AlertRuleResult, error)
+}
+
+// WebhookDeliveryRunner interface for retrying outbound webhook deliveries
+type WebhookDeliveryRunner interface {
+	Run(ctx context.Context, now time.Time) (*jobs.WebhookDeliveryResult, error)
+}
+
 // Config holds scheduler configuration
 type Config struct {
 	Enabled                    bool
 	SyncCronSchedule           string // e.g., "0 * * * *" for every hour
 	TokenRefreshCronSchedule   string // e.g., "*/30 * * * *" for every 30 minutes
 	MetricsAggregationSchedule string // e.g., "0 */6 * * *" for every 6 hours
+	ReportDeliverySchedule     string // e.g., "* * * * *" to check report schedules every minute
+	ExchangeRateSchedule       string // e.g., "0 17 * * *" after the ECB publishes its daily rates
+	BudgetAlertSchedule        string // e.g., "15 * * * *" to check budgets every hour
+	AnomalyDetectionSchedule   string // e.g., "30 6 * * *" once yesterday's metrics are complete
+	AlertRuleSchedule          string // e.g., "45 * * * *" to evaluate alert rules every hour
+	WebhookDeliverySchedule    string // e.g., "* * * * *" to send pending webhook deliveries every minute
 	ConcurrentSyncs            int
 }
 
@@ -54,6 +97,12 @@ func DefaultConfig() *Config {
 		SyncCronSchedule:           "0 * * * *",    // Every hour
 		TokenRefreshCronSchedule:   "*/30 * * * *", // Every 30 minutes
 		MetricsAggregationSchedule: "0 */6 * * *",  // Every 6 hours
+		ReportDeliverySchedule:     "* * * * *",    // Every minute
+		ExchangeRateSchedule:       "0 17 * * *",   // Daily at 17:00
+		BudgetAlertSchedule:        "15 * * * *",   // Every hour
+		AnomalyDetectionSchedule:   "30 6 * * *",   // Daily at 06:30
+		AlertRuleSchedule:          "45 * * * *",   // Every hour
+		WebhookDeliverySchedule:    "* * * * *",    // Every minute
 		ConcurrentSyncs:            3,
 	}
 }
@@ -66,7 +115,7 @@ func NewScheduler(
 	logger zerolog.Logger,
 ) *Scheduler {
 	return &Scheduler{
-		cron:             cron.New(cron.WithSeconds()),
+		cron:             cron.New(),
 		syncService:      syncService,
 		tokenManager:     tokenManager,
 		connectedAccRepo: connectedAccRepo,
@@ -75,6 +124,48 @@ func NewScheduler(
 	}
 }
 
+// SetReportDeliveryJob sets the job that emails scheduled reports
+func (s *Scheduler) SetReportDeliveryJob(job ReportDeliveryRunner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reportDelivery = job
+}
+
+// SetExchangeRateJob sets the job that imports exchange rates
+func (s *Scheduler) SetExchangeRateJob(job ExchangeRateRunner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.exchangeRates = job
+}
+
+// SetBudgetAlertJob sets the job that raises budget alerts
+func (s *Scheduler) SetBudgetAlertJob(job BudgetAlertRunner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.budgetAlerts = job
+}
+
+// SetAnomalyDetectionJob sets the job that sweeps campaign metrics for anomalies
+func (s *Scheduler) SetAnomalyDetectionJob(job AnomalyDetectionRunner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.anomalies = job
+}
+
+// SetAlertRuleJob sets the job that evaluates alert rules
+func (s *Scheduler) SetAlertRuleJob(job AlertRuleRunner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.alertRules = job
+}
+
+// SetWebhookDeliveryJob sets the job that sends webhook deliveries
+func (s *Scheduler) SetWebhookDeliveryJob(job WebhookDeliveryRunner) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.webhooks = job
+}
+
 // Start starts the scheduler with the given configuration
 func (s *Scheduler) Start(config *Config) error {
 	s.mu.Lock()
@@ -126,6 +217,72 @@ func (s *Scheduler) Start(config *Config) error {
 		s.logger.Info().Str("schedule", config.MetricsAggregationSchedule).Msg("Scheduled metrics aggregation job")
 	}
 
+	// Schedule report delivery job
+	if config.ReportDeliverySchedule != "" && s.reportDelivery != nil {
+		id, err := s.cron.AddFunc(config.ReportDeliverySchedule, s.runReportDelivery)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Failed to schedule report delivery job")
+			return err
+		}
+		s.jobs["report_delivery"] = id
+		s.logger.Info().Str("schedule", config.ReportDeliverySchedule).Msg("Scheduled report delivery job")
+	}
+
+	// Schedule exchange rate import job
+	if config.ExchangeRateSchedule != "" && s.exchangeRates != nil {
+		id, err := s.cron.AddFunc(config.ExchangeRateSchedule, s.runExchangeRateImport)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Failed to schedule exchange rate job")
+			return err
+		}
+		s.jobs["exchange_rates"] = id
+		s.logger.Info().Str("schedule", config.ExchangeRateSchedule).Msg("Scheduled exchange rate job")
+	}
+
+	// Schedule budget alert job
+	if config.BudgetAlertSchedule != "" && s.budgetAlerts != nil {
+		id, err := s.cron.AddFunc(config.BudgetAlertSchedule, s.runBudgetAlerts)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Failed to schedule budget alert job")
+			return err
+		}
+		s.jobs["budget_alerts"] = id
+		s.logger.Info().Str("schedule", config.BudgetAlertSchedule).Msg("Scheduled budget alert job")
+	}
+
+	// Schedule anomaly detection job
+	if config.AnomalyDetectionSchedule != "" && s.anomalies != nil {
+		id, err := s.cron.AddFunc(config.AnomalyDetectionSchedule, s.runAnomalyDetection)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Failed to schedule anomaly detection job")
+			return err
+		}
+		s.jobs["anomaly_detection"] = id
+		s.logger.Info().Str("schedule", config.AnomalyDetectionSchedule).Msg("Scheduled anomaly detection job")
+	}
+
+	// Schedule alert rule job
+	if config.AlertRuleSchedule != "" && s.alertRules != nil {
+		id, err := s.cron.AddFunc(config.AlertRuleSchedule, s.runAlertRules)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Failed to schedule alert rule job")
+			return err
+		}
+		s.jobs["alert_rules"] = id
+		s.logger.Info().Str("schedule", config.AlertRuleSchedule).Msg("Scheduled alert rule job")
+	}
+
+	// Schedule webhook delivery job
+	if config.WebhookDeliverySchedule != "" && s.webhooks != nil {
+		id, err := s.cron.AddFunc(config.WebhookDeliverySchedule, s.runWebhookDeliveries)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Failed to schedule webhook delivery job")
+			return err
+		}
+		s.jobs["webhook_deliveries"] = id
+		s.logger.Info().Str("schedule", config.WebhookDeliverySchedule).Msg("Scheduled webhook delivery job")
+	}
+
 	s.cron.Start()
 	s.running = true
 	s.logger.Info().Msg("Scheduler started")
@@ -176,6 +333,36 @@ func (s *Scheduler) RunNow(jobName string) error {
 		go s.runTokenRefresh()
 	case "metrics_aggregation":
 		go s.runMetricsAggregation()
+	case "report_delivery":
+		if s.reportDelivery == nil {
+			return ErrUnknownJob
+		}
+		go s.runReportDelivery()
+	case "exchange_rates":
+		if s.exchangeRates == nil {
+			return ErrUnknownJob
+		}
+		go s.runExchangeRateImport()
+	case "budget_alerts":
+		if s.budgetAlerts == nil {
+			return ErrUnknownJob
+		}
+		go s.runBudgetAlerts()
+	case "anomaly_detection":
+		if s.anomalies == nil {
+			return ErrUnknownJob
+		}
+		go s.runAnomalyDetection()
+	case "alert_rules":
+		if s.alertRules == nil {
+			return ErrUnknownJob
+		}
+		go s.runAlertRules()
+	case "webhook_deliveries":
+		if s.webhooks == nil {
+			return ErrUnknownJob
+		}
+		go s.runWebhookDeliveries()
 	default:
 		return ErrUnknownJob
 	}
@@ -286,6 +473,60 @@ func (s *Scheduler) runMetricsAggregation() {
 		Msg("Scheduled metrics aggregation completed")
 }
 
+func (s *Scheduler) runReportDelivery() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+	defer cancel()
+
+	if _, err := s.reportDelivery.Run(ctx, time.Now()); err != nil {
+		s.logger.Error().Err(err).Msg("Scheduled report delivery failed")
+	}
+}
+
+func (s *Scheduler) runExchangeRateImport() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	if _, err := s.exchangeRates.Run(ctx); err != nil {
+		s.logger.Error().Err(err).Msg("Scheduled exchange rate import failed")
+	}
+}
+
+func (s *Scheduler) runBudgetAlerts() {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Minute)
+	defer cancel()
+
+	if _, err := s.budgetAlerts.Run(ctx, time.Now()); err != nil {
+		s.logger.Error().Err(err).Msg("Scheduled budget check failed")
+	}
+}
+
+func (s *Scheduler) runAnomalyDetection() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	if _, err := s.anomalies.Run(ctx, time.Now()); err != nil {
+		s.logger.Error().Err(err).Msg("Scheduled anomaly detection failed")
+	}
+}
+
+func (s *Scheduler) runAlertRules() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	if _, err := s.alertRules.Run(ctx, time.Now()); err != nil {
+		s.logger.Error().Err(err).Msg("Scheduled alert rule evaluation failed")
+	}
+}
+
+func (s *Scheduler) runWebhookDeliveries() {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	if _, err := s.webhooks.Run(ctx, time.Now()); err != nil {
+		s.logger.Error().Err(err).Msg("Scheduled webhook delivery failed")
+	}
+}
+
 // JobStatus represents the status of a scheduled job
 type JobStatus struct {
 	Name      string    `json:"name"`
@@ -346,6 +346,13 @@ func NewAggregationService(
 	}
 }
 
+// SetCurrencyConverter sets the converter used for currency conversion
+func (s *AggregationService) SetCurrencyConverter(converter *currency.Converter) {
+	if converter != nil {
+		s.currencyConverter = converter
+	}
+}
+
 // ============================================================================
 // Core Aggregation Methods
 // ============================================================================
@@ -411,11 +418,11 @@ func (s *AggregationService) GetUnifiedMetrics(ctx context.Context, req Aggregat
 				CampaignID:   campaign.ID,
 				CampaignName: campaign.PlatformCampaignName,
 				AdAccountID:  campaign.AdAccountID,
-				Spend:        s.convertCurrency(m.Spend, m.Currency, req.TargetCurrency),
+				Spend:        s.convertCurrency(m.Spend, m.Currency, req.TargetCurrency, m.MetricDate),
 				Impressions:  m.Impressions,
 				Clicks:       m.Clicks,
 				Conversions:  m.Conversions,
-				Revenue:      s.convertCurrency(m.ConversionValue, m.Currency, req.TargetCurrency),
+				Revenue:      s.convertCurrency(m.ConversionValue, m.Currency, req.TargetCurrency, m.MetricDate),
 				Currency:     req.TargetCurrency,
 				LastSyncedAt: m.LastSyncedAt,
 			}
@@ -713,14 +720,15 @@ func (s *AggregationService) ComparePlatforms(ctx context.Context, req Aggregati
 // Helper Methods
 // ============================================================================
 
-func (s *AggregationService) convertCurrency(amount decimal.Decimal, from, to string) decimal.Decimal {
+// convertCurrency converts amount at the exchange rate of date
+func (s *AggregationService) convertCurrency(amount decimal.Decimal, from, to string, date time.Time) decimal.Decimal {
 	if from == to || from == "" || to == "" {
 		return amount
 	}
 	if amount.IsZero() {
 		return amount
 	}
-	converted, err := s.currencyConverter.Convert(amount, from, to)
+	converted, err := s.currencyConverter.ConvertAt(amount, from, to, date)
 	if err != nil {
 		return amount
 	}
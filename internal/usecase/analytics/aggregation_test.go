@@ -1,8 +1,13 @@
 package analytics
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/pkg/currency"
+	"github.com/google/uuid"
 	"github.com/shopspring/decimal"
 )
 
@@ -481,6 +486,59 @@ func TestUnifiedMetrics_CalculateDerived_SmallNumbers(t *testing.T) {
 	}
 }
 
+// ============================================================================
+// Currency Conversion Tests
+// ============================================================================
+
+func TestGetUnifiedMetrics_ConvertsAtMetricDate(t *testing.T) {
+	metricsRepo := newMockMetricsRepo()
+	campaignRepo := newMockCampaignRepo()
+
+	orgID := uuid.New()
+	campaign := createTestCampaign(orgID, entity.PlatformMeta)
+	campaignRepo.campaigns = []entity.Campaign{campaign}
+
+	january := time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC)
+	july := time.Date(2024, 7, 15, 0, 0, 0, 0, time.UTC)
+	jan := createTestMetric(campaign.ID, orgID, entity.PlatformMeta, january, 100, 0, 1000, 10, 0)
+	jul := createTestMetric(campaign.ID, orgID, entity.PlatformMeta, july, 100, 0, 1000, 10, 0)
+	jan.Currency, jul.Currency = "USD", "USD"
+	metricsRepo.campaignMetrics[campaign.ID] = []entity.CampaignMetricsDaily{jan, jul}
+
+	provider := currency.NewDatedRateProvider("EUR", nil, nil)
+	provider.SetRates([]currency.DatedRate{
+		{Date: time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC), Base: "EUR", Quote: "USD", Rate: decimal.NewFromInt(1)},
+		{Date: time.Date(2024, 1, 12, 0, 0, 0, 0, time.UTC), Base: "EUR", Quote: "MYR", Rate: decimal.NewFromInt(4)},
+		{Date: time.Date(2024, 7, 12, 0, 0, 0, 0, time.UTC), Base: "EUR", Quote: "USD", Rate: decimal.NewFromInt(1)},
+		{Date: time.Date(2024, 7, 12, 0, 0, 0, 0, time.UTC), Base: "EUR", Quote: "MYR", Rate: decimal.NewFromInt(5)},
+	})
+
+	service := NewAggregationService(metricsRepo, campaignRepo, nil, nil)
+	service.SetCurrencyConverter(currency.NewConverter(provider))
+
+	metrics, err := service.GetUnifiedMetrics(context.Background(), AggregationRequest{
+		OrganizationID: orgID,
+		DateRange:      entity.DateRange{StartDate: january, EndDate: july},
+		TargetCurrency: "MYR",
+	})
+	if err != nil {
+		t.Fatalf("GetUnifiedMetrics() error = %v", err)
+	}
+
+	if len(metrics) != 2 {
+		t.Fatalf("len(metrics) = %d, want 2", len(metrics))
+	}
+	for _, m := range metrics {
+		want := decimal.NewFromInt(400)
+		if m.Date.Equal(july) {
+			want = decimal.NewFromInt(500)
+		}
+		if !m.Spend.Equal(want) {
+			t.Errorf("Spend on %s = %v, want %v", m.Date.Format("2006-01-02"), m.Spend, want)
+		}
+	}
+}
+
 // ============================================================================
 // Helper Functions
 // ============================================================================
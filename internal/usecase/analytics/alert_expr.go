@@ -0,0 +1,408 @@
+package analytics
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/shopspring/decimal"
+)
+
+// Alert rule language
+//
+// A rule compares metrics of entity.CalculatedMetrics for one campaign and day
+// with numbers, combined with "and", "or", "not" and parentheses, optionally
+// followed by "for N days" to require the condition on N consecutive days:
+//
+//	roas < 1.5 for 3 days
+//	spend > 500
+//	(cpa > 40 or ctr < 0.5) and conversions >= 10
+//
+// Fields are spend, revenue, impressions, clicks, conversions, reach, likes,
+// comments, shares, roas, cpa, ctr, cpc, cpm and conversion_rate; the "total_"
+// prefix of the JSON names is optional. A ratio with a zero denominator (e.g.
+// ROAS without spend) makes its comparison false.
+
+// maxAlertRuleDays bounds the window of "for N days"
+const maxAlertRuleDays = 31
+
+// alertRuleFields maps field names to their value in calculated metrics
+var alertRuleFields = map[string]func(m *entity.CalculatedMetrics) (float64, bool){
+	"spend":           func(m *entity.CalculatedMetrics) (float64, bool) { return m.TotalSpend.InexactFloat64(), true },
+	"revenue":         func(m *entity.CalculatedMetrics) (float64, bool) { return m.TotalRevenue.InexactFloat64(), true },
+	"impressions":     func(m *entity.CalculatedMetrics) (float64, bool) { return float64(m.TotalImpressions), true },
+	"clicks":          func(m *entity.CalculatedMetrics) (float64, bool) { return float64(m.TotalClicks), true },
+	"conversions":     func(m *entity.CalculatedMetrics) (float64, bool) { return float64(m.TotalConversions), true },
+	"reach":           func(m *entity.CalculatedMetrics) (float64, bool) { return float64(m.TotalReach), true },
+	"likes":           func(m *entity.CalculatedMetrics) (float64, bool) { return float64(m.TotalLikes), true },
+	"comments":        func(m *entity.CalculatedMetrics) (float64, bool) { return float64(m.TotalComments), true },
+	"shares":          func(m *entity.CalculatedMetrics) (float64, bool) { return float64(m.TotalShares), true },
+	"roas":            func(m *entity.CalculatedMetrics) (float64, bool) { return floatPtrValue(m.ROAS) },
+	"cpa":             func(m *entity.CalculatedMetrics) (float64, bool) { return decimalPtrValue(m.CPA) },
+	"ctr":             func(m *entity.CalculatedMetrics) (float64, bool) { return floatPtrValue(m.CTR) },
+	"cpc":             func(m *entity.CalculatedMetrics) (float64, bool) { return decimalPtrValue(m.CPC) },
+	"cpm":             func(m *entity.CalculatedMetrics) (float64, bool) { return decimalPtrValue(m.CPM) },
+	"conversion_rate": func(m *entity.CalculatedMetrics) (float64, bool) { return floatPtrValue(m.ConversionRate) },
+}
+
+// AlertRuleExpression is a parsed alert rule
+type AlertRuleExpression struct {
+	root alertNode
+
+	// Days is the number of consecutive days the condition must hold, at least 1
+	Days int
+}
+
+// Matches evaluates the condition against one day of metrics
+func (e *AlertRuleExpression) Matches(m *entity.CalculatedMetrics) bool {
+	return e.root.eval(m)
+}
+
+// Fields returns the metrics referenced by the rule, sorted
+func (e *AlertRuleExpression) Fields() []string {
+	seen := make(map[string]bool)
+	e.root.fields(seen)
+
+	fields := make([]string, 0, len(seen))
+	for field := range seen {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Values returns the referenced metrics that are defined for a day
+func (e *AlertRuleExpression) Values(m *entity.CalculatedMetrics) map[string]float64 {
+	values := make(map[string]float64)
+	for _, field := range e.Fields() {
+		if value, ok := alertRuleFields[field](m); ok {
+			values[field] = value
+		}
+	}
+	return values
+}
+
+// ParseAlertRule parses an expression of the alert rule language
+func ParseAlertRule(src string) (*AlertRuleExpression, error) {
+	tokens, err := lexAlertRule(src)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &alertRuleParser{tokens: tokens}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	expr := &AlertRuleExpression{root: root, Days: 1}
+	if p.peek().isKeyword("for") {
+		p.next()
+		days, err := p.expect(alertTokenNumber, "number of days")
+		if err != nil {
+			return nil, err
+		}
+		n, err := strconv.Atoi(days.text)
+		if err != nil || n < 1 || n > maxAlertRuleDays {
+			return nil, fmt.Errorf("number of days must be a whole number between 1 and %d, got %q", maxAlertRuleDays, days.text)
+		}
+		unit := p.next()
+		if !unit.isKeyword("day") && !unit.isKeyword("days") {
+			return nil, fmt.Errorf("expected \"days\" at position %d", unit.pos)
+		}
+		expr.Days = n
+	}
+
+	if tok := p.peek(); tok.kind != alertTokenEOF {
+		return nil, fmt.Errorf("unexpected %q at position %d", tok.text, tok.pos)
+	}
+	return expr, nil
+}
+
+// alertNode is a node of a parsed rule
+type alertNode interface {
+	eval(m *entity.CalculatedMetrics) bool
+	fields(seen map[string]bool)
+}
+
+type alertComparison struct {
+	field string
+	op    string
+	value float64
+}
+
+func (c *alertComparison) eval(m *entity.CalculatedMetrics) bool {
+	actual, ok := alertRuleFields[c.field](m)
+	if !ok {
+		return false
+	}
+	switch c.op {
+	case "<":
+		return actual < c.value
+	case "<=":
+		return actual <= c.value
+	case ">":
+		return actual > c.value
+	case ">=":
+		return actual >= c.value
+	case "==":
+		return actual == c.value
+	case "!=":
+		return actual != c.value
+	default:
+		return false
+	}
+}
+
+func (c *alertComparison) fields(seen map[string]bool) {
+	seen[c.field] = true
+}
+
+type alertLogical struct {
+	and         bool
+	left, right alertNode
+}
+
+func (l *alertLogical) eval(m *entity.CalculatedMetrics) bool {
+	if l.and {
+		return l.left.eval(m) && l.right.eval(m)
+	}
+	return l.left.eval(m) || l.right.eval(m)
+}
+
+func (l *alertLogical) fields(seen map[string]bool) {
+	l.left.fields(seen)
+	l.right.fields(seen)
+}
+
+type alertNot struct {
+	operand alertNode
+}
+
+func (n *alertNot) eval(m *entity.CalculatedMetrics) bool {
+	return !n.operand.eval(m)
+}
+
+func (n *alertNot) fields(seen map[string]bool) {
+	n.operand.fields(seen)
+}
+
+// alertRuleParser is a recursive descent parser where "and" binds tighter than "or":
+//
+//	or         = and { "or" and }
+//	and        = unary { "and" unary }
+//	unary      = "not" unary | "(" or ")" | comparison
+//	comparison = field operator number
+type alertRuleParser struct {
+	tokens []alertToken
+	pos    int
+}
+
+func (p *alertRuleParser) peek() alertToken {
+	return p.tokens[p.pos]
+}
+
+func (p *alertRuleParser) next() alertToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != alertTokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *alertRuleParser) expect(kind alertTokenKind, what string) (alertToken, error) {
+	tok := p.next()
+	if tok.kind != kind {
+		if tok.kind == alertTokenEOF {
+			return tok, fmt.Errorf("expected %s at end of rule", what)
+		}
+		return tok, fmt.Errorf("expected %s at position %d, got %q", what, tok.pos, tok.text)
+	}
+	return tok, nil
+}
+
+func (p *alertRuleParser) parseOr() (alertNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &alertLogical{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *alertRuleParser) parseAnd() (alertNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().isKeyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &alertLogical{and: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *alertRuleParser) parseUnary() (alertNode, error) {
+	tok := p.peek()
+	switch {
+	case tok.isKeyword("not"):
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &alertNot{operand: operand}, nil
+	case tok.kind == alertTokenLParen:
+		p.next()
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := p.expect(alertTokenRParen, "\")\""); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	default:
+		return p.parseComparison()
+	}
+}
+
+func (p *alertRuleParser) parseComparison() (alertNode, error) {
+	ident, err := p.expect(alertTokenIdent, "metric name")
+	if err != nil {
+		return nil, err
+	}
+	field := strings.TrimPrefix(ident.text, "total_")
+	if _, ok := alertRuleFields[field]; !ok {
+		return nil, fmt.Errorf("unknown metric %q at position %d", ident.text, ident.pos)
+	}
+
+	op, err := p.expect(alertTokenOperator, "comparison operator")
+	if err != nil {
+		return nil, err
+	}
+
+	number, err := p.expect(alertTokenNumber, "number")
+	if err != nil {
+		return nil, err
+	}
+	value, err := strconv.ParseFloat(number.text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid number %q at position %d", number.text, number.pos)
+	}
+
+	return &alertComparison{field: field, op: op.text, value: value}, nil
+}
+
+type alertTokenKind int
+
+const (
+	alertTokenEOF alertTokenKind = iota
+	alertTokenIdent
+	alertTokenNumber
+	alertTokenOperator
+	alertTokenLParen
+	alertTokenRParen
+)
+
+type alertToken struct {
+	kind alertTokenKind
+	text string
+	pos  int // 1-based position in the rule
+}
+
+func (t alertToken) isKeyword(keyword string) bool {
+	return t.kind == alertTokenIdent && t.text == keyword
+}
+
+// lexAlertRule splits a rule into tokens. Identifiers are case-insensitive,
+// "&&" and "||" are accepted for "and" and "or", and "=" for "==".
+func lexAlertRule(src string) ([]alertToken, error) {
+	var tokens []alertToken
+	runes := []rune(src)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsLetter(r) || r == '_':
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, alertToken{kind: alertTokenIdent, text: strings.ToLower(string(runes[start:i])), pos: start + 1})
+		case unicode.IsDigit(r) || r == '.' || (r == '-' && i+1 < len(runes) && (unicode.IsDigit(runes[i+1]) || runes[i+1] == '.')):
+			i++
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.') {
+				i++
+			}
+			tokens = append(tokens, alertToken{kind: alertTokenNumber, text: string(runes[start:i]), pos: start + 1})
+		case r == '(':
+			i++
+			tokens = append(tokens, alertToken{kind: alertTokenLParen, text: "(", pos: start + 1})
+		case r == ')':
+			i++
+			tokens = append(tokens, alertToken{kind: alertTokenRParen, text: ")", pos: start + 1})
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, fmt.Errorf("unexpected %q at position %d", string(r), start+1)
+			}
+			i += 2
+			keyword := "and"
+			if r == '|' {
+				keyword = "or"
+			}
+			tokens = append(tokens, alertToken{kind: alertTokenIdent, text: keyword, pos: start + 1})
+		case r == '<' || r == '>' || r == '=' || r == '!':
+			i++
+			op := string(r)
+			if i < len(runes) && runes[i] == '=' {
+				op += "="
+				i++
+			}
+			switch op {
+			case "=":
+				op = "=="
+			case "!":
+				return nil, fmt.Errorf("unexpected \"!\" at position %d", start+1)
+			}
+			tokens = append(tokens, alertToken{kind: alertTokenOperator, text: op, pos: start + 1})
+		default:
+			return nil, fmt.Errorf("unexpected %q at position %d", string(r), start+1)
+		}
+	}
+
+	if len(tokens) == 0 {
+		return nil, fmt.Errorf("rule is empty")
+	}
+	return append(tokens, alertToken{kind: alertTokenEOF, pos: len(runes) + 1}), nil
+}
+
+func floatPtrValue(v *float64) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return *v, true
+}
+
+func decimalPtrValue(v *decimal.Decimal) (float64, bool) {
+	if v == nil {
+		return 0, false
+	}
+	return v.InexactFloat64(), true
+}
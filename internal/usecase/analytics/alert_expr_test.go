@@ -0,0 +1,131 @@
+package analytics
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/shopspring/decimal"
+)
+
+func alertTestMetrics(spend, revenue float64, clicks, conversions int64) *entity.CalculatedMetrics {
+	m := &entity.CalculatedMetrics{
+		TotalSpend:       decimal.NewFromFloat(spend),
+		TotalRevenue:     decimal.NewFromFloat(revenue),
+		TotalImpressions: 10000,
+		TotalClicks:      clicks,
+		TotalConversions: conversions,
+	}
+	m.CalculateDerivedFields()
+	return m
+}
+
+func TestParseAlertRule(t *testing.T) {
+	tests := []struct {
+		src    string
+		days   int
+		fields []string
+	}{
+		{"roas < 1.5", 1, []string{"roas"}},
+		{"ROAS < 1.5 for 3 days", 3, []string{"roas"}},
+		{"total_spend > 500 for 1 day", 1, []string{"spend"}},
+		{"(cpa > 40 or ctr < 0.5) and conversions >= 10", 1, []string{"conversions", "cpa", "ctr"}},
+		{"spend > 100 && not (roas >= 2 || revenue = 0)", 1, []string{"revenue", "roas", "spend"}},
+		{"conversion_rate != -1", 1, []string{"conversion_rate"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			expr, err := ParseAlertRule(tt.src)
+			if err != nil {
+				t.Fatalf("ParseAlertRule() error = %v", err)
+			}
+			if expr.Days != tt.days {
+				t.Errorf("Days = %d, want %d", expr.Days, tt.days)
+			}
+			if got := expr.Fields(); !reflect.DeepEqual(got, tt.fields) {
+				t.Errorf("Fields() = %v, want %v", got, tt.fields)
+			}
+		})
+	}
+}
+
+func TestParseAlertRule_Errors(t *testing.T) {
+	tests := []string{
+		"",
+		"roas",
+		"roas <",
+		"roas < high",
+		"profit > 10",
+		"roas < 1.5 and",
+		"(roas < 1.5",
+		"roas < 1.5)",
+		"roas < 1.5 for 0 days",
+		"roas < 1.5 for 2.5 days",
+		"roas < 1.5 for 60 days",
+		"roas < 1.5 for 3 weeks",
+		"roas < 1.5 spend > 2",
+		"roas ! 1",
+		"roas < 1.5 & spend > 2",
+		"roas < $1",
+	}
+
+	for _, src := range tests {
+		if _, err := ParseAlertRule(src); err == nil {
+			t.Errorf("ParseAlertRule(%q) expected an error", src)
+		}
+	}
+}
+
+func TestAlertRuleExpression_Matches(t *testing.T) {
+	// Spend 200, revenue 250, 100 clicks and 4 conversions: ROAS 1.25, CPA 50, CTR 1%
+	metrics := alertTestMetrics(200, 250, 100, 4)
+
+	tests := []struct {
+		src   string
+		match bool
+	}{
+		{"roas < 1.5", true},
+		{"roas >= 1.5", false},
+		{"spend > 500", false},
+		{"cpa == 50", true},
+		{"ctr <= 1 and clicks = 100", true},
+		{"spend > 500 or conversions < 5", true},
+		{"spend > 500 or conversions < 4", false},
+		{"roas < 2 and (spend > 500 or cpa > 40)", true},
+		{"not roas < 1.5", false},
+		// "and" binds tighter than "or"
+		{"spend > 100 or spend > 500 and roas > 10", true},
+		{"(spend > 100 or spend > 500) and roas > 10", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			expr, err := ParseAlertRule(tt.src)
+			if err != nil {
+				t.Fatalf("ParseAlertRule() error = %v", err)
+			}
+			if got := expr.Matches(metrics); got != tt.match {
+				t.Errorf("Matches() = %v, want %v", got, tt.match)
+			}
+		})
+	}
+}
+
+func TestAlertRuleExpression_UndefinedRatios(t *testing.T) {
+	// Without spend there is no ROAS, so neither comparison holds
+	metrics := alertTestMetrics(0, 0, 0, 0)
+
+	for _, src := range []string{"roas < 1.5", "roas >= 1.5", "cpa > 0"} {
+		expr, _ := ParseAlertRule(src)
+		if expr.Matches(metrics) {
+			t.Errorf("%q matched metrics without spend", src)
+		}
+	}
+
+	expr, _ := ParseAlertRule("spend == 0 and roas < 1")
+	values := expr.Values(metrics)
+	if _, ok := values["roas"]; ok || values["spend"] != 0 || len(values) != 1 {
+		t.Errorf("Values() = %v, want spend only", values)
+	}
+}
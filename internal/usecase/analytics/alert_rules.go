@@ -0,0 +1,454 @@
+package analytics
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/ads-aggregator/ads-aggregator/pkg/httpclient"
+	"github.com/google/uuid"
+)
+
+// AlertNotifier delivers triggered alert rules over one channel
+type AlertNotifier interface {
+	Channel() entity.AlertChannel
+	NotifyAlerts(ctx context.Context, rule *entity.AlertRule, alerts []entity.AlertEvent) error
+}
+
+// AlertListener is notified of the alerts of a rule after they have been
+// delivered over the rule's channels, e.g. to deliver them to webhook endpoints
+type AlertListener interface {
+	AlertsTriggered(ctx context.Context, rule *entity.AlertRule, alerts []entity.AlertEvent)
+}
+
+// AlertRuleService manages user-defined alert rules and evaluates them against
+// daily campaign metrics
+type AlertRuleService struct {
+	analytics *Service
+	ruleRepo  repository.AlertRuleRepository
+	eventRepo repository.AlertEventRepository
+	notifiers []AlertNotifier
+	listeners []AlertListener
+}
+
+// NewAlertRuleService creates a new alert rule service
+func NewAlertRuleService(
+	analytics *Service,
+	ruleRepo repository.AlertRuleRepository,
+	eventRepo repository.AlertEventRepository,
+) *AlertRuleService {
+	return &AlertRuleService{
+		analytics: analytics,
+		ruleRepo:  ruleRepo,
+		eventRepo: eventRepo,
+	}
+}
+
+// AddNotifier adds a notifier for the rules delivering over its channel
+func (s *AlertRuleService) AddNotifier(notifier AlertNotifier) {
+	s.notifiers = append(s.notifiers, notifier)
+}
+
+// AddListener adds a listener notified of every triggered alert regardless of the rule's channels
+func (s *AlertRuleService) AddListener(listener AlertListener) {
+	s.listeners = append(s.listeners, listener)
+}
+
+// AlertRuleInput holds the user-editable fields of an alert rule
+type AlertRuleInput struct {
+	Name            string
+	Description     string
+	Expression      string
+	Currency        string
+	Platforms       []entity.Platform
+	AdAccountIDs    []uuid.UUID
+	CampaignIDs     []uuid.UUID
+	Channels        []entity.AlertChannel
+	WebhookURL      string
+	WebhookSecret   string // Generated when empty
+	CooldownMinutes *int
+	IsActive        *bool
+}
+
+// ListRules lists the alert rules of an organization
+func (s *AlertRuleService) ListRules(ctx context.Context, orgID uuid.UUID) ([]entity.AlertRule, error) {
+	rules, err := s.ruleRepo.ListByOrganization(ctx, orgID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load alert rules", http.StatusInternalServerError)
+	}
+	if rules == nil {
+		rules = []entity.AlertRule{}
+	}
+	return rules, nil
+}
+
+// GetRule returns an alert rule of an organization
+func (s *AlertRuleService) GetRule(ctx context.Context, orgID, ruleID uuid.UUID) (*entity.AlertRule, error) {
+	rule, err := s.ruleRepo.GetByID(ctx, ruleID)
+	if err != nil || rule == nil || rule.OrganizationID != orgID {
+		return nil, errors.ErrNotFound("Alert rule")
+	}
+	return rule, nil
+}
+
+// CreateRule validates and creates an alert rule
+func (s *AlertRuleService) CreateRule(ctx context.Context, orgID, userID uuid.UUID, input AlertRuleInput) (*entity.AlertRule, error) {
+	rule := &entity.AlertRule{
+		BaseEntity:     entity.NewBaseEntity(),
+		OrganizationID: orgID,
+		IsActive:       true,
+		CreatedBy:      &userID,
+	}
+	if err := applyAlertRuleInput(rule, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Create(ctx, rule); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to create alert rule", http.StatusInternalServerError)
+	}
+	return rule, nil
+}
+
+// UpdateRule validates and replaces the user-editable fields of an alert rule.
+// The webhook secret is kept when the input has none.
+func (s *AlertRuleService) UpdateRule(ctx context.Context, orgID, ruleID uuid.UUID, input AlertRuleInput) (*entity.AlertRule, error) {
+	rule, err := s.GetRule(ctx, orgID, ruleID)
+	if err != nil {
+		return nil, err
+	}
+
+	if input.WebhookSecret == "" {
+		input.WebhookSecret = rule.WebhookSecret
+	}
+	if err := applyAlertRuleInput(rule, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.ruleRepo.Update(ctx, rule); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to update alert rule", http.StatusInternalServerError)
+	}
+	return rule, nil
+}
+
+// DeleteRule deletes an alert rule and its history
+func (s *AlertRuleService) DeleteRule(ctx context.Context, orgID, ruleID uuid.UUID) error {
+	if _, err := s.GetRule(ctx, orgID, ruleID); err != nil {
+		return err
+	}
+	if err := s.ruleRepo.Delete(ctx, ruleID); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete alert rule", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// ListEvents lists the alert history of an organization, most recent first
+func (s *AlertRuleService) ListEvents(ctx context.Context, filter entity.AlertEventFilter) ([]entity.AlertEvent, int64, error) {
+	events, total, err := s.eventRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load alert history", http.StatusInternalServerError)
+	}
+	if events == nil {
+		events = []entity.AlertEvent{}
+	}
+	return events, total, nil
+}
+
+// AcknowledgeEvent marks a triggered alert as seen
+func (s *AlertRuleService) AcknowledgeEvent(ctx context.Context, orgID, eventID, userID uuid.UUID) error {
+	found, err := s.eventRepo.Acknowledge(ctx, orgID, eventID, userID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to acknowledge alert", http.StatusInternalServerError)
+	}
+	if !found {
+		return errors.ErrNotFound("Alert")
+	}
+	return nil
+}
+
+// EvaluateRules evaluates the organization's active rules against the given
+// active campaigns, or all of its active campaigns when campaignIDs is empty.
+// A rule triggers for a campaign when its condition holds on each of the last
+// complete days before asOf that it spans, unless it already triggered for the
+// campaign within its cooldown or for the same day. Triggered alerts are
+// recorded and delivered over the rule's channels. It returns the alerts
+// triggered; delivery failures are recorded on the alerts and returned after
+// every rule is evaluated.
+func (s *AlertRuleService) EvaluateRules(ctx context.Context, orgID uuid.UUID, campaignIDs []uuid.UUID, asOf time.Time) ([]entity.AlertEvent, error) {
+	rules, err := s.ruleRepo.ListActive(ctx, orgID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load alert rules", http.StatusInternalServerError)
+	}
+	if len(rules) == 0 {
+		return nil, nil
+	}
+
+	// Rules are validated when saved, so a rule that no longer parses is skipped
+	exprs := make(map[uuid.UUID]*AlertRuleExpression, len(rules))
+	window := 1
+	for _, rule := range rules {
+		expr, err := ParseAlertRule(rule.Expression)
+		if err != nil {
+			continue
+		}
+		exprs[rule.ID] = expr
+		if expr.Days > window {
+			window = expr.Days
+		}
+	}
+
+	campaigns, err := activeCampaigns(ctx, s.analytics.campaignRepo, orgID, campaignIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	day := pacingDay(asOf).AddDate(0, 0, -1)
+	dateRange := entity.DateRange{StartDate: day.AddDate(0, 0, -(window - 1)), EndDate: day}
+
+	campaignMetrics := make(map[uuid.UUID][]entity.CampaignMetricsDaily)
+	var triggered []entity.AlertEvent
+	var firstErr error
+	for i := range rules {
+		rule := &rules[i]
+		expr, ok := exprs[rule.ID]
+		if !ok {
+			continue
+		}
+
+		var events []entity.AlertEvent
+		for _, campaign := range campaigns {
+			if !rule.InScope(&campaign) {
+				continue
+			}
+
+			metrics, loaded := campaignMetrics[campaign.ID]
+			if !loaded {
+				metrics, err = s.analytics.metricsRepo.GetCampaignMetrics(ctx, campaign.ID, dateRange)
+				if err != nil {
+					return triggered, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load campaign metrics", http.StatusInternalServerError)
+				}
+				campaignMetrics[campaign.ID] = metrics
+			}
+			values, matched := s.evaluateAlertRule(rule, expr, campaign, metrics, day)
+			if !matched {
+				continue
+			}
+
+			last, err := s.eventRepo.LastTriggeredAt(ctx, rule.ID, campaign.ID)
+			if err != nil {
+				return triggered, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load alert history", http.StatusInternalServerError)
+			}
+			if last != nil && asOf.Sub(*last) < rule.Cooldown() {
+				continue
+			}
+
+			event := entity.AlertEvent{
+				ID:             uuid.New(),
+				OrganizationID: orgID,
+				RuleID:         rule.ID,
+				RuleName:       rule.Name,
+				Expression:     rule.Expression,
+				CampaignID:     campaign.ID,
+				CampaignName:   campaign.PlatformCampaignName,
+				Platform:       campaign.Platform,
+				MetricDate:     day,
+				Values:         values,
+				Currency:       rule.Currency,
+				TriggeredAt:    asOf,
+			}
+			created, err := s.eventRepo.CreateIfNotExists(ctx, &event)
+			if err != nil {
+				return triggered, errors.Wrap(err, errors.ErrCodeInternal, "Failed to save alert", http.StatusInternalServerError)
+			}
+			if created {
+				events = append(events, event)
+			}
+		}
+
+		if len(events) == 0 {
+			continue
+		}
+		if err := s.deliver(ctx, rule, events); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		if err := s.ruleRepo.SetLastTriggered(ctx, rule.ID, asOf); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		for _, listener := range s.listeners {
+			listener.AlertsTriggered(ctx, rule, events)
+		}
+		triggered = append(triggered, events...)
+	}
+
+	if firstErr != nil {
+		return triggered, errors.Wrap(firstErr, errors.ErrCodeInternal, "Failed to deliver alerts", http.StatusInternalServerError)
+	}
+	return triggered, nil
+}
+
+// evaluateAlertRule checks the condition of a rule on each day of its window
+// ending on day, converted to the rule's currency. A day without a metrics row
+// counts as a day without delivery. It returns the rule's metrics on day.
+func (s *AlertRuleService) evaluateAlertRule(
+	rule *entity.AlertRule,
+	expr *AlertRuleExpression,
+	campaign entity.Campaign,
+	metrics []entity.CampaignMetricsDaily,
+	day time.Time,
+) (map[string]float64, bool) {
+	byDay := make(map[time.Time][]entity.CampaignMetricsDaily)
+	for _, m := range metrics {
+		date := pacingDay(m.MetricDate)
+		byDay[date] = append(byDay[date], m)
+	}
+
+	platform := campaign.Platform
+	var values map[string]float64
+	for i := 0; i < expr.Days; i++ {
+		date := day.AddDate(0, 0, -i)
+		calculated := s.analytics.calculateMetricsFromDaily(byDay[date], rule.Currency, &platform, 1)
+		if !expr.Matches(calculated) {
+			return nil, false
+		}
+		if i == 0 {
+			values = expr.Values(calculated)
+		}
+	}
+	return values, true
+}
+
+// deliver sends the alerts of a rule over each of its channels and records the
+// channels that succeeded on every alert
+func (s *AlertRuleService) deliver(ctx context.Context, rule *entity.AlertRule, events []entity.AlertEvent) error {
+	var delivered []entity.AlertChannel
+	var failures []string
+	for _, notifier := range s.notifiers {
+		channel := notifier.Channel()
+		if !rule.HasChannel(channel) {
+			continue
+		}
+		if err := notifier.NotifyAlerts(ctx, rule, events); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", channel, err))
+			continue
+		}
+		delivered = append(delivered, channel)
+	}
+
+	deliveryError := strings.Join(failures, "; ")
+	for i := range events {
+		events[i].DeliveredChannels = delivered
+		events[i].DeliveryError = deliveryError
+		if err := s.eventRepo.UpdateDelivery(ctx, events[i].ID, delivered, deliveryError); err != nil {
+			return err
+		}
+	}
+
+	if deliveryError != "" {
+		return fmt.Errorf("%s", deliveryError)
+	}
+	return nil
+}
+
+// applyAlertRuleInput validates the input and copies it onto the rule
+func applyAlertRuleInput(rule *entity.AlertRule, input AlertRuleInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return errors.ErrValidation("name is required")
+	}
+	if _, err := ParseAlertRule(input.Expression); err != nil {
+		return errors.ErrValidation(fmt.Sprintf("Invalid expression: %v", err))
+	}
+	if input.Currency != "" && len(input.Currency) != 3 {
+		return errors.ErrValidation(fmt.Sprintf("Invalid currency: %s", input.Currency))
+	}
+	for _, platform := range input.Platforms {
+		if !platform.IsValid() {
+			return errors.ErrValidation(fmt.Sprintf("Unknown platform: %s", platform))
+		}
+	}
+
+	if len(input.Channels) == 0 {
+		return errors.ErrValidation("At least one channel is required")
+	}
+	channels := make([]entity.AlertChannel, 0, len(input.Channels))
+	for _, channel := range input.Channels {
+		if !channel.IsValid() {
+			return errors.ErrValidation(fmt.Sprintf("Unknown channel: %s", channel))
+		}
+		if !containsAlertChannel(channels, channel) {
+			channels = append(channels, channel)
+		}
+	}
+
+	webhookURL := strings.TrimSpace(input.WebhookURL)
+	if containsAlertChannel(channels, entity.AlertChannelWebhook) {
+		parsed, err := url.Parse(webhookURL)
+		if webhookURL == "" || err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return errors.ErrValidation("webhook_url must be an http or https URL when the webhook channel is enabled")
+		}
+		if err := httpclient.ValidatePublicURL(parsed); err != nil {
+			return errors.ErrValidation("webhook_url must point to a public host")
+		}
+	}
+
+	cooldown := entity.DefaultAlertCooldownMinutes
+	if input.CooldownMinutes != nil {
+		if *input.CooldownMinutes < 0 {
+			return errors.ErrValidation("cooldown_minutes must not be negative")
+		}
+		cooldown = *input.CooldownMinutes
+	}
+
+	secret := input.WebhookSecret
+	if secret == "" && webhookURL != "" {
+		generated, err := generateWebhookSecret()
+		if err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to generate webhook secret", http.StatusInternalServerError)
+		}
+		secret = generated
+	}
+
+	rule.Name = name
+	rule.Description = input.Description
+	rule.Expression = strings.TrimSpace(input.Expression)
+	rule.Currency = strings.ToUpper(input.Currency)
+	if rule.Currency == "" {
+		rule.Currency = "MYR"
+	}
+	rule.Platforms = input.Platforms
+	rule.AdAccountIDs = input.AdAccountIDs
+	rule.CampaignIDs = input.CampaignIDs
+	rule.Channels = channels
+	rule.WebhookURL = webhookURL
+	rule.WebhookSecret = secret
+	rule.CooldownMinutes = cooldown
+	if input.IsActive != nil {
+		rule.IsActive = *input.IsActive
+	}
+	return nil
+}
+
+func containsAlertChannel(channels []entity.AlertChannel, channel entity.AlertChannel) bool {
+	for _, c := range channels {
+		if c == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// generateWebhookSecret generates the secret used to sign webhook payloads
+func generateWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
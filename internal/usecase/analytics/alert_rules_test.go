@@ -0,0 +1,382 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type mockAlertRuleRepository struct {
+	rules map[uuid.UUID]*entity.AlertRule
+}
+
+func newMockAlertRuleRepo() *mockAlertRuleRepository {
+	return &mockAlertRuleRepository{rules: make(map[uuid.UUID]*entity.AlertRule)}
+}
+
+func (m *mockAlertRuleRepository) Create(ctx context.Context, rule *entity.AlertRule) error {
+	m.rules[rule.ID] = rule
+	return nil
+}
+
+func (m *mockAlertRuleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.AlertRule, error) {
+	return m.rules[id], nil
+}
+
+func (m *mockAlertRuleRepository) Update(ctx context.Context, rule *entity.AlertRule) error {
+	m.rules[rule.ID] = rule
+	return nil
+}
+
+func (m *mockAlertRuleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.rules, id)
+	return nil
+}
+
+func (m *mockAlertRuleRepository) ListByOrganization(ctx context.Context, orgID uuid.UUID) ([]entity.AlertRule, error) {
+	var rules []entity.AlertRule
+	for _, rule := range m.rules {
+		if rule.OrganizationID == orgID {
+			rules = append(rules, *rule)
+		}
+	}
+	return rules, nil
+}
+
+func (m *mockAlertRuleRepository) ListActive(ctx context.Context, orgID uuid.UUID) ([]entity.AlertRule, error) {
+	var rules []entity.AlertRule
+	for _, rule := range m.rules {
+		if rule.OrganizationID == orgID && rule.IsActive {
+			rules = append(rules, *rule)
+		}
+	}
+	return rules, nil
+}
+
+func (m *mockAlertRuleRepository) SetLastTriggered(ctx context.Context, id uuid.UUID, triggeredAt time.Time) error {
+	if rule, ok := m.rules[id]; ok {
+		rule.LastTriggeredAt = &triggeredAt
+	}
+	return nil
+}
+
+type mockAlertEventRepository struct {
+	events []entity.AlertEvent
+}
+
+func (m *mockAlertEventRepository) CreateIfNotExists(ctx context.Context, event *entity.AlertEvent) (bool, error) {
+	for _, e := range m.events {
+		if e.RuleID == event.RuleID && e.CampaignID == event.CampaignID && e.MetricDate.Equal(event.MetricDate) {
+			return false, nil
+		}
+	}
+	m.events = append(m.events, *event)
+	return true, nil
+}
+
+func (m *mockAlertEventRepository) LastTriggeredAt(ctx context.Context, ruleID, campaignID uuid.UUID) (*time.Time, error) {
+	var last *time.Time
+	for i := range m.events {
+		e := &m.events[i]
+		if e.RuleID == ruleID && e.CampaignID == campaignID && (last == nil || e.TriggeredAt.After(*last)) {
+			last = &e.TriggeredAt
+		}
+	}
+	return last, nil
+}
+
+func (m *mockAlertEventRepository) UpdateDelivery(ctx context.Context, id uuid.UUID, channels []entity.AlertChannel, deliveryError string) error {
+	for i := range m.events {
+		if m.events[i].ID == id {
+			m.events[i].DeliveredChannels = channels
+			m.events[i].DeliveryError = deliveryError
+		}
+	}
+	return nil
+}
+
+func (m *mockAlertEventRepository) List(ctx context.Context, filter entity.AlertEventFilter) ([]entity.AlertEvent, int64, error) {
+	var events []entity.AlertEvent
+	for _, e := range m.events {
+		if e.OrganizationID == filter.OrganizationID {
+			events = append(events, e)
+		}
+	}
+	return events, int64(len(events)), nil
+}
+
+func (m *mockAlertEventRepository) Acknowledge(ctx context.Context, orgID, eventID, userID uuid.UUID) (bool, error) {
+	for i := range m.events {
+		if m.events[i].ID == eventID && m.events[i].OrganizationID == orgID {
+			now := time.Now()
+			m.events[i].AcknowledgedAt = &now
+			m.events[i].AcknowledgedBy = &userID
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type recordingAlertNotifier struct {
+	channel  entity.AlertChannel
+	received []entity.AlertEvent
+	err      error
+}
+
+func (n *recordingAlertNotifier) Channel() entity.AlertChannel {
+	return n.channel
+}
+
+func (n *recordingAlertNotifier) NotifyAlerts(ctx context.Context, rule *entity.AlertRule, alerts []entity.AlertEvent) error {
+	n.received = append(n.received, alerts...)
+	return n.err
+}
+
+func newTestAlertRuleService() (*AlertRuleService, *mockMetricsRepository, *mockCampaignRepository, *mockAlertRuleRepository, *mockAlertEventRepository) {
+	analytics, metricsRepo, campaignRepo := createTestService()
+	ruleRepo := newMockAlertRuleRepo()
+	eventRepo := &mockAlertEventRepository{}
+	return NewAlertRuleService(analytics, ruleRepo, eventRepo), metricsRepo, campaignRepo, ruleRepo, eventRepo
+}
+
+func TestCreateRule_Validation(t *testing.T) {
+	svc, _, _, _, _ := newTestAlertRuleService()
+	orgID, userID := uuid.New(), uuid.New()
+
+	valid := AlertRuleInput{
+		Name:       "Low ROAS",
+		Expression: "roas < 1.5 for 3 days",
+		Channels:   []entity.AlertChannel{entity.AlertChannelEmail, entity.AlertChannelSSE, entity.AlertChannelEmail},
+	}
+	rule, err := svc.CreateRule(context.Background(), orgID, userID, valid)
+	if err != nil {
+		t.Fatalf("CreateRule() error = %v", err)
+	}
+	if rule.Currency != "MYR" || rule.CooldownMinutes != entity.DefaultAlertCooldownMinutes || !rule.IsActive {
+		t.Errorf("rule defaults = %s, %d, %v", rule.Currency, rule.CooldownMinutes, rule.IsActive)
+	}
+	if len(rule.Channels) != 2 || rule.WebhookSecret != "" {
+		t.Errorf("channels = %v, secret = %q, want email and sse without secret", rule.Channels, rule.WebhookSecret)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(in *AlertRuleInput)
+	}{
+		{"missing name", func(in *AlertRuleInput) { in.Name = " " }},
+		{"invalid expression", func(in *AlertRuleInput) { in.Expression = "roas <" }},
+		{"no channel", func(in *AlertRuleInput) { in.Channels = nil }},
+		{"unknown channel", func(in *AlertRuleInput) { in.Channels = []entity.AlertChannel{"sms"} }},
+		{"unknown platform", func(in *AlertRuleInput) { in.Platforms = []entity.Platform{"myspace"} }},
+		{"webhook without URL", func(in *AlertRuleInput) { in.Channels = []entity.AlertChannel{entity.AlertChannelWebhook} }},
+		{"webhook with non-http URL", func(in *AlertRuleInput) {
+			in.Channels = []entity.AlertChannel{entity.AlertChannelWebhook}
+			in.WebhookURL = "ftp://example.com/hook"
+		}},
+		{"webhook to cloud metadata", func(in *AlertRuleInput) {
+			in.Channels = []entity.AlertChannel{entity.AlertChannelWebhook}
+			in.WebhookURL = "http://169.254.169.254/latest/meta-data"
+		}},
+		{"webhook to localhost", func(in *AlertRuleInput) {
+			in.Channels = []entity.AlertChannel{entity.AlertChannelWebhook}
+			in.WebhookURL = "http://localhost:9200/hook"
+		}},
+		{"negative cooldown", func(in *AlertRuleInput) { cooldown := -1; in.CooldownMinutes = &cooldown }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := valid
+			tt.modify(&input)
+			if _, err := svc.CreateRule(context.Background(), orgID, userID, input); err == nil {
+				t.Error("expected a validation error")
+			}
+		})
+	}
+}
+
+func TestUpdateRule_KeepsWebhookSecret(t *testing.T) {
+	svc, _, _, _, _ := newTestAlertRuleService()
+	orgID := uuid.New()
+
+	input := AlertRuleInput{
+		Name:       "Overspend",
+		Expression: "spend > 500",
+		Channels:   []entity.AlertChannel{entity.AlertChannelWebhook},
+		WebhookURL: "https://example.com/hooks/ads",
+	}
+	rule, err := svc.CreateRule(context.Background(), orgID, uuid.New(), input)
+	if err != nil {
+		t.Fatalf("CreateRule() error = %v", err)
+	}
+	if len(rule.WebhookSecret) != 64 {
+		t.Fatalf("generated secret = %q, want 32 hex-encoded bytes", rule.WebhookSecret)
+	}
+	secret := rule.WebhookSecret
+
+	input.Expression = "spend > 800"
+	updated, err := svc.UpdateRule(context.Background(), orgID, rule.ID, input)
+	if err != nil {
+		t.Fatalf("UpdateRule() error = %v", err)
+	}
+	if updated.Expression != "spend > 800" || updated.WebhookSecret != secret {
+		t.Errorf("updated rule = %q with secret %q, want new expression and the same secret", updated.Expression, updated.WebhookSecret)
+	}
+
+	if _, err := svc.UpdateRule(context.Background(), uuid.New(), rule.ID, input); err == nil {
+		t.Error("expected not found for another organization")
+	}
+}
+
+func TestEvaluateRules_ConsecutiveDaysAndScope(t *testing.T) {
+	svc, metricsRepo, campaignRepo, ruleRepo, eventRepo := newTestAlertRuleService()
+	email := &recordingAlertNotifier{channel: entity.AlertChannelEmail}
+	webhook := &recordingAlertNotifier{channel: entity.AlertChannelWebhook}
+	svc.AddNotifier(email)
+	svc.AddNotifier(webhook)
+
+	orgID := uuid.New()
+	lowRoas := createTestCampaign(orgID, entity.PlatformMeta)
+	recovered := createTestCampaign(orgID, entity.PlatformMeta)
+	tiktok := createTestCampaign(orgID, entity.PlatformTikTok)
+	campaignRepo.campaigns = append(campaignRepo.campaigns, lowRoas, recovered, tiktok)
+
+	day := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		date := day.AddDate(0, 0, -i)
+		metricsRepo.campaignMetrics[lowRoas.ID] = append(metricsRepo.campaignMetrics[lowRoas.ID],
+			createTestMetric(lowRoas.ID, orgID, entity.PlatformMeta, date, 100, 120, 5000, 50, 2))
+		metricsRepo.campaignMetrics[tiktok.ID] = append(metricsRepo.campaignMetrics[tiktok.ID],
+			createTestMetric(tiktok.ID, orgID, entity.PlatformTikTok, date, 100, 120, 5000, 50, 2))
+
+		// ROAS of 3 two days ago breaks the streak
+		revenue := 120.0
+		if i == 2 {
+			revenue = 300
+		}
+		metricsRepo.campaignMetrics[recovered.ID] = append(metricsRepo.campaignMetrics[recovered.ID],
+			createTestMetric(recovered.ID, orgID, entity.PlatformMeta, date, 100, revenue, 5000, 50, 2))
+	}
+
+	rule, err := svc.CreateRule(context.Background(), orgID, uuid.New(), AlertRuleInput{
+		Name:       "Meta ROAS below 1.5",
+		Expression: "roas < 1.5 for 3 days",
+		Platforms:  []entity.Platform{entity.PlatformMeta},
+		Channels:   []entity.AlertChannel{entity.AlertChannelEmail},
+	})
+	if err != nil {
+		t.Fatalf("CreateRule() error = %v", err)
+	}
+
+	asOf := day.Add(30 * time.Hour)
+	alerts, err := svc.EvaluateRules(context.Background(), orgID, nil, asOf)
+	if err != nil {
+		t.Fatalf("EvaluateRules() error = %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].CampaignID != lowRoas.ID {
+		t.Fatalf("alerts = %+v, want only the Meta campaign with three low days", alerts)
+	}
+	alert := alerts[0]
+	if !alert.MetricDate.Equal(day) || alert.Values["roas"] != 1.2 || alert.Currency != "MYR" {
+		t.Errorf("alert = %s roas %v %s, want %s roas 1.2 MYR", alert.MetricDate, alert.Values["roas"], alert.Currency, day)
+	}
+	if len(email.received) != 1 || len(webhook.received) != 0 {
+		t.Errorf("email received %d, webhook %d, want 1 and 0", len(email.received), len(webhook.received))
+	}
+	if len(eventRepo.events[0].DeliveredChannels) != 1 || eventRepo.events[0].DeliveryError != "" {
+		t.Errorf("delivery = %v %q, want email without error", eventRepo.events[0].DeliveredChannels, eventRepo.events[0].DeliveryError)
+	}
+	if ruleRepo.rules[rule.ID].LastTriggeredAt == nil {
+		t.Error("rule last_triggered_at not set")
+	}
+
+	// The next sync on the same day and the next day are within the cooldown
+	for _, at := range []time.Time{asOf.Add(time.Hour), asOf.Add(20 * time.Hour)} {
+		again, err := svc.EvaluateRules(context.Background(), orgID, []uuid.UUID{lowRoas.ID}, at)
+		if err != nil {
+			t.Fatalf("EvaluateRules() error = %v", err)
+		}
+		if len(again) != 0 {
+			t.Errorf("EvaluateRules(%s) = %d alerts, want none within the cooldown", at, len(again))
+		}
+	}
+}
+
+func TestEvaluateRules_MissingDayAndCurrency(t *testing.T) {
+	svc, metricsRepo, campaignRepo, _, _ := newTestAlertRuleService()
+
+	orgID := uuid.New()
+	campaign := createTestCampaign(orgID, entity.PlatformShopee)
+	campaignRepo.campaigns = append(campaignRepo.campaigns, campaign)
+
+	day := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	spend := createTestMetric(campaign.ID, orgID, entity.PlatformShopee, day, 600, 900, 5000, 50, 2)
+	metricsRepo.campaignMetrics[campaign.ID] = []entity.CampaignMetricsDaily{spend}
+
+	ctx := context.Background()
+	input := func(name, expression string) AlertRuleInput {
+		return AlertRuleInput{Name: name, Expression: expression, Channels: []entity.AlertChannel{entity.AlertChannelSSE}}
+	}
+	if _, err := svc.CreateRule(ctx, orgID, uuid.New(), input("Overspend", "spend > 500")); err != nil {
+		t.Fatal(err)
+	}
+	// The day before has no metrics row, which counts as zero spend
+	if _, err := svc.CreateRule(ctx, orgID, uuid.New(), input("Spend for two days", "spend > 0 for 2 days")); err != nil {
+		t.Fatal(err)
+	}
+	usd := input("Overspend in USD", "spend > 500")
+	usd.Currency = "USD"
+	if _, err := svc.CreateRule(ctx, orgID, uuid.New(), usd); err != nil {
+		t.Fatal(err)
+	}
+
+	alerts, err := svc.EvaluateRules(ctx, orgID, nil, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("EvaluateRules() error = %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].RuleName != "Overspend" || alerts[0].Values["spend"] != 600 {
+		t.Errorf("alerts = %+v, want only the MYR overspend rule", alerts)
+	}
+}
+
+func TestEvaluateRules_DeliveryFailureIsRecorded(t *testing.T) {
+	svc, metricsRepo, campaignRepo, _, eventRepo := newTestAlertRuleService()
+	svc.AddNotifier(&recordingAlertNotifier{channel: entity.AlertChannelSSE})
+	svc.AddNotifier(&recordingAlertNotifier{channel: entity.AlertChannelWebhook, err: errors.New("status 500")})
+
+	orgID := uuid.New()
+	campaign := createTestCampaign(orgID, entity.PlatformMeta)
+	campaignRepo.campaigns = append(campaignRepo.campaigns, campaign)
+
+	day := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	metricsRepo.campaignMetrics[campaign.ID] = []entity.CampaignMetricsDaily{
+		createTestMetric(campaign.ID, orgID, entity.PlatformMeta, day, 600, 900, 5000, 50, 2),
+	}
+
+	if _, err := svc.CreateRule(context.Background(), orgID, uuid.New(), AlertRuleInput{
+		Name:       "Overspend",
+		Expression: "spend > 500",
+		Channels:   []entity.AlertChannel{entity.AlertChannelSSE, entity.AlertChannelWebhook},
+		WebhookURL: "https://example.com/hook",
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	alerts, err := svc.EvaluateRules(context.Background(), orgID, nil, day.AddDate(0, 0, 1))
+	if err == nil {
+		t.Fatal("expected the delivery error")
+	}
+	if len(alerts) != 1 || len(eventRepo.events) != 1 {
+		t.Fatalf("alerts = %d, stored = %d, want 1", len(alerts), len(eventRepo.events))
+	}
+	stored := eventRepo.events[0]
+	if len(stored.DeliveredChannels) != 1 || stored.DeliveredChannels[0] != entity.AlertChannelSSE {
+		t.Errorf("delivered channels = %v, want sse", stored.DeliveredChannels)
+	}
+	if stored.DeliveryError != "webhook: status 500" {
+		t.Errorf("delivery error = %q", stored.DeliveryError)
+	}
+}
@@ -0,0 +1,398 @@
+package analytics
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// Anomaly detection tuning. A value is anomalous when its robust z-score against
+// the baseline is at least anomalyMinScore and it differs from the baseline
+// median by at least anomalyMinChange.
+const (
+	anomalyLookbackDays       = 56 // Eight weeks of history
+	anomalyRollingDays        = 28
+	anomalyMinDayOfWeekPoints = 4
+	anomalyMinRollingPoints   = 7
+	anomalyMinScore           = 3.0
+	anomalyMediumScore        = 4.5
+	anomalyHighScore          = 6.0
+	anomalyMinChange          = 0.2
+	anomalyMinScaleRatio      = 0.1    // Floor of the deviation as a share of the median
+	madToStdDev               = 1.4826 // Scales the MAD to a standard deviation for normal data
+)
+
+// AnomalyNotifier pushes newly detected anomalies to an organization
+type AnomalyNotifier interface {
+	NotifyAnomalies(ctx context.Context, orgID uuid.UUID, anomalies []entity.MetricAnomaly) error
+}
+
+// AnomalyService detects daily campaign metrics that deviate from their rolling
+// baseline, records them and notifies the organization
+type AnomalyService struct {
+	metricsRepo  repository.MetricsRepository
+	campaignRepo repository.CampaignRepository
+	anomalyRepo  repository.AnomalyRepository
+	notifiers    []AnomalyNotifier
+}
+
+// NewAnomalyService creates a new anomaly service
+func NewAnomalyService(
+	metricsRepo repository.MetricsRepository,
+	campaignRepo repository.CampaignRepository,
+	anomalyRepo repository.AnomalyRepository,
+) *AnomalyService {
+	return &AnomalyService{
+		metricsRepo:  metricsRepo,
+		campaignRepo: campaignRepo,
+		anomalyRepo:  anomalyRepo,
+	}
+}
+
+// AddNotifier adds a notifier that receives every newly detected anomaly
+func (s *AnomalyService) AddNotifier(notifier AnomalyNotifier) {
+	s.notifiers = append(s.notifiers, notifier)
+}
+
+// DetectAnomalies checks the last complete day before asOf of the given active
+// campaigns, or of all the organization's active campaigns when campaignIDs is
+// empty. Anomalies already recorded are skipped, so it is safe to run after every
+// sync. It returns the anomalies detected for the first time.
+func (s *AnomalyService) DetectAnomalies(ctx context.Context, orgID uuid.UUID, campaignIDs []uuid.UUID, asOf time.Time) ([]entity.MetricAnomaly, error) {
+	campaigns, err := activeCampaigns(ctx, s.campaignRepo, orgID, campaignIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	day := pacingDay(asOf).AddDate(0, 0, -1)
+	dateRange := entity.DateRange{StartDate: day.AddDate(0, 0, -anomalyLookbackDays), EndDate: day}
+
+	var raised []entity.MetricAnomaly
+	for _, campaign := range campaigns {
+		metrics, err := s.metricsRepo.GetCampaignMetrics(ctx, campaign.ID, dateRange)
+		if err != nil {
+			return raised, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load campaign metrics", http.StatusInternalServerError)
+		}
+
+		for _, anomaly := range detectCampaignAnomalies(campaign, metrics, day) {
+			anomaly := anomaly
+			created, err := s.anomalyRepo.CreateIfNotExists(ctx, &anomaly)
+			if err != nil {
+				return raised, errors.Wrap(err, errors.ErrCodeInternal, "Failed to save anomaly", http.StatusInternalServerError)
+			}
+			if created {
+				raised = append(raised, anomaly)
+			}
+		}
+	}
+
+	if len(raised) == 0 {
+		return raised, nil
+	}
+	return raised, s.notify(ctx, orgID, raised)
+}
+
+// ListAnomalies lists the anomalies of an organization, most recent first
+func (s *AnomalyService) ListAnomalies(ctx context.Context, filter entity.AnomalyFilter) ([]entity.MetricAnomaly, int64, error) {
+	anomalies, total, err := s.anomalyRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load anomalies", http.StatusInternalServerError)
+	}
+	if anomalies == nil {
+		anomalies = []entity.MetricAnomaly{}
+	}
+	return anomalies, total, nil
+}
+
+// AcknowledgeAnomaly marks an anomaly as seen
+func (s *AnomalyService) AcknowledgeAnomaly(ctx context.Context, orgID, anomalyID, userID uuid.UUID) error {
+	found, err := s.anomalyRepo.Acknowledge(ctx, orgID, anomalyID, userID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to acknowledge anomaly", http.StatusInternalServerError)
+	}
+	if !found {
+		return errors.ErrNotFound("Anomaly")
+	}
+	return nil
+}
+
+// activeCampaigns returns the given active campaigns of the organization, or all
+// of its active campaigns when campaignIDs is empty
+func activeCampaigns(ctx context.Context, campaignRepo repository.CampaignRepository, orgID uuid.UUID, campaignIDs []uuid.UUID) ([]entity.Campaign, error) {
+	var campaigns []entity.Campaign
+	if len(campaignIDs) == 0 {
+		all, err := campaignRepo.ListByOrganization(ctx, orgID, nil)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load campaigns", http.StatusInternalServerError)
+		}
+		campaigns = all
+	} else {
+		for _, id := range campaignIDs {
+			campaign, err := campaignRepo.GetByID(ctx, id)
+			if err != nil || campaign == nil || campaign.OrganizationID != orgID {
+				continue
+			}
+			campaigns = append(campaigns, *campaign)
+		}
+	}
+
+	// Paused campaigns are expected to stop spending
+	active := campaigns[:0]
+	for _, campaign := range campaigns {
+		if campaign.Status == entity.CampaignStatusActive {
+			active = append(active, campaign)
+		}
+	}
+	return active, nil
+}
+
+// notify sends anomalies to every notifier and records when at least one succeeded
+func (s *AnomalyService) notify(ctx context.Context, orgID uuid.UUID, anomalies []entity.MetricAnomaly) error {
+	if len(s.notifiers) == 0 {
+		return nil
+	}
+
+	var firstErr error
+	delivered := false
+	for _, notifier := range s.notifiers {
+		if err := notifier.NotifyAnomalies(ctx, orgID, anomalies); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		delivered = true
+	}
+
+	if delivered {
+		now := time.Now()
+		ids := make([]uuid.UUID, len(anomalies))
+		for i := range anomalies {
+			anomalies[i].NotifiedAt = &now
+			ids[i] = anomalies[i].ID
+		}
+		if err := s.anomalyRepo.MarkNotified(ctx, ids, now); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	if firstErr != nil {
+		return errors.Wrap(firstErr, errors.ErrCodeInternal, "Failed to notify anomalies", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// detectCampaignAnomalies compares each watched metric of day with the campaign's
+// earlier days. A day without a metrics row counts as a day without delivery.
+func detectCampaignAnomalies(campaign entity.Campaign, metrics []entity.CampaignMetricsDaily, day time.Time) []entity.MetricAnomaly {
+	current := entity.CampaignMetricsDaily{MetricDate: day}
+	var history []entity.CampaignMetricsDaily
+	for _, m := range metrics {
+		date := pacingDay(m.MetricDate)
+		switch {
+		case date.Equal(day):
+			current = m
+		case date.Before(day):
+			history = append(history, m)
+		}
+	}
+	if len(history) == 0 {
+		return nil
+	}
+
+	currency := current.Currency
+	if currency == "" {
+		currency = history[len(history)-1].Currency
+	}
+
+	var anomalies []entity.MetricAnomaly
+	for _, metric := range entity.AnomalyMetrics {
+		value, ok := anomalyMetricValue(current, metric)
+		if !ok {
+			continue
+		}
+
+		baseline, values := anomalyBaseline(history, metric, day)
+		if baseline == "" {
+			continue
+		}
+
+		score, ok := scoreAnomaly(value, values)
+		if !ok {
+			continue
+		}
+
+		anomaly := entity.MetricAnomaly{
+			ID:             uuid.New(),
+			OrganizationID: campaign.OrganizationID,
+			CampaignID:     campaign.ID,
+			CampaignName:   campaign.PlatformCampaignName,
+			Platform:       campaign.Platform,
+			MetricDate:     day,
+			Metric:         metric,
+			Value:          value,
+			Expected:       score.expected,
+			Deviation:      score.deviation,
+			Score:          score.score,
+			ChangePercent:  score.changePercent,
+			Direction:      entity.AnomalyDirectionSpike,
+			Severity:       anomalySeverity(metric, value, score),
+			Baseline:       baseline,
+			BaselineDays:   len(values),
+		}
+		if score.score < 0 {
+			anomaly.Direction = entity.AnomalyDirectionDrop
+		}
+		if metric == entity.AnomalyMetricSpend || metric == entity.AnomalyMetricCPC || metric == entity.AnomalyMetricCPA {
+			anomaly.Currency = currency
+		}
+		anomalies = append(anomalies, anomaly)
+	}
+
+	return anomalies
+}
+
+// anomalyMetricValue returns a metric of a day, computed from the raw counts.
+// Ratios are undefined when their denominator is zero.
+func anomalyMetricValue(m entity.CampaignMetricsDaily, metric entity.AnomalyMetric) (float64, bool) {
+	spend := m.Spend.InexactFloat64()
+	switch metric {
+	case entity.AnomalyMetricSpend:
+		return spend, true
+	case entity.AnomalyMetricCTR:
+		if m.Impressions == 0 {
+			return 0, false
+		}
+		return float64(m.Clicks) / float64(m.Impressions) * 100, true
+	case entity.AnomalyMetricCPC:
+		if m.Clicks == 0 {
+			return 0, false
+		}
+		return spend / float64(m.Clicks), true
+	case entity.AnomalyMetricCPA:
+		if m.Conversions == 0 {
+			return 0, false
+		}
+		return spend / float64(m.Conversions), true
+	case entity.AnomalyMetricROAS:
+		if spend == 0 {
+			return 0, false
+		}
+		return m.ConversionValue.InexactFloat64() / spend, true
+	default:
+		return 0, false
+	}
+}
+
+// anomalyBaseline picks the values to compare day with: the same weekday over
+// the lookback window when there are enough of them, which absorbs weekly
+// seasonality, otherwise every day of the last anomalyRollingDays days
+func anomalyBaseline(history []entity.CampaignMetricsDaily, metric entity.AnomalyMetric, day time.Time) (entity.AnomalyBaseline, []float64) {
+	var sameWeekday, rolling []float64
+	rollingStart := day.AddDate(0, 0, -anomalyRollingDays)
+	for _, m := range history {
+		value, ok := anomalyMetricValue(m, metric)
+		if !ok {
+			continue
+		}
+		date := pacingDay(m.MetricDate)
+		if date.Weekday() == day.Weekday() {
+			sameWeekday = append(sameWeekday, value)
+		}
+		if !date.Before(rollingStart) {
+			rolling = append(rolling, value)
+		}
+	}
+
+	switch {
+	case len(sameWeekday) >= anomalyMinDayOfWeekPoints:
+		return entity.AnomalyBaselineDayOfWeek, sameWeekday
+	case len(rolling) >= anomalyMinRollingPoints:
+		return entity.AnomalyBaselineRolling, rolling
+	default:
+		return "", nil
+	}
+}
+
+// anomalyScore describes how far a value is from its baseline
+type anomalyScore struct {
+	expected      float64
+	deviation     float64
+	score         float64
+	changePercent float64
+}
+
+// scoreAnomaly computes the robust z-score of value against the baseline using
+// the median and the median absolute deviation, which a few outliers in the
+// baseline cannot skew. It reports whether the value is anomalous.
+func scoreAnomaly(value float64, baseline []float64) (anomalyScore, bool) {
+	if len(baseline) == 0 {
+		return anomalyScore{}, false
+	}
+
+	expected := median(baseline)
+	deviations := make([]float64, len(baseline))
+	for i, v := range baseline {
+		deviations[i] = math.Abs(v - expected)
+	}
+	mad := median(deviations)
+
+	// A steady baseline has a MAD near zero, which would flag any small change
+	scale := math.Max(madToStdDev*mad, anomalyMinScaleRatio*math.Abs(expected))
+	if scale == 0 {
+		return anomalyScore{}, false
+	}
+
+	result := anomalyScore{
+		expected:  expected,
+		deviation: mad,
+		score:     (value - expected) / scale,
+	}
+	if expected != 0 {
+		result.changePercent = (value - expected) / math.Abs(expected) * 100
+	}
+
+	if math.Abs(result.score) < anomalyMinScore || math.Abs(result.changePercent) < anomalyMinChange*100 {
+		return result, false
+	}
+	return result, true
+}
+
+// anomalySeverity grades an anomaly by its score. Spend stopping altogether
+// usually means a rejected ad, an exhausted balance or a broken connection.
+func anomalySeverity(metric entity.AnomalyMetric, value float64, score anomalyScore) entity.AnomalySeverity {
+	if metric == entity.AnomalyMetricSpend && value == 0 && score.expected > 0 {
+		return entity.AnomalySeverityCritical
+	}
+
+	z := math.Abs(score.score)
+	switch {
+	case z >= anomalyHighScore:
+		return entity.AnomalySeverityHigh
+	case z >= anomalyMediumScore:
+		return entity.AnomalySeverityMedium
+	default:
+		return entity.AnomalySeverityLow
+	}
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
@@ -0,0 +1,265 @@
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type mockAnomalyRepository struct {
+	anomalies []entity.MetricAnomaly
+	notified  []uuid.UUID
+}
+
+func (m *mockAnomalyRepository) CreateIfNotExists(ctx context.Context, anomaly *entity.MetricAnomaly) (bool, error) {
+	for _, a := range m.anomalies {
+		if a.CampaignID == anomaly.CampaignID && a.MetricDate.Equal(anomaly.MetricDate) && a.Metric == anomaly.Metric {
+			return false, nil
+		}
+	}
+	m.anomalies = append(m.anomalies, *anomaly)
+	return true, nil
+}
+
+func (m *mockAnomalyRepository) List(ctx context.Context, filter entity.AnomalyFilter) ([]entity.MetricAnomaly, int64, error) {
+	var anomalies []entity.MetricAnomaly
+	for _, a := range m.anomalies {
+		if a.OrganizationID == filter.OrganizationID {
+			anomalies = append(anomalies, a)
+		}
+	}
+	return anomalies, int64(len(anomalies)), nil
+}
+
+func (m *mockAnomalyRepository) MarkNotified(ctx context.Context, ids []uuid.UUID, notifiedAt time.Time) error {
+	m.notified = append(m.notified, ids...)
+	return nil
+}
+
+func (m *mockAnomalyRepository) Acknowledge(ctx context.Context, orgID, anomalyID, userID uuid.UUID) (bool, error) {
+	for i := range m.anomalies {
+		if m.anomalies[i].ID == anomalyID && m.anomalies[i].OrganizationID == orgID {
+			now := time.Now()
+			m.anomalies[i].AcknowledgedAt = &now
+			m.anomalies[i].AcknowledgedBy = &userID
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type recordingAnomalyNotifier struct {
+	received []entity.MetricAnomaly
+	err      error
+}
+
+func (n *recordingAnomalyNotifier) NotifyAnomalies(ctx context.Context, orgID uuid.UUID, anomalies []entity.MetricAnomaly) error {
+	n.received = append(n.received, anomalies...)
+	return n.err
+}
+
+// steadyHistory returns eight weeks of daily metrics before day with spend around
+// 100, a 1% CTR, 5 conversions and a ROAS of 4
+func steadyHistory(campaignID, orgID uuid.UUID, day time.Time) []entity.CampaignMetricsDaily {
+	var metrics []entity.CampaignMetricsDaily
+	for i := anomalyLookbackDays; i >= 1; i-- {
+		spend := 98 + float64(i%5)
+		metrics = append(metrics, createTestMetric(campaignID, orgID, entity.PlatformMeta, day.AddDate(0, 0, -i), spend, spend*4, 5000, 50, 5))
+	}
+	return metrics
+}
+
+func newTestAnomalyService() (*AnomalyService, *mockMetricsRepository, *mockCampaignRepository, *mockAnomalyRepository) {
+	metricsRepo := newMockMetricsRepo()
+	campaignRepo := newMockCampaignRepo()
+	anomalyRepo := &mockAnomalyRepository{}
+	return NewAnomalyService(metricsRepo, campaignRepo, anomalyRepo), metricsRepo, campaignRepo, anomalyRepo
+}
+
+func TestDetectAnomalies_CPASpikeAndROASDrop(t *testing.T) {
+	svc, metricsRepo, campaignRepo, anomalyRepo := newTestAnomalyService()
+	notifier := &recordingAnomalyNotifier{}
+	svc.AddNotifier(notifier)
+
+	orgID := uuid.New()
+	campaign := createTestCampaign(orgID, entity.PlatformMeta)
+	campaignRepo.campaigns = append(campaignRepo.campaigns, campaign)
+
+	asOf := time.Date(2026, 3, 16, 9, 0, 0, 0, time.UTC)
+	day := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	metrics := steadyHistory(campaign.ID, orgID, day)
+	// Same spend and clicks, but conversions fell from 5 to 2
+	metrics = append(metrics, createTestMetric(campaign.ID, orgID, entity.PlatformMeta, day, 100, 160, 5000, 50, 2))
+	metricsRepo.campaignMetrics[campaign.ID] = metrics
+
+	anomalies, err := svc.DetectAnomalies(context.Background(), orgID, nil, asOf)
+	if err != nil {
+		t.Fatalf("DetectAnomalies() error = %v", err)
+	}
+
+	found := make(map[entity.AnomalyMetric]entity.MetricAnomaly)
+	for _, a := range anomalies {
+		found[a.Metric] = a
+	}
+	if len(found) != 2 {
+		t.Fatalf("anomalies = %+v, want CPA and ROAS only", anomalies)
+	}
+
+	cpa := found[entity.AnomalyMetricCPA]
+	if cpa.Direction != entity.AnomalyDirectionSpike || cpa.Severity != entity.AnomalySeverityHigh {
+		t.Errorf("CPA anomaly = %s/%s, want spike/high", cpa.Direction, cpa.Severity)
+	}
+	if cpa.Baseline != entity.AnomalyBaselineDayOfWeek || cpa.BaselineDays != 8 {
+		t.Errorf("CPA baseline = %s over %d days, want day_of_week over 8", cpa.Baseline, cpa.BaselineDays)
+	}
+	if cpa.Value != 50 || cpa.Expected != 20 || cpa.ChangePercent != 150 {
+		t.Errorf("CPA value = %v expected %v change %v, want 50, 20, 150", cpa.Value, cpa.Expected, cpa.ChangePercent)
+	}
+	if cpa.Currency != "MYR" {
+		t.Errorf("CPA currency = %q, want MYR", cpa.Currency)
+	}
+
+	roas := found[entity.AnomalyMetricROAS]
+	if roas.Direction != entity.AnomalyDirectionDrop || roas.Score >= 0 {
+		t.Errorf("ROAS anomaly direction = %s score = %v, want a drop", roas.Direction, roas.Score)
+	}
+	if roas.Currency != "" {
+		t.Errorf("ROAS currency = %q, want none", roas.Currency)
+	}
+
+	if len(notifier.received) != 2 || len(anomalyRepo.notified) != 2 {
+		t.Errorf("notified %d anomalies, marked %d, want 2", len(notifier.received), len(anomalyRepo.notified))
+	}
+	for _, a := range anomalies {
+		if a.NotifiedAt == nil {
+			t.Errorf("anomaly %s has no notified_at", a.Metric)
+		}
+	}
+
+	// A second run after the next sync raises nothing new
+	again, err := svc.DetectAnomalies(context.Background(), orgID, []uuid.UUID{campaign.ID}, asOf)
+	if err != nil {
+		t.Fatalf("DetectAnomalies() error = %v", err)
+	}
+	if len(again) != 0 || len(notifier.received) != 2 {
+		t.Errorf("second run raised %d anomalies, want 0", len(again))
+	}
+}
+
+func TestDetectAnomalies_SpendStopped(t *testing.T) {
+	svc, metricsRepo, campaignRepo, _ := newTestAnomalyService()
+
+	orgID := uuid.New()
+	campaign := createTestCampaign(orgID, entity.PlatformMeta)
+	paused := createTestCampaign(orgID, entity.PlatformMeta)
+	paused.Status = entity.CampaignStatusPaused
+	campaignRepo.campaigns = append(campaignRepo.campaigns, campaign, paused)
+
+	day := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	// No metrics row at all for the day
+	metricsRepo.campaignMetrics[campaign.ID] = steadyHistory(campaign.ID, orgID, day)
+	metricsRepo.campaignMetrics[paused.ID] = steadyHistory(paused.ID, orgID, day)
+
+	anomalies, err := svc.DetectAnomalies(context.Background(), orgID, nil, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("DetectAnomalies() error = %v", err)
+	}
+
+	if len(anomalies) != 1 {
+		t.Fatalf("anomalies = %+v, want only the spend drop of the active campaign", anomalies)
+	}
+	a := anomalies[0]
+	if a.CampaignID != campaign.ID || a.Metric != entity.AnomalyMetricSpend {
+		t.Errorf("anomaly = %s on %s, want spend on the active campaign", a.Metric, a.CampaignID)
+	}
+	if a.Severity != entity.AnomalySeverityCritical || a.Direction != entity.AnomalyDirectionDrop || a.ChangePercent != -100 {
+		t.Errorf("anomaly = %s/%s %v%%, want critical drop of 100%%", a.Severity, a.Direction, a.ChangePercent)
+	}
+}
+
+func TestDetectAnomalies_NotifierFailureKeepsAnomalies(t *testing.T) {
+	svc, metricsRepo, campaignRepo, anomalyRepo := newTestAnomalyService()
+	svc.AddNotifier(&recordingAnomalyNotifier{err: errors.New("queue unavailable")})
+
+	orgID := uuid.New()
+	campaign := createTestCampaign(orgID, entity.PlatformMeta)
+	campaignRepo.campaigns = append(campaignRepo.campaigns, campaign)
+
+	day := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+	metricsRepo.campaignMetrics[campaign.ID] = steadyHistory(campaign.ID, orgID, day)
+
+	anomalies, err := svc.DetectAnomalies(context.Background(), orgID, nil, day.AddDate(0, 0, 1))
+	if err == nil {
+		t.Fatal("expected the notification error")
+	}
+	if len(anomalies) != 1 || len(anomalyRepo.anomalies) != 1 {
+		t.Errorf("anomalies = %d, stored = %d, want 1", len(anomalies), len(anomalyRepo.anomalies))
+	}
+	if len(anomalyRepo.notified) != 0 {
+		t.Error("anomalies should not be marked notified when every notifier failed")
+	}
+}
+
+func TestAnomalyBaseline_FallsBackToRolling(t *testing.T) {
+	orgID, campaignID := uuid.New(), uuid.New()
+	day := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	// Ten days of history include only one earlier Sunday
+	var history []entity.CampaignMetricsDaily
+	for i := 10; i >= 1; i-- {
+		history = append(history, createTestMetric(campaignID, orgID, entity.PlatformMeta, day.AddDate(0, 0, -i), 100, 400, 5000, 50, 5))
+	}
+
+	baseline, values := anomalyBaseline(history, entity.AnomalyMetricSpend, day)
+	if baseline != entity.AnomalyBaselineRolling || len(values) != 10 {
+		t.Errorf("baseline = %s over %d days, want rolling over 10", baseline, len(values))
+	}
+
+	baseline, _ = anomalyBaseline(history[:5], entity.AnomalyMetricSpend, day)
+	if baseline != "" {
+		t.Errorf("baseline = %s, want none with five days of history", baseline)
+	}
+}
+
+func TestScoreAnomaly(t *testing.T) {
+	steady := []float64{100, 100, 100, 100, 100, 100, 100, 100}
+
+	tests := []struct {
+		name      string
+		value     float64
+		baseline  []float64
+		anomalous bool
+	}{
+		{"small change on a steady baseline", 115, steady, false},
+		{"large change on a steady baseline", 200, steady, true},
+		{"drop to zero", 0, steady, true},
+		{"within a noisy baseline", 140, []float64{60, 140, 80, 120, 100, 150, 50, 100}, false},
+		{"one outlier in the baseline does not mask a spike", 200, []float64{100, 100, 100, 1000, 100, 100, 100, 100}, true},
+		{"all-zero baseline", 10, []float64{0, 0, 0, 0, 0, 0, 0}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, anomalous := scoreAnomaly(tt.value, tt.baseline)
+			if anomalous != tt.anomalous {
+				t.Errorf("scoreAnomaly(%v) = %v, want %v", tt.value, anomalous, tt.anomalous)
+			}
+		})
+	}
+}
+
+func TestMedian(t *testing.T) {
+	if got := median([]float64{3, 1, 2}); got != 2 {
+		t.Errorf("median(odd) = %v, want 2", got)
+	}
+	if got := median([]float64{4, 1, 3, 2}); got != 2.5 {
+		t.Errorf("median(even) = %v, want 2.5", got)
+	}
+	if got := median(nil); got != 0 {
+		t.Errorf("median(nil) = %v, want 0", got)
+	}
+}
@@ -0,0 +1,377 @@
+package analytics
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// attributionOrderPageSize is how many orders are attributed per page
+const attributionOrderPageSize = 500
+
+// SetAttributionRepositories sets the repositories used for order attribution
+func (s *Service) SetAttributionRepositories(orderRepo repository.OrderRepository, attributionRepo repository.AttributionRepository) {
+	s.orderRepo = orderRepo
+	s.attributionRepo = attributionRepo
+}
+
+// TouchpointInput describes an ad click reported by the storefront
+type TouchpointInput struct {
+	CustomerID  string
+	CampaignID  *uuid.UUID
+	AdID        *uuid.UUID
+	ClickID     string
+	UTMSource   string
+	UTMMedium   string
+	UTMCampaign string
+	UTMContent  string
+	ClickedAt   time.Time
+}
+
+// AttributionRunResult summarizes an attribution run
+type AttributionRunResult struct {
+	Orders       int `json:"orders"`
+	Attributed   int `json:"attributed"`
+	Unattributed int `json:"unattributed"`
+}
+
+// RecordTouchpoint stores an ad click so that later orders from the same customer can be attributed to it
+func (s *Service) RecordTouchpoint(ctx context.Context, orgID uuid.UUID, input TouchpointInput) (*entity.AttributionTouchpoint, error) {
+	if s.attributionRepo == nil {
+		return nil, errors.ErrInternal("Attribution is not configured")
+	}
+
+	customerID := strings.TrimSpace(input.CustomerID)
+	if customerID == "" {
+		return nil, errors.ErrValidation("customer_id is required")
+	}
+	if input.CampaignID == nil && strings.TrimSpace(input.UTMCampaign) == "" {
+		return nil, errors.ErrValidation("Either campaign_id or utm_campaign is required")
+	}
+
+	if input.CampaignID != nil {
+		campaign, err := s.campaignRepo.GetByID(ctx, *input.CampaignID)
+		if err != nil || campaign == nil || campaign.OrganizationID != orgID {
+			return nil, errors.ErrNotFound("Campaign")
+		}
+	}
+
+	now := time.Now()
+	clickedAt := input.ClickedAt
+	if clickedAt.IsZero() {
+		clickedAt = now
+	}
+	if clickedAt.After(now.Add(5 * time.Minute)) {
+		return nil, errors.ErrValidation("clicked_at cannot be in the future")
+	}
+
+	touchpoint := &entity.AttributionTouchpoint{
+		BaseEntity:     entity.NewBaseEntity(),
+		OrganizationID: orgID,
+		CustomerID:     customerID,
+		CampaignID:     input.CampaignID,
+		AdID:           input.AdID,
+		ClickID:        strings.TrimSpace(input.ClickID),
+		UTMSource:      strings.TrimSpace(input.UTMSource),
+		UTMMedium:      strings.TrimSpace(input.UTMMedium),
+		UTMCampaign:    strings.TrimSpace(input.UTMCampaign),
+		UTMContent:     strings.TrimSpace(input.UTMContent),
+		ClickedAt:      clickedAt,
+	}
+
+	if err := s.attributionRepo.CreateTouchpoint(ctx, touchpoint); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to record touchpoint", http.StatusInternalServerError)
+	}
+
+	return touchpoint, nil
+}
+
+// AttributeOrders (re)attributes every order in the date range under all attribution models
+func (s *Service) AttributeOrders(ctx context.Context, orgID uuid.UUID, dateRange entity.DateRange) (*AttributionRunResult, error) {
+	if s.orderRepo == nil || s.attributionRepo == nil {
+		return nil, errors.ErrInternal("Attribution is not configured")
+	}
+
+	campaigns, err := s.campaignRepo.ListByOrganization(ctx, orgID, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load campaigns", http.StatusInternalServerError)
+	}
+	resolver := newCampaignResolver(campaigns)
+
+	// Clicks up to the lookback before the first order and until the end of the last day
+	touchpoints, err := s.attributionRepo.ListTouchpoints(ctx, orgID,
+		dateRange.StartDate.Add(-entity.DefaultAttributionLookback),
+		dateRange.EndDate.AddDate(0, 0, 1))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load touchpoints", http.StatusInternalServerError)
+	}
+	byCustomer := make(map[string][]entity.AttributionTouchpoint)
+	for _, tp := range touchpoints {
+		byCustomer[tp.CustomerID] = append(byCustomer[tp.CustomerID], tp)
+	}
+
+	result := &AttributionRunResult{}
+	for page := 1; ; page++ {
+		orders, _, err := s.orderRepo.List(ctx, entity.OrderFilter{
+			OrganizationID: orgID,
+			DateRange:      &dateRange,
+			Pagination:     &entity.Pagination{Page: page, PageSize: attributionOrderPageSize},
+		})
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load orders", http.StatusInternalServerError)
+		}
+
+		for i := range orders {
+			order := &orders[i]
+			attributions := attributeOrder(order, byCustomer[order.CustomerID], resolver, entity.DefaultAttributionLookback)
+			if err := s.attributionRepo.ReplaceOrderAttributions(ctx, order.ID, attributions); err != nil {
+				return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to save order attribution", http.StatusInternalServerError)
+			}
+
+			result.Orders++
+			if len(attributions) > 0 {
+				result.Attributed++
+			} else {
+				result.Unattributed++
+			}
+		}
+
+		if len(orders) < attributionOrderPageSize {
+			break
+		}
+	}
+
+	return result, nil
+}
+
+// GetCampaignAttribution returns attributed revenue and ROAS per campaign next to
+// the platform-reported conversion value, converted to targetCurrency
+func (s *Service) GetCampaignAttribution(ctx context.Context, orgID uuid.UUID, dateRange entity.DateRange, model entity.AttributionModel, targetCurrency string) ([]entity.CampaignAttribution, error) {
+	if s.attributionRepo == nil {
+		return nil, errors.ErrInternal("Attribution is not configured")
+	}
+	if model == "" {
+		model = entity.AttributionModelLastClick
+	}
+	if !model.IsValid() {
+		return nil, errors.ErrValidation("Unsupported attribution model: " + string(model))
+	}
+	if targetCurrency == "" {
+		targetCurrency = "MYR"
+	}
+
+	totals, err := s.attributionRepo.SummarizeByCampaign(ctx, orgID, model, dateRange)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to summarize attribution", http.StatusInternalServerError)
+	}
+	attributed := make(map[uuid.UUID]*entity.AttributionTotal)
+	for _, t := range totals {
+		total, ok := attributed[t.CampaignID]
+		if !ok {
+			total = &entity.AttributionTotal{CampaignID: t.CampaignID, Currency: targetCurrency}
+			attributed[t.CampaignID] = total
+		}
+		total.Orders = total.Orders.Add(t.Orders)
+		total.Revenue = total.Revenue.Add(s.convertCurrency(t.Revenue, t.Currency, targetCurrency))
+	}
+
+	campaigns, err := s.campaignRepo.ListByOrganization(ctx, orgID, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load campaigns", http.StatusInternalServerError)
+	}
+
+	results := []entity.CampaignAttribution{}
+	for _, campaign := range campaigns {
+		metrics, err := s.metricsRepo.GetCampaignMetrics(ctx, campaign.ID, dateRange)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load campaign metrics", http.StatusInternalServerError)
+		}
+
+		row := entity.CampaignAttribution{
+			CampaignID:   campaign.ID,
+			CampaignName: campaign.PlatformCampaignName,
+			Platform:     campaign.Platform,
+			Model:        model,
+			Currency:     targetCurrency,
+		}
+		for _, m := range metrics {
+			row.Spend = row.Spend.Add(s.convertCurrencyAt(m.Spend, m.Currency, targetCurrency, m.MetricDate))
+			row.PlatformConversionValue = row.PlatformConversionValue.Add(s.convertCurrencyAt(m.ConversionValue, m.Currency, targetCurrency, m.MetricDate))
+		}
+		if total, ok := attributed[campaign.ID]; ok {
+			row.AttributedOrders, _ = total.Orders.Float64()
+			row.AttributedRevenue = total.Revenue
+		}
+
+		if row.Spend.IsZero() && row.AttributedRevenue.IsZero() {
+			continue
+		}
+		if !row.Spend.IsZero() {
+			row.PlatformROAS, _ = row.PlatformConversionValue.Div(row.Spend).Float64()
+			row.AttributedROAS, _ = row.AttributedRevenue.Div(row.Spend).Float64()
+		}
+		results = append(results, row)
+	}
+
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].AttributedRevenue.GreaterThan(results[j].AttributedRevenue)
+	})
+
+	return results, nil
+}
+
+// resolvedTouch is a click that could be linked to a campaign
+type resolvedTouch struct {
+	campaignID   uuid.UUID
+	adID         *uuid.UUID
+	touchpointID *uuid.UUID
+	clickedAt    time.Time
+}
+
+// attributeOrder splits the order's revenue between the campaigns clicked within
+// the lookback window before it. The UTM parameters captured on the order count
+// as the most recent click. Orders that do not count as revenue get no attribution.
+func attributeOrder(order *entity.Order, touchpoints []entity.AttributionTouchpoint, resolver *campaignResolver, lookback time.Duration) []entity.OrderAttribution {
+	if !order.Status.IsRevenue() {
+		return nil
+	}
+
+	convertedAt := order.OrderCreatedAt
+	var touches []resolvedTouch
+	for i := range touchpoints {
+		tp := &touchpoints[i]
+		if tp.ClickedAt.After(convertedAt) || convertedAt.Sub(tp.ClickedAt) > lookback {
+			continue
+		}
+		campaign := resolver.resolve(tp.CampaignID, tp.UTMSource, tp.UTMCampaign)
+		if campaign == nil {
+			continue
+		}
+		touches = append(touches, resolvedTouch{
+			campaignID:   campaign.ID,
+			adID:         tp.AdID,
+			touchpointID: &tp.ID,
+			clickedAt:    tp.ClickedAt,
+		})
+	}
+	sort.SliceStable(touches, func(i, j int) bool { return touches[i].clickedAt.Before(touches[j].clickedAt) })
+
+	if campaign := resolver.resolve(nil, order.UTMSource, order.UTMCampaign); campaign != nil {
+		// Skip if the last recorded click already points at the same campaign
+		if len(touches) == 0 || touches[len(touches)-1].campaignID != campaign.ID {
+			touches = append(touches, resolvedTouch{campaignID: campaign.ID, clickedAt: convertedAt})
+		}
+	}
+
+	if len(touches) == 0 {
+		return nil
+	}
+
+	clicks := make([]time.Time, len(touches))
+	for i, t := range touches {
+		clicks[i] = t.clickedAt
+	}
+
+	var attributions []entity.OrderAttribution
+	for _, model := range entity.AttributionModels() {
+		for i, w := range model.Weights(clicks, convertedAt) {
+			if w == 0 {
+				continue
+			}
+			weight := decimal.NewFromFloat(w).Round(6)
+			attributions = append(attributions, entity.OrderAttribution{
+				BaseEntity:     entity.NewBaseEntity(),
+				OrderID:        order.ID,
+				OrganizationID: order.OrganizationID,
+				Model:          model,
+				CampaignID:     touches[i].campaignID,
+				AdID:           touches[i].adID,
+				TouchpointID:   touches[i].touchpointID,
+				Weight:         weight,
+				Revenue:        order.TotalAmount.Mul(decimal.NewFromFloat(w)).Round(4),
+				Currency:       order.Currency,
+				OrderDate:      order.OrderDate,
+			})
+		}
+	}
+
+	return attributions
+}
+
+// campaignResolver links clicks to campaigns by ID or by utm_campaign, which may
+// carry either the platform campaign ID or the campaign name
+type campaignResolver struct {
+	byID  map[uuid.UUID]*entity.Campaign
+	byKey map[string][]*entity.Campaign
+}
+
+func newCampaignResolver(campaigns []entity.Campaign) *campaignResolver {
+	r := &campaignResolver{
+		byID:  make(map[uuid.UUID]*entity.Campaign, len(campaigns)),
+		byKey: make(map[string][]*entity.Campaign),
+	}
+	for i := range campaigns {
+		c := &campaigns[i]
+		r.byID[c.ID] = c
+		for _, key := range []string{c.PlatformCampaignID, c.PlatformCampaignName} {
+			if key = normalizeUTMValue(key); key != "" {
+				r.byKey[key] = append(r.byKey[key], c)
+			}
+		}
+	}
+	return r
+}
+
+// resolve returns the matching campaign, preferring campaigns on the platform
+// named by utm_source when several share a name
+func (r *campaignResolver) resolve(campaignID *uuid.UUID, utmSource, utmCampaign string) *entity.Campaign {
+	if campaignID != nil {
+		return r.byID[*campaignID]
+	}
+
+	candidates := r.byKey[normalizeUTMValue(utmCampaign)]
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	if platform, ok := platformFromUTMSource(utmSource); ok {
+		for _, c := range candidates {
+			if c.Platform == platform {
+				return c
+			}
+		}
+	}
+	return candidates[0]
+}
+
+// normalizeUTMValue makes UTM values comparable to campaign names
+func normalizeUTMValue(value string) string {
+	value = strings.ToLower(strings.TrimSpace(value))
+	return strings.ReplaceAll(value, "+", " ")
+}
+
+// platformFromUTMSource maps common utm_source values to an ad platform
+func platformFromUTMSource(source string) (entity.Platform, bool) {
+	switch normalizeUTMValue(source) {
+	case "facebook", "fb", "instagram", "ig", "meta":
+		return entity.PlatformMeta, true
+	case "google", "youtube", "adwords":
+		return entity.PlatformGoogle, true
+	case "tiktok":
+		return entity.PlatformTikTok, true
+	case "shopee":
+		return entity.PlatformShopee, true
+	case "lazada":
+		return entity.PlatformLazada, true
+	default:
+		return "", false
+	}
+}
@@ -0,0 +1,292 @@
+package analytics
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type mockAttributionRepository struct {
+	touchpoints []entity.AttributionTouchpoint
+	totals      []entity.AttributionTotal
+}
+
+func (m *mockAttributionRepository) CreateTouchpoint(ctx context.Context, touchpoint *entity.AttributionTouchpoint) error {
+	m.touchpoints = append(m.touchpoints, *touchpoint)
+	return nil
+}
+
+func (m *mockAttributionRepository) ListTouchpoints(ctx context.Context, orgID uuid.UUID, from, to time.Time) ([]entity.AttributionTouchpoint, error) {
+	return m.touchpoints, nil
+}
+
+func (m *mockAttributionRepository) ReplaceOrderAttributions(ctx context.Context, orderID uuid.UUID, attributions []entity.OrderAttribution) error {
+	return nil
+}
+
+func (m *mockAttributionRepository) SummarizeByCampaign(ctx context.Context, orgID uuid.UUID, model entity.AttributionModel, dateRange entity.DateRange) ([]entity.AttributionTotal, error) {
+	return m.totals, nil
+}
+
+func TestAttributionModel_Weights(t *testing.T) {
+	convertedAt := time.Date(2024, 9, 15, 12, 0, 0, 0, time.UTC)
+	clicks := []time.Time{
+		convertedAt.Add(-7 * 24 * time.Hour),
+		convertedAt.Add(-24 * time.Hour),
+		convertedAt,
+	}
+
+	tests := []struct {
+		model entity.AttributionModel
+		want  []float64
+	}{
+		{entity.AttributionModelLastClick, []float64{0, 0, 1}},
+		{entity.AttributionModelFirstClick, []float64{1, 0, 0}},
+		{entity.AttributionModelLinear, []float64{1.0 / 3, 1.0 / 3, 1.0 / 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.model), func(t *testing.T) {
+			got := tt.model.Weights(clicks, convertedAt)
+			for i := range tt.want {
+				if math.Abs(got[i]-tt.want[i]) > 1e-9 {
+					t.Errorf("weight[%d] = %f, want %f", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+
+	t.Run("time_decay", func(t *testing.T) {
+		got := entity.AttributionModelTimeDecay.Weights(clicks, convertedAt)
+		var sum float64
+		for _, w := range got {
+			sum += w
+		}
+		if math.Abs(sum-1) > 1e-9 {
+			t.Errorf("weights sum to %f, want 1", sum)
+		}
+		// A click one half-life old gets half the credit of a click at conversion
+		if math.Abs(got[0]*2-got[2]) > 1e-9 {
+			t.Errorf("expected oldest click to get half of the latest, got %v", got)
+		}
+		if !(got[0] < got[1] && got[1] < got[2]) {
+			t.Errorf("expected weights to grow towards the conversion, got %v", got)
+		}
+	})
+
+	if weights := entity.AttributionModelLinear.Weights(nil, convertedAt); len(weights) != 0 {
+		t.Errorf("expected no weights without clicks, got %v", weights)
+	}
+}
+
+func TestAttributeOrder(t *testing.T) {
+	orgID := uuid.New()
+	meta := createTestCampaign(orgID, entity.PlatformMeta)
+	meta.PlatformCampaignName = "Raya Sale"
+	google := createTestCampaign(orgID, entity.PlatformGoogle)
+	google.PlatformCampaignName = "Raya Sale"
+	tiktok := createTestCampaign(orgID, entity.PlatformTikTok)
+	tiktok.PlatformCampaignID = "1790001"
+	resolver := newCampaignResolver([]entity.Campaign{meta, google, tiktok})
+
+	orderedAt := time.Date(2024, 9, 15, 12, 0, 0, 0, time.UTC)
+	order := &entity.Order{
+		BaseEntity:     entity.BaseEntity{ID: uuid.New()},
+		OrganizationID: orgID,
+		CustomerID:     "buyer-1",
+		Status:         entity.OrderStatusConfirmed,
+		TotalAmount:    decimal.NewFromInt(100),
+		Currency:       "MYR",
+		OrderDate:      orderedAt.Truncate(24 * time.Hour),
+		OrderCreatedAt: orderedAt,
+		UTMSource:      "google",
+		UTMCampaign:    "raya+sale",
+	}
+
+	adID := uuid.New()
+	touchpoints := []entity.AttributionTouchpoint{
+		// Outside the lookback window
+		{BaseEntity: entity.NewBaseEntity(), CampaignID: &meta.ID, ClickedAt: orderedAt.Add(-8 * 24 * time.Hour)},
+		{BaseEntity: entity.NewBaseEntity(), UTMSource: "tiktok", UTMCampaign: "1790001", ClickedAt: orderedAt.Add(-2 * time.Hour)},
+		{BaseEntity: entity.NewBaseEntity(), CampaignID: &meta.ID, AdID: &adID, ClickedAt: orderedAt.Add(-3 * 24 * time.Hour)},
+		// After the order
+		{BaseEntity: entity.NewBaseEntity(), CampaignID: &meta.ID, ClickedAt: orderedAt.Add(time.Hour)},
+		// Unknown campaign
+		{BaseEntity: entity.NewBaseEntity(), UTMCampaign: "spring", ClickedAt: orderedAt.Add(-time.Hour)},
+	}
+
+	attributions := attributeOrder(order, touchpoints, resolver, entity.DefaultAttributionLookback)
+
+	byModel := make(map[entity.AttributionModel][]entity.OrderAttribution)
+	for _, a := range attributions {
+		byModel[a.Model] = append(byModel[a.Model], a)
+	}
+
+	// The order's own UTM resolves to the Google campaign through utm_source
+	last := byModel[entity.AttributionModelLastClick]
+	if len(last) != 1 || last[0].CampaignID != google.ID || !last[0].Revenue.Equal(decimal.NewFromInt(100)) {
+		t.Fatalf("unexpected last click attribution %+v", last)
+	}
+	if last[0].TouchpointID != nil {
+		t.Error("expected the order UTM touch to have no touchpoint")
+	}
+
+	first := byModel[entity.AttributionModelFirstClick]
+	if len(first) != 1 || first[0].CampaignID != meta.ID || first[0].AdID == nil || *first[0].AdID != adID {
+		t.Fatalf("unexpected first click attribution %+v", first)
+	}
+
+	linear := byModel[entity.AttributionModelLinear]
+	if len(linear) != 3 {
+		t.Fatalf("expected 3 linear attributions, got %d", len(linear))
+	}
+	wantCampaigns := []uuid.UUID{meta.ID, tiktok.ID, google.ID}
+	total := decimal.Zero
+	for i, a := range linear {
+		if a.CampaignID != wantCampaigns[i] {
+			t.Errorf("linear[%d] campaign = %s, want %s", i, a.CampaignID, wantCampaigns[i])
+		}
+		total = total.Add(a.Revenue)
+	}
+	if total.Sub(decimal.NewFromInt(100)).Abs().GreaterThan(decimal.NewFromFloat(0.001)) {
+		t.Errorf("linear revenue sums to %s, want 100", total)
+	}
+
+	if decay := byModel[entity.AttributionModelTimeDecay]; len(decay) != 3 || !decay[0].Revenue.LessThan(decay[2].Revenue) {
+		t.Errorf("unexpected time decay attribution %+v", decay)
+	}
+}
+
+func TestAttributeOrder_NoCredit(t *testing.T) {
+	orgID := uuid.New()
+	campaign := createTestCampaign(orgID, entity.PlatformMeta)
+	campaign.PlatformCampaignName = "Raya Sale"
+	resolver := newCampaignResolver([]entity.Campaign{campaign})
+
+	orderedAt := time.Date(2024, 9, 15, 12, 0, 0, 0, time.UTC)
+	touchpoints := []entity.AttributionTouchpoint{
+		{BaseEntity: entity.NewBaseEntity(), CampaignID: &campaign.ID, ClickedAt: orderedAt.Add(-time.Hour)},
+	}
+
+	cancelled := &entity.Order{
+		Status:         entity.OrderStatusCancelled,
+		TotalAmount:    decimal.NewFromInt(50),
+		OrderCreatedAt: orderedAt,
+		UTMCampaign:    "Raya Sale",
+	}
+	if got := attributeOrder(cancelled, touchpoints, resolver, entity.DefaultAttributionLookback); got != nil {
+		t.Errorf("expected no attribution for a cancelled order, got %d rows", len(got))
+	}
+
+	organic := &entity.Order{
+		Status:         entity.OrderStatusDelivered,
+		TotalAmount:    decimal.NewFromInt(50),
+		OrderCreatedAt: orderedAt,
+	}
+	if got := attributeOrder(organic, nil, resolver, entity.DefaultAttributionLookback); got != nil {
+		t.Errorf("expected no attribution without clicks, got %d rows", len(got))
+	}
+
+	// The order UTM repeats the last click, so it is not counted twice
+	repeat := &entity.Order{
+		Status:         entity.OrderStatusDelivered,
+		TotalAmount:    decimal.NewFromInt(50),
+		OrderCreatedAt: orderedAt,
+		UTMCampaign:    "raya sale",
+	}
+	got := attributeOrder(repeat, touchpoints, resolver, entity.DefaultAttributionLookback)
+	if len(got) != len(entity.AttributionModels()) {
+		t.Fatalf("expected one attribution per model, got %d", len(got))
+	}
+	for _, a := range got {
+		if a.TouchpointID == nil || *a.TouchpointID != touchpoints[0].ID {
+			t.Errorf("expected %s credit to go to the recorded click", a.Model)
+		}
+	}
+}
+
+func TestRecordTouchpoint_Validation(t *testing.T) {
+	service, _, campaignRepo := createTestService()
+	attributionRepo := &mockAttributionRepository{}
+	service.SetAttributionRepositories(nil, attributionRepo)
+
+	orgID := uuid.New()
+	campaign := createTestCampaign(orgID, entity.PlatformMeta)
+	campaignRepo.campaigns = []entity.Campaign{campaign}
+	otherCampaign := createTestCampaign(uuid.New(), entity.PlatformMeta)
+	campaignRepo.campaigns = append(campaignRepo.campaigns, otherCampaign)
+
+	tests := []struct {
+		name  string
+		input TouchpointInput
+	}{
+		{"missing customer", TouchpointInput{CampaignID: &campaign.ID}},
+		{"missing campaign", TouchpointInput{CustomerID: "buyer-1"}},
+		{"other organization", TouchpointInput{CustomerID: "buyer-1", CampaignID: &otherCampaign.ID}},
+		{"future click", TouchpointInput{CustomerID: "buyer-1", UTMCampaign: "raya", ClickedAt: time.Now().Add(time.Hour)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.RecordTouchpoint(context.Background(), orgID, tt.input); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	tp, err := service.RecordTouchpoint(context.Background(), orgID, TouchpointInput{
+		CustomerID: " buyer-1 ",
+		CampaignID: &campaign.ID,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if tp.CustomerID != "buyer-1" || tp.ClickedAt.IsZero() || len(attributionRepo.touchpoints) != 1 {
+		t.Errorf("unexpected touchpoint %+v", tp)
+	}
+}
+
+func TestGetCampaignAttribution(t *testing.T) {
+	service, metricsRepo, campaignRepo := createTestService()
+	orgID := uuid.New()
+	active := createTestCampaign(orgID, entity.PlatformMeta)
+	idle := createTestCampaign(orgID, entity.PlatformGoogle)
+	campaignRepo.campaigns = []entity.Campaign{active, idle}
+
+	date := time.Date(2024, 9, 10, 0, 0, 0, 0, time.UTC)
+	metricsRepo.campaignMetrics[active.ID] = []entity.CampaignMetricsDaily{
+		createTestMetric(active.ID, orgID, entity.PlatformMeta, date, 100, 500, 1000, 50, 5),
+	}
+
+	service.SetAttributionRepositories(nil, &mockAttributionRepository{
+		totals: []entity.AttributionTotal{
+			{CampaignID: active.ID, Currency: "MYR", Orders: decimal.NewFromInt(3), Revenue: decimal.NewFromInt(300)},
+		},
+	})
+
+	dateRange := entity.DateRange{StartDate: date, EndDate: date}
+	results, err := service.GetCampaignAttribution(context.Background(), orgID, dateRange, "", "MYR")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(results) != 1 {
+		t.Fatalf("expected campaigns without spend or revenue to be skipped, got %d", len(results))
+	}
+
+	row := results[0]
+	if row.Model != entity.AttributionModelLastClick || row.AttributedOrders != 3 {
+		t.Errorf("unexpected row %+v", row)
+	}
+	if row.PlatformROAS != 5 || row.AttributedROAS != 3 {
+		t.Errorf("ROAS = %f platform / %f attributed, want 5 / 3", row.PlatformROAS, row.AttributedROAS)
+	}
+
+	if _, err := service.GetCampaignAttribution(context.Background(), orgID, dateRange, "u_shaped", "MYR"); err == nil {
+		t.Error("expected error for an unsupported model")
+	}
+}
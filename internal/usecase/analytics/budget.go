@@ -0,0 +1,275 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// BudgetService compares organization budgets against month-to-date spend
+// across all platforms and raises alerts as spend crosses the alert thresholds
+type BudgetService struct {
+	budgetRepo  repository.OrganizationBudgetRepository
+	alertRepo   repository.BudgetAlertRepository
+	aggregation *AggregationService
+}
+
+// NewBudgetService creates a new budget service
+func NewBudgetService(
+	budgetRepo repository.OrganizationBudgetRepository,
+	alertRepo repository.BudgetAlertRepository,
+	aggregation *AggregationService,
+) *BudgetService {
+	return &BudgetService{
+		budgetRepo:  budgetRepo,
+		alertRepo:   alertRepo,
+		aggregation: aggregation,
+	}
+}
+
+// SetBudgetService sets the service used to show budget usage on the dashboard
+func (s *Service) SetBudgetService(budgets *BudgetService) {
+	s.budgets = budgets
+}
+
+// BudgetInput holds the user-editable fields of an organization budget
+type BudgetInput struct {
+	MonthlyAmount       decimal.Decimal
+	Currency            string
+	PlatformAllocations map[entity.Platform]decimal.Decimal
+	IsActive            *bool
+}
+
+// GetBudget returns the budget of an organization
+func (s *BudgetService) GetBudget(ctx context.Context, orgID uuid.UUID) (*entity.OrganizationBudget, error) {
+	budget, err := s.budgetRepo.GetByOrganization(ctx, orgID)
+	if err != nil || budget == nil {
+		return nil, errors.ErrNotFound("Budget")
+	}
+	return budget, nil
+}
+
+// SetBudget validates and stores the budget of an organization, replacing any existing budget
+func (s *BudgetService) SetBudget(ctx context.Context, orgID uuid.UUID, input BudgetInput) (*entity.OrganizationBudget, error) {
+	if err := validateBudgetInput(input); err != nil {
+		return nil, err
+	}
+
+	budget := &entity.OrganizationBudget{
+		BaseEntity:          entity.NewBaseEntity(),
+		OrganizationID:      orgID,
+		MonthlyAmount:       input.MonthlyAmount,
+		Currency:            strings.ToUpper(input.Currency),
+		PlatformAllocations: input.PlatformAllocations,
+		IsActive:            true,
+	}
+	if budget.Currency == "" {
+		budget.Currency = "MYR"
+	}
+	if input.IsActive != nil {
+		budget.IsActive = *input.IsActive
+	}
+
+	if err := s.budgetRepo.Upsert(ctx, budget); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to save budget", http.StatusInternalServerError)
+	}
+
+	// Upsert keeps the ID of an existing budget
+	return s.GetBudget(ctx, orgID)
+}
+
+// DeleteBudget deletes the budget of an organization
+func (s *BudgetService) DeleteBudget(ctx context.Context, orgID uuid.UUID) error {
+	if _, err := s.GetBudget(ctx, orgID); err != nil {
+		return err
+	}
+	if err := s.budgetRepo.Delete(ctx, orgID); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete budget", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// GetStatus returns month-to-date spend against the budget and each platform
+// allocation, with the alerts raised this month
+func (s *BudgetService) GetStatus(ctx context.Context, orgID uuid.UUID, asOf time.Time) (*entity.BudgetStatus, error) {
+	budget, err := s.GetBudget(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	status, err := s.budgetStatus(ctx, budget, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	alerts, err := s.alertRepo.ListByMonth(ctx, orgID, status.Month)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load budget alerts", http.StatusInternalServerError)
+	}
+	status.Alerts = alerts
+	if status.Alerts == nil {
+		status.Alerts = []entity.BudgetAlert{}
+	}
+
+	return status, nil
+}
+
+// CheckAlerts raises an alert for every threshold of the budget and of each
+// platform allocation that month-to-date spend has crossed for the first time
+// this month. It returns the alerts raised.
+func (s *BudgetService) CheckAlerts(ctx context.Context, orgID uuid.UUID, asOf time.Time) ([]entity.BudgetAlert, error) {
+	budget, err := s.GetBudget(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !budget.IsActive {
+		return nil, nil
+	}
+
+	status, err := s.budgetStatus(ctx, budget, asOf)
+	if err != nil {
+		return nil, err
+	}
+
+	usages := []entity.BudgetUsage{status.Total}
+	for _, usage := range status.Platforms {
+		if !usage.IsUnallocated {
+			usages = append(usages, usage)
+		}
+	}
+
+	var raised []entity.BudgetAlert
+	for _, usage := range usages {
+		for _, threshold := range entity.BudgetAlertThresholds {
+			if threshold > usage.ThresholdHit {
+				break
+			}
+
+			alert := entity.BudgetAlert{
+				ID:             uuid.New(),
+				OrganizationID: orgID,
+				BudgetID:       budget.ID,
+				Month:          status.Month,
+				Platform:       usage.Platform,
+				AlertType:      entity.BudgetAlertTypeFor(threshold),
+				Threshold:      threshold,
+				Spend:          usage.Spend,
+				Budget:         usage.Budget,
+				Currency:       budget.Currency,
+				UsagePercent:   usage.UsagePercent,
+			}
+			created, err := s.alertRepo.CreateIfNotExists(ctx, &alert)
+			if err != nil {
+				return raised, errors.Wrap(err, errors.ErrCodeInternal, "Failed to save budget alert", http.StatusInternalServerError)
+			}
+			if created {
+				raised = append(raised, alert)
+			}
+		}
+	}
+
+	return raised, nil
+}
+
+// AcknowledgeAlert marks a budget alert as seen
+func (s *BudgetService) AcknowledgeAlert(ctx context.Context, orgID, alertID, userID uuid.UUID) error {
+	found, err := s.alertRepo.Acknowledge(ctx, orgID, alertID, userID)
+	if err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to acknowledge budget alert", http.StatusInternalServerError)
+	}
+	if !found {
+		return errors.ErrNotFound("Budget alert")
+	}
+	return nil
+}
+
+// budgetStatus aggregates spend from the first of the month through asOf in the
+// budget currency and compares it with the budget and its allocations
+func (s *BudgetService) budgetStatus(ctx context.Context, budget *entity.OrganizationBudget, asOf time.Time) (*entity.BudgetStatus, error) {
+	today := pacingDay(asOf)
+	month := monthStart(today)
+
+	response, err := s.aggregation.Aggregate(ctx, AggregationRequest{
+		OrganizationID: budget.OrganizationID,
+		DateRange:      entity.DateRange{StartDate: month, EndDate: today},
+		Level:          AggregationByPlatform,
+		TargetCurrency: budget.Currency,
+		UseCache:       true,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to aggregate spend", http.StatusInternalServerError)
+	}
+
+	spendByPlatform := make(map[entity.Platform]decimal.Decimal)
+	for _, result := range response.Results {
+		if result.Platform != nil {
+			spendByPlatform[*result.Platform] = spendByPlatform[*result.Platform].Add(result.TotalSpend)
+		}
+	}
+	totalSpend := decimal.Zero
+	if response.Total != nil {
+		totalSpend = response.Total.TotalSpend
+	}
+
+	status := &entity.BudgetStatus{
+		BudgetID:    budget.ID,
+		Currency:    budget.Currency,
+		Month:       month,
+		AsOf:        asOf,
+		DaysElapsed: today.Day(),
+		DaysInMonth: month.AddDate(0, 1, -1).Day(),
+		Total:       entity.NewBudgetUsage("", budget.MonthlyAmount, totalSpend),
+		Platforms:   []entity.BudgetUsage{},
+	}
+
+	for platform, allocation := range budget.PlatformAllocations {
+		status.Platforms = append(status.Platforms, entity.NewBudgetUsage(platform, allocation, spendByPlatform[platform]))
+	}
+	for platform, spend := range spendByPlatform {
+		if _, ok := budget.PlatformAllocations[platform]; ok {
+			continue
+		}
+		usage := entity.NewBudgetUsage(platform, decimal.Zero, spend)
+		usage.IsUnallocated = len(budget.PlatformAllocations) > 0
+		status.Platforms = append(status.Platforms, usage)
+	}
+	sort.Slice(status.Platforms, func(i, j int) bool {
+		return status.Platforms[i].Platform < status.Platforms[j].Platform
+	})
+
+	return status, nil
+}
+
+func validateBudgetInput(input BudgetInput) error {
+	if !input.MonthlyAmount.IsPositive() {
+		return errors.ErrValidation("monthly_amount must be greater than zero")
+	}
+	if input.Currency != "" && len(input.Currency) != 3 {
+		return errors.ErrValidation(fmt.Sprintf("Invalid currency: %s", input.Currency))
+	}
+
+	allocated := decimal.Zero
+	for platform, amount := range input.PlatformAllocations {
+		if !platform.IsValid() {
+			return errors.ErrValidation(fmt.Sprintf("Unknown platform: %s", platform))
+		}
+		if !amount.IsPositive() {
+			return errors.ErrValidation(fmt.Sprintf("Allocation for %s must be greater than zero", platform))
+		}
+		allocated = allocated.Add(amount)
+	}
+	if allocated.GreaterThan(input.MonthlyAmount) {
+		return errors.ErrValidation(fmt.Sprintf("Platform allocations (%s) exceed the monthly amount (%s)", allocated.StringFixed(2), input.MonthlyAmount.StringFixed(2)))
+	}
+
+	return nil
+}
@@ -0,0 +1,330 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type mockBudgetRepository struct {
+	budgets map[uuid.UUID]entity.OrganizationBudget
+}
+
+func newMockBudgetRepository() *mockBudgetRepository {
+	return &mockBudgetRepository{budgets: make(map[uuid.UUID]entity.OrganizationBudget)}
+}
+
+func (m *mockBudgetRepository) GetByOrganization(ctx context.Context, orgID uuid.UUID) (*entity.OrganizationBudget, error) {
+	budget, ok := m.budgets[orgID]
+	if !ok {
+		return nil, fmt.Errorf("record not found")
+	}
+	return &budget, nil
+}
+
+func (m *mockBudgetRepository) Upsert(ctx context.Context, budget *entity.OrganizationBudget) error {
+	if existing, ok := m.budgets[budget.OrganizationID]; ok {
+		budget.ID = existing.ID
+	}
+	m.budgets[budget.OrganizationID] = *budget
+	return nil
+}
+
+func (m *mockBudgetRepository) Delete(ctx context.Context, orgID uuid.UUID) error {
+	delete(m.budgets, orgID)
+	return nil
+}
+
+func (m *mockBudgetRepository) ListActive(ctx context.Context) ([]entity.OrganizationBudget, error) {
+	var budgets []entity.OrganizationBudget
+	for _, b := range m.budgets {
+		if b.IsActive {
+			budgets = append(budgets, b)
+		}
+	}
+	return budgets, nil
+}
+
+type mockBudgetAlertRepository struct {
+	alerts []entity.BudgetAlert
+}
+
+func (m *mockBudgetAlertRepository) CreateIfNotExists(ctx context.Context, alert *entity.BudgetAlert) (bool, error) {
+	for _, a := range m.alerts {
+		if a.OrganizationID == alert.OrganizationID && a.Month.Equal(alert.Month) &&
+			a.Platform == alert.Platform && a.Threshold == alert.Threshold {
+			return false, nil
+		}
+	}
+	m.alerts = append(m.alerts, *alert)
+	return true, nil
+}
+
+func (m *mockBudgetAlertRepository) ListByMonth(ctx context.Context, orgID uuid.UUID, month time.Time) ([]entity.BudgetAlert, error) {
+	var alerts []entity.BudgetAlert
+	for _, a := range m.alerts {
+		if a.OrganizationID == orgID && a.Month.Equal(month) {
+			alerts = append(alerts, a)
+		}
+	}
+	return alerts, nil
+}
+
+func (m *mockBudgetAlertRepository) Acknowledge(ctx context.Context, orgID, alertID, userID uuid.UUID) (bool, error) {
+	for i := range m.alerts {
+		if m.alerts[i].ID == alertID && m.alerts[i].OrganizationID == orgID {
+			now := time.Now()
+			m.alerts[i].AcknowledgedAt = &now
+			m.alerts[i].AcknowledgedBy = &userID
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// createTestBudgetService returns a budget service over campaigns on Meta, TikTok and Shopee
+// that spent 100, 60 and 40 MYR a day from the first of September 2024
+func createTestBudgetService(t *testing.T, orgID uuid.UUID, days int) (*BudgetService, *mockBudgetRepository, *mockBudgetAlertRepository) {
+	t.Helper()
+
+	metricsRepo := newMockMetricsRepo()
+	campaignRepo := newMockCampaignRepo()
+
+	for platform, spend := range map[entity.Platform]float64{
+		entity.PlatformMeta:   100,
+		entity.PlatformTikTok: 60,
+		entity.PlatformShopee: 40,
+	} {
+		campaign := createTestCampaign(orgID, platform)
+		campaignRepo.campaigns = append(campaignRepo.campaigns, campaign)
+		for d := 0; d < days; d++ {
+			date := time.Date(2024, 9, 1+d, 0, 0, 0, 0, time.UTC)
+			metricsRepo.campaignMetrics[campaign.ID] = append(metricsRepo.campaignMetrics[campaign.ID],
+				createTestMetric(campaign.ID, orgID, platform, date, spend, 0, 1000, 10, 0))
+		}
+	}
+
+	budgetRepo := newMockBudgetRepository()
+	alertRepo := &mockBudgetAlertRepository{}
+	aggregation := NewAggregationService(metricsRepo, campaignRepo, nil, nil)
+	return NewBudgetService(budgetRepo, alertRepo, aggregation), budgetRepo, alertRepo
+}
+
+func TestBudgetService_SetBudget_Validation(t *testing.T) {
+	orgID := uuid.New()
+	service, _, _ := createTestBudgetService(t, orgID, 0)
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		input BudgetInput
+	}{
+		{"zero amount", BudgetInput{MonthlyAmount: decimal.Zero}},
+		{"invalid currency", BudgetInput{MonthlyAmount: decimal.NewFromInt(100), Currency: "RINGGIT"}},
+		{"unknown platform", BudgetInput{
+			MonthlyAmount:       decimal.NewFromInt(100),
+			PlatformAllocations: map[entity.Platform]decimal.Decimal{"myspace": decimal.NewFromInt(50)},
+		}},
+		{"negative allocation", BudgetInput{
+			MonthlyAmount:       decimal.NewFromInt(100),
+			PlatformAllocations: map[entity.Platform]decimal.Decimal{entity.PlatformMeta: decimal.NewFromInt(-1)},
+		}},
+		{"allocations exceed amount", BudgetInput{
+			MonthlyAmount: decimal.NewFromInt(100),
+			PlatformAllocations: map[entity.Platform]decimal.Decimal{
+				entity.PlatformMeta:   decimal.NewFromInt(60),
+				entity.PlatformTikTok: decimal.NewFromInt(60),
+			},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.SetBudget(ctx, orgID, tt.input); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+
+	budget, err := service.SetBudget(ctx, orgID, BudgetInput{MonthlyAmount: decimal.NewFromInt(100), Currency: "usd"})
+	if err != nil {
+		t.Fatalf("SetBudget() error = %v", err)
+	}
+	if budget.Currency != "USD" || !budget.IsActive {
+		t.Errorf("budget = %+v, want active USD budget", budget)
+	}
+
+	// Replacing the budget keeps its ID
+	replaced, err := service.SetBudget(ctx, orgID, BudgetInput{MonthlyAmount: decimal.NewFromInt(200)})
+	if err != nil {
+		t.Fatalf("SetBudget() error = %v", err)
+	}
+	if replaced.ID != budget.ID || replaced.Currency != "MYR" {
+		t.Errorf("replaced budget = %+v", replaced)
+	}
+}
+
+func TestBudgetService_GetStatus(t *testing.T) {
+	orgID := uuid.New()
+	service, _, _ := createTestBudgetService(t, orgID, 10)
+	ctx := context.Background()
+
+	if _, err := service.GetStatus(ctx, orgID, time.Now()); err == nil {
+		t.Fatal("expected error without a budget")
+	}
+
+	// 10 days at 200 a day is 2000 of 2500; Shopee has no allocation
+	_, err := service.SetBudget(ctx, orgID, BudgetInput{
+		MonthlyAmount: decimal.NewFromInt(2500),
+		PlatformAllocations: map[entity.Platform]decimal.Decimal{
+			entity.PlatformMeta:   decimal.NewFromInt(1500),
+			entity.PlatformTikTok: decimal.NewFromInt(500),
+		},
+	})
+	if err != nil {
+		t.Fatalf("SetBudget() error = %v", err)
+	}
+
+	status, err := service.GetStatus(ctx, orgID, time.Date(2024, 9, 10, 15, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+
+	if !status.Total.Spend.Equal(decimal.NewFromInt(2000)) || status.Total.UsagePercent != 80 || status.Total.ThresholdHit != 80 {
+		t.Errorf("Total = %+v, want 2000 spent, 80%%", status.Total)
+	}
+	if status.DaysElapsed != 10 || status.DaysInMonth != 30 {
+		t.Errorf("days = %d/%d, want 10/30", status.DaysElapsed, status.DaysInMonth)
+	}
+
+	want := map[entity.Platform]struct {
+		spend       int64
+		threshold   int
+		over        bool
+		unallocated bool
+	}{
+		entity.PlatformMeta:   {1000, 50, false, false},
+		entity.PlatformTikTok: {600, 100, true, false},
+		entity.PlatformShopee: {400, 0, false, true},
+	}
+	if len(status.Platforms) != len(want) {
+		t.Fatalf("len(Platforms) = %d, want %d", len(status.Platforms), len(want))
+	}
+	for _, usage := range status.Platforms {
+		w := want[usage.Platform]
+		if !usage.Spend.Equal(decimal.NewFromInt(w.spend)) || usage.ThresholdHit != w.threshold ||
+			usage.IsOverBudget != w.over || usage.IsUnallocated != w.unallocated {
+			t.Errorf("%s usage = %+v, want %+v", usage.Platform, usage, w)
+		}
+	}
+}
+
+func TestBudgetService_CheckAlerts(t *testing.T) {
+	orgID := uuid.New()
+	service, _, alertRepo := createTestBudgetService(t, orgID, 10)
+	ctx := context.Background()
+	asOf := time.Date(2024, 9, 10, 0, 0, 0, 0, time.UTC)
+
+	if _, err := service.SetBudget(ctx, orgID, BudgetInput{
+		MonthlyAmount:       decimal.NewFromInt(2500),
+		PlatformAllocations: map[entity.Platform]decimal.Decimal{entity.PlatformTikTok: decimal.NewFromInt(500)},
+	}); err != nil {
+		t.Fatalf("SetBudget() error = %v", err)
+	}
+
+	raised, err := service.CheckAlerts(ctx, orgID, asOf)
+	if err != nil {
+		t.Fatalf("CheckAlerts() error = %v", err)
+	}
+
+	// The budget crossed 50% and 80%; the TikTok allocation crossed all three thresholds
+	got := make(map[string]entity.BudgetAlertType)
+	for _, alert := range raised {
+		got[fmt.Sprintf("%s/%d", alert.Platform, alert.Threshold)] = alert.AlertType
+	}
+	want := map[string]entity.BudgetAlertType{
+		"/50":        entity.BudgetAlertNotice,
+		"/80":        entity.BudgetAlertWarning,
+		"tiktok/50":  entity.BudgetAlertNotice,
+		"tiktok/80":  entity.BudgetAlertWarning,
+		"tiktok/100": entity.BudgetAlertExceeded,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("raised %v, want %v", got, want)
+	}
+	for key, alertType := range want {
+		if got[key] != alertType {
+			t.Errorf("alert %s = %q, want %q", key, got[key], alertType)
+		}
+	}
+
+	// Alerts are raised once per month
+	raised, err = service.CheckAlerts(ctx, orgID, asOf.Add(time.Hour))
+	if err != nil {
+		t.Fatalf("CheckAlerts() error = %v", err)
+	}
+	if len(raised) != 0 {
+		t.Errorf("raised %d alerts again, want 0", len(raised))
+	}
+
+	status, err := service.GetStatus(ctx, orgID, asOf)
+	if err != nil {
+		t.Fatalf("GetStatus() error = %v", err)
+	}
+	if len(status.Alerts) != 5 {
+		t.Errorf("len(Alerts) = %d, want 5", len(status.Alerts))
+	}
+
+	if err := service.AcknowledgeAlert(ctx, orgID, alertRepo.alerts[0].ID, uuid.New()); err != nil {
+		t.Errorf("AcknowledgeAlert() error = %v", err)
+	}
+	if err := service.AcknowledgeAlert(ctx, uuid.New(), alertRepo.alerts[1].ID, uuid.New()); err == nil {
+		t.Error("expected error acknowledging another organization's alert")
+	}
+}
+
+func TestBudgetService_CheckAlerts_InactiveBudget(t *testing.T) {
+	orgID := uuid.New()
+	service, _, alertRepo := createTestBudgetService(t, orgID, 30)
+	ctx := context.Background()
+
+	inactive := false
+	if _, err := service.SetBudget(ctx, orgID, BudgetInput{MonthlyAmount: decimal.NewFromInt(100), IsActive: &inactive}); err != nil {
+		t.Fatalf("SetBudget() error = %v", err)
+	}
+
+	raised, err := service.CheckAlerts(ctx, orgID, time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("CheckAlerts() error = %v", err)
+	}
+	if len(raised) != 0 || len(alertRepo.alerts) != 0 {
+		t.Errorf("raised %d alerts for an inactive budget", len(raised))
+	}
+}
+
+func TestGetDashboardMetrics_IncludesBudget(t *testing.T) {
+	orgID := uuid.New()
+	budgets, _, _ := createTestBudgetService(t, orgID, 0)
+	if _, err := budgets.SetBudget(context.Background(), orgID, BudgetInput{MonthlyAmount: decimal.NewFromInt(1000)}); err != nil {
+		t.Fatalf("SetBudget() error = %v", err)
+	}
+
+	service, _, _ := createTestService()
+	service.SetBudgetService(budgets)
+
+	dashboard, err := service.GetDashboardMetrics(context.Background(), orgID, entity.DateRange{
+		StartDate: time.Now().AddDate(0, 0, -7),
+		EndDate:   time.Now(),
+	})
+	if err != nil {
+		t.Fatalf("GetDashboardMetrics() error = %v", err)
+	}
+	if dashboard.Budget == nil || !dashboard.Budget.Total.Budget.Equal(decimal.NewFromInt(1000)) {
+		t.Errorf("Budget = %+v, want the organization budget", dashboard.Budget)
+	}
+}
@@ -0,0 +1,155 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/pkg/xlsx"
+	"github.com/shopspring/decimal"
+)
+
+// campaignSheetColumns are the columns shared by the campaign and per-platform sheets
+var campaignSheetColumns = []string{
+	"Campaign ID", "Campaign Name", "Platform", "Status", "Spend", "Impressions",
+	"Clicks", "CTR", "CPC", "Conversions", "Revenue", "ROAS",
+}
+
+// exportToExcel converts the report to an Office Open XML workbook with a summary
+// sheet, a campaign sheet, and one sheet per platform
+func (s *Service) exportToExcel(report *ReportExport) ([]byte, error) {
+	money := xlsx.CurrencyFormat(report.Summary.Currency)
+	workbook := xlsx.NewWorkbook()
+
+	s.writeSummarySheet(workbook.AddSheet("Summary"), report, money)
+
+	campaigns := workbook.AddSheet("Campaigns")
+	campaigns.SetHeader(campaignSheetColumns...)
+	for _, row := range report.CampaignMetrics {
+		campaigns.AddRow(campaignRowCells(row, money)...)
+	}
+
+	for _, platform := range reportPlatforms(report) {
+		sheet := workbook.AddSheet(platformSheetName(platform))
+		sheet.SetHeader(campaignSheetColumns...)
+
+		for _, row := range report.CampaignMetrics {
+			if row.Platform == platform {
+				sheet.AddRow(campaignRowCells(row, money)...)
+			}
+		}
+
+		// Close each platform sheet with the platform totals
+		for _, pm := range report.PlatformMetrics {
+			if pm.Platform != platform {
+				continue
+			}
+			sheet.AddBlankRow()
+			sheet.AddRow(
+				xlsx.Text("Total"),
+				xlsx.Text(""),
+				xlsx.Text(string(pm.Platform)),
+				xlsx.Text(""),
+				xlsx.Decimal(pm.Spend, money),
+				xlsx.Int(pm.Impressions),
+				xlsx.Int(pm.Clicks),
+				xlsx.Float(pm.CTR, xlsx.FormatPercent),
+				xlsx.Decimal(pm.CPC, money),
+				xlsx.Int(pm.Conversions),
+				xlsx.Text(""),
+				xlsx.Float(pm.ROAS, xlsx.FormatMultiple),
+			)
+		}
+	}
+
+	return workbook.Bytes()
+}
+
+// writeSummarySheet writes report metadata and headline totals as metric/value pairs
+func (s *Service) writeSummarySheet(sheet *xlsx.Sheet, report *ReportExport, money string) {
+	summary := report.Summary
+
+	sheet.SetHeader("Metric", "Value")
+	sheet.AddRow(xlsx.Text("Organization ID"), xlsx.Text(report.OrganizationID.String()))
+	sheet.AddRow(xlsx.Text("Period Start"), xlsx.Text(report.DateRange.StartDate.Format("2006-01-02")))
+	sheet.AddRow(xlsx.Text("Period End"), xlsx.Text(report.DateRange.EndDate.Format("2006-01-02")))
+	sheet.AddRow(xlsx.Text("Generated At"), xlsx.Text(report.GeneratedAt.UTC().Format(time.RFC3339)))
+	sheet.AddRow(xlsx.Text("Currency"), xlsx.Text(summary.Currency))
+	sheet.AddBlankRow()
+	sheet.AddRow(xlsx.Text("Total Spend"), xlsx.Decimal(summary.TotalSpend, money))
+	sheet.AddRow(xlsx.Text("Total Revenue"), xlsx.Decimal(summary.TotalRevenue, money))
+	sheet.AddRow(xlsx.Text("Impressions"), xlsx.Int(summary.TotalImpressions))
+	sheet.AddRow(xlsx.Text("Clicks"), xlsx.Int(summary.TotalClicks))
+	sheet.AddRow(xlsx.Text("Conversions"), xlsx.Int(summary.TotalConversions))
+	sheet.AddRow(xlsx.Text("CTR"), xlsx.Float(summary.AverageCTR, xlsx.FormatPercent))
+	sheet.AddRow(xlsx.Text("CPC"), xlsx.Decimal(roundMoney(summary.AverageCPC), money))
+	sheet.AddRow(xlsx.Text("CPM"), xlsx.Decimal(roundMoney(summary.AverageCPM), money))
+	sheet.AddRow(xlsx.Text("CPA"), xlsx.Decimal(roundMoney(summary.AverageCPA), money))
+	sheet.AddRow(xlsx.Text("ROAS"), xlsx.Float(summary.OverallROAS, xlsx.FormatMultiple))
+	sheet.AddRow(xlsx.Text("Campaigns"), xlsx.Int(int64(len(report.CampaignMetrics))))
+}
+
+// campaignRowCells converts a campaign report row to typed worksheet cells
+func campaignRowCells(row CampaignReportRow, money string) []xlsx.Cell {
+	return []xlsx.Cell{
+		xlsx.Text(row.CampaignID.String()),
+		xlsx.Text(row.CampaignName),
+		xlsx.Text(string(row.Platform)),
+		xlsx.Text(row.Status),
+		xlsx.Decimal(row.Spend, money),
+		xlsx.Int(row.Impressions),
+		xlsx.Int(row.Clicks),
+		xlsx.Float(row.CTR, xlsx.FormatPercent),
+		xlsx.Decimal(roundMoney(row.CPC), money),
+		xlsx.Int(row.Conversions),
+		xlsx.Decimal(row.Revenue, money),
+		xlsx.Float(row.ROAS, xlsx.FormatMultiple),
+	}
+}
+
+// reportPlatforms returns the platforms present in the report in a stable order
+func reportPlatforms(report *ReportExport) []entity.Platform {
+	seen := make(map[entity.Platform]bool)
+	platforms := make([]entity.Platform, 0)
+
+	add := func(p entity.Platform) {
+		if p != "" && !seen[p] {
+			seen[p] = true
+			platforms = append(platforms, p)
+		}
+	}
+	for _, pm := range report.PlatformMetrics {
+		add(pm.Platform)
+	}
+	for _, row := range report.CampaignMetrics {
+		add(row.Platform)
+	}
+
+	sort.Slice(platforms, func(i, j int) bool {
+		return platforms[i] < platforms[j]
+	})
+	return platforms
+}
+
+// platformSheetName returns the worksheet title for a platform
+func platformSheetName(platform entity.Platform) string {
+	switch platform {
+	case entity.PlatformMeta:
+		return "Meta"
+	case entity.PlatformTikTok:
+		return "TikTok"
+	case entity.PlatformShopee:
+		return "Shopee"
+	case entity.PlatformGoogle:
+		return "Google"
+	case entity.PlatformLazada:
+		return "Lazada"
+	default:
+		return string(platform)
+	}
+}
+
+// roundMoney rounds derived monetary values (CPC, CPA) to 4 decimal places to match storage precision
+func roundMoney(d decimal.Decimal) decimal.Decimal {
+	return d.Round(4)
+}
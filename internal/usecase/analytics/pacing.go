@@ -0,0 +1,404 @@
+package analytics
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// Budget sources of a campaign's pacing
+const (
+	budgetSourceCampaign = "campaign"
+	budgetSourceAdSets   = "ad_sets"
+	budgetSourceNone     = "none"
+)
+
+// SetAdSetRepository sets the repository used to pace ad set budgets
+func (s *Service) SetAdSetRepository(adSetRepo repository.AdSetRepository) {
+	s.adSetRepo = adSetRepo
+}
+
+// GetCampaignPacing returns the month-to-date and lifetime pacing of a campaign
+// and of its ad sets, converted to targetCurrency
+func (s *Service) GetCampaignPacing(ctx context.Context, orgID, campaignID uuid.UUID, targetCurrency string) (*entity.CampaignPacing, error) {
+	if targetCurrency == "" {
+		targetCurrency = "MYR"
+	}
+
+	campaign, err := s.campaignRepo.GetByID(ctx, campaignID)
+	if err != nil || campaign == nil || campaign.OrganizationID != orgID {
+		return nil, errors.ErrNotFound("Campaign")
+	}
+
+	return s.paceCampaign(ctx, campaign, time.Now().UTC(), targetCurrency, true)
+}
+
+// GetPacing returns the pacing of the organization's active campaigns that have
+// a budget. With flaggedOnly, only campaigns likely to under- or overspend are listed.
+func (s *Service) GetPacing(ctx context.Context, orgID uuid.UUID, targetCurrency string, flaggedOnly bool) (*entity.PacingReport, error) {
+	return s.getPacing(ctx, orgID, time.Now().UTC(), targetCurrency, flaggedOnly)
+}
+
+func (s *Service) getPacing(ctx context.Context, orgID uuid.UUID, asOf time.Time, targetCurrency string, flaggedOnly bool) (*entity.PacingReport, error) {
+	if targetCurrency == "" {
+		targetCurrency = "MYR"
+	}
+
+	campaigns, _, err := s.campaignRepo.List(ctx, entity.CampaignFilter{
+		OrganizationID: orgID,
+		Statuses:       []entity.CampaignStatus{entity.CampaignStatusActive},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load campaigns", http.StatusInternalServerError)
+	}
+
+	report := &entity.PacingReport{
+		Currency:  targetCurrency,
+		AsOf:      pacingDay(asOf),
+		Campaigns: []entity.CampaignPacing{},
+	}
+	for i := range campaigns {
+		if campaigns[i].Status != entity.CampaignStatusActive {
+			continue
+		}
+
+		pacing, err := s.paceCampaign(ctx, &campaigns[i], asOf, targetCurrency, false)
+		if err != nil {
+			return nil, err
+		}
+		if pacing.BudgetSource == budgetSourceNone {
+			continue
+		}
+
+		if mtd := pacing.MonthToDate; mtd != nil {
+			report.MonthBudget = report.MonthBudget.Add(mtd.Budget)
+			report.MonthSpend = report.MonthSpend.Add(mtd.Spend)
+			report.ProjectedSpend = report.ProjectedSpend.Add(mtd.ProjectedSpend)
+		}
+		switch pacing.Status {
+		case entity.PacingStatusOverspending:
+			report.Overspending++
+		case entity.PacingStatusUnderspending:
+			report.Underspending++
+		}
+
+		if flaggedOnly && !pacing.Status.IsFlagged() {
+			continue
+		}
+		report.Campaigns = append(report.Campaigns, *pacing)
+	}
+
+	// Flagged campaigns first, furthest off budget first
+	sort.SliceStable(report.Campaigns, func(i, j int) bool {
+		a, b := report.Campaigns[i], report.Campaigns[j]
+		if a.Status.IsFlagged() != b.Status.IsFlagged() {
+			return a.Status.IsFlagged()
+		}
+		return pacingDeviation(a) > pacingDeviation(b)
+	})
+
+	return report, nil
+}
+
+// paceCampaign paces a campaign against its own budget or, when it has none,
+// the combined budgets of its active ad sets
+func (s *Service) paceCampaign(ctx context.Context, campaign *entity.Campaign, asOf time.Time, targetCurrency string, withAdSets bool) (*entity.CampaignPacing, error) {
+	pacing := &entity.CampaignPacing{
+		CampaignID:     campaign.ID,
+		CampaignName:   campaign.PlatformCampaignName,
+		Platform:       campaign.Platform,
+		CampaignStatus: campaign.Status,
+		Currency:       targetCurrency,
+		BudgetSource:   budgetSourceCampaign,
+	}
+	pacing.DailyBudget = s.convertBudget(campaign.DailyBudget, campaign.BudgetCurrency, targetCurrency)
+	pacing.LifetimeBudget = s.convertBudget(campaign.LifetimeBudget, campaign.BudgetCurrency, targetCurrency)
+
+	var adSets []entity.AdSet
+	if s.adSetRepo != nil && (withAdSets || (pacing.DailyBudget == nil && pacing.LifetimeBudget == nil)) {
+		var err error
+		adSets, err = s.adSetRepo.ListByCampaign(ctx, campaign.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load ad sets", http.StatusInternalServerError)
+		}
+	}
+
+	if pacing.DailyBudget == nil && pacing.LifetimeBudget == nil {
+		pacing.DailyBudget, pacing.LifetimeBudget = s.sumAdSetBudgets(adSets, campaign.BudgetCurrency, targetCurrency)
+		pacing.BudgetSource = budgetSourceAdSets
+		if pacing.DailyBudget == nil && pacing.LifetimeBudget == nil {
+			pacing.BudgetSource = budgetSourceNone
+			pacing.Status = entity.PacingStatusNoBudget
+			return pacing, nil
+		}
+	}
+
+	start := campaignStartDate(campaign)
+	today := pacingDay(asOf)
+
+	metrics, err := s.metricsRepo.GetCampaignMetrics(ctx, campaign.ID, entity.DateRange{
+		StartDate: minTime(start, monthStart(today)),
+		EndDate:   today.AddDate(0, 0, -1),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load campaign metrics", http.StatusInternalServerError)
+	}
+	spend := make(map[time.Time]decimal.Decimal)
+	for _, m := range metrics {
+		day := pacingDay(m.MetricDate)
+		spend[day] = spend[day].Add(s.convertCurrencyAt(m.Spend, m.Currency, targetCurrency, m.MetricDate))
+	}
+
+	pacing.MonthToDate, pacing.Lifetime = pacePeriods(pacing.DailyBudget, pacing.LifetimeBudget, start, campaign.EndDate, spend, today)
+	pacing.Status = worstPacingStatus(pacing.MonthToDate, pacing.Lifetime)
+
+	if withAdSets {
+		for i := range adSets {
+			adSetPacing, err := s.paceAdSet(ctx, &adSets[i], start, campaign.EndDate, campaign.BudgetCurrency, targetCurrency, today)
+			if err != nil {
+				return nil, err
+			}
+			if adSetPacing != nil {
+				pacing.AdSets = append(pacing.AdSets, *adSetPacing)
+			}
+		}
+	}
+
+	return pacing, nil
+}
+
+// paceAdSet paces an ad set with its own budget. Ad sets without dates run for the
+// campaign's dates, and their budgets are in the campaign's budget currency.
+func (s *Service) paceAdSet(ctx context.Context, adSet *entity.AdSet, campaignStart time.Time, campaignEnd *time.Time, budgetCurrency, targetCurrency string, today time.Time) (*entity.AdSetPacing, error) {
+	daily := s.convertBudget(adSet.DailyBudget, budgetCurrency, targetCurrency)
+	lifetime := s.convertBudget(adSet.LifetimeBudget, budgetCurrency, targetCurrency)
+	if daily == nil && lifetime == nil {
+		return nil, nil
+	}
+
+	start, end := campaignStart, campaignEnd
+	if adSet.StartDate != nil {
+		start = pacingDay(*adSet.StartDate)
+	}
+	if adSet.EndDate != nil {
+		end = adSet.EndDate
+	}
+
+	metrics, err := s.metricsRepo.GetAdSetMetrics(ctx, adSet.ID, entity.DateRange{
+		StartDate: minTime(start, monthStart(today)),
+		EndDate:   today.AddDate(0, 0, -1),
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load ad set metrics", http.StatusInternalServerError)
+	}
+	spend := make(map[time.Time]decimal.Decimal)
+	for _, m := range metrics {
+		day := pacingDay(m.MetricDate)
+		spend[day] = spend[day].Add(s.convertCurrencyAt(m.Spend, m.Currency, targetCurrency, m.MetricDate))
+	}
+
+	pacing := &entity.AdSetPacing{AdSetID: adSet.ID, AdSetName: adSet.PlatformAdSetName}
+	pacing.MonthToDate, pacing.Lifetime = pacePeriods(daily, lifetime, start, end, spend, today)
+	pacing.Status = worstPacingStatus(pacing.MonthToDate, pacing.Lifetime)
+	return pacing, nil
+}
+
+// sumAdSetBudgets totals the budgets of active ad sets. A total is only returned
+// if at least one ad set has a budget of that kind.
+func (s *Service) sumAdSetBudgets(adSets []entity.AdSet, budgetCurrency, targetCurrency string) (daily, lifetime *decimal.Decimal) {
+	for _, adSet := range adSets {
+		if adSet.Status != entity.CampaignStatusActive {
+			continue
+		}
+		if budget := s.convertBudget(adSet.DailyBudget, budgetCurrency, targetCurrency); budget != nil {
+			total := budget.Add(decimalOrZero(daily))
+			daily = &total
+		}
+		if budget := s.convertBudget(adSet.LifetimeBudget, budgetCurrency, targetCurrency); budget != nil {
+			total := budget.Add(decimalOrZero(lifetime))
+			lifetime = &total
+		}
+	}
+	return daily, lifetime
+}
+
+// convertBudget converts a budget at today's rate; missing and zero budgets are nil
+func (s *Service) convertBudget(budget *decimal.Decimal, from, to string) *decimal.Decimal {
+	if budget == nil || !budget.IsPositive() {
+		return nil
+	}
+	converted := s.convertCurrency(*budget, from, to)
+	return &converted
+}
+
+// pacePeriods paces spend for the current month and, for lifetime budgets with
+// an end date, for the whole flight. Months are budgeted with the daily budget
+// or, failing that, an even share of the lifetime budget.
+func pacePeriods(daily, lifetime *decimal.Decimal, start time.Time, end *time.Time, spend map[time.Time]decimal.Decimal, today time.Time) (monthToDate, flight *entity.BudgetPacing) {
+	var endDay *time.Time
+	if end != nil {
+		day := pacingDay(*end)
+		endDay = &day
+	}
+
+	if lifetime != nil && endDay != nil && !endDay.Before(start) {
+		flight = budgetPacing(entity.PacingPeriodLifetime, start, *endDay, *lifetime, spend, today)
+	}
+
+	periodStart, periodEnd := monthStart(today), monthStart(today).AddDate(0, 1, -1)
+	if start.After(periodStart) {
+		periodStart = start
+	}
+	if endDay != nil && endDay.Before(periodEnd) {
+		periodEnd = *endDay
+	}
+	if periodEnd.Before(periodStart) {
+		return nil, flight
+	}
+
+	periodDays := decimal.NewFromInt(int64(daysBetween(periodStart, periodEnd) + 1))
+	switch {
+	case daily != nil:
+		monthToDate = budgetPacing(entity.PacingPeriodMonthToDate, periodStart, periodEnd, daily.Mul(periodDays), spend, today)
+	case flight != nil:
+		flightDays := decimal.NewFromInt(int64(daysBetween(start, *endDay) + 1))
+		monthToDate = budgetPacing(entity.PacingPeriodMonthToDate, periodStart, periodEnd, lifetime.Div(flightDays).Mul(periodDays), spend, today)
+	}
+
+	return monthToDate, flight
+}
+
+// budgetPacing paces spend over the days from start to end inclusive. The days
+// before today count as elapsed; the rest are projected at the run rate of the
+// last PacingRunRateDays elapsed days.
+func budgetPacing(period entity.PacingPeriod, start, end time.Time, budget decimal.Decimal, spend map[time.Time]decimal.Decimal, today time.Time) *entity.BudgetPacing {
+	pacing := &entity.BudgetPacing{
+		Period:    period,
+		StartDate: start,
+		EndDate:   end,
+		Budget:    budget,
+	}
+
+	totalDays := daysBetween(start, end) + 1
+	lastElapsed := minTime(today.AddDate(0, 0, -1), end)
+	if !lastElapsed.Before(start) {
+		pacing.DaysElapsed = daysBetween(start, lastElapsed) + 1
+	}
+	pacing.DaysRemaining = totalDays - pacing.DaysElapsed
+
+	if pacing.DaysElapsed == 0 {
+		pacing.Status = entity.PacingStatusNotStarted
+		return pacing
+	}
+
+	var recent decimal.Decimal
+	window := pacing.DaysElapsed
+	if window > entity.PacingRunRateDays {
+		window = entity.PacingRunRateDays
+	}
+	for i := 0; i < pacing.DaysElapsed; i++ {
+		day := start.AddDate(0, 0, i)
+		pacing.Spend = pacing.Spend.Add(spend[day])
+		if i >= pacing.DaysElapsed-window {
+			recent = recent.Add(spend[day])
+		}
+	}
+
+	pacing.RunRate = recent.Div(decimal.NewFromInt(int64(window))).Round(4)
+	pacing.ExpectedSpend = budget.Mul(decimal.NewFromInt(int64(pacing.DaysElapsed))).Div(decimal.NewFromInt(int64(totalDays))).Round(4)
+	pacing.ProjectedSpend = pacing.Spend.Add(pacing.RunRate.Mul(decimal.NewFromInt(int64(pacing.DaysRemaining))))
+
+	if pacing.ExpectedSpend.IsPositive() {
+		pacing.PacingPct, _ = pacing.Spend.Div(pacing.ExpectedSpend).Mul(decimal.NewFromInt(100)).Float64()
+	}
+	if budget.IsPositive() {
+		pacing.ProjectedPct, _ = pacing.ProjectedSpend.Div(budget).Mul(decimal.NewFromInt(100)).Float64()
+	}
+
+	switch {
+	case pacing.ProjectedPct > (1+entity.PacingTolerance)*100:
+		pacing.Status = entity.PacingStatusOverspending
+	case pacing.ProjectedPct < (1-entity.PacingTolerance)*100:
+		pacing.Status = entity.PacingStatusUnderspending
+	default:
+		pacing.Status = entity.PacingStatusOnTrack
+	}
+	return pacing
+}
+
+// worstPacingStatus returns the most severe status of the periods
+func worstPacingStatus(periods ...*entity.BudgetPacing) entity.PacingStatus {
+	severity := map[entity.PacingStatus]int{
+		entity.PacingStatusNotStarted:    1,
+		entity.PacingStatusOnTrack:       2,
+		entity.PacingStatusUnderspending: 3,
+		entity.PacingStatusOverspending:  4,
+	}
+
+	status := entity.PacingStatusNoBudget
+	for _, period := range periods {
+		if period != nil && severity[period.Status] > severity[status] {
+			status = period.Status
+		}
+	}
+	return status
+}
+
+// pacingDeviation is how far a campaign's projected month-to-date spend is from budget, in percent
+func pacingDeviation(pacing entity.CampaignPacing) float64 {
+	period := pacing.MonthToDate
+	if period == nil {
+		period = pacing.Lifetime
+	}
+	if period == nil {
+		return 0
+	}
+	return math.Abs(period.ProjectedPct - 100)
+}
+
+// campaignStartDate is the day a campaign started running
+func campaignStartDate(campaign *entity.Campaign) time.Time {
+	switch {
+	case campaign.StartDate != nil:
+		return pacingDay(*campaign.StartDate)
+	case campaign.PlatformCreatedAt != nil:
+		return pacingDay(*campaign.PlatformCreatedAt)
+	default:
+		return pacingDay(campaign.CreatedAt)
+	}
+}
+
+// pacingDay truncates t to its calendar day in UTC
+func pacingDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func monthStart(day time.Time) time.Time {
+	return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func daysBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24)
+}
+
+func minTime(a, b time.Time) time.Time {
+	if b.Before(a) {
+		return b
+	}
+	return a
+}
+
+func decimalOrZero(d *decimal.Decimal) decimal.Decimal {
+	if d == nil {
+		return decimal.Zero
+	}
+	return *d
+}
@@ -0,0 +1,250 @@
+package analytics
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type mockAdSetRepository struct {
+	adSets []entity.AdSet
+}
+
+func (m *mockAdSetRepository) Create(ctx context.Context, adSet *entity.AdSet) error { return nil }
+func (m *mockAdSetRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.AdSet, error) {
+	return nil, nil
+}
+func (m *mockAdSetRepository) GetByPlatformID(ctx context.Context, campaignID uuid.UUID, platformAdSetID string) (*entity.AdSet, error) {
+	return nil, nil
+}
+func (m *mockAdSetRepository) Update(ctx context.Context, adSet *entity.AdSet) error { return nil }
+func (m *mockAdSetRepository) Delete(ctx context.Context, id uuid.UUID) error        { return nil }
+func (m *mockAdSetRepository) List(ctx context.Context, filter entity.AdSetFilter) ([]entity.AdSet, int64, error) {
+	return m.adSets, int64(len(m.adSets)), nil
+}
+func (m *mockAdSetRepository) ListByCampaign(ctx context.Context, campaignID uuid.UUID) ([]entity.AdSet, error) {
+	var adSets []entity.AdSet
+	for _, a := range m.adSets {
+		if a.CampaignID == campaignID {
+			adSets = append(adSets, a)
+		}
+	}
+	return adSets, nil
+}
+func (m *mockAdSetRepository) Upsert(ctx context.Context, adSet *entity.AdSet) error { return nil }
+func (m *mockAdSetRepository) BulkUpsert(ctx context.Context, adSets []entity.AdSet) error {
+	return nil
+}
+
+func decimalPtr(v int64) *decimal.Decimal {
+	d := decimal.NewFromInt(v)
+	return &d
+}
+
+// dailySpend spends amount on every day from start to end inclusive
+func dailySpend(start, end time.Time, amount int64) map[time.Time]decimal.Decimal {
+	spend := make(map[time.Time]decimal.Decimal)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		spend[day] = decimal.NewFromInt(amount)
+	}
+	return spend
+}
+
+func TestBudgetPacing(t *testing.T) {
+	start := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 9, 30, 0, 0, 0, 0, time.UTC)
+	today := time.Date(2024, 9, 11, 0, 0, 0, 0, time.UTC) // 10 days elapsed, 20 remaining
+
+	tests := []struct {
+		name      string
+		spend     map[time.Time]decimal.Decimal
+		projected int64
+		status    entity.PacingStatus
+	}{
+		{"on budget", dailySpend(start, today.AddDate(0, 0, -1), 100), 3000, entity.PacingStatusOnTrack},
+		{"overspending", dailySpend(start, today.AddDate(0, 0, -1), 150), 4500, entity.PacingStatusOverspending},
+		{"underspending", dailySpend(start, today.AddDate(0, 0, -1), 50), 1500, entity.PacingStatusUnderspending},
+		{
+			// Spend picked up in the last week: the run rate only averages recent days
+			"recent run rate",
+			func() map[time.Time]decimal.Decimal {
+				spend := dailySpend(start, start.AddDate(0, 0, 2), 20)
+				for day, amount := range dailySpend(start.AddDate(0, 0, 3), today.AddDate(0, 0, -1), 140) {
+					spend[day] = amount
+				}
+				return spend
+			}(),
+			60 + 980 + 140*20, entity.PacingStatusOverspending,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pacing := budgetPacing(entity.PacingPeriodMonthToDate, start, end, decimal.NewFromInt(3000), tt.spend, today)
+
+			if pacing.DaysElapsed != 10 || pacing.DaysRemaining != 20 {
+				t.Errorf("days elapsed/remaining = %d/%d, want 10/20", pacing.DaysElapsed, pacing.DaysRemaining)
+			}
+			if !pacing.ExpectedSpend.Equal(decimal.NewFromInt(1000)) {
+				t.Errorf("ExpectedSpend = %v, want 1000", pacing.ExpectedSpend)
+			}
+			if !pacing.ProjectedSpend.Equal(decimal.NewFromInt(tt.projected)) {
+				t.Errorf("ProjectedSpend = %v, want %d", pacing.ProjectedSpend, tt.projected)
+			}
+			if pacing.Status != tt.status {
+				t.Errorf("Status = %q, want %q", pacing.Status, tt.status)
+			}
+		})
+	}
+
+	// Today's spend is partial and ignored; nothing has elapsed on the first day
+	pacing := budgetPacing(entity.PacingPeriodMonthToDate, start, end, decimal.NewFromInt(3000), dailySpend(start, start, 500), start)
+	if pacing.Status != entity.PacingStatusNotStarted || !pacing.Spend.IsZero() {
+		t.Errorf("first day pacing = %+v, want not started", pacing)
+	}
+
+	// After the end, spend is final
+	pacing = budgetPacing(entity.PacingPeriodLifetime, start, end, decimal.NewFromInt(3000), dailySpend(start, end, 100), end.AddDate(0, 0, 5))
+	if pacing.DaysRemaining != 0 || !pacing.ProjectedSpend.Equal(decimal.NewFromInt(3000)) || pacing.Status != entity.PacingStatusOnTrack {
+		t.Errorf("ended pacing = %+v", pacing)
+	}
+}
+
+func TestPacePeriods_LifetimeBudget(t *testing.T) {
+	// A 61-day flight from mid-August with a 6100 lifetime budget is 100 a day
+	start := time.Date(2024, 8, 16, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 10, 15, 0, 0, 0, 0, time.UTC)
+	today := time.Date(2024, 10, 6, 0, 0, 0, 0, time.UTC)
+
+	monthToDate, flight := pacePeriods(nil, decimalPtr(6100), start, &end, dailySpend(start, today, 100), today)
+
+	if flight == nil || !flight.Budget.Equal(decimal.NewFromInt(6100)) || flight.Status != entity.PacingStatusOnTrack {
+		t.Fatalf("unexpected lifetime pacing %+v", flight)
+	}
+
+	// October runs until the flight ends on the 15th
+	if monthToDate == nil {
+		t.Fatal("expected month-to-date pacing")
+	}
+	if !monthToDate.EndDate.Equal(end) || !monthToDate.Budget.Equal(decimal.NewFromInt(1500)) {
+		t.Errorf("month-to-date end/budget = %v/%v, want %v/1500", monthToDate.EndDate, monthToDate.Budget, end)
+	}
+	if monthToDate.DaysElapsed != 5 || !monthToDate.Spend.Equal(decimal.NewFromInt(500)) {
+		t.Errorf("month-to-date elapsed/spend = %d/%v, want 5/500", monthToDate.DaysElapsed, monthToDate.Spend)
+	}
+
+	// Without an end date a lifetime budget cannot be paced
+	monthToDate, flight = pacePeriods(nil, decimalPtr(6100), start, nil, nil, today)
+	if monthToDate != nil || flight != nil {
+		t.Errorf("expected no pacing without an end date, got %+v / %+v", monthToDate, flight)
+	}
+}
+
+func TestGetPacing(t *testing.T) {
+	service, metricsRepo, campaignRepo := createTestService()
+	ctx := context.Background()
+
+	orgID := uuid.New()
+	today := time.Date(2024, 9, 11, 0, 0, 0, 0, time.UTC)
+	monthStart := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+
+	onTrack := createTestCampaign(orgID, entity.PlatformMeta)
+	onTrack.DailyBudget = decimalPtr(100)
+	onTrack.BudgetCurrency = "MYR"
+
+	over := createTestCampaign(orgID, entity.PlatformGoogle)
+	over.DailyBudget = decimalPtr(100)
+	over.BudgetCurrency = "MYR"
+
+	noBudget := createTestCampaign(orgID, entity.PlatformTikTok)
+
+	paused := createTestCampaign(orgID, entity.PlatformMeta)
+	paused.Status = entity.CampaignStatusPaused
+	paused.DailyBudget = decimalPtr(100)
+
+	// Ad set budget optimization: the budget lives on the ad sets
+	adSetBudgeted := createTestCampaign(orgID, entity.PlatformMeta)
+	adSetBudgeted.BudgetCurrency = "MYR"
+	service.SetAdSetRepository(&mockAdSetRepository{adSets: []entity.AdSet{
+		{BaseEntity: entity.BaseEntity{ID: uuid.New()}, CampaignID: adSetBudgeted.ID, Status: entity.CampaignStatusActive, DailyBudget: decimalPtr(30)},
+		{BaseEntity: entity.BaseEntity{ID: uuid.New()}, CampaignID: adSetBudgeted.ID, Status: entity.CampaignStatusActive, DailyBudget: decimalPtr(20)},
+		{BaseEntity: entity.BaseEntity{ID: uuid.New()}, CampaignID: adSetBudgeted.ID, Status: entity.CampaignStatusPaused, DailyBudget: decimalPtr(500)},
+	}})
+
+	campaignRepo.campaigns = []entity.Campaign{onTrack, over, noBudget, paused, adSetBudgeted}
+	for id, amount := range map[uuid.UUID]float64{onTrack.ID: 100, over.ID: 200, adSetBudgeted.ID: 50} {
+		for day := monthStart; day.Before(today); day = day.AddDate(0, 0, 1) {
+			metricsRepo.campaignMetrics[id] = append(metricsRepo.campaignMetrics[id],
+				createTestMetric(id, orgID, entity.PlatformMeta, day, amount, 0, 1000, 10, 0))
+		}
+	}
+
+	report, err := service.getPacing(ctx, orgID, today.Add(9*time.Hour), "", false)
+	if err != nil {
+		t.Fatalf("getPacing() error = %v", err)
+	}
+
+	if len(report.Campaigns) != 3 {
+		t.Fatalf("len(Campaigns) = %d, want 3", len(report.Campaigns))
+	}
+	if report.Campaigns[0].CampaignID != over.ID || report.Campaigns[0].Status != entity.PacingStatusOverspending {
+		t.Errorf("expected the overspending campaign first, got %+v", report.Campaigns[0])
+	}
+	if report.Overspending != 1 || report.Underspending != 0 {
+		t.Errorf("overspending/underspending = %d/%d, want 1/0", report.Overspending, report.Underspending)
+	}
+	// 3000 + 3000 + 1500 budgeted for September
+	if !report.MonthBudget.Equal(decimal.NewFromInt(7500)) {
+		t.Errorf("MonthBudget = %v, want 7500", report.MonthBudget)
+	}
+
+	for _, row := range report.Campaigns {
+		if row.CampaignID == adSetBudgeted.ID {
+			if row.BudgetSource != budgetSourceAdSets || !row.DailyBudget.Equal(decimal.NewFromInt(50)) {
+				t.Errorf("ad set budgeted campaign source/budget = %s/%v, want ad_sets/50", row.BudgetSource, row.DailyBudget)
+			}
+			if row.Status != entity.PacingStatusOnTrack {
+				t.Errorf("ad set budgeted campaign status = %s, want on_track", row.Status)
+			}
+		}
+	}
+
+	flagged, err := service.getPacing(ctx, orgID, today, "", true)
+	if err != nil {
+		t.Fatalf("getPacing(flagged) error = %v", err)
+	}
+	if len(flagged.Campaigns) != 1 || flagged.Campaigns[0].CampaignID != over.ID {
+		t.Errorf("expected only the overspending campaign, got %d", len(flagged.Campaigns))
+	}
+}
+
+func TestGetCampaignPacing_NotFound(t *testing.T) {
+	service, _, campaignRepo := createTestService()
+
+	campaign := createTestCampaign(uuid.New(), entity.PlatformMeta)
+	campaignRepo.campaigns = []entity.Campaign{campaign}
+
+	if _, err := service.GetCampaignPacing(context.Background(), uuid.New(), campaign.ID, ""); err == nil {
+		t.Error("expected error for another organization's campaign")
+	}
+}
+
+func TestWorstPacingStatus(t *testing.T) {
+	over := &entity.BudgetPacing{Status: entity.PacingStatusOverspending}
+	under := &entity.BudgetPacing{Status: entity.PacingStatusUnderspending}
+
+	if got := worstPacingStatus(under, over); got != entity.PacingStatusOverspending {
+		t.Errorf("worstPacingStatus() = %q, want overspending", got)
+	}
+	if got := worstPacingStatus(nil, nil); got != entity.PacingStatusNoBudget {
+		t.Errorf("worstPacingStatus(nil) = %q, want no_budget", got)
+	}
+	if math.Abs(pacingDeviation(entity.CampaignPacing{MonthToDate: &entity.BudgetPacing{ProjectedPct: 80}})-20) > 1e-9 {
+		t.Error("expected a deviation of 20")
+	}
+}
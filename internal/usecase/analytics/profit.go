@@ -0,0 +1,333 @@
+package analytics
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// maxSKUCostRows caps the size of a single SKU cost upload
+const maxSKUCostRows = 50000
+
+// SetProfitRepositories sets the repositories used for profitability analytics
+func (s *Service) SetProfitRepositories(salesSummaryRepo repository.SalesSummaryRepository, skuCostRepo repository.SKUCostRepository) {
+	s.salesSummaryRepo = salesSummaryRepo
+	s.skuCostRepo = skuCostRepo
+}
+
+// SKUCostImportResult summarizes a SKU cost upload
+type SKUCostImportResult struct {
+	Imported int `json:"imported"`
+}
+
+// GetProfitability returns profit after ad spend, COGS and marketplace fees per day,
+// platform and campaign, converted to targetCurrency. Order profit is credited to
+// campaigns with the given attribution model.
+func (s *Service) GetProfitability(ctx context.Context, orgID uuid.UUID, dateRange entity.DateRange, model entity.AttributionModel, targetCurrency string) (*entity.ProfitabilityReport, error) {
+	if s.salesSummaryRepo == nil {
+		return nil, errors.ErrInternal("Profitability is not configured")
+	}
+	if model == "" {
+		model = entity.AttributionModelLastClick
+	}
+	if !model.IsValid() {
+		return nil, errors.ErrValidation("Unsupported attribution model: " + string(model))
+	}
+	if targetCurrency == "" {
+		targetCurrency = "MYR"
+	}
+
+	daily := make(map[time.Time]*entity.ProfitSummary)
+	platforms := make(map[entity.Platform]*entity.ProfitSummary)
+	campaignRows := make(map[uuid.UUID]*entity.ProfitSummary)
+	total := &entity.ProfitSummary{}
+
+	dayRow := func(date time.Time) *entity.ProfitSummary {
+		day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.UTC)
+		row, ok := daily[day]
+		if !ok {
+			row = &entity.ProfitSummary{Date: &day}
+			daily[day] = row
+		}
+		return row
+	}
+	platformRow := func(platform entity.Platform) *entity.ProfitSummary {
+		row, ok := platforms[platform]
+		if !ok {
+			row = &entity.ProfitSummary{Platform: platform}
+			platforms[platform] = row
+		}
+		return row
+	}
+
+	summaries, err := s.salesSummaryRepo.List(ctx, orgID, dateRange)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load sales summaries", http.StatusInternalServerError)
+	}
+	for _, summary := range summaries {
+		sales := entity.ProfitSummary{
+			Orders:          float64(summary.ConfirmedOrders),
+			Revenue:         s.convertCurrencyAt(summary.NetRevenue, summary.Currency, targetCurrency, summary.SummaryDate),
+			Shipping:        s.convertCurrencyAt(summary.ShippingCollected, summary.Currency, targetCurrency, summary.SummaryDate),
+			COGS:            s.convertCurrencyAt(summary.TotalCOGS, summary.Currency, targetCurrency, summary.SummaryDate),
+			MarketplaceFees: s.convertCurrencyAt(summary.CommissionFees.Add(summary.ServiceFees), summary.Currency, targetCurrency, summary.SummaryDate),
+		}
+		for _, row := range []*entity.ProfitSummary{dayRow(summary.SummaryDate), platformRow(summary.Platform), total} {
+			addSales(row, sales)
+		}
+	}
+
+	campaigns, err := s.campaignRepo.ListByOrganization(ctx, orgID, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load campaigns", http.StatusInternalServerError)
+	}
+	for _, campaign := range campaigns {
+		metrics, err := s.metricsRepo.GetCampaignMetrics(ctx, campaign.ID, dateRange)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load campaign metrics", http.StatusInternalServerError)
+		}
+		if len(metrics) == 0 {
+			continue
+		}
+
+		campaignID := campaign.ID
+		row := &entity.ProfitSummary{
+			Platform:     campaign.Platform,
+			CampaignID:   &campaignID,
+			CampaignName: campaign.PlatformCampaignName,
+		}
+		campaignRows[campaign.ID] = row
+
+		for _, m := range metrics {
+			spend := s.convertCurrencyAt(m.Spend, m.Currency, targetCurrency, m.MetricDate)
+			for _, r := range []*entity.ProfitSummary{row, dayRow(m.MetricDate), platformRow(campaign.Platform), total} {
+				r.AdSpend = r.AdSpend.Add(spend)
+			}
+		}
+	}
+
+	if s.attributionRepo != nil {
+		totals, err := s.attributionRepo.SummarizeByCampaign(ctx, orgID, model, dateRange)
+		if err != nil {
+			return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to summarize attribution", http.StatusInternalServerError)
+		}
+		for _, t := range totals {
+			row, ok := campaignRows[t.CampaignID]
+			if !ok {
+				campaign, err := s.campaignRepo.GetByID(ctx, t.CampaignID)
+				if err != nil || campaign == nil {
+					continue
+				}
+				campaignID := campaign.ID
+				row = &entity.ProfitSummary{
+					Platform:     campaign.Platform,
+					CampaignID:   &campaignID,
+					CampaignName: campaign.PlatformCampaignName,
+				}
+				campaignRows[campaign.ID] = row
+			}
+
+			orders, _ := t.Orders.Float64()
+			addSales(row, entity.ProfitSummary{
+				Orders:          orders,
+				Revenue:         s.convertCurrency(t.Revenue, t.Currency, targetCurrency),
+				Shipping:        s.convertCurrency(t.Shipping, t.Currency, targetCurrency),
+				COGS:            s.convertCurrency(t.COGS, t.Currency, targetCurrency),
+				MarketplaceFees: s.convertCurrency(t.Fees, t.Currency, targetCurrency),
+			})
+		}
+	}
+
+	report := &entity.ProfitabilityReport{
+		Currency:   targetCurrency,
+		DateRange:  dateRange,
+		Model:      model,
+		Daily:      finalizeProfitRows(daily),
+		ByPlatform: finalizeProfitRows(platforms),
+		ByCampaign: finalizeProfitRows(campaignRows),
+	}
+	total.Finalize()
+	report.Total = *total
+
+	sort.Slice(report.Daily, func(i, j int) bool {
+		return report.Daily[i].Date.Before(*report.Daily[j].Date)
+	})
+	sort.Slice(report.ByPlatform, func(i, j int) bool {
+		return report.ByPlatform[i].Platform < report.ByPlatform[j].Platform
+	})
+	sort.Slice(report.ByCampaign, func(i, j int) bool {
+		return report.ByCampaign[i].NetProfit.GreaterThan(report.ByCampaign[j].NetProfit)
+	})
+
+	return report, nil
+}
+
+// addSales adds the order counts, revenue and costs of sales to row
+func addSales(row *entity.ProfitSummary, sales entity.ProfitSummary) {
+	row.Orders += sales.Orders
+	row.Revenue = row.Revenue.Add(sales.Revenue)
+	row.Shipping = row.Shipping.Add(sales.Shipping)
+	row.COGS = row.COGS.Add(sales.COGS)
+	row.MarketplaceFees = row.MarketplaceFees.Add(sales.MarketplaceFees)
+}
+
+// finalizeProfitRows derives the profit figures of each row and returns them as a slice
+func finalizeProfitRows[K comparable](rows map[K]*entity.ProfitSummary) []entity.ProfitSummary {
+	results := make([]entity.ProfitSummary, 0, len(rows))
+	for _, row := range rows {
+		row.Finalize()
+		results = append(results, *row)
+	}
+	return results
+}
+
+// UploadSKUCosts imports a SKU cost CSV and rebuilds the sales summaries of the
+// days with orders for the uploaded SKUs, so that their COGS reflect the new costs
+func (s *Service) UploadSKUCosts(ctx context.Context, orgID uuid.UUID, r io.Reader, defaultCurrency string) (*SKUCostImportResult, error) {
+	if s.salesSummaryRepo == nil || s.skuCostRepo == nil {
+		return nil, errors.ErrInternal("Profitability is not configured")
+	}
+
+	costs, err := ParseSKUCostCSV(r, defaultCurrency)
+	if err != nil {
+		return nil, err
+	}
+
+	skus := make([]string, 0, len(costs))
+	for i := range costs {
+		costs[i].BaseEntity = entity.NewBaseEntity()
+		costs[i].OrganizationID = orgID
+		skus = append(skus, costs[i].SKU)
+	}
+
+	if err := s.skuCostRepo.BulkUpsert(ctx, costs); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to save SKU costs", http.StatusInternalServerError)
+	}
+	if err := s.salesSummaryRepo.RebuildForSKUs(ctx, orgID, skus); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to rebuild sales summaries", http.StatusInternalServerError)
+	}
+
+	return &SKUCostImportResult{Imported: len(costs)}, nil
+}
+
+// ListSKUCosts returns an organization's uploaded SKU costs
+func (s *Service) ListSKUCosts(ctx context.Context, orgID uuid.UUID) ([]entity.SKUCost, error) {
+	if s.skuCostRepo == nil {
+		return nil, errors.ErrInternal("Profitability is not configured")
+	}
+
+	costs, err := s.skuCostRepo.List(ctx, orgID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load SKU costs", http.StatusInternalServerError)
+	}
+	return costs, nil
+}
+
+// ParseSKUCostCSV reads a SKU cost table. The header row must have "sku" and
+// "unit_cost" columns and may have a "currency" column; rows without a currency
+// use defaultCurrency. A SKU listed twice in the same currency keeps its last cost.
+func ParseSKUCostCSV(r io.Reader, defaultCurrency string) ([]entity.SKUCost, error) {
+	if defaultCurrency == "" {
+		defaultCurrency = "MYR"
+	}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.ErrValidation("SKU cost file is empty")
+	}
+	if err != nil {
+		return nil, errors.ErrValidation("Invalid CSV: " + err.Error())
+	}
+
+	columns := map[string]int{"sku": -1, "unit_cost": -1, "currency": -1}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if _, ok := columns[name]; ok {
+			columns[name] = i
+		}
+	}
+	if columns["sku"] < 0 || columns["unit_cost"] < 0 {
+		return nil, errors.ErrValidation("CSV header must include sku and unit_cost columns")
+	}
+
+	field := func(record []string, column string) string {
+		i := columns[column]
+		if i < 0 || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	type costKey struct{ sku, currency string }
+	index := make(map[costKey]int)
+	var costs []entity.SKUCost
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, errors.ErrValidation(fmt.Sprintf("Invalid CSV on line %d: %v", line, err))
+		}
+
+		sku := field(record, "sku")
+		rawCost := field(record, "unit_cost")
+		if sku == "" && rawCost == "" {
+			continue
+		}
+		if sku == "" {
+			return nil, errors.ErrValidation(fmt.Sprintf("Line %d: sku is required", line))
+		}
+		if len(sku) > 100 {
+			return nil, errors.ErrValidation(fmt.Sprintf("Line %d: sku must be at most 100 characters", line))
+		}
+
+		unitCost, err := decimal.NewFromString(rawCost)
+		if err != nil {
+			return nil, errors.ErrValidation(fmt.Sprintf("Line %d: invalid unit_cost %q", line, rawCost))
+		}
+		if unitCost.IsNegative() {
+			return nil, errors.ErrValidation(fmt.Sprintf("Line %d: unit_cost cannot be negative", line))
+		}
+
+		currency := strings.ToUpper(field(record, "currency"))
+		if currency == "" {
+			currency = strings.ToUpper(defaultCurrency)
+		}
+		if len(currency) != 3 {
+			return nil, errors.ErrValidation(fmt.Sprintf("Line %d: invalid currency %q", line, currency))
+		}
+
+		cost := entity.SKUCost{SKU: sku, UnitCost: unitCost, Currency: currency}
+		key := costKey{sku, currency}
+		if i, ok := index[key]; ok {
+			costs[i] = cost
+			continue
+		}
+		if len(costs) >= maxSKUCostRows {
+			return nil, errors.ErrValidation(fmt.Sprintf("SKU cost file cannot have more than %d rows", maxSKUCostRows))
+		}
+		index[key] = len(costs)
+		costs = append(costs, cost)
+	}
+
+	if len(costs) == 0 {
+		return nil, errors.ErrValidation("SKU cost file has no rows")
+	}
+	return costs, nil
+}
@@ -0,0 +1,227 @@
+package analytics
+
+import (
+	"context"
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type mockSalesSummaryRepository struct {
+	summaries   []entity.SalesSummaryDaily
+	rebuiltSKUs []string
+}
+
+func (m *mockSalesSummaryRepository) RebuildDaily(ctx context.Context, orgID uuid.UUID, platform entity.Platform, dates []time.Time) error {
+	return nil
+}
+
+func (m *mockSalesSummaryRepository) RebuildForSKUs(ctx context.Context, orgID uuid.UUID, skus []string) error {
+	m.rebuiltSKUs = append(m.rebuiltSKUs, skus...)
+	return nil
+}
+
+func (m *mockSalesSummaryRepository) List(ctx context.Context, orgID uuid.UUID, dateRange entity.DateRange) ([]entity.SalesSummaryDaily, error) {
+	return m.summaries, nil
+}
+
+type mockSKUCostRepository struct {
+	costs []entity.SKUCost
+}
+
+func (m *mockSKUCostRepository) BulkUpsert(ctx context.Context, costs []entity.SKUCost) error {
+	m.costs = append(m.costs, costs...)
+	return nil
+}
+
+func (m *mockSKUCostRepository) List(ctx context.Context, orgID uuid.UUID) ([]entity.SKUCost, error) {
+	return m.costs, nil
+}
+
+func TestProfitSummary_Finalize(t *testing.T) {
+	summary := entity.ProfitSummary{
+		Revenue:         decimal.NewFromInt(1000),
+		Shipping:        decimal.NewFromInt(50),
+		COGS:            decimal.NewFromInt(400),
+		MarketplaceFees: decimal.NewFromInt(50),
+		AdSpend:         decimal.NewFromInt(200),
+	}
+	summary.Finalize()
+
+	if !summary.GrossProfit.Equal(decimal.NewFromInt(500)) {
+		t.Errorf("GrossProfit = %v, want 500", summary.GrossProfit)
+	}
+	if !summary.NetProfit.Equal(decimal.NewFromInt(300)) {
+		t.Errorf("NetProfit = %v, want 300", summary.NetProfit)
+	}
+	if summary.POAS != 2.5 {
+		t.Errorf("POAS = %f, want 2.5", summary.POAS)
+	}
+	if summary.ContributionMarginPct != 30 {
+		t.Errorf("ContributionMarginPct = %f, want 30", summary.ContributionMarginPct)
+	}
+
+	empty := entity.ProfitSummary{}
+	empty.Finalize()
+	if empty.POAS != 0 || empty.ContributionMarginPct != 0 {
+		t.Errorf("expected zero ratios without spend or revenue, got %+v", empty)
+	}
+}
+
+func TestParseSKUCostCSV(t *testing.T) {
+	input := "\ufeffSKU, Unit_Cost ,Currency\n" +
+		"TMB-01,12.50,\n" +
+		"TMB-02,3,sgd\n" +
+		",,\n" +
+		"TMB-01,13.00,MYR\n"
+
+	costs, err := ParseSKUCostCSV(strings.NewReader(input), "MYR")
+	if err != nil {
+		t.Fatalf("ParseSKUCostCSV() error = %v", err)
+	}
+
+	if len(costs) != 2 {
+		t.Fatalf("len(costs) = %d, want 2", len(costs))
+	}
+	if costs[0].SKU != "TMB-01" || !costs[0].UnitCost.Equal(decimal.NewFromInt(13)) || costs[0].Currency != "MYR" {
+		t.Errorf("unexpected first cost %+v", costs[0])
+	}
+	if costs[1].SKU != "TMB-02" || !costs[1].UnitCost.Equal(decimal.NewFromInt(3)) || costs[1].Currency != "SGD" {
+		t.Errorf("unexpected second cost %+v", costs[1])
+	}
+}
+
+func TestParseSKUCostCSV_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", "empty"},
+		{"missing column", "sku,price\nA,1\n", "header"},
+		{"no rows", "sku,unit_cost\n", "no rows"},
+		{"missing sku", "sku,unit_cost\n,5\n", "Line 2"},
+		{"bad cost", "sku,unit_cost\nA,1\nB,abc\n", "Line 3"},
+		{"negative cost", "sku,unit_cost\nA,-1\n", "negative"},
+		{"bad currency", "sku,unit_cost,currency\nA,1,RINGGIT\n", "currency"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseSKUCostCSV(strings.NewReader(tt.input), "MYR")
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to mention %q", err.Error(), tt.want)
+			}
+		})
+	}
+}
+
+func TestUploadSKUCosts(t *testing.T) {
+	service, _, _ := createTestService()
+	salesSummaryRepo := &mockSalesSummaryRepository{}
+	skuCostRepo := &mockSKUCostRepository{}
+	service.SetProfitRepositories(salesSummaryRepo, skuCostRepo)
+
+	orgID := uuid.New()
+	result, err := service.UploadSKUCosts(context.Background(), orgID, strings.NewReader("sku,unit_cost\nA,1\nB,2\n"), "")
+	if err != nil {
+		t.Fatalf("UploadSKUCosts() error = %v", err)
+	}
+
+	if result.Imported != 2 || len(skuCostRepo.costs) != 2 {
+		t.Fatalf("imported %d, saved %d, want 2", result.Imported, len(skuCostRepo.costs))
+	}
+	if skuCostRepo.costs[0].OrganizationID != orgID || skuCostRepo.costs[0].ID == uuid.Nil {
+		t.Errorf("expected costs to be scoped to the organization, got %+v", skuCostRepo.costs[0])
+	}
+	if len(salesSummaryRepo.rebuiltSKUs) != 2 {
+		t.Errorf("rebuilt summaries for %v, want A and B", salesSummaryRepo.rebuiltSKUs)
+	}
+}
+
+func TestGetProfitability(t *testing.T) {
+	service, metricsRepo, campaignRepo := createTestService()
+	ctx := context.Background()
+
+	orgID := uuid.New()
+	day1 := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+
+	campaign := createTestCampaign(orgID, entity.PlatformShopee)
+	campaign.PlatformCampaignName = "Shopee Search"
+	campaignRepo.campaigns = []entity.Campaign{campaign}
+	metricsRepo.campaignMetrics[campaign.ID] = []entity.CampaignMetricsDaily{
+		createTestMetric(campaign.ID, orgID, entity.PlatformShopee, day1, 100, 500, 1000, 50, 5),
+		createTestMetric(campaign.ID, orgID, entity.PlatformShopee, day2, 50, 200, 500, 20, 2),
+	}
+
+	salesSummaryRepo := &mockSalesSummaryRepository{summaries: []entity.SalesSummaryDaily{
+		{
+			OrganizationID: orgID, Platform: entity.PlatformShopee, SummaryDate: day1, ConfirmedOrders: 10,
+			NetRevenue: decimal.NewFromInt(1000), ShippingCollected: decimal.NewFromInt(50), TotalCOGS: decimal.NewFromInt(400),
+			CommissionFees: decimal.NewFromInt(30), ServiceFees: decimal.NewFromInt(20), Currency: "MYR",
+		},
+		{
+			OrganizationID: orgID, Platform: entity.PlatformShopee, SummaryDate: day2, ConfirmedOrders: 2,
+			NetRevenue: decimal.NewFromInt(100), TotalCOGS: decimal.NewFromInt(80), Currency: "MYR",
+		},
+	}}
+	service.SetProfitRepositories(salesSummaryRepo, &mockSKUCostRepository{})
+	service.SetAttributionRepositories(nil, &mockAttributionRepository{totals: []entity.AttributionTotal{{
+		CampaignID: campaign.ID, Currency: "MYR", Orders: decimal.NewFromFloat(4.5),
+		Revenue: decimal.NewFromInt(450), Shipping: decimal.NewFromInt(20), COGS: decimal.NewFromInt(180), Fees: decimal.NewFromInt(25),
+	}}})
+
+	report, err := service.GetProfitability(ctx, orgID, entity.DateRange{StartDate: day1, EndDate: day2}, "", "")
+	if err != nil {
+		t.Fatalf("GetProfitability() error = %v", err)
+	}
+
+	if report.Currency != "MYR" || report.Model != entity.AttributionModelLastClick {
+		t.Errorf("Currency/Model = %s/%s, want MYR/last_click", report.Currency, report.Model)
+	}
+
+	// 1100 revenue - 50 shipping - 480 COGS - 50 fees = 520 gross, - 150 spend = 370 net
+	if !report.Total.GrossProfit.Equal(decimal.NewFromInt(520)) || !report.Total.NetProfit.Equal(decimal.NewFromInt(370)) {
+		t.Errorf("Total gross/net = %v/%v, want 520/370", report.Total.GrossProfit, report.Total.NetProfit)
+	}
+	if math.Abs(report.Total.POAS-520.0/150) > 1e-9 {
+		t.Errorf("Total POAS = %f, want %f", report.Total.POAS, 520.0/150)
+	}
+
+	if len(report.Daily) != 2 || !report.Daily[0].Date.Equal(day1) {
+		t.Fatalf("unexpected daily rows %+v", report.Daily)
+	}
+	// Day 2 loses money: 100 - 80 - 50 spend
+	if !report.Daily[1].NetProfit.Equal(decimal.NewFromInt(-30)) {
+		t.Errorf("day 2 NetProfit = %v, want -30", report.Daily[1].NetProfit)
+	}
+
+	if len(report.ByPlatform) != 1 || report.ByPlatform[0].Platform != entity.PlatformShopee {
+		t.Fatalf("unexpected platform rows %+v", report.ByPlatform)
+	}
+
+	if len(report.ByCampaign) != 1 {
+		t.Fatalf("len(ByCampaign) = %d, want 1", len(report.ByCampaign))
+	}
+	row := report.ByCampaign[0]
+	if row.CampaignName != "Shopee Search" || row.Orders != 4.5 {
+		t.Errorf("unexpected campaign row %+v", row)
+	}
+	// 450 - 20 - 180 - 25 = 225 gross, - 150 spend = 75 net
+	if !row.GrossProfit.Equal(decimal.NewFromInt(225)) || !row.NetProfit.Equal(decimal.NewFromInt(75)) || row.POAS != 1.5 {
+		t.Errorf("campaign gross/net/POAS = %v/%v/%f, want 225/75/1.5", row.GrossProfit, row.NetProfit, row.POAS)
+	}
+
+	if _, err := service.GetProfitability(ctx, orgID, entity.DateRange{StartDate: day1, EndDate: day2}, "bogus", ""); err == nil {
+		t.Error("expected error for an unsupported model")
+	}
+}
@@ -0,0 +1,219 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// ReportArtifactRetention is how long generated report files remain downloadable
+const ReportArtifactRetention = 72 * time.Hour
+
+// ErrCodeReportExpired is returned when a report artifact is past its retention period
+const ErrCodeReportExpired = "REPORT_EXPIRED"
+
+// ReportJobListener is notified when a queued report has been built and can be downloaded
+type ReportJobListener interface {
+	ReportJobCompleted(ctx context.Context, job *entity.ReportJob)
+}
+
+// AddReportJobListener adds a listener notified when a report job completes
+func (s *Service) AddReportJobListener(listener ReportJobListener) {
+	s.reportListeners = append(s.reportListeners, listener)
+}
+
+// SetReportJobRepository sets the repository used for asynchronous report jobs
+func (s *Service) SetReportJobRepository(reportJobRepo repository.ReportJobRepository) {
+	s.reportJobRepo = reportJobRepo
+}
+
+// ReportJobRequest describes a report to be generated asynchronously
+type ReportJobRequest struct {
+	OrganizationID uuid.UUID
+	RequestedBy    *uuid.UUID
+	DateRange      entity.DateRange
+	Format         entity.ReportFormat
+	WhiteLabel     bool
+}
+
+// SubmitReportJob queues a report for generation by the worker
+func (s *Service) SubmitReportJob(ctx context.Context, req ReportJobRequest) (*entity.ReportJob, error) {
+	if s.reportJobRepo == nil {
+		return nil, errors.ErrInternal("Report jobs are not configured")
+	}
+	if !req.Format.IsValid() {
+		return nil, errors.ErrValidation(fmt.Sprintf("Unsupported report format: %s", req.Format))
+	}
+	if req.DateRange.EndDate.Before(req.DateRange.StartDate) {
+		return nil, errors.ErrValidation("end_date must not be before start_date")
+	}
+
+	job := &entity.ReportJob{
+		BaseEntity:     entity.NewBaseEntity(),
+		OrganizationID: req.OrganizationID,
+		RequestedBy:    req.RequestedBy,
+		Format:         req.Format,
+		Status:         entity.ReportJobStatusPending,
+		DateRangeStart: req.DateRange.StartDate,
+		DateRangeEnd:   req.DateRange.EndDate,
+		WhiteLabel:     req.WhiteLabel,
+		MaxAttempts:    3,
+	}
+
+	if err := s.reportJobRepo.Create(ctx, job); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to create report job", http.StatusInternalServerError)
+	}
+
+	return job, nil
+}
+
+// GetReportJob returns a report job belonging to the organization
+func (s *Service) GetReportJob(ctx context.Context, orgID, jobID uuid.UUID) (*entity.ReportJob, error) {
+	if s.reportJobRepo == nil {
+		return nil, errors.ErrInternal("Report jobs are not configured")
+	}
+
+	job, err := s.reportJobRepo.GetByID(ctx, jobID)
+	if err != nil || job == nil || job.OrganizationID != orgID {
+		return nil, errors.ErrNotFound("Report")
+	}
+
+	// Artifacts are removed by the cleanup task; report expiry as soon as it passes
+	if job.Status == entity.ReportJobStatusCompleted && job.IsExpired() {
+		job.Status = entity.ReportJobStatusExpired
+	}
+
+	return job, nil
+}
+
+// ListReportJobs returns the most recent report jobs for the organization
+func (s *Service) ListReportJobs(ctx context.Context, orgID uuid.UUID, limit int) ([]entity.ReportJob, error) {
+	if s.reportJobRepo == nil {
+		return nil, errors.ErrInternal("Report jobs are not configured")
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return s.reportJobRepo.ListByOrganization(ctx, orgID, limit)
+}
+
+// GetReportArtifact returns a completed report job and its generated file
+func (s *Service) GetReportArtifact(ctx context.Context, orgID, jobID uuid.UUID) (*entity.ReportJob, []byte, error) {
+	job, err := s.GetReportJob(ctx, orgID, jobID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch job.Status {
+	case entity.ReportJobStatusCompleted:
+	case entity.ReportJobStatusExpired:
+		return nil, nil, errors.New(ErrCodeReportExpired, "Report has expired, please generate it again", http.StatusGone)
+	case entity.ReportJobStatusFailed:
+		return nil, nil, errors.ErrConflict("Report generation failed").WithDetails(job.ErrorMessage)
+	default:
+		return nil, nil, errors.ErrConflict("Report is not ready yet")
+	}
+
+	artifact, err := s.reportJobRepo.GetArtifact(ctx, job.ID)
+	if err != nil {
+		return nil, nil, errors.New(ErrCodeReportExpired, "Report has expired, please generate it again", http.StatusGone)
+	}
+
+	return job, artifact.Content, nil
+}
+
+// ProcessNextReportJob claims and builds the next pending report. It returns false when
+// there was nothing to process.
+func (s *Service) ProcessNextReportJob(ctx context.Context, workerID string) (bool, error) {
+	if s.reportJobRepo == nil {
+		return false, fmt.Errorf("report job repository not configured")
+	}
+
+	job, err := s.reportJobRepo.ClaimNext(ctx, workerID)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim report job: %w", err)
+	}
+	if job == nil {
+		return false, nil
+	}
+
+	data, err := s.RenderReport(ctx, job.OrganizationID, job.DateRange(), job.Format, job.WhiteLabel)
+	if err != nil {
+		job.MarkFailed(err)
+		if updateErr := s.reportJobRepo.Update(ctx, job); updateErr != nil {
+			return true, fmt.Errorf("failed to record report job failure: %w", updateErr)
+		}
+		return true, fmt.Errorf("report job %s failed: %w", job.ID, err)
+	}
+
+	expiresAt := time.Now().Add(ReportArtifactRetention)
+	job.MarkCompleted(reportFileName(job), job.Format.ContentType(), int64(len(data)), expiresAt)
+
+	artifact := &entity.ReportArtifact{
+		ReportJobID: job.ID,
+		Content:     data,
+		ExpiresAt:   expiresAt,
+	}
+	if err := s.reportJobRepo.SaveArtifact(ctx, job, artifact); err != nil {
+		return true, fmt.Errorf("failed to store report artifact: %w", err)
+	}
+
+	for _, listener := range s.reportListeners {
+		listener.ReportJobCompleted(ctx, job)
+	}
+
+	return true, nil
+}
+
+// CleanupExpiredReports deletes expired report artifacts and returns how many were removed
+func (s *Service) CleanupExpiredReports(ctx context.Context) (int64, error) {
+	if s.reportJobRepo == nil {
+		return 0, nil
+	}
+	return s.reportJobRepo.DeleteExpired(ctx, time.Now())
+}
+
+// RequeueStaleReportJobs returns jobs whose worker stopped before finishing to
+// the queue, or fails them when they have no attempts left
+func (s *Service) RequeueStaleReportJobs(ctx context.Context, timeout time.Duration) (int64, error) {
+	if s.reportJobRepo == nil {
+		return 0, nil
+	}
+	return s.reportJobRepo.ResetStale(ctx, time.Now().Add(-timeout))
+}
+
+// RenderReport builds the report for the date range in the requested format
+func (s *Service) RenderReport(ctx context.Context, orgID uuid.UUID, dateRange entity.DateRange, format entity.ReportFormat, whiteLabel bool) ([]byte, error) {
+	if format == entity.ReportFormatPDF {
+		return s.RenderReportPDF(ctx, orgID, dateRange, whiteLabel)
+	}
+
+	report, err := s.GenerateReport(ctx, orgID, dateRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate report: %w", err)
+	}
+
+	switch format {
+	case entity.ReportFormatCSV:
+		return s.exportToCSV(report)
+	case entity.ReportFormatXLSX:
+		return s.exportToExcel(report)
+	case entity.ReportFormatJSON:
+		return json.Marshal(report)
+	default:
+		return nil, fmt.Errorf("unsupported report format: %s", format)
+	}
+}
+
+// reportFileName returns the download file name for a report job
+func reportFileName(job *entity.ReportJob) string {
+	return fmt.Sprintf("report_%s_%s.%s",
+		job.DateRangeStart.Format("2006-01-02"), job.DateRangeEnd.Format("2006-01-02"), job.Format)
+}
@@ -0,0 +1,368 @@
+package analytics
+
+import (
+	"context"
+	stderrors "errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type mockReportJobRepository struct {
+	mu        sync.Mutex
+	jobs      map[uuid.UUID]*entity.ReportJob
+	order     []uuid.UUID
+	artifacts map[uuid.UUID]*entity.ReportArtifact
+}
+
+func newMockReportJobRepo() *mockReportJobRepository {
+	return &mockReportJobRepository{
+		jobs:      make(map[uuid.UUID]*entity.ReportJob),
+		artifacts: make(map[uuid.UUID]*entity.ReportArtifact),
+	}
+}
+
+func (m *mockReportJobRepository) Create(ctx context.Context, job *entity.ReportJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *job
+	m.jobs[job.ID] = &stored
+	m.order = append(m.order, job.ID)
+	return nil
+}
+
+func (m *mockReportJobRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.ReportJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, stderrors.New("record not found")
+	}
+	copied := *job
+	return &copied, nil
+}
+
+func (m *mockReportJobRepository) Update(ctx context.Context, job *entity.ReportJob) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored := *job
+	m.jobs[job.ID] = &stored
+	return nil
+}
+
+func (m *mockReportJobRepository) ListByOrganization(ctx context.Context, orgID uuid.UUID, limit int) ([]entity.ReportJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var jobs []entity.ReportJob
+	for _, id := range m.order {
+		if job := m.jobs[id]; job.OrganizationID == orgID && len(jobs) < limit {
+			jobs = append(jobs, *job)
+		}
+	}
+	return jobs, nil
+}
+
+func (m *mockReportJobRepository) ClaimNext(ctx context.Context, workerID string) (*entity.ReportJob, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range m.order {
+		job := m.jobs[id]
+		if job.Status != entity.ReportJobStatusPending {
+			continue
+		}
+		now := time.Now()
+		job.Status = entity.ReportJobStatusRunning
+		job.WorkerID = workerID
+		job.Attempts++
+		job.StartedAt = &now
+		copied := *job
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *mockReportJobRepository) ResetStale(ctx context.Context, startedBefore time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var reset int64
+	for _, job := range m.jobs {
+		if job.Status != entity.ReportJobStatusRunning || job.StartedAt == nil || !job.StartedAt.Before(startedBefore) {
+			continue
+		}
+		job.WorkerID = ""
+		if job.Attempts < job.MaxAttempts {
+			job.Status = entity.ReportJobStatusPending
+		} else {
+			now := time.Now()
+			job.Status = entity.ReportJobStatusFailed
+			job.ErrorMessage = entity.ReportJobAbandonedMessage
+			job.CompletedAt = &now
+		}
+		reset++
+	}
+	return reset, nil
+}
+
+func (m *mockReportJobRepository) SaveArtifact(ctx context.Context, job *entity.ReportJob, artifact *entity.ReportArtifact) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.artifacts[artifact.ReportJobID] = artifact
+	stored := *job
+	m.jobs[job.ID] = &stored
+	return nil
+}
+
+func (m *mockReportJobRepository) GetArtifact(ctx context.Context, jobID uuid.UUID) (*entity.ReportArtifact, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	artifact, ok := m.artifacts[jobID]
+	if !ok {
+		return nil, stderrors.New("record not found")
+	}
+	return artifact, nil
+}
+
+func (m *mockReportJobRepository) DeleteExpired(ctx context.Context, now time.Time) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var deleted int64
+	for id, artifact := range m.artifacts {
+		if artifact.ExpiresAt.Before(now) {
+			delete(m.artifacts, id)
+			deleted++
+		}
+	}
+	return deleted, nil
+}
+
+func createReportJobTestService() (*Service, *mockReportJobRepository) {
+	service, metricsRepo, campaignRepo := createTestService()
+	metricsRepo.aggregated = &entity.AggregatedMetrics{
+		TotalSpend:   decimal.NewFromInt(1000),
+		TotalRevenue: decimal.NewFromInt(3000),
+		OverallROAS:  3,
+		Currency:     "MYR",
+	}
+	campaignRepo.summaries = []entity.CampaignSummary{
+		{ID: uuid.New(), Platform: entity.PlatformMeta, Name: "Spring Sale", TotalSpend: decimal.NewFromInt(1000)},
+	}
+
+	reportJobRepo := newMockReportJobRepo()
+	service.SetReportJobRepository(reportJobRepo)
+	return service, reportJobRepo
+}
+
+func TestSubmitReportJob_Validation(t *testing.T) {
+	service, _ := createReportJobTestService()
+	ctx := context.Background()
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		req  ReportJobRequest
+	}{
+		{
+			name: "unsupported format",
+			req: ReportJobRequest{
+				OrganizationID: uuid.New(),
+				DateRange:      entity.DateRange{StartDate: start, EndDate: start.AddDate(0, 0, 7)},
+				Format:         "docx",
+			},
+		},
+		{
+			name: "end before start",
+			req: ReportJobRequest{
+				OrganizationID: uuid.New(),
+				DateRange:      entity.DateRange{StartDate: start, EndDate: start.AddDate(0, 0, -1)},
+				Format:         entity.ReportFormatCSV,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := service.SubmitReportJob(ctx, tt.req)
+			var appErr *errors.AppError
+			if !stderrors.As(err, &appErr) || appErr.HTTPStatus != http.StatusBadRequest {
+				t.Errorf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReportJob_Lifecycle(t *testing.T) {
+	service, _ := createReportJobTestService()
+	ctx := context.Background()
+	orgID := uuid.New()
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	job, err := service.SubmitReportJob(ctx, ReportJobRequest{
+		OrganizationID: orgID,
+		DateRange:      entity.DateRange{StartDate: start, EndDate: start.AddDate(0, 0, 6)},
+		Format:         entity.ReportFormatCSV,
+	})
+	if err != nil {
+		t.Fatalf("SubmitReportJob failed: %v", err)
+	}
+	if job.Status != entity.ReportJobStatusPending {
+		t.Errorf("expected pending status, got %s", job.Status)
+	}
+
+	// Another organization cannot see the job
+	if _, err := service.GetReportJob(ctx, uuid.New(), job.ID); err == nil {
+		t.Error("expected not found for a different organization")
+	}
+
+	// Downloading before the worker runs is a conflict
+	_, _, err = service.GetReportArtifact(ctx, orgID, job.ID)
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.HTTPStatus != http.StatusConflict {
+		t.Fatalf("expected conflict before processing, got %v", err)
+	}
+
+	processed, err := service.ProcessNextReportJob(ctx, "worker-1")
+	if err != nil || !processed {
+		t.Fatalf("ProcessNextReportJob = %v, %v", processed, err)
+	}
+
+	status, err := service.GetReportJob(ctx, orgID, job.ID)
+	if err != nil {
+		t.Fatalf("GetReportJob failed: %v", err)
+	}
+	if !status.IsDownloadable() {
+		t.Fatalf("expected completed job, got status %s", status.Status)
+	}
+	if status.FileName != "report_2024-03-01_2024-03-07.csv" {
+		t.Errorf("unexpected file name %q", status.FileName)
+	}
+	if status.ExpiresAt == nil || status.ExpiresAt.Sub(time.Now()) > ReportArtifactRetention {
+		t.Errorf("unexpected expiry %v", status.ExpiresAt)
+	}
+
+	downloaded, data, err := service.GetReportArtifact(ctx, orgID, job.ID)
+	if err != nil {
+		t.Fatalf("GetReportArtifact failed: %v", err)
+	}
+	if downloaded.ContentType != "text/csv" || int64(len(data)) != downloaded.SizeBytes {
+		t.Errorf("unexpected artifact: %s, %d bytes", downloaded.ContentType, len(data))
+	}
+	if !strings.Contains(string(data), "Spring Sale") {
+		t.Error("report content is missing campaign rows")
+	}
+
+	// Queue is now empty
+	processed, err = service.ProcessNextReportJob(ctx, "worker-1")
+	if err != nil || processed {
+		t.Errorf("expected empty queue, got %v, %v", processed, err)
+	}
+}
+
+func TestReportJob_Expired(t *testing.T) {
+	service, reportJobRepo := createReportJobTestService()
+	ctx := context.Background()
+	orgID := uuid.New()
+
+	expiredAt := time.Now().Add(-time.Hour)
+	job := &entity.ReportJob{
+		BaseEntity:     entity.NewBaseEntity(),
+		OrganizationID: orgID,
+		Format:         entity.ReportFormatCSV,
+		Status:         entity.ReportJobStatusCompleted,
+		ExpiresAt:      &expiredAt,
+	}
+	_ = reportJobRepo.Create(ctx, job)
+
+	status, err := service.GetReportJob(ctx, orgID, job.ID)
+	if err != nil {
+		t.Fatalf("GetReportJob failed: %v", err)
+	}
+	if status.Status != entity.ReportJobStatusExpired {
+		t.Errorf("expected expired status, got %s", status.Status)
+	}
+
+	_, _, err = service.GetReportArtifact(ctx, orgID, job.ID)
+	var appErr *errors.AppError
+	if !stderrors.As(err, &appErr) || appErr.HTTPStatus != http.StatusGone {
+		t.Errorf("expected gone for expired report, got %v", err)
+	}
+}
+
+func TestReportJob_RetriesThenFails(t *testing.T) {
+	service, reportJobRepo := createReportJobTestService()
+	ctx := context.Background()
+
+	// An unknown format makes every render attempt fail
+	job := &entity.ReportJob{
+		BaseEntity:     entity.NewBaseEntity(),
+		OrganizationID: uuid.New(),
+		Format:         "docx",
+		Status:         entity.ReportJobStatusPending,
+		MaxAttempts:    2,
+	}
+	_ = reportJobRepo.Create(ctx, job)
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		processed, err := service.ProcessNextReportJob(ctx, "worker-1")
+		if !processed || err == nil {
+			t.Fatalf("attempt %d: expected processed failure, got %v, %v", attempt, processed, err)
+		}
+	}
+
+	stored, _ := reportJobRepo.GetByID(ctx, job.ID)
+	if stored.Status != entity.ReportJobStatusFailed {
+		t.Errorf("expected failed status after max attempts, got %s", stored.Status)
+	}
+	if stored.ErrorMessage == "" {
+		t.Error("expected error message to be recorded")
+	}
+
+	processed, _ := service.ProcessNextReportJob(ctx, "worker-1")
+	if processed {
+		t.Error("failed job should not be claimed again")
+	}
+}
+
+func TestReportJob_StaleJobFailsWithoutAttemptsLeft(t *testing.T) {
+	service, reportJobRepo := createReportJobTestService()
+	ctx := context.Background()
+
+	job := &entity.ReportJob{
+		BaseEntity:     entity.NewBaseEntity(),
+		OrganizationID: uuid.New(),
+		Format:         entity.ReportFormatCSV,
+		Status:         entity.ReportJobStatusPending,
+		MaxAttempts:    2,
+	}
+	_ = reportJobRepo.Create(ctx, job)
+
+	// The worker dies on every attempt, leaving the job running until it goes stale
+	for attempt := 1; attempt <= 2; attempt++ {
+		claimed, _ := reportJobRepo.ClaimNext(ctx, "worker-1")
+		if claimed == nil {
+			t.Fatalf("attempt %d: job was not claimed", attempt)
+		}
+
+		if _, err := service.RequeueStaleReportJobs(ctx, -time.Second); err != nil {
+			t.Fatalf("RequeueStaleReportJobs() error = %v", err)
+		}
+	}
+
+	stored, _ := reportJobRepo.GetByID(ctx, job.ID)
+	if stored.Status != entity.ReportJobStatusFailed {
+		t.Errorf("status = %s, want failed after max attempts", stored.Status)
+	}
+	if stored.ErrorMessage != entity.ReportJobAbandonedMessage {
+		t.Errorf("error message = %q, want %q", stored.ErrorMessage, entity.ReportJobAbandonedMessage)
+	}
+
+	if claimed, _ := reportJobRepo.ClaimNext(ctx, "worker-1"); claimed != nil {
+		t.Error("failed job should not be claimed again")
+	}
+}
@@ -0,0 +1,640 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/pdf"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+// ============================================================================
+// Report Branding
+// ============================================================================
+
+const (
+	defaultBrandName  = "AdsAnalytic"
+	defaultBrandColor = "#2563EB"
+)
+
+// ReportBranding controls the header, accent color and footer of rendered reports
+type ReportBranding struct {
+	CompanyName  string `json:"company_name"`
+	PrimaryColor string `json:"primary_color"` // Hex color, e.g. #2563EB
+	FooterText   string `json:"footer_text"`
+	WhiteLabel   bool   `json:"white_label"`
+}
+
+// DefaultReportBranding returns the product branding used when white-labelling is not available
+func DefaultReportBranding() ReportBranding {
+	return ReportBranding{
+		CompanyName:  defaultBrandName,
+		PrimaryColor: defaultBrandColor,
+		FooterText:   "Generated by " + defaultBrandName,
+	}
+}
+
+// BrandingForOrganization builds white-label branding from the organization profile.
+// Optional overrides are read from the "branding" object in organization settings:
+// company_name, primary_color and footer_text.
+func BrandingForOrganization(org *entity.Organization) ReportBranding {
+	branding := ReportBranding{
+		CompanyName:  org.Name,
+		PrimaryColor: defaultBrandColor,
+		FooterText:   org.Name,
+		WhiteLabel:   true,
+	}
+
+	settings, ok := org.Settings["branding"].(map[string]interface{})
+	if !ok {
+		return branding
+	}
+	if name, ok := settings["company_name"].(string); ok && name != "" {
+		branding.CompanyName = name
+		branding.FooterText = name
+	}
+	if color, ok := settings["primary_color"].(string); ok {
+		if _, valid := pdf.ParseHexColor(color); valid {
+			branding.PrimaryColor = color
+		}
+	}
+	if footer, ok := settings["footer_text"].(string); ok && footer != "" {
+		branding.FooterText = footer
+	}
+
+	return branding
+}
+
+// SetOrganizationRepository sets the repository used to resolve organization branding
+func (s *Service) SetOrganizationRepository(orgRepo repository.OrganizationRepository) {
+	s.orgRepo = orgRepo
+}
+
+// resolveBranding returns organization branding when white-labelling is enabled for the
+// plan, falling back to the product branding otherwise
+func (s *Service) resolveBranding(ctx context.Context, orgID uuid.UUID, whiteLabel bool) ReportBranding {
+	if !whiteLabel || s.orgRepo == nil {
+		return DefaultReportBranding()
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil || org == nil {
+		return DefaultReportBranding()
+	}
+
+	return BrandingForOrganization(org)
+}
+
+// ============================================================================
+// PDF Rendering
+// ============================================================================
+
+// RenderReportPDF renders the client report for the date range as a PDF document.
+// whiteLabel should reflect PlanLimits.WhiteLabelEnabled for the organization.
+func (s *Service) RenderReportPDF(ctx context.Context, orgID uuid.UUID, dateRange entity.DateRange, whiteLabel bool) ([]byte, error) {
+	report, err := s.GenerateReport(ctx, orgID, dateRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate report: %w", err)
+	}
+
+	crossPlatform, err := s.GetCrossPlatformReport(ctx, orgID, dateRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate cross-platform report: %w", err)
+	}
+
+	trend, err := s.metricsRepo.GetDailyTrend(ctx, entity.MetricsFilter{
+		OrganizationID: orgID,
+		DateRange:      dateRange,
+		GroupBy:        "day",
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get daily trend: %w", err)
+	}
+
+	branding := s.resolveBranding(ctx, orgID, whiteLabel)
+
+	return renderReportPDF(report, crossPlatform, trend, branding)
+}
+
+// Page layout in points
+const (
+	pdfMargin        = 40.0
+	pdfHeaderHeight  = 96.0
+	pdfFooterSpace   = 48.0
+	pdfRowHeight     = 18.0
+	pdfSectionGap    = 22.0
+	pdfMaxCampaigns  = 25
+	pdfChartHeight   = 130.0
+	pdfChartAxisGap  = 56.0
+	pdfKPICardHeight = 50.0
+)
+
+var (
+	pdfStripeColor = pdf.Color{R: 243, G: 244, B: 246}
+	pdfCardColor   = pdf.Color{R: 249, G: 250, B: 251}
+)
+
+// reportRenderer lays out report sections top to bottom, starting new pages as needed
+type reportRenderer struct {
+	doc      *pdf.Document
+	page     *pdf.Page
+	y        float64
+	accent   pdf.Color
+	branding ReportBranding
+	currency string
+}
+
+func renderReportPDF(report *ReportExport, crossPlatform *CrossPlatformReport, trend []entity.DailyMetricsTrend, branding ReportBranding) ([]byte, error) {
+	accent, ok := pdf.ParseHexColor(branding.PrimaryColor)
+	if !ok {
+		accent, _ = pdf.ParseHexColor(defaultBrandColor)
+	}
+
+	r := &reportRenderer{
+		doc:      pdf.NewA4(),
+		accent:   accent,
+		branding: branding,
+		currency: report.Summary.Currency,
+	}
+	r.doc.SetTitle(fmt.Sprintf("Performance Report %s - %s",
+		report.DateRange.StartDate.Format("2006-01-02"), report.DateRange.EndDate.Format("2006-01-02")))
+	r.doc.SetAuthor(branding.CompanyName)
+
+	r.writeCoverHeader(report)
+	r.writeSummary(report)
+	r.writePlatformTable(crossPlatform)
+	r.writeInsights(crossPlatform.Insights)
+	r.writeTrendCharts(trend)
+	r.writeCampaignTable(report.CampaignMetrics)
+	r.writeFooters()
+
+	return r.doc.Bytes()
+}
+
+func (r *reportRenderer) contentWidth() float64 {
+	return r.doc.Width() - 2*pdfMargin
+}
+
+// ensureSpace starts a new page when the next block of height h would overflow
+func (r *reportRenderer) ensureSpace(h float64) {
+	if r.page == nil || r.y+h > r.doc.Height()-pdfFooterSpace {
+		r.newPage()
+	}
+}
+
+func (r *reportRenderer) newPage() {
+	r.page = r.doc.AddPage()
+
+	// Continuation pages carry a slim accent bar with the company name
+	r.page.SetFillColor(r.accent)
+	r.page.FillRect(0, 0, r.doc.Width(), 6)
+	r.page.SetFillColor(pdf.Gray)
+	r.page.Text(pdfMargin, 26, pdf.FontRegular, 8, r.branding.CompanyName)
+	r.y = 44
+}
+
+func (r *reportRenderer) writeCoverHeader(report *ReportExport) {
+	r.page = r.doc.AddPage()
+
+	r.page.SetFillColor(r.accent)
+	r.page.FillRect(0, 0, r.doc.Width(), pdfHeaderHeight)
+
+	r.page.SetFillColor(pdf.White)
+	r.page.Text(pdfMargin, 44, pdf.FontBold, 20, "Advertising Performance Report")
+	r.page.Text(pdfMargin, 70, pdf.FontRegular, 11, r.branding.CompanyName)
+
+	period := fmt.Sprintf("%s - %s",
+		report.DateRange.StartDate.Format("02 Jan 2006"), report.DateRange.EndDate.Format("02 Jan 2006"))
+	r.page.TextRight(r.doc.Width()-pdfMargin, 70, pdf.FontRegular, 11, period)
+
+	r.y = pdfHeaderHeight + 28
+	r.page.SetFillColor(pdf.Gray)
+	r.page.Text(pdfMargin, r.y, pdf.FontRegular, 8,
+		"Generated "+report.GeneratedAt.UTC().Format("02 Jan 2006 15:04 MST"))
+	r.y += pdfSectionGap
+}
+
+func (r *reportRenderer) writeHeading(title string) {
+	r.ensureSpace(pdfSectionGap + 40)
+	r.page.SetFillColor(r.accent)
+	r.page.Text(pdfMargin, r.y, pdf.FontBold, 13, title)
+	r.y += 14
+}
+
+func (r *reportRenderer) writeSummary(report *ReportExport) {
+	summary := report.Summary
+
+	r.writeHeading("Summary")
+
+	cards := []struct {
+		label string
+		value string
+	}{
+		{"Total Spend", formatMoney(summary.TotalSpend, r.currency)},
+		{"Revenue", formatMoney(summary.TotalRevenue, r.currency)},
+		{"ROAS", formatRatio(summary.OverallROAS)},
+		{"Impressions", formatThousands(summary.TotalImpressions)},
+		{"Clicks", formatThousands(summary.TotalClicks)},
+		{"Conversions", formatThousands(summary.TotalConversions)},
+		{"CTR", formatPercent(summary.AverageCTR)},
+		{"CPC", formatMoney(summary.AverageCPC, r.currency)},
+		{"CPA", formatMoney(summary.AverageCPA, r.currency)},
+	}
+
+	const columns, gap = 3, 12.0
+	cardWidth := (r.contentWidth() - gap*(columns-1)) / columns
+
+	for i, card := range cards {
+		if i%columns == 0 {
+			if i > 0 {
+				r.y += pdfKPICardHeight + gap
+			}
+			r.ensureSpace(pdfKPICardHeight)
+		}
+		x := pdfMargin + float64(i%columns)*(cardWidth+gap)
+
+		r.page.SetFillColor(pdfCardColor)
+		r.page.FillRect(x, r.y, cardWidth, pdfKPICardHeight)
+		r.page.SetFillColor(r.accent)
+		r.page.FillRect(x, r.y, 3, pdfKPICardHeight)
+
+		r.page.SetFillColor(pdf.Gray)
+		r.page.Text(x+12, r.y+18, pdf.FontRegular, 8, strings.ToUpper(card.label))
+		r.page.SetFillColor(pdf.Black)
+		r.page.Text(x+12, r.y+38, pdf.FontBold, 14, pdf.TruncateText(pdf.FontBold, 14, card.value, cardWidth-18))
+	}
+	r.y += pdfKPICardHeight + pdfSectionGap
+}
+
+// tableColumn describes a table column; widths are fractions of the content width
+type tableColumn struct {
+	title string
+	width float64
+	right bool
+}
+
+func (r *reportRenderer) writeTableHeader(columns []tableColumn) {
+	r.page.SetFillColor(r.accent)
+	r.page.FillRect(pdfMargin, r.y, r.contentWidth(), pdfRowHeight)
+	r.page.SetFillColor(pdf.White)
+	r.writeTableCells(columns, columnTitles(columns), pdf.FontBold)
+	r.y += pdfRowHeight
+}
+
+func (r *reportRenderer) writeTableRow(columns []tableColumn, values []string, index int, font pdf.Font) {
+	if r.y+pdfRowHeight > r.doc.Height()-pdfFooterSpace {
+		r.newPage()
+		r.writeTableHeader(columns)
+	}
+	if index%2 == 1 {
+		r.page.SetFillColor(pdfStripeColor)
+		r.page.FillRect(pdfMargin, r.y, r.contentWidth(), pdfRowHeight)
+	}
+	r.page.SetFillColor(pdf.Black)
+	r.writeTableCells(columns, values, font)
+	r.y += pdfRowHeight
+}
+
+func (r *reportRenderer) writeTableCells(columns []tableColumn, values []string, font pdf.Font) {
+	const size, padding = 8.5, 5.0
+	x := pdfMargin
+	for i, col := range columns {
+		width := col.width * r.contentWidth()
+		text := pdf.TruncateText(font, size, values[i], width-2*padding)
+		if col.right {
+			r.page.TextRight(x+width-padding, r.y+12.5, font, size, text)
+		} else {
+			r.page.Text(x+padding, r.y+12.5, font, size, text)
+		}
+		x += width
+	}
+}
+
+func columnTitles(columns []tableColumn) []string {
+	titles := make([]string, len(columns))
+	for i, col := range columns {
+		titles[i] = col.title
+	}
+	return titles
+}
+
+func (r *reportRenderer) writePlatformTable(crossPlatform *CrossPlatformReport) {
+	r.writeHeading("Platform Comparison")
+
+	columns := []tableColumn{
+		{"Platform", 0.16, false},
+		{"Spend", 0.16, true},
+		{"Impressions", 0.14, true},
+		{"Clicks", 0.10, true},
+		{"CTR", 0.09, true},
+		{"CPC", 0.13, true},
+		{"Conversions", 0.12, true},
+		{"ROAS", 0.10, true},
+	}
+	r.writeTableHeader(columns)
+
+	// Present platforms by spend; generateInsights leaves ByPlatform sorted by CTR
+	platforms := make([]entity.PlatformMetricsSummary, len(crossPlatform.ByPlatform))
+	copy(platforms, crossPlatform.ByPlatform)
+	sort.SliceStable(platforms, func(i, j int) bool {
+		return platforms[i].Spend.GreaterThan(platforms[j].Spend)
+	})
+
+	if len(platforms) == 0 {
+		r.writeEmptyRow("No platform data for this period")
+	}
+	for i, p := range platforms {
+		r.writeTableRow(columns, []string{
+			platformSheetName(p.Platform),
+			formatMoney(p.Spend, r.currency),
+			formatThousands(p.Impressions),
+			formatThousands(p.Clicks),
+			formatPercent(p.CTR),
+			formatMoney(p.CPC, r.currency),
+			formatThousands(p.Conversions),
+			formatRatio(p.ROAS),
+		}, i, pdf.FontRegular)
+	}
+
+	if len(platforms) > 1 {
+		total := crossPlatform.TotalMetrics
+		r.writeTableRow(columns, []string{
+			"Total",
+			formatMoney(total.TotalSpend, r.currency),
+			formatThousands(total.TotalImpressions),
+			formatThousands(total.TotalClicks),
+			formatPercent(total.AverageCTR),
+			formatMoney(total.AverageCPC, r.currency),
+			formatThousands(total.TotalConversions),
+			formatRatio(total.OverallROAS),
+		}, len(platforms), pdf.FontBold)
+	}
+	r.y += pdfSectionGap
+}
+
+func (r *reportRenderer) writeEmptyRow(message string) {
+	r.page.SetFillColor(pdf.Gray)
+	r.page.Text(pdfMargin+5, r.y+12.5, pdf.FontRegular, 8.5, message)
+	r.y += pdfRowHeight
+}
+
+func (r *reportRenderer) writeInsights(insights []string) {
+	if len(insights) == 0 {
+		return
+	}
+
+	r.writeHeading("Insights")
+
+	const size, lineHeight, indent = 10.0, 14.0, 14.0
+	for _, insight := range insights {
+		lines := pdf.WrapText(pdf.FontRegular, size, insight, r.contentWidth()-indent)
+		r.ensureSpace(float64(len(lines))*lineHeight + 4)
+
+		r.page.SetFillColor(r.accent)
+		r.page.Text(pdfMargin, r.y+10, pdf.FontBold, size, "•")
+		r.page.SetFillColor(pdf.Black)
+		for _, line := range lines {
+			r.page.Text(pdfMargin+indent, r.y+10, pdf.FontRegular, size, line)
+			r.y += lineHeight
+		}
+		r.y += 4
+	}
+	r.y += pdfSectionGap - 4
+}
+
+func (r *reportRenderer) writeTrendCharts(trend []entity.DailyMetricsTrend) {
+	sorted := make([]entity.DailyMetricsTrend, len(trend))
+	copy(sorted, trend)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Date.Before(sorted[j].Date)
+	})
+
+	spend := make([]float64, len(sorted))
+	roas := make([]float64, len(sorted))
+	labels := make([]string, len(sorted))
+	for i, day := range sorted {
+		spend[i], _ = day.Spend.Float64()
+		roas[i] = day.ROAS
+		labels[i] = day.Date.Format("02 Jan")
+	}
+
+	r.writeHeading("Trends")
+	r.writeLineChart("Daily Spend", spend, labels, formatCompact)
+	r.writeLineChart("Daily ROAS", roas, labels, formatRatio)
+}
+
+// writeLineChart draws a single-series line chart with horizontal gridlines
+func (r *reportRenderer) writeLineChart(title string, values []float64, labels []string, format func(float64) string) {
+	r.ensureSpace(pdfChartHeight + 40)
+
+	r.page.SetFillColor(pdf.Black)
+	r.page.Text(pdfMargin, r.y+10, pdf.FontBold, 10, title)
+	r.y += 20
+
+	left := pdfMargin + pdfChartAxisGap
+	width := r.contentWidth() - pdfChartAxisGap
+	top := r.y
+	bottom := r.y + pdfChartHeight
+
+	maxValue := 0.0
+	for _, v := range values {
+		maxValue = math.Max(maxValue, v)
+	}
+
+	if len(values) == 0 || maxValue <= 0 {
+		r.page.SetStrokeColor(pdf.LightGray)
+		r.page.SetLineWidth(0.5)
+		r.page.StrokeRect(left, top, width, pdfChartHeight)
+		r.page.SetFillColor(pdf.Gray)
+		r.page.TextCenter(left+width/2, top+pdfChartHeight/2, pdf.FontRegular, 9, "No data for this period")
+		r.y = bottom + pdfSectionGap
+		return
+	}
+
+	scale := niceCeiling(maxValue)
+	const gridLines = 4
+
+	r.page.SetLineWidth(0.5)
+	for i := 0; i <= gridLines; i++ {
+		value := scale * float64(i) / gridLines
+		y := bottom - pdfChartHeight*float64(i)/gridLines
+
+		r.page.SetStrokeColor(pdf.LightGray)
+		r.page.Line(left, y, left+width, y)
+		r.page.SetFillColor(pdf.Gray)
+		r.page.TextRight(left-6, y+3, pdf.FontRegular, 7.5, format(value))
+	}
+
+	points := make([]pdf.Point, len(values))
+	for i, v := range values {
+		x := left + width/2
+		if len(values) > 1 {
+			x = left + width*float64(i)/float64(len(values)-1)
+		}
+		points[i] = pdf.Point{X: x, Y: bottom - pdfChartHeight*v/scale}
+	}
+
+	r.page.SetStrokeColor(r.accent)
+	r.page.SetLineWidth(1.5)
+	if len(points) == 1 {
+		r.page.SetFillColor(r.accent)
+		r.page.FillRect(points[0].X-2, points[0].Y-2, 4, 4)
+	} else {
+		r.page.Polyline(points)
+	}
+
+	// Label the first, middle and last days
+	r.page.SetFillColor(pdf.Gray)
+	r.page.Text(left, bottom+12, pdf.FontRegular, 7.5, labels[0])
+	if len(labels) > 2 {
+		mid := len(labels) / 2
+		r.page.TextCenter(points[mid].X, bottom+12, pdf.FontRegular, 7.5, labels[mid])
+	}
+	if len(labels) > 1 {
+		r.page.TextRight(left+width, bottom+12, pdf.FontRegular, 7.5, labels[len(labels)-1])
+	}
+
+	r.y = bottom + 16 + pdfSectionGap
+}
+
+func (r *reportRenderer) writeCampaignTable(rows []CampaignReportRow) {
+	if len(rows) == 0 {
+		return
+	}
+
+	campaigns := make([]CampaignReportRow, len(rows))
+	copy(campaigns, rows)
+	sort.SliceStable(campaigns, func(i, j int) bool {
+		return campaigns[i].Spend.GreaterThan(campaigns[j].Spend)
+	})
+
+	title := "Campaigns"
+	if len(campaigns) > pdfMaxCampaigns {
+		campaigns = campaigns[:pdfMaxCampaigns]
+		title = fmt.Sprintf("Top %d Campaigns by Spend", pdfMaxCampaigns)
+	}
+
+	r.writeHeading(title)
+
+	columns := []tableColumn{
+		{"Campaign", 0.34, false},
+		{"Platform", 0.12, false},
+		{"Spend", 0.16, true},
+		{"Clicks", 0.10, true},
+		{"CTR", 0.09, true},
+		{"Conversions", 0.11, true},
+		{"ROAS", 0.08, true},
+	}
+	r.writeTableHeader(columns)
+
+	for i, c := range campaigns {
+		r.writeTableRow(columns, []string{
+			c.CampaignName,
+			platformSheetName(c.Platform),
+			formatMoney(c.Spend, r.currency),
+			formatThousands(c.Clicks),
+			formatPercent(c.CTR),
+			formatThousands(c.Conversions),
+			formatRatio(c.ROAS),
+		}, i, pdf.FontRegular)
+	}
+	r.y += pdfSectionGap
+}
+
+// writeFooters stamps the footer text and page numbers once the page count is known
+func (r *reportRenderer) writeFooters() {
+	pages := r.doc.Pages()
+	footerY := r.doc.Height() - 24
+
+	for i, page := range pages {
+		page.SetStrokeColor(pdf.LightGray)
+		page.SetLineWidth(0.5)
+		page.Line(pdfMargin, footerY-12, r.doc.Width()-pdfMargin, footerY-12)
+
+		page.SetFillColor(pdf.Gray)
+		page.Text(pdfMargin, footerY, pdf.FontRegular, 8, r.branding.FooterText)
+		page.TextRight(r.doc.Width()-pdfMargin, footerY, pdf.FontRegular, 8, fmt.Sprintf("Page %d of %d", i+1, len(pages)))
+	}
+}
+
+// ============================================================================
+// Formatting Helpers
+// ============================================================================
+
+// formatThousands formats an integer with thousands separators
+func formatThousands(n int64) string {
+	s := fmt.Sprintf("%d", n)
+	negative := strings.HasPrefix(s, "-")
+	if negative {
+		s = s[1:]
+	}
+
+	var b strings.Builder
+	for i, c := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(c)
+	}
+
+	if negative {
+		return "-" + b.String()
+	}
+	return b.String()
+}
+
+// formatMoney formats an amount with the currency code and two decimal places
+func formatMoney(amount decimal.Decimal, currencyCode string) string {
+	rounded := amount.Round(2)
+	whole := rounded.Truncate(0)
+	cents := rounded.Sub(whole).Abs().Shift(2).IntPart()
+
+	formatted := fmt.Sprintf("%s.%02d", formatThousands(whole.IntPart()), cents)
+	if rounded.IsNegative() && whole.IsZero() {
+		formatted = "-" + formatted
+	}
+	if currencyCode == "" {
+		return formatted
+	}
+	return currencyCode + " " + formatted
+}
+
+func formatPercent(v float64) string {
+	return fmt.Sprintf("%.2f%%", v)
+}
+
+func formatRatio(v float64) string {
+	return fmt.Sprintf("%.2fx", v)
+}
+
+// formatCompact formats chart axis values, abbreviating thousands and millions
+func formatCompact(v float64) string {
+	switch {
+	case v >= 1_000_000:
+		return fmt.Sprintf("%.1fM", v/1_000_000)
+	case v >= 1_000:
+		return fmt.Sprintf("%.1fK", v/1_000)
+	default:
+		return fmt.Sprintf("%.0f", v)
+	}
+}
+
+// niceCeiling rounds v up to a 1, 2, 2.5 or 5 multiple of a power of ten for chart scales
+func niceCeiling(v float64) float64 {
+	if v <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(v)))
+	for _, step := range []float64{1, 2, 2.5, 5, 10} {
+		if v <= step*magnitude {
+			return step * magnitude
+		}
+	}
+	return 10 * magnitude
+}
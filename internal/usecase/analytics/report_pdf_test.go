@@ -0,0 +1,196 @@
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/google/uuid"
+	"github.com/shopspring/decimal"
+)
+
+type mockOrganizationRepository struct {
+	orgs map[uuid.UUID]*entity.Organization
+}
+
+func (m *mockOrganizationRepository) Create(ctx context.Context, org *entity.Organization) error {
+	return nil
+}
+
+func (m *mockOrganizationRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.Organization, error) {
+	if org, ok := m.orgs[id]; ok {
+		return org, nil
+	}
+	return nil, fmt.Errorf("organization not found")
+}
+
+func (m *mockOrganizationRepository) GetBySlug(ctx context.Context, slug string) (*entity.Organization, error) {
+	return nil, nil
+}
+
+func (m *mockOrganizationRepository) Update(ctx context.Context, org *entity.Organization) error {
+	return nil
+}
+
+func (m *mockOrganizationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+func (m *mockOrganizationRepository) List(ctx context.Context, pagination *entity.Pagination) ([]entity.Organization, error) {
+	return nil, nil
+}
+
+func (m *mockOrganizationRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]entity.Organization, error) {
+	return nil, nil
+}
+
+func TestBrandingForOrganization(t *testing.T) {
+	org := &entity.Organization{
+		Name: "Acme Digital",
+		Settings: entity.JSONMap{
+			"branding": map[string]interface{}{
+				"primary_color": "#FF6600",
+				"footer_text":   "Prepared by Acme Digital for internal use",
+			},
+		},
+	}
+
+	branding := BrandingForOrganization(org)
+
+	if !branding.WhiteLabel {
+		t.Error("WhiteLabel should be true for organization branding")
+	}
+	if branding.CompanyName != "Acme Digital" {
+		t.Errorf("CompanyName = %q, want %q", branding.CompanyName, "Acme Digital")
+	}
+	if branding.PrimaryColor != "#FF6600" {
+		t.Errorf("PrimaryColor = %q, want %q", branding.PrimaryColor, "#FF6600")
+	}
+	if branding.FooterText != "Prepared by Acme Digital for internal use" {
+		t.Errorf("FooterText = %q", branding.FooterText)
+	}
+
+	org.Settings["branding"] = map[string]interface{}{"primary_color": "orange"}
+	if got := BrandingForOrganization(org).PrimaryColor; got != defaultBrandColor {
+		t.Errorf("invalid primary_color should fall back to default, got %q", got)
+	}
+}
+
+func TestResolveBranding(t *testing.T) {
+	service, _, _ := createTestService()
+	orgID := uuid.New()
+	service.SetOrganizationRepository(&mockOrganizationRepository{
+		orgs: map[uuid.UUID]*entity.Organization{
+			orgID: {BaseEntity: entity.BaseEntity{ID: orgID}, Name: "Acme Digital"},
+		},
+	})
+	ctx := context.Background()
+
+	if got := service.resolveBranding(ctx, orgID, false); got.CompanyName != defaultBrandName {
+		t.Errorf("without white-label CompanyName = %q, want %q", got.CompanyName, defaultBrandName)
+	}
+	if got := service.resolveBranding(ctx, orgID, true); got.CompanyName != "Acme Digital" {
+		t.Errorf("with white-label CompanyName = %q, want %q", got.CompanyName, "Acme Digital")
+	}
+	if got := service.resolveBranding(ctx, uuid.New(), true); got.CompanyName != defaultBrandName {
+		t.Errorf("unknown organization should fall back to default branding, got %q", got.CompanyName)
+	}
+}
+
+func TestRenderReportPDF(t *testing.T) {
+	service, metricsRepo, campaignRepo := createTestService()
+	ctx := context.Background()
+
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	metricsRepo.aggregated = &entity.AggregatedMetrics{
+		TotalSpend:   decimal.NewFromInt(1500),
+		TotalRevenue: decimal.NewFromInt(4500),
+		OverallROAS:  3,
+		Currency:     "MYR",
+	}
+	metricsRepo.platformMetrics = []entity.PlatformMetricsSummary{
+		{Platform: entity.PlatformMeta, Spend: decimal.NewFromInt(1000), ROAS: 3.5, CTR: 1.2, CPC: decimal.NewFromFloat(0.8)},
+		{Platform: entity.PlatformShopee, Spend: decimal.NewFromInt(500), ROAS: 2, CTR: 2.4, CPC: decimal.NewFromFloat(0.4)},
+	}
+	for i := 0; i < 14; i++ {
+		metricsRepo.dailyTrend = append(metricsRepo.dailyTrend, entity.DailyMetricsTrend{
+			Date:  start.AddDate(0, 0, i),
+			Spend: decimal.NewFromInt(int64(80 + i*5)),
+			ROAS:  2.5 + float64(i%3)*0.5,
+		})
+	}
+	// Enough campaigns to force the campaign table onto a second page
+	for i := 0; i < 40; i++ {
+		campaignRepo.summaries = append(campaignRepo.summaries, entity.CampaignSummary{
+			ID:         uuid.New(),
+			Platform:   entity.PlatformMeta,
+			Name:       fmt.Sprintf("Campaign %02d", i),
+			TotalSpend: decimal.NewFromInt(int64(100 - i)),
+		})
+	}
+
+	dateRange := entity.DateRange{StartDate: start, EndDate: start.AddDate(0, 0, 13)}
+
+	data, err := service.RenderReportPDF(ctx, uuid.New(), dateRange, false)
+	if err != nil {
+		t.Fatalf("RenderReportPDF failed: %v", err)
+	}
+
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		t.Fatal("output is not a PDF document")
+	}
+
+	match := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(data)
+	if match == nil {
+		t.Fatal("page tree missing /Count")
+	}
+	if pages, _ := strconv.Atoi(string(match[1])); pages < 2 {
+		t.Errorf("page count = %d, want at least 2", pages)
+	}
+	if !bytes.Contains(data, []byte("/Author ("+defaultBrandName+")")) {
+		t.Error("report without white-label should carry the default branding")
+	}
+}
+
+func TestFormatMoney(t *testing.T) {
+	tests := []struct {
+		amount   decimal.Decimal
+		currency string
+		expected string
+	}{
+		{decimal.NewFromFloat(1234567.891), "MYR", "MYR 1,234,567.89"},
+		{decimal.NewFromFloat(0.5), "USD", "USD 0.50"},
+		{decimal.NewFromFloat(-0.25), "", "-0.25"},
+		{decimal.NewFromInt(-1500), "SGD", "SGD -1,500.00"},
+	}
+
+	for _, tt := range tests {
+		if got := formatMoney(tt.amount, tt.currency); got != tt.expected {
+			t.Errorf("formatMoney(%s, %q) = %q, want %q", tt.amount, tt.currency, got, tt.expected)
+		}
+	}
+}
+
+func TestNiceCeiling(t *testing.T) {
+	tests := []struct {
+		input    float64
+		expected float64
+	}{
+		{0, 1},
+		{7, 10},
+		{180, 200},
+		{2.2, 2.5},
+		{4200, 5000},
+	}
+
+	for _, tt := range tests {
+		if got := niceCeiling(tt.input); got != tt.expected {
+			t.Errorf("niceCeiling(%v) = %v, want %v", tt.input, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,190 @@
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+)
+
+// MaxReportScheduleRecipients limits how many addresses a single schedule may email
+const MaxReportScheduleRecipients = 20
+
+// SetReportScheduleRepository sets the repository used for scheduled report delivery
+func (s *Service) SetReportScheduleRepository(reportScheduleRepo repository.ReportScheduleRepository) {
+	s.reportScheduleRepo = reportScheduleRepo
+}
+
+// ReportScheduleInput holds the user-editable fields of a report schedule
+type ReportScheduleInput struct {
+	Name           string
+	CronExpression string
+	Timezone       string
+	DatePreset     entity.ReportDatePreset
+	Format         entity.ReportFormat
+	Recipients     []string
+	IsActive       *bool
+}
+
+// ListReportSchedules returns the report schedules of an organization
+func (s *Service) ListReportSchedules(ctx context.Context, orgID uuid.UUID) ([]entity.ReportSchedule, error) {
+	if s.reportScheduleRepo == nil {
+		return nil, errors.ErrInternal("Report schedules are not configured")
+	}
+	return s.reportScheduleRepo.ListByOrganization(ctx, orgID)
+}
+
+// GetReportSchedule returns a report schedule belonging to the organization
+func (s *Service) GetReportSchedule(ctx context.Context, orgID, scheduleID uuid.UUID) (*entity.ReportSchedule, error) {
+	if s.reportScheduleRepo == nil {
+		return nil, errors.ErrInternal("Report schedules are not configured")
+	}
+
+	schedule, err := s.reportScheduleRepo.GetByID(ctx, scheduleID)
+	if err != nil || schedule == nil || schedule.OrganizationID != orgID {
+		return nil, errors.ErrNotFound("Report schedule")
+	}
+	return schedule, nil
+}
+
+// CreateReportSchedule validates and stores a new report schedule
+func (s *Service) CreateReportSchedule(ctx context.Context, orgID uuid.UUID, createdBy *uuid.UUID, whiteLabel bool, input ReportScheduleInput) (*entity.ReportSchedule, error) {
+	if s.reportScheduleRepo == nil {
+		return nil, errors.ErrInternal("Report schedules are not configured")
+	}
+
+	schedule := &entity.ReportSchedule{
+		BaseEntity:     entity.NewBaseEntity(),
+		OrganizationID: orgID,
+		CreatedBy:      createdBy,
+		WhiteLabel:     whiteLabel,
+		IsActive:       true,
+	}
+	if err := applyReportScheduleInput(schedule, input, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.reportScheduleRepo.Create(ctx, schedule); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to create report schedule", http.StatusInternalServerError)
+	}
+
+	return schedule, nil
+}
+
+// UpdateReportSchedule validates and applies changes to an existing report schedule
+func (s *Service) UpdateReportSchedule(ctx context.Context, orgID, scheduleID uuid.UUID, whiteLabel bool, input ReportScheduleInput) (*entity.ReportSchedule, error) {
+	schedule, err := s.GetReportSchedule(ctx, orgID, scheduleID)
+	if err != nil {
+		return nil, err
+	}
+
+	schedule.WhiteLabel = whiteLabel
+	if err := applyReportScheduleInput(schedule, input, time.Now()); err != nil {
+		return nil, err
+	}
+
+	if err := s.reportScheduleRepo.Update(ctx, schedule); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to update report schedule", http.StatusInternalServerError)
+	}
+
+	return schedule, nil
+}
+
+// DeleteReportSchedule deletes a report schedule belonging to the organization
+func (s *Service) DeleteReportSchedule(ctx context.Context, orgID, scheduleID uuid.UUID) error {
+	if _, err := s.GetReportSchedule(ctx, orgID, scheduleID); err != nil {
+		return err
+	}
+
+	if err := s.reportScheduleRepo.Delete(ctx, scheduleID); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete report schedule", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// applyReportScheduleInput validates input, copies it onto the schedule and
+// recalculates the next run
+func applyReportScheduleInput(schedule *entity.ReportSchedule, input ReportScheduleInput, now time.Time) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return errors.ErrValidation("name is required")
+	}
+
+	timezone := input.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return errors.ErrValidation(fmt.Sprintf("Unknown timezone: %s", timezone))
+	}
+
+	if !input.DatePreset.IsValid() {
+		return errors.ErrValidation(fmt.Sprintf("Unsupported date_preset: %s", input.DatePreset))
+	}
+
+	// JSON reports are for API consumers and are not emailed
+	if !input.Format.IsValid() || input.Format == entity.ReportFormatJSON {
+		return errors.ErrValidation(fmt.Sprintf("Unsupported report format: %s", input.Format))
+	}
+
+	recipients, err := normalizeRecipients(input.Recipients)
+	if err != nil {
+		return err
+	}
+
+	schedule.Name = name
+	schedule.CronExpression = strings.TrimSpace(input.CronExpression)
+	schedule.Timezone = timezone
+	schedule.DatePreset = input.DatePreset
+	schedule.Format = input.Format
+	schedule.Recipients = recipients
+	if input.IsActive != nil {
+		schedule.IsActive = *input.IsActive
+	}
+
+	nextRun, err := schedule.NextRunAfter(now)
+	if err != nil {
+		return errors.ErrValidation(fmt.Sprintf("Invalid cron_expression: %v", err))
+	}
+	if err := schedule.ValidateFrequency(); err != nil {
+		return errors.ErrValidation(fmt.Sprintf("Invalid cron_expression: %v", err))
+	}
+	schedule.NextRunAt = &nextRun
+
+	return nil
+}
+
+// normalizeRecipients validates email addresses and removes duplicates
+func normalizeRecipients(recipients []string) ([]string, error) {
+	seen := make(map[string]bool, len(recipients))
+	normalized := make([]string, 0, len(recipients))
+
+	for _, r := range recipients {
+		addr, err := mail.ParseAddress(strings.TrimSpace(r))
+		if err != nil {
+			return nil, errors.ErrValidation(fmt.Sprintf("Invalid recipient email: %s", r))
+		}
+		address := strings.ToLower(addr.Address)
+		if seen[address] {
+			continue
+		}
+		seen[address] = true
+		normalized = append(normalized, address)
+	}
+
+	if len(normalized) == 0 {
+		return nil, errors.ErrValidation("At least one recipient is required")
+	}
+	if len(normalized) > MaxReportScheduleRecipients {
+		return nil, errors.ErrValidation(fmt.Sprintf("A schedule can have at most %d recipients", MaxReportScheduleRecipients))
+	}
+
+	return normalized, nil
+}
@@ -0,0 +1,200 @@
+package analytics
+
+import (
+	"context"
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+type mockReportScheduleRepository struct {
+	schedules map[uuid.UUID]*entity.ReportSchedule
+}
+
+func newMockReportScheduleRepo() *mockReportScheduleRepository {
+	return &mockReportScheduleRepository{schedules: make(map[uuid.UUID]*entity.ReportSchedule)}
+}
+
+func (m *mockReportScheduleRepository) Create(ctx context.Context, schedule *entity.ReportSchedule) error {
+	m.schedules[schedule.ID] = schedule
+	return nil
+}
+
+func (m *mockReportScheduleRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.ReportSchedule, error) {
+	schedule, ok := m.schedules[id]
+	if !ok {
+		return nil, stderrors.New("record not found")
+	}
+	copied := *schedule
+	return &copied, nil
+}
+
+func (m *mockReportScheduleRepository) Update(ctx context.Context, schedule *entity.ReportSchedule) error {
+	m.schedules[schedule.ID] = schedule
+	return nil
+}
+
+func (m *mockReportScheduleRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.schedules, id)
+	return nil
+}
+
+func (m *mockReportScheduleRepository) ListByOrganization(ctx context.Context, orgID uuid.UUID) ([]entity.ReportSchedule, error) {
+	var schedules []entity.ReportSchedule
+	for _, s := range m.schedules {
+		if s.OrganizationID == orgID {
+			schedules = append(schedules, *s)
+		}
+	}
+	return schedules, nil
+}
+
+func (m *mockReportScheduleRepository) ListDue(ctx context.Context, now time.Time, limit int) ([]entity.ReportSchedule, error) {
+	return nil, nil
+}
+
+func (m *mockReportScheduleRepository) ClaimRun(ctx context.Context, id uuid.UUID, expectedNextRun, nextRun, runAt time.Time) (bool, error) {
+	return false, nil
+}
+
+func (m *mockReportScheduleRepository) RecordRunResult(ctx context.Context, id uuid.UUID, status, errorMessage string) error {
+	return nil
+}
+
+func validScheduleInput() ReportScheduleInput {
+	return ReportScheduleInput{
+		Name:           "Weekly client report",
+		CronExpression: "0 9 * * 1",
+		Timezone:       "Asia/Kuala_Lumpur",
+		DatePreset:     entity.ReportDatePresetLast7Days,
+		Format:         entity.ReportFormatPDF,
+		Recipients:     []string{"Alice@Example.com", "alice@example.com", "Bob <bob@example.com>"},
+	}
+}
+
+func TestCreateReportSchedule(t *testing.T) {
+	service, _, _ := createTestService()
+	service.SetReportScheduleRepository(newMockReportScheduleRepo())
+	ctx := context.Background()
+	orgID := uuid.New()
+
+	schedule, err := service.CreateReportSchedule(ctx, orgID, nil, false, validScheduleInput())
+	if err != nil {
+		t.Fatalf("CreateReportSchedule failed: %v", err)
+	}
+
+	if len(schedule.Recipients) != 2 || schedule.Recipients[0] != "alice@example.com" || schedule.Recipients[1] != "bob@example.com" {
+		t.Errorf("unexpected recipients %v", schedule.Recipients)
+	}
+	if !schedule.IsActive {
+		t.Error("new schedules should be active")
+	}
+
+	// Next run is Monday 09:00 in Kuala Lumpur
+	if schedule.NextRunAt == nil {
+		t.Fatal("expected next run to be set")
+	}
+	loc, _ := time.LoadLocation("Asia/Kuala_Lumpur")
+	next := schedule.NextRunAt.In(loc)
+	if next.Weekday() != time.Monday || next.Hour() != 9 || next.Minute() != 0 {
+		t.Errorf("unexpected next run %v", next)
+	}
+
+	if _, err := service.GetReportSchedule(ctx, uuid.New(), schedule.ID); err == nil {
+		t.Error("expected not found for a different organization")
+	}
+}
+
+func TestCreateReportSchedule_Validation(t *testing.T) {
+	service, _, _ := createTestService()
+	service.SetReportScheduleRepository(newMockReportScheduleRepo())
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		modify func(*ReportScheduleInput)
+	}{
+		{"missing name", func(in *ReportScheduleInput) { in.Name = " " }},
+		{"invalid cron", func(in *ReportScheduleInput) { in.CronExpression = "every monday" }},
+		{"every minute", func(in *ReportScheduleInput) { in.CronExpression = "* * * * *" }},
+		{"twice an hour", func(in *ReportScheduleInput) { in.CronExpression = "0,30 9 * * 1" }},
+		{"every 30 minutes", func(in *ReportScheduleInput) { in.CronExpression = "@every 30m" }},
+		{"unknown timezone", func(in *ReportScheduleInput) { in.Timezone = "Mars/Olympus" }},
+		{"unknown preset", func(in *ReportScheduleInput) { in.DatePreset = "last_decade" }},
+		{"json format", func(in *ReportScheduleInput) { in.Format = entity.ReportFormatJSON }},
+		{"no recipients", func(in *ReportScheduleInput) { in.Recipients = nil }},
+		{"invalid recipient", func(in *ReportScheduleInput) { in.Recipients = []string{"not-an-email"} }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := validScheduleInput()
+			tt.modify(&input)
+			if _, err := service.CreateReportSchedule(ctx, uuid.New(), nil, false, input); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+}
+
+func TestCreateReportSchedule_AllowsHourly(t *testing.T) {
+	service, _, _ := createTestService()
+	service.SetReportScheduleRepository(newMockReportScheduleRepo())
+	ctx := context.Background()
+
+	for _, expr := range []string{"0 * * * *", "@hourly", "@every 2h", "15 9 1 * *"} {
+		input := validScheduleInput()
+		input.CronExpression = expr
+		if _, err := service.CreateReportSchedule(ctx, uuid.New(), nil, false, input); err != nil {
+			t.Errorf("%q: unexpected error %v", expr, err)
+		}
+	}
+}
+
+func TestReportDatePreset_Resolve(t *testing.T) {
+	// Monday 9 Sep 2024, 09:00
+	now := time.Date(2024, 9, 9, 9, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		preset     entity.ReportDatePreset
+		start, end string
+	}{
+		{entity.ReportDatePresetYesterday, "2024-09-08", "2024-09-08"},
+		{entity.ReportDatePresetLast7Days, "2024-09-02", "2024-09-08"},
+		{entity.ReportDatePresetLast30Days, "2024-08-10", "2024-09-08"},
+		{entity.ReportDatePresetLastWeek, "2024-09-02", "2024-09-08"},
+		{entity.ReportDatePresetLastMonth, "2024-08-01", "2024-08-31"},
+		{entity.ReportDatePresetMonthToDate, "2024-09-01", "2024-09-08"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.preset), func(t *testing.T) {
+			dr := tt.preset.Resolve(now)
+			if got := dr.StartDate.Format("2006-01-02"); got != tt.start {
+				t.Errorf("start = %s, want %s", got, tt.start)
+			}
+			if got := dr.EndDate.Format("2006-01-02"); got != tt.end {
+				t.Errorf("end = %s, want %s", got, tt.end)
+			}
+		})
+	}
+
+	// On the first of the month, month_to_date covers the previous month
+	dr := entity.ReportDatePresetMonthToDate.Resolve(time.Date(2024, 9, 1, 9, 0, 0, 0, time.UTC))
+	if dr.StartDate.Format("2006-01-02") != "2024-08-01" || dr.EndDate.Format("2006-01-02") != "2024-08-31" {
+		t.Errorf("unexpected month_to_date range on the 1st: %v - %v", dr.StartDate, dr.EndDate)
+	}
+}
+
+func TestReportSchedule_ReportDateRangeUsesTimezone(t *testing.T) {
+	schedule := &entity.ReportSchedule{Timezone: "Asia/Kuala_Lumpur", DatePreset: entity.ReportDatePresetYesterday}
+
+	// 17:00 UTC on 8 Sep is already 9 Sep in Kuala Lumpur
+	dr := schedule.ReportDateRange(time.Date(2024, 9, 8, 17, 0, 0, 0, time.UTC))
+	if got := dr.StartDate.Format("2006-01-02"); got != "2024-09-08" {
+		t.Errorf("yesterday in Kuala Lumpur = %s, want 2024-09-08", got)
+	}
+}
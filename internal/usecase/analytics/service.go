@@ -15,9 +15,19 @@ import (
 
 // Service handles analytics and metrics aggregation
 type Service struct {
-	metricsRepo       repository.MetricsRepository
-	campaignRepo      repository.CampaignRepository
-	currencyConverter *currency.Converter
+	metricsRepo        repository.MetricsRepository
+	campaignRepo       repository.CampaignRepository
+	adSetRepo          repository.AdSetRepository
+	orgRepo            repository.OrganizationRepository
+	reportJobRepo      repository.ReportJobRepository
+	reportScheduleRepo repository.ReportScheduleRepository
+	orderRepo          repository.OrderRepository
+	attributionRepo    repository.AttributionRepository
+	salesSummaryRepo   repository.SalesSummaryRepository
+	skuCostRepo        repository.SKUCostRepository
+	budgets            *BudgetService
+	reportListeners    []ReportJobListener
+	currencyConverter  *currency.Converter
 }
 
 // NewService creates a new analytics service
@@ -64,6 +74,7 @@ type DashboardMetrics struct {
 	DailyTrend        []entity.DailyMetricsTrend      `json:"daily_trend"`
 	TopCampaigns      []entity.TopPerformer           `json:"top_campaigns"`
 	Comparison        *entity.MetricsComparison       `json:"comparison,omitempty"`
+	Budget            *entity.BudgetStatus            `json:"budget,omitempty"` // Month-to-date, when the organization has a budget
 }
 
 // GetDashboardMetrics returns comprehensive dashboard metrics
@@ -132,6 +143,13 @@ func (s *Service) GetDashboardMetrics(ctx context.Context, orgID uuid.UUID, date
 		Comparison:        comparison,
 	}
 
+	// Budget usage is shown for the current month regardless of the date range
+	if s.budgets != nil {
+		if status, err := s.budgets.GetStatus(ctx, orgID, time.Now()); err == nil {
+			dashboard.Budget = status
+		}
+	}
+
 	return dashboard, nil
 }
 
@@ -276,7 +294,7 @@ func (s *Service) GetCrossPlatformReport(ctx context.Context, orgID uuid.UUID, d
 
 	// Get trends by platform
 	trends := make(map[entity.Platform][]entity.DailyMetricsTrend)
-	platforms := []entity.Platform{entity.PlatformMeta, entity.PlatformTikTok, entity.PlatformShopee}
+	platforms := []entity.Platform{entity.PlatformMeta, entity.PlatformTikTok, entity.PlatformShopee, entity.PlatformGoogle, entity.PlatformLazada}
 
 	for _, platform := range platforms {
 		filter.Platforms = []entity.Platform{platform}
@@ -481,13 +499,6 @@ func (s *Service) exportToCSV(report *ReportExport) ([]byte, error) {
 	return result, nil
 }
 
-// exportToExcel converts the report to Excel format (simplified - returns CSV with xlsx extension for now)
-func (s *Service) exportToExcel(report *ReportExport) ([]byte, error) {
-	// For a production system, use excelize library
-	// For now, return CSV format that Excel can open
-	return s.exportToCSV(report)
-}
-
 // escapeCSV escapes special characters in CSV fields
 func escapeCSV(s string) string {
 	// If the string contains comma, newline, or quote, wrap in quotes and escape quotes
@@ -685,8 +696,8 @@ func (s *Service) calculateMetricsFromDaily(
 	// Aggregate all daily metrics
 	for _, m := range dailyMetrics {
 		// Currency conversion if needed
-		spend := s.convertCurrency(m.Spend, m.Currency, targetCurrency)
-		revenue := s.convertCurrency(m.ConversionValue, m.Currency, targetCurrency)
+		spend := s.convertCurrencyAt(m.Spend, m.Currency, targetCurrency, m.MetricDate)
+		revenue := s.convertCurrencyAt(m.ConversionValue, m.Currency, targetCurrency, m.MetricDate)
 
 		metrics.TotalSpend = metrics.TotalSpend.Add(spend)
 		metrics.TotalRevenue = metrics.TotalRevenue.Add(revenue)
@@ -766,6 +777,24 @@ func (s *Service) convertCurrency(amount decimal.Decimal, from, to string) decim
 	return converted
 }
 
+// convertCurrencyAt converts amount at the exchange rate of date, so that past
+// spend keeps the rate of the day it was spent
+func (s *Service) convertCurrencyAt(amount decimal.Decimal, from, to string, date time.Time) decimal.Decimal {
+	if from == to || from == "" || to == "" {
+		return amount
+	}
+
+	if amount.IsZero() {
+		return amount
+	}
+
+	converted, err := s.currencyConverter.ConvertAt(amount, from, to, date)
+	if err != nil {
+		return amount
+	}
+	return converted
+}
+
 // GetSupportedCurrencies returns list of supported currencies for conversion
 func (s *Service) GetSupportedCurrencies() []string {
 	return s.currencyConverter.GetSupportedCurrencies()
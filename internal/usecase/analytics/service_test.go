@@ -1,7 +1,11 @@
 package analytics
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"io"
+	"strings"
 	"testing"
 	"time"
 
@@ -107,6 +111,7 @@ func (m *mockMetricsRepository) GetTopPerformingCampaigns(ctx context.Context, o
 
 type mockCampaignRepository struct {
 	campaigns []entity.Campaign
+	summaries []entity.CampaignSummary
 }
 
 func newMockCampaignRepo() *mockCampaignRepository {
@@ -186,7 +191,7 @@ func (m *mockCampaignRepository) BulkUpsert(ctx context.Context, campaigns []ent
 }
 
 func (m *mockCampaignRepository) GetSummaries(ctx context.Context, filter entity.CampaignFilter) ([]entity.CampaignSummary, error) {
-	return nil, nil
+	return m.summaries, nil
 }
 
 func (m *mockCampaignRepository) UpdateLastSynced(ctx context.Context, id uuid.UUID) error {
@@ -1114,3 +1119,78 @@ func TestCTRCalculation(t *testing.T) {
 		t.Errorf("CTR: got %f, want %f", *result.OverallMetrics.CTR, expectedCTR)
 	}
 }
+
+// ============================================================================
+// Export Tests
+// ============================================================================
+
+func TestExportAnalytics_Excel(t *testing.T) {
+	service, metricsRepo, campaignRepo := createTestService()
+	ctx := context.Background()
+
+	metricsRepo.aggregated = &entity.AggregatedMetrics{
+		TotalSpend:       decimal.NewFromFloat(150.5),
+		TotalImpressions: 30000,
+		TotalClicks:      900,
+		Currency:         "MYR",
+	}
+	metricsRepo.platformMetrics = []entity.PlatformMetricsSummary{
+		{Platform: entity.PlatformMeta, Spend: decimal.NewFromInt(100)},
+		{Platform: entity.PlatformShopee, Spend: decimal.NewFromFloat(50.5)},
+	}
+	campaignRepo.summaries = []entity.CampaignSummary{
+		{ID: uuid.New(), Platform: entity.PlatformMeta, Name: "Spring <Sale> & More", TotalSpend: decimal.NewFromInt(100)},
+		{ID: uuid.New(), Platform: entity.PlatformShopee, Name: "Shop Boost", TotalSpend: decimal.NewFromFloat(50.5)},
+	}
+
+	dateRange := entity.DateRange{
+		StartDate: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		EndDate:   time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC),
+	}
+
+	data, err := service.ExportAnalytics(ctx, uuid.New(), dateRange, "xlsx")
+	if err != nil {
+		t.Fatalf("ExportAnalytics failed: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("export is not a valid xlsx archive: %v", err)
+	}
+
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(content)
+	}
+
+	for _, name := range []string{`name="Summary"`, `name="Campaigns"`, `name="Meta"`, `name="Shopee"`} {
+		if !strings.Contains(parts["xl/workbook.xml"], name) {
+			t.Errorf("workbook.xml missing sheet %s", name)
+		}
+	}
+
+	if !strings.Contains(parts["xl/styles.xml"], `formatCode="&#34;MYR&#34; #,##0.00"`) {
+		t.Error("styles.xml missing MYR currency format")
+	}
+
+	campaigns := parts["xl/worksheets/sheet2.xml"]
+	if !strings.Contains(campaigns, `state="frozen"`) {
+		t.Error("campaign sheet header row is not frozen")
+	}
+	if !strings.Contains(campaigns, "Spring &lt;Sale&gt; &amp; More") {
+		t.Error("campaign sheet missing escaped campaign name")
+	}
+	if !strings.Contains(campaigns, "<v>50.5</v>") {
+		t.Error("campaign sheet spend should be written as a numeric cell")
+	}
+
+	if strings.Contains(parts["xl/worksheets/sheet3.xml"], "Shop Boost") {
+		t.Error("Meta sheet should only contain Meta campaigns")
+	}
+}
@@ -0,0 +1,264 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+)
+
+const (
+	// apiKeyDisplayLength is the number of leading key characters kept to tell keys apart
+	apiKeyDisplayLength = len(entity.APIKeyPrefix) + 8
+
+	// apiKeyLastUsedInterval limits how often a key's last use is written
+	apiKeyLastUsedInterval = time.Minute
+)
+
+// APIKeyService manages organization API keys and authenticates requests made with them
+type APIKeyService struct {
+	keyRepo          repository.APIKeyRepository
+	subscriptionRepo repository.SubscriptionRepository
+}
+
+// NewAPIKeyService creates a new API key service. The subscription repository
+// decides which organizations' plans include API access.
+func NewAPIKeyService(keyRepo repository.APIKeyRepository, subscriptionRepo repository.SubscriptionRepository) *APIKeyService {
+	return &APIKeyService{keyRepo: keyRepo, subscriptionRepo: subscriptionRepo}
+}
+
+// APIKeyInput holds the fields of a new API key
+type APIKeyInput struct {
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+// ListKeys lists the API keys of an organization, most recent first
+func (s *APIKeyService) ListKeys(ctx context.Context, orgID uuid.UUID) ([]entity.APIKey, error) {
+	keys, err := s.keyRepo.ListByOrganization(ctx, orgID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load API keys", 500)
+	}
+	if keys == nil {
+		keys = []entity.APIKey{}
+	}
+	return keys, nil
+}
+
+// GetKey returns an API key of an organization
+func (s *APIKeyService) GetKey(ctx context.Context, orgID, keyID uuid.UUID) (*entity.APIKey, error) {
+	key, err := s.keyRepo.GetByID(ctx, keyID)
+	if err != nil || key == nil || key.OrganizationID != orgID {
+		return nil, errors.ErrNotFound("API key")
+	}
+	return key, nil
+}
+
+// CreateKey creates an API key and returns it with the key itself, which is not
+// stored and cannot be retrieved later. A key can only be granted scopes within
+// the permissions of the user creating it.
+func (s *APIKeyService) CreateKey(ctx context.Context, orgID, userID uuid.UUID, permissions []string, input APIKeyInput) (*entity.APIKey, string, error) {
+	if !s.apiAccessEnabled(ctx, orgID) {
+		return nil, "", errors.ErrForbidden("API access is not available on your current plan")
+	}
+
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, "", errors.ErrValidation("Name is required")
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, "", errors.ErrValidation("expires_at must be in the future")
+	}
+
+	if len(input.Scopes) == 0 {
+		return nil, "", errors.ErrValidation("At least one scope is required")
+	}
+	scopes := make([]string, 0, len(input.Scopes))
+	for _, scope := range input.Scopes {
+		scope = strings.ToLower(strings.TrimSpace(scope))
+		if !entity.IsValidAPIKeyScope(scope) {
+			return nil, "", errors.ErrValidation(fmt.Sprintf("Unknown scope: %s", scope))
+		}
+		if !scopePermitted(permissions, scope) {
+			return nil, "", errors.ErrForbidden(fmt.Sprintf("You cannot grant the %s scope", scope))
+		}
+		if !containsString(scopes, scope) {
+			scopes = append(scopes, scope)
+		}
+	}
+
+	rawKey, err := generateAPIKey()
+	if err != nil {
+		return nil, "", errors.ErrInternal("Failed to generate API key")
+	}
+
+	key := &entity.APIKey{
+		BaseEntity:     entity.NewBaseEntity(),
+		OrganizationID: orgID,
+		Name:           name,
+		Prefix:         rawKey[:apiKeyDisplayLength],
+		KeyHash:        hashAPIKey(rawKey),
+		Scopes:         scopes,
+		ExpiresAt:      input.ExpiresAt,
+	}
+	if userID != uuid.Nil {
+		key.CreatedBy = &userID
+	}
+
+	if err := s.keyRepo.Create(ctx, key); err != nil {
+		return nil, "", errors.Wrap(err, errors.ErrCodeInternal, "Failed to create API key", 500)
+	}
+	return key, rawKey, nil
+}
+
+// RotateKey replaces an API key with a new one keeping its name, scopes and
+// expiry. The previous key stops working right away.
+func (s *APIKeyService) RotateKey(ctx context.Context, orgID, keyID uuid.UUID) (*entity.APIKey, string, error) {
+	key, err := s.GetKey(ctx, orgID, keyID)
+	if err != nil {
+		return nil, "", err
+	}
+	if key.RevokedAt != nil {
+		return nil, "", errors.ErrValidation("A revoked API key cannot be rotated")
+	}
+
+	rawKey, err := generateAPIKey()
+	if err != nil {
+		return nil, "", errors.ErrInternal("Failed to generate API key")
+	}
+	now := time.Now()
+	key.Prefix = rawKey[:apiKeyDisplayLength]
+	key.KeyHash = hashAPIKey(rawKey)
+	key.RotatedAt = &now
+
+	if err := s.keyRepo.Update(ctx, key); err != nil {
+		return nil, "", errors.Wrap(err, errors.ErrCodeInternal, "Failed to rotate API key", 500)
+	}
+	return key, rawKey, nil
+}
+
+// RevokeKey permanently disables an API key. The key is kept so its usage
+// remains visible.
+func (s *APIKeyService) RevokeKey(ctx context.Context, orgID, keyID, userID uuid.UUID) error {
+	key, err := s.GetKey(ctx, orgID, keyID)
+	if err != nil {
+		return err
+	}
+	if key.RevokedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	key.RevokedAt = &now
+	if userID != uuid.Nil {
+		key.RevokedBy = &userID
+	}
+	if err := s.keyRepo.Update(ctx, key); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to revoke API key", 500)
+	}
+	return nil
+}
+
+// Authenticate returns the active API key matching rawKey and records its use
+func (s *APIKeyService) Authenticate(ctx context.Context, rawKey, clientIP string) (*entity.APIKey, error) {
+	if !strings.HasPrefix(rawKey, entity.APIKeyPrefix) {
+		return nil, errors.ErrUnauthorized("Invalid API key")
+	}
+
+	key, err := s.keyRepo.GetByHash(ctx, hashAPIKey(rawKey))
+	if err != nil || key == nil {
+		return nil, errors.ErrUnauthorized("Invalid API key")
+	}
+
+	now := time.Now()
+	if key.RevokedAt != nil {
+		return nil, errors.ErrUnauthorized("API key has been revoked")
+	}
+	if !key.IsActive(now) {
+		return nil, errors.ErrUnauthorized("API key has expired")
+	}
+	if !s.apiAccessEnabled(ctx, key.OrganizationID) {
+		return nil, errors.ErrForbidden("API access is not available on your current plan")
+	}
+
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyLastUsedInterval || key.LastUsedIP != clientIP {
+		// Usage tracking must not fail the request
+		if err := s.keyRepo.UpdateLastUsed(ctx, key.ID, now, clientIP); err == nil {
+			key.LastUsedAt = &now
+			key.LastUsedIP = clientIP
+		}
+	}
+
+	return key, nil
+}
+
+// apiAccessEnabled reports whether the organization's plan includes API access.
+// An organization without a subscription is on the free plan, and access is
+// refused when no subscription repository is configured.
+func (s *APIKeyService) apiAccessEnabled(ctx context.Context, orgID uuid.UUID) bool {
+	if s.subscriptionRepo == nil {
+		return false
+	}
+	sub, err := s.subscriptionRepo.GetByOrganization(ctx, orgID)
+	if err != nil || sub == nil {
+		return entity.GetPlanLimits(entity.PlanTierFree).APIAccessEnabled
+	}
+	return sub.GetLimits().APIAccessEnabled
+}
+
+// scopePermitted reports whether permissions cover the scope. A wildcard scope
+// such as "analytics:*" needs every permission of its resource.
+func scopePermitted(permissions []string, scope string) bool {
+	if resource, ok := strings.CutSuffix(scope, ":*"); ok {
+		for _, s := range entity.APIKeyScopes() {
+			if strings.HasPrefix(s, resource+":") && !scopePermitted(permissions, s) {
+				return false
+			}
+		}
+		return true
+	}
+
+	for _, permission := range permissions {
+		if permission == "*" || permission == scope {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(permission, "*"); ok && strings.HasSuffix(prefix, ":") && strings.HasPrefix(scope, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// generateAPIKey returns a new random key with the API key prefix
+func generateAPIKey() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return entity.APIKeyPrefix + hex.EncodeToString(b), nil
+}
+
+// hashAPIKey returns the hex SHA-256 of a key. Keys are random and long, so an
+// unsalted hash is enough to look them up without storing them.
+func hashAPIKey(rawKey string) string {
+	sum := sha256.Sum256([]byte(rawKey))
+	return hex.EncodeToString(sum[:])
+}
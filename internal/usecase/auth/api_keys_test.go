@@ -0,0 +1,253 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	appErrors "github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+)
+
+type mockAPIKeyRepository struct {
+	keys          map[uuid.UUID]*entity.APIKey
+	lastUsedCalls int
+}
+
+func newMockAPIKeyRepo() *mockAPIKeyRepository {
+	return &mockAPIKeyRepository{keys: make(map[uuid.UUID]*entity.APIKey)}
+}
+
+func (m *mockAPIKeyRepository) Create(ctx context.Context, key *entity.APIKey) error {
+	stored := *key
+	m.keys[key.ID] = &stored
+	return nil
+}
+
+func (m *mockAPIKeyRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.APIKey, error) {
+	if key, ok := m.keys[id]; ok {
+		copied := *key
+		return &copied, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockAPIKeyRepository) GetByHash(ctx context.Context, keyHash string) (*entity.APIKey, error) {
+	for _, key := range m.keys {
+		if key.KeyHash == keyHash {
+			copied := *key
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockAPIKeyRepository) Update(ctx context.Context, key *entity.APIKey) error {
+	stored := *key
+	m.keys[key.ID] = &stored
+	return nil
+}
+
+func (m *mockAPIKeyRepository) ListByOrganization(ctx context.Context, orgID uuid.UUID) ([]entity.APIKey, error) {
+	var keys []entity.APIKey
+	for _, key := range m.keys {
+		if key.OrganizationID == orgID {
+			keys = append(keys, *key)
+		}
+	}
+	return keys, nil
+}
+
+func (m *mockAPIKeyRepository) UpdateLastUsed(ctx context.Context, id uuid.UUID, usedAt time.Time, ip string) error {
+	m.lastUsedCalls++
+	if key, ok := m.keys[id]; ok {
+		key.LastUsedAt = &usedAt
+		key.LastUsedIP = ip
+	}
+	return nil
+}
+
+type stubSubscriptionRepository struct {
+	repository.SubscriptionRepository
+	sub *entity.Subscription
+}
+
+func (s *stubSubscriptionRepository) GetByOrganization(ctx context.Context, orgID uuid.UUID) (*entity.Subscription, error) {
+	if s.sub == nil {
+		return nil, errors.New("record not found")
+	}
+	return s.sub, nil
+}
+
+// proSubscriptions puts every organization on a plan with API access
+func proSubscriptions() *stubSubscriptionRepository {
+	return &stubSubscriptionRepository{sub: &entity.Subscription{PlanTier: entity.PlanTierPro}}
+}
+
+func TestAPIKeyService_CreateAndAuthenticate(t *testing.T) {
+	repo := newMockAPIKeyRepo()
+	s := NewAPIKeyService(repo, proSubscriptions())
+	orgID, userID := uuid.New(), uuid.New()
+
+	key, rawKey, err := s.CreateKey(context.Background(), orgID, userID, []string{"*"}, APIKeyInput{
+		Name:   " BI export ",
+		Scopes: []string{"analytics:read", "Analytics:Read", "campaigns:*"},
+	})
+	if err != nil {
+		t.Fatalf("CreateKey() error = %v", err)
+	}
+	if !strings.HasPrefix(rawKey, entity.APIKeyPrefix) || !strings.HasPrefix(rawKey, key.Prefix) {
+		t.Errorf("key %q does not start with %q and its display prefix %q", rawKey, entity.APIKeyPrefix, key.Prefix)
+	}
+	if key.Name != "BI export" || len(key.Scopes) != 2 {
+		t.Errorf("key = %q with scopes %v, want trimmed name and deduplicated scopes", key.Name, key.Scopes)
+	}
+	if stored := repo.keys[key.ID]; stored.KeyHash == "" || strings.Contains(stored.KeyHash, rawKey) {
+		t.Error("stored key is not hashed")
+	}
+
+	got, err := s.Authenticate(context.Background(), rawKey, "10.0.0.1")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if got.ID != key.ID || got.OrganizationID != orgID {
+		t.Errorf("authenticated key %s of %s, want %s of %s", got.ID, got.OrganizationID, key.ID, orgID)
+	}
+	if repo.keys[key.ID].LastUsedAt == nil || repo.keys[key.ID].LastUsedIP != "10.0.0.1" {
+		t.Error("last use not recorded")
+	}
+
+	// Repeated use from the same address within a minute is not written again
+	if _, err := s.Authenticate(context.Background(), rawKey, "10.0.0.1"); err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if repo.lastUsedCalls != 1 {
+		t.Errorf("last use written %d times, want 1", repo.lastUsedCalls)
+	}
+
+	for _, bad := range []string{"", "aak_", rawKey + "x", strings.TrimPrefix(rawKey, entity.APIKeyPrefix)} {
+		if _, err := s.Authenticate(context.Background(), bad, "10.0.0.1"); appErrors.GetHTTPStatus(err) != 401 {
+			t.Errorf("Authenticate(%q) error = %v, want unauthorized", bad, err)
+		}
+	}
+}
+
+func TestAPIKeyService_CreateKeyScopes(t *testing.T) {
+	s := NewAPIKeyService(newMockAPIKeyRepo(), proSubscriptions())
+	admin := []string{"campaigns:read", "campaigns:write", "analytics:read", "analytics:write", "accounts:read", "accounts:write", "users:read", "users:invite"}
+	analyst := []string{"campaigns:read", "analytics:read", "accounts:read"}
+
+	tests := []struct {
+		name        string
+		permissions []string
+		scopes      []string
+		wantErr     bool
+	}{
+		{"admin grants write", admin, []string{"analytics:write"}, false},
+		{"admin grants wildcard covered by permissions", admin, []string{"analytics:*"}, false},
+		{"analyst grants read", analyst, []string{"analytics:read", "campaigns:read"}, false},
+		{"analyst cannot grant write", analyst, []string{"campaigns:write"}, true},
+		{"analyst cannot grant wildcard", analyst, []string{"analytics:*"}, true},
+		{"wildcard permission covers scope", []string{"accounts:*"}, []string{"accounts:write"}, false},
+		{"user scopes are not grantable", []string{"*"}, []string{"users:invite"}, true},
+		{"unknown scope", []string{"*"}, []string{"billing:read"}, true},
+		{"no scopes", []string{"*"}, nil, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := s.CreateKey(context.Background(), uuid.New(), uuid.New(), tt.permissions, APIKeyInput{Name: "key", Scopes: tt.scopes})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CreateKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	past := time.Now().Add(-time.Hour)
+	if _, _, err := s.CreateKey(context.Background(), uuid.New(), uuid.New(), []string{"*"}, APIKeyInput{Name: "key", Scopes: []string{"analytics:read"}, ExpiresAt: &past}); err == nil {
+		t.Error("CreateKey() with past expiry error = nil, want validation error")
+	}
+	if _, _, err := s.CreateKey(context.Background(), uuid.New(), uuid.New(), []string{"*"}, APIKeyInput{Name: " ", Scopes: []string{"analytics:read"}}); err == nil {
+		t.Error("CreateKey() without name error = nil, want validation error")
+	}
+}
+
+func TestAPIKeyService_RotateAndRevoke(t *testing.T) {
+	repo := newMockAPIKeyRepo()
+	s := NewAPIKeyService(repo, proSubscriptions())
+	orgID, userID := uuid.New(), uuid.New()
+
+	key, oldKey, _ := s.CreateKey(context.Background(), orgID, userID, []string{"*"}, APIKeyInput{Name: "key", Scopes: []string{"analytics:read"}})
+
+	rotated, newKey, err := s.RotateKey(context.Background(), orgID, key.ID)
+	if err != nil {
+		t.Fatalf("RotateKey() error = %v", err)
+	}
+	if rotated.ID != key.ID || newKey == oldKey || rotated.RotatedAt == nil {
+		t.Error("RotateKey() did not replace the key in place")
+	}
+	if _, err := s.Authenticate(context.Background(), oldKey, ""); err == nil {
+		t.Error("rotated-out key still authenticates")
+	}
+	if _, err := s.Authenticate(context.Background(), newKey, ""); err != nil {
+		t.Errorf("Authenticate() with rotated key error = %v", err)
+	}
+
+	if _, _, err := s.RotateKey(context.Background(), uuid.New(), key.ID); appErrors.GetHTTPStatus(err) != 404 {
+		t.Errorf("RotateKey() of another organization's key error = %v, want not found", err)
+	}
+
+	if err := s.RevokeKey(context.Background(), orgID, key.ID, userID); err != nil {
+		t.Fatalf("RevokeKey() error = %v", err)
+	}
+	if stored := repo.keys[key.ID]; stored.RevokedAt == nil || stored.RevokedBy == nil || *stored.RevokedBy != userID {
+		t.Error("revocation not recorded")
+	}
+	if _, err := s.Authenticate(context.Background(), newKey, ""); appErrors.GetHTTPStatus(err) != 401 {
+		t.Errorf("Authenticate() with revoked key error = %v, want unauthorized", err)
+	}
+	if _, _, err := s.RotateKey(context.Background(), orgID, key.ID); err == nil {
+		t.Error("RotateKey() of a revoked key error = nil, want validation error")
+	}
+}
+
+func TestAPIKeyService_AuthenticateExpiredAndPlan(t *testing.T) {
+	repo := newMockAPIKeyRepo()
+	subs := proSubscriptions()
+	s := NewAPIKeyService(repo, subs)
+
+	future := time.Now().Add(time.Hour)
+	key, rawKey, _ := s.CreateKey(context.Background(), uuid.New(), uuid.New(), []string{"*"}, APIKeyInput{Name: "key", Scopes: []string{"analytics:read"}, ExpiresAt: &future})
+	past := time.Now().Add(-time.Minute)
+	repo.keys[key.ID].ExpiresAt = &past
+	if _, err := s.Authenticate(context.Background(), rawKey, ""); appErrors.GetHTTPStatus(err) != 401 {
+		t.Errorf("Authenticate() with expired key error = %v, want unauthorized", err)
+	}
+	repo.keys[key.ID].ExpiresAt = &future
+
+	subs.sub = &entity.Subscription{PlanTier: entity.PlanTierFree}
+	if _, err := s.Authenticate(context.Background(), rawKey, ""); appErrors.GetHTTPStatus(err) != 403 {
+		t.Errorf("Authenticate() on the free plan error = %v, want forbidden", err)
+	}
+	if _, _, err := s.CreateKey(context.Background(), uuid.New(), uuid.New(), []string{"*"}, APIKeyInput{Name: "key", Scopes: []string{"analytics:read"}}); appErrors.GetHTTPStatus(err) != 403 {
+		t.Errorf("CreateKey() on the free plan error = %v, want forbidden", err)
+	}
+
+	subs.sub = &entity.Subscription{PlanTier: entity.PlanTierPro}
+	if _, err := s.Authenticate(context.Background(), rawKey, ""); err != nil {
+		t.Errorf("Authenticate() on the pro plan error = %v", err)
+	}
+}
+
+func TestAPIKeyService_RefusesWithoutSubscriptions(t *testing.T) {
+	s := NewAPIKeyService(newMockAPIKeyRepo(), nil)
+
+	_, _, err := s.CreateKey(context.Background(), uuid.New(), uuid.New(), []string{"*"}, APIKeyInput{Name: "key", Scopes: []string{"analytics:read"}})
+	if appErrors.GetHTTPStatus(err) != 403 {
+		t.Errorf("CreateKey() without a subscription repository error = %v, want forbidden", err)
+	}
+}
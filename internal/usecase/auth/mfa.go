@@ -0,0 +1,579 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/crypto"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/ads-aggregator/ads-aggregator/pkg/jwt"
+	"github.com/ads-aggregator/ads-aggregator/pkg/totp"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	// mfaChallengeExpiry is how long the second step of a login can take
+	mfaChallengeExpiry = 5 * time.Minute
+
+	// mfaMaxFailedAttempts is the number of consecutive wrong codes after which
+	// codes are refused for mfaLockoutDuration
+	mfaMaxFailedAttempts = 5
+	mfaLockoutDuration   = 15 * time.Minute
+
+	// recoveryCodeCount is the number of recovery codes issued at a time
+	recoveryCodeCount = 10
+
+	// defaultMFAIssuer names the account in authenticator apps when no app name is configured
+	defaultMFAIssuer = "Ads Aggregator"
+)
+
+// MFAChallengeEnroll is the challenge method of users who must set up a
+// second factor before their first login completes
+const MFAChallengeEnroll = "enroll"
+
+// recoveryCodeCost is the bcrypt cost of recovery code hashes
+var recoveryCodeCost = bcrypt.DefaultCost
+
+// MFAChallenge asks the client to complete a login with a second factor
+type MFAChallenge struct {
+	Token     string    `json:"mfa_token"`
+	Method    string    `json:"method"` // "totp" to enter a code, "enroll" to set up a second factor first
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// MFAEnrollment holds what an authenticator app needs to generate codes
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// MFAStatus describes a user's second factor
+type MFAStatus struct {
+	Enabled                bool       `json:"enabled"`
+	EnabledAt              *time.Time `json:"enabled_at,omitempty"`
+	Pending                bool       `json:"pending"`  // Enrolment started but not confirmed
+	Required               bool       `json:"required"` // Required by the organization for the user's role
+	RecoveryCodesRemaining int        `json:"recovery_codes_remaining"`
+}
+
+// SecuritySettings are the organization's authentication settings
+type SecuritySettings struct {
+	RequireMFA bool `json:"require_mfa"` // Require a second factor for owners and admins
+}
+
+// mfaLogin is a login whose password step is done
+type mfaLogin struct {
+	user *entity.User
+	org  *entity.Organization
+	role entity.UserRole
+}
+
+// SetMFARepository enables TOTP multi-factor authentication. Secrets are
+// encrypted with encryptor when it is not nil.
+func (s *Service) SetMFARepository(mfaRepo repository.UserMFARepository, encryptor *crypto.TokenEncryptor) {
+	s.mfaRepo = mfaRepo
+	s.mfaEncryptor = encryptor
+}
+
+// MFAConfigured reports whether multi-factor authentication is enabled
+func (s *Service) MFAConfigured() bool {
+	return s.mfaRepo != nil
+}
+
+// ============================================================================
+// Login
+// ============================================================================
+
+// mfaChallenge returns the challenge that must be completed before a login
+// issues tokens, or nil when the user has no second factor and none is
+// required. Lookup failures fail the login rather than skip the check.
+func (s *Service) mfaChallenge(ctx context.Context, user *entity.User, org *entity.Organization, role entity.UserRole) (*MFAChallenge, error) {
+	if s.mfaRepo == nil {
+		return nil, nil
+	}
+
+	mfa, err := s.loadMFA(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var method string
+	switch {
+	case mfa.IsEnabled():
+		method = entity.MFAMethodTOTP
+	case org.RequiresMFA(role):
+		method = MFAChallengeEnroll
+	default:
+		return nil, nil
+	}
+
+	token, expiresAt, err := s.jwtManager.GenerateMFAToken(user.ID.String(), org.ID.String(), user.Email, string(role), mfaChallengeExpiry)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to generate MFA challenge", 500)
+	}
+
+	return &MFAChallenge{
+		Token:     token,
+		Method:    method,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// VerifyMFALogin completes a login with a TOTP or recovery code
+func (s *Service) VerifyMFALogin(ctx context.Context, mfaToken, code string) (*AuthResponse, error) {
+	login, err := s.resolveMFAChallenge(ctx, mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	mfa, err := s.loadMFA(ctx, login.user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !mfa.IsEnabled() {
+		return nil, errors.ErrBadRequest("Multi-factor authentication is not enabled")
+	}
+
+	if err := s.checkMFACode(ctx, mfa, code, true, errors.ErrUnauthorized("Invalid verification code")); err != nil {
+		return nil, err
+	}
+
+	return s.completeLogin(ctx, login.user, login.org, login.role)
+}
+
+// BeginMFAEnrollmentForLogin starts enrolment for a user whose organization
+// requires a second factor before their login can complete
+func (s *Service) BeginMFAEnrollmentForLogin(ctx context.Context, mfaToken string) (*MFAEnrollment, error) {
+	login, err := s.resolveMFAChallenge(ctx, mfaToken)
+	if err != nil {
+		return nil, err
+	}
+	return s.beginEnrollment(ctx, login.user)
+}
+
+// ConfirmMFAEnrollmentForLogin enables the second factor enrolled during a
+// login and completes it. The response carries the new recovery codes.
+func (s *Service) ConfirmMFAEnrollmentForLogin(ctx context.Context, mfaToken, code string) (*AuthResponse, error) {
+	login, err := s.resolveMFAChallenge(ctx, mfaToken)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := s.confirmEnrollment(ctx, login.user.ID, code)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.completeLogin(ctx, login.user, login.org, login.role)
+	if err != nil {
+		return nil, err
+	}
+	result.RecoveryCodes = codes
+	return result, nil
+}
+
+// resolveMFAChallenge validates an MFA challenge token and reloads the login
+// it was issued for, so a user disabled or removed meanwhile cannot complete it
+func (s *Service) resolveMFAChallenge(ctx context.Context, mfaToken string) (*mfaLogin, error) {
+	if s.mfaRepo == nil {
+		return nil, errors.ErrBadRequest("Multi-factor authentication is not configured")
+	}
+
+	claims, err := s.jwtManager.ValidateMFAToken(mfaToken)
+	if err != nil {
+		if jwt.IsTokenExpired(err) {
+			return nil, errors.ErrUnauthorized("MFA challenge has expired, please log in again")
+		}
+		return nil, errors.ErrUnauthorized("Invalid MFA challenge")
+	}
+
+	userID, err := uuid.Parse(claims.UserID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized("Invalid MFA challenge")
+	}
+	orgID, err := uuid.Parse(claims.OrganizationID)
+	if err != nil {
+		return nil, errors.ErrUnauthorized("Invalid MFA challenge")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, errors.ErrUnauthorized("Invalid MFA challenge")
+	}
+	if !user.IsActive {
+		return nil, errors.ErrUnauthorized("Account is disabled")
+	}
+
+	membership, err := s.orgMemberRepo.GetByOrgAndUser(ctx, orgID, userID)
+	if err != nil || membership == nil {
+		return nil, errors.ErrUnauthorized("Invalid MFA challenge")
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, errors.ErrInternal("Failed to load organization")
+	}
+
+	return &mfaLogin{user: user, org: org, role: membership.Role}, nil
+}
+
+// ============================================================================
+// Management
+// ============================================================================
+
+// GetMFAStatus returns the second factor status of a user
+func (s *Service) GetMFAStatus(ctx context.Context, userID, orgID uuid.UUID, role entity.UserRole) (*MFAStatus, error) {
+	mfa, err := s.loadMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &MFAStatus{}
+	if org, err := s.orgRepo.GetByID(ctx, orgID); err == nil {
+		status.Required = org.RequiresMFA(role)
+	}
+	if mfa != nil {
+		status.Enabled = mfa.IsEnabled()
+		status.EnabledAt = mfa.EnabledAt
+		status.Pending = !mfa.IsEnabled()
+		if mfa.IsEnabled() {
+			status.RecoveryCodesRemaining = len(mfa.RecoveryCodes)
+		}
+	}
+	return status, nil
+}
+
+// BeginMFAEnrollment generates a new TOTP secret for a user. The second factor
+// is enabled once EnableMFA confirms a code generated from it.
+func (s *Service) BeginMFAEnrollment(ctx context.Context, userID uuid.UUID) (*MFAEnrollment, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, errors.ErrNotFound("User")
+	}
+	return s.beginEnrollment(ctx, user)
+}
+
+// EnableMFA enables a user's pending second factor and returns their recovery
+// codes, which are shown only once
+func (s *Service) EnableMFA(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	return s.confirmEnrollment(ctx, userID, code)
+}
+
+// DisableMFA removes a user's second factor after checking their password and
+// a current code. Users whose organization requires a second factor for their
+// role cannot remove it.
+func (s *Service) DisableMFA(ctx context.Context, userID, orgID uuid.UUID, role entity.UserRole, password, code string) error {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return errors.ErrNotFound("User")
+	}
+	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)); err != nil {
+		return errors.ErrValidation("Invalid password")
+	}
+
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return errors.ErrInternal("Failed to load organization")
+	}
+	if org.RequiresMFA(role) {
+		return errors.ErrForbidden("Your organization requires multi-factor authentication for your role")
+	}
+
+	mfa, err := s.loadMFA(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !mfa.IsEnabled() {
+		return errors.ErrBadRequest("Multi-factor authentication is not enabled")
+	}
+	if err := s.checkMFACode(ctx, mfa, code, true, errors.ErrValidation("Invalid verification code")); err != nil {
+		return err
+	}
+
+	if err := s.mfaRepo.DeleteByUserID(ctx, userID); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to disable multi-factor authentication", 500)
+	}
+	return nil
+}
+
+// RegenerateRecoveryCodes replaces a user's recovery codes after checking a
+// current TOTP code
+func (s *Service) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	mfa, err := s.loadMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !mfa.IsEnabled() {
+		return nil, errors.ErrBadRequest("Multi-factor authentication is not enabled")
+	}
+	if err := s.checkMFACode(ctx, mfa, code, false, errors.ErrValidation("Invalid verification code")); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.issueRecoveryCodes(mfa)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.mfaRepo.Save(ctx, mfa); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to save recovery codes", 500)
+	}
+	return codes, nil
+}
+
+// GetSecuritySettings returns an organization's authentication settings
+func (s *Service) GetSecuritySettings(ctx context.Context, orgID uuid.UUID) (*SecuritySettings, error) {
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, errors.ErrNotFound("Organization")
+	}
+	required, _ := org.Settings[entity.OrgSettingRequireMFA].(bool)
+	return &SecuritySettings{RequireMFA: required}, nil
+}
+
+// UpdateSecuritySettings updates an organization's authentication settings.
+// Owners and admins without a second factor are asked to set one up at their
+// next login once MFA is required.
+func (s *Service) UpdateSecuritySettings(ctx context.Context, orgID uuid.UUID, settings SecuritySettings) (*SecuritySettings, error) {
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, errors.ErrNotFound("Organization")
+	}
+
+	if org.Settings == nil {
+		org.Settings = entity.JSONMap{}
+	}
+	org.Settings[entity.OrgSettingRequireMFA] = settings.RequireMFA
+
+	if err := s.orgRepo.Update(ctx, org); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to update security settings", 500)
+	}
+	return &settings, nil
+}
+
+// ============================================================================
+// Helpers
+// ============================================================================
+
+// loadMFA returns a user's second factor, or nil if they have none
+func (s *Service) loadMFA(ctx context.Context, userID uuid.UUID) (*entity.UserMFA, error) {
+	if s.mfaRepo == nil {
+		return nil, errors.ErrBadRequest("Multi-factor authentication is not configured")
+	}
+	mfa, err := s.mfaRepo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load multi-factor authentication", 500)
+	}
+	return mfa, nil
+}
+
+// beginEnrollment stores a new pending secret for the user, replacing any
+// earlier pending one
+func (s *Service) beginEnrollment(ctx context.Context, user *entity.User) (*MFAEnrollment, error) {
+	mfa, err := s.loadMFA(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.IsEnabled() {
+		return nil, errors.ErrConflict("Multi-factor authentication is already enabled")
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, errors.ErrInternal("Failed to generate MFA secret")
+	}
+	sealed, err := s.sealMFASecret(secret)
+	if err != nil {
+		return nil, errors.ErrInternal("Failed to encrypt MFA secret")
+	}
+
+	if mfa == nil {
+		mfa = &entity.UserMFA{
+			BaseEntity: entity.NewBaseEntity(),
+			UserID:     user.ID,
+		}
+	}
+	mfa.Secret = sealed
+	mfa.LastUsedStep = 0
+	mfa.FailedAttempts = 0
+	mfa.LockedUntil = nil
+
+	if err := s.mfaRepo.Save(ctx, mfa); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to save MFA enrolment", 500)
+	}
+
+	issuer := s.emailConfig.AppName
+	if issuer == "" {
+		issuer = defaultMFAIssuer
+	}
+	return &MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(secret, issuer, user.Email),
+	}, nil
+}
+
+// confirmEnrollment enables a pending second factor with a first code and
+// returns new recovery codes
+func (s *Service) confirmEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	mfa, err := s.loadMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa == nil {
+		return nil, errors.ErrBadRequest("Start multi-factor authentication enrolment first")
+	}
+	if mfa.IsEnabled() {
+		return nil, errors.ErrConflict("Multi-factor authentication is already enabled")
+	}
+	if err := s.checkMFACode(ctx, mfa, code, false, errors.ErrValidation("Invalid verification code")); err != nil {
+		return nil, err
+	}
+
+	codes, err := s.issueRecoveryCodes(mfa)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	mfa.EnabledAt = &now
+
+	if err := s.mfaRepo.Save(ctx, mfa); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to enable multi-factor authentication", 500)
+	}
+	return codes, nil
+}
+
+// checkMFACode accepts a TOTP code, or a recovery code when allowed, and
+// returns invalid otherwise. Accepted TOTP steps and recovery codes cannot be
+// used again, and repeated wrong codes lock the factor for a while. A code is
+// only accepted once it is marked used in the store, so concurrent logins
+// cannot both use it.
+func (s *Service) checkMFACode(ctx context.Context, mfa *entity.UserMFA, code string, allowRecovery bool, invalid error) error {
+	now := time.Now()
+	if mfa.IsLocked(now) {
+		return errors.ErrForbidden("Too many invalid verification codes, please try again later")
+	}
+
+	var use func() (bool, error)
+	code = strings.TrimSpace(code)
+	if len(code) == totp.Digits {
+		secret, err := s.openMFASecret(mfa.Secret)
+		if err != nil {
+			return errors.ErrInternal("Failed to decrypt MFA secret")
+		}
+		if step, ok := totp.Validate(secret, code, now); ok && step > mfa.LastUsedStep {
+			mfa.LastUsedStep = step
+			use = func() (bool, error) { return s.mfaRepo.UseStep(ctx, mfa) }
+		}
+	} else if allowRecovery {
+		if i := matchRecoveryCode(mfa.RecoveryCodes, code); i >= 0 {
+			usedHash := mfa.RecoveryCodes[i]
+			mfa.RecoveryCodes = append(mfa.RecoveryCodes[:i:i], mfa.RecoveryCodes[i+1:]...)
+			use = func() (bool, error) { return s.mfaRepo.UseRecoveryCode(ctx, mfa, usedHash) }
+		}
+	}
+
+	if use != nil {
+		accepted, err := use()
+		if err != nil {
+			return errors.Wrap(err, errors.ErrCodeInternal, "Failed to save multi-factor authentication", 500)
+		}
+		if accepted {
+			mfa.FailedAttempts = 0
+			mfa.LockedUntil = nil
+			return nil
+		}
+
+		// Another login used the code first, so reload the factor to count the attempt
+		current, err := s.mfaRepo.GetByUserID(ctx, mfa.UserID)
+		if err != nil || current == nil {
+			return invalid
+		}
+		*mfa = *current
+	}
+
+	mfa.FailedAttempts++
+	if mfa.FailedAttempts >= mfaMaxFailedAttempts {
+		lockedUntil := now.Add(mfaLockoutDuration)
+		mfa.LockedUntil = &lockedUntil
+		mfa.FailedAttempts = 0
+	}
+
+	if err := s.mfaRepo.Save(ctx, mfa); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to save multi-factor authentication", 500)
+	}
+	return invalid
+}
+
+// issueRecoveryCodes replaces the recovery codes of mfa and returns them. Only
+// their bcrypt hashes are kept.
+func (s *Service) issueRecoveryCodes(mfa *entity.UserMFA) ([]string, error) {
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, errors.ErrInternal("Failed to generate recovery codes")
+		}
+		hash, err := bcrypt.GenerateFromPassword([]byte(normalizeRecoveryCode(code)), recoveryCodeCost)
+		if err != nil {
+			return nil, errors.ErrInternal("Failed to hash recovery codes")
+		}
+		codes[i] = code
+		hashes[i] = string(hash)
+	}
+
+	now := time.Now()
+	mfa.RecoveryCodes = hashes
+	mfa.RecoveryCodesGeneratedAt = &now
+	return codes, nil
+}
+
+func (s *Service) sealMFASecret(secret string) (string, error) {
+	if s.mfaEncryptor == nil {
+		return secret, nil
+	}
+	return s.mfaEncryptor.Encrypt(secret)
+}
+
+func (s *Service) openMFASecret(stored string) (string, error) {
+	if s.mfaEncryptor == nil {
+		return stored, nil
+	}
+	return s.mfaEncryptor.Decrypt(stored)
+}
+
+// matchRecoveryCode returns the index of the hash matching code, or -1
+func matchRecoveryCode(hashes []string, code string) int {
+	code = normalizeRecoveryCode(code)
+	if code == "" {
+		return -1
+	}
+	for i, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(code)) == nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// generateRecoveryCode returns a random code formatted as two groups of five
+// hex characters, e.g. "3f9a1-c07be"
+func generateRecoveryCode() (string, error) {
+	b := make([]byte, 5)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	code := hex.EncodeToString(b)
+	return code[:5] + "-" + code[5:], nil
+}
+
+// normalizeRecoveryCode ignores case, spaces and dashes in entered codes
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
@@ -0,0 +1,347 @@
+package auth
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/pkg/crypto"
+	appErrors "github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/ads-aggregator/ads-aggregator/pkg/jwt"
+	"github.com/ads-aggregator/ads-aggregator/pkg/totp"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type mockUserMFARepository struct {
+	factors map[uuid.UUID]*entity.UserMFA
+}
+
+func newMockUserMFARepo() *mockUserMFARepository {
+	return &mockUserMFARepository{factors: make(map[uuid.UUID]*entity.UserMFA)}
+}
+
+func (m *mockUserMFARepository) GetByUserID(ctx context.Context, userID uuid.UUID) (*entity.UserMFA, error) {
+	if mfa, ok := m.factors[userID]; ok {
+		copied := *mfa
+		copied.RecoveryCodes = append([]string(nil), mfa.RecoveryCodes...)
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *mockUserMFARepository) Save(ctx context.Context, mfa *entity.UserMFA) error {
+	stored := *mfa
+	stored.RecoveryCodes = append([]string(nil), mfa.RecoveryCodes...)
+	m.factors[mfa.UserID] = &stored
+	return nil
+}
+
+func (m *mockUserMFARepository) UseStep(ctx context.Context, mfa *entity.UserMFA) (bool, error) {
+	stored, ok := m.factors[mfa.UserID]
+	if !ok || stored.LastUsedStep >= mfa.LastUsedStep {
+		return false, nil
+	}
+	stored.LastUsedStep = mfa.LastUsedStep
+	stored.FailedAttempts = 0
+	stored.LockedUntil = nil
+	return true, nil
+}
+
+func (m *mockUserMFARepository) UseRecoveryCode(ctx context.Context, mfa *entity.UserMFA, usedHash string) (bool, error) {
+	stored, ok := m.factors[mfa.UserID]
+	if !ok || matchHash(stored.RecoveryCodes, usedHash) < 0 {
+		return false, nil
+	}
+	stored.RecoveryCodes = append([]string(nil), mfa.RecoveryCodes...)
+	stored.FailedAttempts = 0
+	stored.LockedUntil = nil
+	return true, nil
+}
+
+func matchHash(hashes []string, hash string) int {
+	for i := range hashes {
+		if hashes[i] == hash {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *mockUserMFARepository) DeleteByUserID(ctx context.Context, userID uuid.UUID) error {
+	delete(m.factors, userID)
+	return nil
+}
+
+type mfaTestEnv struct {
+	service *Service
+	mfaRepo *mockUserMFARepository
+	user    *entity.User
+	org     *entity.Organization
+}
+
+const mfaTestPassword = "correct horse battery"
+
+func newMFATestEnv(t *testing.T, role entity.UserRole, requireMFA bool) *mfaTestEnv {
+	t.Helper()
+	recoveryCodeCost = bcrypt.MinCost
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte(mfaTestPassword), bcrypt.MinCost)
+	user := &entity.User{BaseEntity: entity.NewBaseEntity(), Email: "owner@example.com", PasswordHash: string(hash), IsActive: true}
+	org := &entity.Organization{BaseEntity: entity.NewBaseEntity(), Name: "Acme", Settings: entity.JSONMap{entity.OrgSettingRequireMFA: requireMFA}}
+
+	userRepo := newMockUserRepo()
+	orgRepo := newMockOrgRepo()
+	memberRepo := newMockOrgMemberRepo()
+	userRepo.Create(context.Background(), user)
+	orgRepo.Create(context.Background(), org)
+	memberRepo.Create(context.Background(), &entity.OrganizationMember{
+		BaseEntity:     entity.NewBaseEntity(),
+		OrganizationID: org.ID,
+		UserID:         user.ID,
+		Role:           role,
+		IsActive:       true,
+	})
+
+	encryptor, err := crypto.NewTokenEncryptor("12345678901234567890123456789012")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mfaRepo := newMockUserMFARepo()
+	s := NewService(userRepo, orgRepo, memberRepo, nil, nil, jwt.NewManager("test-secret", time.Hour, 24*time.Hour), nil, nil, nil, EmailConfig{AppName: "Ads Aggregator"})
+	s.SetMFARepository(mfaRepo, encryptor)
+
+	return &mfaTestEnv{service: s, mfaRepo: mfaRepo, user: user, org: org}
+}
+
+func (e *mfaTestEnv) login(t *testing.T) *AuthResponse {
+	t.Helper()
+	result, err := e.service.Login(context.Background(), &LoginRequest{Email: e.user.Email, Password: mfaTestPassword})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+	return result
+}
+
+// codeAt returns the TOTP code of secret offset steps from now
+func codeAt(t *testing.T, secret string, offset int) string {
+	t.Helper()
+	code, err := totp.Code(secret, time.Now().Add(time.Duration(offset*totp.Period)*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+func TestLogin_WithoutMFA(t *testing.T) {
+	env := newMFATestEnv(t, entity.RoleOwner, false)
+
+	result := env.login(t)
+	if result.MFA != nil || result.Tokens == nil {
+		t.Errorf("Login() = MFA %v, tokens %v, want tokens without a challenge", result.MFA, result.Tokens)
+	}
+}
+
+func TestMFA_EnrollEnableAndLogin(t *testing.T) {
+	env := newMFATestEnv(t, entity.RoleAnalyst, false)
+	ctx := context.Background()
+
+	enrollment, err := env.service.BeginMFAEnrollment(ctx, env.user.ID)
+	if err != nil {
+		t.Fatalf("BeginMFAEnrollment() error = %v", err)
+	}
+	if stored := env.mfaRepo.factors[env.user.ID]; stored.Secret == enrollment.Secret || stored.IsEnabled() {
+		t.Error("pending secret is stored unencrypted or already enabled")
+	}
+
+	// A pending factor does not affect login
+	if result := env.login(t); result.MFA != nil {
+		t.Error("Login() asked for a second factor before enrolment was confirmed")
+	}
+
+	if _, err := env.service.EnableMFA(ctx, env.user.ID, "000000"); err == nil {
+		t.Error("EnableMFA() with a wrong code error = nil")
+	}
+	codes, err := env.service.EnableMFA(ctx, env.user.ID, codeAt(t, enrollment.Secret, 0))
+	if err != nil {
+		t.Fatalf("EnableMFA() error = %v", err)
+	}
+	if len(codes) != recoveryCodeCount {
+		t.Fatalf("got %d recovery codes, want %d", len(codes), recoveryCodeCount)
+	}
+	for _, hash := range env.mfaRepo.factors[env.user.ID].RecoveryCodes {
+		for _, code := range codes {
+			if hash == code {
+				t.Fatal("recovery code stored in plain text")
+			}
+		}
+	}
+
+	result := env.login(t)
+	if result.MFA == nil || result.MFA.Method != entity.MFAMethodTOTP || result.Tokens != nil {
+		t.Fatalf("Login() = MFA %+v, tokens %v, want a TOTP challenge and no tokens", result.MFA, result.Tokens)
+	}
+
+	// The code used to enable the factor cannot be replayed
+	if _, err := env.service.VerifyMFALogin(ctx, result.MFA.Token, codeAt(t, enrollment.Secret, 0)); appErrors.GetHTTPStatus(err) != 401 {
+		t.Errorf("VerifyMFALogin() with a used code error = %v, want unauthorized", err)
+	}
+	verified, err := env.service.VerifyMFALogin(ctx, result.MFA.Token, codeAt(t, enrollment.Secret, 1))
+	if err != nil {
+		t.Fatalf("VerifyMFALogin() error = %v", err)
+	}
+	if verified.Tokens == nil {
+		t.Error("VerifyMFALogin() issued no tokens")
+	}
+
+	// Recovery codes work once, in any case and with or without the dash
+	upper := codes[3][:5] + codes[3][6:]
+	if _, err := env.service.VerifyMFALogin(ctx, result.MFA.Token, upper); err != nil {
+		t.Errorf("VerifyMFALogin() with a recovery code error = %v", err)
+	}
+	if _, err := env.service.VerifyMFALogin(ctx, result.MFA.Token, codes[3]); err == nil {
+		t.Error("recovery code accepted twice")
+	}
+	if remaining := len(env.mfaRepo.factors[env.user.ID].RecoveryCodes); remaining != recoveryCodeCount-1 {
+		t.Errorf("%d recovery codes left, want %d", remaining, recoveryCodeCount-1)
+	}
+
+	// Tokens of other types are not challenge tokens
+	if _, err := env.service.VerifyMFALogin(ctx, verified.Tokens.AccessToken, codeAt(t, enrollment.Secret, -1)); appErrors.GetHTTPStatus(err) != 401 {
+		t.Errorf("VerifyMFALogin() with an access token error = %v, want unauthorized", err)
+	}
+
+	if err := env.service.DisableMFA(ctx, env.user.ID, env.org.ID, entity.RoleAnalyst, "wrong password", codes[0]); err == nil {
+		t.Error("DisableMFA() with a wrong password error = nil")
+	}
+	if err := env.service.DisableMFA(ctx, env.user.ID, env.org.ID, entity.RoleAnalyst, mfaTestPassword, codes[0]); err != nil {
+		t.Fatalf("DisableMFA() error = %v", err)
+	}
+	if result := env.login(t); result.MFA != nil {
+		t.Error("Login() asked for a second factor after it was disabled")
+	}
+}
+
+func TestMFA_RequiredByOrganization(t *testing.T) {
+	env := newMFATestEnv(t, entity.RoleOwner, true)
+	ctx := context.Background()
+
+	result := env.login(t)
+	if result.MFA == nil || result.MFA.Method != MFAChallengeEnroll || result.Tokens != nil {
+		t.Fatalf("Login() = MFA %+v, tokens %v, want an enrolment challenge and no tokens", result.MFA, result.Tokens)
+	}
+
+	// An enrolment challenge cannot be completed without enrolling
+	if _, err := env.service.VerifyMFALogin(ctx, result.MFA.Token, "123456"); err == nil {
+		t.Error("VerifyMFALogin() without a second factor error = nil")
+	}
+
+	enrollment, err := env.service.BeginMFAEnrollmentForLogin(ctx, result.MFA.Token)
+	if err != nil {
+		t.Fatalf("BeginMFAEnrollmentForLogin() error = %v", err)
+	}
+	completed, err := env.service.ConfirmMFAEnrollmentForLogin(ctx, result.MFA.Token, codeAt(t, enrollment.Secret, 0))
+	if err != nil {
+		t.Fatalf("ConfirmMFAEnrollmentForLogin() error = %v", err)
+	}
+	if completed.Tokens == nil || len(completed.RecoveryCodes) != recoveryCodeCount {
+		t.Error("ConfirmMFAEnrollmentForLogin() did not issue tokens and recovery codes")
+	}
+
+	// Once enabled, a challenge token cannot be used to replace the factor
+	if _, err := env.service.BeginMFAEnrollmentForLogin(ctx, result.MFA.Token); appErrors.GetHTTPStatus(err) != 409 {
+		t.Errorf("BeginMFAEnrollmentForLogin() with MFA enabled error = %v, want conflict", err)
+	}
+
+	status, err := env.service.GetMFAStatus(ctx, env.user.ID, env.org.ID, entity.RoleOwner)
+	if err != nil {
+		t.Fatalf("GetMFAStatus() error = %v", err)
+	}
+	if !status.Enabled || !status.Required || status.RecoveryCodesRemaining != recoveryCodeCount {
+		t.Errorf("GetMFAStatus() = %+v", status)
+	}
+
+	if err := env.service.DisableMFA(ctx, env.user.ID, env.org.ID, entity.RoleOwner, mfaTestPassword, completed.RecoveryCodes[0]); appErrors.GetHTTPStatus(err) != 403 {
+		t.Errorf("DisableMFA() while required error = %v, want forbidden", err)
+	}
+}
+
+func TestMFA_Lockout(t *testing.T) {
+	env := newMFATestEnv(t, entity.RoleAdmin, false)
+	ctx := context.Background()
+
+	enrollment, _ := env.service.BeginMFAEnrollment(ctx, env.user.ID)
+	if _, err := env.service.EnableMFA(ctx, env.user.ID, codeAt(t, enrollment.Secret, 0)); err != nil {
+		t.Fatalf("EnableMFA() error = %v", err)
+	}
+	challenge := env.login(t).MFA
+
+	for i := 0; i < mfaMaxFailedAttempts; i++ {
+		if _, err := env.service.VerifyMFALogin(ctx, challenge.Token, "wrong-code"); appErrors.GetHTTPStatus(err) != 401 {
+			t.Fatalf("attempt %d error = %v, want unauthorized", i+1, err)
+		}
+	}
+	if _, err := env.service.VerifyMFALogin(ctx, challenge.Token, codeAt(t, enrollment.Secret, 1)); appErrors.GetHTTPStatus(err) != 403 {
+		t.Errorf("VerifyMFALogin() while locked error = %v, want forbidden", err)
+	}
+}
+
+func TestMFA_ConcurrentLoginsCannotShareACode(t *testing.T) {
+	env := newMFATestEnv(t, entity.RoleAdmin, false)
+	ctx := context.Background()
+
+	enrollment, _ := env.service.BeginMFAEnrollment(ctx, env.user.ID)
+	codes, err := env.service.EnableMFA(ctx, env.user.ID, codeAt(t, enrollment.Secret, -1))
+	if err != nil {
+		t.Fatalf("EnableMFA() error = %v", err)
+	}
+
+	// Both logins loaded the factor before either accepted a code
+	first, _ := env.mfaRepo.GetByUserID(ctx, env.user.ID)
+	second, _ := env.mfaRepo.GetByUserID(ctx, env.user.ID)
+	invalid := appErrors.ErrUnauthorized("Invalid verification code")
+
+	code := codeAt(t, enrollment.Secret, 0)
+	if err := env.service.checkMFACode(ctx, first, code, true, invalid); err != nil {
+		t.Fatalf("checkMFACode() error = %v", err)
+	}
+	if err := env.service.checkMFACode(ctx, second, code, true, invalid); err != invalid {
+		t.Errorf("checkMFACode() with a code used concurrently error = %v, want invalid", err)
+	}
+
+	first, _ = env.mfaRepo.GetByUserID(ctx, env.user.ID)
+	second, _ = env.mfaRepo.GetByUserID(ctx, env.user.ID)
+	if err := env.service.checkMFACode(ctx, first, codes[0], true, invalid); err != nil {
+		t.Fatalf("checkMFACode() with a recovery code error = %v", err)
+	}
+	if err := env.service.checkMFACode(ctx, second, codes[0], true, invalid); err != invalid {
+		t.Errorf("checkMFACode() with a recovery code used concurrently error = %v, want invalid", err)
+	}
+	if stored := env.mfaRepo.factors[env.user.ID]; len(stored.RecoveryCodes) != recoveryCodeCount-1 || stored.FailedAttempts != 1 {
+		t.Errorf("stored factor has %d recovery codes and %d failed attempts, want %d and 1",
+			len(stored.RecoveryCodes), stored.FailedAttempts, recoveryCodeCount-1)
+	}
+}
+
+func TestOrganization_RequiresMFA(t *testing.T) {
+	org := &entity.Organization{Settings: entity.JSONMap{entity.OrgSettingRequireMFA: true}}
+
+	tests := []struct {
+		role entity.UserRole
+		want bool
+	}{
+		{entity.RoleOwner, true},
+		{entity.RoleAdmin, true},
+		{entity.RoleAnalyst, false},
+		{entity.RoleViewer, false},
+	}
+	for _, tt := range tests {
+		if got := org.RequiresMFA(tt.role); got != tt.want {
+			t.Errorf("RequiresMFA(%s) = %v, want %v", tt.role, got, tt.want)
+		}
+	}
+	if (&entity.Organization{}).RequiresMFA(entity.RoleOwner) {
+		t.Error("RequiresMFA() without settings = true")
+	}
+}
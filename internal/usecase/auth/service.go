@@ -10,6 +10,7 @@ import (
 	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
 	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
 	"github.com/ads-aggregator/ads-aggregator/internal/domain/service"
+	"github.com/ads-aggregator/ads-aggregator/pkg/crypto"
 	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
 	"github.com/ads-aggregator/ads-aggregator/pkg/jwt"
 	"github.com/google/uuid"
@@ -28,6 +29,10 @@ type Service struct {
 	stateStore            StateStore
 	emailSender           EmailSender
 	emailConfig           EmailConfig
+	mfaRepo               repository.UserMFARepository
+	mfaEncryptor          *crypto.TokenEncryptor
+	sessionRepo           repository.UserSessionRepository
+	accountListeners      []AccountConnectedListener
 }
 
 // EmailSender interface for sending emails
@@ -51,6 +56,13 @@ type StateStore interface {
 	Delete(ctx context.Context, stateID string) error
 }
 
+// AccountConnectedListener is notified when a platform account is connected
+// for the first time, e.g. to backfill its history. Errors are the listener's
+// to handle; they never fail the OAuth callback.
+type AccountConnectedListener interface {
+	AccountConnected(ctx context.Context, account *entity.ConnectedAccount, userID uuid.UUID)
+}
+
 // ConnectorRegistry provides access to platform connectors
 type ConnectorRegistry interface {
 	Get(platform entity.Platform) (service.PlatformConnector, bool)
@@ -83,6 +95,11 @@ func NewService(
 	}
 }
 
+// AddAccountListener adds a listener notified when a platform account is first connected
+func (s *Service) AddAccountListener(listener AccountConnectedListener) {
+	s.accountListeners = append(s.accountListeners, listener)
+}
+
 // ============================================================================
 // User Authentication
 // ============================================================================
@@ -102,11 +119,14 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
-// AuthResponse represents authentication response
+// AuthResponse represents authentication response. When the login needs a
+// second factor, MFA is set instead of Tokens.
 type AuthResponse struct {
-	User         *entity.User         `json:"user"`
-	Organization *entity.Organization `json:"organization,omitempty"`
-	Tokens       *jwt.TokenPair       `json:"tokens"`
+	User          *entity.User         `json:"user"`
+	Organization  *entity.Organization `json:"organization,omitempty"`
+	Tokens        *jwt.TokenPair       `json:"tokens"`
+	MFA           *MFAChallenge        `json:"mfa,omitempty"`
+	RecoveryCodes []string             `json:"recovery_codes,omitempty"`
 }
 
 // Register creates a new user and organization
@@ -166,13 +186,7 @@ func (s *Service) Register(ctx context.Context, req *RegisterRequest) (*AuthResp
 	}
 
 	// Generate tokens
-	tokens, err := s.jwtManager.GenerateTokenPair(
-		user.ID.String(),
-		org.ID.String(),
-		user.Email,
-		string(entity.RoleOwner),
-		[]string{"*"}, // Full permissions for owner
-	)
+	tokens, err := s.issueTokens(ctx, user, org, entity.RoleOwner)
 	if err != nil {
 		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to generate tokens", 500)
 	}
@@ -215,17 +229,29 @@ func (s *Service) Login(ctx context.Context, req *LoginRequest) (*AuthResponse,
 		return nil, errors.ErrInternal("Failed to load organization")
 	}
 
+	// Ask for a second factor before issuing tokens when one is set up or required
+	challenge, err := s.mfaChallenge(ctx, user, org, membership.Role)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != nil {
+		return &AuthResponse{
+			User:         user,
+			Organization: org,
+			MFA:          challenge,
+		}, nil
+	}
+
+	return s.completeLogin(ctx, user, org, membership.Role)
+}
+
+// completeLogin records the login and issues the user's tokens
+func (s *Service) completeLogin(ctx context.Context, user *entity.User, org *entity.Organization, role entity.UserRole) (*AuthResponse, error) {
 	// Update last login
 	_ = s.userRepo.UpdateLastLogin(ctx, user.ID)
 
 	// Generate tokens
-	tokens, err := s.jwtManager.GenerateTokenPair(
-		user.ID.String(),
-		org.ID.String(),
-		user.Email,
-		string(membership.Role),
-		s.getPermissionsForRole(membership.Role),
-	)
+	tokens, err := s.issueTokens(ctx, user, org, role)
 	if err != nil {
 		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to generate tokens", 500)
 	}
@@ -237,8 +263,18 @@ func (s *Service) Login(ctx context.Context, req *LoginRequest) (*AuthResponse,
 	}, nil
 }
 
-// RefreshToken refreshes the access token using refresh token
+// RefreshToken refreshes the access token using refresh token. With
+// server-side sessions the refresh token is rotated: it can be exchanged once,
+// and exchanging it again revokes its session.
 func (s *Service) RefreshToken(ctx context.Context, refreshToken string) (*jwt.TokenPair, error) {
+	if s.sessionRepo != nil {
+		claims, err := s.jwtManager.ValidateRefreshToken(refreshToken)
+		if err != nil {
+			return nil, errors.ErrUnauthorized("Invalid refresh token")
+		}
+		return s.rotateSession(ctx, claims)
+	}
+
 	tokens, err := s.jwtManager.RefreshTokenPair(refreshToken)
 	if err != nil {
 		return nil, errors.ErrUnauthorized("Invalid refresh token")
@@ -258,7 +294,8 @@ func (s *Service) ValidateToken(ctx context.Context, accessToken string) (*jwt.C
 	return claims, nil
 }
 
-// ChangePassword changes a user's password
+// ChangePassword changes a user's password and ends their other sessions. The
+// session kept is the one in the context's ClientInfo.
 func (s *Service) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassword, newPassword string) error {
 	user, err := s.userRepo.GetByID(ctx, userID)
 	if err != nil {
@@ -277,7 +314,14 @@ func (s *Service) ChangePassword(ctx context.Context, userID uuid.UUID, oldPassw
 	}
 
 	user.PasswordHash = string(hashedPassword)
-	return s.userRepo.Update(ctx, user)
+	if err := s.userRepo.Update(ctx, user); err != nil {
+		return err
+	}
+
+	if err := s.endSessionsAfterPasswordChange(ctx, userID, clientInfoFrom(ctx).SessionID); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to end other sessions", 500)
+	}
+	return nil
 }
 
 // ============================================================================
@@ -450,6 +494,11 @@ func (s *Service) ResetPassword(ctx context.Context, tokenStr, newPassword strin
 	// Delete all password reset tokens for this user
 	_ = s.verificationTokenRepo.DeleteByUserAndType(ctx, token.UserID, entity.TokenTypePasswordReset)
 
+	// End every session, the reset may be recovering a compromised account
+	if err := s.endSessionsAfterPasswordChange(ctx, token.UserID, uuid.Nil); err != nil {
+		return errors.ErrInternal("Failed to end sessions")
+	}
+
 	return nil
 }
 
@@ -687,6 +736,10 @@ func (s *Service) HandleOAuthCallback(ctx context.Context, platform entity.Platf
 		return nil, "", errors.ErrInternal("Failed to create connected account")
 	}
 
+	for _, listener := range s.accountListeners {
+		listener.AccountConnected(ctx, account, oauthState.UserID)
+	}
+
 	return account, oauthState.RedirectURL, nil
 }
 
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/service"
 	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
 	"github.com/google/uuid"
 )
@@ -64,6 +65,20 @@ func (m *mockUserRepository) VerifyEmail(ctx context.Context, id uuid.UUID) erro
 	return nil
 }
 
+func (m *mockUserRepository) UpdatePassword(ctx context.Context, id uuid.UUID, passwordHash string) error {
+	if user, ok := m.users[id]; ok {
+		user.PasswordHash = passwordHash
+	}
+	return nil
+}
+
+func (m *mockUserRepository) UpdateProfile(ctx context.Context, id uuid.UUID, firstName, lastName, phone string) error {
+	if user, ok := m.users[id]; ok {
+		user.FirstName, user.LastName, user.Phone = firstName, lastName, phone
+	}
+	return nil
+}
+
 type mockOrgRepository struct {
 	orgs map[uuid.UUID]*entity.Organization
 }
@@ -526,6 +541,73 @@ func TestDisconnectPlatform(t *testing.T) {
 	}
 }
 
+// oauthRegistry serves a connector completing the OAuth exchange for any platform
+type oauthRegistry struct{}
+
+func (oauthRegistry) Get(platform entity.Platform) (service.PlatformConnector, bool) {
+	return &oauthConnector{}, true
+}
+
+type oauthConnector struct {
+	service.PlatformConnector
+	mock mockPlatformConnector
+}
+
+func (c *oauthConnector) ExchangeCode(ctx context.Context, code string) (*entity.OAuthToken, error) {
+	return c.mock.ExchangeCode(ctx, code)
+}
+
+func (c *oauthConnector) GetUserInfo(ctx context.Context, accessToken string) (*entity.PlatformUser, error) {
+	return c.mock.GetUserInfo(ctx, accessToken)
+}
+
+type accountListenerRecorder struct {
+	accountIDs []uuid.UUID
+	userIDs    []uuid.UUID
+}
+
+func (r *accountListenerRecorder) AccountConnected(ctx context.Context, account *entity.ConnectedAccount, userID uuid.UUID) {
+	r.accountIDs = append(r.accountIDs, account.ID)
+	r.userIDs = append(r.userIDs, userID)
+}
+
+func TestHandleOAuthCallback_NotifiesListenersOfNewAccounts(t *testing.T) {
+	ctx := context.Background()
+	stateStore := newMockStateStore()
+	s := NewService(nil, nil, nil, newMockConnectedAccountRepo(), nil, nil, oauthRegistry{}, stateStore, nil, EmailConfig{})
+	recorder := &accountListenerRecorder{}
+	s.AddAccountListener(recorder)
+
+	orgID, userID := uuid.New(), uuid.New()
+	connect := func(state string) *entity.ConnectedAccount {
+		stateStore.Save(ctx, &entity.OAuthState{
+			State:          state,
+			OrganizationID: orgID,
+			UserID:         userID,
+			Platform:       entity.PlatformMeta,
+			ExpiresAt:      time.Now().Add(time.Minute),
+		})
+		account, _, err := s.HandleOAuthCallback(ctx, entity.PlatformMeta, "code", state)
+		if err != nil {
+			t.Fatalf("HandleOAuthCallback() failed: %v", err)
+		}
+		return account
+	}
+
+	account := connect("first")
+	if len(recorder.accountIDs) != 1 || recorder.accountIDs[0] != account.ID || recorder.userIDs[0] != userID {
+		t.Fatalf("listener got accounts %v from users %v, want %s from %s", recorder.accountIDs, recorder.userIDs, account.ID, userID)
+	}
+
+	// Reconnecting only refreshes the tokens of the existing account
+	if reconnected := connect("second"); reconnected.ID != account.ID {
+		t.Errorf("reconnect created account %s, want %s updated", reconnected.ID, account.ID)
+	}
+	if len(recorder.accountIDs) != 1 {
+		t.Errorf("listener notified %d times, want once", len(recorder.accountIDs))
+	}
+}
+
 // ============================================================================
 // Role Permissions Tests
 // ============================================================================
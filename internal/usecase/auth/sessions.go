@@ -0,0 +1,271 @@
+package auth
+
+import (
+	"context"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/ads-aggregator/ads-aggregator/pkg/jwt"
+	"github.com/google/uuid"
+)
+
+// refreshReuseGracePeriod is how long after a rotation the replaced refresh
+// token is refused without revoking its session. Browser tabs sharing a cookie
+// refresh concurrently; only the first exchange succeeds, and the others must
+// not be mistaken for a stolen token.
+const refreshReuseGracePeriod = 10 * time.Second
+
+// ClientInfo describes the client making a request
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+	SessionID uuid.UUID // Session of the request's access token, if any
+}
+
+type clientInfoKey struct{}
+
+// WithClientInfo returns a context carrying the client of a request, recorded
+// on the sessions it starts or refreshes
+func WithClientInfo(ctx context.Context, info ClientInfo) context.Context {
+	return context.WithValue(ctx, clientInfoKey{}, info)
+}
+
+// clientInfoFrom returns the client info of a context, empty if it has none
+func clientInfoFrom(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	return info
+}
+
+// Session is a login on one device as shown to its user
+type Session struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"user_agent,omitempty"`
+	IPAddress  string    `json:"ip_address,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // The session of the request listing it
+}
+
+// SetSessionRepository enables server-side sessions. Refresh tokens are then
+// rotated on every use and can be revoked; without it they are stateless.
+func (s *Service) SetSessionRepository(sessionRepo repository.UserSessionRepository) {
+	s.sessionRepo = sessionRepo
+}
+
+// issueTokens generates a token pair for a new login, starting its session
+func (s *Service) issueTokens(ctx context.Context, user *entity.User, org *entity.Organization, role entity.UserRole) (*jwt.TokenPair, error) {
+	if s.sessionRepo == nil {
+		return s.jwtManager.GenerateTokenPair(user.ID.String(), org.ID.String(), user.Email, string(role), s.getPermissionsForRole(role))
+	}
+
+	base := entity.NewBaseEntity()
+	tokens, err := s.jwtManager.GenerateSessionTokenPair(base.ID.String(), user.ID.String(), org.ID.String(), user.Email, string(role), s.getPermissionsForRole(role))
+	if err != nil {
+		return nil, err
+	}
+
+	client := clientInfoFrom(ctx)
+	session := &entity.UserSession{
+		BaseEntity:     base,
+		UserID:         user.ID,
+		OrganizationID: org.ID,
+		CurrentTokenID: tokens.RefreshTokenID,
+		UserAgent:      truncate(client.UserAgent, 500),
+		IPAddress:      client.IPAddress,
+		LastUsedAt:     base.CreatedAt,
+		ExpiresAt:      tokens.RefreshTokenExpiresAt,
+	}
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// rotateSession exchanges the latest refresh token of a session for a new pair
+func (s *Service) rotateSession(ctx context.Context, claims *jwt.Claims) (*jwt.TokenPair, error) {
+	now := time.Now()
+
+	session, err := s.sessionRepo.GetByTokenID(ctx, claims.ID)
+	if err != nil || session == nil {
+		return nil, s.rejectStaleRefreshToken(ctx, claims, now)
+	}
+	if !session.IsActive(now) {
+		return nil, errors.ErrUnauthorized("Session has expired or been revoked")
+	}
+
+	user, err := s.userRepo.GetByID(ctx, session.UserID)
+	if err != nil || user == nil {
+		return nil, errors.ErrUnauthorized("Invalid refresh token")
+	}
+	if !user.IsActive {
+		_ = s.sessionRepo.Revoke(ctx, session.ID, entity.SessionRevokedUserDisabled, now)
+		return nil, errors.ErrUnauthorized("Account is disabled")
+	}
+
+	role := entity.UserRole(claims.Role)
+	tokens, err := s.jwtManager.GenerateSessionTokenPair(session.ID.String(), claims.UserID, claims.OrganizationID, claims.Email, claims.Role, s.getPermissionsForRole(role))
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to generate tokens", 500)
+	}
+
+	client := clientInfoFrom(ctx)
+	session.PreviousTokenID = session.CurrentTokenID
+	session.CurrentTokenID = tokens.RefreshTokenID
+	session.LastUsedAt = now
+	session.RotatedAt = &now
+	session.ExpiresAt = tokens.RefreshTokenExpiresAt
+	if client.UserAgent != "" {
+		session.UserAgent = truncate(client.UserAgent, 500)
+	}
+	if client.IPAddress != "" {
+		session.IPAddress = client.IPAddress
+	}
+
+	rotated, err := s.sessionRepo.Rotate(ctx, session, claims.ID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to refresh session", 500)
+	}
+	if !rotated {
+		// Another exchange of the same token won the race
+		return nil, errors.ErrUnauthorized("Refresh token has already been used")
+	}
+	return tokens, nil
+}
+
+// rejectStaleRefreshToken handles a valid refresh token that is not the latest
+// of any session. Unless it was replaced moments ago, it is a copy being
+// replayed, and its session is revoked so neither copy works any more.
+func (s *Service) rejectStaleRefreshToken(ctx context.Context, claims *jwt.Claims, now time.Time) error {
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		// Issued before server-side sessions
+		return errors.ErrUnauthorized("Invalid refresh token")
+	}
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil || session == nil || session.UserID.String() != claims.UserID {
+		return errors.ErrUnauthorized("Invalid refresh token")
+	}
+	if session.RevokedAt != nil {
+		return errors.ErrUnauthorized("Session has been revoked")
+	}
+
+	if claims.ID == session.PreviousTokenID && session.RotatedAt != nil && now.Sub(*session.RotatedAt) < refreshReuseGracePeriod {
+		return errors.ErrUnauthorized("Refresh token has already been used")
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, session.ID, entity.SessionRevokedReuse, now); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to revoke session", 500)
+	}
+	return errors.ErrUnauthorized("Refresh token reuse detected, please log in again")
+}
+
+// Logout ends the session of a refresh token. Invalid or expired tokens have
+// nothing left to end and are ignored.
+func (s *Service) Logout(ctx context.Context, refreshToken string) error {
+	if s.sessionRepo == nil || refreshToken == "" {
+		return nil
+	}
+	claims, err := s.jwtManager.ValidateRefreshToken(refreshToken)
+	if err != nil {
+		return nil
+	}
+	sessionID, err := uuid.Parse(claims.SessionID)
+	if err != nil {
+		return nil
+	}
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil || session == nil || session.UserID.String() != claims.UserID {
+		return nil
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, session.ID, entity.SessionRevokedLogout, time.Now()); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to end session", 500)
+	}
+	return nil
+}
+
+// ListSessions lists a user's active sessions, marking the current one
+func (s *Service) ListSessions(ctx context.Context, userID, currentSessionID uuid.UUID) ([]Session, error) {
+	if s.sessionRepo == nil {
+		return nil, errors.ErrBadRequest("Sessions are not configured")
+	}
+
+	records, err := s.sessionRepo.ListActiveByUser(ctx, userID, time.Now())
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load sessions", 500)
+	}
+
+	sessions := make([]Session, 0, len(records))
+	for _, r := range records {
+		sessions = append(sessions, Session{
+			ID:         r.ID,
+			UserAgent:  r.UserAgent,
+			IPAddress:  r.IPAddress,
+			CreatedAt:  r.CreatedAt,
+			LastUsedAt: r.LastUsedAt,
+			ExpiresAt:  r.ExpiresAt,
+			Current:    r.ID == currentSessionID,
+		})
+	}
+	return sessions, nil
+}
+
+// RevokeSession ends one of a user's sessions. Its refresh token stops working
+// right away; access tokens already issued expire on their own.
+func (s *Service) RevokeSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if s.sessionRepo == nil {
+		return errors.ErrBadRequest("Sessions are not configured")
+	}
+
+	session, err := s.sessionRepo.GetByID(ctx, sessionID)
+	if err != nil || session == nil || session.UserID != userID {
+		return errors.ErrNotFound("Session")
+	}
+	if session.RevokedAt != nil {
+		return nil
+	}
+
+	if err := s.sessionRepo.Revoke(ctx, sessionID, entity.SessionRevokedByUser, time.Now()); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to revoke session", 500)
+	}
+	return nil
+}
+
+// RevokeOtherSessions ends every session of a user except the current one and
+// returns how many were ended
+func (s *Service) RevokeOtherSessions(ctx context.Context, userID, currentSessionID uuid.UUID) (int64, error) {
+	if s.sessionRepo == nil {
+		return 0, errors.ErrBadRequest("Sessions are not configured")
+	}
+
+	revoked, err := s.sessionRepo.RevokeByUser(ctx, userID, currentSessionID, entity.SessionRevokedByUser, time.Now())
+	if err != nil {
+		return 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to revoke sessions", 500)
+	}
+	return revoked, nil
+}
+
+// SessionsConfigured reports whether server-side sessions are enabled
+func (s *Service) SessionsConfigured() bool {
+	return s.sessionRepo != nil
+}
+
+// endSessionsAfterPasswordChange revokes a user's sessions except keep, so a
+// changed password also locks out whoever knew the old one
+func (s *Service) endSessionsAfterPasswordChange(ctx context.Context, userID, keep uuid.UUID) error {
+	if s.sessionRepo == nil {
+		return nil
+	}
+	_, err := s.sessionRepo.RevokeByUser(ctx, userID, keep, entity.SessionRevokedPasswordChange, time.Now())
+	return err
+}
+
+func truncate(s string, max int) string {
+	if len(s) > max {
+		return s[:max]
+	}
+	return s
+}
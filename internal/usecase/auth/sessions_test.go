@@ -0,0 +1,236 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	appErrors "github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+)
+
+type mockUserSessionRepository struct {
+	sessions map[uuid.UUID]*entity.UserSession
+}
+
+func newMockUserSessionRepo() *mockUserSessionRepository {
+	return &mockUserSessionRepository{sessions: make(map[uuid.UUID]*entity.UserSession)}
+}
+
+func (m *mockUserSessionRepository) Create(ctx context.Context, session *entity.UserSession) error {
+	stored := *session
+	m.sessions[session.ID] = &stored
+	return nil
+}
+
+func (m *mockUserSessionRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.UserSession, error) {
+	if session, ok := m.sessions[id]; ok {
+		copied := *session
+		return &copied, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockUserSessionRepository) GetByTokenID(ctx context.Context, tokenID string) (*entity.UserSession, error) {
+	for _, session := range m.sessions {
+		if session.CurrentTokenID == tokenID {
+			copied := *session
+			return &copied, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (m *mockUserSessionRepository) Rotate(ctx context.Context, session *entity.UserSession, previousTokenID string) (bool, error) {
+	stored, ok := m.sessions[session.ID]
+	if !ok || stored.CurrentTokenID != previousTokenID || stored.RevokedAt != nil {
+		return false, nil
+	}
+	updated := *session
+	m.sessions[session.ID] = &updated
+	return true, nil
+}
+
+func (m *mockUserSessionRepository) Revoke(ctx context.Context, id uuid.UUID, reason string, revokedAt time.Time) error {
+	if session, ok := m.sessions[id]; ok && session.RevokedAt == nil {
+		session.RevokedAt = &revokedAt
+		session.RevokedReason = reason
+	}
+	return nil
+}
+
+func (m *mockUserSessionRepository) RevokeByUser(ctx context.Context, userID, exceptID uuid.UUID, reason string, revokedAt time.Time) (int64, error) {
+	var revoked int64
+	for _, session := range m.sessions {
+		if session.UserID == userID && session.ID != exceptID && session.IsActive(revokedAt) {
+			session.RevokedAt = &revokedAt
+			session.RevokedReason = reason
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+func (m *mockUserSessionRepository) ListActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]entity.UserSession, error) {
+	var sessions []entity.UserSession
+	for _, session := range m.sessions {
+		if session.UserID == userID && session.IsActive(now) {
+			sessions = append(sessions, *session)
+		}
+	}
+	return sessions, nil
+}
+
+func newSessionTestEnv(t *testing.T) (*mfaTestEnv, *mockUserSessionRepository) {
+	t.Helper()
+	env := newMFATestEnv(t, entity.RoleOwner, false)
+	sessions := newMockUserSessionRepo()
+	env.service.SetSessionRepository(sessions)
+	return env, sessions
+}
+
+func TestSessions_LoginStartsSession(t *testing.T) {
+	env, sessions := newSessionTestEnv(t)
+	ctx := WithClientInfo(context.Background(), ClientInfo{UserAgent: "Firefox", IPAddress: "10.0.0.1"})
+
+	result, err := env.service.Login(ctx, &LoginRequest{Email: env.user.Email, Password: mfaTestPassword})
+	if err != nil {
+		t.Fatalf("Login() error = %v", err)
+	}
+
+	claims, err := env.service.jwtManager.ValidateAccessToken(result.Tokens.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	session, ok := sessions.sessions[uuid.MustParse(claims.SessionID)]
+	if !ok {
+		t.Fatalf("no session %s recorded", claims.SessionID)
+	}
+	if session.CurrentTokenID != result.Tokens.RefreshTokenID || session.UserAgent != "Firefox" || session.IPAddress != "10.0.0.1" {
+		t.Errorf("session = %+v", session)
+	}
+}
+
+func TestSessions_RotationAndReuseDetection(t *testing.T) {
+	env, sessions := newSessionTestEnv(t)
+	ctx := context.Background()
+
+	first := env.login(t).Tokens
+	second, err := env.service.RefreshToken(ctx, first.RefreshToken)
+	if err != nil {
+		t.Fatalf("RefreshToken() error = %v", err)
+	}
+	if second.SessionID != first.SessionID || second.RefreshTokenID == first.RefreshTokenID {
+		t.Fatal("RefreshToken() did not rotate within the session")
+	}
+	claims, _ := env.service.jwtManager.ValidateAccessToken(second.AccessToken)
+	if len(claims.Permissions) == 0 {
+		t.Error("refreshed access token has no permissions")
+	}
+
+	// A concurrent exchange of the replaced token is refused without ending the session
+	if _, err := env.service.RefreshToken(ctx, first.RefreshToken); appErrors.GetHTTPStatus(err) != 401 {
+		t.Errorf("RefreshToken() within the grace period error = %v, want unauthorized", err)
+	}
+	sessionID := uuid.MustParse(second.SessionID)
+	if sessions.sessions[sessionID].RevokedAt != nil {
+		t.Fatal("session revoked by an exchange within the grace period")
+	}
+
+	// Later, the replaced token is a copy and revokes the whole family
+	rotatedAt := time.Now().Add(-time.Minute)
+	sessions.sessions[sessionID].RotatedAt = &rotatedAt
+	if _, err := env.service.RefreshToken(ctx, first.RefreshToken); appErrors.GetHTTPStatus(err) != 401 {
+		t.Errorf("RefreshToken() with a reused token error = %v, want unauthorized", err)
+	}
+	if session := sessions.sessions[sessionID]; session.RevokedAt == nil || session.RevokedReason != entity.SessionRevokedReuse {
+		t.Fatalf("session = %+v, want revoked for reuse", session)
+	}
+	if _, err := env.service.RefreshToken(ctx, second.RefreshToken); err == nil {
+		t.Error("latest token of a revoked session still refreshes")
+	}
+}
+
+func TestSessions_LegacyRefreshToken(t *testing.T) {
+	env, _ := newSessionTestEnv(t)
+
+	legacy, err := env.service.jwtManager.GenerateTokenPair(env.user.ID.String(), env.org.ID.String(), env.user.Email, "owner", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := env.service.RefreshToken(context.Background(), legacy.RefreshToken); appErrors.GetHTTPStatus(err) != 401 {
+		t.Errorf("RefreshToken() with a token without session error = %v, want unauthorized", err)
+	}
+}
+
+func TestSessions_LogoutAndRevoke(t *testing.T) {
+	env, sessions := newSessionTestEnv(t)
+	ctx := context.Background()
+
+	laptop := env.login(t).Tokens
+	phone := env.login(t).Tokens
+	tablet := env.login(t).Tokens
+	laptopID := uuid.MustParse(laptop.SessionID)
+
+	if err := env.service.Logout(ctx, tablet.RefreshToken); err != nil {
+		t.Fatalf("Logout() error = %v", err)
+	}
+	if _, err := env.service.RefreshToken(ctx, tablet.RefreshToken); err == nil {
+		t.Error("refresh token still works after logout")
+	}
+	if err := env.service.Logout(ctx, "not-a-token"); err != nil {
+		t.Errorf("Logout() with an invalid token error = %v, want nil", err)
+	}
+
+	list, err := env.service.ListSessions(ctx, env.user.ID, laptopID)
+	if err != nil {
+		t.Fatalf("ListSessions() error = %v", err)
+	}
+	if len(list) != 2 {
+		t.Fatalf("got %d sessions, want 2", len(list))
+	}
+	for _, s := range list {
+		if s.Current != (s.ID == laptopID) {
+			t.Errorf("session %s current = %v", s.ID, s.Current)
+		}
+	}
+
+	if err := env.service.RevokeSession(ctx, uuid.New(), uuid.MustParse(phone.SessionID)); appErrors.GetHTTPStatus(err) != 404 {
+		t.Errorf("RevokeSession() of another user's session error = %v, want not found", err)
+	}
+	if err := env.service.RevokeSession(ctx, env.user.ID, uuid.MustParse(phone.SessionID)); err != nil {
+		t.Fatalf("RevokeSession() error = %v", err)
+	}
+	if _, err := env.service.RefreshToken(ctx, phone.RefreshToken); err == nil {
+		t.Error("refresh token still works after its session was revoked")
+	}
+	if _, err := env.service.RefreshToken(ctx, laptop.RefreshToken); err != nil {
+		t.Errorf("RefreshToken() of an unrelated session error = %v", err)
+	}
+
+	if len(sessions.sessions) != 3 {
+		t.Errorf("got %d session records, want 3", len(sessions.sessions))
+	}
+}
+
+func TestSessions_ChangePasswordEndsOtherSessions(t *testing.T) {
+	env, sessions := newSessionTestEnv(t)
+
+	current := env.login(t).Tokens
+	other := env.login(t).Tokens
+	currentID := uuid.MustParse(current.SessionID)
+
+	ctx := WithClientInfo(context.Background(), ClientInfo{SessionID: currentID})
+	if err := env.service.ChangePassword(ctx, env.user.ID, mfaTestPassword, "a new password"); err != nil {
+		t.Fatalf("ChangePassword() error = %v", err)
+	}
+
+	if sessions.sessions[currentID].RevokedAt != nil {
+		t.Error("current session ended by its own password change")
+	}
+	if session := sessions.sessions[uuid.MustParse(other.SessionID)]; session.RevokedReason != entity.SessionRevokedPasswordChange {
+		t.Errorf("other session = %+v, want revoked by the password change", session)
+	}
+}
@@ -18,6 +18,7 @@ type TokenManager struct {
 	tokenRefreshRepo  repository.TokenRefreshLogRepository
 	connectorRegistry ConnectorRegistry
 	encryptor         *crypto.TokenEncryptor
+	expiryListeners   []TokenExpiryListener
 	logger            zerolog.Logger
 }
 
@@ -26,6 +27,12 @@ type ConnectorRegistry interface {
 	Get(platform entity.Platform) (service.PlatformConnector, bool)
 }
 
+// TokenExpiryListener is notified when a connected account's token expired and
+// could not be refreshed, so the account must be reconnected
+type TokenExpiryListener interface {
+	TokenExpired(ctx context.Context, account *entity.ConnectedAccount)
+}
+
 // NewTokenManager creates a new token manager
 func NewTokenManager(
 	connectedAccRepo repository.ConnectedAccountRepository,
@@ -52,6 +59,11 @@ func NewTokenManager(
 	}, nil
 }
 
+// AddExpiryListener adds a listener notified when an account's token expires
+func (m *TokenManager) AddExpiryListener(listener TokenExpiryListener) {
+	m.expiryListeners = append(m.expiryListeners, listener)
+}
+
 // RefreshTokenIfNeeded refreshes the token if it's expiring within 30 minutes
 func (m *TokenManager) RefreshTokenIfNeeded(ctx context.Context, accountID uuid.UUID) error {
 	account, err := m.connectedAccRepo.GetByID(ctx, accountID)
@@ -124,10 +136,6 @@ func (m *TokenManager) ValidateToken(ctx context.Context, accountID uuid.UUID) (
 	if account.IsTokenExpired() {
 		// Try to refresh
 		if err := m.refreshToken(ctx, account); err != nil {
-			// Update status to expired
-			account.Status = entity.AccountStatusExpired
-			_ = m.connectedAccRepo.Update(ctx, account)
-
 			return nil, errors.New(
 				errors.ErrCodeOAuthFailed,
 				"Token has expired and could not be refreshed. Please reconnect your account.",
@@ -199,6 +207,9 @@ func (m *TokenManager) refreshToken(ctx context.Context, account *entity.Connect
 			Str("platform", string(account.Platform)).
 			Msg("Token refresh failed")
 
+		if account.IsTokenExpired() {
+			m.expire(ctx, account)
+		}
 		return err
 	}
 
@@ -241,6 +252,21 @@ func (m *TokenManager) refreshToken(ctx context.Context, account *entity.Connect
 	return nil
 }
 
+// expire marks an account whose token expired and could not be refreshed, so
+// it is no longer synced or refreshed until it is reconnected
+func (m *TokenManager) expire(ctx context.Context, account *entity.ConnectedAccount) {
+	account.Status = entity.AccountStatusExpired
+	if err := m.connectedAccRepo.Update(ctx, account); err != nil {
+		m.logger.Error().
+			Err(err).
+			Str("account_id", account.ID.String()).
+			Msg("Failed to mark account expired")
+	}
+	for _, listener := range m.expiryListeners {
+		listener.TokenExpired(ctx, account)
+	}
+}
+
 // EncryptToken encrypts a token for storage
 func (m *TokenManager) EncryptToken(token string) (string, error) {
 	if m.encryptor == nil || token == "" {
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/service"
 	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
@@ -184,6 +185,31 @@ func (m *mockConnectorRegistry) Get(platform entity.Platform) (interface{}, bool
 	return nil, false
 }
 
+// failingRefreshConnector is a connector whose token refresh always fails
+type failingRefreshConnector struct {
+	service.PlatformConnector
+	err error
+}
+
+func (c *failingRefreshConnector) RefreshToken(ctx context.Context, refreshToken string) (*entity.OAuthToken, error) {
+	return nil, c.err
+}
+
+type stubConnectorRegistry map[entity.Platform]service.PlatformConnector
+
+func (r stubConnectorRegistry) Get(platform entity.Platform) (service.PlatformConnector, bool) {
+	connector, ok := r[platform]
+	return connector, ok
+}
+
+type recordingExpiryListener struct {
+	expired []uuid.UUID
+}
+
+func (l *recordingExpiryListener) TokenExpired(ctx context.Context, account *entity.ConnectedAccount) {
+	l.expired = append(l.expired, account.ID)
+}
+
 // ============================================================================
 // Helper Functions
 // ============================================================================
@@ -427,6 +453,54 @@ func TestTokenManager_RefreshTokenIfNeeded_NotFound(t *testing.T) {
 	}
 }
 
+func TestTokenManager_RefreshTokenIfNeeded_ExpiredTokenNotifiesListeners(t *testing.T) {
+	accountRepo := newMockConnectedAccountRepo()
+	logRepo := newMockTokenRefreshLogRepo()
+	registry := stubConnectorRegistry{
+		entity.PlatformMeta: &failingRefreshConnector{err: errors.ErrBadRequest("invalid refresh token")},
+	}
+
+	tm, err := NewTokenManager(accountRepo, logRepo, registry, "", zerolog.Nop())
+	if err != nil {
+		t.Fatalf("failed to create token manager: %v", err)
+	}
+	listener := &recordingExpiryListener{}
+	tm.AddExpiryListener(listener)
+
+	// Token expiring soon: the failed refresh is retried on the next run
+	expiringID := uuid.New()
+	expiresSoon := time.Now().Add(20 * time.Minute)
+	accountRepo.addAccount(createTestAccount(expiringID, entity.AccountStatusActive, &expiresSoon))
+
+	if err := tm.RefreshTokenIfNeeded(context.Background(), expiringID); err == nil {
+		t.Fatal("expected refresh error")
+	}
+	if len(listener.expired) != 0 {
+		t.Fatalf("listener notified for a token that has not expired yet: %v", listener.expired)
+	}
+	if status := accountRepo.accounts[expiringID].Status; status != entity.AccountStatusActive {
+		t.Errorf("status = %s, want %s", status, entity.AccountStatusActive)
+	}
+
+	// Token already expired: the account must be reconnected
+	expiredID := uuid.New()
+	expiredAt := time.Now().Add(-time.Hour)
+	accountRepo.addAccount(createTestAccount(expiredID, entity.AccountStatusActive, &expiredAt))
+
+	if err := tm.RefreshTokenIfNeeded(context.Background(), expiredID); err == nil {
+		t.Fatal("expected refresh error")
+	}
+	if len(listener.expired) != 1 || listener.expired[0] != expiredID {
+		t.Fatalf("expired = %v, want [%s]", listener.expired, expiredID)
+	}
+	if status := accountRepo.accounts[expiredID].Status; status != entity.AccountStatusExpired {
+		t.Errorf("status = %s, want %s", status, entity.AccountStatusExpired)
+	}
+	if len(logRepo.logs) != 2 || logRepo.logs[1].RefreshStatus != "failed" {
+		t.Errorf("expected two failed refresh logs, got %d", len(logRepo.logs))
+	}
+}
+
 func TestTokenManager_GetDecryptedAccessToken(t *testing.T) {
 	accountRepo := newMockConnectedAccountRepo()
 	logRepo := newMockTokenRefreshLogRepo()
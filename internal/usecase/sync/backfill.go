@@ -0,0 +1,214 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/pkg/ratelimit"
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Historical Backfill - Metrics for long date ranges, in checkpointed chunks
+// ============================================================================
+
+// metricsBackfilledFromKey is the sync state metadata key holding the first
+// day (YYYY-MM-DD) of the account's backfilled metrics
+const metricsBackfilledFromKey = "metrics_backfilled_from"
+
+// BackfillRequest represents a request for a historical metrics backfill
+type BackfillRequest struct {
+	ConnectedAccountID uuid.UUID  `json:"connected_account_id"`
+	UserID             *uuid.UUID `json:"user_id,omitempty"` // Nil when the system starts the backfill
+	Months             int        `json:"months,omitempty"`  // Defaults to all the history the platform keeps
+}
+
+// TriggerBackfill creates a job syncing an account's structure and then its
+// metrics history, e.g. right after the account is connected. The job has the
+// lowest priority and a rate budget of its own, so regular syncs keep running.
+func (s *Scheduler) TriggerBackfill(ctx context.Context, req BackfillRequest) (*entity.SyncJob, error) {
+	account, err := s.connAccountRepo.GetByID(ctx, req.ConnectedAccountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get connected account: %w", err)
+	}
+
+	config, ok := s.GetScheduleConfig(account.Platform)
+	if !ok {
+		return nil, fmt.Errorf("no sync configuration for platform: %s", account.Platform)
+	}
+
+	months := config.BackfillMaxMonths
+	if req.Months > 0 && (months == 0 || req.Months < months) {
+		months = req.Months
+	}
+	if months <= 0 {
+		return nil, fmt.Errorf("platform %s does not support backfills", account.Platform)
+	}
+
+	now := time.Now()
+	start := now.AddDate(0, -months, 0)
+
+	job := &entity.SyncJob{
+		BaseEntity:         entity.NewBaseEntity(),
+		OrganizationID:     account.OrganizationID,
+		ConnectedAccountID: account.ID,
+		Platform:           account.Platform,
+		SyncType:           entity.SyncTypeInitial,
+		SyncScope:          entity.SyncScopeAccount,
+		Status:             entity.SyncStatusPending,
+		Priority:           entity.SyncJobPriorityBackfill,
+		DateRangeStart:     &start,
+		DateRangeEnd:       &now,
+		ScheduledAt:        now,
+		MaxRetries:         config.MaxRetries,
+		TriggeredBy:        "scheduler",
+		TriggeredByUser:    req.UserID,
+	}
+	if req.UserID != nil {
+		job.TriggeredBy = fmt.Sprintf("user:%s", *req.UserID)
+	}
+
+	if err := s.syncJobRepo.Create(ctx, job); err != nil {
+		return nil, fmt.Errorf("failed to create backfill job: %w", err)
+	}
+
+	log.Printf("[Scheduler] Created backfill job %s for account %s (%d months)", job.ID, account.ID, months)
+	s.notifyWorkers()
+
+	return job, nil
+}
+
+// AccountConnected queues the backfill of a newly connected account on
+// platforms that keep metrics history. It implements the auth package's
+// AccountConnectedListener.
+func (s *Scheduler) AccountConnected(ctx context.Context, account *entity.ConnectedAccount, userID uuid.UUID) {
+	if config, ok := s.GetScheduleConfig(account.Platform); !ok || config.BackfillMaxMonths <= 0 {
+		return
+	}
+
+	if _, err := s.TriggerBackfill(ctx, BackfillRequest{ConnectedAccountID: account.ID, UserID: &userID}); err != nil {
+		log.Printf("[Scheduler] Error creating backfill job for account %s: %v", account.ID, err)
+	}
+}
+
+// backfillChunks splits a date range into whole-day chunks of at most days,
+// newest first. A range that fits in one chunk is returned as is.
+func backfillChunks(dateRange entity.DateRange, days int) []entity.DateRange {
+	start := startOfDay(dateRange.StartDate)
+	end := startOfDay(dateRange.EndDate)
+	if days <= 0 || end.Sub(start) < time.Duration(days)*24*time.Hour {
+		return []entity.DateRange{dateRange}
+	}
+
+	var chunks []entity.DateRange
+	for chunkEnd := end; !chunkEnd.Before(start); {
+		chunkStart := chunkEnd.AddDate(0, 0, -(days - 1))
+		if chunkStart.Before(start) {
+			chunkStart = start
+		}
+		chunks = append(chunks, entity.DateRange{StartDate: chunkStart, EndDate: chunkEnd})
+		chunkEnd = chunkStart.AddDate(0, 0, -1)
+	}
+	return chunks
+}
+
+// chunksAfterCheckpoint drops the chunks an earlier run of the job already synced
+func chunksAfterCheckpoint(job *entity.SyncJob, chunks []entity.DateRange) []entity.DateRange {
+	value, _ := job.Checkpoint[checkpointChunkKey].(string)
+	syncedFrom, err := time.ParseInLocation("2006-01-02", value, chunks[0].StartDate.Location())
+	if err != nil {
+		return chunks
+	}
+	for i, chunk := range chunks {
+		if chunk.StartDate.Before(syncedFrom) {
+			return chunks[i:]
+		}
+	}
+	return nil
+}
+
+// backfillMetrics syncs the metrics of every campaign chunk by chunk, newest
+// first. Each finished chunk moves the job's checkpoint, so a paused, failed
+// or interrupted backfill resumes from the last good chunk. Unlike a regular
+// sync, a failed request fails the job rather than leaving a gap in the history.
+func (r *JobRunner) backfillMetrics(ctx context.Context, run *jobRun, campaigns []entity.Campaign, chunks []entity.DateRange) error {
+	job := run.job
+	total := len(chunks)
+	remaining := chunksAfterCheckpoint(job, chunks)
+	done := total - len(remaining)
+
+	// Backfills have a rate budget of their own on top of the account's
+	var backfillLimiter *ratelimit.Limiter
+	if perMinute := r.configs[job.Platform].BackfillRequestsPerMinute; perMinute > 0 {
+		backfillLimiter = r.engine.backfillLimiter(run.account, perMinute)
+	}
+
+	synced := make(map[uuid.UUID]bool)
+	for i, chunk := range remaining {
+		from, to := chunk.StartDate.Format("2006-01-02"), chunk.EndDate.Format("2006-01-02")
+		r.updateProgress(ctx, job, 10+80*(done+i)/total, fmt.Sprintf("Backfilling metrics for %s to %s (%d/%d)", from, to, done+i+1, total))
+
+		for _, campaign := range campaignsAfterCheckpoint(job, campaigns) {
+			if backfillLimiter != nil {
+				if err := backfillLimiter.Wait(ctx); err != nil {
+					return fmt.Errorf("rate limit wait failed: %w", err)
+				}
+			}
+
+			count, err := r.engine.syncCampaignMetrics(ctx, run.account, campaign, run.connector, run.limiter, chunk)
+			if ctx.Err() != nil {
+				// Interrupted, so the campaign is synced again on resume
+				return ctx.Err()
+			}
+			if err != nil {
+				return fmt.Errorf("failed to get insights for campaign %s from %s to %s: %w", campaign.ID, from, to, err)
+			}
+			run.result.MetricsSynced += count
+			if count > 0 {
+				synced[campaign.ID] = true
+			}
+			r.saveCheckpoint(ctx, run, checkpointCampaignKey, campaign.ID.String())
+		}
+
+		// The next chunk starts again from the first campaign
+		delete(job.Checkpoint, checkpointCampaignKey)
+		r.saveCheckpoint(ctx, run, checkpointChunkKey, from)
+	}
+
+	campaignIDs := make([]uuid.UUID, 0, len(synced))
+	for id := range synced {
+		campaignIDs = append(campaignIDs, id)
+	}
+	r.engine.notifyMetricsSynced(ctx, run.account.OrganizationID, campaignIDs)
+
+	r.recordBackfilledFrom(ctx, job.ConnectedAccountID, chunks[total-1].StartDate)
+	r.updateProgress(ctx, job, 100, fmt.Sprintf("Backfill complete: %d days of metrics", int(chunks[0].EndDate.Sub(chunks[total-1].StartDate).Hours()/24)+1))
+
+	return nil
+}
+
+// recordBackfilledFrom notes on the account's sync state how far back its metrics go
+func (r *JobRunner) recordBackfilledFrom(ctx context.Context, connectedAccountID uuid.UUID, from time.Time) {
+	state, err := r.syncStateRepo.GetByConnectedAccount(ctx, connectedAccountID)
+	if err != nil || state == nil {
+		return
+	}
+
+	if value, ok := state.Metadata[metricsBackfilledFromKey].(string); ok && value <= from.Format("2006-01-02") {
+		return
+	}
+	if state.Metadata == nil {
+		state.Metadata = entity.JSONMap{}
+	}
+	state.Metadata[metricsBackfilledFromKey] = from.Format("2006-01-02")
+	if err := r.syncStateRepo.Update(ctx, state); err != nil {
+		log.Printf("[JobRunner] Error saving backfill range: %v", err)
+	}
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
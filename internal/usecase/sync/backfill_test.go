@@ -0,0 +1,193 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/service"
+	"github.com/ads-aggregator/ads-aggregator/pkg/retry"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func day(value string) time.Time {
+	t, _ := time.Parse("2006-01-02", value)
+	return t
+}
+
+func TestBackfillChunks(t *testing.T) {
+	short := entity.DateRange{StartDate: day("2024-03-01"), EndDate: day("2024-03-07")}
+	if chunks := backfillChunks(short, 30); len(chunks) != 1 || chunks[0] != short {
+		t.Errorf("short range split into %v", chunks)
+	}
+
+	chunks := backfillChunks(entity.DateRange{StartDate: day("2024-01-01"), EndDate: day("2024-03-31")}, 30)
+	want := []entity.DateRange{
+		{StartDate: day("2024-03-02"), EndDate: day("2024-03-31")},
+		{StartDate: day("2024-02-01"), EndDate: day("2024-03-01")},
+		{StartDate: day("2024-01-02"), EndDate: day("2024-01-31")},
+		{StartDate: day("2024-01-01"), EndDate: day("2024-01-01")},
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("got %d chunks, want %d", len(chunks), len(want))
+	}
+	for i := range want {
+		if !chunks[i].StartDate.Equal(want[i].StartDate) || !chunks[i].EndDate.Equal(want[i].EndDate) {
+			t.Errorf("chunk %d = %v, want %v", i, chunks[i], want[i])
+		}
+	}
+}
+
+type backfillConnector struct {
+	service.PlatformConnector
+
+	failOn *entity.DateRange // Fails the next request for this chunk
+	calls  []entity.DateRange
+}
+
+func (c *backfillConnector) GetCampaignInsights(ctx context.Context, accessToken string, campaignID string, dateRange entity.DateRange) ([]entity.CampaignMetricsDaily, error) {
+	c.calls = append(c.calls, dateRange)
+	if c.failOn != nil && dateRange.StartDate.Equal(c.failOn.StartDate) && campaignID == "second" {
+		c.failOn = nil
+		return nil, errors.New("service unavailable")
+	}
+	return []entity.CampaignMetricsDaily{{}}, nil
+}
+
+type backfillCampaignRepo struct {
+	repository.CampaignRepository
+	campaigns []entity.Campaign
+}
+
+func (r *backfillCampaignRepo) ListByAdAccount(ctx context.Context, adAccountID uuid.UUID) ([]entity.Campaign, error) {
+	return append([]entity.Campaign(nil), r.campaigns...), nil
+}
+
+type backfillMetricsRepo struct {
+	repository.MetricsRepository
+}
+
+func (r *backfillMetricsRepo) BulkUpsertCampaignMetrics(ctx context.Context, metrics []entity.CampaignMetricsDaily) error {
+	return nil
+}
+
+type backfillAccountRepo struct {
+	repository.ConnectedAccountRepository
+}
+
+func (r *backfillAccountRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.ConnectedAccount, error) {
+	return &entity.ConnectedAccount{BaseEntity: entity.BaseEntity{ID: id}, Platform: entity.PlatformMeta, Status: entity.AccountStatusActive}, nil
+}
+
+type backfillStateRepo struct {
+	repository.SyncStateRepository
+}
+
+func (r *backfillStateRepo) GetByConnectedAccount(ctx context.Context, connectedAccountID uuid.UUID) (*entity.SyncState, error) {
+	return nil, errors.New("record not found")
+}
+
+type progressRecorder struct {
+	percents []int
+}
+
+func (p *progressRecorder) SyncJobProgress(ctx context.Context, job *entity.SyncJob, percent int, message string) {
+	p.percents = append(p.percents, percent)
+}
+
+func TestJobRunner_BackfillResumesFromLastGoodChunk(t *testing.T) {
+	jobs := &queueJobRepo{}
+	job := jobs.add(entity.SyncJobPriorityBackfill, entity.SyncStatusRunning)
+	job.SyncScope = entity.SyncScopeMetrics
+	start, end := day("2024-01-02"), day("2024-03-31") // Three chunks of 30 days
+	job.DateRangeStart, job.DateRangeEnd = &start, &end
+
+	chunks := backfillChunks(entity.DateRange{StartDate: start, EndDate: end}, 30)
+	connector := &backfillConnector{failOn: &chunks[1]}
+	campaigns := &backfillCampaignRepo{campaigns: []entity.Campaign{
+		{BaseEntity: entity.BaseEntity{ID: uuid.MustParse("10000000-0000-0000-0000-000000000000")}, PlatformCampaignID: "first"},
+		{BaseEntity: entity.BaseEntity{ID: uuid.MustParse("20000000-0000-0000-0000-000000000000")}, PlatformCampaignID: "second"},
+	}}
+
+	engine := NewService(&ServiceConfig{RetryConfig: &retry.Config{MaxRetries: 0}}, &backfillAccountRepo{}, &engineAdAccountRepo{},
+		campaigns, nil, nil, &backfillMetricsRepo{}, engineRegistry{entity.PlatformMeta: connector}, zerolog.Nop())
+	runner := NewJobRunner(engine, &backfillStateRepo{}, jobs, nil, nil, &JobRunnerConfig{
+		MaxConcurrentJobs: 1,
+		Configs: map[entity.Platform]entity.SyncScheduleConfig{
+			entity.PlatformMeta: {BackfillChunkDays: 30, BackfillRequestsPerMinute: 60000},
+		},
+	})
+	progress := &progressRecorder{}
+	runner.AddProgressListener(progress)
+
+	// The first run gets through the newest chunk and one campaign of the next
+	run := jobs.get(job.ID)
+	if err := runner.runSync(context.Background(), &run); err == nil {
+		t.Fatal("runSync() error = nil, want the failed request")
+	}
+	if len(connector.calls) != 4 {
+		t.Fatalf("first run made %d requests, want 4", len(connector.calls))
+	}
+	saved := jobs.get(job.ID)
+	if saved.Checkpoint[checkpointChunkKey] != "2024-03-02" || saved.Checkpoint[checkpointCampaignKey] != campaigns.campaigns[0].ID.String() {
+		t.Fatalf("checkpoint = %v", saved.Checkpoint)
+	}
+
+	// The retry picks up the failed campaign and the remaining chunk
+	connector.calls = nil
+	if err := runner.runSync(context.Background(), &saved); err != nil {
+		t.Fatalf("runSync() resumed error = %v", err)
+	}
+	if len(connector.calls) != 3 {
+		t.Fatalf("resumed run made %d requests, want 3", len(connector.calls))
+	}
+	for _, call := range connector.calls {
+		if call.StartDate.Equal(chunks[0].StartDate) {
+			t.Error("resumed run synced the newest chunk again")
+		}
+	}
+	if saved.RecordsProcessed != 6 {
+		t.Errorf("records processed = %d, want 6", saved.RecordsProcessed)
+	}
+	if last := progress.percents[len(progress.percents)-1]; last != 100 {
+		t.Errorf("last progress = %d, want 100", last)
+	}
+}
+
+func TestScheduler_AccountConnectedQueuesBackfill(t *testing.T) {
+	repo := &queueJobRepo{}
+	s := NewScheduler(nil, repo, &backfillAccountRepo{}, &SchedulerConfig{
+		Configs: map[entity.Platform]entity.SyncScheduleConfig{
+			entity.PlatformMeta:   {BackfillMaxMonths: 37, MaxRetries: 3},
+			entity.PlatformShopee: {MaxRetries: 3},
+		},
+	})
+
+	userID := uuid.New()
+	account := &entity.ConnectedAccount{BaseEntity: entity.NewBaseEntity(), Platform: entity.PlatformMeta}
+	s.AccountConnected(context.Background(), account, userID)
+
+	if len(repo.jobs) != 1 {
+		t.Fatalf("queued %d jobs, want 1 backfill", len(repo.jobs))
+	}
+	job := repo.jobs[0]
+	if job.Priority != entity.SyncJobPriorityBackfill || job.ConnectedAccountID != account.ID {
+		t.Errorf("job = priority %d for %s, want a backfill for %s", job.Priority, job.ConnectedAccountID, account.ID)
+	}
+	if job.TriggeredByUser == nil || *job.TriggeredByUser != userID {
+		t.Errorf("TriggeredByUser = %v, want %s", job.TriggeredByUser, userID)
+	}
+	if months := job.DateRangeEnd.Sub(*job.DateRangeStart).Hours() / 24 / 30; months < 36 {
+		t.Errorf("backfill covers about %.0f months, want 37", months)
+	}
+
+	// Platforms without metrics history are not backfilled
+	s.AccountConnected(context.Background(), &entity.ConnectedAccount{BaseEntity: entity.NewBaseEntity(), Platform: entity.PlatformShopee}, userID)
+	if len(repo.jobs) != 1 {
+		t.Errorf("queued %d jobs, want no backfill for Shopee", len(repo.jobs))
+	}
+}
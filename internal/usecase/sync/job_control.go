@@ -0,0 +1,173 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Job Control - Cancel, pause and resume sync jobs
+// ============================================================================
+
+var (
+	// ErrJobCancelled is the context cause of a job cancelled by a user
+	ErrJobCancelled = errors.New("sync job cancelled")
+
+	// ErrJobPaused is the context cause of a job paused by a user
+	ErrJobPaused = errors.New("sync job paused")
+
+	// ErrInvalidJobTransition is returned when a job's status does not allow
+	// the requested change, e.g. pausing a completed job
+	ErrInvalidJobTransition = errors.New("sync job status does not allow this change")
+
+	// errLeaseLost is the context cause of a job whose lease passed to another worker
+	errLeaseLost = errors.New("sync job lease lost")
+)
+
+const (
+	// checkpointStructureKey marks that a full account sync has stored the account structure
+	checkpointStructureKey = "structure_synced"
+
+	// checkpointCampaignKey holds the last campaign, in ID order, whose metrics
+	// were stored. In a backfill it refers to the chunk being synced.
+	checkpointCampaignKey = "metrics_campaign"
+
+	// checkpointChunkKey holds the first day (YYYY-MM-DD) of the oldest backfill
+	// chunk synced so far
+	checkpointChunkKey = "metrics_synced_from"
+)
+
+// CancelJob cancels a queued, running or paused job. A running job is stopped
+// through its context: right away on this process, and at its next heartbeat
+// on any other. Data synced before the cancel is kept.
+func (s *Scheduler) CancelJob(ctx context.Context, jobID uuid.UUID) (*entity.SyncJob, error) {
+	return s.stopJob(ctx, jobID, entity.SyncStatusCancelled, ErrJobCancelled,
+		entity.SyncStatusPending, entity.SyncStatusRetrying, entity.SyncStatusRunning, entity.SyncStatusPaused)
+}
+
+// PauseJob pauses a queued or running job until it is resumed. Paused jobs
+// are not claimed and do not hold up other syncs of their account.
+func (s *Scheduler) PauseJob(ctx context.Context, jobID uuid.UUID) (*entity.SyncJob, error) {
+	return s.stopJob(ctx, jobID, entity.SyncStatusPaused, ErrJobPaused,
+		entity.SyncStatusPending, entity.SyncStatusRetrying, entity.SyncStatusRunning)
+}
+
+// ResumeJob queues a paused job again. It continues from its last checkpoint.
+func (s *Scheduler) ResumeJob(ctx context.Context, jobID uuid.UUID) (*entity.SyncJob, error) {
+	resumed, err := s.syncJobRepo.SetStatusIf(ctx, jobID, entity.SyncStatusPending, entity.SyncStatusPaused)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resume sync job: %w", err)
+	}
+	if !resumed {
+		return nil, ErrInvalidJobTransition
+	}
+
+	log.Printf("[Scheduler] Resumed job %s", jobID)
+	s.notifyWorkers()
+
+	return s.syncJobRepo.GetByID(ctx, jobID)
+}
+
+func (s *Scheduler) stopJob(ctx context.Context, jobID uuid.UUID, status entity.SyncStatus, cause error, from ...entity.SyncStatus) (*entity.SyncJob, error) {
+	stopped, err := s.syncJobRepo.SetStatusIf(ctx, jobID, status, from...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update sync job: %w", err)
+	}
+	if !stopped {
+		return nil, ErrInvalidJobTransition
+	}
+
+	s.mu.RLock()
+	cancel, running := s.running[jobID]
+	s.mu.RUnlock()
+	if running {
+		cancel(cause)
+	}
+
+	log.Printf("[Scheduler] Job %s %s", jobID, status)
+
+	return s.syncJobRepo.GetByID(ctx, jobID)
+}
+
+// releasedLeaseCause tells why a worker no longer holds the lease of its job
+func (s *Scheduler) releasedLeaseCause(ctx context.Context, jobID uuid.UUID) error {
+	job, err := s.syncJobRepo.GetByID(ctx, jobID)
+	if err == nil && job != nil {
+		switch job.Status {
+		case entity.SyncStatusCancelled:
+			return ErrJobCancelled
+		case entity.SyncStatusPaused:
+			return ErrJobPaused
+		}
+	}
+	return errLeaseLost
+}
+
+// jobStopCause returns ErrJobCancelled or ErrJobPaused if a user stopped the
+// job running with ctx, and nil otherwise
+func jobStopCause(ctx context.Context) error {
+	cause := context.Cause(ctx)
+	if errors.Is(cause, ErrJobCancelled) || errors.Is(cause, ErrJobPaused) {
+		return cause
+	}
+	return nil
+}
+
+// handleJobStopped saves the progress of a run stopped by a user. The job's
+// status was already changed by the stop itself.
+func (r *JobRunner) handleJobStopped(ctx context.Context, job *entity.SyncJob, cause error) {
+	// The job context is cancelled, but its progress still has to be saved
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+	defer cancel()
+
+	job.Status = entity.SyncStatusCancelled
+	if errors.Is(cause, ErrJobPaused) {
+		job.Status = entity.SyncStatusPaused
+	}
+
+	if err := r.syncJobRepo.SaveCheckpoint(ctx, job.ID, job.Checkpoint, job.RecordsProcessed, job.RecordsFailed); err != nil {
+		log.Printf("[JobRunner] Error saving checkpoint of job %s: %v", job.ID, err)
+	}
+
+	if err := r.syncStateRepo.UpdateSyncStopped(ctx, job.ConnectedAccountID, job.Status); err != nil {
+		log.Printf("[JobRunner] Error updating sync state: %v", err)
+	}
+
+	log.Printf("[JobRunner] Job %s %s after %d records", job.ID, job.Status, job.RecordsProcessed)
+}
+
+// saveCheckpoint records progress on the job, so a resumed or retried run
+// continues from there
+func (r *JobRunner) saveCheckpoint(ctx context.Context, run *jobRun, key string, value interface{}) {
+	run.tally()
+
+	job := run.job
+	if job.Checkpoint == nil {
+		job.Checkpoint = entity.JSONMap{}
+	}
+	job.Checkpoint[key] = value
+
+	if err := r.syncJobRepo.SaveCheckpoint(ctx, job.ID, job.Checkpoint, job.RecordsProcessed, job.RecordsFailed); err != nil && ctx.Err() == nil {
+		log.Printf("[JobRunner] Error saving checkpoint of job %s: %v", job.ID, err)
+	}
+}
+
+// campaignsAfterCheckpoint orders campaigns by ID and drops those whose
+// metrics an earlier run of the job already stored
+func campaignsAfterCheckpoint(job *entity.SyncJob, campaigns []entity.Campaign) []entity.Campaign {
+	sort.Slice(campaigns, func(i, j int) bool { return campaigns[i].ID.String() < campaigns[j].ID.String() })
+
+	last, _ := job.Checkpoint[checkpointCampaignKey].(string)
+	if last == "" {
+		return campaigns
+	}
+	i := sort.Search(len(campaigns), func(i int) bool { return campaigns[i].ID.String() > last })
+	return campaigns[i:]
+}
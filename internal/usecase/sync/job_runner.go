@@ -4,37 +4,45 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"sort"
 	"sync"
 	"time"
 
 	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
 	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
 	"github.com/ads-aggregator/ads-aggregator/internal/domain/service"
+	"github.com/ads-aggregator/ads-aggregator/pkg/ratelimit"
 	"github.com/google/uuid"
-	"golang.org/x/time/rate"
 )
 
 // ============================================================================
 // Job Runner - Executes sync jobs with retry and rate limiting
 // ============================================================================
 
-// JobRunner executes sync jobs with proper error handling and retry logic
+// JobRunner executes sync jobs with proper error handling and retry logic. It
+// runs one scope of a queued job at a time through the sync Service, which
+// makes the API calls within the account's rate limit and retries transient
+// errors, and checkpoints as it goes so a paused, interrupted or retried job
+// resumes where it stopped.
 type JobRunner struct {
+	// Sync engine, holding the connectors and the data repositories
+	engine *Service
+
 	// Repositories
-	syncStateRepo   repository.SyncStateRepository
-	syncJobRepo     repository.SyncJobRepository
-	retryQueueRepo  repository.RetryQueueRepository
-	errorLogRepo    repository.SyncErrorLogRepository
-	connAccountRepo repository.ConnectedAccountRepository
-	campaignRepo    repository.CampaignRepository
-	metricsRepo     repository.MetricsRepository
+	syncStateRepo  repository.SyncStateRepository
+	syncJobRepo    repository.SyncJobRepository
+	retryQueueRepo repository.RetryQueueRepository
+	errorLogRepo   repository.SyncErrorLogRepository
+
+	// Marketplace order repositories (optional, see SetOrderRepositories)
+	orderRepo        repository.OrderRepository
+	salesSummaryRepo repository.SalesSummaryRepository
 
-	// Platform connectors
-	connectors map[entity.Platform]service.PlatformConnector
+	// Notified when a job completes or fails for good (optional, see AddJobListener)
+	jobListeners []SyncJobListener
 
-	// Rate limiters per platform
-	rateLimiters map[entity.Platform]*rate.Limiter
-	rateLimiterMu sync.RWMutex
+	// Notified as jobs make progress (optional, see AddProgressListener)
+	progressListeners []SyncProgressListener
 
 	// Configuration
 	configs map[entity.Platform]entity.SyncScheduleConfig
@@ -49,6 +57,20 @@ type JobRunner struct {
 	cancel context.CancelFunc
 }
 
+// SyncJobListener is notified when a sync job completes, or fails and will not
+// be retried, e.g. to deliver sync webhooks. On failure the job carries the
+// error message and its classification in ErrorCode.
+type SyncJobListener interface {
+	SyncJobCompleted(ctx context.Context, job *entity.SyncJob)
+	SyncJobFailed(ctx context.Context, job *entity.SyncJob, err error)
+}
+
+// SyncProgressListener is notified as a sync job makes progress, e.g. to push
+// it over the SSE event stream
+type SyncProgressListener interface {
+	SyncJobProgress(ctx context.Context, job *entity.SyncJob, percent int, message string)
+}
+
 // JobRunnerConfig holds configuration for the job runner
 type JobRunnerConfig struct {
 	MaxConcurrentJobs int
@@ -63,50 +85,46 @@ func DefaultJobRunnerConfig() *JobRunnerConfig {
 	}
 }
 
-// NewJobRunner creates a new job runner
+// NewJobRunner creates a new job runner. Metrics listeners are added to the
+// engine, which notifies them for jobs and on-demand syncs alike.
 func NewJobRunner(
+	engine *Service,
 	syncStateRepo repository.SyncStateRepository,
 	syncJobRepo repository.SyncJobRepository,
 	retryQueueRepo repository.RetryQueueRepository,
 	errorLogRepo repository.SyncErrorLogRepository,
-	connAccountRepo repository.ConnectedAccountRepository,
-	campaignRepo repository.CampaignRepository,
-	metricsRepo repository.MetricsRepository,
 	config *JobRunnerConfig,
 ) *JobRunner {
 	if config == nil {
 		config = DefaultJobRunnerConfig()
 	}
 
-	runner := &JobRunner{
+	return &JobRunner{
+		engine:            engine,
 		syncStateRepo:     syncStateRepo,
 		syncJobRepo:       syncJobRepo,
 		retryQueueRepo:    retryQueueRepo,
 		errorLogRepo:      errorLogRepo,
-		connAccountRepo:   connAccountRepo,
-		campaignRepo:      campaignRepo,
-		metricsRepo:       metricsRepo,
-		connectors:        make(map[entity.Platform]service.PlatformConnector),
-		rateLimiters:      make(map[entity.Platform]*rate.Limiter),
 		configs:           config.Configs,
 		maxConcurrentJobs: config.MaxConcurrentJobs,
 		jobSemaphore:      make(chan struct{}, config.MaxConcurrentJobs),
 	}
+}
 
-	// Initialize rate limiters
-	for platform, cfg := range config.Configs {
-		runner.rateLimiters[platform] = rate.NewLimiter(
-			rate.Limit(cfg.MaxRequestsPerMinute)/60, // Convert to per-second
-			cfg.RequestBurstSize,
-		)
-	}
+// AddJobListener adds a listener notified when a job completes or fails without further retries
+func (r *JobRunner) AddJobListener(listener SyncJobListener) {
+	r.jobListeners = append(r.jobListeners, listener)
+}
 
-	return runner
+// AddProgressListener adds a listener notified whenever a job reports progress
+func (r *JobRunner) AddProgressListener(listener SyncProgressListener) {
+	r.progressListeners = append(r.progressListeners, listener)
 }
 
-// RegisterConnector registers a platform connector
-func (r *JobRunner) RegisterConnector(platform entity.Platform, connector service.PlatformConnector) {
-	r.connectors[platform] = connector
+// SetOrderRepositories sets the repositories used by the orders sync scope
+func (r *JobRunner) SetOrderRepositories(orderRepo repository.OrderRepository, salesSummaryRepo repository.SalesSummaryRepository) {
+	r.orderRepo = orderRepo
+	r.salesSummaryRepo = salesSummaryRepo
 }
 
 // Start starts the job runner
@@ -157,20 +175,18 @@ func (r *JobRunner) ExecuteJob(ctx context.Context, job *entity.SyncJob) error {
 
 	// Execute based on sync scope
 	var err error
-	switch job.SyncScope {
-	case entity.SyncScopeAccount:
-		err = r.syncFullAccount(ctx, job)
-	case entity.SyncScopeCampaign:
-		err = r.syncCampaign(ctx, job)
-	case entity.SyncScopeMetrics:
-		err = r.syncMetrics(ctx, job)
-	case entity.SyncScopeStructure:
-		err = r.syncStructure(ctx, job)
-	default:
-		err = fmt.Errorf("unknown sync scope: %s", job.SyncScope)
+	if job.SyncScope == entity.SyncScopeOrders {
+		err = r.runOrders(ctx, job)
+	} else {
+		err = r.runSync(ctx, job)
 	}
 
-	// Handle result
+	// Handle result. A job stopped by a user keeps its status whatever the
+	// interrupted sync returned.
+	if cause := jobStopCause(ctx); cause != nil {
+		r.handleJobStopped(ctx, job, cause)
+		return cause
+	}
 	if err != nil {
 		r.handleJobError(ctx, job, err)
 		return err
@@ -189,6 +205,11 @@ func (r *JobRunner) ExecuteJob(ctx context.Context, job *entity.SyncJob) error {
 	log.Printf("[JobRunner] Job %s completed: %d records processed, %d failed",
 		job.ID, job.RecordsProcessed, job.RecordsFailed)
 
+	job.Status = entity.SyncStatusCompleted
+	for _, listener := range r.jobListeners {
+		listener.SyncJobCompleted(ctx, job)
+	}
+
 	return nil
 }
 
@@ -196,245 +217,354 @@ func (r *JobRunner) ExecuteJob(ctx context.Context, job *entity.SyncJob) error {
 // Sync Operations
 // ============================================================================
 
-func (r *JobRunner) syncFullAccount(ctx context.Context, job *entity.SyncJob) error {
-	log.Printf("[JobRunner] Full account sync for %s", job.ConnectedAccountID)
+// jobRun is one run of a job through the sync engine. Its result collects what
+// the engine synced and the errors it met along the way.
+type jobRun struct {
+	job       *entity.SyncJob
+	account   *entity.ConnectedAccount
+	connector service.PlatformConnector
+	limiter   *ratelimit.Limiter
+	result    *service.SyncResult
+
+	// What of the result was already added to the job's record counts
+	synced, failed int
+}
 
-	// Sync structure first
-	if err := r.syncStructure(ctx, job); err != nil {
-		return fmt.Errorf("structure sync failed: %w", err)
-	}
+// tally adds what the run synced since the last tally to the job's record counts
+func (run *jobRun) tally() {
+	synced := run.result.CampaignsSynced + run.result.AdSetsSynced + run.result.AdsSynced + run.result.MetricsSynced
+	run.job.RecordsProcessed += synced - run.synced
+	run.job.RecordsFailed += len(run.result.Errors) - run.failed
+	run.synced, run.failed = synced, len(run.result.Errors)
+}
 
-	// Then sync metrics
-	if err := r.syncMetrics(ctx, job); err != nil {
-		return fmt.Errorf("metrics sync failed: %w", err)
+// err returns the first error of a run that synced nothing at all. A run that
+// partly succeeded completes, with its errors counted as failed records.
+func (run *jobRun) err() error {
+	if !run.result.IsSuccess() && !run.result.HasPartialSuccess() {
+		return run.result.Errors[0]
 	}
-
 	return nil
 }
 
-func (r *JobRunner) syncStructure(ctx context.Context, job *entity.SyncJob) error {
-	connector, ok := r.connectors[job.Platform]
-	if !ok {
-		return fmt.Errorf("no connector for platform: %s", job.Platform)
-	}
-
-	// Get connected account for access token
-	account, err := r.connAccountRepo.GetByID(ctx, job.ConnectedAccountID)
+// runSync runs a campaign, metrics, structure or full account job through the engine
+func (r *JobRunner) runSync(ctx context.Context, job *entity.SyncJob) error {
+	account, connector, err := r.engine.activeAccount(ctx, job.ConnectedAccountID)
 	if err != nil {
-		return fmt.Errorf("failed to get connected account: %w", err)
-	}
-
-	// Check token expiry
-	if account.IsTokenExpired() {
-		return fmt.Errorf("access token expired")
-	}
-
-	// Rate limit
-	if err := r.waitForRateLimit(ctx, job.Platform); err != nil {
 		return err
 	}
 
-	// Update progress
-	r.updateProgress(ctx, job.ID, 10, "Fetching campaigns...")
+	run := &jobRun{
+		job:       job,
+		account:   account,
+		connector: connector,
+		limiter:   r.engine.accountLimiter(account),
+		result: &service.SyncResult{
+			Platform:  account.Platform,
+			AccountID: account.ID,
+			StartedAt: time.Now().Unix(),
+			Errors:    make([]error, 0),
+		},
+	}
+	defer run.tally()
 
-	// Get campaigns
-	campaigns, err := connector.GetCampaigns(ctx, account.AccessToken, account.PlatformAccountID)
-	if err != nil {
-		return fmt.Errorf("failed to get campaigns: %w", err)
+	switch job.SyncScope {
+	case entity.SyncScopeAccount:
+		return r.runAccount(ctx, run)
+	case entity.SyncScopeCampaign:
+		return r.runCampaign(ctx, run)
+	case entity.SyncScopeMetrics:
+		return r.runMetrics(ctx, run)
+	case entity.SyncScopeStructure:
+		return r.runStructure(ctx, run)
+	default:
+		return fmt.Errorf("unknown sync scope: %s", job.SyncScope)
 	}
+}
 
-	r.updateProgress(ctx, job.ID, 50, fmt.Sprintf("Processing %d campaigns...", len(campaigns)))
+func (r *JobRunner) runAccount(ctx context.Context, run *jobRun) error {
+	log.Printf("[JobRunner] Full account sync for %s", run.account.ID)
 
-	// Upsert campaigns
-	for _, campaign := range campaigns {
-		campaign.OrganizationID = account.OrganizationID
-		if err := r.campaignRepo.Upsert(ctx, &campaign); err != nil {
-			log.Printf("[JobRunner] Error upserting campaign: %v", err)
-			job.RecordsFailed++
-		} else {
-			job.RecordsProcessed++
+	// Sync structure first, unless an earlier run of the job already did
+	if done, _ := run.job.Checkpoint[checkpointStructureKey].(bool); !done {
+		if err := r.runStructure(ctx, run); err != nil {
+			return fmt.Errorf("structure sync failed: %w", err)
 		}
+		r.saveCheckpoint(ctx, run, checkpointStructureKey, true)
 	}
 
-	r.updateProgress(ctx, job.ID, 100, "Structure sync complete")
+	// Then sync metrics
+	if err := r.runMetrics(ctx, run); err != nil {
+		return fmt.Errorf("metrics sync failed: %w", err)
+	}
 
 	return nil
 }
 
-func (r *JobRunner) syncMetrics(ctx context.Context, job *entity.SyncJob) error {
-	connector, ok := r.connectors[job.Platform]
-	if !ok {
-		return fmt.Errorf("no connector for platform: %s", job.Platform)
-	}
+// runStructure syncs the ad accounts of the job's account and the campaigns, ad
+// sets and ads of each. Connectors that can list changes only fetch what changed
+// since the last sync; the others list everything.
+func (r *JobRunner) runStructure(ctx context.Context, run *jobRun) error {
+	job := run.job
+	r.updateProgress(ctx, job, 10, "Fetching ad accounts...")
 
-	account, err := r.connAccountRepo.GetByID(ctx, job.ConnectedAccountID)
+	if err := r.engine.syncAdAccounts(ctx, run.account, run.connector, run.limiter); err != nil {
+		r.engine.recordError(run.result, err, "Failed to sync ad accounts")
+	}
+	adAccounts, err := r.engine.adAccountRepo.ListByConnectedAccount(ctx, run.account.ID)
 	if err != nil {
-		return fmt.Errorf("failed to get connected account: %w", err)
+		return fmt.Errorf("failed to list ad accounts: %w", err)
 	}
 
-	if account.IsTokenExpired() {
-		return fmt.Errorf("access token expired")
+	incrementalConnector, incremental := run.connector.(service.IncrementalStructureConnector)
+
+	var state *entity.SyncState
+	startedAt := time.Now()
+	since, fromCursor := time.Time{}, false
+	if incremental {
+		state, err = r.syncStateRepo.GetByConnectedAccount(ctx, job.ConnectedAccountID)
+		if err != nil {
+			log.Printf("[JobRunner] Error getting sync state, listing the whole structure: %v", err)
+		}
+		since, fromCursor = structureSyncSince(job, state, startedAt)
 	}
+	failedBefore := len(run.result.Errors)
 
-	// Get date range
-	dateRange := entity.DateRange{}
-	if job.DateRangeStart != nil {
-		dateRange.StartDate = *job.DateRangeStart
-	} else {
-		dateRange.StartDate = time.Now().AddDate(0, 0, -7)
+	options := &SyncOptions{SyncCampaigns: true, SyncAdSets: true, SyncAds: true}
+	for i, adAccount := range adAccounts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r.updateProgress(ctx, job, 10+80*i/len(adAccounts), fmt.Sprintf("Syncing ad account %d/%d...", i+1, len(adAccounts)))
+
+		if incremental {
+			r.engine.syncStructureChanges(ctx, run.account, adAccount, incrementalConnector, run.limiter, since, run.result)
+		} else {
+			r.engine.syncAdAccount(ctx, run.account, adAccount, run.connector, run.limiter, options, run.result)
+		}
+		run.tally()
 	}
-	if job.DateRangeEnd != nil {
-		dateRange.EndDate = *job.DateRangeEnd
-	} else {
-		dateRange.EndDate = time.Now()
+
+	// Only move the high-water mark when every object was stored, so failed
+	// objects are fetched again on the next run
+	if state != nil && len(run.result.Errors) == failedBefore {
+		r.recordStructureSynced(ctx, state, startedAt, !fromCursor)
 	}
 
-	r.updateProgress(ctx, job.ID, 10, "Fetching campaigns for metrics...")
+	r.updateProgress(ctx, job, 100, "Structure sync complete")
+
+	return run.err()
+}
+
+// runMetrics syncs the metrics of every campaign of the job's account, campaign
+// by campaign. Long ranges are backfilled in checkpointed chunks instead.
+func (r *JobRunner) runMetrics(ctx context.Context, run *jobRun) error {
+	job := run.job
+	dateRange := jobDateRange(job)
+
+	r.updateProgress(ctx, job, 10, "Fetching campaigns for metrics...")
 
-	// Get campaigns
-	campaigns, err := r.campaignRepo.ListByAdAccount(ctx, job.ConnectedAccountID)
+	campaigns, err := r.engine.storedCampaigns(ctx, run.account)
 	if err != nil {
-		return fmt.Errorf("failed to list campaigns: %w", err)
+		return err
 	}
 
 	totalCampaigns := len(campaigns)
 	if totalCampaigns == 0 {
-		r.updateProgress(ctx, job.ID, 100, "No campaigns to sync")
-		return nil
+		r.updateProgress(ctx, job, 100, "No campaigns to sync")
+		return run.err()
 	}
 
-	// Sync metrics for each campaign
-	for i, campaign := range campaigns {
-		if err := r.waitForRateLimit(ctx, job.Platform); err != nil {
-			return err
-		}
+	// Long ranges are fetched in checkpointed chunks
+	config := r.configs[job.Platform]
+	if chunks := backfillChunks(dateRange, config.BackfillChunkDays); len(chunks) > 1 {
+		return r.backfillMetrics(ctx, run, campaigns, chunks)
+	}
 
-		progress := 10 + (80 * (i + 1) / totalCampaigns)
-		r.updateProgress(ctx, job.ID, progress, fmt.Sprintf("Syncing metrics for campaign %d/%d", i+1, totalCampaigns))
+	// Skip campaigns stored by an earlier run of the job
+	campaigns = campaignsAfterCheckpoint(job, campaigns)
+	done := totalCampaigns - len(campaigns)
 
-		metrics, err := connector.GetCampaignInsights(ctx, account.AccessToken, campaign.PlatformCampaignID, dateRange)
-		if err != nil {
-			log.Printf("[JobRunner] Error getting insights for campaign %s: %v", campaign.ID, err)
-			job.RecordsFailed++
-			continue
-		}
+	var syncedCampaigns []uuid.UUID
+	for i, campaign := range campaigns {
+		progress := 10 + (80 * (done + i + 1) / totalCampaigns)
+		r.updateProgress(ctx, job, progress, fmt.Sprintf("Syncing metrics for campaign %d/%d", done+i+1, totalCampaigns))
 
-		// Upsert metrics
-		for j := range metrics {
-			metrics[j].CampaignID = campaign.ID
-			metrics[j].OrganizationID = account.OrganizationID
-			if err := r.metricsRepo.UpsertCampaignMetrics(ctx, &metrics[j]); err != nil {
-				log.Printf("[JobRunner] Error upserting metrics: %v", err)
-				job.RecordsFailed++
-			} else {
-				job.RecordsProcessed++
+		count, err := r.engine.syncCampaignMetrics(ctx, run.account, campaign, run.connector, run.limiter, dateRange)
+		if ctx.Err() != nil {
+			// Interrupted, so the campaign is synced again on resume
+			return ctx.Err()
+		}
+		if err != nil {
+			r.engine.recordError(run.result, err, "Failed to sync campaign metrics")
+		} else {
+			run.result.MetricsSynced += count
+			if count > 0 {
+				syncedCampaigns = append(syncedCampaigns, campaign.ID)
 			}
 		}
+		r.saveCheckpoint(ctx, run, checkpointCampaignKey, campaign.ID.String())
 	}
 
-	r.updateProgress(ctx, job.ID, 100, "Metrics sync complete")
+	r.engine.notifyMetricsSynced(ctx, run.account.OrganizationID, syncedCampaigns)
 
-	return nil
+	r.updateProgress(ctx, job, 100, "Metrics sync complete")
+
+	return run.err()
 }
 
-func (r *JobRunner) syncCampaign(ctx context.Context, job *entity.SyncJob) error {
+func (r *JobRunner) runCampaign(ctx context.Context, run *jobRun) error {
+	job := run.job
 	if job.CampaignID == nil {
 		return fmt.Errorf("campaign ID is required for campaign sync")
 	}
 
-	connector, ok := r.connectors[job.Platform]
-	if !ok {
-		return fmt.Errorf("no connector for platform: %s", job.Platform)
-	}
-
-	account, err := r.connAccountRepo.GetByID(ctx, job.ConnectedAccountID)
+	campaign, err := r.engine.campaignRepo.GetByID(ctx, *job.CampaignID)
 	if err != nil {
-		return fmt.Errorf("failed to get connected account: %w", err)
+		return fmt.Errorf("failed to get campaign: %w", err)
 	}
 
-	if account.IsTokenExpired() {
-		return fmt.Errorf("access token expired")
-	}
+	r.updateProgress(ctx, job, 30, "Fetching campaign metrics...")
 
-	campaign, err := r.campaignRepo.GetByID(ctx, *job.CampaignID)
+	count, err := r.engine.syncCampaignMetrics(ctx, run.account, *campaign, run.connector, run.limiter, jobDateRange(job))
 	if err != nil {
-		return fmt.Errorf("failed to get campaign: %w", err)
+		return fmt.Errorf("failed to sync campaign metrics: %w", err)
 	}
+	run.result.MetricsSynced += count
+	if count > 0 {
+		r.engine.notifyMetricsSynced(ctx, run.account.OrganizationID, []uuid.UUID{campaign.ID})
+	}
+
+	r.updateProgress(ctx, job, 100, "Campaign sync complete")
 
-	// Get date range
-	dateRange := entity.DateRange{}
+	return nil
+}
+
+// jobDateRange returns the metrics date range of a job, by default the last 7 days
+func jobDateRange(job *entity.SyncJob) entity.DateRange {
+	dateRange := entity.Last7Days()
 	if job.DateRangeStart != nil {
 		dateRange.StartDate = *job.DateRangeStart
-	} else {
-		dateRange.StartDate = time.Now().AddDate(0, 0, -7)
 	}
 	if job.DateRangeEnd != nil {
 		dateRange.EndDate = *job.DateRangeEnd
-	} else {
-		dateRange.EndDate = time.Now()
 	}
+	return dateRange
+}
 
-	r.updateProgress(ctx, job.ID, 30, "Fetching campaign metrics...")
+func (r *JobRunner) runOrders(ctx context.Context, job *entity.SyncJob) error {
+	if r.orderRepo == nil || r.salesSummaryRepo == nil {
+		return fmt.Errorf("order sync is not configured")
+	}
 
-	if err := r.waitForRateLimit(ctx, job.Platform); err != nil {
+	account, connector, err := r.engine.activeAccount(ctx, job.ConnectedAccountID)
+	if err != nil {
 		return err
 	}
+	orderConnector, ok := connector.(service.OrderConnector)
+	if !ok {
+		return fmt.Errorf("platform %s does not support order sync", job.Platform)
+	}
 
-	metrics, err := connector.GetCampaignInsights(ctx, account.AccessToken, campaign.PlatformCampaignID, dateRange)
+	state, err := r.syncStateRepo.GetByConnectedAccount(ctx, job.ConnectedAccountID)
 	if err != nil {
-		return fmt.Errorf("failed to get campaign insights: %w", err)
+		log.Printf("[JobRunner] Error getting sync state, syncing default order window: %v", err)
 	}
+	from, to := orderSyncWindow(job, state, time.Now())
 
-	r.updateProgress(ctx, job.ID, 70, "Saving metrics...")
+	r.updateProgress(ctx, job, 10, fmt.Sprintf("Fetching orders updated since %s...", from.Format(time.RFC3339)))
 
-	for i := range metrics {
-		metrics[i].CampaignID = campaign.ID
-		metrics[i].OrganizationID = account.OrganizationID
-		if err := r.metricsRepo.UpsertCampaignMetrics(ctx, &metrics[i]); err != nil {
-			log.Printf("[JobRunner] Error upserting metrics: %v", err)
-			job.RecordsFailed++
-		} else {
-			job.RecordsProcessed++
-		}
+	var orders []entity.Order
+	err = r.engine.call(ctx, r.engine.accountLimiter(account), "GetUpdatedOrders", func(ctx context.Context) error {
+		var err error
+		orders, err = orderConnector.GetUpdatedOrders(ctx, account.AccessToken, account.PlatformAccountID, from, to)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get orders: %w", err)
 	}
 
-	r.updateProgress(ctx, job.ID, 100, "Campaign sync complete")
+	r.updateProgress(ctx, job, 50, fmt.Sprintf("Processing %d orders...", len(orders)))
 
-	return nil
-}
+	// Every day touched by an updated order needs its summary rebuilt
+	dates := make(map[time.Time]bool)
+	for i := range orders {
+		orders[i].OrganizationID = account.OrganizationID
+		for j := range orders[i].Items {
+			orders[i].Items[j].OrganizationID = account.OrganizationID
+		}
 
-// ============================================================================
-// Rate Limiting
-// ============================================================================
+		if err := r.orderRepo.Upsert(ctx, &orders[i]); err != nil {
+			log.Printf("[JobRunner] Error upserting order %s: %v", orders[i].PlatformOrderID, err)
+			job.RecordsFailed++
+			continue
+		}
+		job.RecordsProcessed++
+		dates[orders[i].OrderDate] = true
+	}
 
-func (r *JobRunner) waitForRateLimit(ctx context.Context, platform entity.Platform) error {
-	r.rateLimiterMu.RLock()
-	limiter, ok := r.rateLimiters[platform]
-	r.rateLimiterMu.RUnlock()
+	r.updateProgress(ctx, job, 80, fmt.Sprintf("Rolling up sales for %d days...", len(dates)))
 
-	if !ok {
-		return nil // No rate limiter for this platform
+	summaryDates := make([]time.Time, 0, len(dates))
+	for date := range dates {
+		summaryDates = append(summaryDates, date)
+	}
+	sort.Slice(summaryDates, func(i, j int) bool { return summaryDates[i].Before(summaryDates[j]) })
+
+	if err := r.salesSummaryRepo.RebuildDaily(ctx, account.OrganizationID, job.Platform, summaryDates); err != nil {
+		return fmt.Errorf("failed to roll up daily sales: %w", err)
 	}
 
-	if err := limiter.Wait(ctx); err != nil {
-		return fmt.Errorf("rate limit wait failed: %w", err)
+	// Only move the cursor forward when every order was stored, so failed
+	// orders are fetched again on the next run
+	if state != nil && job.RecordsFailed == 0 && job.DateRangeStart == nil {
+		if state.Metadata == nil {
+			state.Metadata = entity.JSONMap{}
+		}
+		state.Metadata[ordersSyncedUntilKey] = to.UTC().Format(time.RFC3339)
+		if err := r.syncStateRepo.Update(ctx, state); err != nil {
+			log.Printf("[JobRunner] Error saving order sync cursor: %v", err)
+		}
 	}
 
+	r.updateProgress(ctx, job, 100, "Order sync complete")
+
 	return nil
 }
 
-// SetRateLimitFromHeader updates rate limiter based on API response headers
-func (r *JobRunner) SetRateLimitFromHeader(platform entity.Platform, remaining int, resetAt time.Time) {
-	r.rateLimiterMu.Lock()
-	defer r.rateLimiterMu.Unlock()
+const (
+	// ordersSyncedUntilKey is the sync state metadata key holding the order sync cursor
+	ordersSyncedUntilKey = "orders_synced_until"
 
-	if remaining <= 0 {
-		// Set a very low limit until reset
-		duration := time.Until(resetAt)
-		if duration > 0 {
-			r.rateLimiters[platform] = rate.NewLimiter(rate.Every(duration), 1)
+	// defaultOrderSyncLookback is how far back the first order sync reaches
+	defaultOrderSyncLookback = 30 * 24 * time.Hour
+
+	// orderSyncOverlap re-reads recent updates in case the marketplace records them late
+	orderSyncOverlap = 10 * time.Minute
+)
+
+// orderSyncWindow returns the update_time range to fetch orders for. An explicit
+// job date range wins; otherwise the sync continues from the stored cursor.
+func orderSyncWindow(job *entity.SyncJob, state *entity.SyncState, now time.Time) (from, to time.Time) {
+	to = now
+	if job.DateRangeEnd != nil && job.DateRangeEnd.Before(now) {
+		to = *job.DateRangeEnd
+	}
+
+	if job.DateRangeStart != nil {
+		return *job.DateRangeStart, to
+	}
+
+	from = now.Add(-defaultOrderSyncLookback)
+	if state != nil {
+		if value, ok := state.Metadata[ordersSyncedUntilKey].(string); ok {
+			if cursor, err := time.Parse(time.RFC3339, value); err == nil {
+				from = cursor.Add(-orderSyncOverlap)
+			}
 		}
 	}
+
+	return from, to
 }
 
 // ============================================================================
@@ -473,6 +603,13 @@ func (r *JobRunner) handleJobError(ctx context.Context, job *entity.SyncJob, err
 		if stateErr := r.syncStateRepo.UpdateSyncFailed(ctx, job.ConnectedAccountID, err.Error()); stateErr != nil {
 			log.Printf("[JobRunner] Error updating sync state: %v", stateErr)
 		}
+
+		job.Status = entity.SyncStatusFailed
+		job.ErrorMessage = err.Error()
+		job.ErrorCode = r.classifyError(err)
+		for _, listener := range r.jobListeners {
+			listener.SyncJobFailed(ctx, job, err)
+		}
 	}
 }
 
@@ -587,10 +724,14 @@ func (r *JobRunner) ProcessRetryQueue(ctx context.Context) error {
 // Progress Updates
 // ============================================================================
 
-func (r *JobRunner) updateProgress(ctx context.Context, jobID uuid.UUID, percent int, message string) {
-	if err := r.syncJobRepo.UpdateProgress(ctx, jobID, percent, message); err != nil {
+func (r *JobRunner) updateProgress(ctx context.Context, job *entity.SyncJob, percent int, message string) {
+	if err := r.syncJobRepo.UpdateProgress(ctx, job.ID, percent, message); err != nil {
 		log.Printf("[JobRunner] Error updating progress: %v", err)
 	}
+
+	for _, listener := range r.progressListeners {
+		listener.SyncJobProgress(ctx, job, percent, message)
+	}
 }
 
 // ============================================================================
@@ -0,0 +1,145 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/retry"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func TestOrderSyncWindow(t *testing.T) {
+	now := time.Date(2024, 9, 15, 12, 0, 0, 0, time.UTC)
+	cursor := time.Date(2024, 9, 15, 11, 0, 0, 0, time.UTC)
+	stateWithCursor := &entity.SyncState{Metadata: entity.JSONMap{ordersSyncedUntilKey: cursor.Format(time.RFC3339)}}
+	start := time.Date(2024, 9, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2024, 9, 10, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		job      *entity.SyncJob
+		state    *entity.SyncState
+		wantFrom time.Time
+		wantTo   time.Time
+	}{
+		{"first sync", &entity.SyncJob{}, nil, now.Add(-defaultOrderSyncLookback), now},
+		{"no cursor yet", &entity.SyncJob{}, &entity.SyncState{}, now.Add(-defaultOrderSyncLookback), now},
+		{"continues from cursor", &entity.SyncJob{}, stateWithCursor, cursor.Add(-orderSyncOverlap), now},
+		{"explicit range", &entity.SyncJob{DateRangeStart: &start, DateRangeEnd: &end}, stateWithCursor, start, end},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to := orderSyncWindow(tt.job, tt.state, now)
+			if !from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo) {
+				t.Errorf("window = [%v, %v], want [%v, %v]", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+}
+
+func TestCampaignsAfterCheckpoint(t *testing.T) {
+	ids := []uuid.UUID{
+		uuid.MustParse("30000000-0000-0000-0000-000000000000"),
+		uuid.MustParse("10000000-0000-0000-0000-000000000000"),
+		uuid.MustParse("20000000-0000-0000-0000-000000000000"),
+	}
+	campaigns := func() []entity.Campaign {
+		var list []entity.Campaign
+		for _, id := range ids {
+			list = append(list, entity.Campaign{BaseEntity: entity.BaseEntity{ID: id}})
+		}
+		return list
+	}
+
+	fresh := campaignsAfterCheckpoint(&entity.SyncJob{}, campaigns())
+	if len(fresh) != 3 || fresh[0].ID != ids[1] || fresh[2].ID != ids[0] {
+		t.Errorf("without checkpoint got %v, want all campaigns in ID order", fresh)
+	}
+
+	resumed := campaignsAfterCheckpoint(&entity.SyncJob{Checkpoint: entity.JSONMap{checkpointCampaignKey: ids[1].String()}}, campaigns())
+	if len(resumed) != 2 || resumed[0].ID != ids[2] {
+		t.Errorf("after checkpoint got %v, want the last two campaigns", resumed)
+	}
+}
+
+type runnerStateRepo struct {
+	repository.SyncStateRepository
+	completed bool
+}
+
+func (r *runnerStateRepo) UpdateSyncStarted(ctx context.Context, connectedAccountID, jobID uuid.UUID) error {
+	return nil
+}
+
+func (r *runnerStateRepo) UpdateSyncCompleted(ctx context.Context, connectedAccountID uuid.UUID, syncType entity.SyncType) error {
+	r.completed = true
+	return nil
+}
+
+type jobListenerRecorder struct {
+	completed []*entity.SyncJob
+	failed    []*entity.SyncJob
+}
+
+func (l *jobListenerRecorder) SyncJobCompleted(ctx context.Context, job *entity.SyncJob) {
+	l.completed = append(l.completed, job)
+}
+
+func (l *jobListenerRecorder) SyncJobFailed(ctx context.Context, job *entity.SyncJob, err error) {
+	l.failed = append(l.failed, job)
+}
+
+func TestJobRunner_ExecuteJobSyncsThroughEngine(t *testing.T) {
+	accounts := &engineAccountRepo{}
+	accountID := accounts.add(entity.PlatformTikTok)
+	connector := &engineConnector{failInsights: true}
+	campaigns := &engineCampaignRepo{}
+	engine := NewService(&ServiceConfig{RetryConfig: &retry.Config{MaxRetries: 0}}, accounts, &engineAdAccountRepo{},
+		campaigns, &engineAdSetRepo{}, &engineAdRepo{}, &engineMetricsRepo{}, engineRegistry{entity.PlatformTikTok: connector}, zerolog.Nop())
+
+	jobs := &queueJobRepo{}
+	job := jobs.add(entity.SyncJobPriorityManual, entity.SyncStatusRunning)
+	job.ConnectedAccountID = accountID
+	job.Platform = entity.PlatformTikTok
+	job.SyncType = entity.SyncTypeManual
+	job.SyncScope = entity.SyncScopeAccount
+
+	state := &runnerStateRepo{}
+	listener := &jobListenerRecorder{}
+	runner := NewJobRunner(engine, state, jobs, nil, nil, &JobRunnerConfig{
+		MaxConcurrentJobs: 1,
+		Configs:           map[entity.Platform]entity.SyncScheduleConfig{entity.PlatformTikTok: {}},
+	})
+	runner.AddJobListener(listener)
+
+	// Failed metrics leave the job partly successful, so it completes
+	if err := runner.ExecuteJob(context.Background(), job); err != nil {
+		t.Fatalf("ExecuteJob() error = %v", err)
+	}
+	if len(listener.completed) != 1 || !state.completed {
+		t.Fatalf("job not completed, listener = %+v", listener)
+	}
+	if job.RecordsProcessed != 6 || job.RecordsFailed != 2 {
+		t.Errorf("records = %d processed, %d failed, want 2 campaigns, 2 ad sets and 2 ads, and 2 failed metrics requests",
+			job.RecordsProcessed, job.RecordsFailed)
+	}
+
+	// Campaigns belong to the ad account, not the connected account
+	adAccountID := uuid.NewSHA1(accountID, []byte("act_1"))
+	for _, campaign := range campaigns.campaigns {
+		if campaign.AdAccountID != adAccountID {
+			t.Errorf("campaign %s stored under %s, want ad account %s", campaign.PlatformCampaignID, campaign.AdAccountID, adAccountID)
+		}
+	}
+
+	// Calls went through the account's rate limiter
+	if _, ok := engine.rateLimiter.Get(fmt.Sprintf("%s_%s", entity.PlatformTikTok, accounts.accounts[0].PlatformAccountID)); !ok {
+		t.Error("account rate limiter not used")
+	}
+}
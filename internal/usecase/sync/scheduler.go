@@ -2,6 +2,7 @@ package sync
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"sync"
@@ -35,9 +36,20 @@ type Scheduler struct {
 	// Tick interval for checking scheduled jobs
 	tickInterval time.Duration
 
-	// Worker ID for job claiming
+	// Worker ID for job claiming, suffixed with the worker number
 	workerID string
 
+	// Job queue
+	concurrency       int
+	pollInterval      time.Duration
+	leaseDuration     time.Duration
+	heartbeatInterval time.Duration
+	reapInterval      time.Duration
+	wake              chan struct{} // Signals idle workers that a job was queued
+
+	// Cancels the context of each job running on this process
+	running map[uuid.UUID]context.CancelCauseFunc
+
 	// Callback for job execution
 	jobExecutor JobExecutor
 }
@@ -52,14 +64,26 @@ type SchedulerConfig struct {
 	TickInterval time.Duration
 	WorkerID     string
 	Configs      map[entity.Platform]entity.SyncScheduleConfig
+
+	// Job queue
+	Concurrency       int           // Jobs run at once by this process
+	PollInterval      time.Duration // How often idle workers look for a job
+	LeaseDuration     time.Duration // How long a claimed job stays leased without a heartbeat
+	HeartbeatInterval time.Duration // How often running jobs renew their lease
+	ReapInterval      time.Duration // How often jobs with an expired lease are requeued
 }
 
 // DefaultSchedulerConfig returns default scheduler configuration
 func DefaultSchedulerConfig() *SchedulerConfig {
 	return &SchedulerConfig{
-		TickInterval: 1 * time.Minute,
-		WorkerID:     fmt.Sprintf("worker-%s", uuid.New().String()[:8]),
-		Configs:      entity.DefaultSyncScheduleConfigs(),
+		TickInterval:      1 * time.Minute,
+		WorkerID:          fmt.Sprintf("worker-%s", uuid.New().String()[:8]),
+		Configs:           entity.DefaultSyncScheduleConfigs(),
+		Concurrency:       4,
+		PollInterval:      5 * time.Second,
+		LeaseDuration:     2 * time.Minute,
+		HeartbeatInterval: 30 * time.Second,
+		ReapInterval:      1 * time.Minute,
 	}
 }
 
@@ -74,13 +98,39 @@ func NewScheduler(
 		config = DefaultSchedulerConfig()
 	}
 
+	// Fill in unset queue settings without changing the caller's config
+	cfg := *config
+	defaults := DefaultSchedulerConfig()
+	if cfg.Concurrency <= 0 {
+		cfg.Concurrency = defaults.Concurrency
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = defaults.PollInterval
+	}
+	if cfg.LeaseDuration <= 0 {
+		cfg.LeaseDuration = defaults.LeaseDuration
+	}
+	if cfg.HeartbeatInterval <= 0 || cfg.HeartbeatInterval >= cfg.LeaseDuration {
+		cfg.HeartbeatInterval = cfg.LeaseDuration / 4
+	}
+	if cfg.ReapInterval <= 0 {
+		cfg.ReapInterval = defaults.ReapInterval
+	}
+
 	return &Scheduler{
-		syncStateRepo:   syncStateRepo,
-		syncJobRepo:     syncJobRepo,
-		connAccountRepo: connAccountRepo,
-		configs:         config.Configs,
-		tickInterval:    config.TickInterval,
-		workerID:        config.WorkerID,
+		syncStateRepo:     syncStateRepo,
+		syncJobRepo:       syncJobRepo,
+		connAccountRepo:   connAccountRepo,
+		configs:           cfg.Configs,
+		tickInterval:      cfg.TickInterval,
+		workerID:          cfg.WorkerID,
+		concurrency:       cfg.Concurrency,
+		pollInterval:      cfg.PollInterval,
+		leaseDuration:     cfg.LeaseDuration,
+		heartbeatInterval: cfg.HeartbeatInterval,
+		reapInterval:      cfg.ReapInterval,
+		wake:              make(chan struct{}, 1),
+		running:           make(map[uuid.UUID]context.CancelCauseFunc),
 	}
 }
 
@@ -119,9 +169,14 @@ func (s *Scheduler) Start(ctx context.Context) error {
 	s.wg.Add(1)
 	go s.runDailySyncChecker()
 
-	// Start job processor
+	// Start job workers and the reaper for jobs abandoned by crashed workers
+	for i := 1; i <= s.concurrency; i++ {
+		s.wg.Add(1)
+		go s.runWorker(fmt.Sprintf("%s-%d", s.workerID, i))
+	}
+
 	s.wg.Add(1)
-	go s.runJobProcessor()
+	go s.runReaper()
 
 	return nil
 }
@@ -255,7 +310,7 @@ func (s *Scheduler) triggerHourlySync(platform entity.Platform) {
 			SyncType:           entity.SyncTypeHourly,
 			SyncScope:          entity.SyncScopeMetrics,
 			Status:             entity.SyncStatusPending,
-			Priority:           1,
+			Priority:           entity.SyncJobPriorityHourly,
 			ScheduledAt:        time.Now(),
 			MaxRetries:         s.configs[platform].MaxRetries,
 			TriggeredBy:        "scheduler",
@@ -274,6 +329,31 @@ func (s *Scheduler) triggerHourlySync(platform entity.Platform) {
 
 		log.Printf("[Scheduler] Created hourly sync job %s for account %s",
 			job.ID, state.ConnectedAccountID)
+
+		// Marketplaces also sync orders updated since the last run
+		if platform == entity.PlatformShopee || platform == entity.PlatformLazada {
+			orderJob := &entity.SyncJob{
+				BaseEntity:         entity.NewBaseEntity(),
+				OrganizationID:     state.OrganizationID,
+				ConnectedAccountID: state.ConnectedAccountID,
+				Platform:           platform,
+				SyncType:           entity.SyncTypeIncremental,
+				SyncScope:          entity.SyncScopeOrders,
+				Status:             entity.SyncStatusPending,
+				Priority:           entity.SyncJobPriorityHourly,
+				ScheduledAt:        time.Now(),
+				MaxRetries:         s.configs[platform].MaxRetries,
+				TriggeredBy:        "scheduler",
+			}
+
+			if err := s.syncJobRepo.Create(ctx, orderJob); err != nil {
+				log.Printf("[Scheduler] Error creating order sync job: %v", err)
+				continue
+			}
+
+			log.Printf("[Scheduler] Created order sync job %s for account %s",
+				orderJob.ID, state.ConnectedAccountID)
+		}
 	}
 }
 
@@ -343,7 +423,7 @@ func (s *Scheduler) triggerDailySync(platform entity.Platform, config entity.Syn
 			SyncType:           entity.SyncTypeDaily,
 			SyncScope:          entity.SyncScopeAccount, // Full account sync
 			Status:             entity.SyncStatusPending,
-			Priority:           0, // Lower priority than hourly
+			Priority:           entity.SyncJobPriorityDaily,
 			ScheduledAt:        time.Now(),
 			MaxRetries:         config.MaxRetries,
 			TriggeredBy:        "scheduler",
@@ -366,67 +446,168 @@ func (s *Scheduler) triggerDailySync(platform entity.Platform, config entity.Syn
 }
 
 // ============================================================================
-// Job Processor
+// Job Queue
 // ============================================================================
 
-func (s *Scheduler) runJobProcessor() {
+// runWorker claims and runs jobs one at a time until the scheduler stops.
+// Between jobs it waits for the poll interval or a newly queued job.
+func (s *Scheduler) runWorker(workerID string) {
 	defer s.wg.Done()
 
-	log.Printf("[Scheduler] Starting job processor")
+	log.Printf("[Scheduler] Starting job worker %s", workerID)
 
-	ticker := time.NewTicker(10 * time.Second) // Check for jobs every 10 seconds
+	ticker := time.NewTicker(s.pollInterval)
 	defer ticker.Stop()
 
 	for {
+		// Drain the queue before waiting again
+		for s.processNextJob(workerID) {
+			if s.ctx.Err() != nil {
+				return
+			}
+		}
+
 		select {
 		case <-s.ctx.Done():
 			return
 		case <-ticker.C:
-			s.processNextJob()
+		case <-s.wake:
 		}
 	}
 }
 
-func (s *Scheduler) processNextJob() {
+// processNextJob claims the next job for the worker and runs it, reporting
+// whether there was a job to run
+func (s *Scheduler) processNextJob(workerID string) bool {
 	s.mu.RLock()
 	executor := s.jobExecutor
 	s.mu.RUnlock()
 
 	if executor == nil {
-		return // No executor set
+		return false // No executor set
 	}
 
 	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
-	defer cancel()
-
-	// Get pending jobs
-	jobs, err := s.syncJobRepo.ListPending(ctx, 1)
+	job, err := s.syncJobRepo.ClaimNext(ctx, workerID, time.Now().Add(s.leaseDuration))
+	cancel()
 	if err != nil {
-		log.Printf("[Scheduler] Error listing pending jobs: %v", err)
-		return
+		log.Printf("[Scheduler] Worker %s failed to claim a job: %v", workerID, err)
+		return false
 	}
-
-	if len(jobs) == 0 {
-		return
+	if job == nil {
+		return false
 	}
 
-	job := jobs[0]
+	log.Printf("[Scheduler] Worker %s processing job %s for %s (priority %d)", workerID, job.ID, job.Platform, job.Priority)
 
-	// Try to claim the job
-	if err := s.syncJobRepo.ClaimJob(ctx, job.ID, s.workerID); err != nil {
-		log.Printf("[Scheduler] Failed to claim job %s: %v", job.ID, err)
-		return
-	}
+	// The job runs until it finishes, is stopped by a user or the worker loses
+	// its lease; the cause of its cancelled context tells which
+	jobCtx, cancelJob := context.WithCancelCause(s.ctx)
+	s.mu.Lock()
+	s.running[job.ID] = cancelJob
+	s.mu.Unlock()
 
-	log.Printf("[Scheduler] Processing job %s for %s", job.ID, job.Platform)
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		if cause := s.heartbeat(jobCtx, workerID, job.ID); cause != nil {
+			cancelJob(cause)
+		}
+	}()
 
-	// Execute the job
-	if err := executor.ExecuteJob(s.ctx, &job); err != nil {
+	err = executor.ExecuteJob(jobCtx, job)
+	cancelJob(nil)
+	<-heartbeatDone
+
+	s.mu.Lock()
+	delete(s.running, job.ID)
+	s.mu.Unlock()
+
+	cause := context.Cause(jobCtx)
+	switch {
+	case errors.Is(cause, errLeaseLost):
+		// The reaper gave the job to another worker, which owns its outcome now
+		log.Printf("[Scheduler] Worker %s lost the lease on job %s", workerID, job.ID)
+	case jobStopCause(jobCtx) != nil:
+		log.Printf("[Scheduler] Job %s stopped: %v", job.ID, cause)
+	case err != nil:
 		log.Printf("[Scheduler] Job %s failed: %v", job.ID, err)
-		s.handleJobFailure(ctx, &job, err)
-	} else {
+		ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+		s.handleJobFailure(ctx, job, err)
+		cancel()
+	default:
 		log.Printf("[Scheduler] Job %s completed successfully", job.ID)
 	}
+
+	return true
+}
+
+// heartbeat renews the lease of a running job until ctx is done. Once the
+// lease is no longer held it returns why: the job was cancelled or paused, or
+// its lease was lost. Failing to reach the repository does not count: the
+// lease stays valid until it expires, and the next beat tries again.
+func (s *Scheduler) heartbeat(ctx context.Context, workerID string, jobID uuid.UUID) error {
+	ticker := time.NewTicker(s.heartbeatInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			held, err := s.syncJobRepo.ExtendLease(ctx, jobID, workerID, time.Now().Add(s.leaseDuration))
+			if err != nil {
+				if ctx.Err() == nil {
+					log.Printf("[Scheduler] Failed to renew lease on job %s: %v", jobID, err)
+				}
+				continue
+			}
+			if !held {
+				return s.releasedLeaseCause(ctx, jobID)
+			}
+		}
+	}
+}
+
+// runReaper periodically requeues jobs whose worker stopped renewing its lease
+func (s *Scheduler) runReaper() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(s.reapInterval)
+	defer ticker.Stop()
+
+	for {
+		s.reapExpiredLeases()
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *Scheduler) reapExpiredLeases() {
+	ctx, cancel := context.WithTimeout(s.ctx, 30*time.Second)
+	defer cancel()
+
+	released, err := s.syncJobRepo.RequeueExpired(ctx, time.Now())
+	if err != nil {
+		log.Printf("[Scheduler] Error requeueing jobs with expired leases: %v", err)
+		return
+	}
+	if released > 0 {
+		log.Printf("[Scheduler] Released %d jobs with expired leases", released)
+		s.notifyWorkers()
+	}
+}
+
+// notifyWorkers wakes an idle worker to pick up a newly queued job
+func (s *Scheduler) notifyWorkers() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
 }
 
 func (s *Scheduler) handleJobFailure(ctx context.Context, job *entity.SyncJob, err error) {
@@ -494,7 +675,7 @@ func (s *Scheduler) TriggerManualSync(ctx context.Context, req ManualSyncRequest
 		SyncType:           entity.SyncTypeManual,
 		SyncScope:          req.Scope,
 		Status:             entity.SyncStatusPending,
-		Priority:           10, // High priority for manual syncs
+		Priority:           entity.SyncJobPriorityManual,
 		ScheduledAt:        time.Now(),
 		MaxRetries:         1, // Less retries for manual syncs
 		TriggeredBy:        fmt.Sprintf("user:%s", req.UserID),
@@ -528,6 +709,8 @@ func (s *Scheduler) TriggerManualSync(ctx context.Context, req ManualSyncRequest
 	log.Printf("[Scheduler] Created manual sync job %s for account %s by user %s",
 		job.ID, req.ConnectedAccountID, req.UserID)
 
+	s.notifyWorkers()
+
 	return job, nil
 }
 
@@ -0,0 +1,475 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/google/uuid"
+)
+
+// queueJobRepo is an in-memory job queue with the claiming and lease semantics
+// of the sync job repository
+type queueJobRepo struct {
+	repository.SyncJobRepository
+
+	mu      sync.Mutex
+	jobs    []*entity.SyncJob
+	retries int
+}
+
+func (r *queueJobRepo) add(priority int, status entity.SyncStatus) *entity.SyncJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job := &entity.SyncJob{
+		BaseEntity:  entity.NewBaseEntity(),
+		Platform:    entity.PlatformMeta,
+		Status:      status,
+		Priority:    priority,
+		ScheduledAt: time.Now().Add(time.Duration(len(r.jobs)) * time.Millisecond),
+		MaxRetries:  3,
+	}
+	r.jobs = append(r.jobs, job)
+	return job
+}
+
+func (r *queueJobRepo) Create(ctx context.Context, job *entity.SyncJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs = append(r.jobs, job)
+	return nil
+}
+
+func (r *queueJobRepo) get(id uuid.UUID) entity.SyncJob {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, job := range r.jobs {
+		if job.ID == id {
+			return *job
+		}
+	}
+	return entity.SyncJob{}
+}
+
+func (r *queueJobRepo) ClaimNext(ctx context.Context, workerID string, leaseUntil time.Time) (*entity.SyncJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var next *entity.SyncJob
+	for _, job := range r.jobs {
+		if job.Status != entity.SyncStatusPending {
+			continue
+		}
+		if next == nil || job.Priority > next.Priority || (job.Priority == next.Priority && job.ScheduledAt.Before(next.ScheduledAt)) {
+			next = job
+		}
+	}
+	if next == nil {
+		return nil, nil
+	}
+	next.Status = entity.SyncStatusRunning
+	next.WorkerID = workerID
+	next.LeaseExpiresAt = &leaseUntil
+	claimed := *next
+	return &claimed, nil
+}
+
+func (r *queueJobRepo) ExtendLease(ctx context.Context, id uuid.UUID, workerID string, leaseUntil time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, job := range r.jobs {
+		if job.ID == id && job.Status == entity.SyncStatusRunning && job.WorkerID == workerID {
+			job.LeaseExpiresAt = &leaseUntil
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *queueJobRepo) RequeueExpired(ctx context.Context, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var released int64
+	for _, job := range r.jobs {
+		if job.Status != entity.SyncStatusRunning || job.LeaseExpiresAt == nil || !job.LeaseExpiresAt.Before(now) {
+			continue
+		}
+		job.WorkerID = ""
+		job.LeaseExpiresAt = nil
+		if job.CanRetry() {
+			job.RetryCount++
+			job.Status = entity.SyncStatusPending
+		} else {
+			job.Status = entity.SyncStatusFailed
+		}
+		released++
+	}
+	return released, nil
+}
+
+func (r *queueJobRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.SyncJob, error) {
+	job := r.get(id)
+	if job.ID == uuid.Nil {
+		return nil, errors.New("record not found")
+	}
+	return &job, nil
+}
+
+func (r *queueJobRepo) SetStatusIf(ctx context.Context, id uuid.UUID, status entity.SyncStatus, from ...entity.SyncStatus) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, job := range r.jobs {
+		if job.ID != id || !slices.Contains(from, job.Status) {
+			continue
+		}
+		job.Status = status
+		job.WorkerID = ""
+		job.LeaseExpiresAt = nil
+		return true, nil
+	}
+	return false, nil
+}
+
+func (r *queueJobRepo) setStatus(id uuid.UUID, status entity.SyncStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, job := range r.jobs {
+		if job.ID == id {
+			job.Status = status
+		}
+	}
+}
+
+func (r *queueJobRepo) SaveCheckpoint(ctx context.Context, id uuid.UUID, checkpoint entity.JSONMap, recordsProcessed, recordsFailed int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, job := range r.jobs {
+		if job.ID == id {
+			job.Checkpoint = entity.JSONMap{}
+			for k, v := range checkpoint {
+				job.Checkpoint[k] = v
+			}
+			job.RecordsProcessed = recordsProcessed
+			job.RecordsFailed = recordsFailed
+		}
+	}
+	return nil
+}
+
+func (r *queueJobRepo) Update(ctx context.Context, job *entity.SyncJob) error {
+	return nil
+}
+
+func (r *queueJobRepo) MarkCompleted(ctx context.Context, id uuid.UUID, recordsProcessed, recordsFailed int) error {
+	r.setStatus(id, entity.SyncStatusCompleted)
+	return nil
+}
+
+func (r *queueJobRepo) UpdateProgress(ctx context.Context, id uuid.UUID, percent int, message string) error {
+	return nil
+}
+
+func (r *queueJobRepo) ScheduleRetry(ctx context.Context, id uuid.UUID, retryAfter time.Time, lastError string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.retries++
+	return nil
+}
+
+type executorFunc func(ctx context.Context, job *entity.SyncJob) error
+
+func (f executorFunc) ExecuteJob(ctx context.Context, job *entity.SyncJob) error {
+	return f(ctx, job)
+}
+
+func startQueueTestScheduler(t *testing.T, repo *queueJobRepo, concurrency int, executor JobExecutor) *Scheduler {
+	t.Helper()
+	s := NewScheduler(nil, repo, nil, &SchedulerConfig{
+		TickInterval:      time.Hour,
+		WorkerID:          "test",
+		Configs:           map[entity.Platform]entity.SyncScheduleConfig{},
+		Concurrency:       concurrency,
+		PollInterval:      5 * time.Millisecond,
+		LeaseDuration:     50 * time.Millisecond,
+		HeartbeatInterval: 10 * time.Millisecond,
+		ReapInterval:      10 * time.Millisecond,
+	})
+	s.SetJobExecutor(executor)
+	if err := s.Start(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = s.Stop() })
+	return s
+}
+
+func waitFor(t *testing.T, what string, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestScheduler_ClaimsByPriority(t *testing.T) {
+	repo := &queueJobRepo{}
+	daily := repo.add(entity.SyncJobPriorityDaily, entity.SyncStatusPending)
+	hourly := repo.add(entity.SyncJobPriorityHourly, entity.SyncStatusPending)
+	manual := repo.add(entity.SyncJobPriorityManual, entity.SyncStatusPending)
+	webhook := repo.add(entity.SyncJobPriorityWebhook, entity.SyncStatusPending)
+
+	var mu sync.Mutex
+	var order []uuid.UUID
+	startQueueTestScheduler(t, repo, 1, executorFunc(func(ctx context.Context, job *entity.SyncJob) error {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, job.ID)
+		return nil
+	}))
+
+	waitFor(t, "all jobs to run", func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(order) == 4
+	})
+
+	want := []uuid.UUID{manual.ID, webhook.ID, hourly.ID, daily.ID}
+	for i := range want {
+		if order[i] != want[i] {
+			t.Fatalf("job %d ran with priority %d, want %d", i, repo.get(order[i]).Priority, repo.get(want[i]).Priority)
+		}
+	}
+}
+
+func TestScheduler_RunsJobsConcurrently(t *testing.T) {
+	repo := &queueJobRepo{}
+	for i := 0; i < 3; i++ {
+		repo.add(entity.SyncJobPriorityHourly, entity.SyncStatusPending)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	allStarted := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(allStarted)
+	}()
+
+	startQueueTestScheduler(t, repo, 3, executorFunc(func(ctx context.Context, job *entity.SyncJob) error {
+		wg.Done()
+		select {
+		case <-allStarted:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}))
+
+	select {
+	case <-allStarted:
+	case <-time.After(2 * time.Second):
+		t.Fatal("jobs did not run at the same time")
+	}
+}
+
+func TestScheduler_HeartbeatKeepsLease(t *testing.T) {
+	repo := &queueJobRepo{}
+	job := repo.add(entity.SyncJobPriorityManual, entity.SyncStatusPending)
+
+	runs := make(chan struct{}, 2)
+	done := make(chan struct{})
+	startQueueTestScheduler(t, repo, 2, executorFunc(func(ctx context.Context, j *entity.SyncJob) error {
+		runs <- struct{}{}
+		// Outlive the lease several times over
+		select {
+		case <-time.After(200 * time.Millisecond):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		close(done)
+		return nil
+	}))
+
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("job did not finish")
+	}
+	if len(runs) != 1 {
+		t.Errorf("job ran %d times, want once", len(runs))
+	}
+	if got := repo.get(job.ID); got.RetryCount != 0 {
+		t.Errorf("job requeued %d times while its worker was alive", got.RetryCount)
+	}
+}
+
+func TestScheduler_ReaperRequeuesExpiredLeases(t *testing.T) {
+	repo := &queueJobRepo{}
+	abandoned := repo.add(entity.SyncJobPriorityHourly, entity.SyncStatusRunning)
+	expired := time.Now().Add(-time.Minute)
+	abandoned.WorkerID = "crashed-1"
+	abandoned.LeaseExpiresAt = &expired
+
+	ran := make(chan *entity.SyncJob, 1)
+	startQueueTestScheduler(t, repo, 1, executorFunc(func(ctx context.Context, job *entity.SyncJob) error {
+		ran <- job
+		return nil
+	}))
+
+	select {
+	case job := <-ran:
+		if job.ID != abandoned.ID || job.RetryCount != 1 || job.WorkerID != "test-1" {
+			t.Errorf("requeued job = %+v", job)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("abandoned job was not requeued")
+	}
+}
+
+func TestScheduler_LostLeaseCancelsJob(t *testing.T) {
+	repo := &queueJobRepo{}
+	job := repo.add(entity.SyncJobPriorityManual, entity.SyncStatusPending)
+
+	cancelled := make(chan struct{})
+	var once sync.Once
+	startQueueTestScheduler(t, repo, 1, executorFunc(func(ctx context.Context, j *entity.SyncJob) error {
+		first := false
+		once.Do(func() {
+			// Another worker takes over, e.g. after a long pause of this one
+			first = true
+			repo.mu.Lock()
+			job.WorkerID = "other-1"
+			repo.mu.Unlock()
+		})
+		if !first {
+			return nil
+		}
+		<-ctx.Done()
+		close(cancelled)
+		return ctx.Err()
+	}))
+
+	select {
+	case <-cancelled:
+	case <-time.After(2 * time.Second):
+		t.Fatal("job kept running after its lease was lost")
+	}
+
+	// The new holder owns the outcome, so the failed run schedules no retry
+	time.Sleep(20 * time.Millisecond)
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if repo.retries != 0 {
+		t.Errorf("scheduled %d retries for a job whose lease was lost", repo.retries)
+	}
+}
+
+func TestScheduler_PauseAndResumeRunningJob(t *testing.T) {
+	repo := &queueJobRepo{}
+	job := repo.add(entity.SyncJobPriorityDaily, entity.SyncStatusPending)
+
+	started := make(chan struct{}, 2)
+	causes := make(chan error, 2)
+	runs := 0
+	s := startQueueTestScheduler(t, repo, 1, executorFunc(func(ctx context.Context, j *entity.SyncJob) error {
+		started <- struct{}{}
+		if runs++; runs > 1 {
+			// Resumed run
+			causes <- nil
+			return nil
+		}
+		<-ctx.Done()
+		causes <- context.Cause(ctx)
+		return ctx.Err()
+	}))
+
+	<-started
+	paused, err := s.PauseJob(context.Background(), job.ID)
+	if err != nil {
+		t.Fatalf("PauseJob() error = %v", err)
+	}
+	if paused.Status != entity.SyncStatusPaused {
+		t.Errorf("status = %s, want paused", paused.Status)
+	}
+	if cause := <-causes; !errors.Is(cause, ErrJobPaused) {
+		t.Fatalf("job context cause = %v, want ErrJobPaused", cause)
+	}
+
+	// A paused job stays put until resumed
+	time.Sleep(30 * time.Millisecond)
+	if len(started) != 0 {
+		t.Fatal("paused job was claimed again")
+	}
+	if _, err := s.PauseJob(context.Background(), job.ID); !errors.Is(err, ErrInvalidJobTransition) {
+		t.Errorf("PauseJob() of a paused job error = %v, want ErrInvalidJobTransition", err)
+	}
+
+	if _, err := s.ResumeJob(context.Background(), job.ID); err != nil {
+		t.Fatalf("ResumeJob() error = %v", err)
+	}
+	select {
+	case <-causes:
+	case <-time.After(2 * time.Second):
+		t.Fatal("resumed job did not run")
+	}
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	if repo.retries != 0 {
+		t.Errorf("scheduled %d retries for a paused job", repo.retries)
+	}
+}
+
+func TestScheduler_CancelSeenAtHeartbeat(t *testing.T) {
+	repo := &queueJobRepo{}
+	job := repo.add(entity.SyncJobPriorityManual, entity.SyncStatusPending)
+
+	started := make(chan struct{})
+	causes := make(chan error, 1)
+	startQueueTestScheduler(t, repo, 1, executorFunc(func(ctx context.Context, j *entity.SyncJob) error {
+		close(started)
+		<-ctx.Done()
+		causes <- context.Cause(ctx)
+		return ctx.Err()
+	}))
+
+	// Cancelled through another process, which cannot reach the job's context
+	<-started
+	repo.setStatus(job.ID, entity.SyncStatusCancelled)
+
+	select {
+	case cause := <-causes:
+		if !errors.Is(cause, ErrJobCancelled) {
+			t.Errorf("job context cause = %v, want ErrJobCancelled", cause)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("cancelled job kept running")
+	}
+}
+
+func TestScheduler_CancelQueuedJob(t *testing.T) {
+	repo := &queueJobRepo{}
+	job := repo.add(entity.SyncJobPriorityDaily, entity.SyncStatusPending)
+	s := NewScheduler(nil, repo, nil, nil)
+	ctx := context.Background()
+
+	cancelled, err := s.CancelJob(ctx, job.ID)
+	if err != nil {
+		t.Fatalf("CancelJob() error = %v", err)
+	}
+	if cancelled.Status != entity.SyncStatusCancelled {
+		t.Errorf("status = %s, want cancelled", cancelled.Status)
+	}
+	if _, err := s.CancelJob(ctx, job.ID); !errors.Is(err, ErrInvalidJobTransition) {
+		t.Errorf("CancelJob() of a cancelled job error = %v, want ErrInvalidJobTransition", err)
+	}
+	if _, err := s.ResumeJob(ctx, job.ID); !errors.Is(err, ErrInvalidJobTransition) {
+		t.Errorf("ResumeJob() of a cancelled job error = %v, want ErrInvalidJobTransition", err)
+	}
+}
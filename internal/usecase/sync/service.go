@@ -10,12 +10,20 @@ import (
 	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
 	domainService "github.com/ads-aggregator/ads-aggregator/internal/domain/service"
 	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/ads-aggregator/ads-aggregator/pkg/ratelimit"
+	"github.com/ads-aggregator/ads-aggregator/pkg/retry"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 )
 
-// Service handles data synchronization from ad platforms
+// Service handles data synchronization from ad platforms. It syncs accounts of
+// every platform through their connector, with a worker pool for batches, a
+// rate limiter per account and retries for transient API errors. A failed call
+// is recorded on the result and the sync moves on, so an account can partly
+// succeed. It serves syncs run on demand, and JobRunner runs queued sync jobs
+// through it, adding checkpoints so they resume where they stopped.
 type Service struct {
+	config            *ServiceConfig
 	connectedAccRepo  repository.ConnectedAccountRepository
 	adAccountRepo     repository.AdAccountRepository
 	campaignRepo      repository.CampaignRepository
@@ -23,18 +31,91 @@ type Service struct {
 	adRepo            repository.AdRepository
 	metricsRepo       repository.MetricsRepository
 	connectorRegistry ConnectorRegistry
+	rateLimiter       *ratelimit.MultiLimiter
+	retryer           *retry.Retryer
+	metricsListeners  []MetricsSyncListener
 	logger            zerolog.Logger
 	mu                sync.Mutex
 	syncing           map[uuid.UUID]bool // Track accounts being synced
 }
 
+// Ensure Service implements the DataSyncer interface
+var _ domainService.DataSyncer = (*Service)(nil)
+
 // ConnectorRegistry provides access to platform connectors
 type ConnectorRegistry interface {
 	Get(platform entity.Platform) (domainService.PlatformConnector, bool)
 }
 
+// MetricsSyncListener is notified after daily campaign metrics have been synced,
+// e.g. to look for anomalies in the fresh data
+type MetricsSyncListener interface {
+	MetricsSynced(ctx context.Context, orgID uuid.UUID, campaignIDs []uuid.UUID)
+}
+
+// ServiceConfig holds configuration for the sync service
+type ServiceConfig struct {
+	// MaxConcurrency is the maximum number of accounts a batch syncs at once
+	MaxConcurrency int
+	// SyncTimeout is the timeout for syncing a single account
+	SyncTimeout time.Duration
+	// BatchTimeout is the timeout for an entire batch sync
+	BatchTimeout time.Duration
+	// RetryConfig for failed API calls
+	RetryConfig *retry.Config
+	// AccountRateLimits caps the API calls made for a single account, per platform
+	AccountRateLimits map[entity.Platform]AccountRateLimit
+}
+
+// AccountRateLimit is the number of API calls allowed for an account per window
+type AccountRateLimit struct {
+	Calls  int
+	Window time.Duration
+}
+
+// defaultAccountRateLimit applies to platforms without a configured limit
+var defaultAccountRateLimit = AccountRateLimit{Calls: 60, Window: time.Minute}
+
+// DefaultServiceConfig returns production-ready defaults
+func DefaultServiceConfig() *ServiceConfig {
+	return &ServiceConfig{
+		MaxConcurrency: 5,
+		SyncTimeout:    10 * time.Minute,
+		BatchTimeout:   60 * time.Minute,
+		RetryConfig:    retry.DefaultConfig(),
+		AccountRateLimits: map[entity.Platform]AccountRateLimit{
+			entity.PlatformMeta:   {Calls: 200, Window: time.Hour}, // Meta: 200/hour per ad account
+			entity.PlatformTikTok: {Calls: 10, Window: time.Minute},
+			entity.PlatformShopee: {Calls: 60, Window: time.Minute},
+			entity.PlatformGoogle: {Calls: 100, Window: time.Minute},
+			entity.PlatformLazada: {Calls: 50, Window: time.Minute},
+		},
+	}
+}
+
+// SyncOptions configures what to sync
+type SyncOptions struct {
+	DateRange     entity.DateRange
+	SyncCampaigns bool
+	SyncAdSets    bool
+	SyncAds       bool
+	SyncMetrics   bool
+}
+
+// DefaultSyncOptions returns default sync options
+func DefaultSyncOptions() *SyncOptions {
+	return &SyncOptions{
+		DateRange:     entity.Last7Days(),
+		SyncCampaigns: true,
+		SyncAdSets:    true,
+		SyncAds:       true,
+		SyncMetrics:   true,
+	}
+}
+
 // NewService creates a new sync service
 func NewService(
+	config *ServiceConfig,
 	connectedAccRepo repository.ConnectedAccountRepository,
 	adAccountRepo repository.AdAccountRepository,
 	campaignRepo repository.CampaignRepository,
@@ -44,7 +125,25 @@ func NewService(
 	connectorRegistry ConnectorRegistry,
 	logger zerolog.Logger,
 ) *Service {
+	defaults := DefaultServiceConfig()
+	if config == nil {
+		config = defaults
+	}
+	if config.MaxConcurrency <= 0 {
+		config.MaxConcurrency = defaults.MaxConcurrency
+	}
+	if config.SyncTimeout <= 0 {
+		config.SyncTimeout = defaults.SyncTimeout
+	}
+	if config.BatchTimeout <= 0 {
+		config.BatchTimeout = defaults.BatchTimeout
+	}
+	if config.AccountRateLimits == nil {
+		config.AccountRateLimits = defaults.AccountRateLimits
+	}
+
 	return &Service{
+		config:            config,
 		connectedAccRepo:  connectedAccRepo,
 		adAccountRepo:     adAccountRepo,
 		campaignRepo:      campaignRepo,
@@ -52,13 +151,30 @@ func NewService(
 		adRepo:            adRepo,
 		metricsRepo:       metricsRepo,
 		connectorRegistry: connectorRegistry,
+		rateLimiter:       ratelimit.NewMultiLimiter(),
+		retryer:           retry.NewRetryer(config.RetryConfig, logger),
 		logger:            logger.With().Str("service", "sync").Logger(),
 		syncing:           make(map[uuid.UUID]bool),
 	}
 }
 
+// AddMetricsListener adds a listener notified after metrics are synced
+func (s *Service) AddMetricsListener(listener MetricsSyncListener) {
+	s.metricsListeners = append(s.metricsListeners, listener)
+}
+
 // SyncAccount syncs all data for a single connected account
 func (s *Service) SyncAccount(ctx context.Context, accountID uuid.UUID) (*domainService.SyncResult, error) {
+	return s.SyncAccountWithOptions(ctx, accountID, DefaultSyncOptions())
+}
+
+// SyncAccountWithOptions syncs the selected data for a single connected account.
+// Errors of individual API calls are returned on the result, not as the error.
+func (s *Service) SyncAccountWithOptions(ctx context.Context, accountID uuid.UUID, options *SyncOptions) (*domainService.SyncResult, error) {
+	if options == nil {
+		options = DefaultSyncOptions()
+	}
+
 	// Check if already syncing
 	if s.isSyncing(accountID) {
 		return nil, errors.ErrConflict("Account is already being synced")
@@ -66,87 +182,115 @@ func (s *Service) SyncAccount(ctx context.Context, accountID uuid.UUID) (*domain
 	s.setSyncing(accountID, true)
 	defer s.setSyncing(accountID, false)
 
+	account, connector, err := s.activeAccount(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.syncAccount(ctx, account, connector, options), nil
+}
+
+// activeAccount returns a connected account that can be synced, and its connector
+func (s *Service) activeAccount(ctx context.Context, accountID uuid.UUID) (*entity.ConnectedAccount, domainService.PlatformConnector, error) {
 	// Get connected account
 	account, err := s.connectedAccRepo.GetByID(ctx, accountID)
 	if err != nil {
-		return nil, errors.ErrNotFound("Connected account")
+		return nil, nil, errors.ErrNotFound("Connected account")
 	}
 
 	// Check account status
 	if account.Status != entity.AccountStatusActive {
-		return nil, errors.ErrBadRequest("Account is not active")
+		return nil, nil, errors.ErrBadRequest("Account is not active")
+	}
+
+	if account.IsTokenExpired() {
+		return nil, nil, errors.NewTokenExpiredError(account.Platform.String(), "access", *account.TokenExpiresAt)
 	}
 
 	// Get connector
 	connector, ok := s.connectorRegistry.Get(account.Platform)
 	if !ok {
-		return nil, errors.ErrBadRequest(fmt.Sprintf("Unsupported platform: %s", account.Platform))
+		return nil, nil, errors.ErrBadRequest(fmt.Sprintf("Unsupported platform: %s", account.Platform))
 	}
 
+	return account, connector, nil
+}
+
+// syncAccount runs the sync of one account within its timeout and rate limit
+func (s *Service) syncAccount(ctx context.Context, account *entity.ConnectedAccount, connector domainService.PlatformConnector, options *SyncOptions) *domainService.SyncResult {
+	ctx, cancel := context.WithTimeout(ctx, s.config.SyncTimeout)
+	defer cancel()
+
 	result := &domainService.SyncResult{
 		Platform:  account.Platform,
-		AccountID: accountID,
+		AccountID: account.ID,
 		StartedAt: time.Now().Unix(),
 		Errors:    make([]error, 0),
 	}
+	limiter := s.accountLimiter(account)
 
 	s.logger.Info().
-		Str("account_id", accountID.String()).
+		Str("account_id", account.ID.String()).
 		Str("platform", string(account.Platform)).
+		Bool("sync_campaigns", options.SyncCampaigns).
+		Bool("sync_adsets", options.SyncAdSets).
+		Bool("sync_ads", options.SyncAds).
+		Bool("sync_metrics", options.SyncMetrics).
 		Msg("Starting account sync")
 
 	// Sync ad accounts
-	adAccountsSynced, err := s.syncAdAccounts(ctx, account, connector)
-	if err != nil {
-		s.logger.Error().Err(err).Msg("Failed to sync ad accounts")
+	if err := s.syncAdAccounts(ctx, account, connector, limiter); err != nil {
+		s.logger.Error().Err(err).Str("account_id", account.ID.String()).Msg("Failed to sync ad accounts")
 		result.Errors = append(result.Errors, err)
 	}
 
 	// Get all ad accounts for this connected account
-	adAccounts, err := s.adAccountRepo.ListByConnectedAccount(ctx, accountID)
+	adAccounts, err := s.adAccountRepo.ListByConnectedAccount(ctx, account.ID)
 	if err != nil {
 		s.logger.Error().Err(err).Msg("Failed to list ad accounts")
 		result.Errors = append(result.Errors, err)
 	}
 
-	// Sync campaigns for each ad account
+	// Sync campaigns, ad sets, ads and metrics for each ad account
+	var syncedCampaigns []uuid.UUID
 	for _, adAccount := range adAccounts {
-		campaignsSynced, adSetsSynced, adsSynced, syncErr := s.syncCampaignsForAccount(ctx, account, adAccount, connector)
-		result.CampaignsSynced += campaignsSynced
-		result.AdSetsSynced += adSetsSynced
-		result.AdsSynced += adsSynced
-		if syncErr != nil {
-			result.Errors = append(result.Errors, syncErr)
-		}
+		syncedCampaigns = append(syncedCampaigns, s.syncAdAccount(ctx, account, adAccount, connector, limiter, options, result)...)
 	}
 
-	result.CampaignsSynced += adAccountsSynced
+	s.notifyMetricsSynced(ctx, account.OrganizationID, syncedCampaigns)
+
 	result.CompletedAt = time.Now().Unix()
 
 	// Update last synced timestamp
-	if err := s.connectedAccRepo.UpdateLastSynced(ctx, accountID); err != nil {
+	if err := s.connectedAccRepo.UpdateLastSynced(ctx, account.ID); err != nil {
 		s.logger.Error().Err(err).Msg("Failed to update last synced timestamp")
 	}
 
 	s.logger.Info().
-		Str("account_id", accountID.String()).
+		Str("account_id", account.ID.String()).
+		Str("platform", string(account.Platform)).
 		Int("campaigns_synced", result.CampaignsSynced).
 		Int("ad_sets_synced", result.AdSetsSynced).
 		Int("ads_synced", result.AdsSynced).
+		Int("metrics_synced", result.MetricsSynced).
 		Int("errors", len(result.Errors)).
 		Msg("Account sync completed")
 
-	return result, nil
+	return result
 }
 
 // syncAdAccounts syncs ad accounts from the platform
-func (s *Service) syncAdAccounts(ctx context.Context, account *entity.ConnectedAccount, connector domainService.PlatformConnector) (int, error) {
-	platformAccounts, err := connector.GetAdAccounts(ctx, account.AccessToken)
+func (s *Service) syncAdAccounts(ctx context.Context, account *entity.ConnectedAccount, connector domainService.PlatformConnector, limiter *ratelimit.Limiter) error {
+	var platformAccounts []entity.PlatformAccount
+	err := s.call(ctx, limiter, "GetAdAccounts", func(ctx context.Context) error {
+		var err error
+		platformAccounts, err = connector.GetAdAccounts(ctx, account.AccessToken)
+		return err
+	})
 	if err != nil {
-		return 0, err
+		return err
 	}
 
-	synced := 0
 	for _, pa := range platformAccounts {
 		adAccount := &entity.AdAccount{
 			BaseEntity:            entity.NewBaseEntity(),
@@ -157,171 +301,268 @@ func (s *Service) syncAdAccounts(ctx context.Context, account *entity.ConnectedA
 			PlatformAdAccountName: pa.Name,
 			Currency:              pa.Currency,
 			Timezone:              pa.Timezone,
-			IsActive:              pa.Status == "active",
+			IsActive:              pa.Status == "active" || pa.Status == "ACTIVE",
 		}
 
 		if err := s.adAccountRepo.Upsert(ctx, adAccount); err != nil {
 			s.logger.Error().Err(err).Str("ad_account_id", pa.ID).Msg("Failed to upsert ad account")
-			continue
 		}
-		synced++
 	}
 
-	return synced, nil
+	return nil
 }
 
-// syncCampaignsForAccount syncs campaigns, ad sets, and ads for an ad account
-func (s *Service) syncCampaignsForAccount(
+// syncAdAccount syncs the campaigns, ad sets, ads and metrics of an ad account
+// into result, and returns the campaigns whose metrics were synced
+func (s *Service) syncAdAccount(
 	ctx context.Context,
 	account *entity.ConnectedAccount,
 	adAccount entity.AdAccount,
 	connector domainService.PlatformConnector,
-) (int, int, int, error) {
-	campaignsSynced := 0
-	adSetsSynced := 0
-	adsSynced := 0
-
-	// Get campaigns
-	campaigns, err := connector.GetCampaigns(ctx, account.AccessToken, adAccount.PlatformAdAccountID)
-	if err != nil {
-		return 0, 0, 0, err
-	}
-
-	for _, campaign := range campaigns {
-		campaign.AdAccountID = adAccount.ID
-		campaign.OrganizationID = account.OrganizationID
-
-		// Upsert campaign
-		if err := s.campaignRepo.Upsert(ctx, &campaign); err != nil {
-			s.logger.Error().Err(err).Str("campaign_id", campaign.PlatformCampaignID).Msg("Failed to upsert campaign")
-			continue
-		}
-		campaignsSynced++
-
-		// Sync ad sets for this campaign
-		adSets, err := connector.GetAdSets(ctx, account.AccessToken, campaign.PlatformCampaignID)
+	limiter *ratelimit.Limiter,
+	options *SyncOptions,
+	result *domainService.SyncResult,
+) []uuid.UUID {
+	var campaigns []entity.Campaign
+	if options.SyncCampaigns {
+		var platformCampaigns []entity.Campaign
+		err := s.call(ctx, limiter, "GetCampaigns", func(ctx context.Context) error {
+			var err error
+			platformCampaigns, err = connector.GetCampaigns(ctx, account.AccessToken, adAccount.PlatformAdAccountID)
+			return err
+		})
 		if err != nil {
-			s.logger.Error().Err(err).Str("campaign_id", campaign.PlatformCampaignID).Msg("Failed to get ad sets")
-			continue
+			s.recordError(result, err, "Failed to get campaigns")
+			return nil
 		}
 
-		for _, adSet := range adSets {
-			adSet.CampaignID = campaign.ID
-			adSet.OrganizationID = account.OrganizationID
+		now := time.Now()
+		for _, campaign := range platformCampaigns {
+			campaign.AdAccountID = adAccount.ID
+			campaign.OrganizationID = account.OrganizationID
+			campaign.LastSyncedAt = &now
 
-			if err := s.adSetRepo.Upsert(ctx, &adSet); err != nil {
-				s.logger.Error().Err(err).Str("ad_set_id", adSet.PlatformAdSetID).Msg("Failed to upsert ad set")
+			if err := s.campaignRepo.Upsert(ctx, &campaign); err != nil {
+				s.recordError(result, err, "Failed to upsert campaign")
 				continue
 			}
-			adSetsSynced++
+			result.CampaignsSynced++
+			campaigns = append(campaigns, campaign)
 
-			// Sync ads for this ad set
-			ads, err := connector.GetAds(ctx, account.AccessToken, adSet.PlatformAdSetID)
-			if err != nil {
-				s.logger.Error().Err(err).Str("ad_set_id", adSet.PlatformAdSetID).Msg("Failed to get ads")
-				continue
+			if options.SyncAdSets {
+				s.syncAdSets(ctx, account, campaign, connector, limiter, options, result)
 			}
+		}
 
-			for _, ad := range ads {
-				ad.AdSetID = adSet.ID
-				ad.CampaignID = campaign.ID
-				ad.OrganizationID = account.OrganizationID
-
-				if err := s.adRepo.Upsert(ctx, &ad); err != nil {
-					s.logger.Error().Err(err).Str("ad_id", ad.PlatformAdID).Msg("Failed to upsert ad")
-					continue
-				}
-				adsSynced++
-			}
+		s.markUnlistedCampaignsDeleted(ctx, adAccount, platformCampaigns, result)
+	} else if options.SyncMetrics {
+		stored, err := s.campaignRepo.ListByAdAccount(ctx, adAccount.ID)
+		if err != nil {
+			s.recordError(result, err, "Failed to list campaigns")
+			return nil
 		}
+		campaigns = stored
 	}
 
-	return campaignsSynced, adSetsSynced, adsSynced, nil
-}
-
-// SyncMetrics syncs metrics for an account within a date range
-func (s *Service) SyncMetrics(ctx context.Context, accountID uuid.UUID, dateRange entity.DateRange) (int, error) {
-	account, err := s.connectedAccRepo.GetByID(ctx, accountID)
-	if err != nil {
-		return 0, errors.ErrNotFound("Connected account")
+	if !options.SyncMetrics {
+		return nil
 	}
 
-	connector, ok := s.connectorRegistry.Get(account.Platform)
-	if !ok {
-		return 0, errors.ErrBadRequest(fmt.Sprintf("Unsupported platform: %s", account.Platform))
+	var syncedCampaigns []uuid.UUID
+	for _, campaign := range campaigns {
+		count, err := s.syncCampaignMetrics(ctx, account, campaign, connector, limiter, options.DateRange)
+		if err != nil {
+			s.recordError(result, err, "Failed to sync campaign metrics")
+			continue
+		}
+		result.MetricsSynced += count
+		if count > 0 {
+			syncedCampaigns = append(syncedCampaigns, campaign.ID)
+		}
 	}
 
-	metricsSynced := 0
+	return syncedCampaigns
+}
 
-	// Get campaigns for this account
-	campaigns, err := s.campaignRepo.ListByOrganization(ctx, account.OrganizationID, nil)
+// syncAdSets syncs the ad sets of a campaign, and their ads
+func (s *Service) syncAdSets(
+	ctx context.Context,
+	account *entity.ConnectedAccount,
+	campaign entity.Campaign,
+	connector domainService.PlatformConnector,
+	limiter *ratelimit.Limiter,
+	options *SyncOptions,
+	result *domainService.SyncResult,
+) {
+	var adSets []entity.AdSet
+	err := s.call(ctx, limiter, "GetAdSets", func(ctx context.Context) error {
+		var err error
+		adSets, err = connector.GetAdSets(ctx, account.AccessToken, campaign.PlatformCampaignID)
+		return err
+	})
 	if err != nil {
-		return 0, err
+		s.recordError(result, err, "Failed to get ad sets")
+		return
 	}
 
-	for _, campaign := range campaigns {
-		if campaign.Platform != account.Platform {
+	now := time.Now()
+	for _, adSet := range adSets {
+		adSet.CampaignID = campaign.ID
+		adSet.OrganizationID = account.OrganizationID
+		adSet.LastSyncedAt = &now
+
+		if err := s.adSetRepo.Upsert(ctx, &adSet); err != nil {
+			s.recordError(result, err, "Failed to upsert ad set")
 			continue
 		}
+		result.AdSetsSynced++
 
-		// Get insights for campaign
-		insights, err := connector.GetCampaignInsights(ctx, account.AccessToken, campaign.PlatformCampaignID, dateRange)
+		if !options.SyncAds {
+			continue
+		}
+
+		var ads []entity.Ad
+		err := s.call(ctx, limiter, "GetAds", func(ctx context.Context) error {
+			var err error
+			ads, err = connector.GetAds(ctx, account.AccessToken, adSet.PlatformAdSetID)
+			return err
+		})
 		if err != nil {
-			s.logger.Error().Err(err).Str("campaign_id", campaign.PlatformCampaignID).Msg("Failed to get campaign insights")
+			s.recordError(result, err, "Failed to get ads")
 			continue
 		}
 
-		for _, insight := range insights {
-			insight.CampaignID = campaign.ID
-			insight.OrganizationID = account.OrganizationID
+		for _, ad := range ads {
+			ad.AdSetID = adSet.ID
+			ad.CampaignID = campaign.ID
+			ad.OrganizationID = account.OrganizationID
+			ad.LastSyncedAt = &now
 
-			if err := s.metricsRepo.UpsertCampaignMetrics(ctx, &insight); err != nil {
-				s.logger.Error().Err(err).Str("campaign_id", campaign.PlatformCampaignID).Msg("Failed to upsert campaign metrics")
+			if err := s.adRepo.Upsert(ctx, &ad); err != nil {
+				s.recordError(result, err, "Failed to upsert ad")
 				continue
 			}
-			metricsSynced++
+			result.AdsSynced++
 		}
 	}
+}
 
-	return metricsSynced, nil
+// syncCampaignMetrics fetches and stores the daily metrics of a campaign
+func (s *Service) syncCampaignMetrics(
+	ctx context.Context,
+	account *entity.ConnectedAccount,
+	campaign entity.Campaign,
+	connector domainService.PlatformConnector,
+	limiter *ratelimit.Limiter,
+	dateRange entity.DateRange,
+) (int, error) {
+	var metrics []entity.CampaignMetricsDaily
+	err := s.call(ctx, limiter, "GetCampaignInsights", func(ctx context.Context) error {
+		var err error
+		metrics, err = connector.GetCampaignInsights(ctx, account.AccessToken, campaign.PlatformCampaignID, dateRange)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if len(metrics) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	for i := range metrics {
+		metrics[i].OrganizationID = account.OrganizationID
+		metrics[i].CampaignID = campaign.ID
+		metrics[i].Platform = account.Platform
+		metrics[i].LastSyncedAt = &now
+		metrics[i].CalculateDerivedMetrics()
+	}
+
+	if err := s.metricsRepo.BulkUpsertCampaignMetrics(ctx, metrics); err != nil {
+		return 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to bulk upsert campaign metrics", 500)
+	}
+
+	return len(metrics), nil
 }
 
-// BatchSync syncs multiple accounts
+// SyncCampaigns syncs only the campaigns of an account
+func (s *Service) SyncCampaigns(ctx context.Context, accountID uuid.UUID) (int, error) {
+	result, err := s.SyncAccountWithOptions(ctx, accountID, &SyncOptions{SyncCampaigns: true})
+	if err != nil {
+		return 0, err
+	}
+	if !result.IsSuccess() && !result.HasPartialSuccess() {
+		return 0, result.Errors[0]
+	}
+	return result.CampaignsSynced, nil
+}
+
+// SyncMetrics syncs metrics for an account within a date range
+func (s *Service) SyncMetrics(ctx context.Context, accountID uuid.UUID, dateRange entity.DateRange) (int, error) {
+	result, err := s.SyncAccountWithOptions(ctx, accountID, &SyncOptions{DateRange: dateRange, SyncMetrics: true})
+	if err != nil {
+		return 0, err
+	}
+	if !result.IsSuccess() && !result.HasPartialSuccess() {
+		return 0, result.Errors[0]
+	}
+	return result.MetricsSynced, nil
+}
+
+// BatchSync syncs multiple accounts with a pool of MaxConcurrency workers.
+// Without account IDs, it syncs every active account of the request's
+// organization and platforms.
 func (s *Service) BatchSync(ctx context.Context, request domainService.BatchSyncRequest) (*domainService.BatchSyncResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.config.BatchTimeout)
+	defer cancel()
+
 	result := &domainService.BatchSyncResult{
 		OrganizationID: request.OrganizationID,
 		Results:        make([]domainService.SyncResult, 0),
 		StartedAt:      time.Now().Unix(),
 	}
 
-	var wg sync.WaitGroup
-	resultsChan := make(chan domainService.SyncResult, len(request.AccountIDs))
-	semaphore := make(chan struct{}, 3) // Limit concurrent syncs
+	accountIDs := request.AccountIDs
+	if len(accountIDs) == 0 {
+		var err error
+		accountIDs, err = s.activeAccountIDs(ctx, request.OrganizationID, request.Platforms)
+		if err != nil {
+			return nil, err
+		}
+	}
+	options := syncOptionsFromRequest(request)
+
+	accountsChan := make(chan uuid.UUID)
+	resultsChan := make(chan domainService.SyncResult, len(accountIDs))
 
-	for _, accountID := range request.AccountIDs {
+	var wg sync.WaitGroup
+	for i := 0; i < s.config.MaxConcurrency; i++ {
 		wg.Add(1)
-		go func(accID uuid.UUID) {
+		go func() {
 			defer wg.Done()
-			semaphore <- struct{}{}        // Acquire
-			defer func() { <-semaphore }() // Release
-
-			syncResult, err := s.SyncAccount(ctx, accID)
-			if err != nil {
-				resultsChan <- domainService.SyncResult{
-					AccountID: accID,
-					Errors:    []error{err},
+			for accID := range accountsChan {
+				syncResult, err := s.SyncAccountWithOptions(ctx, accID, options)
+				if err != nil {
+					resultsChan <- domainService.SyncResult{
+						AccountID: accID,
+						Errors:    []error{err},
+					}
+					continue
 				}
-				return
+				resultsChan <- *syncResult
 			}
-			resultsChan <- *syncResult
-		}(accountID)
+		}()
+	}
+
+	for _, accountID := range accountIDs {
+		accountsChan <- accountID
 	}
+	close(accountsChan)
 
 	// Wait for all syncs to complete
-	go func() {
-		wg.Wait()
-		close(resultsChan)
-	}()
+	wg.Wait()
+	close(resultsChan)
 
 	// Collect results
 	for syncResult := range resultsChan {
@@ -337,24 +578,21 @@ func (s *Service) BatchSync(ctx context.Context, request domainService.BatchSync
 	}
 
 	result.CompletedAt = time.Now().Unix()
+
+	s.logger.Info().
+		Int("total", result.TotalAccounts).
+		Int("successful", result.SuccessCount).
+		Int("partial", result.PartialCount).
+		Int("failed", result.FailureCount).
+		Dur("duration", time.Duration(result.CompletedAt-result.StartedAt)*time.Second).
+		Msg("Batch sync completed")
+
 	return result, nil
 }
 
 // SyncAllActive syncs all active accounts
 func (s *Service) SyncAllActive(ctx context.Context) (*domainService.BatchSyncResult, error) {
-	// Get all active accounts
-	accounts, err := s.connectedAccRepo.ListActive(ctx)
-	if err != nil {
-		return nil, err
-	}
-
-	accountIDs := make([]uuid.UUID, len(accounts))
-	for i, acc := range accounts {
-		accountIDs[i] = acc.ID
-	}
-
 	return s.BatchSync(ctx, domainService.BatchSyncRequest{
-		AccountIDs:    accountIDs,
 		SyncCampaigns: true,
 		SyncAdSets:    true,
 		SyncAds:       true,
@@ -365,6 +603,96 @@ func (s *Service) SyncAllActive(ctx context.Context) (*domainService.BatchSyncRe
 
 // Helper methods
 
+// call makes an API call for an account within its rate limit, retrying
+// transient errors
+func (s *Service) call(ctx context.Context, limiter *ratelimit.Limiter, operation string, fn retry.Func) error {
+	retryResult := s.retryer.Execute(ctx, operation, func(ctx context.Context) error {
+		if err := limiter.Wait(ctx); err != nil {
+			return err
+		}
+		return fn(ctx)
+	})
+	if !retryResult.Success {
+		return retryResult.LastError
+	}
+	return nil
+}
+
+// accountLimiter returns the rate limiter shared by all syncs of an account
+func (s *Service) accountLimiter(account *entity.ConnectedAccount) *ratelimit.Limiter {
+	limit, ok := s.config.AccountRateLimits[account.Platform]
+	if !ok {
+		limit = defaultAccountRateLimit
+	}
+	key := fmt.Sprintf("%s_%s", account.Platform, account.PlatformAccountID)
+	return s.rateLimiter.GetOrCreate(key, limit.Calls, limit.Window)
+}
+
+// backfillLimiter returns the rate limiter shared by the backfills of an
+// account. Backfill calls wait for it before the account's own limiter, so
+// they never use up the budget of regular syncs.
+func (s *Service) backfillLimiter(account *entity.ConnectedAccount, callsPerMinute int) *ratelimit.Limiter {
+	key := fmt.Sprintf("%s_%s_backfill", account.Platform, account.PlatformAccountID)
+	return s.rateLimiter.GetOrCreate(key, callsPerMinute, time.Minute)
+}
+
+// storedCampaigns lists the stored campaigns of every ad account of a connected account
+func (s *Service) storedCampaigns(ctx context.Context, account *entity.ConnectedAccount) ([]entity.Campaign, error) {
+	adAccounts, err := s.adAccountRepo.ListByConnectedAccount(ctx, account.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list ad accounts: %w", err)
+	}
+
+	var campaigns []entity.Campaign
+	for _, adAccount := range adAccounts {
+		stored, err := s.campaignRepo.ListByAdAccount(ctx, adAccount.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list campaigns: %w", err)
+		}
+		campaigns = append(campaigns, stored...)
+	}
+	return campaigns, nil
+}
+
+// activeAccountIDs lists the active accounts of an organization and platforms,
+// where a zero organization and no platforms match all of them
+func (s *Service) activeAccountIDs(ctx context.Context, orgID uuid.UUID, platforms []entity.Platform) ([]uuid.UUID, error) {
+	accounts, err := s.connectedAccRepo.ListActive(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to list active accounts", 500)
+	}
+
+	var accountIDs []uuid.UUID
+	for _, acc := range accounts {
+		if orgID != uuid.Nil && acc.OrganizationID != orgID {
+			continue
+		}
+		if len(platforms) > 0 && !containsPlatform(platforms, acc.Platform) {
+			continue
+		}
+		accountIDs = append(accountIDs, acc.ID)
+	}
+	return accountIDs, nil
+}
+
+func (s *Service) recordError(result *domainService.SyncResult, err error, msg string) {
+	s.logger.Error().
+		Err(err).
+		Str("account_id", result.AccountID.String()).
+		Str("platform", string(result.Platform)).
+		Msg(msg)
+	result.Errors = append(result.Errors, err)
+}
+
+func (s *Service) notifyMetricsSynced(ctx context.Context, orgID uuid.UUID, campaignIDs []uuid.UUID) {
+	if len(campaignIDs) == 0 {
+		return
+	}
+	for _, listener := range s.metricsListeners {
+		listener.MetricsSynced(ctx, orgID, campaignIDs)
+	}
+}
+
 func (s *Service) isSyncing(accountID uuid.UUID) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -380,3 +708,36 @@ func (s *Service) setSyncing(accountID uuid.UUID, syncing bool) {
 		delete(s.syncing, accountID)
 	}
 }
+
+// syncOptionsFromRequest returns what a batch request asks to sync. A request
+// selecting nothing syncs everything.
+func syncOptionsFromRequest(request domainService.BatchSyncRequest) *SyncOptions {
+	if !request.SyncCampaigns && !request.SyncAdSets && !request.SyncAds && !request.SyncMetrics {
+		options := DefaultSyncOptions()
+		if !request.DateRange.StartDate.IsZero() {
+			options.DateRange = request.DateRange
+		}
+		return options
+	}
+
+	options := &SyncOptions{
+		DateRange:     request.DateRange,
+		SyncCampaigns: request.SyncCampaigns,
+		SyncAdSets:    request.SyncAdSets,
+		SyncAds:       request.SyncAds,
+		SyncMetrics:   request.SyncMetrics,
+	}
+	if options.DateRange.StartDate.IsZero() {
+		options.DateRange = entity.Last7Days()
+	}
+	return options
+}
+
+func containsPlatform(platforms []entity.Platform, platform entity.Platform) bool {
+	for _, p := range platforms {
+		if p == platform {
+			return true
+		}
+	}
+	return false
+}
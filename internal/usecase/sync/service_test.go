@@ -0,0 +1,374 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	stdsync "sync"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/service"
+	"github.com/ads-aggregator/ads-aggregator/pkg/retry"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ============================================================================
+// Mocks
+// ============================================================================
+
+type engineConnector struct {
+	service.PlatformConnector
+
+	mu           stdsync.Mutex
+	calls        int
+	inFlight     int
+	maxInFlight  int
+	failAccounts bool
+	failInsights bool
+}
+
+func (c *engineConnector) call() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls++
+}
+
+func (c *engineConnector) GetAdAccounts(ctx context.Context, accessToken string) ([]entity.PlatformAccount, error) {
+	c.mu.Lock()
+	c.calls++
+	c.inFlight++
+	if c.inFlight > c.maxInFlight {
+		c.maxInFlight = c.inFlight
+	}
+	c.mu.Unlock()
+
+	time.Sleep(10 * time.Millisecond)
+
+	c.mu.Lock()
+	c.inFlight--
+	c.mu.Unlock()
+
+	if c.failAccounts {
+		return nil, errors.New("service unavailable")
+	}
+	return []entity.PlatformAccount{{ID: "act_1", Name: "Account", Status: "ACTIVE"}}, nil
+}
+
+func (c *engineConnector) GetCampaigns(ctx context.Context, accessToken, adAccountID string) ([]entity.Campaign, error) {
+	c.call()
+	if c.failAccounts {
+		return nil, errors.New("service unavailable")
+	}
+	return []entity.Campaign{{PlatformCampaignID: "c1"}, {PlatformCampaignID: "c2"}}, nil
+}
+
+func (c *engineConnector) GetAdSets(ctx context.Context, accessToken, campaignID string) ([]entity.AdSet, error) {
+	c.call()
+	return []entity.AdSet{{PlatformAdSetID: campaignID + "_s1"}}, nil
+}
+
+func (c *engineConnector) GetAds(ctx context.Context, accessToken, adSetID string) ([]entity.Ad, error) {
+	c.call()
+	return []entity.Ad{{PlatformAdID: adSetID + "_a1"}}, nil
+}
+
+func (c *engineConnector) GetCampaignInsights(ctx context.Context, accessToken, campaignID string, dateRange entity.DateRange) ([]entity.CampaignMetricsDaily, error) {
+	c.call()
+	if c.failInsights {
+		return nil, errors.New("insights unavailable")
+	}
+	return []entity.CampaignMetricsDaily{{MetricDate: dateRange.StartDate}, {MetricDate: dateRange.EndDate}}, nil
+}
+
+type engineRegistry map[entity.Platform]service.PlatformConnector
+
+func (r engineRegistry) Get(platform entity.Platform) (service.PlatformConnector, bool) {
+	connector, ok := r[platform]
+	return connector, ok
+}
+
+type engineAccountRepo struct {
+	repository.ConnectedAccountRepository
+	accounts []entity.ConnectedAccount
+}
+
+func (r *engineAccountRepo) add(platform entity.Platform) uuid.UUID {
+	base := entity.NewBaseEntity()
+	account := entity.ConnectedAccount{
+		BaseEntity:        base,
+		OrganizationID:    uuid.MustParse("00000000-0000-0000-0000-0000000000aa"),
+		Platform:          platform,
+		PlatformAccountID: base.ID.String(),
+		AccessToken:       "token",
+		Status:            entity.AccountStatusActive,
+	}
+	r.accounts = append(r.accounts, account)
+	return account.ID
+}
+
+func (r *engineAccountRepo) GetByID(ctx context.Context, id uuid.UUID) (*entity.ConnectedAccount, error) {
+	for i := range r.accounts {
+		if r.accounts[i].ID == id {
+			account := r.accounts[i]
+			return &account, nil
+		}
+	}
+	return nil, errors.New("record not found")
+}
+
+func (r *engineAccountRepo) ListActive(ctx context.Context) ([]entity.ConnectedAccount, error) {
+	return r.accounts, nil
+}
+
+func (r *engineAccountRepo) UpdateLastSynced(ctx context.Context, id uuid.UUID) error {
+	return nil
+}
+
+type engineAdAccountRepo struct {
+	repository.AdAccountRepository
+}
+
+func (r *engineAdAccountRepo) Upsert(ctx context.Context, adAccount *entity.AdAccount) error {
+	return nil
+}
+
+func (r *engineAdAccountRepo) ListByConnectedAccount(ctx context.Context, connectedAccountID uuid.UUID) ([]entity.AdAccount, error) {
+	// The same ad account on every listing
+	id := uuid.NewSHA1(connectedAccountID, []byte("act_1"))
+	return []entity.AdAccount{{BaseEntity: entity.BaseEntity{ID: id}, ConnectedAccountID: connectedAccountID, PlatformAdAccountID: "act_1"}}, nil
+}
+
+type engineCampaignRepo struct {
+	repository.CampaignRepository
+	mu        stdsync.Mutex
+	campaigns []entity.Campaign
+}
+
+func (r *engineCampaignRepo) Upsert(ctx context.Context, campaign *entity.Campaign) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, stored := range r.campaigns {
+		if stored.AdAccountID == campaign.AdAccountID && stored.PlatformCampaignID == campaign.PlatformCampaignID {
+			campaign.ID = stored.ID
+			return nil
+		}
+	}
+	campaign.ID = uuid.New()
+	r.campaigns = append(r.campaigns, *campaign)
+	return nil
+}
+
+func (r *engineCampaignRepo) ListByAdAccount(ctx context.Context, adAccountID uuid.UUID) ([]entity.Campaign, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var campaigns []entity.Campaign
+	for _, campaign := range r.campaigns {
+		if campaign.AdAccountID == adAccountID {
+			campaigns = append(campaigns, campaign)
+		}
+	}
+	return campaigns, nil
+}
+
+type engineAdSetRepo struct {
+	repository.AdSetRepository
+}
+
+func (r *engineAdSetRepo) Upsert(ctx context.Context, adSet *entity.AdSet) error {
+	adSet.ID = uuid.New()
+	return nil
+}
+
+type engineAdRepo struct {
+	repository.AdRepository
+}
+
+func (r *engineAdRepo) Upsert(ctx context.Context, ad *entity.Ad) error {
+	return nil
+}
+
+type engineMetricsRepo struct {
+	repository.MetricsRepository
+	mu      stdsync.Mutex
+	metrics []entity.CampaignMetricsDaily
+}
+
+func (r *engineMetricsRepo) BulkUpsertCampaignMetrics(ctx context.Context, metrics []entity.CampaignMetricsDaily) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.metrics = append(r.metrics, metrics...)
+	return nil
+}
+
+type metricsListenerRecorder struct {
+	mu          stdsync.Mutex
+	campaignIDs []uuid.UUID
+}
+
+func (l *metricsListenerRecorder) MetricsSynced(ctx context.Context, orgID uuid.UUID, campaignIDs []uuid.UUID) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.campaignIDs = append(l.campaignIDs, campaignIDs...)
+}
+
+func newTestService(accounts *engineAccountRepo, registry engineRegistry, metrics *engineMetricsRepo, config *ServiceConfig) *Service {
+	if config == nil {
+		config = DefaultServiceConfig()
+	}
+	config.RetryConfig = &retry.Config{MaxRetries: 0, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+	return NewService(config, accounts, &engineAdAccountRepo{}, &engineCampaignRepo{}, &engineAdSetRepo{}, &engineAdRepo{}, metrics, registry, zerolog.Nop())
+}
+
+// ============================================================================
+// Tests
+// ============================================================================
+
+func TestService_SyncAccount_EveryPlatform(t *testing.T) {
+	for _, platform := range []entity.Platform{entity.PlatformMeta, entity.PlatformTikTok, entity.PlatformShopee} {
+		t.Run(string(platform), func(t *testing.T) {
+			accounts := &engineAccountRepo{}
+			accountID := accounts.add(platform)
+			metrics := &engineMetricsRepo{}
+			listener := &metricsListenerRecorder{}
+			svc := newTestService(accounts, engineRegistry{platform: &engineConnector{}}, metrics, nil)
+			svc.AddMetricsListener(listener)
+
+			result, err := svc.SyncAccount(context.Background(), accountID)
+			if err != nil {
+				t.Fatalf("SyncAccount() error = %v", err)
+			}
+			if !result.IsSuccess() {
+				t.Fatalf("SyncAccount() errors = %v", result.Errors)
+			}
+			if result.CampaignsSynced != 2 || result.AdSetsSynced != 2 || result.AdsSynced != 2 || result.MetricsSynced != 4 {
+				t.Errorf("synced %d campaigns, %d ad sets, %d ads, %d metrics, want 2, 2, 2, 4",
+					result.CampaignsSynced, result.AdSetsSynced, result.AdsSynced, result.MetricsSynced)
+			}
+
+			for _, m := range metrics.metrics {
+				if m.Platform != platform || m.CampaignID == uuid.Nil || m.OrganizationID != accounts.accounts[0].OrganizationID {
+					t.Errorf("stored metrics = %+v", m)
+				}
+			}
+			if len(listener.campaignIDs) != 2 {
+				t.Errorf("listener notified of %d campaigns, want 2", len(listener.campaignIDs))
+			}
+		})
+	}
+}
+
+func TestService_SyncAccount_PartialSuccess(t *testing.T) {
+	accounts := &engineAccountRepo{}
+	accountID := accounts.add(entity.PlatformTikTok)
+	svc := newTestService(accounts, engineRegistry{entity.PlatformTikTok: &engineConnector{failInsights: true}}, &engineMetricsRepo{}, nil)
+
+	result, err := svc.SyncAccount(context.Background(), accountID)
+	if err != nil {
+		t.Fatalf("SyncAccount() error = %v", err)
+	}
+	if len(result.Errors) != 2 {
+		t.Errorf("errors = %v, want one per campaign", result.Errors)
+	}
+	if result.CampaignsSynced != 2 || !result.HasPartialSuccess() {
+		t.Errorf("result = %+v, want the structure synced despite failed metrics", result)
+	}
+}
+
+func TestService_SyncAccount_TokenExpired(t *testing.T) {
+	accounts := &engineAccountRepo{}
+	accountID := accounts.add(entity.PlatformShopee)
+	expired := time.Now().Add(-time.Hour)
+	accounts.accounts[0].TokenExpiresAt = &expired
+	connector := &engineConnector{}
+	svc := newTestService(accounts, engineRegistry{entity.PlatformShopee: connector}, &engineMetricsRepo{}, nil)
+
+	if _, err := svc.SyncAccount(context.Background(), accountID); err == nil {
+		t.Fatal("SyncAccount() error = nil, want the expired token")
+	}
+	if connector.calls != 0 {
+		t.Errorf("connector called %d times with an expired token", connector.calls)
+	}
+}
+
+func TestService_SyncAccount_AccountRateLimit(t *testing.T) {
+	accounts := &engineAccountRepo{}
+	accountID := accounts.add(entity.PlatformTikTok)
+	connector := &engineConnector{}
+	config := DefaultServiceConfig()
+	config.SyncTimeout = 50 * time.Millisecond
+	config.AccountRateLimits[entity.PlatformTikTok] = AccountRateLimit{Calls: 3, Window: time.Hour}
+	svc := newTestService(accounts, engineRegistry{entity.PlatformTikTok: connector}, &engineMetricsRepo{}, config)
+
+	result, err := svc.SyncAccount(context.Background(), accountID)
+	if err != nil {
+		t.Fatalf("SyncAccount() error = %v", err)
+	}
+	if connector.calls != 3 {
+		t.Errorf("connector called %d times, want the limit of 3", connector.calls)
+	}
+	if result.IsSuccess() {
+		t.Error("SyncAccount() succeeded beyond the account's rate limit")
+	}
+}
+
+func TestService_BatchSync(t *testing.T) {
+	accounts := &engineAccountRepo{}
+	accounts.add(entity.PlatformMeta)
+	accounts.add(entity.PlatformTikTok)
+	accounts.add(entity.PlatformTikTok)
+	accounts.add(entity.PlatformShopee)
+	accounts.add(entity.PlatformLazada)
+
+	connector := &engineConnector{}
+	config := DefaultServiceConfig()
+	config.MaxConcurrency = 2
+	svc := newTestService(accounts, engineRegistry{
+		entity.PlatformMeta:   connector,
+		entity.PlatformTikTok: connector,
+		entity.PlatformShopee: connector,
+		entity.PlatformLazada: &engineConnector{failAccounts: true},
+	}, &engineMetricsRepo{}, config)
+
+	result, err := svc.BatchSync(context.Background(), service.BatchSyncRequest{})
+	if err != nil {
+		t.Fatalf("BatchSync() error = %v", err)
+	}
+	if result.TotalAccounts != 5 || result.SuccessCount != 4 || result.FailureCount != 1 {
+		t.Errorf("batch = %d total, %d succeeded, %d failed, want 5, 4, 1",
+			result.TotalAccounts, result.SuccessCount, result.FailureCount)
+	}
+	if connector.maxInFlight > 2 {
+		t.Errorf("synced %d accounts at once, want at most 2", connector.maxInFlight)
+	}
+
+	result, err = svc.BatchSync(context.Background(), service.BatchSyncRequest{
+		Platforms:     []entity.Platform{entity.PlatformTikTok},
+		SyncCampaigns: true,
+	})
+	if err != nil {
+		t.Fatalf("BatchSync() error = %v", err)
+	}
+	if result.TotalAccounts != 2 || result.SuccessCount != 2 {
+		t.Errorf("TikTok batch = %+v, want both TikTok accounts synced", result)
+	}
+	for _, r := range result.Results {
+		if r.AdSetsSynced != 0 || r.MetricsSynced != 0 {
+			t.Errorf("synced ad sets or metrics that were not requested: %+v", r)
+		}
+	}
+}
+
+func TestService_BatchSync_NoAccounts(t *testing.T) {
+	svc := newTestService(&engineAccountRepo{}, engineRegistry{}, &engineMetricsRepo{}, nil)
+
+	result, err := svc.SyncAllActive(context.Background())
+	if err != nil {
+		t.Fatalf("SyncAllActive() error = %v", err)
+	}
+	if result.TotalAccounts != 0 || len(result.Results) != 0 {
+		t.Errorf("result = %+v, want an empty batch", result)
+	}
+}
@@ -0,0 +1,209 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	domainService "github.com/ads-aggregator/ads-aggregator/internal/domain/service"
+	"github.com/ads-aggregator/ads-aggregator/pkg/ratelimit"
+	"github.com/google/uuid"
+)
+
+// ============================================================================
+// Incremental Structure Sync - Only the campaigns, ad sets and ads that changed
+// ============================================================================
+
+const (
+	// structureSyncedUntilKey is the sync state metadata key holding the
+	// structure sync high-water mark
+	structureSyncedUntilKey = "structure_synced_until"
+
+	// structureFullSyncedAtKey is the sync state metadata key holding when the
+	// account's whole structure was last listed
+	structureFullSyncedAtKey = "structure_full_synced_at"
+
+	// structureSyncOverlap re-reads recent changes in case the platform records them late
+	structureSyncOverlap = 10 * time.Minute
+
+	// structureFullSyncInterval is how often the whole structure is listed anyway,
+	// to pick up changes that did not move an object's update time
+	structureFullSyncInterval = 7 * 24 * time.Hour
+)
+
+// structureSyncSince returns the point in time a structure sync continues from.
+// It reports false when the account's whole structure is listed instead: on
+// its first sync, for initial and manual syncs, and once the last full listing
+// is older than structureFullSyncInterval.
+func structureSyncSince(job *entity.SyncJob, state *entity.SyncState, now time.Time) (time.Time, bool) {
+	if state == nil || job.SyncType == entity.SyncTypeInitial || job.SyncType == entity.SyncTypeManual {
+		return time.Time{}, false
+	}
+
+	fullValue, _ := state.Metadata[structureFullSyncedAtKey].(string)
+	fullSyncedAt, err := time.Parse(time.RFC3339, fullValue)
+	if err != nil || now.Sub(fullSyncedAt) > structureFullSyncInterval {
+		return time.Time{}, false
+	}
+
+	value, _ := state.Metadata[structureSyncedUntilKey].(string)
+	cursor, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return cursor.Add(-structureSyncOverlap), true
+}
+
+// syncStructureChanges stores the campaigns, ad sets and ads of an ad account
+// changed since the given time, or all of them for the zero time, into result.
+// Deleted objects come with the deleted status, so deletions are picked up
+// like any other change.
+func (s *Service) syncStructureChanges(
+	ctx context.Context,
+	account *entity.ConnectedAccount,
+	adAccount entity.AdAccount,
+	connector domainService.IncrementalStructureConnector,
+	limiter *ratelimit.Limiter,
+	since time.Time,
+	result *domainService.SyncResult,
+) {
+	var changes *domainService.StructureChanges
+	err := s.call(ctx, limiter, "GetStructureChanges", func(ctx context.Context) error {
+		var err error
+		changes, err = connector.GetStructureChanges(ctx, account.AccessToken, adAccount.PlatformAdAccountID, since)
+		return err
+	})
+	if err != nil {
+		s.recordError(result, err, "Failed to get structure changes")
+		return
+	}
+
+	// Platform IDs of the parents stored by this sync, so children need no lookup
+	campaignIDs := make(map[string]uuid.UUID)
+	adSetIDs := make(map[string]uuid.UUID)
+
+	now := time.Now()
+	for i := range changes.Campaigns {
+		campaign := &changes.Campaigns[i]
+		campaign.AdAccountID = adAccount.ID
+		campaign.OrganizationID = account.OrganizationID
+		campaign.LastSyncedAt = &now
+		if err := s.campaignRepo.Upsert(ctx, campaign); err != nil {
+			s.recordError(result, err, "Failed to upsert campaign")
+			continue
+		}
+		result.CampaignsSynced++
+		campaignIDs[campaign.PlatformCampaignID] = campaign.ID
+	}
+
+	for i := range changes.AdSets {
+		adSet := &changes.AdSets[i].AdSet
+		campaignID, err := s.storedCampaignID(ctx, adAccount.ID, campaignIDs, changes.AdSets[i].PlatformCampaignID)
+		if err != nil {
+			s.recordError(result, fmt.Errorf("campaign of ad set %s: %w", adSet.PlatformAdSetID, err), "Failed to find campaign of ad set")
+			continue
+		}
+		adSet.CampaignID = campaignID
+		adSet.OrganizationID = account.OrganizationID
+		adSet.LastSyncedAt = &now
+		if err := s.adSetRepo.Upsert(ctx, adSet); err != nil {
+			s.recordError(result, err, "Failed to upsert ad set")
+			continue
+		}
+		result.AdSetsSynced++
+		adSetIDs[adSet.PlatformAdSetID] = adSet.ID
+	}
+
+	for i := range changes.Ads {
+		ad := &changes.Ads[i].Ad
+		campaignID, err := s.storedCampaignID(ctx, adAccount.ID, campaignIDs, changes.Ads[i].PlatformCampaignID)
+		if err == nil {
+			ad.CampaignID = campaignID
+			ad.AdSetID, err = s.storedAdSetID(ctx, campaignID, adSetIDs, changes.Ads[i].PlatformAdSetID)
+		}
+		if err != nil {
+			s.recordError(result, fmt.Errorf("ad set of ad %s: %w", ad.PlatformAdID, err), "Failed to find ad set of ad")
+			continue
+		}
+		ad.OrganizationID = account.OrganizationID
+		ad.LastSyncedAt = &now
+		if err := s.adRepo.Upsert(ctx, ad); err != nil {
+			s.recordError(result, err, "Failed to upsert ad")
+			continue
+		}
+		result.AdsSynced++
+	}
+}
+
+// storedCampaignID returns the ID of the stored campaign with a platform ID
+func (s *Service) storedCampaignID(ctx context.Context, adAccountID uuid.UUID, ids map[string]uuid.UUID, platformCampaignID string) (uuid.UUID, error) {
+	if id, ok := ids[platformCampaignID]; ok {
+		return id, nil
+	}
+	campaign, err := s.campaignRepo.GetByPlatformID(ctx, adAccountID, platformCampaignID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	ids[platformCampaignID] = campaign.ID
+	return campaign.ID, nil
+}
+
+// storedAdSetID returns the ID of the stored ad set with a platform ID
+func (s *Service) storedAdSetID(ctx context.Context, campaignID uuid.UUID, ids map[string]uuid.UUID, platformAdSetID string) (uuid.UUID, error) {
+	if id, ok := ids[platformAdSetID]; ok {
+		return id, nil
+	}
+	adSet, err := s.adSetRepo.GetByPlatformID(ctx, campaignID, platformAdSetID)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	ids[platformAdSetID] = adSet.ID
+	return adSet.ID, nil
+}
+
+// markUnlistedCampaignsDeleted marks the stored campaigns of an ad account
+// missing from a full listing as deleted. Connectors without change listings
+// do not report deletions any other way.
+func (s *Service) markUnlistedCampaignsDeleted(ctx context.Context, adAccount entity.AdAccount, listed []entity.Campaign, result *domainService.SyncResult) {
+	stored, err := s.campaignRepo.ListByAdAccount(ctx, adAccount.ID)
+	if err != nil {
+		s.recordError(result, err, "Failed to list stored campaigns")
+		return
+	}
+
+	seen := make(map[string]bool, len(listed))
+	for _, campaign := range listed {
+		seen[campaign.PlatformCampaignID] = true
+	}
+
+	for i := range stored {
+		if seen[stored[i].PlatformCampaignID] || stored[i].Status == entity.CampaignStatusDeleted {
+			continue
+		}
+		stored[i].Status = entity.CampaignStatusDeleted
+		if err := s.campaignRepo.Update(ctx, &stored[i]); err != nil {
+			s.recordError(result, err, "Failed to mark campaign deleted")
+			continue
+		}
+		result.CampaignsSynced++
+	}
+}
+
+// recordStructureSynced moves the account's structure sync high-water mark to
+// when the sync started
+func (r *JobRunner) recordStructureSynced(ctx context.Context, state *entity.SyncState, startedAt time.Time, full bool) {
+	if state.Metadata == nil {
+		state.Metadata = entity.JSONMap{}
+	}
+	state.Metadata[structureSyncedUntilKey] = startedAt.UTC().Format(time.RFC3339)
+	if full {
+		state.Metadata[structureFullSyncedAtKey] = startedAt.UTC().Format(time.RFC3339)
+	}
+
+	if err := r.syncStateRepo.Update(ctx, state); err != nil {
+		log.Printf("[JobRunner] Error saving structure sync cursor: %v", err)
+	}
+}
@@ -0,0 +1,299 @@
+package sync
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/service"
+	"github.com/ads-aggregator/ads-aggregator/pkg/retry"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+func TestStructureSyncSince(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	cursor := now.Add(-time.Hour)
+	metadata := entity.JSONMap{
+		structureSyncedUntilKey:  cursor.Format(time.RFC3339),
+		structureFullSyncedAtKey: now.AddDate(0, 0, -2).Format(time.RFC3339),
+	}
+
+	tests := []struct {
+		name     string
+		syncType entity.SyncType
+		state    *entity.SyncState
+		want     time.Time
+		wantOK   bool
+	}{
+		{"continues from cursor", entity.SyncTypeDaily, &entity.SyncState{Metadata: metadata}, cursor.Add(-structureSyncOverlap), true},
+		{"webhook continues from cursor", entity.SyncTypeWebhook, &entity.SyncState{Metadata: metadata}, cursor.Add(-structureSyncOverlap), true},
+		{"initial lists everything", entity.SyncTypeInitial, &entity.SyncState{Metadata: metadata}, time.Time{}, false},
+		{"manual lists everything", entity.SyncTypeManual, &entity.SyncState{Metadata: metadata}, time.Time{}, false},
+		{"no sync state", entity.SyncTypeDaily, nil, time.Time{}, false},
+		{"first sync", entity.SyncTypeDaily, &entity.SyncState{}, time.Time{}, false},
+		{"stale full listing", entity.SyncTypeDaily, &entity.SyncState{Metadata: entity.JSONMap{
+			structureSyncedUntilKey:  cursor.Format(time.RFC3339),
+			structureFullSyncedAtKey: now.AddDate(0, 0, -8).Format(time.RFC3339),
+		}}, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := structureSyncSince(&entity.SyncJob{SyncType: tt.syncType}, tt.state, now)
+			if ok != tt.wantOK || !got.Equal(tt.want) {
+				t.Errorf("structureSyncSince() = %v, %t, want %v, %t", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+// adAccountsConnector lists a single ad account
+type adAccountsConnector struct {
+	service.PlatformConnector
+}
+
+func (c *adAccountsConnector) GetAdAccounts(ctx context.Context, accessToken string) ([]entity.PlatformAccount, error) {
+	return []entity.PlatformAccount{{ID: "act_1", Status: "ACTIVE"}}, nil
+}
+
+type changesConnector struct {
+	adAccountsConnector
+
+	changes *service.StructureChanges
+	since   []time.Time
+}
+
+func (c *changesConnector) GetStructureChanges(ctx context.Context, accessToken string, adAccountID string, since time.Time) (*service.StructureChanges, error) {
+	c.since = append(c.since, since)
+	return c.changes, nil
+}
+
+type listingConnector struct {
+	adAccountsConnector
+	campaigns []entity.Campaign
+}
+
+func (c *listingConnector) GetCampaigns(ctx context.Context, accessToken string, adAccountID string) ([]entity.Campaign, error) {
+	return append([]entity.Campaign(nil), c.campaigns...), nil
+}
+
+func (c *listingConnector) GetAdSets(ctx context.Context, accessToken string, campaignID string) ([]entity.AdSet, error) {
+	return nil, nil
+}
+
+type structureCampaignRepo struct {
+	repository.CampaignRepository
+	stored map[string]entity.Campaign // By platform ID
+}
+
+func (r *structureCampaignRepo) Upsert(ctx context.Context, campaign *entity.Campaign) error {
+	if existing, ok := r.stored[campaign.PlatformCampaignID]; ok {
+		campaign.ID = existing.ID
+	} else {
+		campaign.ID = uuid.New()
+	}
+	r.stored[campaign.PlatformCampaignID] = *campaign
+	return nil
+}
+
+func (r *structureCampaignRepo) Update(ctx context.Context, campaign *entity.Campaign) error {
+	r.stored[campaign.PlatformCampaignID] = *campaign
+	return nil
+}
+
+func (r *structureCampaignRepo) GetByPlatformID(ctx context.Context, adAccountID uuid.UUID, platformCampaignID string) (*entity.Campaign, error) {
+	campaign, ok := r.stored[platformCampaignID]
+	if !ok {
+		return nil, errors.New("record not found")
+	}
+	return &campaign, nil
+}
+
+func (r *structureCampaignRepo) ListByAdAccount(ctx context.Context, adAccountID uuid.UUID) ([]entity.Campaign, error) {
+	var campaigns []entity.Campaign
+	for _, campaign := range r.stored {
+		campaigns = append(campaigns, campaign)
+	}
+	return campaigns, nil
+}
+
+type structureAdSetRepo struct {
+	repository.AdSetRepository
+	stored map[string]entity.AdSet
+}
+
+func (r *structureAdSetRepo) Upsert(ctx context.Context, adSet *entity.AdSet) error {
+	adSet.ID = uuid.New()
+	r.stored[adSet.PlatformAdSetID] = *adSet
+	return nil
+}
+
+type structureAdRepo struct {
+	repository.AdRepository
+	stored map[string]entity.Ad
+}
+
+func (r *structureAdRepo) Upsert(ctx context.Context, ad *entity.Ad) error {
+	r.stored[ad.PlatformAdID] = *ad
+	return nil
+}
+
+type structureStateRepo struct {
+	repository.SyncStateRepository
+	state   *entity.SyncState
+	updates int
+}
+
+func (r *structureStateRepo) GetByConnectedAccount(ctx context.Context, connectedAccountID uuid.UUID) (*entity.SyncState, error) {
+	return r.state, nil
+}
+
+func (r *structureStateRepo) Update(ctx context.Context, state *entity.SyncState) error {
+	r.updates++
+	return nil
+}
+
+type structureFixture struct {
+	runner    *JobRunner
+	job       *entity.SyncJob
+	state     *structureStateRepo
+	campaigns *structureCampaignRepo
+	adSets    *structureAdSetRepo
+	ads       *structureAdRepo
+}
+
+func newStructureFixture(connector service.PlatformConnector, metadata entity.JSONMap) *structureFixture {
+	jobs := &queueJobRepo{}
+	f := &structureFixture{
+		job:       jobs.add(entity.SyncJobPriorityDaily, entity.SyncStatusRunning),
+		state:     &structureStateRepo{state: &entity.SyncState{Metadata: metadata}},
+		campaigns: &structureCampaignRepo{stored: map[string]entity.Campaign{}},
+		adSets:    &structureAdSetRepo{stored: map[string]entity.AdSet{}},
+		ads:       &structureAdRepo{stored: map[string]entity.Ad{}},
+	}
+	f.job.SyncType = entity.SyncTypeDaily
+	f.job.SyncScope = entity.SyncScopeStructure
+
+	engine := NewService(&ServiceConfig{RetryConfig: &retry.Config{MaxRetries: 0}}, &backfillAccountRepo{}, &engineAdAccountRepo{},
+		f.campaigns, f.adSets, f.ads, nil, engineRegistry{entity.PlatformMeta: connector}, zerolog.Nop())
+	f.runner = NewJobRunner(engine, f.state, jobs, nil, nil, &JobRunnerConfig{
+		MaxConcurrentJobs: 1,
+		Configs:           map[entity.Platform]entity.SyncScheduleConfig{entity.PlatformMeta: {}},
+	})
+	return f
+}
+
+func TestJobRunner_IncrementalStructureSync(t *testing.T) {
+	cursor := time.Now().Add(-2 * time.Hour).UTC().Truncate(time.Second)
+	fullSyncedAt := time.Now().AddDate(0, 0, -1).UTC().Format(time.RFC3339)
+
+	connector := &changesConnector{changes: &service.StructureChanges{
+		Campaigns: []entity.Campaign{{PlatformCampaignID: "removed", Status: entity.CampaignStatusDeleted}},
+		AdSets:    []service.AdSetChange{{AdSet: entity.AdSet{PlatformAdSetID: "new-group"}, PlatformCampaignID: "existing"}},
+		Ads:       []service.AdChange{{Ad: entity.Ad{PlatformAdID: "new-ad"}, PlatformCampaignID: "existing", PlatformAdSetID: "new-group"}},
+	}}
+	f := newStructureFixture(connector, entity.JSONMap{
+		structureSyncedUntilKey:  cursor.Format(time.RFC3339),
+		structureFullSyncedAtKey: fullSyncedAt,
+	})
+	existing := entity.Campaign{BaseEntity: entity.NewBaseEntity(), PlatformCampaignID: "existing", Status: entity.CampaignStatusActive}
+	f.campaigns.stored["existing"] = existing
+
+	started := time.Now()
+	if err := f.runner.runSync(context.Background(), f.job); err != nil {
+		t.Fatalf("runSync() error = %v", err)
+	}
+
+	if len(connector.since) != 1 || !connector.since[0].Equal(cursor.Add(-structureSyncOverlap)) {
+		t.Fatalf("changes requested since %v, want %v", connector.since, cursor.Add(-structureSyncOverlap))
+	}
+	if got := f.campaigns.stored["removed"].Status; got != entity.CampaignStatusDeleted {
+		t.Errorf("removed campaign status = %q, want %q", got, entity.CampaignStatusDeleted)
+	}
+
+	adSet := f.adSets.stored["new-group"]
+	if adSet.CampaignID != existing.ID {
+		t.Errorf("ad set campaign = %s, want stored campaign %s", adSet.CampaignID, existing.ID)
+	}
+	ad := f.ads.stored["new-ad"]
+	if ad.CampaignID != existing.ID || ad.AdSetID != adSet.ID {
+		t.Errorf("ad parents = %s/%s, want %s/%s", ad.CampaignID, ad.AdSetID, existing.ID, adSet.ID)
+	}
+	if f.job.RecordsProcessed != 3 || f.job.RecordsFailed != 0 {
+		t.Errorf("records = %d processed, %d failed, want 3, 0", f.job.RecordsProcessed, f.job.RecordsFailed)
+	}
+
+	metadata := f.state.state.Metadata
+	syncedUntil, _ := time.Parse(time.RFC3339, metadata[structureSyncedUntilKey].(string))
+	if f.state.updates != 1 || syncedUntil.Before(started.Truncate(time.Second)) {
+		t.Errorf("high-water mark = %v, want moved to the sync start", metadata[structureSyncedUntilKey])
+	}
+	if metadata[structureFullSyncedAtKey] != fullSyncedAt {
+		t.Errorf("full listing time = %v, want unchanged", metadata[structureFullSyncedAtKey])
+	}
+}
+
+func TestJobRunner_StructureSyncListsEverythingFirst(t *testing.T) {
+	connector := &changesConnector{changes: &service.StructureChanges{
+		Campaigns: []entity.Campaign{{PlatformCampaignID: "first"}},
+	}}
+	f := newStructureFixture(connector, nil)
+
+	if err := f.runner.runSync(context.Background(), f.job); err != nil {
+		t.Fatalf("runSync() error = %v", err)
+	}
+
+	if len(connector.since) != 1 || !connector.since[0].IsZero() {
+		t.Fatalf("changes requested since %v, want the whole structure", connector.since)
+	}
+	for _, key := range []string{structureSyncedUntilKey, structureFullSyncedAtKey} {
+		if _, ok := f.state.state.Metadata[key].(string); !ok {
+			t.Errorf("metadata %s not recorded", key)
+		}
+	}
+}
+
+func TestJobRunner_StructureSyncKeepsCursorOnFailure(t *testing.T) {
+	cursor := time.Now().Add(-time.Hour).UTC().Format(time.RFC3339)
+	connector := &changesConnector{changes: &service.StructureChanges{
+		Campaigns: []entity.Campaign{{PlatformCampaignID: "stored"}},
+		AdSets:    []service.AdSetChange{{AdSet: entity.AdSet{PlatformAdSetID: "orphan"}, PlatformCampaignID: "unknown"}},
+	}}
+	f := newStructureFixture(connector, entity.JSONMap{
+		structureSyncedUntilKey:  cursor,
+		structureFullSyncedAtKey: cursor,
+	})
+
+	if err := f.runner.runSync(context.Background(), f.job); err != nil {
+		t.Fatalf("runSync() error = %v", err)
+	}
+
+	if f.job.RecordsProcessed != 1 || f.job.RecordsFailed != 1 {
+		t.Errorf("records = %d processed, %d failed, want 1, 1", f.job.RecordsProcessed, f.job.RecordsFailed)
+	}
+	if f.state.updates != 0 || f.state.state.Metadata[structureSyncedUntilKey] != cursor {
+		t.Errorf("high-water mark moved to %v after a failed ad set", f.state.state.Metadata[structureSyncedUntilKey])
+	}
+}
+
+func TestJobRunner_FullCampaignListingMarksUnlistedDeleted(t *testing.T) {
+	connector := &listingConnector{campaigns: []entity.Campaign{{PlatformCampaignID: "kept", Status: entity.CampaignStatusActive}}}
+	f := newStructureFixture(connector, nil)
+	f.campaigns.stored["kept"] = entity.Campaign{BaseEntity: entity.NewBaseEntity(), PlatformCampaignID: "kept", Status: entity.CampaignStatusActive}
+	f.campaigns.stored["gone"] = entity.Campaign{BaseEntity: entity.NewBaseEntity(), PlatformCampaignID: "gone", Status: entity.CampaignStatusPaused}
+
+	if err := f.runner.runSync(context.Background(), f.job); err != nil {
+		t.Fatalf("runSync() error = %v", err)
+	}
+
+	if got := f.campaigns.stored["gone"].Status; got != entity.CampaignStatusDeleted {
+		t.Errorf("unlisted campaign status = %q, want %q", got, entity.CampaignStatusDeleted)
+	}
+	if got := f.campaigns.stored["kept"].Status; got != entity.CampaignStatusActive {
+		t.Errorf("listed campaign status = %q, want %q", got, entity.CampaignStatusActive)
+	}
+}
@@ -0,0 +1,38 @@
+package webhook
+
+import "time"
+
+// SyncData is the data of sync.completed and sync.failed events
+type SyncData struct {
+	SyncJobID          string     `json:"sync_job_id"`
+	ConnectedAccountID string     `json:"connected_account_id"`
+	Platform           string     `json:"platform"`
+	SyncType           string     `json:"sync_type"`
+	SyncScope          string     `json:"sync_scope"`
+	RecordsProcessed   int        `json:"records_processed"`
+	RecordsFailed      int        `json:"records_failed"`
+	StartedAt          *time.Time `json:"started_at,omitempty"`
+	FinishedAt         time.Time  `json:"finished_at"`
+	Error              string     `json:"error,omitempty"`      // sync.failed only
+	ErrorType          string     `json:"error_type,omitempty"` // sync.failed only, e.g. "auth" or "rate_limit"
+}
+
+// TokenExpiredData is the data of token.expired events
+type TokenExpiredData struct {
+	ConnectedAccountID string     `json:"connected_account_id"`
+	Platform           string     `json:"platform"`
+	AccountName        string     `json:"account_name,omitempty"`
+	ExpiredAt          *time.Time `json:"expired_at,omitempty"`
+}
+
+// ReportReadyData is the data of report.ready events
+type ReportReadyData struct {
+	ReportID    string     `json:"report_id"`
+	Format      string     `json:"format"`
+	FileName    string     `json:"file_name"`
+	SizeBytes   int64      `json:"size_bytes"`
+	StartDate   string     `json:"start_date"`
+	EndDate     string     `json:"end_date"`
+	DownloadURL string     `json:"download_url"` // Relative to the API base URL; requires authentication
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+}
@@ -0,0 +1,508 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/crypto"
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/ads-aggregator/ads-aggregator/pkg/httpclient"
+	"github.com/google/uuid"
+)
+
+// retryBackoff is the wait before each retry of a failed delivery
+var retryBackoff = []time.Duration{
+	time.Minute,
+	5 * time.Minute,
+	30 * time.Minute,
+	2 * time.Hour,
+	6 * time.Hour,
+	12 * time.Hour,
+}
+
+// maxAttempts is the number of attempts of a delivery before it is marked failed
+var maxAttempts = len(retryBackoff) + 1
+
+const (
+	// deliveryLease keeps a delivery being attempted from being claimed by the
+	// retry sweep; it outlasts the sender's request timeout
+	deliveryLease = 2 * time.Minute
+
+	// maxResponseBody is the number of response bytes kept on a delivery
+	maxResponseBody = 2048
+)
+
+// Sender posts the payload of a delivery to an endpoint. It returns an error
+// for transport failures and responses other than 2xx; the response is
+// returned whenever one was received.
+type Sender interface {
+	Send(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) (*Response, error)
+}
+
+// Response is the endpoint's response to a delivery attempt
+type Response struct {
+	StatusCode int
+	Body       string
+	Duration   time.Duration
+}
+
+// Payload is the JSON body of a webhook request
+type Payload struct {
+	ID             string      `json:"id"` // Event ID, the same for every retry and replay
+	Event          string      `json:"event"`
+	OrganizationID string      `json:"organization_id"`
+	Timestamp      time.Time   `json:"timestamp"`
+	Data           interface{} `json:"data"`
+}
+
+// EndpointWithSecret is a webhook endpoint along with its signing secret. The
+// secret is only shown when the endpoint is created or its secret rotated.
+type EndpointWithSecret struct {
+	entity.WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+// Service manages the webhook endpoints of organizations and delivers events to them
+type Service struct {
+	endpointRepo     repository.WebhookEndpointRepository
+	deliveryRepo     repository.WebhookDeliveryRepository
+	subscriptionRepo repository.SubscriptionRepository
+	secretEncryptor  *crypto.TokenEncryptor
+	sender           Sender
+}
+
+// NewService creates a new webhook service. The subscription repository
+// decides which organizations' plans include webhooks.
+func NewService(
+	endpointRepo repository.WebhookEndpointRepository,
+	deliveryRepo repository.WebhookDeliveryRepository,
+	subscriptionRepo repository.SubscriptionRepository,
+	sender Sender,
+) *Service {
+	return &Service{
+		endpointRepo:     endpointRepo,
+		deliveryRepo:     deliveryRepo,
+		subscriptionRepo: subscriptionRepo,
+		sender:           sender,
+	}
+}
+
+// SetSecretEncryptor sets the encryptor endpoint secrets are stored with.
+// Without one they are stored as is.
+func (s *Service) SetSecretEncryptor(encryptor *crypto.TokenEncryptor) {
+	s.secretEncryptor = encryptor
+}
+
+// EndpointInput holds the user-editable fields of a webhook endpoint
+type EndpointInput struct {
+	URL         string
+	Description string
+	Events      []entity.WebhookEventType
+	IsActive    *bool
+}
+
+// ListEndpoints lists the webhook endpoints of an organization
+func (s *Service) ListEndpoints(ctx context.Context, orgID uuid.UUID) ([]entity.WebhookEndpoint, error) {
+	endpoints, err := s.endpointRepo.ListByOrganization(ctx, orgID)
+	if err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load webhook endpoints", http.StatusInternalServerError)
+	}
+	if endpoints == nil {
+		endpoints = []entity.WebhookEndpoint{}
+	}
+	return endpoints, nil
+}
+
+// GetEndpoint returns a webhook endpoint of an organization
+func (s *Service) GetEndpoint(ctx context.Context, orgID, endpointID uuid.UUID) (*entity.WebhookEndpoint, error) {
+	endpoint, err := s.endpointRepo.GetByID(ctx, endpointID)
+	if err != nil || endpoint == nil || endpoint.OrganizationID != orgID {
+		return nil, errors.ErrNotFound("Webhook endpoint")
+	}
+	return endpoint, nil
+}
+
+// CreateEndpoint validates and creates a webhook endpoint with a new signing
+// secret, which is returned this once
+func (s *Service) CreateEndpoint(ctx context.Context, orgID, userID uuid.UUID, input EndpointInput) (*EndpointWithSecret, error) {
+	if !s.webhooksEnabled(ctx, orgID) {
+		return nil, errors.ErrForbidden("Webhooks are not available on your current plan")
+	}
+
+	endpoint := &entity.WebhookEndpoint{
+		BaseEntity:     entity.NewBaseEntity(),
+		OrganizationID: orgID,
+		IsActive:       true,
+		CreatedBy:      &userID,
+	}
+	if err := applyEndpointInput(endpoint, input); err != nil {
+		return nil, err
+	}
+	secret, err := s.newSecret(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.endpointRepo.Create(ctx, endpoint); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to create webhook endpoint", http.StatusInternalServerError)
+	}
+	return &EndpointWithSecret{WebhookEndpoint: *endpoint, Secret: secret}, nil
+}
+
+// UpdateEndpoint validates and replaces the user-editable fields of a webhook endpoint
+func (s *Service) UpdateEndpoint(ctx context.Context, orgID, endpointID uuid.UUID, input EndpointInput) (*entity.WebhookEndpoint, error) {
+	endpoint, err := s.GetEndpoint(ctx, orgID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyEndpointInput(endpoint, input); err != nil {
+		return nil, err
+	}
+
+	if err := s.endpointRepo.Update(ctx, endpoint); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to update webhook endpoint", http.StatusInternalServerError)
+	}
+	return endpoint, nil
+}
+
+// RotateSecret replaces the signing secret of a webhook endpoint and returns
+// the new one. Requests are signed with it from the next attempt on.
+func (s *Service) RotateSecret(ctx context.Context, orgID, endpointID uuid.UUID) (*EndpointWithSecret, error) {
+	endpoint, err := s.GetEndpoint(ctx, orgID, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := s.newSecret(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.endpointRepo.Update(ctx, endpoint); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to update webhook endpoint", http.StatusInternalServerError)
+	}
+	return &EndpointWithSecret{WebhookEndpoint: *endpoint, Secret: secret}, nil
+}
+
+// DeleteEndpoint deletes a webhook endpoint and its delivery log
+func (s *Service) DeleteEndpoint(ctx context.Context, orgID, endpointID uuid.UUID) error {
+	if _, err := s.GetEndpoint(ctx, orgID, endpointID); err != nil {
+		return err
+	}
+	if err := s.endpointRepo.Delete(ctx, endpointID); err != nil {
+		return errors.Wrap(err, errors.ErrCodeInternal, "Failed to delete webhook endpoint", http.StatusInternalServerError)
+	}
+	return nil
+}
+
+// ListDeliveries lists the webhook deliveries of an organization, most recent first
+func (s *Service) ListDeliveries(ctx context.Context, filter entity.WebhookDeliveryFilter) ([]entity.WebhookDelivery, int64, error) {
+	deliveries, total, err := s.deliveryRepo.List(ctx, filter)
+	if err != nil {
+		return nil, 0, errors.Wrap(err, errors.ErrCodeInternal, "Failed to load webhook deliveries", http.StatusInternalServerError)
+	}
+	if deliveries == nil {
+		deliveries = []entity.WebhookDelivery{}
+	}
+	return deliveries, total, nil
+}
+
+// GetDelivery returns a webhook delivery of an organization
+func (s *Service) GetDelivery(ctx context.Context, orgID, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	delivery, err := s.deliveryRepo.GetByID(ctx, deliveryID)
+	if err != nil || delivery == nil || delivery.OrganizationID != orgID {
+		return nil, errors.ErrNotFound("Webhook delivery")
+	}
+	return delivery, nil
+}
+
+// Publish records a delivery of an event for every active endpoint of the
+// organization subscribed to it. Deliveries are created pending and due right
+// away, and sent by DeliverDue, so publishing never waits on an endpoint. It
+// returns the deliveries created.
+func (s *Service) Publish(ctx context.Context, orgID uuid.UUID, eventType entity.WebhookEventType, data interface{}) ([]entity.WebhookDelivery, error) {
+	if !eventType.IsValid() {
+		return nil, fmt.Errorf("unknown webhook event type: %s", eventType)
+	}
+	if !s.webhooksEnabled(ctx, orgID) {
+		return nil, nil
+	}
+
+	endpoints, err := s.endpointRepo.ListSubscribed(ctx, orgID, eventType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list webhook endpoints: %w", err)
+	}
+	if len(endpoints) == 0 {
+		return nil, nil
+	}
+
+	now := time.Now().UTC()
+	eventID := uuid.New()
+	payload, err := json.Marshal(Payload{
+		ID:             eventID.String(),
+		Event:          string(eventType),
+		OrganizationID: orgID.String(),
+		Timestamp:      now,
+		Data:           data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	deliveries := make([]entity.WebhookDelivery, 0, len(endpoints))
+	var firstErr error
+	for i := range endpoints {
+		delivery := &entity.WebhookDelivery{
+			BaseEntity:     entity.NewBaseEntity(),
+			OrganizationID: orgID,
+			EndpointID:     endpoints[i].ID,
+			EventID:        eventID,
+			EventType:      eventType,
+			Payload:        payload,
+			Status:         entity.WebhookDeliveryPending,
+			NextAttemptAt:  &now,
+		}
+		if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to create webhook delivery: %w", err)
+			}
+			continue
+		}
+		deliveries = append(deliveries, *delivery)
+	}
+
+	return deliveries, firstErr
+}
+
+// ReplayDelivery sends the event of a delivery to its endpoint again as a new
+// delivery, which is retried like any other. Unlike Publish, the first attempt
+// is made right away so the user sees its outcome.
+func (s *Service) ReplayDelivery(ctx context.Context, orgID, deliveryID uuid.UUID) (*entity.WebhookDelivery, error) {
+	original, err := s.GetDelivery(ctx, orgID, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	endpoint, err := s.GetEndpoint(ctx, orgID, original.EndpointID)
+	if err != nil {
+		return nil, err
+	}
+	if !endpoint.IsActive {
+		return nil, errors.ErrValidation("Webhook endpoint is disabled")
+	}
+
+	delivery := &entity.WebhookDelivery{
+		BaseEntity:     entity.NewBaseEntity(),
+		OrganizationID: orgID,
+		EndpointID:     endpoint.ID,
+		EventID:        original.EventID,
+		EventType:      original.EventType,
+		Payload:        original.Payload,
+		Status:         entity.WebhookDeliveryPending,
+		ReplayOf:       &original.ID,
+	}
+	if err := s.deliverNow(ctx, endpoint, delivery, time.Now().UTC()); err != nil {
+		return nil, errors.Wrap(err, errors.ErrCodeInternal, "Failed to replay webhook delivery", http.StatusInternalServerError)
+	}
+	return delivery, nil
+}
+
+// DeliverDue attempts up to limit deliveries whose next attempt is due, new
+// and retried ones alike, and returns them with the outcome of the attempt.
+// Deliveries of endpoints that were disabled in the meantime are marked failed.
+func (s *Service) DeliverDue(ctx context.Context, now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	deliveries, err := s.deliveryRepo.ClaimDue(ctx, now, deliveryLease, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+
+	endpoints := make(map[uuid.UUID]*entity.WebhookEndpoint)
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		endpoint, loaded := endpoints[delivery.EndpointID]
+		if !loaded {
+			endpoint, err = s.endpointRepo.GetByID(ctx, delivery.EndpointID)
+			if err != nil {
+				endpoint = nil
+			}
+			endpoints[delivery.EndpointID] = endpoint
+		}
+
+		if endpoint == nil || !endpoint.IsActive {
+			delivery.Status = entity.WebhookDeliveryFailed
+			delivery.NextAttemptAt = nil
+			delivery.Error = "webhook endpoint is disabled"
+			if err := s.deliveryRepo.Update(ctx, delivery); err != nil {
+				return deliveries, fmt.Errorf("failed to update webhook delivery: %w", err)
+			}
+			continue
+		}
+
+		if err := s.attempt(ctx, endpoint, delivery, now); err != nil {
+			return deliveries, err
+		}
+	}
+
+	return deliveries, nil
+}
+
+// deliverNow records a new delivery and attempts it. The delivery is leased
+// while the attempt is in flight so the retry sweep does not pick it up.
+func (s *Service) deliverNow(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery, now time.Time) error {
+	leasedUntil := now.Add(deliveryLease)
+	delivery.NextAttemptAt = &leasedUntil
+	if err := s.deliveryRepo.Create(ctx, delivery); err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	return s.attempt(ctx, endpoint, delivery, now)
+}
+
+// attempt sends a delivery and records the outcome. A failed attempt is
+// scheduled for retry with backoff until maxAttempts is reached.
+func (s *Service) attempt(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery, now time.Time) error {
+	delivery.Attempts++
+	delivery.LastAttemptAt = &now
+
+	resp, err := s.send(ctx, endpoint, delivery)
+	delivery.ResponseStatus = 0
+	delivery.ResponseBody = ""
+	delivery.DurationMs = 0
+	if resp != nil {
+		delivery.ResponseStatus = resp.StatusCode
+		delivery.ResponseBody = truncate(resp.Body, maxResponseBody)
+		delivery.DurationMs = int(resp.Duration.Milliseconds())
+	}
+
+	switch {
+	case err == nil:
+		delivery.Status = entity.WebhookDeliverySucceeded
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+		delivery.Error = ""
+	case delivery.Attempts >= maxAttempts:
+		delivery.Status = entity.WebhookDeliveryFailed
+		delivery.NextAttemptAt = nil
+		delivery.Error = err.Error()
+	default:
+		next := now.Add(retryBackoff[delivery.Attempts-1])
+		delivery.Status = entity.WebhookDeliveryPending
+		delivery.NextAttemptAt = &next
+		delivery.Error = err.Error()
+	}
+
+	if err := s.deliveryRepo.Update(ctx, delivery); err != nil {
+		return fmt.Errorf("failed to update webhook delivery: %w", err)
+	}
+	return nil
+}
+
+// send passes the delivery to the sender with the endpoint's secret decrypted
+func (s *Service) send(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) (*Response, error) {
+	secret := endpoint.Secret
+	if s.secretEncryptor != nil {
+		var err error
+		if secret, err = s.secretEncryptor.Decrypt(endpoint.Secret); err != nil {
+			return nil, fmt.Errorf("failed to decrypt webhook secret: %w", err)
+		}
+	}
+
+	signing := *endpoint
+	signing.Secret = secret
+	return s.sender.Send(ctx, &signing, delivery)
+}
+
+// newSecret generates a signing secret, stores it on the endpoint encrypted
+// and returns it in plain text
+func (s *Service) newSecret(endpoint *entity.WebhookEndpoint) (string, error) {
+	secret, err := generateSecret()
+	if err != nil {
+		return "", errors.Wrap(err, errors.ErrCodeInternal, "Failed to generate webhook secret", http.StatusInternalServerError)
+	}
+
+	endpoint.Secret = secret
+	if s.secretEncryptor != nil {
+		if endpoint.Secret, err = s.secretEncryptor.Encrypt(secret); err != nil {
+			return "", errors.Wrap(err, errors.ErrCodeInternal, "Failed to encrypt webhook secret", http.StatusInternalServerError)
+		}
+	}
+	return secret, nil
+}
+
+// webhooksEnabled reports whether the organization's plan includes webhooks.
+// An organization without a subscription is on the free plan, and webhooks are
+// refused when no subscription repository is configured.
+func (s *Service) webhooksEnabled(ctx context.Context, orgID uuid.UUID) bool {
+	if s.subscriptionRepo == nil {
+		return false
+	}
+	sub, err := s.subscriptionRepo.GetByOrganization(ctx, orgID)
+	if err != nil || sub == nil {
+		return entity.GetPlanLimits(entity.PlanTierFree).WebhooksEnabled
+	}
+	return sub.GetLimits().WebhooksEnabled
+}
+
+// applyEndpointInput validates the input and copies it onto the endpoint
+func applyEndpointInput(endpoint *entity.WebhookEndpoint, input EndpointInput) error {
+	endpointURL := strings.TrimSpace(input.URL)
+	parsed, err := url.Parse(endpointURL)
+	if endpointURL == "" || err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return errors.ErrValidation("url must be an http or https URL")
+	}
+	if err := httpclient.ValidatePublicURL(parsed); err != nil {
+		return errors.ErrValidation("url must point to a public host")
+	}
+
+	if len(input.Events) == 0 {
+		return errors.ErrValidation("At least one event is required")
+	}
+	events := make([]entity.WebhookEventType, 0, len(input.Events))
+	for _, eventType := range input.Events {
+		if !eventType.IsValid() {
+			return errors.ErrValidation(fmt.Sprintf("Unknown event: %s", eventType))
+		}
+		if !containsEventType(events, eventType) {
+			events = append(events, eventType)
+		}
+	}
+
+	endpoint.URL = endpointURL
+	endpoint.Description = input.Description
+	endpoint.Events = events
+	if input.IsActive != nil {
+		endpoint.IsActive = *input.IsActive
+	}
+	return nil
+}
+
+func containsEventType(events []entity.WebhookEventType, eventType entity.WebhookEventType) bool {
+	for _, e := range events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// generateSecret generates the secret used to sign an endpoint's requests
+func generateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max]
+}
@@ -0,0 +1,474 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/entity"
+	"github.com/ads-aggregator/ads-aggregator/internal/domain/repository"
+	"github.com/ads-aggregator/ads-aggregator/pkg/crypto"
+	appErrors "github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+)
+
+type mockEndpointRepository struct {
+	endpoints map[uuid.UUID]*entity.WebhookEndpoint
+}
+
+func newMockEndpointRepo() *mockEndpointRepository {
+	return &mockEndpointRepository{endpoints: make(map[uuid.UUID]*entity.WebhookEndpoint)}
+}
+
+func (m *mockEndpointRepository) Create(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	m.endpoints[endpoint.ID] = endpoint
+	return nil
+}
+
+func (m *mockEndpointRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.WebhookEndpoint, error) {
+	return m.endpoints[id], nil
+}
+
+func (m *mockEndpointRepository) Update(ctx context.Context, endpoint *entity.WebhookEndpoint) error {
+	m.endpoints[endpoint.ID] = endpoint
+	return nil
+}
+
+func (m *mockEndpointRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	delete(m.endpoints, id)
+	return nil
+}
+
+func (m *mockEndpointRepository) ListByOrganization(ctx context.Context, orgID uuid.UUID) ([]entity.WebhookEndpoint, error) {
+	var endpoints []entity.WebhookEndpoint
+	for _, endpoint := range m.endpoints {
+		if endpoint.OrganizationID == orgID {
+			endpoints = append(endpoints, *endpoint)
+		}
+	}
+	return endpoints, nil
+}
+
+func (m *mockEndpointRepository) ListSubscribed(ctx context.Context, orgID uuid.UUID, eventType entity.WebhookEventType) ([]entity.WebhookEndpoint, error) {
+	var endpoints []entity.WebhookEndpoint
+	for _, endpoint := range m.endpoints {
+		if endpoint.OrganizationID == orgID && endpoint.IsActive && endpoint.Subscribes(eventType) {
+			endpoints = append(endpoints, *endpoint)
+		}
+	}
+	return endpoints, nil
+}
+
+type mockDeliveryRepository struct {
+	deliveries map[uuid.UUID]*entity.WebhookDelivery
+}
+
+func newMockDeliveryRepo() *mockDeliveryRepository {
+	return &mockDeliveryRepository{deliveries: make(map[uuid.UUID]*entity.WebhookDelivery)}
+}
+
+func (m *mockDeliveryRepository) Create(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	stored := *delivery
+	m.deliveries[delivery.ID] = &stored
+	return nil
+}
+
+func (m *mockDeliveryRepository) GetByID(ctx context.Context, id uuid.UUID) (*entity.WebhookDelivery, error) {
+	if delivery, ok := m.deliveries[id]; ok {
+		copied := *delivery
+		return &copied, nil
+	}
+	return nil, nil
+}
+
+func (m *mockDeliveryRepository) Update(ctx context.Context, delivery *entity.WebhookDelivery) error {
+	stored := *delivery
+	m.deliveries[delivery.ID] = &stored
+	return nil
+}
+
+func (m *mockDeliveryRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if len(deliveries) == limit {
+			break
+		}
+		if delivery.Status == entity.WebhookDeliveryPending && delivery.NextAttemptAt != nil && !delivery.NextAttemptAt.After(now) {
+			leasedUntil := now.Add(lease)
+			delivery.NextAttemptAt = &leasedUntil
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (m *mockDeliveryRepository) List(ctx context.Context, filter entity.WebhookDeliveryFilter) ([]entity.WebhookDelivery, int64, error) {
+	var deliveries []entity.WebhookDelivery
+	for _, delivery := range m.deliveries {
+		if delivery.OrganizationID == filter.OrganizationID {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	return deliveries, int64(len(deliveries)), nil
+}
+
+type stubSender struct {
+	status  int
+	err     error
+	sent    []entity.WebhookDelivery
+	secrets []string
+}
+
+func (s *stubSender) Send(ctx context.Context, endpoint *entity.WebhookEndpoint, delivery *entity.WebhookDelivery) (*Response, error) {
+	s.sent = append(s.sent, *delivery)
+	s.secrets = append(s.secrets, endpoint.Secret)
+	return &Response{StatusCode: s.status, Body: "ok", Duration: 15 * time.Millisecond}, s.err
+}
+
+// stubSubscriptionRepository returns the subscription of listed organizations,
+// and fallback for the others when it is set
+type stubSubscriptionRepository struct {
+	repository.SubscriptionRepository
+	subs     map[uuid.UUID]*entity.Subscription
+	fallback *entity.Subscription
+}
+
+func (s *stubSubscriptionRepository) GetByOrganization(ctx context.Context, orgID uuid.UUID) (*entity.Subscription, error) {
+	if sub, ok := s.subs[orgID]; ok {
+		return sub, nil
+	}
+	if s.fallback != nil {
+		return s.fallback, nil
+	}
+	return nil, errors.New("record not found")
+}
+
+func newTestService(sender Sender) (*Service, *mockEndpointRepository, *mockDeliveryRepository) {
+	endpoints := newMockEndpointRepo()
+	deliveries := newMockDeliveryRepo()
+	subscriptions := &stubSubscriptionRepository{fallback: &entity.Subscription{PlanTier: entity.PlanTierPro}}
+	return NewService(endpoints, deliveries, subscriptions, sender), endpoints, deliveries
+}
+
+func addEndpoint(repo *mockEndpointRepository, orgID uuid.UUID, active bool, events ...entity.WebhookEventType) *entity.WebhookEndpoint {
+	endpoint := &entity.WebhookEndpoint{
+		BaseEntity:     entity.NewBaseEntity(),
+		OrganizationID: orgID,
+		URL:            "https://example.com/hooks",
+		Secret:         "whsec_test",
+		Events:         events,
+		IsActive:       active,
+	}
+	repo.endpoints[endpoint.ID] = endpoint
+	return endpoint
+}
+
+func TestService_Publish(t *testing.T) {
+	orgID := uuid.New()
+	sender := &stubSender{status: 200}
+	service, endpoints, deliveries := newTestService(sender)
+	subscribed := addEndpoint(endpoints, orgID, true, entity.WebhookEventSyncCompleted, entity.WebhookEventSyncFailed)
+	addEndpoint(endpoints, orgID, true, entity.WebhookEventReportReady)
+	addEndpoint(endpoints, orgID, false, entity.WebhookEventSyncCompleted)
+	addEndpoint(endpoints, uuid.New(), true, entity.WebhookEventSyncCompleted)
+
+	published, err := service.Publish(context.Background(), orgID, entity.WebhookEventSyncCompleted, SyncData{SyncJobID: "job-1"})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(published) != 1 || published[0].EndpointID != subscribed.ID {
+		t.Fatalf("published %d deliveries, want one to the subscribed endpoint", len(published))
+	}
+
+	// Publishing only queues the delivery
+	delivery := deliveries.deliveries[published[0].ID]
+	if delivery.Status != entity.WebhookDeliveryPending || delivery.Attempts != 0 || len(sender.sent) != 0 {
+		t.Fatalf("delivery = %s after %d attempts, want pending and not sent", delivery.Status, delivery.Attempts)
+	}
+
+	if _, err := service.DeliverDue(context.Background(), time.Now(), 10); err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+	delivery = deliveries.deliveries[published[0].ID]
+	if delivery.Status != entity.WebhookDeliverySucceeded || delivery.Attempts != 1 || delivery.DeliveredAt == nil {
+		t.Errorf("delivery = %s after %d attempts, want succeeded after 1", delivery.Status, delivery.Attempts)
+	}
+	if delivery.ResponseStatus != 200 || delivery.DurationMs != 15 {
+		t.Errorf("response = %d in %dms, want 200 in 15ms", delivery.ResponseStatus, delivery.DurationMs)
+	}
+
+	var payload Payload
+	if err := json.Unmarshal(delivery.Payload, &payload); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if payload.ID != delivery.EventID.String() || payload.Event != "sync.completed" || payload.OrganizationID != orgID.String() {
+		t.Errorf("payload = %+v, want the event ID, type and organization", payload)
+	}
+}
+
+func TestService_PublishPlanGating(t *testing.T) {
+	freeOrg, proOrg, noSubOrg := uuid.New(), uuid.New(), uuid.New()
+	sender := &stubSender{status: 200}
+	service, endpoints, _ := newTestService(sender)
+	service.subscriptionRepo = &stubSubscriptionRepository{subs: map[uuid.UUID]*entity.Subscription{
+		freeOrg: {PlanTier: entity.PlanTierFree},
+		proOrg:  {PlanTier: entity.PlanTierPro},
+	}}
+	for _, orgID := range []uuid.UUID{freeOrg, proOrg, noSubOrg} {
+		addEndpoint(endpoints, orgID, true, entity.WebhookEventReportReady)
+	}
+
+	for _, tt := range []struct {
+		name  string
+		orgID uuid.UUID
+		want  int
+	}{
+		{"free plan", freeOrg, 0},
+		{"pro plan", proOrg, 1},
+		{"no subscription", noSubOrg, 0},
+	} {
+		published, err := service.Publish(context.Background(), tt.orgID, entity.WebhookEventReportReady, ReportReadyData{})
+		if err != nil {
+			t.Fatalf("%s: Publish() error = %v", tt.name, err)
+		}
+		if len(published) != tt.want {
+			t.Errorf("%s: published %d deliveries, want %d", tt.name, len(published), tt.want)
+		}
+	}
+}
+
+func TestService_CreateEndpointPlanGating(t *testing.T) {
+	freeOrg := uuid.New()
+	service, _, _ := newTestService(&stubSender{status: 200})
+	service.subscriptionRepo = &stubSubscriptionRepository{subs: map[uuid.UUID]*entity.Subscription{
+		freeOrg: {PlanTier: entity.PlanTierFree},
+	}}
+	input := EndpointInput{URL: "https://example.com/hooks", Events: []entity.WebhookEventType{entity.WebhookEventReportReady}}
+
+	if _, err := service.CreateEndpoint(context.Background(), freeOrg, uuid.New(), input); appErrors.GetHTTPStatus(err) != 403 {
+		t.Errorf("CreateEndpoint() on the free plan error = %v, want forbidden", err)
+	}
+
+	service.subscriptionRepo = nil
+	if _, err := service.CreateEndpoint(context.Background(), uuid.New(), uuid.New(), input); appErrors.GetHTTPStatus(err) != 403 {
+		t.Errorf("CreateEndpoint() without a subscription repository error = %v, want forbidden", err)
+	}
+}
+
+func TestService_PublishRejectsUnknownEvent(t *testing.T) {
+	service, _, _ := newTestService(&stubSender{status: 200})
+
+	if _, err := service.Publish(context.Background(), uuid.New(), entity.WebhookEventType("campaign.deleted"), nil); err == nil {
+		t.Error("Publish() error = nil, want error for unknown event type")
+	}
+}
+
+func TestService_RetryBackoff(t *testing.T) {
+	orgID := uuid.New()
+	sender := &stubSender{status: 500, err: errors.New("webhook returned status 500")}
+	service, endpoints, deliveries := newTestService(sender)
+	addEndpoint(endpoints, orgID, true, entity.WebhookEventTokenExpired)
+
+	published, err := service.Publish(context.Background(), orgID, entity.WebhookEventTokenExpired, TokenExpiredData{})
+	if err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if len(published) != 1 {
+		t.Fatalf("published %d deliveries, want 1", len(published))
+	}
+	id := published[0].ID
+
+	if _, err := service.DeliverDue(context.Background(), time.Now(), 10); err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+	first := deliveries.deliveries[id]
+	if first.Status != entity.WebhookDeliveryPending || first.NextAttemptAt == nil {
+		t.Fatalf("delivery = %s, want pending with a next attempt", first.Status)
+	}
+	if got := first.NextAttemptAt.Sub(*first.LastAttemptAt); got != retryBackoff[0] {
+		t.Errorf("first retry after %v, want %v", got, retryBackoff[0])
+	}
+	if first.ResponseStatus != 500 || first.Error == "" {
+		t.Errorf("response = %d with error %q, want 500 with an error", first.ResponseStatus, first.Error)
+	}
+
+	now := *first.NextAttemptAt
+	for attempt := 2; attempt <= maxAttempts; attempt++ {
+		due, err := service.DeliverDue(context.Background(), now, 10)
+		if err != nil {
+			t.Fatalf("DeliverDue() error = %v", err)
+		}
+		if len(due) != 1 {
+			t.Fatalf("attempt %d: %d deliveries due, want 1", attempt, len(due))
+		}
+		delivery := deliveries.deliveries[id]
+		if delivery.Attempts != attempt {
+			t.Fatalf("attempts = %d, want %d", delivery.Attempts, attempt)
+		}
+		if attempt < maxAttempts {
+			if got := delivery.NextAttemptAt.Sub(now); got != retryBackoff[attempt-1] {
+				t.Errorf("retry %d after %v, want %v", attempt, got, retryBackoff[attempt-1])
+			}
+			now = *delivery.NextAttemptAt
+		}
+	}
+
+	final := deliveries.deliveries[id]
+	if final.Status != entity.WebhookDeliveryFailed || final.NextAttemptAt != nil {
+		t.Errorf("delivery = %s after %d attempts, want failed with no next attempt", final.Status, final.Attempts)
+	}
+	if due, _ := service.DeliverDue(context.Background(), now.Add(24*time.Hour), 10); len(due) != 0 {
+		t.Errorf("%d failed deliveries retried, want 0", len(due))
+	}
+}
+
+func TestService_DeliverDueDisabledEndpoint(t *testing.T) {
+	orgID := uuid.New()
+	sender := &stubSender{status: 503, err: errors.New("webhook returned status 503")}
+	service, endpoints, deliveries := newTestService(sender)
+	endpoint := addEndpoint(endpoints, orgID, true, entity.WebhookEventAlertTriggered)
+
+	published, _ := service.Publish(context.Background(), orgID, entity.WebhookEventAlertTriggered, map[string]string{})
+	service.DeliverDue(context.Background(), time.Now(), 10)
+	endpoint.IsActive = false
+
+	due, err := service.DeliverDue(context.Background(), time.Now().Add(time.Hour), 10)
+	if err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+	if len(due) != 1 || due[0].Status != entity.WebhookDeliveryFailed {
+		t.Fatalf("due = %+v, want the delivery marked failed", due)
+	}
+	if len(sender.sent) != 1 {
+		t.Errorf("sent %d requests, want only the first attempt", len(sender.sent))
+	}
+	if deliveries.deliveries[published[0].ID].Status != entity.WebhookDeliveryFailed {
+		t.Error("stored delivery not marked failed")
+	}
+}
+
+func TestService_ReplayDelivery(t *testing.T) {
+	orgID := uuid.New()
+	sender := &stubSender{status: 200}
+	service, endpoints, _ := newTestService(sender)
+	endpoint := addEndpoint(endpoints, orgID, true, entity.WebhookEventReportReady)
+
+	published, _ := service.Publish(context.Background(), orgID, entity.WebhookEventReportReady, ReportReadyData{ReportID: "r-1"})
+	original := published[0]
+
+	replay, err := service.ReplayDelivery(context.Background(), orgID, original.ID)
+	if err != nil {
+		t.Fatalf("ReplayDelivery() error = %v", err)
+	}
+	if replay.ID == original.ID || replay.ReplayOf == nil || *replay.ReplayOf != original.ID {
+		t.Errorf("replay = %s replaying %v, want a new delivery replaying %s", replay.ID, replay.ReplayOf, original.ID)
+	}
+	if replay.EventID != original.EventID || string(replay.Payload) != string(original.Payload) {
+		t.Error("replay does not share the original event ID and payload")
+	}
+	if replay.Status != entity.WebhookDeliverySucceeded || len(sender.sent) != 1 {
+		t.Errorf("replay = %s after %d requests, want sent right away and succeeded", replay.Status, len(sender.sent))
+	}
+
+	if _, err := service.ReplayDelivery(context.Background(), uuid.New(), original.ID); err == nil {
+		t.Error("ReplayDelivery() of another organization's delivery error = nil, want not found")
+	}
+
+	endpoint.IsActive = false
+	if _, err := service.ReplayDelivery(context.Background(), orgID, original.ID); err == nil {
+		t.Error("ReplayDelivery() to a disabled endpoint error = nil, want validation error")
+	}
+}
+
+func TestService_CreateEndpoint(t *testing.T) {
+	orgID := uuid.New()
+	service, _, _ := newTestService(&stubSender{status: 200})
+
+	endpoint, err := service.CreateEndpoint(context.Background(), orgID, uuid.New(), EndpointInput{
+		URL:    " https://example.com/hooks ",
+		Events: []entity.WebhookEventType{entity.WebhookEventSyncFailed, entity.WebhookEventSyncFailed, entity.WebhookEventTokenExpired},
+	})
+	if err != nil {
+		t.Fatalf("CreateEndpoint() error = %v", err)
+	}
+	if endpoint.URL != "https://example.com/hooks" || !endpoint.IsActive {
+		t.Errorf("endpoint = %q active=%v, want trimmed URL and active", endpoint.URL, endpoint.IsActive)
+	}
+	if len(endpoint.Events) != 2 {
+		t.Errorf("events = %v, want duplicates removed", endpoint.Events)
+	}
+	if len(endpoint.Secret) < 32 {
+		t.Errorf("secret = %q, want a generated secret", endpoint.Secret)
+	}
+
+	secret := endpoint.Secret
+	rotated, err := service.RotateSecret(context.Background(), orgID, endpoint.ID)
+	if err != nil {
+		t.Fatalf("RotateSecret() error = %v", err)
+	}
+	if rotated.Secret == "" || rotated.Secret == secret {
+		t.Error("RotateSecret() did not replace the secret")
+	}
+
+	invalid := []EndpointInput{
+		{URL: "ftp://example.com", Events: []entity.WebhookEventType{entity.WebhookEventSyncFailed}},
+		{URL: "example.com/hooks", Events: []entity.WebhookEventType{entity.WebhookEventSyncFailed}},
+		{URL: "https://example.com/hooks"},
+		{URL: "https://example.com/hooks", Events: []entity.WebhookEventType{"campaign.deleted"}},
+		{URL: "http://localhost:8080/hooks", Events: []entity.WebhookEventType{entity.WebhookEventSyncFailed}},
+		{URL: "http://169.254.169.254/latest/meta-data", Events: []entity.WebhookEventType{entity.WebhookEventSyncFailed}},
+		{URL: "https://10.0.0.5/hooks", Events: []entity.WebhookEventType{entity.WebhookEventSyncFailed}},
+	}
+	for _, input := range invalid {
+		if _, err := service.CreateEndpoint(context.Background(), orgID, uuid.New(), input); err == nil {
+			t.Errorf("CreateEndpoint(%+v) error = nil, want validation error", input)
+		}
+	}
+}
+
+func TestService_EndpointSecretIsEncryptedAndHidden(t *testing.T) {
+	orgID := uuid.New()
+	sender := &stubSender{status: 200}
+	service, endpoints, _ := newTestService(sender)
+	encryptor, err := crypto.NewTokenEncryptor("12345678901234567890123456789012")
+	if err != nil {
+		t.Fatal(err)
+	}
+	service.SetSecretEncryptor(encryptor)
+
+	created, err := service.CreateEndpoint(context.Background(), orgID, uuid.New(), EndpointInput{
+		URL:    "https://example.com/hooks",
+		Events: []entity.WebhookEventType{entity.WebhookEventSyncFailed},
+	})
+	if err != nil {
+		t.Fatalf("CreateEndpoint() error = %v", err)
+	}
+	if stored := endpoints.endpoints[created.ID].Secret; stored == "" || stored == created.Secret {
+		t.Errorf("stored secret = %q, want the secret encrypted", stored)
+	}
+
+	// Only the create and rotate responses include the secret
+	body, _ := json.Marshal(created)
+	if !strings.Contains(string(body), created.Secret) {
+		t.Error("create response does not include the secret")
+	}
+	listed, _ := service.ListEndpoints(context.Background(), orgID)
+	body, _ = json.Marshal(listed)
+	if strings.Contains(string(body), "secret") {
+		t.Errorf("listed endpoints = %s, want no secret", body)
+	}
+
+	// Deliveries are signed with the decrypted secret
+	if _, err := service.Publish(context.Background(), orgID, entity.WebhookEventSyncFailed, map[string]string{}); err != nil {
+		t.Fatalf("Publish() error = %v", err)
+	}
+	if _, err := service.DeliverDue(context.Background(), time.Now(), 10); err != nil {
+		t.Fatalf("DeliverDue() error = %v", err)
+	}
+	if len(sender.secrets) != 1 || sender.secrets[0] != created.Secret {
+		t.Errorf("sender got secrets %v, want the plain secret", sender.secrets)
+	}
+}
@@ -0,0 +1,211 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ads-aggregator/ads-aggregator/pkg/errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// ReportProcessor builds queued reports. It is implemented by the analytics service.
+type ReportProcessor interface {
+	ProcessNextReportJob(ctx context.Context, workerID string) (bool, error)
+	RequeueStaleReportJobs(ctx context.Context, timeout time.Duration) (int64, error)
+	CleanupExpiredReports(ctx context.Context) (int64, error)
+}
+
+// ReportWorkerConfig holds configuration for the report worker
+type ReportWorkerConfig struct {
+	// MaxConcurrency is the number of reports built in parallel
+	MaxConcurrency int
+	// PollInterval is how long an idle worker waits before checking for new jobs
+	PollInterval time.Duration
+	// JobTimeout bounds the time spent building a single report
+	JobTimeout time.Duration
+	// StaleAfter is how long a running job may go without completing before it is requeued
+	StaleAfter time.Duration
+	// CleanupInterval is how often expired report artifacts are deleted
+	CleanupInterval time.Duration
+}
+
+// DefaultReportWorkerConfig returns production-ready defaults for report generation
+func DefaultReportWorkerConfig() *ReportWorkerConfig {
+	return &ReportWorkerConfig{
+		MaxConcurrency:  2,
+		PollInterval:    5 * time.Second,
+		JobTimeout:      10 * time.Minute,
+		StaleAfter:      30 * time.Minute,
+		CleanupInterval: time.Hour,
+	}
+}
+
+// ReportWorker polls for queued report jobs and builds them in the background
+type ReportWorker struct {
+	config    *ReportWorkerConfig
+	processor ReportProcessor
+	workerID  string
+	logger    zerolog.Logger
+	isRunning atomic.Bool
+	processed atomic.Int64
+	failed    atomic.Int64
+	stopChan  chan struct{}
+}
+
+// NewReportWorker creates a new report worker
+func NewReportWorker(config *ReportWorkerConfig, processor ReportProcessor, logger zerolog.Logger) *ReportWorker {
+	if config == nil {
+		config = DefaultReportWorkerConfig()
+	}
+
+	hostname, _ := os.Hostname()
+	workerID := fmt.Sprintf("%s-%s", hostname, uuid.New().String()[:8])
+
+	return &ReportWorker{
+		config:    config,
+		processor: processor,
+		workerID:  workerID,
+		logger:    logger.With().Str("worker", "report").Str("worker_id", workerID).Logger(),
+		stopChan:  make(chan struct{}),
+	}
+}
+
+// Start starts the report worker pool and the maintenance loop
+func (w *ReportWorker) Start(ctx context.Context) error {
+	if w.isRunning.Load() {
+		return errors.ErrBadRequest("Worker is already running")
+	}
+
+	w.isRunning.Store(true)
+	w.logger.Info().
+		Int("concurrency", w.config.MaxConcurrency).
+		Dur("poll_interval", w.config.PollInterval).
+		Msg("Starting report worker pool")
+
+	var wg sync.WaitGroup
+	for i := 0; i < w.config.MaxConcurrency; i++ {
+		wg.Add(1)
+		go w.poll(ctx, &wg, fmt.Sprintf("%s-%d", w.workerID, i))
+	}
+
+	wg.Add(1)
+	go w.maintain(ctx, &wg)
+
+	go func() {
+		wg.Wait()
+		w.isRunning.Store(false)
+		w.logger.Info().Msg("Report worker pool stopped")
+	}()
+
+	return nil
+}
+
+// Stop gracefully stops the worker pool
+func (w *ReportWorker) Stop() {
+	if !w.isRunning.Load() {
+		return
+	}
+
+	w.logger.Info().Msg("Stopping report worker pool")
+	close(w.stopChan)
+}
+
+// IsRunning returns true if the worker is currently running
+func (w *ReportWorker) IsRunning() bool {
+	return w.isRunning.Load()
+}
+
+// GetStats returns worker statistics
+func (w *ReportWorker) GetStats() map[string]interface{} {
+	return map[string]interface{}{
+		"is_running": w.isRunning.Load(),
+		"processed":  w.processed.Load(),
+		"failed":     w.failed.Load(),
+	}
+}
+
+// poll processes jobs until the queue is empty, then sleeps for the poll interval
+func (w *ReportWorker) poll(ctx context.Context, wg *sync.WaitGroup, workerID string) {
+	defer wg.Done()
+
+	for {
+		for w.processOne(ctx, workerID) {
+			select {
+			case <-ctx.Done():
+				return
+			case <-w.stopChan:
+				return
+			default:
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopChan:
+			return
+		case <-time.After(w.config.PollInterval):
+		}
+	}
+}
+
+// processOne builds a single report and reports whether a job was claimed
+func (w *ReportWorker) processOne(ctx context.Context, workerID string) bool {
+	jobCtx, cancel := context.WithTimeout(ctx, w.config.JobTimeout)
+	defer cancel()
+
+	start := time.Now()
+	claimed, err := w.processor.ProcessNextReportJob(jobCtx, workerID)
+	if err != nil {
+		w.logger.Error().Err(err).Msg("Report job failed")
+		if claimed {
+			w.failed.Add(1)
+		}
+		return claimed
+	}
+
+	if claimed {
+		w.processed.Add(1)
+		w.logger.Info().Dur("duration", time.Since(start)).Msg("Report job completed")
+	}
+	return claimed
+}
+
+// maintain periodically requeues stale jobs and deletes expired artifacts
+func (w *ReportWorker) maintain(ctx context.Context, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	ticker := time.NewTicker(w.config.CleanupInterval)
+	defer ticker.Stop()
+
+	w.runMaintenance(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stopChan:
+			return
+		case <-ticker.C:
+			w.runMaintenance(ctx)
+		}
+	}
+}
+
+func (w *ReportWorker) runMaintenance(ctx context.Context) {
+	if requeued, err := w.processor.RequeueStaleReportJobs(ctx, w.config.StaleAfter); err != nil {
+		w.logger.Error().Err(err).Msg("Failed to requeue stale report jobs")
+	} else if requeued > 0 {
+		w.logger.Warn().Int64("count", requeued).Msg("Requeued or failed stale report jobs")
+	}
+
+	if deleted, err := w.processor.CleanupExpiredReports(ctx); err != nil {
+		w.logger.Error().Err(err).Msg("Failed to clean up expired reports")
+	} else if deleted > 0 {
+		w.logger.Info().Int64("count", deleted).Msg("Deleted expired report artifacts")
+	}
+}
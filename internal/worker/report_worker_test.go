@@ -0,0 +1,82 @@
+package worker
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type mockReportProcessor struct {
+	pending   atomic.Int32
+	processed atomic.Int32
+	cleanups  atomic.Int32
+}
+
+func (m *mockReportProcessor) ProcessNextReportJob(ctx context.Context, workerID string) (bool, error) {
+	if m.pending.Add(-1) < 0 {
+		m.pending.Add(1)
+		return false, nil
+	}
+	m.processed.Add(1)
+	return true, nil
+}
+
+func (m *mockReportProcessor) RequeueStaleReportJobs(ctx context.Context, timeout time.Duration) (int64, error) {
+	return 0, nil
+}
+
+func (m *mockReportProcessor) CleanupExpiredReports(ctx context.Context) (int64, error) {
+	m.cleanups.Add(1)
+	return 0, nil
+}
+
+func TestReportWorker_ProcessesQueue(t *testing.T) {
+	processor := &mockReportProcessor{}
+	processor.pending.Store(5)
+
+	config := &ReportWorkerConfig{
+		MaxConcurrency:  2,
+		PollInterval:    10 * time.Millisecond,
+		JobTimeout:      time.Second,
+		StaleAfter:      time.Minute,
+		CleanupInterval: time.Hour,
+	}
+	w := NewReportWorker(config, processor, zerolog.Nop())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := w.Start(ctx); err != nil {
+		t.Fatalf("Start failed: %v", err)
+	}
+	if err := w.Start(ctx); err == nil {
+		t.Error("expected error when starting a running worker")
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for processor.processed.Load() < 5 && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if got := processor.processed.Load(); got != 5 {
+		t.Errorf("expected 5 processed jobs, got %d", got)
+	}
+	if processor.cleanups.Load() == 0 {
+		t.Error("expected cleanup to run on start")
+	}
+
+	w.Stop()
+	deadline = time.Now().Add(time.Second)
+	for w.IsRunning() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if w.IsRunning() {
+		t.Error("worker should stop")
+	}
+	if stats := w.GetStats(); stats["processed"].(int64) != 5 {
+		t.Errorf("unexpected stats %v", stats)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/shopspring/decimal"
 )
@@ -156,6 +157,21 @@ func (c *Converter) Convert(amount decimal.Decimal, from, to string) (decimal.De
 	return amount.Mul(rate), nil
 }
 
+// ConvertAt converts an amount at the rate of the given date. Providers without
+// historical rates use their current rate.
+func (c *Converter) ConvertAt(amount decimal.Decimal, from, to string, date time.Time) (decimal.Decimal, error) {
+	if amount.IsZero() {
+		return decimal.Zero, nil
+	}
+
+	rate, err := c.GetRateAt(from, to, date)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	return amount.Mul(rate), nil
+}
+
 // ConvertWithDefault converts an amount, returning a default value on error
 func (c *Converter) ConvertWithDefault(amount decimal.Decimal, from, to string, defaultValue decimal.Decimal) decimal.Decimal {
 	result, err := c.Convert(amount, from, to)
@@ -179,6 +195,14 @@ func (c *Converter) GetRate(from, to string) (decimal.Decimal, error) {
 	return c.provider.GetRate(from, to)
 }
 
+// GetRateAt returns the exchange rate between two currencies on the given date
+func (c *Converter) GetRateAt(from, to string, date time.Time) (decimal.Decimal, error) {
+	if historical, ok := c.provider.(HistoricalRateProvider); ok {
+		return historical.GetRateAt(from, to, date)
+	}
+	return c.provider.GetRate(from, to)
+}
+
 // GetSupportedCurrencies returns supported currency codes
 func (c *Converter) GetSupportedCurrencies() []string {
 	return c.provider.GetSupportedCurrencies()
@@ -0,0 +1,190 @@
+package currency
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// European Central Bank reference rate feeds. Rates are quoted against EUR and
+// published on TARGET business days around 16:00 CET.
+const (
+	ECBBaseCurrency = "EUR"
+	ECBDailyURL     = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-daily.xml"
+	ECB90DayURL     = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist-90d.xml"
+	ECBHistoryURL   = "https://www.ecb.europa.eu/stats/eurofxref/eurofxref-hist.xml"
+)
+
+// ECBSource reads ECB-style reference rates, in either the XML or the CSV
+// format, from a URL or a local file
+type ECBSource struct {
+	location string
+	client   *http.Client
+}
+
+// NewECBSource creates a source for an http(s) URL or a local file path
+func NewECBSource(location string) *ECBSource {
+	return &ECBSource{
+		location: location,
+		client:   &http.Client{Timeout: 60 * time.Second},
+	}
+}
+
+// FetchRates downloads and parses the feed
+func (s *ECBSource) FetchRates(ctx context.Context) ([]DatedRate, error) {
+	body, err := s.open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	return ParseECBRates(body)
+}
+
+func (s *ECBSource) open(ctx context.Context) (io.ReadCloser, error) {
+	if !strings.HasPrefix(s.location, "http://") && !strings.HasPrefix(s.location, "https://") {
+		return os.Open(strings.TrimPrefix(s.location, "file://"))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.location, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("exchange rate feed returned status %d", resp.StatusCode)
+	}
+	return resp.Body, nil
+}
+
+// ParseECBRates parses an ECB feed, detecting whether it is XML or CSV
+func ParseECBRates(r io.Reader) ([]DatedRate, error) {
+	br := bufio.NewReader(r)
+	head, _ := br.Peek(512)
+	head = bytes.TrimLeft(bytes.TrimPrefix(head, []byte("\xef\xbb\xbf")), " \t\r\n")
+
+	if bytes.HasPrefix(head, []byte("<")) {
+		return ParseECBXML(br)
+	}
+	return ParseECBCSV(br)
+}
+
+// ecbEnvelope is the eurofxref XML document
+type ecbEnvelope struct {
+	Cube struct {
+		Days []struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// ParseECBXML parses the eurofxref XML format
+func ParseECBXML(r io.Reader) ([]DatedRate, error) {
+	var doc ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("invalid ECB XML: %w", err)
+	}
+
+	var rates []DatedRate
+	for _, day := range doc.Cube.Days {
+		date, err := time.Parse("2006-01-02", day.Time)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ECB rate date %q", day.Time)
+		}
+		for _, r := range day.Rates {
+			rate, err := decimal.NewFromString(strings.TrimSpace(r.Rate))
+			if err != nil {
+				return nil, fmt.Errorf("invalid ECB rate %q for %s on %s", r.Rate, r.Currency, day.Time)
+			}
+			rates = append(rates, DatedRate{Date: date, Base: ECBBaseCurrency, Quote: strings.ToUpper(r.Currency), Rate: rate})
+		}
+	}
+
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("ECB XML has no rates")
+	}
+	return rates, nil
+}
+
+// ParseECBCSV parses the eurofxref CSV format: a Date column followed by one
+// column per currency. Currencies without a rate that day are "N/A" or empty.
+func ParseECBCSV(r io.Reader) ([]DatedRate, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("invalid ECB CSV: %w", err)
+	}
+	if len(header) < 2 || !strings.EqualFold(strings.TrimSpace(header[0]), "date") {
+		return nil, fmt.Errorf("invalid ECB CSV: first column must be Date")
+	}
+
+	var rates []DatedRate
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid ECB CSV: %w", err)
+		}
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		date, err := parseECBDate(strings.TrimSpace(record[0]))
+		if err != nil {
+			return nil, err
+		}
+		for i := 1; i < len(record) && i < len(header); i++ {
+			code := strings.ToUpper(strings.TrimSpace(header[i]))
+			value := strings.TrimSpace(record[i])
+			if code == "" || value == "" || value == "N/A" {
+				continue
+			}
+			rate, err := decimal.NewFromString(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid ECB rate %q for %s on %s", value, code, record[0])
+			}
+			rates = append(rates, DatedRate{Date: date, Base: ECBBaseCurrency, Quote: code, Rate: rate})
+		}
+	}
+
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("ECB CSV has no rates")
+	}
+	return rates, nil
+}
+
+// parseECBDate accepts the ISO dates of the history file and the "02 September 2024" of the daily file
+func parseECBDate(value string) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "02 January 2006"} {
+		if date, err := time.Parse(layout, value); err == nil {
+			return date, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid ECB rate date %q", value)
+}
+
+var _ RateSource = (*ECBSource)(nil)
@@ -0,0 +1,220 @@
+package currency
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// DatedRate is the number of Quote units one Base unit bought on Date
+type DatedRate struct {
+	Date  time.Time
+	Base  string
+	Quote string
+	Rate  decimal.Decimal
+}
+
+// HistoricalRateProvider is an ExchangeRateProvider that also knows past rates
+type HistoricalRateProvider interface {
+	ExchangeRateProvider
+	GetRateAt(from, to string, date time.Time) (decimal.Decimal, error)
+}
+
+// RateStore persists dated exchange rates
+type RateStore interface {
+	SaveRates(ctx context.Context, rates []DatedRate) error
+	LoadRates(ctx context.Context, base string) ([]DatedRate, error)
+}
+
+// RateSource fetches dated exchange rates from an external feed
+type RateSource interface {
+	FetchRates(ctx context.Context) ([]DatedRate, error)
+}
+
+// DatedRateProvider converts at the rate published on or before a given date.
+// Rates are kept in memory and persisted through a RateStore; pairs it has no
+// rate for (including dates before its first rate) go to the fallback provider.
+type DatedRateProvider struct {
+	mu       sync.RWMutex
+	base     string
+	store    RateStore
+	fallback ExchangeRateProvider
+	days     []time.Time                              // Sorted ascending
+	rates    map[time.Time]map[string]decimal.Decimal // Units of each currency per base unit
+}
+
+// NewDatedRateProvider creates a provider for rates quoted against base.
+// store and fallback are optional.
+func NewDatedRateProvider(base string, store RateStore, fallback ExchangeRateProvider) *DatedRateProvider {
+	return &DatedRateProvider{
+		base:     strings.ToUpper(base),
+		store:    store,
+		fallback: fallback,
+		rates:    make(map[time.Time]map[string]decimal.Decimal),
+	}
+}
+
+// Load replaces the in-memory rates with the rates in the store
+func (p *DatedRateProvider) Load(ctx context.Context) error {
+	if p.store == nil {
+		return nil
+	}
+
+	rates, err := p.store.LoadRates(ctx, p.base)
+	if err != nil {
+		return fmt.Errorf("failed to load exchange rates: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.days = nil
+	p.rates = make(map[time.Time]map[string]decimal.Decimal)
+	p.addRates(rates)
+	return nil
+}
+
+// Import fetches rates from source, saves them to the store and makes them
+// available for conversion. It returns the number of rates imported.
+func (p *DatedRateProvider) Import(ctx context.Context, source RateSource) (int, error) {
+	rates, err := source.FetchRates(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch exchange rates: %w", err)
+	}
+
+	if p.store != nil && len(rates) > 0 {
+		if err := p.store.SaveRates(ctx, rates); err != nil {
+			return 0, fmt.Errorf("failed to save exchange rates: %w", err)
+		}
+	}
+
+	p.SetRates(rates)
+	return len(rates), nil
+}
+
+// SetRates adds rates to the in-memory table, replacing rates for the same day
+// and currency. Rates quoted against another base are ignored.
+func (p *DatedRateProvider) SetRates(rates []DatedRate) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.addRates(rates)
+}
+
+// addRates merges rates into the table; the caller must hold the write lock
+func (p *DatedRateProvider) addRates(rates []DatedRate) {
+	added := false
+	for _, r := range rates {
+		if !strings.EqualFold(r.Base, p.base) || !r.Rate.IsPositive() {
+			continue
+		}
+
+		day := rateDay(r.Date)
+		dayRates, ok := p.rates[day]
+		if !ok {
+			dayRates = map[string]decimal.Decimal{p.base: decimal.NewFromInt(1)}
+			p.rates[day] = dayRates
+			p.days = append(p.days, day)
+			added = true
+		}
+		dayRates[strings.ToUpper(r.Quote)] = r.Rate
+	}
+
+	if added {
+		sort.Slice(p.days, func(i, j int) bool { return p.days[i].Before(p.days[j]) })
+	}
+}
+
+// GetRate returns the latest exchange rate from one currency to another
+func (p *DatedRateProvider) GetRate(from, to string) (decimal.Decimal, error) {
+	return p.GetRateAt(from, to, time.Now())
+}
+
+// GetRateAt returns the exchange rate from one currency to another published on
+// or before date. Feeds skip weekends and holidays, so the rate of the last
+// business day before date applies.
+func (p *DatedRateProvider) GetRateAt(from, to string, date time.Time) (decimal.Decimal, error) {
+	from = strings.ToUpper(from)
+	to = strings.ToUpper(to)
+
+	if from == to {
+		return decimal.NewFromInt(1), nil
+	}
+
+	if rate, ok := p.lookup(from, to, date); ok {
+		return rate, nil
+	}
+
+	if p.fallback == nil {
+		return decimal.Zero, fmt.Errorf("no exchange rate from %s to %s on %s", from, to, date.Format("2006-01-02"))
+	}
+	return p.fallback.GetRate(from, to)
+}
+
+func (p *DatedRateProvider) lookup(from, to string, date time.Time) (decimal.Decimal, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	day := rateDay(date)
+	i := sort.Search(len(p.days), func(i int) bool { return p.days[i].After(day) })
+	if i == 0 {
+		return decimal.Zero, false
+	}
+
+	dayRates := p.rates[p.days[i-1]]
+	fromRate, fromOK := dayRates[from]
+	toRate, toOK := dayRates[to]
+	if !fromOK || !toOK {
+		return decimal.Zero, false
+	}
+
+	// One 'from' unit is 1/fromRate base units, which buy toRate/fromRate 'to' units
+	return toRate.Div(fromRate), true
+}
+
+// GetSupportedCurrencies returns the currencies of the latest rates and of the fallback provider
+func (p *DatedRateProvider) GetSupportedCurrencies() []string {
+	seen := make(map[string]bool)
+
+	p.mu.RLock()
+	if len(p.days) > 0 {
+		for code := range p.rates[p.days[len(p.days)-1]] {
+			seen[code] = true
+		}
+	}
+	p.mu.RUnlock()
+
+	if p.fallback != nil {
+		for _, code := range p.fallback.GetSupportedCurrencies() {
+			seen[strings.ToUpper(code)] = true
+		}
+	}
+
+	currencies := make([]string, 0, len(seen))
+	for code := range seen {
+		currencies = append(currencies, code)
+	}
+	sort.Strings(currencies)
+	return currencies
+}
+
+// LatestDate returns the most recent day rates are known for
+func (p *DatedRateProvider) LatestDate() (time.Time, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.days) == 0 {
+		return time.Time{}, false
+	}
+	return p.days[len(p.days)-1], true
+}
+
+// rateDay truncates t to its calendar day in UTC
+func rateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,198 @@
+package currency
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type memoryRateStore struct {
+	rates []DatedRate
+}
+
+func (s *memoryRateStore) SaveRates(ctx context.Context, rates []DatedRate) error {
+	s.rates = append(s.rates, rates...)
+	return nil
+}
+
+func (s *memoryRateStore) LoadRates(ctx context.Context, base string) ([]DatedRate, error) {
+	return s.rates, nil
+}
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestParseECBRates_Fixtures(t *testing.T) {
+	for _, fixture := range []string{"testdata/eurofxref-hist.xml", "testdata/eurofxref-hist.csv"} {
+		t.Run(fixture, func(t *testing.T) {
+			rates, err := NewECBSource(fixture).FetchRates(context.Background())
+			if err != nil {
+				t.Fatalf("FetchRates() error = %v", err)
+			}
+
+			// N/A rates are skipped
+			if len(rates) != 8 {
+				t.Fatalf("len(rates) = %d, want 8", len(rates))
+			}
+			found := false
+			for _, r := range rates {
+				if r.Base != "EUR" {
+					t.Errorf("Base = %q, want EUR", r.Base)
+				}
+				if r.Quote == "MYR" && r.Date.Equal(day(2024, 1, 2)) {
+					found = true
+					if !r.Rate.Equal(decimal.RequireFromString("5.0450")) {
+						t.Errorf("MYR rate = %v, want 5.0450", r.Rate)
+					}
+				}
+			}
+			if !found {
+				t.Error("expected a MYR rate on 2024-01-02")
+			}
+		})
+	}
+}
+
+func TestParseECBCSV_DailyDate(t *testing.T) {
+	rates, err := ParseECBCSV(strings.NewReader("Date, USD, MYR, \n02 September 2024, 1.1048, 4.8078, \n"))
+	if err != nil {
+		t.Fatalf("ParseECBCSV() error = %v", err)
+	}
+	if len(rates) != 2 || !rates[0].Date.Equal(day(2024, 9, 2)) {
+		t.Errorf("unexpected rates %+v", rates)
+	}
+
+	if _, err := ParseECBCSV(strings.NewReader("Currency,USD\n2024-09-02,1.1\n")); err == nil {
+		t.Error("expected error without a Date column")
+	}
+}
+
+func TestECBSource_HTTP(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeFile(w, r, "testdata/eurofxref-hist.xml")
+	}))
+	defer server.Close()
+
+	rates, err := NewECBSource(server.URL).FetchRates(context.Background())
+	if err != nil {
+		t.Fatalf("FetchRates() error = %v", err)
+	}
+	if len(rates) != 8 {
+		t.Errorf("len(rates) = %d, want 8", len(rates))
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+
+	if _, err := NewECBSource(failing.URL).FetchRates(context.Background()); err == nil {
+		t.Error("expected error for a failed download")
+	}
+}
+
+func TestDatedRateProvider_GetRateAt(t *testing.T) {
+	store := &memoryRateStore{}
+	provider := NewDatedRateProvider("EUR", store, NewStaticRateProvider())
+
+	imported, err := provider.Import(context.Background(), NewECBSource("testdata/eurofxref-hist.xml"))
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+	if imported != 8 || len(store.rates) != 8 {
+		t.Fatalf("imported %d, stored %d, want 8", imported, len(store.rates))
+	}
+
+	january := decimal.RequireFromString("5.0450").Div(decimal.RequireFromString("1.0956"))
+	july := decimal.RequireFromString("5.0684").Div(decimal.RequireFromString("1.0745"))
+
+	tests := []struct {
+		name string
+		from string
+		to   string
+		date time.Time
+		want decimal.Decimal
+	}{
+		{"publication day", "USD", "MYR", day(2024, 1, 2), january},
+		{"later day uses the last published rate", "usd", "myr", time.Date(2024, 3, 15, 18, 0, 0, 0, time.UTC), january},
+		{"newer rate", "USD", "MYR", day(2024, 7, 1), july},
+		{"after the last rate", "USD", "MYR", day(2025, 1, 1), july},
+		{"base currency", "EUR", "MYR", day(2024, 7, 1), decimal.RequireFromString("5.0684")},
+		{"same currency", "USD", "USD", day(2024, 7, 1), decimal.NewFromInt(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := provider.GetRateAt(tt.from, tt.to, tt.date)
+			if err != nil {
+				t.Fatalf("GetRateAt() error = %v", err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("GetRateAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Before the first rate and for currencies missing from the feed, the fallback applies
+	static := NewStaticRateProvider()
+	for _, tt := range []struct{ from, to string }{{"USD", "MYR"}, {"VND", "MYR"}} {
+		date := day(2023, 6, 1)
+		if tt.from == "VND" {
+			date = day(2024, 7, 1)
+		}
+		got, err := provider.GetRateAt(tt.from, tt.to, date)
+		want, _ := static.GetRate(tt.from, tt.to)
+		if err != nil || !got.Equal(want) {
+			t.Errorf("GetRateAt(%s, %s, %s) = %v, %v; want fallback %v", tt.from, tt.to, date.Format("2006-01-02"), got, err, want)
+		}
+	}
+
+	// Reloading from the store gives the same rates
+	reloaded := NewDatedRateProvider("EUR", store, nil)
+	if err := reloaded.Load(context.Background()); err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if got, _ := reloaded.GetRateAt("USD", "MYR", day(2024, 7, 2)); !got.Equal(july) {
+		t.Errorf("reloaded rate = %v, want %v", got, july)
+	}
+	if latest, ok := reloaded.LatestDate(); !ok || !latest.Equal(day(2024, 7, 1)) {
+		t.Errorf("LatestDate() = %v, %v", latest, ok)
+	}
+	if _, err := reloaded.GetRateAt("USD", "MYR", day(2023, 6, 1)); err == nil {
+		t.Error("expected error without a rate or fallback")
+	}
+}
+
+func TestConverter_ConvertAt(t *testing.T) {
+	provider := NewDatedRateProvider("EUR", nil, nil)
+	provider.SetRates([]DatedRate{
+		{Date: day(2024, 1, 2), Base: "EUR", Quote: "USD", Rate: decimal.NewFromInt(1)},
+		{Date: day(2024, 1, 2), Base: "EUR", Quote: "MYR", Rate: decimal.NewFromInt(4)},
+		{Date: day(2024, 7, 1), Base: "EUR", Quote: "USD", Rate: decimal.NewFromInt(1)},
+		{Date: day(2024, 7, 1), Base: "EUR", Quote: "MYR", Rate: decimal.NewFromInt(5)},
+	})
+	converter := NewConverter(provider)
+
+	amount := decimal.NewFromInt(100)
+	if got, _ := converter.ConvertAt(amount, "USD", "MYR", day(2024, 2, 1)); !got.Equal(decimal.NewFromInt(400)) {
+		t.Errorf("ConvertAt(February) = %v, want 400", got)
+	}
+	if got, _ := converter.ConvertAt(amount, "USD", "MYR", day(2024, 8, 1)); !got.Equal(decimal.NewFromInt(500)) {
+		t.Errorf("ConvertAt(August) = %v, want 500", got)
+	}
+
+	// Providers without history convert at their current rate
+	static := NewConverter(NewStaticRateProviderWithRates("MYR", map[string]decimal.Decimal{
+		"MYR": decimal.NewFromInt(1),
+		"USD": decimal.NewFromInt(4),
+	}))
+	if got, _ := static.ConvertAt(amount, "USD", "MYR", day(2020, 1, 1)); !got.Equal(decimal.NewFromInt(400)) {
+		t.Errorf("static ConvertAt() = %v, want 400", got)
+	}
+}
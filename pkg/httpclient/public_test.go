@@ -0,0 +1,88 @@
+package httpclient
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1::", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"10.0.0.1", false},
+		{"172.16.5.4", false},
+		{"192.168.1.1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"0.1.2.3", false},
+		{"100.64.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"224.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsPublicIP(net.ParseIP(tt.ip)); got != tt.want {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePublicURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		wantErr bool
+	}{
+		{"https://hooks.example.com/ads", false},
+		{"https://93.184.216.34/hook", false},
+		{"http://localhost:8080/hook", true},
+		{"http://LOCALHOST./hook", true},
+		{"http://api.localhost/hook", true},
+		{"http://127.0.0.1/hook", true},
+		{"http://169.254.169.254/latest/meta-data", true},
+		{"http://[::1]:9000/hook", true},
+		{"https://10.1.2.3/hook", true},
+	}
+
+	for _, tt := range tests {
+		parsed, err := url.Parse(tt.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = ValidatePublicURL(parsed)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidatePublicURL(%s) error = %v, wantErr %v", tt.url, err, tt.wantErr)
+		}
+		if err != nil && !errors.Is(err, ErrNonPublicAddress) {
+			t.Errorf("ValidatePublicURL(%s) error = %v, want ErrNonPublicAddress", tt.url, err)
+		}
+	}
+}
+
+func TestNewPublicClient_RefusesLoopback(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer server.Close()
+
+	_, err := NewPublicClient(time.Second).Get(server.URL)
+	if !errors.Is(err, ErrNonPublicAddress) {
+		t.Errorf("Get() error = %v, want ErrNonPublicAddress", err)
+	}
+	if called {
+		t.Error("request reached the loopback server")
+	}
+}
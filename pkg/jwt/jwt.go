@@ -16,6 +16,10 @@ type TokenType string
 const (
 	AccessToken  TokenType = "access"
 	RefreshToken TokenType = "refresh"
+
+	// MFAToken is a short-lived token proving the password step of a login,
+	// exchanged for a token pair once the second factor is verified
+	MFAToken TokenType = "mfa"
 )
 
 // Claims represents the JWT claims structure
@@ -27,6 +31,7 @@ type Claims struct {
 	Role           string    `json:"role,omitempty"`
 	TokenType      TokenType `json:"token_type"`
 	Permissions    []string  `json:"permissions,omitempty"`
+	SessionID      string    `json:"sid,omitempty"` // Server-side session the token belongs to
 }
 
 // Manager handles JWT token operations
@@ -54,22 +59,30 @@ type TokenPair struct {
 	AccessTokenExpiresAt  time.Time `json:"access_token_expires_at"`
 	RefreshTokenExpiresAt time.Time `json:"refresh_token_expires_at"`
 	TokenType             string    `json:"token_type"`
+	SessionID             string    `json:"-"`
+	RefreshTokenID        string    `json:"-"` // jti of the refresh token
 }
 
 // GenerateTokenPair generates both access and refresh tokens
 func (m *Manager) GenerateTokenPair(userID, orgID, email, role string, permissions []string) (*TokenPair, error) {
+	return m.GenerateSessionTokenPair("", userID, orgID, email, role, permissions)
+}
+
+// GenerateSessionTokenPair generates both access and refresh tokens of a
+// server-side session, identified in both tokens by the sid claim
+func (m *Manager) GenerateSessionTokenPair(sessionID, userID, orgID, email, role string, permissions []string) (*TokenPair, error) {
 	now := time.Now()
 
 	// Generate access token
 	accessExpiry := now.Add(m.accessTokenExpiry)
-	accessToken, err := m.generateToken(userID, orgID, email, role, permissions, AccessToken, accessExpiry)
+	accessToken, _, err := m.generateToken(sessionID, userID, orgID, email, role, permissions, AccessToken, accessExpiry)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate access token: %w", err)
 	}
 
 	// Generate refresh token
 	refreshExpiry := now.Add(m.refreshTokenExpiry)
-	refreshToken, err := m.generateToken(userID, orgID, email, role, nil, RefreshToken, refreshExpiry)
+	refreshToken, refreshTokenID, err := m.generateToken(sessionID, userID, orgID, email, role, nil, RefreshToken, refreshExpiry)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
 	}
@@ -80,29 +93,38 @@ func (m *Manager) GenerateTokenPair(userID, orgID, email, role string, permissio
 		AccessTokenExpiresAt:  accessExpiry,
 		RefreshTokenExpiresAt: refreshExpiry,
 		TokenType:             "Bearer",
+		SessionID:             sessionID,
+		RefreshTokenID:        refreshTokenID,
 	}, nil
 }
 
 // GenerateAccessToken generates only an access token
 func (m *Manager) GenerateAccessToken(userID, orgID, email, role string, permissions []string) (string, time.Time, error) {
 	expiry := time.Now().Add(m.accessTokenExpiry)
-	token, err := m.generateToken(userID, orgID, email, role, permissions, AccessToken, expiry)
+	token, _, err := m.generateToken("", userID, orgID, email, role, permissions, AccessToken, expiry)
 	return token, expiry, err
 }
 
 // GenerateRefreshToken generates only a refresh token
 func (m *Manager) GenerateRefreshToken(userID, orgID, email, role string) (string, time.Time, error) {
 	expiry := time.Now().Add(m.refreshTokenExpiry)
-	token, err := m.generateToken(userID, orgID, email, role, nil, RefreshToken, expiry)
+	token, _, err := m.generateToken("", userID, orgID, email, role, nil, RefreshToken, expiry)
 	return token, expiry, err
 }
 
-// generateToken generates a JWT token with the given claims
-func (m *Manager) generateToken(userID, orgID, email, role string, permissions []string, tokenType TokenType, expiry time.Time) (string, error) {
+// GenerateMFAToken generates an MFA challenge token valid for expiry
+func (m *Manager) GenerateMFAToken(userID, orgID, email, role string, expiry time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(expiry)
+	token, _, err := m.generateToken("", userID, orgID, email, role, nil, MFAToken, expiresAt)
+	return token, expiresAt, err
+}
+
+// generateToken generates a JWT token with the given claims and returns it with its token ID
+func (m *Manager) generateToken(sessionID, userID, orgID, email, role string, permissions []string, tokenType TokenType, expiry time.Time) (string, string, error) {
 	// Generate a unique token ID
 	jti, err := generateTokenID()
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 
 	claims := Claims{
@@ -120,10 +142,11 @@ func (m *Manager) generateToken(userID, orgID, email, role string, permissions [
 		Role:           role,
 		TokenType:      tokenType,
 		Permissions:    permissions,
+		SessionID:      sessionID,
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(m.secret)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	return token, jti, err
 }
 
 // ValidateToken validates a JWT token and returns the claims
@@ -185,6 +208,20 @@ func (m *Manager) ValidateRefreshToken(tokenString string) (*Claims, error) {
 	return claims, nil
 }
 
+// ValidateMFAToken validates an MFA challenge token
+func (m *Manager) ValidateMFAToken(tokenString string) (*Claims, error) {
+	claims, err := m.ValidateToken(tokenString)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenType != MFAToken {
+		return nil, ErrInvalidTokenType
+	}
+
+	return claims, nil
+}
+
 // RefreshTokenPair refreshes an access token using a refresh token
 func (m *Manager) RefreshTokenPair(refreshTokenString string) (*TokenPair, error) {
 	claims, err := m.ValidateRefreshToken(refreshTokenString)
@@ -192,8 +229,9 @@ func (m *Manager) RefreshTokenPair(refreshTokenString string) (*TokenPair, error
 		return nil, err
 	}
 
-	// Generate new token pair with the same user info
-	return m.GenerateTokenPair(
+	// Generate new token pair with the same user info and session
+	return m.GenerateSessionTokenPair(
+		claims.SessionID,
 		claims.UserID,
 		claims.OrganizationID,
 		claims.Email,
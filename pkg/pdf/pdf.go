@@ -0,0 +1,345 @@
+// Package pdf provides a minimal PDF 1.4 document writer for generated reports.
+// It supports multiple pages, the standard Helvetica fonts, filled rectangles,
+// lines and polylines, which is enough for tabular reports and simple charts
+// without pulling in a full layout engine.
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Standard page sizes in points (1/72 inch)
+const (
+	A4Width  = 595.28
+	A4Height = 841.89
+)
+
+// Font identifies one of the built-in standard fonts
+type Font int
+
+const (
+	FontRegular Font = iota
+	FontBold
+)
+
+// resourceName returns the font resource name used in content streams
+func (f Font) resourceName() string {
+	if f == FontBold {
+		return "F2"
+	}
+	return "F1"
+}
+
+// Color is an RGB color
+type Color struct {
+	R, G, B uint8
+}
+
+// Common colors
+var (
+	Black     = Color{0, 0, 0}
+	White     = Color{255, 255, 255}
+	Gray      = Color{110, 110, 110}
+	LightGray = Color{225, 225, 225}
+)
+
+// ParseHexColor parses a "#RRGGBB" or "RRGGBB" color string
+func ParseHexColor(s string) (Color, bool) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	if len(s) != 6 {
+		return Color{}, false
+	}
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil {
+		return Color{}, false
+	}
+	return Color{uint8(v >> 16), uint8(v >> 8), uint8(v)}, true
+}
+
+// Point is a coordinate on a page, measured in points from the top-left corner
+type Point struct {
+	X, Y float64
+}
+
+// ============================================================================
+// Document
+// ============================================================================
+
+// Document is an in-memory PDF document
+type Document struct {
+	width  float64
+	height float64
+	title  string
+	author string
+	pages  []*Page
+}
+
+// New creates an empty document with the given page size in points
+func New(width, height float64) *Document {
+	return &Document{width: width, height: height}
+}
+
+// NewA4 creates an empty A4 portrait document
+func NewA4() *Document {
+	return New(A4Width, A4Height)
+}
+
+// SetTitle sets the document title shown by PDF viewers
+func (d *Document) SetTitle(title string) {
+	d.title = title
+}
+
+// SetAuthor sets the document author metadata
+func (d *Document) SetAuthor(author string) {
+	d.author = author
+}
+
+// Width returns the page width in points
+func (d *Document) Width() float64 {
+	return d.width
+}
+
+// Height returns the page height in points
+func (d *Document) Height() float64 {
+	return d.height
+}
+
+// AddPage appends a new blank page
+func (d *Document) AddPage() *Page {
+	page := &Page{height: d.height}
+	d.pages = append(d.pages, page)
+	return page
+}
+
+// Pages returns the document pages in order
+func (d *Document) Pages() []*Page {
+	return d.pages
+}
+
+// ============================================================================
+// Page drawing
+// ============================================================================
+
+// Page is a single page. All coordinates are in points with the origin at the
+// top-left corner; they are flipped to PDF's bottom-left origin when drawn.
+type Page struct {
+	height  float64
+	content bytes.Buffer
+}
+
+func (p *Page) y(y float64) float64 {
+	return p.height - y
+}
+
+// SetFillColor sets the color used for text and filled shapes
+func (p *Page) SetFillColor(c Color) {
+	fmt.Fprintf(&p.content, "%s %s %s rg\n", colorComponent(c.R), colorComponent(c.G), colorComponent(c.B))
+}
+
+// SetStrokeColor sets the color used for lines and outlines
+func (p *Page) SetStrokeColor(c Color) {
+	fmt.Fprintf(&p.content, "%s %s %s RG\n", colorComponent(c.R), colorComponent(c.G), colorComponent(c.B))
+}
+
+// SetLineWidth sets the stroke width in points
+func (p *Page) SetLineWidth(w float64) {
+	fmt.Fprintf(&p.content, "%s w\n", num(w))
+}
+
+// Text draws a single line of text with its baseline at (x, y)
+func (p *Page) Text(x, y float64, font Font, size float64, s string) {
+	fmt.Fprintf(&p.content, "BT /%s %s Tf %s %s Td (%s) Tj ET\n",
+		font.resourceName(), num(size), num(x), num(p.y(y)), escapeString(s))
+}
+
+// TextRight draws text right-aligned so that it ends at x
+func (p *Page) TextRight(x, y float64, font Font, size float64, s string) {
+	p.Text(x-StringWidth(font, size, s), y, font, size, s)
+}
+
+// TextCenter draws text horizontally centered on x
+func (p *Page) TextCenter(x, y float64, font Font, size float64, s string) {
+	p.Text(x-StringWidth(font, size, s)/2, y, font, size, s)
+}
+
+// FillRect draws a filled rectangle whose top-left corner is at (x, y)
+func (p *Page) FillRect(x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "%s %s %s %s re f\n", num(x), num(p.y(y+h)), num(w), num(h))
+}
+
+// StrokeRect draws a rectangle outline whose top-left corner is at (x, y)
+func (p *Page) StrokeRect(x, y, w, h float64) {
+	fmt.Fprintf(&p.content, "%s %s %s %s re S\n", num(x), num(p.y(y+h)), num(w), num(h))
+}
+
+// Line draws a straight line
+func (p *Page) Line(x1, y1, x2, y2 float64) {
+	fmt.Fprintf(&p.content, "%s %s m %s %s l S\n", num(x1), num(p.y(y1)), num(x2), num(p.y(y2)))
+}
+
+// Polyline draws connected line segments through the given points
+func (p *Page) Polyline(points []Point) {
+	if len(points) < 2 {
+		return
+	}
+	fmt.Fprintf(&p.content, "%s %s m", num(points[0].X), num(p.y(points[0].Y)))
+	for _, pt := range points[1:] {
+		fmt.Fprintf(&p.content, " %s %s l", num(pt.X), num(p.y(pt.Y)))
+	}
+	p.content.WriteString(" S\n")
+}
+
+// ============================================================================
+// Serialization
+// ============================================================================
+
+// Bytes serializes the document to PDF
+func (d *Document) Bytes() ([]byte, error) {
+	if len(d.pages) == 0 {
+		d.AddPage()
+	}
+
+	var buf bytes.Buffer
+	var offsets []int
+
+	// Object numbers: 1 catalog, 2 pages, 3-4 fonts, 5 info, then a page and content stream per page
+	const firstPageObj = 6
+	beginObj := func() int {
+		offsets = append(offsets, buf.Len())
+		n := len(offsets)
+		fmt.Fprintf(&buf, "%d 0 obj\n", n)
+		return n
+	}
+	endObj := func() {
+		buf.WriteString("endobj\n")
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	beginObj()
+	buf.WriteString("<< /Type /Catalog /Pages 2 0 R >>\n")
+	endObj()
+
+	beginObj()
+	kids := make([]string, len(d.pages))
+	for i := range d.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPageObj+i*2)
+	}
+	fmt.Fprintf(&buf, "<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>\n",
+		strings.Join(kids, " "), len(d.pages), num(d.width), num(d.height))
+	endObj()
+
+	beginObj()
+	buf.WriteString("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>\n")
+	endObj()
+
+	beginObj()
+	buf.WriteString("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>\n")
+	endObj()
+
+	beginObj()
+	fmt.Fprintf(&buf, "<< /Title (%s) /Author (%s) /Producer (ads-aggregator) /CreationDate (D:%s) >>\n",
+		escapeString(d.title), escapeString(d.author), time.Now().UTC().Format("20060102150405Z"))
+	endObj()
+
+	for _, page := range d.pages {
+		pageObj := beginObj()
+		fmt.Fprintf(&buf, "<< /Type /Page /Parent 2 0 R /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>\n", pageObj+1)
+		endObj()
+
+		var compressed bytes.Buffer
+		zw := zlib.NewWriter(&compressed)
+		if _, err := zw.Write(page.content.Bytes()); err != nil {
+			return nil, fmt.Errorf("failed to compress page content: %w", err)
+		}
+		if err := zw.Close(); err != nil {
+			return nil, fmt.Errorf("failed to compress page content: %w", err)
+		}
+
+		beginObj()
+		fmt.Fprintf(&buf, "<< /Length %d /Filter /FlateDecode >>\nstream\n", compressed.Len())
+		buf.Write(compressed.Bytes())
+		buf.WriteString("\nendstream\n")
+		endObj()
+	}
+
+	xrefOffset := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n", len(offsets)+1)
+	buf.WriteString("0000000000 65535 f \n")
+	for _, off := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 5 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xrefOffset)
+
+	return buf.Bytes(), nil
+}
+
+// num formats a coordinate with at most two decimal places
+func num(f float64) string {
+	return strconv.FormatFloat(round2(f), 'f', -1, 64)
+}
+
+func round2(f float64) float64 {
+	if f < 0 {
+		return -float64(int64(-f*100+0.5)) / 100
+	}
+	return float64(int64(f*100+0.5)) / 100
+}
+
+func colorComponent(v uint8) string {
+	return strconv.FormatFloat(round2(float64(v)/255), 'f', -1, 64)
+}
+
+// escapeString encodes s as a WinAnsi PDF literal string body
+func escapeString(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		c := winAnsiByte(r)
+		switch c {
+		case '\\', '(', ')':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n', '\r', '\t':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
+
+// winAnsiByte maps a rune to its WinAnsiEncoding byte, substituting '?' for
+// characters the standard fonts cannot display
+func winAnsiByte(r rune) byte {
+	switch {
+	case r >= 32 && r <= 126:
+		return byte(r)
+	case r >= 160 && r <= 255:
+		return byte(r)
+	case r == '€':
+		return 128
+	case r == '‘':
+		return 145
+	case r == '’':
+		return 146
+	case r == '“':
+		return 147
+	case r == '”':
+		return 148
+	case r == '•':
+		return 149
+	case r == '–':
+		return 150
+	case r == '—':
+		return 151
+	case r == '\n', r == '\r', r == '\t':
+		return byte(r)
+	default:
+		return '?'
+	}
+}
@@ -0,0 +1,160 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestDocument_Bytes(t *testing.T) {
+	doc := NewA4()
+	doc.SetTitle("Monthly (Report)")
+
+	first := doc.AddPage()
+	first.SetFillColor(Color{37, 99, 235})
+	first.FillRect(0, 0, doc.Width(), 90)
+	first.Text(40, 50, FontBold, 20, `Spend \ Revenue (MYR)`)
+
+	second := doc.AddPage()
+	second.Polyline([]Point{{40, 100}, {100, 80}, {160, 120}})
+
+	data, err := doc.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+
+	if !bytes.HasPrefix(data, []byte("%PDF-1.4")) {
+		t.Error("missing PDF header")
+	}
+	if !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+		t.Error("missing EOF marker")
+	}
+	if !bytes.Contains(data, []byte("/Count 2")) {
+		t.Error("page tree should contain 2 pages")
+	}
+	if !bytes.Contains(data, []byte(`/Title (Monthly \(Report\))`)) {
+		t.Error("title should be escaped in document info")
+	}
+
+	// Every xref entry must point at the start of its object
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(data)
+	if startxref == nil {
+		t.Fatal("missing startxref")
+	}
+	xrefOffset, _ := strconv.Atoi(string(startxref[1]))
+	if !bytes.HasPrefix(data[xrefOffset:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", xrefOffset)
+	}
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(data[xrefOffset:], -1)
+	for i, entry := range entries {
+		offset, _ := strconv.Atoi(string(entry[1]))
+		want := fmt.Sprintf("%d 0 obj", i+1)
+		if !bytes.HasPrefix(data[offset:], []byte(want)) {
+			t.Errorf("xref entry %d points at %q, want %q", i+1, data[offset:offset+len(want)], want)
+		}
+	}
+
+	content := pageContents(t, data)
+	if len(content) != 2 {
+		t.Fatalf("found %d content streams, want 2", len(content))
+	}
+	if !strings.Contains(content[0], `(Spend \\ Revenue \(MYR\)) Tj`) {
+		t.Errorf("first page missing escaped text: %s", content[0])
+	}
+	// Top-left origin is flipped: y=50 on an A4 page is 791.89 from the bottom
+	if !strings.Contains(content[0], "40 791.89 Td") {
+		t.Errorf("first page text not positioned from the top: %s", content[0])
+	}
+	if !strings.Contains(content[1], "40 741.89 m 100 761.89 l 160 721.89 l S") {
+		t.Errorf("second page missing polyline: %s", content[1])
+	}
+}
+
+// pageContents inflates every FlateDecode stream in the document
+func pageContents(t *testing.T, data []byte) []string {
+	t.Helper()
+
+	var contents []string
+	re := regexp.MustCompile(`(?s)/Length (\d+) /Filter /FlateDecode >>\nstream\n`)
+	for _, loc := range re.FindAllSubmatchIndex(data, -1) {
+		length, _ := strconv.Atoi(string(data[loc[2]:loc[3]]))
+		zr, err := zlib.NewReader(bytes.NewReader(data[loc[1] : loc[1]+length]))
+		if err != nil {
+			t.Fatalf("zlib.NewReader() error = %v", err)
+		}
+		raw, err := io.ReadAll(zr)
+		if err != nil {
+			t.Fatalf("inflate error = %v", err)
+		}
+		contents = append(contents, string(raw))
+	}
+	return contents
+}
+
+func TestParseHexColor(t *testing.T) {
+	tests := []struct {
+		input string
+		want  Color
+		ok    bool
+	}{
+		{"#2563EB", Color{37, 99, 235}, true},
+		{"ff0000", Color{255, 0, 0}, true},
+		{"#fff", Color{}, false},
+		{"#zzzzzz", Color{}, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := ParseHexColor(tt.input)
+		if ok != tt.ok || got != tt.want {
+			t.Errorf("ParseHexColor(%q) = %v, %v, want %v, %v", tt.input, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestStringWidth(t *testing.T) {
+	// "Hi" in Helvetica: H=722, i=222 units
+	if got := StringWidth(FontRegular, 10, "Hi"); got != 9.44 {
+		t.Errorf("StringWidth(regular) = %v, want 9.44", got)
+	}
+	// Bold: H=722, i=278 units
+	if got := StringWidth(FontBold, 10, "Hi"); got != 10 {
+		t.Errorf("StringWidth(bold) = %v, want 10", got)
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	text := "Meta has the highest ROAS at 4.20x across all connected platforms"
+	lines := WrapText(FontRegular, 10, text, 120)
+
+	if len(lines) < 2 {
+		t.Fatalf("WrapText() returned %d lines, want several", len(lines))
+	}
+	for _, line := range lines {
+		if StringWidth(FontRegular, 10, line) > 120 {
+			t.Errorf("line %q exceeds max width", line)
+		}
+	}
+	if strings.Join(lines, " ") != text {
+		t.Errorf("WrapText() lost words: %q", lines)
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	long := "Black Friday Mega Sale Retargeting Campaign"
+	got := TruncateText(FontRegular, 10, long, 80)
+
+	if !strings.HasSuffix(got, "...") {
+		t.Errorf("TruncateText() = %q, want ellipsis", got)
+	}
+	if StringWidth(FontRegular, 10, got) > 80 {
+		t.Errorf("TruncateText() = %q exceeds max width", got)
+	}
+	if TruncateText(FontRegular, 10, "Short", 80) != "Short" {
+		t.Error("TruncateText() should leave short text unchanged")
+	}
+}
@@ -1,4 +1,4 @@
-package worker
+package retry
 
 import (
 	"context"
@@ -12,8 +12,8 @@ import (
 	"github.com/rs/zerolog"
 )
 
-// RetryConfig holds configuration for retry logic
-type RetryConfig struct {
+// Config holds configuration for retry logic
+type Config struct {
 	// MaxRetries is the maximum number of retry attempts
 	MaxRetries int
 	// BaseDelay is the initial delay between retries
@@ -26,10 +26,10 @@ type RetryConfig struct {
 	JitterFactor float64
 }
 
-// DefaultRetryConfig returns the default retry configuration
+// DefaultConfig returns the default retry configuration
 // Suitable for Meta API which has 200 calls/hour rate limit
-func DefaultRetryConfig() *RetryConfig {
-	return &RetryConfig{
+func DefaultConfig() *Config {
+	return &Config{
 		MaxRetries:   3,
 		BaseDelay:    1 * time.Second,
 		MaxDelay:     30 * time.Second,
@@ -38,27 +38,27 @@ func DefaultRetryConfig() *RetryConfig {
 	}
 }
 
-// RetryResult contains information about the retry execution
-type RetryResult struct {
+// Result contains information about the retry execution
+type Result struct {
 	Attempts     int
 	TotalLatency time.Duration
 	LastError    error
 	Success      bool
 }
 
-// RetryableFunc is a function that can be retried
-type RetryableFunc func(ctx context.Context) error
+// Func is a function that can be retried
+type Func func(ctx context.Context) error
 
 // Retryer handles retry logic with exponential backoff
 type Retryer struct {
-	config *RetryConfig
+	config *Config
 	logger zerolog.Logger
 }
 
 // NewRetryer creates a new Retryer with the given configuration
-func NewRetryer(config *RetryConfig, logger zerolog.Logger) *Retryer {
+func NewRetryer(config *Config, logger zerolog.Logger) *Retryer {
 	if config == nil {
-		config = DefaultRetryConfig()
+		config = DefaultConfig()
 	}
 	if config.Multiplier <= 0 {
 		config.Multiplier = 2.0
@@ -73,8 +73,8 @@ func NewRetryer(config *RetryConfig, logger zerolog.Logger) *Retryer {
 }
 
 // Execute runs the given function with retry logic
-func (r *Retryer) Execute(ctx context.Context, operation string, fn RetryableFunc) *RetryResult {
-	result := &RetryResult{
+func (r *Retryer) Execute(ctx context.Context, operation string, fn Func) *Result {
+	result := &Result{
 		Attempts: 0,
 	}
 	startTime := time.Now()
@@ -155,7 +155,7 @@ func (r *Retryer) Execute(ctx context.Context, operation string, fn RetryableFun
 }
 
 // ExecuteWithResult runs a function that returns a value with retry logic
-func ExecuteWithResult[T any](ctx context.Context, r *Retryer, operation string, fn func(ctx context.Context) (T, error)) (T, *RetryResult) {
+func ExecuteWithResult[T any](ctx context.Context, r *Retryer, operation string, fn func(ctx context.Context) (T, error)) (T, *Result) {
 	var result T
 	var lastResult T
 
@@ -256,9 +256,9 @@ func GetRetryAfter(err error) time.Duration {
 	return 0
 }
 
-// RetryWithBackoff is a convenience function for simple retry cases
-func RetryWithBackoff(ctx context.Context, maxRetries int, fn RetryableFunc) error {
-	config := &RetryConfig{
+// WithBackoff is a convenience function for simple retry cases
+func WithBackoff(ctx context.Context, maxRetries int, fn Func) error {
+	config := &Config{
 		MaxRetries:   maxRetries,
 		BaseDelay:    100 * time.Millisecond, // Faster for tests, still reasonable for production
 		MaxDelay:     5 * time.Second,
@@ -273,8 +273,8 @@ func RetryWithBackoff(ctx context.Context, maxRetries int, fn RetryableFunc) err
 	return result.LastError
 }
 
-// RetryForever retries indefinitely until success or context cancellation
-func RetryForever(ctx context.Context, baseDelay, maxDelay time.Duration, logger zerolog.Logger, fn RetryableFunc) error {
+// Forever retries indefinitely until success or context cancellation
+func Forever(ctx context.Context, baseDelay, maxDelay time.Duration, logger zerolog.Logger, fn Func) error {
 	attempt := 0
 	for {
 		select {
@@ -1,4 +1,4 @@
-package worker
+package retry
 
 import (
 	"context"
@@ -13,23 +13,23 @@ import (
 func TestNewRetryer(t *testing.T) {
 	tests := []struct {
 		name   string
-		config *RetryConfig
-		want   *RetryConfig
+		config *Config
+		want   *Config
 	}{
 		{
 			name:   "nil config uses defaults",
 			config: nil,
-			want:   DefaultRetryConfig(),
+			want:   DefaultConfig(),
 		},
 		{
 			name: "zero multiplier uses default",
-			config: &RetryConfig{
+			config: &Config{
 				MaxRetries:   5,
 				BaseDelay:    100 * time.Millisecond,
 				Multiplier:   0,
 				JitterFactor: 0,
 			},
-			want: &RetryConfig{
+			want: &Config{
 				MaxRetries:   5,
 				BaseDelay:    100 * time.Millisecond,
 				Multiplier:   2.0,
@@ -38,13 +38,13 @@ func TestNewRetryer(t *testing.T) {
 		},
 		{
 			name: "invalid jitter factor is corrected",
-			config: &RetryConfig{
+			config: &Config{
 				MaxRetries:   3,
 				BaseDelay:    100 * time.Millisecond,
 				Multiplier:   2.0,
 				JitterFactor: 1.5,
 			},
-			want: &RetryConfig{
+			want: &Config{
 				MaxRetries:   3,
 				BaseDelay:    100 * time.Millisecond,
 				Multiplier:   2.0,
@@ -67,7 +67,7 @@ func TestNewRetryer(t *testing.T) {
 }
 
 func TestRetryer_Execute_Success(t *testing.T) {
-	config := &RetryConfig{
+	config := &Config{
 		MaxRetries:   3,
 		BaseDelay:    10 * time.Millisecond,
 		MaxDelay:     100 * time.Millisecond,
@@ -97,7 +97,7 @@ func TestRetryer_Execute_Success(t *testing.T) {
 }
 
 func TestRetryer_Execute_RetryThenSuccess(t *testing.T) {
-	config := &RetryConfig{
+	config := &Config{
 		MaxRetries:   3,
 		BaseDelay:    10 * time.Millisecond,
 		MaxDelay:     100 * time.Millisecond,
@@ -127,7 +127,7 @@ func TestRetryer_Execute_RetryThenSuccess(t *testing.T) {
 }
 
 func TestRetryer_Execute_AllRetrysFail(t *testing.T) {
-	config := &RetryConfig{
+	config := &Config{
 		MaxRetries:   2,
 		BaseDelay:    10 * time.Millisecond,
 		MaxDelay:     100 * time.Millisecond,
@@ -155,7 +155,7 @@ func TestRetryer_Execute_AllRetrysFail(t *testing.T) {
 }
 
 func TestRetryer_Execute_ContextCancelled(t *testing.T) {
-	config := &RetryConfig{
+	config := &Config{
 		MaxRetries:   5,
 		BaseDelay:    100 * time.Millisecond,
 		MaxDelay:     1 * time.Second,
@@ -186,7 +186,7 @@ func TestRetryer_Execute_ContextCancelled(t *testing.T) {
 }
 
 func TestRetryer_Execute_NonRetryableError(t *testing.T) {
-	config := &RetryConfig{
+	config := &Config{
 		MaxRetries:   3,
 		BaseDelay:    10 * time.Millisecond,
 		MaxDelay:     100 * time.Millisecond,
@@ -211,7 +211,7 @@ func TestRetryer_Execute_NonRetryableError(t *testing.T) {
 }
 
 func TestRetryer_CalculateDelay(t *testing.T) {
-	config := &RetryConfig{
+	config := &Config{
 		MaxRetries:   5,
 		BaseDelay:    100 * time.Millisecond,
 		MaxDelay:     10 * time.Second,
@@ -240,7 +240,7 @@ func TestRetryer_CalculateDelay(t *testing.T) {
 }
 
 func TestRetryer_CalculateDelay_MaxCap(t *testing.T) {
-	config := &RetryConfig{
+	config := &Config{
 		MaxRetries:   10,
 		BaseDelay:    100 * time.Millisecond,
 		MaxDelay:     500 * time.Millisecond,
@@ -282,9 +282,9 @@ func TestIsRetryableHTTPStatus(t *testing.T) {
 	}
 }
 
-func TestRetryWithBackoff(t *testing.T) {
+func TestWithBackoff(t *testing.T) {
 	callCount := 0
-	err := RetryWithBackoff(context.Background(), 2, func(ctx context.Context) error {
+	err := WithBackoff(context.Background(), 2, func(ctx context.Context) error {
 		callCount++
 		if callCount < 2 {
 			return appErrors.ErrInternal("temp error")
@@ -334,7 +334,7 @@ func TestIsRateLimitError(t *testing.T) {
 }
 
 func TestExecuteWithResult(t *testing.T) {
-	config := &RetryConfig{
+	config := &Config{
 		MaxRetries:   2,
 		BaseDelay:    10 * time.Millisecond,
 		MaxDelay:     100 * time.Millisecond,
@@ -363,8 +363,8 @@ func TestExecuteWithResult(t *testing.T) {
 	}
 }
 
-func TestDefaultRetryConfig(t *testing.T) {
-	config := DefaultRetryConfig()
+func TestDefaultConfig(t *testing.T) {
+	config := DefaultConfig()
 
 	if config.MaxRetries != 3 {
 		t.Errorf("MaxRetries = %d, want 3", config.MaxRetries)
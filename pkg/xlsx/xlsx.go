@@ -0,0 +1,471 @@
+// Package xlsx provides a minimal Office Open XML (SpreadsheetML) workbook writer
+// for report exports. It supports multiple sheets, typed cells, number formats,
+// and frozen header rows without pulling in a full spreadsheet library.
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/shopspring/decimal"
+)
+
+// Common number format codes
+const (
+	FormatGeneral  = ""
+	FormatInteger  = "#,##0"
+	FormatDecimal  = "#,##0.00"
+	FormatPercent  = `0.00"%"` // Values already expressed as percentages (e.g. CTR 2.5 = 2.5%)
+	FormatMultiple = `0.00"x"` // Ratios such as ROAS
+)
+
+const (
+	maxSheetNameLength = 31
+	minColumnWidth     = 8
+	maxColumnWidth     = 60
+	firstCustomNumFmt  = 164 // Custom number formats must start after the built-in range
+)
+
+// CurrencyFormat returns a number format code that prefixes amounts with the ISO currency code
+func CurrencyFormat(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return FormatDecimal
+	}
+	return `"` + code + `" #,##0.00`
+}
+
+// ============================================================================
+// Cells
+// ============================================================================
+
+type cellKind int
+
+const (
+	cellText cellKind = iota
+	cellNumber
+)
+
+// Cell represents a single worksheet cell value with an optional number format
+type Cell struct {
+	kind   cellKind
+	value  string
+	format string
+}
+
+// Text creates a string cell
+func Text(s string) Cell {
+	return Cell{kind: cellText, value: s}
+}
+
+// Int creates an integer cell with thousands separators
+func Int(n int64) Cell {
+	return Cell{kind: cellNumber, value: strconv.FormatInt(n, 10), format: FormatInteger}
+}
+
+// Float creates a numeric cell with the given number format
+func Float(f float64, format string) Cell {
+	return Cell{kind: cellNumber, value: strconv.FormatFloat(f, 'f', -1, 64), format: format}
+}
+
+// Decimal creates a numeric cell from a decimal value, preserving its precision
+func Decimal(d decimal.Decimal, format string) Cell {
+	return Cell{kind: cellNumber, value: d.String(), format: format}
+}
+
+// ============================================================================
+// Workbook & Sheets
+// ============================================================================
+
+// Workbook is an in-memory spreadsheet workbook
+type Workbook struct {
+	sheets []*Sheet
+}
+
+// Sheet is a single worksheet within a workbook
+type Sheet struct {
+	name   string
+	header []string
+	rows   [][]Cell
+	widths []int
+}
+
+// NewWorkbook creates an empty workbook
+func NewWorkbook() *Workbook {
+	return &Workbook{}
+}
+
+// AddSheet appends a new worksheet. The name is sanitized to satisfy Excel's
+// naming rules and de-duplicated against existing sheets.
+func (w *Workbook) AddSheet(name string) *Sheet {
+	sheet := &Sheet{name: w.uniqueSheetName(sanitizeSheetName(name))}
+	w.sheets = append(w.sheets, sheet)
+	return sheet
+}
+
+// Sheets returns the worksheets in workbook order
+func (w *Workbook) Sheets() []*Sheet {
+	return w.sheets
+}
+
+// Name returns the worksheet name
+func (s *Sheet) Name() string {
+	return s.name
+}
+
+// SetHeader sets the header row. The header row is rendered bold and frozen
+// so it stays visible while scrolling.
+func (s *Sheet) SetHeader(columns ...string) {
+	s.header = columns
+	for i, col := range columns {
+		s.trackWidth(i, col)
+	}
+}
+
+// AddRow appends a data row
+func (s *Sheet) AddRow(cells ...Cell) {
+	s.rows = append(s.rows, cells)
+	for i, cell := range cells {
+		s.trackWidth(i, cell.value)
+	}
+}
+
+// AddBlankRow appends an empty row, useful to separate sections
+func (s *Sheet) AddBlankRow() {
+	s.rows = append(s.rows, nil)
+}
+
+func (s *Sheet) trackWidth(col int, value string) {
+	for len(s.widths) <= col {
+		s.widths = append(s.widths, minColumnWidth)
+	}
+	// Leave room for thousands separators and currency prefixes
+	width := utf8.RuneCountInString(value) + 6
+	if width > maxColumnWidth {
+		width = maxColumnWidth
+	}
+	if width > s.widths[col] {
+		s.widths[col] = width
+	}
+}
+
+func (w *Workbook) uniqueSheetName(name string) string {
+	candidate := name
+	for n := 2; w.hasSheet(candidate); n++ {
+		suffix := fmt.Sprintf(" (%d)", n)
+		base := []rune(name)
+		if len(base)+len(suffix) > maxSheetNameLength {
+			base = base[:maxSheetNameLength-len(suffix)]
+		}
+		candidate = string(base) + suffix
+	}
+	return candidate
+}
+
+func (w *Workbook) hasSheet(name string) bool {
+	for _, s := range w.sheets {
+		if strings.EqualFold(s.name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func sanitizeSheetName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '[', ']', ':', '*', '?', '/', '\\':
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(name))
+	name = strings.Trim(name, "'")
+
+	if name == "" {
+		name = "Sheet"
+	}
+	if runes := []rune(name); len(runes) > maxSheetNameLength {
+		name = string(runes[:maxSheetNameLength])
+	}
+	return name
+}
+
+// ============================================================================
+// Serialization
+// ============================================================================
+
+// Bytes serializes the workbook to an .xlsx file
+func (w *Workbook) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := w.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Write serializes the workbook as an .xlsx (zip) archive to out
+func (w *Workbook) Write(out io.Writer) error {
+	if len(w.sheets) == 0 {
+		w.AddSheet("Sheet1")
+	}
+
+	styles := newStyleSheet()
+	for _, sheet := range w.sheets {
+		for _, row := range sheet.rows {
+			for _, cell := range row {
+				styles.register(cell.format)
+			}
+		}
+	}
+
+	zw := zip.NewWriter(out)
+
+	type part struct {
+		name    string
+		content string
+	}
+	parts := []part{
+		{"[Content_Types].xml", w.contentTypesXML()},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", w.workbookXML()},
+		{"xl/_rels/workbook.xml.rels", w.workbookRelsXML()},
+		{"xl/styles.xml", styles.xml()},
+	}
+	for i, sheet := range w.sheets {
+		parts = append(parts, part{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), sheet.xml(i == 0, styles)})
+	}
+
+	for _, part := range parts {
+		f, err := zw.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", part.name, err)
+		}
+		if _, err := io.WriteString(f, part.content); err != nil {
+			return fmt.Errorf("failed to write %s: %w", part.name, err)
+		}
+	}
+
+	return zw.Close()
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+const rootRelsXML = xmlHeader +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+func (w *Workbook) contentTypesXML() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	b.WriteString(`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>`)
+	for i := range w.sheets {
+		fmt.Fprintf(&b, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i+1)
+	}
+	b.WriteString(`</Types>`)
+	return b.String()
+}
+
+func (w *Workbook) workbookXML() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+	b.WriteString(`<bookViews><workbookView activeTab="0"/></bookViews><sheets>`)
+	for i, sheet := range w.sheets {
+		fmt.Fprintf(&b, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, escape(sheet.name), i+1, i+1)
+	}
+	b.WriteString(`</sheets></workbook>`)
+	return b.String()
+}
+
+func (w *Workbook) workbookRelsXML() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := range w.sheets {
+		fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i+1, i+1)
+	}
+	// Styles relationship follows the worksheets
+	fmt.Fprintf(&b, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>`, len(w.sheets)+1)
+	b.WriteString(`</Relationships>`)
+	return b.String()
+}
+
+func (s *Sheet) xml(selected bool, styles *styleSheet) string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"`)
+	if selected {
+		b.WriteString(` tabSelected="1"`)
+	}
+	b.WriteString(`>`)
+	if len(s.header) > 0 {
+		b.WriteString(`<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`)
+		b.WriteString(`<selection pane="bottomLeft" activeCell="A2" sqref="A2"/>`)
+	}
+	b.WriteString(`</sheetView></sheetViews>`)
+
+	if len(s.widths) > 0 {
+		b.WriteString(`<cols>`)
+		for i, width := range s.widths {
+			fmt.Fprintf(&b, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+		}
+		b.WriteString(`</cols>`)
+	}
+
+	b.WriteString(`<sheetData>`)
+	rowNum := 1
+	if len(s.header) > 0 {
+		fmt.Fprintf(&b, `<row r="%d">`, rowNum)
+		for col, title := range s.header {
+			writeCell(&b, cellRef(col, rowNum), Text(title), styleHeader)
+		}
+		b.WriteString(`</row>`)
+		rowNum++
+	}
+	for _, row := range s.rows {
+		if len(row) == 0 {
+			fmt.Fprintf(&b, `<row r="%d"/>`, rowNum)
+			rowNum++
+			continue
+		}
+		fmt.Fprintf(&b, `<row r="%d">`, rowNum)
+		for col, cell := range row {
+			writeCell(&b, cellRef(col, rowNum), cell, styles.styleFor(cell.format))
+		}
+		b.WriteString(`</row>`)
+		rowNum++
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+	return b.String()
+}
+
+func writeCell(b *strings.Builder, ref string, cell Cell, style int) {
+	styleAttr := ""
+	if style != styleDefault {
+		styleAttr = fmt.Sprintf(` s="%d"`, style)
+	}
+
+	switch cell.kind {
+	case cellNumber:
+		fmt.Fprintf(b, `<c r="%s"%s><v>%s</v></c>`, ref, styleAttr, cell.value)
+	default:
+		if cell.value == "" {
+			fmt.Fprintf(b, `<c r="%s"%s/>`, ref, styleAttr)
+			return
+		}
+		fmt.Fprintf(b, `<c r="%s"%s t="inlineStr"><is><t xml:space="preserve">%s</t></is></c>`, ref, styleAttr, escape(cell.value))
+	}
+}
+
+// cellRef converts a zero-based column index and one-based row number to an A1 reference
+func cellRef(col, row int) string {
+	return columnName(col) + strconv.Itoa(row)
+}
+
+func columnName(col int) string {
+	name := ""
+	for col >= 0 {
+		name = string(rune('A'+col%26)) + name
+		col = col/26 - 1
+	}
+	return name
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// ============================================================================
+// Styles
+// ============================================================================
+
+const (
+	styleDefault = 0
+	styleHeader  = 1
+	// Number format styles are appended after the fixed styles
+	firstNumberStyle = 2
+)
+
+// styleSheet assigns a cell style index to each distinct number format code
+type styleSheet struct {
+	formats []string
+	index   map[string]int
+}
+
+func newStyleSheet() *styleSheet {
+	return &styleSheet{index: make(map[string]int)}
+}
+
+func (s *styleSheet) register(format string) {
+	if format == FormatGeneral {
+		return
+	}
+	if _, ok := s.index[format]; ok {
+		return
+	}
+	s.index[format] = len(s.formats)
+	s.formats = append(s.formats, format)
+}
+
+func (s *styleSheet) styleFor(format string) int {
+	if i, ok := s.index[format]; ok {
+		return firstNumberStyle + i
+	}
+	return styleDefault
+}
+
+func (s *styleSheet) xml() string {
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	if len(s.formats) > 0 {
+		fmt.Fprintf(&b, `<numFmts count="%d">`, len(s.formats))
+		for i, format := range s.formats {
+			fmt.Fprintf(&b, `<numFmt numFmtId="%d" formatCode="%s"/>`, firstCustomNumFmt+i, escape(format))
+		}
+		b.WriteString(`</numFmts>`)
+	}
+
+	b.WriteString(`<fonts count="2">`)
+	b.WriteString(`<font><sz val="11"/><name val="Calibri"/><family val="2"/></font>`)
+	b.WriteString(`<font><b/><sz val="11"/><name val="Calibri"/><family val="2"/></font>`)
+	b.WriteString(`</fonts>`)
+
+	// The first two fills are reserved by the spec; the third shades header rows
+	b.WriteString(`<fills count="3">`)
+	b.WriteString(`<fill><patternFill patternType="none"/></fill>`)
+	b.WriteString(`<fill><patternFill patternType="gray125"/></fill>`)
+	b.WriteString(`<fill><patternFill patternType="solid"><fgColor rgb="FFD9E1F2"/><bgColor indexed="64"/></patternFill></fill>`)
+	b.WriteString(`</fills>`)
+
+	b.WriteString(`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>`)
+	b.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+
+	fmt.Fprintf(&b, `<cellXfs count="%d">`, firstNumberStyle+len(s.formats))
+	b.WriteString(`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>`)
+	b.WriteString(`<xf numFmtId="0" fontId="1" fillId="2" borderId="0" xfId="0" applyFont="1" applyFill="1"/>`)
+	for i := range s.formats {
+		fmt.Fprintf(&b, `<xf numFmtId="%d" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>`, firstCustomNumFmt+i)
+	}
+	b.WriteString(`</cellXfs>`)
+
+	b.WriteString(`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>`)
+	b.WriteString(`</styleSheet>`)
+	return b.String()
+}
@@ -0,0 +1,142 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func readParts(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("zip.NewReader() error = %v", err)
+	}
+
+	parts := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Open(%s) error = %v", f.Name, err)
+		}
+		content, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(content)
+	}
+	return parts
+}
+
+func TestWorkbook_Bytes(t *testing.T) {
+	wb := NewWorkbook()
+
+	sheet := wb.AddSheet("Campaigns")
+	sheet.SetHeader("Name", "Spend", "Clicks")
+	sheet.AddRow(Text("A & B"), Decimal(decimal.NewFromFloat(12.5), CurrencyFormat("usd")), Int(1200))
+	sheet.AddBlankRow()
+	sheet.AddRow(Text("Total"), Decimal(decimal.NewFromFloat(12.5), CurrencyFormat("usd")), Int(1200))
+
+	wb.AddSheet("Empty")
+
+	data, err := wb.Bytes()
+	if err != nil {
+		t.Fatalf("Bytes() error = %v", err)
+	}
+
+	parts := readParts(t, data)
+
+	required := []string{
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels",
+		"xl/styles.xml",
+		"xl/worksheets/sheet1.xml",
+		"xl/worksheets/sheet2.xml",
+	}
+	for _, name := range required {
+		content, ok := parts[name]
+		if !ok {
+			t.Errorf("missing part %s", name)
+			continue
+		}
+		// Every part must be well-formed XML
+		decoder := xml.NewDecoder(strings.NewReader(content))
+		for {
+			if _, err := decoder.Token(); err != nil {
+				if err != io.EOF {
+					t.Errorf("%s is not well-formed: %v", name, err)
+				}
+				break
+			}
+		}
+	}
+
+	sheet1 := parts["xl/worksheets/sheet1.xml"]
+	if !strings.Contains(sheet1, `<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`) {
+		t.Error("sheet1 header row is not frozen")
+	}
+	if !strings.Contains(sheet1, `<c r="B2" s="2"><v>12.5</v></c>`) {
+		t.Errorf("sheet1 missing styled numeric spend cell: %s", sheet1)
+	}
+	if !strings.Contains(sheet1, `<c r="C4" s="3"><v>1200</v></c>`) {
+		t.Errorf("sheet1 missing integer cell after blank row: %s", sheet1)
+	}
+	if strings.Contains(parts["xl/worksheets/sheet2.xml"], "frozen") {
+		t.Error("sheet without header should not freeze panes")
+	}
+
+	styles := parts["xl/styles.xml"]
+	if !strings.Contains(styles, `<numFmt numFmtId="164" formatCode="&#34;USD&#34; #,##0.00"/>`) {
+		t.Errorf("styles.xml missing currency format: %s", styles)
+	}
+	if !strings.Contains(styles, `<numFmt numFmtId="165" formatCode="#,##0"/>`) {
+		t.Errorf("styles.xml missing integer format: %s", styles)
+	}
+}
+
+func TestSheetNames(t *testing.T) {
+	wb := NewWorkbook()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"Meta", "Meta"},
+		{"meta", "meta (2)"},
+		{"Q1/Q2 [draft]", "Q1_Q2 _draft_"},
+		{"", "Sheet"},
+		{strings.Repeat("x", 40), strings.Repeat("x", 31)},
+		{strings.Repeat("x", 40), strings.Repeat("x", 27) + " (2)"},
+	}
+
+	for _, tt := range tests {
+		if got := wb.AddSheet(tt.input).Name(); got != tt.expected {
+			t.Errorf("AddSheet(%q).Name() = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := []struct {
+		col      int
+		expected string
+	}{
+		{0, "A"},
+		{25, "Z"},
+		{26, "AA"},
+		{51, "AZ"},
+		{702, "AAA"},
+	}
+
+	for _, tt := range tests {
+		if got := columnName(tt.col); got != tt.expected {
+			t.Errorf("columnName(%d) = %q, want %q", tt.col, got, tt.expected)
+		}
+	}
+}